@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// loadConfig reads the configuration as serve does, from the --config file
+// and the environment, plus the serve flags in extra, and validates it.
+func loadConfig(cmd *cobra.Command, extra ...string) (*config.Config, *server.ToolsConfig, error) {
+	var args []string
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		args = append(args, "--config", path)
+	}
+	cfg, err := config.Load(append(args, extra...))
+	if err != nil {
+		return nil, nil, &exitError{code: 2, err: err}
+	}
+	toolsConfig, err := server.PrepareConfig(cfg)
+	if err != nil {
+		return nil, nil, &exitError{code: 2, err: fmt.Errorf("invalid configuration: %w", err)}
+	}
+	return cfg, toolsConfig, nil
+}
+
+// newProvider authenticates with the configured token. Logs go to stderr so
+// stdout only carries the command's output.
+func newProvider(cfg *config.Config) (*provider.ApiProvider, *zap.Logger, error) {
+	logger, err := newLogger("stdio")
+	if err != nil {
+		return nil, nil, err
+	}
+	return provider.New(cfg, logger), logger, nil
+}
+
+// warmCaches loads the users and channels caches at the same time, as serve
+// does, from the cache files when they are fresh unless force is set.
+func warmCaches(ctx context.Context, p *provider.ApiProvider, force bool) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		if force {
+			return p.ForceRefreshUsers(ctx)
+		}
+		return p.RefreshUsers(ctx)
+	})
+	g.Go(func() error {
+		if force {
+			return p.ForceRefreshChannels(ctx)
+		}
+		return p.RefreshChannels(ctx)
+	})
+	return g.Wait()
+}
+
+func newToolsCmd() *cobra.Command {
+	tools := &cobra.Command{
+		Use:   "tools",
+		Short: "Inspect the tools the configuration registers",
+	}
+
+	var (
+		toolsConfig  string
+		enabledTools string
+		asJSON       bool
+	)
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the registered tools with their groups, cost and scopes",
+		Long: `List the tools serve would register with this configuration, with their
+groups, cost and the OAuth scopes of which the token needs one. No token is
+needed; tools a token's scopes would skip are listed too, see token check.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var extra []string
+			if toolsConfig != "" {
+				extra = append(extra, "--tools-config", toolsConfig)
+			}
+			if enabledTools != "" {
+				extra = append(extra, "--enabled-tools", enabledTools)
+			}
+			cfg, tc, err := loadConfig(cmd, extra...)
+			if err != nil {
+				return err
+			}
+			infos := server.ConfiguredTools(cfg, tc)
+			if asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(infos)
+			}
+			return writeToolsTable(cmd.OutOrStdout(), infos)
+		},
+	}
+	list.Flags().StringVar(&toolsConfig, "tools-config", "", "tools config file, as SLACK_MCP_TOOLS_CONFIG")
+	list.Flags().StringVarP(&enabledTools, "enabled-tools", "e", "", "comma-separated tools, as SLACK_MCP_ENABLED_TOOLS")
+	list.Flags().BoolVar(&asJSON, "json", false, "print JSON with the descriptions")
+	tools.AddCommand(list)
+	return tools
+}
+
+func writeToolsTable(w io.Writer, infos []server.ToolInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tACCESS\tCOST\tGROUPS\tSCOPES")
+	for _, t := range infos {
+		access := "write"
+		if t.ReadOnly {
+			access = "read"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", t.Name, access, t.Cost, strings.Join(t.Groups, ","), strings.Join(t.Scopes, ","))
+	}
+	return tw.Flush()
+}
+
+func newCacheCmd() *cobra.Command {
+	cache := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the users and channels caches",
+	}
+
+	var force bool
+	warm := &cobra.Command{
+		Use:   "warm",
+		Short: "Fill the users and channels cache files",
+		Long: `Fill the users and channels cache files (SLACK_MCP_USERS_CACHE and
+SLACK_MCP_CHANNELS_CACHE) so the next serve starts ready, e.g. from a cron
+job or an init container.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, _, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			p, logger, err := newProvider(cfg)
+			if err != nil {
+				return err
+			}
+			defer logger.Sync()
+
+			if err := warmCaches(cmd.Context(), p, force); err != nil {
+				return err
+			}
+			cmd.Printf("%d users, %d channels cached\n", len(p.ProvideUsersMap().Users), len(p.ProvideChannelsMaps().Channels))
+			return nil
+		},
+	}
+	warm.Flags().BoolVar(&force, "force", false, "fetch from Slack even when the cache files are fresh")
+	cache.AddCommand(warm)
+	return cache
+}
+
+func newExportCmd() *cobra.Command {
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export Slack content without an MCP client",
+	}
+
+	var (
+		threadTs, oldest, latest string
+		format, tz, output       string
+		includeThreads           bool
+	)
+	channel := &cobra.Command{
+		Use:   "channel <channel>",
+		Short: "Export a channel or thread as a Markdown or HTML transcript",
+		Long: `Export a channel over a date range, or a single thread, as the
+conversations_transcript tool does. The channel is an ID, #name or @user; the
+channel policy (SLACK_MCP_CHANNEL_ALLOWLIST and the like), the allow rules of
+the tools config and SLACK_MCP_REDACT apply.`,
+		Example: `  slack-mcp-server export channel '#incidents' --oldest 2024-03-01 --latest 2024-03-02 -o incident.md`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, toolsConfig, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			p, logger, err := newProvider(cfg)
+			if err != nil {
+				return err
+			}
+			defer logger.Sync()
+
+			ctx := cmd.Context()
+			if err := warmCaches(ctx, p, false); err != nil {
+				return err
+			}
+
+			request := mcp.CallToolRequest{}
+			request.Params.Name = server.ToolConversationsTranscript
+			request.Params.Arguments = map[string]any{
+				"channel_id":      args[0],
+				"thread_ts":       threadTs,
+				"oldest":          oldest,
+				"latest":          latest,
+				"include_threads": includeThreads,
+				"format":          format,
+				"tz":              tz,
+			}
+			transcript := server.BuildPolicyMiddleware(cfg, toolsConfig, p, logger)(handler.NewConversationsHandler(p, logger).ConversationsTranscriptHandler)
+			res, err := transcript(ctx, request)
+			if err != nil {
+				return err
+			}
+			return writeTranscript(cmd, res, output)
+		},
+	}
+	channel.Flags().StringVar(&threadTs, "thread-ts", "", "export only the thread with this parent ts")
+	channel.Flags().StringVar(&oldest, "oldest", "", "start of the range, e.g. 2024-03-01 (default 7 days ago)")
+	channel.Flags().StringVar(&latest, "latest", "", "end of the range; a date includes the whole day (default now)")
+	channel.Flags().BoolVar(&includeThreads, "include-threads", true, "include thread replies under their parent")
+	channel.Flags().StringVar(&format, "format", "markdown", "markdown or html")
+	channel.Flags().StringVar(&tz, "tz", "", "IANA timezone for the range and timestamps (default SLACK_MCP_TIMEZONE)")
+	channel.Flags().StringVarP(&output, "output", "o", "", "file to write (default stdout)")
+	export.AddCommand(channel)
+	return export
+}
+
+// writeTranscript writes the transcript of res to output, or stdout, and
+// reports what it holds on stderr.
+func writeTranscript(cmd *cobra.Command, res *mcp.CallToolResult, output string) error {
+	if res.IsError || len(res.Content) < 2 {
+		return fmt.Errorf("export failed: %s", resultText(res, 0))
+	}
+	var meta handler.TranscriptMetadata
+	if err := json.Unmarshal([]byte(resultText(res, 1)), &meta); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	w := cmd.OutOrStdout()
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if _, err := io.WriteString(w, resultText(res, 0)); err != nil {
+		return err
+	}
+
+	cmd.PrintErrf("Exported %d messages and %d threads of %s\n", meta.Messages, meta.Threads, meta.Channel)
+	if meta.Truncated {
+		cmd.PrintErrln("Warning: the transcript stopped at the message cap; narrow --oldest and --latest for the rest")
+	}
+	return nil
+}
+
+func resultText(res *mcp.CallToolResult, i int) string {
+	if i >= len(res.Content) {
+		return ""
+	}
+	if t, ok := mcp.AsTextContent(res.Content[i]); ok {
+		return t.Text
+	}
+	return ""
+}
+
+func newTokenCmd() *cobra.Command {
+	token := &cobra.Command{
+		Use:   "token",
+		Short: "Inspect the configured Slack token",
+	}
+	check := &cobra.Command{
+		Use:   "check",
+		Short: "Check the token, its expiry and the tools its scopes enable",
+		Long: `Run auth.test with the configured token and, when SLACK_MCP_TOKEN_EXPIRES_AT
+is set, check how long it has left. Prints the token type, its scopes and the
+tools they enable or skip. Exits 1 when a check fails, e.g. when the token
+expires within SLACK_MCP_TOKEN_EXPIRY_WARNING, so it can gate a deploy or
+alert from cron.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, toolsConfig, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			p, logger, err := newProvider(cfg)
+			if err != nil {
+				return err
+			}
+			defer logger.Sync()
+
+			report := server.NewMCPServer(p, logger, cfg, toolsConfig).TokenCheck(cmd.Context())
+			report.Write(cmd.OutOrStdout())
+			if !report.OK() {
+				return &exitError{code: 1}
+			}
+			return nil
+		},
+	}
+	token.AddCommand(check)
+	return token
+}
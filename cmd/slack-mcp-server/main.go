@@ -2,46 +2,111 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server"
 	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var defaultSseHost = "127.0.0.1"
-var defaultSsePort = 13080
-
 func main() {
-	var transport string
-	var enabledToolsFlag string
-	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio, sse or http)")
-	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio, sse or http)")
-	flag.StringVar(&enabledToolsFlag, "e", "", "Comma-separated list of enabled tools (empty = all tools)")
-	flag.StringVar(&enabledToolsFlag, "enabled-tools", "", "Comma-separated list of enabled tools (empty = all tools)")
-	flag.Parse()
-
-	if enabledToolsFlag == "" {
-		enabledToolsFlag = os.Getenv("SLACK_MCP_ENABLED_TOOLS")
+	if err := newRootCmd().Execute(); err != nil {
+		code := 1
+		var exit *exitError
+		if errors.As(err, &exit) {
+			code, err = exit.code, exit.err
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(code)
 	}
+}
 
-	var enabledTools []string
-	if enabledToolsFlag != "" {
-		for _, tool := range strings.Split(enabledToolsFlag, ",") {
-			tool = strings.TrimSpace(tool)
-			if tool != "" {
-				enabledTools = append(enabledTools, tool)
-			}
-		}
+// exitError ends the process with code, after printing err when set.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// newRootCmd returns the command line. Without a subcommand it serves, so
+// "slack-mcp-server --transport sse" keeps working as before subcommands.
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "slack-mcp-server",
+		Short: "Slack MCP server, and one-off exports and diagnostics with the same configuration",
+		Long: `Slack MCP server, and one-off exports and diagnostics with the same configuration.
+
+Without a command it serves, taking the flags of the serve command.`,
+		Args:               cobra.ArbitraryArgs,
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		SilenceErrors:      true,
+		RunE:               serve,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &exitError{code: 2, err: fmt.Errorf("%w\nRun '%s --help' for usage.", err, cmd.CommandPath())}
+	})
+	root.PersistentFlags().String("config", "", "YAML or JSON config file, as SLACK_MCP_CONFIG")
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve [flags]",
+			Short: "Run the MCP server (the default)",
+			Long: `Run the MCP server.
+
+Flags: -t/--transport stdio|sse|http|ws, -e/--enabled-tools, --tools-config,
+--config, --self-test and --self-test-channel; see --help.`,
+			Args:               cobra.ArbitraryArgs,
+			DisableFlagParsing: true,
+			RunE:               serve,
+		},
+		newToolsCmd(),
+		newCacheCmd(),
+		newExportCmd(),
+		newTokenCmd(),
+	)
+	return root
+}
+
+// serve runs the server with the serve flags in args.
+func serve(cmd *cobra.Command, args []string) error {
+	if cmd.HasSubCommands() && slices.ContainsFunc(args, isHelpFlag) {
+		cmd.Print(cmd.UsageString())
+		cmd.Println()
+		cmd.Println("Serve flags:")
+	}
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return &exitError{code: 2, err: err}
 	}
+	transport := cfg.Transport
 
 	logger, err := newLogger(transport)
 	if err != nil {
@@ -49,32 +114,42 @@ func main() {
 	}
 	defer logger.Sync()
 
-	addMessageToolEnv := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
-	err = validateToolConfig(addMessageToolEnv)
+	toolsConfig, err := server.PrepareConfig(cfg)
 	if err != nil {
-		logger.Fatal("error in SLACK_MCP_ADD_MESSAGE_TOOL",
+		logger.Fatal("Invalid configuration",
 			zap.String("context", "console"),
 			zap.Error(err),
 		)
 	}
-
-	err = server.ValidateEnabledTools(enabledTools)
-	if err != nil {
-		logger.Fatal("error in SLACK_MCP_ENABLED_TOOLS",
+	if toolsConfig != nil {
+		logger.Info("Loaded tools config",
 			zap.String("context", "console"),
-			zap.Error(err),
+			zap.String("path", cfg.ToolsConfig),
+			zap.Strings("enabled_tools", cfg.EnabledTools),
 		)
 	}
 
-	p := provider.New(transport, logger)
-	s := server.NewMCPServer(p, logger, enabledTools)
+	p := provider.New(cfg, logger)
+	s := server.NewMCPServer(p, logger, cfg, toolsConfig)
+
+	if cfg.SelfTest {
+		report := s.SelfTest(context.Background(), cfg.SelfTestChannel)
+		report.Write(os.Stdout)
+		if !report.OK() {
+			return &exitError{code: 1}
+		}
+		return nil
+	}
 
-	go func() {
-		var once sync.Once
+	go p.WatchSecrets(context.Background())
+	go p.WatchTokenHealth(context.Background())
+	s.EnableReload(args)
 
-		newUsersWatcher(p, &once, logger)()
-		newChannelsWatcher(p, &once, logger)()
-	}()
+	// Users and channels are listed by different API methods with separate
+	// rate limits, so both caches warm up at the same time.
+	var once sync.Once
+	go newUsersWatcher(p, &once, logger)()
+	go newChannelsWatcher(p, &once, logger)()
 
 	switch transport {
 	case "stdio":
@@ -84,55 +159,25 @@ func main() {
 			}
 			time.Sleep(100 * time.Millisecond)
 		}
-		if err := s.ServeStdio(); err != nil {
-			logger.Fatal("Server error",
-				zap.String("context", "console"),
-				zap.Error(err),
-			)
-		}
-	case "sse":
-		host := os.Getenv("SLACK_MCP_HOST")
-		if host == "" {
-			host = defaultSseHost
-		}
-		port := os.Getenv("SLACK_MCP_PORT")
-		if port == "" {
-			port = strconv.Itoa(defaultSsePort)
-		}
-
-		sseServer := s.ServeSSE(":" + port)
-		logger.Info(
-			fmt.Sprintf("SSE server listening on %s", fmt.Sprintf("%s:%s/sse", host, port)),
-			zap.String("context", "console"),
-			zap.String("host", host),
-			zap.String("port", port),
-		)
-
-		if ready, _ := p.IsReady(); !ready {
-			logger.Info("Slack MCP Server is still warming up caches",
-				zap.String("context", "console"),
-			)
-		}
-
-		if err := sseServer.Start(host + ":" + port); err != nil {
+		if err := s.Run(context.Background(), ""); err != nil {
 			logger.Fatal("Server error",
 				zap.String("context", "console"),
 				zap.Error(err),
 			)
 		}
-	case "http":
-		host := os.Getenv("SLACK_MCP_HOST")
-		if host == "" {
-			host = defaultSseHost
+	case "sse", "http", "ws":
+		host := cfg.Host
+		port := strconv.Itoa(cfg.Port)
+
+		listening := fmt.Sprintf("%s:%s", host, port)
+		switch transport {
+		case "sse":
+			listening += "/sse"
+		case "ws":
+			listening += "/ws"
 		}
-		port := os.Getenv("SLACK_MCP_PORT")
-		if port == "" {
-			port = strconv.Itoa(defaultSsePort)
-		}
-
-		httpServer := s.ServeHTTP(":" + port)
 		logger.Info(
-			fmt.Sprintf("HTTP server listening on %s", fmt.Sprintf("%s:%s", host, port)),
+			fmt.Sprintf("%s server listening on %s", strings.ToUpper(transport), listening),
 			zap.String("context", "console"),
 			zap.String("host", host),
 			zap.String("port", port),
@@ -144,7 +189,7 @@ func main() {
 			)
 		}
 
-		if err := httpServer.Start(host + ":" + port); err != nil {
+		if err := s.Run(context.Background(), net.JoinHostPort(host, port)); err != nil {
 			logger.Fatal("Server error",
 				zap.String("context", "console"),
 				zap.Error(err),
@@ -154,9 +199,14 @@ func main() {
 		logger.Fatal("Invalid transport type",
 			zap.String("context", "console"),
 			zap.String("transport", transport),
-			zap.String("allowed", "stdio, sse, http"),
+			zap.String("allowed", "stdio, sse, http, ws"),
 		)
 	}
+	return nil
+}
+
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "--help" || arg == "-help"
 }
 
 func newUsersWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Logger) func() {
@@ -165,7 +215,7 @@ func newUsersWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Logge
 			zap.String("context", "console"),
 		)
 
-		if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+		if p.Config().Demo() {
 			logger.Info("Demo credentials are set, skip",
 				zap.String("context", "console"),
 			)
@@ -180,8 +230,7 @@ func newUsersWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Logge
 			)
 		}
 
-		ready, _ := p.IsReady()
-		if ready {
+		if p.CacheStatus().Ready {
 			once.Do(func() {
 				logger.Info("Slack MCP Server is fully ready",
 					zap.String("context", "console"),
@@ -197,7 +246,7 @@ func newChannelsWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Lo
 			zap.String("context", "console"),
 		)
 
-		if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+		if p.Config().Demo() {
 			logger.Info("Demo credentials are set, skip.",
 				zap.String("context", "console"),
 			)
@@ -212,8 +261,7 @@ func newChannelsWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Lo
 			)
 		}
 
-		ready, _ := p.IsReady()
-		if ready {
+		if p.CacheStatus().Ready {
 			once.Do(func() {
 				logger.Info("Slack MCP Server is fully ready.",
 					zap.String("context", "console"),
@@ -223,34 +271,6 @@ func newChannelsWatcher(p *provider.ApiProvider, once *sync.Once, logger *zap.Lo
 	}
 }
 
-func validateToolConfig(config string) error {
-	if config == "" || config == "true" || config == "1" {
-		return nil
-	}
-
-	items := strings.Split(config, ",")
-	hasNegated := false
-	hasPositive := false
-
-	for _, item := range items {
-		item = strings.TrimSpace(item)
-		if item == "" {
-			continue
-		}
-		if strings.HasPrefix(item, "!") {
-			hasNegated = true
-		} else {
-			hasPositive = true
-		}
-	}
-
-	if hasNegated && hasPositive {
-		return fmt.Errorf("cannot mix allowed and disallowed (! prefixed) channels")
-	}
-
-	return nil
-}
-
 func newLogger(transport string) (*zap.Logger, error) {
 	atomicLevel := zap.NewAtomicLevelAt(zap.InfoLevel)
 	if envLevel := os.Getenv("SLACK_MCP_LOG_LEVEL"); envLevel != "" {
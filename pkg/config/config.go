@@ -0,0 +1,1106 @@
+// Package config loads the server settings from command-line flags, SLACK_MCP_*
+// environment variables and an optional YAML config file into one typed Config.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/cron"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultTransport = "stdio"
+	DefaultHost      = "127.0.0.1"
+	DefaultPort      = 13080
+)
+
+// DefaultBotTokenMethods are sent with the bot token in dual-token operation,
+// so messages get the bot's stable identity.
+var DefaultBotTokenMethods = []string{"chat.postMessage", "chat.scheduleMessage"}
+
+// BotRoutableMethods is the capability table of dual-token operation: the
+// Slack API methods either token can serve. Every other method, such as
+// search.messages and the history and user methods, needs the user token
+// and always uses it.
+var BotRoutableMethods = []string{
+	"chat.postMessage",
+	"chat.scheduleMessage",
+	"chat.delete",
+	"reactions.add",
+	"reactions.remove",
+	"conversations.invite",
+}
+
+// Config is the typed server configuration. Every field can be set in the
+// config file under its yaml key; fields with an env tag can also be set by
+// that environment variable. Only logging, SLACK_MCP_GOVSLACK, the
+// outbound HTTP client settings (proxy, TLS and user agent) and the
+// variables of the secret backends are read from the environment directly.
+type Config struct {
+	Transport    string   `yaml:"transport"`
+	Host         string   `yaml:"host" env:"SLACK_MCP_HOST"`
+	Port         int      `yaml:"port" env:"SLACK_MCP_PORT"`
+	EnabledTools []string `yaml:"enabled_tools" env:"SLACK_MCP_ENABLED_TOOLS"`
+	ToolsConfig  string   `yaml:"tools_config" env:"SLACK_MCP_TOOLS_CONFIG"`
+	Timezone     string   `yaml:"timezone" env:"SLACK_MCP_TIMEZONE"`
+
+	// APIKey is the bearer token clients of the sse, http and ws transports
+	// must send; empty leaves them unauthenticated. SSEAPIKey is its
+	// deprecated name, used when APIKey is empty.
+	APIKey    string `yaml:"api_key" env:"SLACK_MCP_API_KEY"`
+	SSEAPIKey string `yaml:"-" env:"SLACK_MCP_SSE_API_KEY"`
+
+	// ToolsGroups enable or disable tool groups over the groups of the tools
+	// config. SLACK_MCP_TOOLS_GROUP_<NAME>=true|false sets one from the
+	// environment.
+	ToolsGroups map[string]bool `yaml:"tools_groups"`
+
+	// Slack tokens, by priority: xoxp, then xoxb, then the xoxc/xoxd pair.
+	XOXPToken string `yaml:"xoxp_token" env:"SLACK_MCP_XOXP_TOKEN"`
+	XOXBToken string `yaml:"xoxb_token" env:"SLACK_MCP_XOXB_TOKEN"`
+	XOXCToken string `yaml:"xoxc_token" env:"SLACK_MCP_XOXC_TOKEN"`
+	XOXDToken string `yaml:"xoxd_token" env:"SLACK_MCP_XOXD_TOKEN"`
+
+	// BotTokenMethods are the Slack API methods sent with the bot token when
+	// both XOXPToken and XOXBToken are set. Empty means
+	// DefaultBotTokenMethods.
+	BotTokenMethods []string `yaml:"bot_token_methods" env:"SLACK_MCP_BOT_TOKEN_METHODS"`
+
+	// AuditToken is an Enterprise org-level user token with auditlogs:read
+	// for admin_audit_search; XOXPToken is used when empty.
+	AuditToken string `yaml:"audit_token" env:"SLACK_MCP_AUDIT_TOKEN"`
+
+	// AdminToken is an Enterprise org-level user token with admin.users:write
+	// for the admin_users_* tools; XOXPToken is used when empty.
+	AdminToken string `yaml:"admin_token" env:"SLACK_MCP_ADMIN_TOKEN"`
+
+	// Write tool policies. Empty keeps the tool off unless it is listed in
+	// EnabledTools; "true" allows every channel; otherwise a channel list,
+	// optionally "!"-negated.
+	AddMessageTool    string `yaml:"add_message_tool" env:"SLACK_MCP_ADD_MESSAGE_TOOL"`
+	ReactionTool      string `yaml:"reaction_tool" env:"SLACK_MCP_REACTION_TOOL"`
+	AttachmentTool    string `yaml:"attachment_tool" env:"SLACK_MCP_ATTACHMENT_TOOL"`
+	SavedListTool     string `yaml:"saved_list_tool" env:"SLACK_MCP_SAVED_LIST_TOOL"`
+	SavedCompleteTool string `yaml:"saved_complete_tool" env:"SLACK_MCP_SAVED_COMPLETE_TOOL"`
+	UserStatusTool    string `yaml:"user_status_tool" env:"SLACK_MCP_USER_STATUS_TOOL"`
+	UserProfileTool   string `yaml:"user_profile_tool" env:"SLACK_MCP_USER_PROFILE_TOOL"`
+	InviteTool        string `yaml:"invite_tool" env:"SLACK_MCP_INVITE_TOOL"`
+	ChannelAdminTool  string `yaml:"channel_admin_tool" env:"SLACK_MCP_CHANNEL_ADMIN_TOOL"`
+	CleanupTool       string `yaml:"cleanup_tool" env:"SLACK_MCP_CLEANUP_TOOL"`
+	ThreadFollowTool  string `yaml:"thread_follow_tool" env:"SLACK_MCP_THREAD_FOLLOW_TOOL"`
+	// AuditLogsTool enables admin_audit_search when "true"; it has no
+	// channel list.
+	AuditLogsTool string `yaml:"audit_logs_tool" env:"SLACK_MCP_AUDIT_LOGS_TOOL"`
+	// AssistantTool enables the assistant_threads_* tools when "true".
+	AssistantTool string `yaml:"assistant_tool" env:"SLACK_MCP_ASSISTANT_TOOL"`
+	// AdminUsersTool enables admin_users_invite and admin_users_remove when
+	// "true". Unlike other policies, listing the tools in EnabledTools does
+	// not enable them on its own.
+	AdminUsersTool string `yaml:"admin_users_tool" env:"SLACK_MCP_ADMIN_USERS_TOOL"`
+
+	AddMessageMark      string `yaml:"add_message_mark" env:"SLACK_MCP_ADD_MESSAGE_MARK"`
+	AddMessageUnfurling string `yaml:"add_message_unfurling" env:"SLACK_MCP_ADD_MESSAGE_UNFURLING"`
+	AddMessageIdentity  string `yaml:"add_message_identity" env:"SLACK_MCP_ADD_MESSAGE_IDENTITY"`
+
+	DMAllowlist      string   `yaml:"dm_allowlist" env:"SLACK_MCP_DM_ALLOWLIST"`
+	ChannelAllowlist []string `yaml:"channel_allowlist" env:"SLACK_MCP_CHANNEL_ALLOWLIST"`
+	ChannelDenylist  []string `yaml:"channel_denylist" env:"SLACK_MCP_CHANNEL_DENYLIST"`
+
+	// ChannelSuggestions picks how unresolved channel names are answered:
+	// "fuzzy" (default) suggests close cached names, "sampling" asks the
+	// client's model when it supports MCP sampling, "off" suggests nothing.
+	ChannelSuggestions string `yaml:"channel_suggestions" env:"SLACK_MCP_CHANNEL_SUGGESTIONS"`
+
+	// TokenCheckInterval is how often auth.test checks that the token still
+	// works, a Go duration; DefaultTokenCheckInterval when empty, "0" turns
+	// the check off.
+	TokenCheckInterval string `yaml:"token_check_interval" env:"SLACK_MCP_TOKEN_CHECK_INTERVAL"`
+	// TokenExpiresAt is when the token stops working, RFC 3339 or a date,
+	// e.g. the expiry of the browser's d cookie for xoxc/xoxd tokens. Slack
+	// does not report it, so it is only known when set here.
+	TokenExpiresAt string `yaml:"token_expires_at" env:"SLACK_MCP_TOKEN_EXPIRES_AT"`
+	// TokenExpiryWarning is how long before TokenExpiresAt warnings start;
+	// DefaultTokenExpiryWarning when empty.
+	TokenExpiryWarning string `yaml:"token_expiry_warning" env:"SLACK_MCP_TOKEN_EXPIRY_WARNING"`
+	// TokenAlertUser is a user ID sent a DM when the token is about to
+	// expire or stops working. Empty only logs.
+	TokenAlertUser string `yaml:"token_alert_user" env:"SLACK_MCP_TOKEN_ALERT_USER"`
+
+	// TranscriptDir is where conversations_transcript writes files with
+	// output=file. Empty allows inline transcripts only.
+	TranscriptDir string `yaml:"transcript_dir" env:"SLACK_MCP_TRANSCRIPT_DIR"`
+
+	// EmbeddingSink receives the messages fetched by the history, replies
+	// and transcript tools: posted as NDJSON to an http(s) URL, or appended
+	// as JSONL to any other value, a file path. Empty exports nothing.
+	EmbeddingSink string `yaml:"embedding_sink" env:"SLACK_MCP_EMBEDDING_SINK"`
+
+	// Classifier labels the message rows of the history, replies and search
+	// tools, and may drop rows, before they are returned: an http(s) URL
+	// posted each batch of rows, or the name of a classifier compiled in
+	// with handler.RegisterClassifier. Empty labels nothing.
+	Classifier string `yaml:"classifier" env:"SLACK_MCP_CLASSIFIER"`
+
+	// Redact masks sensitive content in every tool result and resource
+	// before it is returned: built-in rules, "api_keys", "credit_cards" and
+	// "emails", or "all". Empty masks nothing but RedactPatterns.
+	Redact []string `yaml:"redact" env:"SLACK_MCP_REDACT"`
+	// RedactPatterns are further rules, regular expressions; they are only
+	// read from the config file.
+	RedactPatterns []RedactPatternConfig `yaml:"redact_patterns"`
+
+	// WriteJournal is a file where write tool calls are recorded before
+	// they run and when they finish, so calls cut short by a crash are
+	// listed by pending_writes after a restart. Empty keeps no journal.
+	WriteJournal string `yaml:"write_journal" env:"SLACK_MCP_WRITE_JOURNAL"`
+
+	// UsersProfileFields, when "true", adds the custom profile field values
+	// of every active member to the users cache, which users.list leaves
+	// out, at the cost of one users.profile.get call per member on each
+	// cache refresh.
+	UsersProfileFields string `yaml:"users_profile_fields" env:"SLACK_MCP_USERS_PROFILE_FIELDS"`
+
+	// Store is where the users and channels caches, idempotency results
+	// and session contexts are kept: memory, file:///dir or
+	// redis://host:port/db. Replicas behind a load balancer share state by
+	// pointing at the same store. Empty keeps the caches in the
+	// SLACK_MCP_USERS_CACHE and SLACK_MCP_CHANNELS_CACHE files and the rest
+	// in memory.
+	Store string `yaml:"store" env:"SLACK_MCP_STORE"`
+
+	// UsersCache and ChannelsCache are the cache files used without a
+	// Store; empty names files per team in the user cache directory.
+	UsersCache    string `yaml:"users_cache" env:"SLACK_MCP_USERS_CACHE"`
+	ChannelsCache string `yaml:"channels_cache" env:"SLACK_MCP_CHANNELS_CACHE"`
+
+	// HTTPSessions picks how the http transport tracks sessions: "stateful"
+	// (default) issues session IDs, recorded in Store when one is set so
+	// any replica accepts them, and "stateless" issues none, for load
+	// balancers without a shared store at the cost of context_set.
+	HTTPSessions string `yaml:"http_sessions" env:"SLACK_MCP_HTTP_SESSIONS"`
+
+	// CacheTTL is how long the users and channels caches are used before
+	// they are fetched again, a Go duration or seconds; one hour when
+	// empty, "0" keeps them until a forced refresh.
+	CacheTTL string `yaml:"cache_ttl" env:"SLACK_MCP_CACHE_TTL"`
+	// MinRefreshInterval is the least time between forced cache refreshes,
+	// a Go duration or seconds; 30s when empty, "0" does not limit them.
+	MinRefreshInterval string `yaml:"min_refresh_interval" env:"SLACK_MCP_MIN_REFRESH_INTERVAL"`
+	// SecretsRefreshInterval is how often tokens loaded from secret
+	// backends are read again, a Go duration; 15m when empty.
+	SecretsRefreshInterval string `yaml:"secrets_refresh_interval" env:"SLACK_MCP_SECRETS_REFRESH_INTERVAL"`
+
+	// MultiUser, when "true", lets HTTP, SSE and WebSocket clients send
+	// their own Slack token; each token gets its own tool set, up to
+	// MultiUserMaxClients (DefaultMultiUserMaxClients when zero).
+	MultiUser           string `yaml:"multi_user" env:"SLACK_MCP_MULTI_USER"`
+	MultiUserMaxClients int    `yaml:"multi_user_max_clients" env:"SLACK_MCP_MULTI_USER_MAX_CLIENTS"`
+
+	// ResponseCache, when "true", memoizes read tool results.
+	// ResponseCacheTTLs are tool=duration pairs changing the TTL of a
+	// tool, "0" to leave it uncached.
+	ResponseCache     string   `yaml:"response_cache" env:"SLACK_MCP_RESPONSE_CACHE"`
+	ResponseCacheTTLs []string `yaml:"response_cache_ttls" env:"SLACK_MCP_RESPONSE_CACHE_TTLS"`
+
+	// WebhookURL receives an event for every tool call, signed with
+	// WebhookSecret when set; the arguments are included only when
+	// WebhookIncludeArguments is "true".
+	WebhookURL              string `yaml:"webhook_url" env:"SLACK_MCP_WEBHOOK_URL"`
+	WebhookSecret           string `yaml:"webhook_secret" env:"SLACK_MCP_WEBHOOK_SECRET"`
+	WebhookIncludeArguments string `yaml:"webhook_include_arguments" env:"SLACK_MCP_WEBHOOK_INCLUDE_ARGUMENTS"`
+
+	// CORSAllowedOrigins are the browser origins given CORS headers: exact
+	// origins, "https://*.example.com" wildcards or "*". EnforceOrigin,
+	// when "true", also refuses requests from any other origin.
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins" env:"SLACK_MCP_CORS_ALLOWED_ORIGINS"`
+	EnforceOrigin      string   `yaml:"enforce_origin" env:"SLACK_MCP_ENFORCE_ORIGIN"`
+
+	// ScopeCheck, unless "false", hides the tools the token lacks the
+	// scopes for.
+	ScopeCheck string `yaml:"scope_check" env:"SLACK_MCP_SCOPE_CHECK"`
+
+	// LogAPICalls, when "true", logs every Slack API call at debug level.
+	LogAPICalls string `yaml:"log_api_calls" env:"SLACK_MCP_LOG_API_CALLS"`
+
+	// ShutdownGracePeriod is how long shutdown waits for in-flight tool
+	// calls, a Go duration or seconds; 30s when empty.
+	ShutdownGracePeriod string `yaml:"shutdown_grace_period" env:"SLACK_MCP_SHUTDOWN_GRACE_PERIOD"`
+
+	// ResultResourceThreshold is the size in bytes above which tool results
+	// are served as slack://{ws}/results/{id} resources; zero keeps them
+	// inline. ResultResourceTTL is how long those resources are kept, a Go
+	// duration or seconds; 30m when empty. ResultResourceMaxBytes caps the
+	// bytes of all kept resources, 64 MiB when zero; the oldest are evicted.
+	ResultResourceThreshold int    `yaml:"result_resource_threshold" env:"SLACK_MCP_RESULT_RESOURCE_THRESHOLD"`
+	ResultResourceTTL       string `yaml:"result_resource_ttl" env:"SLACK_MCP_RESULT_RESOURCE_TTL"`
+	ResultResourceMaxBytes  int    `yaml:"result_resource_max_bytes" env:"SLACK_MCP_RESULT_RESOURCE_MAX_BYTES"`
+
+	// CSVDelimiter and CSVQuoting set the dialect of CSV tool results: a
+	// one-character delimiter or "tab" (default ","), and "minimal"
+	// (default, quote fields that need it) or "all".
+	CSVDelimiter string `yaml:"csv_delimiter" env:"SLACK_MCP_CSV_DELIMITER"`
+	CSVQuoting   string `yaml:"csv_quoting" env:"SLACK_MCP_CSV_QUOTING"`
+
+	// Digests are only read from the config file.
+	Digests []DigestConfig `yaml:"digests"`
+
+	// Quotas limit the tool calls and Slack API calls of each client; they
+	// are only read from the config file.
+	Quotas QuotaConfig `yaml:"quotas"`
+
+	// Workflows are the Slack workflows workflows_trigger may start; they
+	// are only read from the config file.
+	Workflows []WorkflowConfig `yaml:"workflows"`
+
+	// SearchTemplates are named argument sets of conversations_search_messages,
+	// picked with its template argument; they are only read from the config
+	// file.
+	SearchTemplates []SearchTemplateConfig `yaml:"search_templates"`
+
+	// SelfTest is set by --self-test: check the token and exit instead of
+	// serving. SelfTestChannel is the optional --self-test-channel.
+	SelfTest        bool   `yaml:"-"`
+	SelfTestChannel string `yaml:"-"`
+}
+
+const (
+	DefaultTokenCheckInterval = time.Hour
+	DefaultTokenExpiryWarning = 72 * time.Hour
+	// MinTokenCheckInterval keeps the token check from spending auth.test's
+	// rate limit.
+	MinTokenCheckInterval = time.Minute
+)
+
+const DefaultMultiUserMaxClients = 100
+
+const (
+	DefaultDigestLookback    = 24 * time.Hour
+	DefaultDigestMaxMessages = 50
+)
+
+// DigestConfig schedules a summary of recent messages in Channels, built on
+// every activation of the cron expression Schedule in the configured timezone.
+type DigestConfig struct {
+	Name     string   `yaml:"name"`
+	Schedule string   `yaml:"schedule"`
+	Channels []string `yaml:"channels"`
+	// Lookback is a Go duration such as "24h"; DefaultDigestLookback when empty.
+	Lookback string `yaml:"lookback"`
+	// PostTo is an optional channel the digest is posted to.
+	PostTo string `yaml:"post_to"`
+	// MaxMessages caps the messages per channel; DefaultDigestMaxMessages when zero.
+	MaxMessages int `yaml:"max_messages"`
+}
+
+// LookbackDuration returns Lookback, DefaultDigestLookback when unset or invalid.
+func (d DigestConfig) LookbackDuration() time.Duration {
+	lookback, err := time.ParseDuration(d.Lookback)
+	if err != nil || lookback <= 0 {
+		return DefaultDigestLookback
+	}
+	return lookback
+}
+
+// Limit returns MaxMessages, DefaultDigestMaxMessages when unset.
+func (d DigestConfig) Limit() int {
+	if d.MaxMessages <= 0 {
+		return DefaultDigestMaxMessages
+	}
+	return d.MaxMessages
+}
+
+// WorkflowConfig is a Slack workflow started through its webhook trigger,
+// e.g. https://hooks.slack.com/triggers/T0123/456/abc for a workflow built
+// in Workflow Builder or deployed by a platform app.
+type WorkflowConfig struct {
+	Name       string `yaml:"name"`
+	TriggerURL string `yaml:"trigger_url"`
+	// Description tells the model what the workflow does and when to use it.
+	Description string `yaml:"description"`
+	// Inputs are the variables the trigger expects. When set, each is
+	// required and no other is accepted.
+	Inputs []string `yaml:"inputs"`
+}
+
+// RedactPatternConfig masks the matches of Pattern, a Go regular
+// expression, as [REDACTED:<Name>].
+type RedactPatternConfig struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// SearchTemplateArgs are the conversations_search_messages arguments a
+// search template may set.
+var SearchTemplateArgs = []string{
+	"search_query", "search_modifiers",
+	"filter_in_channel", "filter_in_im_or_mpim", "filter_users_with", "filter_users_from",
+	"filter_date_before", "filter_date_after", "filter_date_on", "filter_date_during",
+	"filter_threads_only", "is_thread", "has_reactions", "has_files", "has_links",
+	"include_unfurls", "sort", "sort_dir", "limit", "render", "tz", "post_filter_regex", "filter_language",
+}
+
+// SearchTemplateConfig is a recurring search, e.g. the escalations in the
+// support channels, that agents run by name instead of spelling out its
+// filters.
+type SearchTemplateConfig struct {
+	Name string `yaml:"name"`
+	// Description tells the model what the template finds.
+	Description string `yaml:"description"`
+	// Args are conversations_search_messages arguments, strings, numbers
+	// or booleans, keyed by one of SearchTemplateArgs.
+	Args map[string]any `yaml:"args"`
+}
+
+// QuotaConfig limits what each client may use. Clients are told apart by
+// the bearer token they send: Clients names some of them and sets their
+// limits, Default applies to every other client, each counted on its own.
+type QuotaConfig struct {
+	Default QuotaLimits   `yaml:"default"`
+	Clients []QuotaClient `yaml:"clients"`
+}
+
+// QuotaLimits caps tool calls and the Slack API calls they make per minute
+// and per day (UTC). Zero means unlimited. The JSON names are used by the
+// quotas resource.
+type QuotaLimits struct {
+	RequestsPerMinute   int `yaml:"requests_per_minute" json:"requests_per_minute"`
+	RequestsPerDay      int `yaml:"requests_per_day" json:"requests_per_day"`
+	SlackCallsPerMinute int `yaml:"slack_calls_per_minute" json:"slack_calls_per_minute"`
+	SlackCallsPerDay    int `yaml:"slack_calls_per_day" json:"slack_calls_per_day"`
+}
+
+// QuotaClient is a client identified by its bearer token. The token is
+// accepted in addition to SLACK_MCP_API_KEY.
+type QuotaClient struct {
+	Name        string `yaml:"name"`
+	APIKey      string `yaml:"api_key"`
+	QuotaLimits `yaml:",inline"`
+}
+
+// Enabled reports whether any limit or client is configured.
+func (q QuotaConfig) Enabled() bool {
+	return q.Default != (QuotaLimits{}) || len(q.Clients) > 0
+}
+
+// Default returns the configuration used when nothing is set.
+func Default() *Config {
+	return &Config{
+		Transport: DefaultTransport,
+		Host:      DefaultHost,
+		Port:      DefaultPort,
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+// defaults, the config file (--config or SLACK_MCP_CONFIG_FILE), YAML given
+// inline in SLACK_MCP_CONFIG, environment variables and the flags in args.
+// It does not validate the result; call Validate once logging is set up.
+func Load(args []string) (*Config, error) {
+	var f struct {
+		transport, enabledTools, toolsConfig, configFile string
+		selfTestChannel                                  string
+		selfTest                                         bool
+	}
+	fs := flag.NewFlagSet("slack-mcp-server", flag.ContinueOnError)
+	fs.StringVar(&f.transport, "t", DefaultTransport, "Transport type (stdio, sse, http or ws)")
+	fs.StringVar(&f.transport, "transport", DefaultTransport, "Transport type (stdio, sse, http or ws)")
+	fs.StringVar(&f.enabledTools, "e", "", "Comma-separated list of enabled tools (empty = all tools)")
+	fs.StringVar(&f.enabledTools, "enabled-tools", "", "Comma-separated list of enabled tools (empty = all tools)")
+	fs.StringVar(&f.toolsConfig, "tools-config", "", "Path to a YAML file enabling tools by group or name and restricting their arguments")
+	fs.StringVar(&f.configFile, "config", "", "Path to a YAML config file")
+	fs.BoolVar(&f.selfTest, "self-test", false, "Check the token, caches and permissions, print a report and exit")
+	fs.StringVar(&f.selfTestChannel, "self-test-channel", "", "Channel ID or #name read, and checked for posting, by --self-test")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	if f.configFile == "" {
+		f.configFile = os.Getenv("SLACK_MCP_CONFIG_FILE")
+	}
+	if f.configFile != "" {
+		data, err := os.ReadFile(f.configFile)
+		if err != nil {
+			return nil, fmt.Errorf("read config file: %w", err)
+		}
+		if err := cfg.applyYAML(data); err != nil {
+			return nil, fmt.Errorf("parse config file %s: %w", f.configFile, err)
+		}
+	}
+	if inline := os.Getenv("SLACK_MCP_CONFIG"); strings.TrimSpace(inline) != "" {
+		if err := cfg.applyYAML([]byte(inline)); err != nil {
+			return nil, fmt.Errorf("parse SLACK_MCP_CONFIG: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "t", "transport":
+			cfg.Transport = f.transport
+		case "e", "enabled-tools":
+			cfg.EnabledTools = splitList(f.enabledTools)
+		case "tools-config":
+			cfg.ToolsConfig = f.toolsConfig
+		case "self-test":
+			cfg.SelfTest = f.selfTest
+		case "self-test-channel":
+			cfg.SelfTestChannel = f.selfTestChannel
+		}
+	})
+
+	return cfg, nil
+}
+
+// FromEnv is Load without flags, for callers that are not the main binary.
+func FromEnv() (*Config, error) {
+	return Load(nil)
+}
+
+func (c *Config) applyYAML(data []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// toolsGroupEnvPrefix starts the variables that set ToolsGroups.
+const toolsGroupEnvPrefix = "SLACK_MCP_TOOLS_GROUP_"
+
+// applyEnv overrides the fields that have an env tag with the non-empty
+// values of their environment variables, and ToolsGroups with the
+// SLACK_MCP_TOOLS_GROUP_<NAME> variables.
+func (c *Config) applyEnv() error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		raw := os.Getenv(name)
+		if name == "" || raw == "" {
+			continue
+		}
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: must be an integer", name, raw)
+			}
+			field.SetInt(int64(n))
+		case reflect.Slice:
+			field.Set(reflect.ValueOf(splitList(raw)))
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		name, raw, _ := strings.Cut(kv, "=")
+		group, ok := strings.CutPrefix(name, toolsGroupEnvPrefix)
+		if !ok || group == "" || raw == "" {
+			continue
+		}
+		var enabled bool
+		switch raw {
+		case "true", "1":
+			enabled = true
+		case "false", "0":
+		default:
+			return fmt.Errorf("invalid %s %q, allowed: true, false", name, raw)
+		}
+		if c.ToolsGroups == nil {
+			c.ToolsGroups = make(map[string]bool)
+		}
+		c.ToolsGroups[strings.ToLower(group)] = enabled
+	}
+	return nil
+}
+
+// Validate checks values that would otherwise only fail once a tool runs.
+func (c *Config) Validate() error {
+	switch c.Transport {
+	case "stdio", "sse", "http", "ws":
+	default:
+		return fmt.Errorf("invalid transport %q, allowed: stdio, sse, http, ws", c.Transport)
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d", c.Port)
+	}
+	if err := validateChannelPolicy(c.AddMessageTool); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_ADD_MESSAGE_TOOL: %w", err)
+	}
+	if err := validateChannelPolicy(c.InviteTool); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_INVITE_TOOL: %w", err)
+	}
+	if err := validateChannelPolicy(c.ChannelAdminTool); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_CHANNEL_ADMIN_TOOL: %w", err)
+	}
+	if err := validateChannelPolicy(c.CleanupTool); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_CLEANUP_TOOL: %w", err)
+	}
+	if err := validateChannelPolicy(c.ThreadFollowTool); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_THREAD_FOLLOW_TOOL: %w", err)
+	}
+	for _, m := range c.BotTokenMethods {
+		if !slices.Contains(BotRoutableMethods, m) {
+			return fmt.Errorf("invalid SLACK_MCP_BOT_TOKEN_METHODS entry %q, allowed: %s", m, strings.Join(BotRoutableMethods, ", "))
+		}
+	}
+	switch c.AuditLogsTool {
+	case "", "true":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_AUDIT_LOGS_TOOL %q, allowed: true or empty", c.AuditLogsTool)
+	}
+	switch c.UsersProfileFields {
+	case "", "true":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_USERS_PROFILE_FIELDS %q, allowed: true or empty", c.UsersProfileFields)
+	}
+	switch c.AssistantTool {
+	case "", "true":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_ASSISTANT_TOOL %q, allowed: true or empty", c.AssistantTool)
+	}
+	switch c.AdminUsersTool {
+	case "", "true":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_ADMIN_USERS_TOOL %q, allowed: true or empty", c.AdminUsersTool)
+	}
+	switch c.ChannelSuggestions {
+	case "", "fuzzy", "sampling", "off":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_CHANNEL_SUGGESTIONS %q, allowed: fuzzy, sampling, off", c.ChannelSuggestions)
+	}
+	switch c.HTTPSessions {
+	case "", "stateful", "stateless":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_HTTP_SESSIONS %q, allowed: stateful, stateless", c.HTTPSessions)
+	}
+	if err := c.validateServerSettings(); err != nil {
+		return err
+	}
+	if _, err := c.CSVComma(); err != nil {
+		return err
+	}
+	switch c.CSVQuoting {
+	case "", "minimal", "all":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_CSV_QUOTING %q, allowed: minimal, all", c.CSVQuoting)
+	}
+	if c.TranscriptDir != "" {
+		if info, err := os.Stat(c.TranscriptDir); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid SLACK_MCP_TRANSCRIPT_DIR %q: not an existing directory", c.TranscriptDir)
+		}
+	}
+	if c.WriteJournal != "" {
+		if info, err := os.Stat(filepath.Dir(c.WriteJournal)); err != nil || !info.IsDir() {
+			return fmt.Errorf("invalid SLACK_MCP_WRITE_JOURNAL %q: the file's directory does not exist", c.WriteJournal)
+		}
+	}
+	if err := store.Validate(c.Store); err != nil {
+		return fmt.Errorf("invalid SLACK_MCP_STORE %q: %w", c.Store, err)
+	}
+	if err := validateEmbeddingSink(c.EmbeddingSink); err != nil {
+		return fmt.Errorf("invalid SLACK_MCP_EMBEDDING_SINK %q: %w", c.EmbeddingSink, err)
+	}
+	if err := validateClassifier(c.Classifier); err != nil {
+		return fmt.Errorf("invalid SLACK_MCP_CLASSIFIER %q: %w", c.Classifier, err)
+	}
+	if _, err := c.Redactor(); err != nil {
+		return err
+	}
+	if err := c.validateTokenHealth(); err != nil {
+		return err
+	}
+	if _, err := text.LoadTimezone(c.Timezone); err != nil {
+		return fmt.Errorf("error in SLACK_MCP_TIMEZONE: %w", err)
+	}
+	if err := validateDigests(c.Digests); err != nil {
+		return fmt.Errorf("error in digests: %w", err)
+	}
+	if err := validateWorkflows(c.Workflows); err != nil {
+		return fmt.Errorf("error in workflows: %w", err)
+	}
+	if err := validateSearchTemplates(c.SearchTemplates); err != nil {
+		return fmt.Errorf("error in search_templates: %w", err)
+	}
+	if err := validateQuotas(c.Quotas); err != nil {
+		return fmt.Errorf("error in quotas: %w", err)
+	}
+	return nil
+}
+
+var digestNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// validateDigests checks that digest names are unique and usable in resource
+// URIs, and that schedules, channels and lookbacks are set correctly.
+func validateDigests(digests []DigestConfig) error {
+	seen := make(map[string]bool, len(digests))
+	for _, d := range digests {
+		if !digestNameRe.MatchString(d.Name) {
+			return fmt.Errorf("invalid digest name %q: use letters, digits, - and _", d.Name)
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("duplicate digest name %q", d.Name)
+		}
+		seen[d.Name] = true
+
+		if _, err := cron.Parse(d.Schedule); err != nil {
+			return fmt.Errorf("digest %q: %w", d.Name, err)
+		}
+		if len(d.Channels) == 0 {
+			return fmt.Errorf("digest %q: channels must not be empty", d.Name)
+		}
+		if d.Lookback != "" {
+			if lookback, err := time.ParseDuration(d.Lookback); err != nil || lookback <= 0 {
+				return fmt.Errorf("digest %q: invalid lookback %q, use a positive duration such as 24h", d.Name, d.Lookback)
+			}
+		}
+		if d.MaxMessages < 0 {
+			return fmt.Errorf("digest %q: max_messages must not be negative", d.Name)
+		}
+	}
+	return nil
+}
+
+// validateWorkflows checks that workflow names are unique and that every
+// trigger URL is a Slack webhook trigger.
+func validateWorkflows(workflows []WorkflowConfig) error {
+	seen := make(map[string]bool, len(workflows))
+	for _, w := range workflows {
+		if !digestNameRe.MatchString(w.Name) {
+			return fmt.Errorf("invalid workflow name %q: use letters, digits, - and _", w.Name)
+		}
+		if seen[w.Name] {
+			return fmt.Errorf("duplicate workflow name %q", w.Name)
+		}
+		seen[w.Name] = true
+
+		u, err := url.Parse(w.TriggerURL)
+		if err != nil || u.Scheme != "https" || u.Host != "hooks.slack.com" ||
+			!(strings.HasPrefix(u.Path, "/triggers/") || strings.HasPrefix(u.Path, "/workflows/")) {
+			return fmt.Errorf("workflow %q: trigger_url must be a webhook trigger URL starting with https://hooks.slack.com/triggers/ or https://hooks.slack.com/workflows/", w.Name)
+		}
+		inputs := make(map[string]bool, len(w.Inputs))
+		for _, in := range w.Inputs {
+			if strings.TrimSpace(in) == "" || inputs[in] {
+				return fmt.Errorf("workflow %q: inputs must be unique, non-empty variable names", w.Name)
+			}
+			inputs[in] = true
+		}
+	}
+	return nil
+}
+
+// validateSearchTemplates checks that template names are unique and that
+// every template sets known search arguments to plain values.
+func validateSearchTemplates(templates []SearchTemplateConfig) error {
+	seen := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		if !digestNameRe.MatchString(t.Name) {
+			return fmt.Errorf("invalid template name %q: use letters, digits, - and _", t.Name)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("duplicate template name %q", t.Name)
+		}
+		seen[t.Name] = true
+
+		if len(t.Args) == 0 {
+			return fmt.Errorf("template %q: args must not be empty", t.Name)
+		}
+		for k, v := range t.Args {
+			if !slices.Contains(SearchTemplateArgs, k) {
+				return fmt.Errorf("template %q: unknown argument %q, allowed: %s", t.Name, k, strings.Join(SearchTemplateArgs, ", "))
+			}
+			switch v.(type) {
+			case string, int, float64, bool:
+			default:
+				return fmt.Errorf("template %q: argument %q must be a string, number or boolean", t.Name, k)
+			}
+		}
+	}
+	return nil
+}
+
+// validateQuotas checks that client names and keys are set and unique and
+// that no limit is negative.
+func validateQuotas(q QuotaConfig) error {
+	if err := q.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	names := make(map[string]bool, len(q.Clients))
+	keys := make(map[string]bool, len(q.Clients))
+	for _, c := range q.Clients {
+		if c.Name == "" {
+			return errors.New("every client needs a name")
+		}
+		if names[c.Name] {
+			return fmt.Errorf("duplicate client name %q", c.Name)
+		}
+		names[c.Name] = true
+		if c.APIKey == "" {
+			return fmt.Errorf("client %q: api_key must not be empty", c.Name)
+		}
+		if keys[c.APIKey] {
+			return fmt.Errorf("client %q: api_key is used by another client", c.Name)
+		}
+		keys[c.APIKey] = true
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("client %q: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func (l QuotaLimits) validate() error {
+	if l.RequestsPerMinute < 0 || l.RequestsPerDay < 0 || l.SlackCallsPerMinute < 0 || l.SlackCallsPerDay < 0 {
+		return errors.New("limits must not be negative")
+	}
+	return nil
+}
+
+// validateTokenHealth checks the settings of the token health check.
+func (c *Config) validateTokenHealth() error {
+	if c.TokenCheckInterval != "" {
+		d, err := time.ParseDuration(c.TokenCheckInterval)
+		if err != nil || (d != 0 && d < MinTokenCheckInterval) {
+			return fmt.Errorf("invalid SLACK_MCP_TOKEN_CHECK_INTERVAL %q: use 0 or a duration of at least %s", c.TokenCheckInterval, MinTokenCheckInterval)
+		}
+	}
+	if c.TokenExpiresAt != "" {
+		if _, err := parseTokenExpiry(c.TokenExpiresAt); err != nil {
+			return fmt.Errorf("invalid SLACK_MCP_TOKEN_EXPIRES_AT %q: use RFC 3339 such as 2026-12-31T18:00:00Z or a date such as 2026-12-31", c.TokenExpiresAt)
+		}
+	}
+	if c.TokenExpiryWarning != "" {
+		if d, err := time.ParseDuration(c.TokenExpiryWarning); err != nil || d <= 0 {
+			return fmt.Errorf("invalid SLACK_MCP_TOKEN_EXPIRY_WARNING %q: use a positive duration such as 72h", c.TokenExpiryWarning)
+		}
+	}
+	if c.TokenAlertUser != "" && !strings.HasPrefix(c.TokenAlertUser, "U") && !strings.HasPrefix(c.TokenAlertUser, "W") {
+		return fmt.Errorf("invalid SLACK_MCP_TOKEN_ALERT_USER %q: use a user ID such as U0123ABCD", c.TokenAlertUser)
+	}
+	return nil
+}
+
+// validateServerSettings checks the cache, multi-user, response cache,
+// webhook, origin, shutdown and result resource settings.
+func (c *Config) validateServerSettings() error {
+	for _, d := range []struct{ name, value string }{
+		{"SLACK_MCP_CACHE_TTL", c.CacheTTL},
+		{"SLACK_MCP_MIN_REFRESH_INTERVAL", c.MinRefreshInterval},
+		{"SLACK_MCP_SHUTDOWN_GRACE_PERIOD", c.ShutdownGracePeriod},
+	} {
+		if _, err := ParseDuration(d.value); d.value != "" && err != nil {
+			return fmt.Errorf("invalid %s %q: use 0, a duration such as 30s or a number of seconds", d.name, d.value)
+		}
+	}
+	if c.ResultResourceTTL != "" {
+		if d, err := ParseDuration(c.ResultResourceTTL); err != nil || d == 0 {
+			return fmt.Errorf("invalid SLACK_MCP_RESULT_RESOURCE_TTL %q: use a positive duration such as 30m or a number of seconds", c.ResultResourceTTL)
+		}
+	}
+	if c.ResultResourceThreshold < 0 {
+		return fmt.Errorf("invalid SLACK_MCP_RESULT_RESOURCE_THRESHOLD %d: must not be negative", c.ResultResourceThreshold)
+	}
+	if c.ResultResourceMaxBytes < 0 {
+		return fmt.Errorf("invalid SLACK_MCP_RESULT_RESOURCE_MAX_BYTES %d: must not be negative", c.ResultResourceMaxBytes)
+	}
+	if c.SecretsRefreshInterval != "" {
+		if d, err := time.ParseDuration(c.SecretsRefreshInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid SLACK_MCP_SECRETS_REFRESH_INTERVAL %q: use a positive duration such as 15m", c.SecretsRefreshInterval)
+		}
+	}
+	for _, s := range []struct{ name, value string }{
+		{"SLACK_MCP_MULTI_USER", c.MultiUser},
+		{"SLACK_MCP_RESPONSE_CACHE", c.ResponseCache},
+		{"SLACK_MCP_WEBHOOK_INCLUDE_ARGUMENTS", c.WebhookIncludeArguments},
+		{"SLACK_MCP_ENFORCE_ORIGIN", c.EnforceOrigin},
+		{"SLACK_MCP_LOG_API_CALLS", c.LogAPICalls},
+	} {
+		switch s.value {
+		case "", "true", "1", "false", "0":
+		default:
+			return fmt.Errorf("invalid %s %q, allowed: true, false or empty", s.name, s.value)
+		}
+	}
+	switch c.ScopeCheck {
+	case "", "true", "false":
+	default:
+		return fmt.Errorf("invalid SLACK_MCP_SCOPE_CHECK %q, allowed: true, false or empty", c.ScopeCheck)
+	}
+	if c.MultiUserMaxClients < 0 {
+		return fmt.Errorf("invalid SLACK_MCP_MULTI_USER_MAX_CLIENTS %d: must not be negative", c.MultiUserMaxClients)
+	}
+	for _, pair := range c.ResponseCacheTTLs {
+		tool, raw, ok := strings.Cut(pair, "=")
+		if _, err := time.ParseDuration(strings.TrimSpace(raw)); !ok || strings.TrimSpace(tool) == "" || err != nil {
+			return fmt.Errorf("invalid SLACK_MCP_RESPONSE_CACHE_TTLS entry %q: use tool=duration, e.g. channels_list=10m", pair)
+		}
+	}
+	if c.WebhookURL != "" {
+		u, err := url.Parse(c.WebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return errors.New("invalid SLACK_MCP_WEBHOOK_URL: must be an absolute http or https URL")
+		}
+	}
+	return nil
+}
+
+// ParseDuration reads a Go duration such as "30s" or a number of seconds
+// such as "30". Negative values are refused.
+func ParseDuration(raw string) (time.Duration, error) {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		secs, serr := strconv.ParseInt(raw, 10, 64)
+		if serr != nil {
+			return 0, err
+		}
+		d = time.Duration(secs) * time.Second
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration %q", raw)
+	}
+	return d, nil
+}
+
+// Demo reports whether the demo tokens are set, with which the server
+// starts without calling Slack.
+func (c *Config) Demo() bool {
+	return c.XOXPToken == "demo" || (c.XOXCToken == "demo" && c.XOXDToken == "demo")
+}
+
+// ServerAPIKey returns APIKey, or the deprecated SSEAPIKey when it is empty.
+func (c *Config) ServerAPIKey() string {
+	if c.APIKey != "" {
+		return c.APIKey
+	}
+	return c.SSEAPIKey
+}
+
+// LogAPICallsEnabled reports whether LogAPICalls is on.
+func (c *Config) LogAPICallsEnabled() bool { return switchOn(c.LogAPICalls) }
+
+// MultiUserEnabled reports whether MultiUser is on.
+func (c *Config) MultiUserEnabled() bool { return switchOn(c.MultiUser) }
+
+// MultiUserLimit returns MultiUserMaxClients, DefaultMultiUserMaxClients
+// when unset.
+func (c *Config) MultiUserLimit() int {
+	if c.MultiUserMaxClients <= 0 {
+		return DefaultMultiUserMaxClients
+	}
+	return c.MultiUserMaxClients
+}
+
+// ResponseCacheEnabled reports whether ResponseCache is on.
+func (c *Config) ResponseCacheEnabled() bool { return switchOn(c.ResponseCache) }
+
+// ResponseCacheTTLOverrides returns the TTLs of ResponseCacheTTLs by tool,
+// skipping invalid entries. Zero or negative TTLs mean the tool is not
+// cached.
+func (c *Config) ResponseCacheTTLOverrides() map[string]time.Duration {
+	ttls := make(map[string]time.Duration, len(c.ResponseCacheTTLs))
+	for _, pair := range c.ResponseCacheTTLs {
+		tool, raw, ok := strings.Cut(pair, "=")
+		ttl, err := time.ParseDuration(strings.TrimSpace(raw))
+		if tool = strings.TrimSpace(tool); !ok || tool == "" || err != nil {
+			continue
+		}
+		ttls[tool] = ttl
+	}
+	return ttls
+}
+
+// WebhookArguments reports whether webhook events include the tool
+// arguments.
+func (c *Config) WebhookArguments() bool { return switchOn(c.WebhookIncludeArguments) }
+
+// OriginEnforced reports whether EnforceOrigin is on.
+func (c *Config) OriginEnforced() bool { return switchOn(c.EnforceOrigin) }
+
+// ScopeCheckEnabled reports whether tools are filtered by the token's
+// scopes, which is the default.
+func (c *Config) ScopeCheckEnabled() bool { return c.ScopeCheck != "false" }
+
+func switchOn(v string) bool { return v == "true" || v == "1" }
+
+// TokenCheckDuration returns TokenCheckInterval, DefaultTokenCheckInterval
+// when unset or invalid and 0 when the check is off.
+func (c *Config) TokenCheckDuration() time.Duration {
+	if c.TokenCheckInterval == "" {
+		return DefaultTokenCheckInterval
+	}
+	d, err := time.ParseDuration(c.TokenCheckInterval)
+	if err != nil || d < 0 {
+		return DefaultTokenCheckInterval
+	}
+	return d
+}
+
+// TokenExpiryTime returns TokenExpiresAt, zero when unset or invalid. A date
+// without time means midnight in the configured timezone.
+func (c *Config) TokenExpiryTime() time.Time {
+	if c.TokenExpiresAt == "" {
+		return time.Time{}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, c.TokenExpiresAt, c.Location()); err == nil {
+		return t
+	}
+	t, _ := parseTokenExpiry(c.TokenExpiresAt)
+	return t
+}
+
+// TokenWarningDuration returns TokenExpiryWarning, DefaultTokenExpiryWarning
+// when unset or invalid.
+func (c *Config) TokenWarningDuration() time.Duration {
+	d, err := time.ParseDuration(c.TokenExpiryWarning)
+	if err != nil || d <= 0 {
+		return DefaultTokenExpiryWarning
+	}
+	return d
+}
+
+func parseTokenExpiry(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, raw)
+}
+
+// CSVComma returns the CSVDelimiter rune.
+func (c *Config) CSVComma() (rune, error) {
+	switch c.CSVDelimiter {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(c.CSVDelimiter)
+	if size != len(c.CSVDelimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, fmt.Errorf("invalid SLACK_MCP_CSV_DELIMITER %q: must be one character other than a quote or newline, or \"tab\"", c.CSVDelimiter)
+	}
+	return r, nil
+}
+
+// Redactor returns the redaction rules of Redact and RedactPatterns.
+func (c *Config) Redactor() (*text.Redactor, error) {
+	r, err := text.NewRedactor(c.Redact)
+	if err != nil {
+		return nil, fmt.Errorf("error in SLACK_MCP_REDACT: %w", err)
+	}
+	for _, p := range c.RedactPatterns {
+		if err := r.AddPattern(p.Name, p.Pattern); err != nil {
+			return nil, fmt.Errorf("error in redact_patterns: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Location returns the Timezone location, UTC when unset or invalid.
+func (c *Config) Location() *time.Location {
+	loc, err := text.LoadTimezone(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// validateEmbeddingSink accepts an http(s) URL with a host, or a file path
+// in an existing directory.
+func validateEmbeddingSink(sink string) error {
+	if sink == "" {
+		return nil
+	}
+	if strings.HasPrefix(sink, "http://") || strings.HasPrefix(sink, "https://") {
+		if u, err := url.Parse(sink); err != nil || u.Host == "" {
+			return errors.New("not a valid URL")
+		}
+		return nil
+	}
+	if info, err := os.Stat(filepath.Dir(sink)); err != nil || !info.IsDir() {
+		return errors.New("the file's directory does not exist")
+	}
+	return nil
+}
+
+// validateClassifier accepts an http(s) URL with a host, or the name of a
+// compiled-in classifier. Whether the name is registered is checked when
+// the server starts.
+func validateClassifier(classifier string) error {
+	if classifier == "" {
+		return nil
+	}
+	if strings.Contains(classifier, "://") {
+		u, err := url.Parse(classifier)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("not a valid http(s) URL")
+		}
+		return nil
+	}
+	for _, r := range classifier {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return errors.New("a classifier name may only contain a-z, 0-9, - and _")
+		}
+	}
+	return nil
+}
+
+// validateChannelPolicy rejects tool policies that mix allowed and "!"
+// disallowed channels.
+func validateChannelPolicy(policy string) error {
+	if policy == "" || policy == "true" || policy == "1" {
+		return nil
+	}
+
+	hasNegated, hasPositive := false, false
+	for _, item := range strings.Split(policy, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.HasPrefix(item, "!") {
+			hasNegated = true
+		} else {
+			hasPositive = true
+		}
+	}
+
+	if hasNegated && hasPositive {
+		return errors.New("cannot mix allowed and disallowed (! prefixed) channels")
+	}
+	return nil
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
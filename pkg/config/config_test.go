@@ -0,0 +1,342 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"SLACK_MCP_CONFIG", "SLACK_MCP_CONFIG_FILE", "SLACK_MCP_HOST", "SLACK_MCP_PORT",
+		"SLACK_MCP_ENABLED_TOOLS", "SLACK_MCP_ADD_MESSAGE_TOOL", "SLACK_MCP_TIMEZONE", "SLACK_MCP_CHANNEL_ALLOWLIST"} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	clearEnv(t)
+	path := writeConfig(t, `
+transport: sse
+host: 0.0.0.0
+port: 9000
+add_message_tool: true
+channel_allowlist: ["#support-*"]
+timezone: Europe/Berlin
+`)
+	t.Setenv("SLACK_MCP_CONFIG", "port: 9100\nreaction_tool: C123\n")
+	t.Setenv("SLACK_MCP_PORT", "9200")
+	t.Setenv("SLACK_MCP_ENABLED_TOOLS", "channels_list")
+
+	cfg, err := Load([]string{"--config", path, "-t", "http", "--enabled-tools", "conversations_history, channels_list"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "http", cfg.Transport, "flag beats file")
+	assert.Equal(t, "0.0.0.0", cfg.Host, "file beats default")
+	assert.Equal(t, 9200, cfg.Port, "env beats inline config")
+	assert.Equal(t, "C123", cfg.ReactionTool, "inline config applies")
+	assert.Equal(t, "true", cfg.AddMessageTool, "YAML booleans are kept as policy strings")
+	assert.Equal(t, []string{"#support-*"}, cfg.ChannelAllowlist)
+	assert.Equal(t, []string{"conversations_history", "channels_list"}, cfg.EnabledTools, "flag beats env")
+	assert.Equal(t, "Europe/Berlin", cfg.Location().String())
+}
+
+func TestLoadConfigFileFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("SLACK_MCP_CONFIG_FILE", writeConfig(t, "host: 10.0.0.1\n"))
+
+	cfg, err := FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", cfg.Host)
+}
+
+func TestLoadSelfTest(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load([]string{"--self-test", "--self-test-channel", "#general"})
+	require.NoError(t, err)
+	assert.True(t, cfg.SelfTest)
+	assert.Equal(t, "#general", cfg.SelfTestChannel)
+
+	_, err = Load([]string{"--config", writeConfig(t, "self_test: true\n")})
+	assert.Error(t, err, "self-test is a flag only")
+}
+
+func TestLoadErrors(t *testing.T) {
+	clearEnv(t)
+
+	_, err := Load([]string{"--config", writeConfig(t, "add_mesage_tool: true\n")})
+	assert.ErrorContains(t, err, "add_mesage_tool")
+
+	t.Setenv("SLACK_MCP_PORT", "http")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "SLACK_MCP_PORT")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(*Config) {}, ""},
+		{"unknown transport", func(c *Config) { c.Transport = "grpc" }, "invalid transport"},
+		{"port out of range", func(c *Config) { c.Port = 70000 }, "invalid port"},
+		{"negated policy", func(c *Config) { c.AddMessageTool = "!C123,!C456" }, ""},
+		{"mixed policy", func(c *Config) { c.AddMessageTool = "C123,!C456" }, "cannot mix"},
+		{"mixed channel admin policy", func(c *Config) { c.ChannelAdminTool = "C123,!C456" }, "SLACK_MCP_CHANNEL_ADMIN_TOOL"},
+		{"mixed cleanup policy", func(c *Config) { c.CleanupTool = "C123,!C456" }, "SLACK_MCP_CLEANUP_TOOL"},
+		{"mixed thread follow policy", func(c *Config) { c.ThreadFollowTool = "!C123,C456" }, "SLACK_MCP_THREAD_FOLLOW_TOOL"},
+		{"unknown timezone", func(c *Config) { c.Timezone = "Mars/Olympus" }, "SLACK_MCP_TIMEZONE"},
+		{"channel suggestions", func(c *Config) { c.ChannelSuggestions = "sampling" }, ""},
+		{"unknown channel suggestions", func(c *Config) { c.ChannelSuggestions = "llm" }, "SLACK_MCP_CHANNEL_SUGGESTIONS"},
+		{"tab CSV delimiter", func(c *Config) { c.CSVDelimiter = "tab"; c.CSVQuoting = "all" }, ""},
+		{"long CSV delimiter", func(c *Config) { c.CSVDelimiter = "||" }, "SLACK_MCP_CSV_DELIMITER"},
+		{"quote CSV delimiter", func(c *Config) { c.CSVDelimiter = `"` }, "SLACK_MCP_CSV_DELIMITER"},
+		{"unknown CSV quoting", func(c *Config) { c.CSVQuoting = "none" }, "SLACK_MCP_CSV_QUOTING"},
+		{"audit logs tool", func(c *Config) { c.AuditLogsTool = "true" }, ""},
+		{"audit logs tool with channels", func(c *Config) { c.AuditLogsTool = "#general" }, "SLACK_MCP_AUDIT_LOGS_TOOL"},
+		{"users profile fields", func(c *Config) { c.UsersProfileFields = "true" }, ""},
+		{"users profile fields yes", func(c *Config) { c.UsersProfileFields = "yes" }, "SLACK_MCP_USERS_PROFILE_FIELDS"},
+		{"assistant tool with channels", func(c *Config) { c.AssistantTool = "D123" }, "SLACK_MCP_ASSISTANT_TOOL"},
+		{"admin users tool", func(c *Config) { c.AdminUsersTool = "true" }, ""},
+		{"admin users tool set to 1", func(c *Config) { c.AdminUsersTool = "1" }, "SLACK_MCP_ADMIN_USERS_TOOL"},
+		{"bot token methods", func(c *Config) { c.BotTokenMethods = []string{"chat.postMessage", "reactions.add"} }, ""},
+		{"bot token for search", func(c *Config) { c.BotTokenMethods = []string{"search.messages"} }, "SLACK_MCP_BOT_TOKEN_METHODS"},
+		{"token health", func(c *Config) {
+			c.TokenCheckInterval, c.TokenExpiresAt, c.TokenExpiryWarning, c.TokenAlertUser = "30m", "2026-12-31", "48h", "U0123ABCD"
+		}, ""},
+		{"token check off", func(c *Config) { c.TokenCheckInterval = "0" }, ""},
+		{"token check too often", func(c *Config) { c.TokenCheckInterval = "10s" }, "SLACK_MCP_TOKEN_CHECK_INTERVAL"},
+		{"token expiry timestamp", func(c *Config) { c.TokenExpiresAt = "2026-12-31T18:00:00+01:00" }, ""},
+		{"token expiry in words", func(c *Config) { c.TokenExpiresAt = "next week" }, "SLACK_MCP_TOKEN_EXPIRES_AT"},
+		{"negative token warning", func(c *Config) { c.TokenExpiryWarning = "-1h" }, "SLACK_MCP_TOKEN_EXPIRY_WARNING"},
+		{"token alert channel", func(c *Config) { c.TokenAlertUser = "#ops" }, "SLACK_MCP_TOKEN_ALERT_USER"},
+		{"redaction", func(c *Config) {
+			c.Redact = []string{"emails", "credit_cards"}
+			c.RedactPatterns = []RedactPatternConfig{{Name: "employee_id", Pattern: `EMP-\d{6}`}}
+		}, ""},
+		{"unknown redaction rule", func(c *Config) { c.Redact = []string{"phones"} }, "SLACK_MCP_REDACT"},
+		{"invalid redaction pattern", func(c *Config) {
+			c.RedactPatterns = []RedactPatternConfig{{Name: "broken", Pattern: "("}}
+		}, "redact_patterns"},
+		{"search template", func(c *Config) {
+			c.SearchTemplates = []SearchTemplateConfig{{Name: "support_escalations", Args: map[string]any{"filter_in_channel": "#support", "has_reactions": true, "limit": 50}}}
+		}, ""},
+		{"search template without args", func(c *Config) {
+			c.SearchTemplates = []SearchTemplateConfig{{Name: "empty"}}
+		}, "args must not be empty"},
+		{"search template with cursor", func(c *Config) {
+			c.SearchTemplates = []SearchTemplateConfig{{Name: "paged", Args: map[string]any{"cursor": "abc"}}}
+		}, `unknown argument "cursor"`},
+		{"search template with list", func(c *Config) {
+			c.SearchTemplates = []SearchTemplateConfig{{Name: "lists", Args: map[string]any{"search_query": []any{"a", "b"}}}}
+		}, "must be a string, number or boolean"},
+		{"duplicate search template", func(c *Config) {
+			c.SearchTemplates = []SearchTemplateConfig{{Name: "t", Args: map[string]any{"is_thread": true}}, {Name: "t", Args: map[string]any{"is_thread": true}}}
+		}, "duplicate template name"},
+		{"transcript dir", func(c *Config) { c.TranscriptDir = os.TempDir() }, ""},
+		{"missing transcript dir", func(c *Config) { c.TranscriptDir = "/nonexistent/transcripts" }, "SLACK_MCP_TRANSCRIPT_DIR"},
+		{"embedding sink URL", func(c *Config) { c.EmbeddingSink = "https://rag.example.com/ingest" }, ""},
+		{"embedding sink file", func(c *Config) { c.EmbeddingSink = filepath.Join(os.TempDir(), "slack.jsonl") }, ""},
+		{"embedding sink URL without host", func(c *Config) { c.EmbeddingSink = "https:///ingest" }, "SLACK_MCP_EMBEDDING_SINK"},
+		{"embedding sink in missing dir", func(c *Config) { c.EmbeddingSink = "/nonexistent/slack.jsonl" }, "SLACK_MCP_EMBEDDING_SINK"},
+		{"classifier URL", func(c *Config) { c.Classifier = "https://classify.example.com/v1" }, ""},
+		{"classifier name", func(c *Config) { c.Classifier = "needs-reply" }, ""},
+		{"classifier URL without host", func(c *Config) { c.Classifier = "http:///v1" }, "SLACK_MCP_CLASSIFIER"},
+		{"classifier non-http URL", func(c *Config) { c.Classifier = "grpc://classify:9000" }, "SLACK_MCP_CLASSIFIER"},
+		{"classifier bad name", func(c *Config) { c.Classifier = "Needs Reply" }, "SLACK_MCP_CLASSIFIER"},
+		{"write journal", func(c *Config) { c.WriteJournal = filepath.Join(os.TempDir(), "write-journal.jsonl") }, ""},
+		{"write journal in missing dir", func(c *Config) { c.WriteJournal = "/nonexistent/write-journal.jsonl" }, "SLACK_MCP_WRITE_JOURNAL"},
+		{"redis store", func(c *Config) { c.Store = "redis://cache:6379/1" }, ""},
+		{"file store", func(c *Config) { c.Store = "file:///var/lib/slack-mcp" }, ""},
+		{"sqlite store without file", func(c *Config) { c.Store = "sqlite:///var/lib/" }, "SLACK_MCP_STORE"},
+		{"unknown store", func(c *Config) { c.Store = "etcd://cache:2379" }, "SLACK_MCP_STORE"},
+		{"stateless http sessions", func(c *Config) { c.HTTPSessions = "stateless" }, ""},
+		{"bad http sessions", func(c *Config) { c.HTTPSessions = "sticky" }, "SLACK_MCP_HTTP_SESSIONS"},
+		{"server settings", func(c *Config) {
+			c.CacheTTL, c.MinRefreshInterval, c.ShutdownGracePeriod, c.SecretsRefreshInterval = "0", "60", "1m", "5m"
+			c.MultiUser, c.MultiUserMaxClients, c.ResponseCache, c.ScopeCheck = "1", 20, "true", "false"
+			c.ResponseCacheTTLs = []string{"channels_list=10m", "users_search=0"}
+			c.WebhookURL, c.WebhookIncludeArguments, c.EnforceOrigin = "https://hooks.example.com/mcp", "true", "true"
+		}, ""},
+		{"negative cache TTL", func(c *Config) { c.CacheTTL = "-1h" }, "SLACK_MCP_CACHE_TTL"},
+		{"min refresh interval in words", func(c *Config) { c.MinRefreshInterval = "often" }, "SLACK_MCP_MIN_REFRESH_INTERVAL"},
+		{"bad shutdown grace period", func(c *Config) { c.ShutdownGracePeriod = "soon" }, "SLACK_MCP_SHUTDOWN_GRACE_PERIOD"},
+		{"secrets refresh in seconds", func(c *Config) { c.SecretsRefreshInterval = "900" }, "SLACK_MCP_SECRETS_REFRESH_INTERVAL"},
+		{"multi user yes", func(c *Config) { c.MultiUser = "yes" }, "SLACK_MCP_MULTI_USER"},
+		{"negative multi user clients", func(c *Config) { c.MultiUserMaxClients = -1 }, "SLACK_MCP_MULTI_USER_MAX_CLIENTS"},
+		{"response cache TTL without tool", func(c *Config) { c.ResponseCacheTTLs = []string{"10m"} }, "SLACK_MCP_RESPONSE_CACHE_TTLS"},
+		{"scope check off", func(c *Config) { c.ScopeCheck = "0" }, "SLACK_MCP_SCOPE_CHECK"},
+		{"webhook URL without scheme", func(c *Config) { c.WebhookURL = "hooks.example.com/mcp" }, "SLACK_MCP_WEBHOOK_URL"},
+		{"enforce origin on", func(c *Config) { c.EnforceOrigin = "on" }, "SLACK_MCP_ENFORCE_ORIGIN"},
+		{"log API calls yes", func(c *Config) { c.LogAPICalls = "yes" }, "SLACK_MCP_LOG_API_CALLS"},
+		{"result resources", func(c *Config) { c.ResultResourceThreshold, c.ResultResourceTTL = 32768, "1h" }, ""},
+		{"result resource TTL zero", func(c *Config) { c.ResultResourceTTL = "0" }, "SLACK_MCP_RESULT_RESOURCE_TTL"},
+		{"negative result resource threshold", func(c *Config) { c.ResultResourceThreshold = -1 }, "SLACK_MCP_RESULT_RESOURCE_THRESHOLD"},
+		{"negative result resource max bytes", func(c *Config) { c.ResultResourceMaxBytes = -1 }, "SLACK_MCP_RESULT_RESOURCE_MAX_BYTES"},
+		{"digest", func(c *Config) {
+			c.Digests = []DigestConfig{{Name: "daily", Schedule: "0 9 * * 1-5", Channels: []string{"#general"}, Lookback: "24h"}}
+		}, ""},
+		{"digest bad schedule", func(c *Config) {
+			c.Digests = []DigestConfig{{Name: "daily", Schedule: "0 25 * * *", Channels: []string{"#general"}}}
+		}, "invalid cron expression"},
+		{"digest bad name", func(c *Config) {
+			c.Digests = []DigestConfig{{Name: "daily/ops", Schedule: "@daily", Channels: []string{"#general"}}}
+		}, "invalid digest name"},
+		{"digest without channels", func(c *Config) {
+			c.Digests = []DigestConfig{{Name: "daily", Schedule: "@daily"}}
+		}, "channels must not be empty"},
+		{"digest bad lookback", func(c *Config) {
+			c.Digests = []DigestConfig{{Name: "daily", Schedule: "@daily", Channels: []string{"#general"}, Lookback: "1 day"}}
+		}, "invalid lookback"},
+		{"duplicate digest", func(c *Config) {
+			d := DigestConfig{Name: "daily", Schedule: "@daily", Channels: []string{"#general"}}
+			c.Digests = []DigestConfig{d, d}
+		}, "duplicate digest name"},
+		{"workflow", func(c *Config) {
+			c.Workflows = []WorkflowConfig{{Name: "access_request", TriggerURL: "https://hooks.slack.com/triggers/T0123/456/abc", Inputs: []string{"system", "reason"}}}
+		}, ""},
+		{"workflow builder webhook", func(c *Config) {
+			c.Workflows = []WorkflowConfig{{Name: "oncall", TriggerURL: "https://hooks.slack.com/workflows/T0123/A456/789/abc"}}
+		}, ""},
+		{"workflow URL elsewhere", func(c *Config) {
+			c.Workflows = []WorkflowConfig{{Name: "access_request", TriggerURL: "https://example.com/triggers/T0123/456/abc"}}
+		}, "trigger_url must be a webhook trigger URL"},
+		{"workflow incoming webhook", func(c *Config) {
+			c.Workflows = []WorkflowConfig{{Name: "access_request", TriggerURL: "https://hooks.slack.com/services/T0123/B456/abc"}}
+		}, "trigger_url must be a webhook trigger URL"},
+		{"duplicate workflow", func(c *Config) {
+			w := WorkflowConfig{Name: "oncall", TriggerURL: "https://hooks.slack.com/triggers/T0123/456/abc"}
+			c.Workflows = []WorkflowConfig{w, w}
+		}, "duplicate workflow name"},
+		{"workflow duplicate input", func(c *Config) {
+			c.Workflows = []WorkflowConfig{{Name: "oncall", TriggerURL: "https://hooks.slack.com/triggers/T0123/456/abc", Inputs: []string{"a", "a"}}}
+		}, "inputs must be unique"},
+		{"quotas", func(c *Config) {
+			c.Quotas = QuotaConfig{
+				Default: QuotaLimits{RequestsPerMinute: 60},
+				Clients: []QuotaClient{{Name: "ci", APIKey: "k1", QuotaLimits: QuotaLimits{SlackCallsPerDay: 1000}}},
+			}
+		}, ""},
+		{"negative quota", func(c *Config) { c.Quotas.Default.RequestsPerDay = -1 }, "must not be negative"},
+		{"quota client without key", func(c *Config) { c.Quotas.Clients = []QuotaClient{{Name: "ci"}} }, "api_key must not be empty"},
+		{"quota clients sharing a key", func(c *Config) {
+			c.Quotas.Clients = []QuotaClient{{Name: "a", APIKey: "k"}, {Name: "b", APIKey: "k"}}
+		}, "used by another client"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.mutate(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenHealthSettings(t *testing.T) {
+	c := Default()
+	assert.Equal(t, DefaultTokenCheckInterval, c.TokenCheckDuration())
+	assert.Equal(t, DefaultTokenExpiryWarning, c.TokenWarningDuration())
+	assert.True(t, c.TokenExpiryTime().IsZero())
+
+	c.TokenCheckInterval, c.TokenExpiryWarning = "0", "24h"
+	assert.Zero(t, c.TokenCheckDuration(), "0 turns the check off")
+	assert.Equal(t, 24*time.Hour, c.TokenWarningDuration())
+
+	c.Timezone, c.TokenExpiresAt = "Europe/Berlin", "2026-12-31"
+	assert.Equal(t, "2026-12-30T23:00:00Z", c.TokenExpiryTime().UTC().Format(time.RFC3339), "dates are midnight in the configured timezone")
+	c.TokenExpiresAt = "2026-12-31T18:00:00Z"
+	assert.Equal(t, "2026-12-31T18:00:00Z", c.TokenExpiryTime().UTC().Format(time.RFC3339))
+}
+
+func TestServerSettings(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("SLACK_MCP_MULTI_USER", "true")
+	t.Setenv("SLACK_MCP_MULTI_USER_MAX_CLIENTS", "25")
+	t.Setenv("SLACK_MCP_RESPONSE_CACHE_TTLS", "channels_list=10m, users_search=0")
+	t.Setenv("SLACK_MCP_CORS_ALLOWED_ORIGINS", "https://app.example.com,https://*.corp.example")
+	t.Setenv("SLACK_MCP_SCOPE_CHECK", "false")
+	t.Setenv("SLACK_MCP_LOG_API_CALLS", "1")
+
+	c, err := Load(nil)
+	require.NoError(t, err)
+	require.NoError(t, c.Validate())
+	assert.True(t, c.MultiUserEnabled())
+	assert.Equal(t, 25, c.MultiUserLimit())
+	assert.Equal(t, map[string]time.Duration{"channels_list": 10 * time.Minute, "users_search": 0}, c.ResponseCacheTTLOverrides())
+	assert.Equal(t, []string{"https://app.example.com", "https://*.corp.example"}, c.CORSAllowedOrigins)
+	assert.False(t, c.ScopeCheckEnabled())
+	assert.False(t, c.ResponseCacheEnabled())
+	assert.False(t, c.OriginEnforced())
+	assert.True(t, c.LogAPICallsEnabled())
+
+	d := Default()
+	assert.False(t, d.MultiUserEnabled())
+	assert.Equal(t, DefaultMultiUserMaxClients, d.MultiUserLimit())
+	assert.True(t, d.ScopeCheckEnabled())
+	assert.False(t, d.LogAPICallsEnabled())
+}
+
+func TestEnvSettings(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("SLACK_MCP_API_KEY", "")
+	t.Setenv("SLACK_MCP_SSE_API_KEY", "old-key")
+	t.Setenv("SLACK_MCP_USERS_CACHE", "/tmp/users.json")
+	t.Setenv("SLACK_MCP_TOOLS_GROUP_READ", "false")
+	t.Setenv("SLACK_MCP_TOOLS_GROUP_USERGROUPS", "1")
+	t.Setenv("SLACK_MCP_XOXP_TOKEN", "demo")
+
+	c, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "old-key", c.ServerAPIKey(), "deprecated name is used without SLACK_MCP_API_KEY")
+	assert.Equal(t, "/tmp/users.json", c.UsersCache)
+	assert.Equal(t, map[string]bool{"read": false, "usergroups": true}, c.ToolsGroups)
+	assert.True(t, c.Demo())
+
+	t.Setenv("SLACK_MCP_API_KEY", "new-key")
+	c, err = Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", c.ServerAPIKey())
+
+	t.Setenv("SLACK_MCP_TOOLS_GROUP_READ", "off")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, `invalid SLACK_MCP_TOOLS_GROUP_READ "off"`)
+}
+
+func TestParseDuration(t *testing.T) {
+	for raw, want := range map[string]time.Duration{"0": 0, "45": 45 * time.Second, "1m30s": 90 * time.Second} {
+		d, err := ParseDuration(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, d, raw)
+	}
+	for _, raw := range []string{"", "-5", "-1s", "soon"} {
+		_, err := ParseDuration(raw)
+		assert.Error(t, err, raw)
+	}
+}
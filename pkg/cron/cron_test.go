@@ -0,0 +1,51 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *"} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Wednesday 2025-01-15 10:17:30 in Berlin.
+	from := time.Date(2025, 1, 15, 10, 17, 30, 0, berlin)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 18, 0, 0, berlin)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 30, 0, 0, berlin)},
+		{"0 9 * * *", time.Date(2025, 1, 16, 9, 0, 0, 0, berlin)},
+		{"0 9 * * 1-5", time.Date(2025, 1, 16, 9, 0, 0, 0, berlin)},
+		{"30 8 * * 1", time.Date(2025, 1, 20, 8, 30, 0, 0, berlin)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, berlin)},
+		{"0 12 1 * *", time.Date(2025, 2, 1, 12, 0, 0, 0, berlin)},
+		{"0 12 1 * 5", time.Date(2025, 1, 17, 12, 0, 0, 0, berlin)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, berlin)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, berlin)},
+		{"@weekly", time.Date(2025, 1, 19, 0, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(from))
+		})
+	}
+
+	never, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, never.Next(from).IsZero())
+}
@@ -0,0 +1,443 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultActivityLimit = 200
+	maxActivityLimit     = 1000
+	defaultActivityDays  = 7
+
+	activitySearchPageSize = 100
+	// activityReactionLookups caps the reactions.get calls for reactions
+	// received, one per own message that has reactions.
+	activityReactionLookups = 30
+	// activityReactionPages caps the reactions.list pages for reactions given.
+	activityReactionPages = 5
+	// activityJoinScanChannels caps the channels whose history is scanned for
+	// the user's channel_join messages.
+	activityJoinScanChannels = 50
+)
+
+const (
+	activityMessages          = "messages"
+	activityReactionsGiven    = "reactions_given"
+	activityReactionsReceived = "reactions_received"
+	activityChannelsJoined    = "channels_joined"
+)
+
+var activitySections = []string{activityMessages, activityReactionsGiven, activityReactionsReceived, activityChannelsJoined}
+
+// ActivityEvent is the CSV output row for activity_feed.
+type ActivityEvent struct {
+	Kind        string `csv:"kind"`
+	MsgID       string `csv:"msgID"`
+	Time        string `csv:"time"`
+	TimeHuman   string `csv:"timeHuman"`
+	ChannelID   string `csv:"channelID"`
+	ChannelName string `csv:"channelName"`
+	ThreadTs    string `csv:"threadTs"`
+	Reaction    string `csv:"reaction"`
+	Count       int    `csv:"count"`
+	Snippet     string `csv:"snippet"`
+	Permalink   string `csv:"permalink"`
+}
+
+// ActivitySummary is the JSON block appended to activity_feed results.
+// Truncated lists the sections that hit a lookup cap and may be incomplete.
+type ActivitySummary struct {
+	Since             string   `json:"since"`
+	Until             string   `json:"until"`
+	MessagesSent      int      `json:"messages_sent"`
+	ThreadReplies     int      `json:"thread_replies"`
+	ThreadsRepliedTo  int      `json:"threads_replied_to"`
+	ReactionsGiven    int      `json:"reactions_given"`
+	ReactionsReceived int      `json:"reactions_received"`
+	ChannelsJoined    int      `json:"channels_joined"`
+	Truncated         []string `json:"truncated,omitempty"`
+}
+
+type activityWindow struct {
+	from, to time.Time // [from, to)
+	// after and before are the exclusive search.messages date filters.
+	after, before string
+	loc           *time.Location
+}
+
+func (w activityWindow) contains(ts string) bool {
+	t, err := text.SlackTimestampToTime(ts)
+	return err == nil && !t.Before(w.from) && t.Before(w.to)
+}
+
+func (w activityWindow) searchFilter() string {
+	return " after:" + w.after + " before:" + w.before
+}
+
+type ActivityHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewActivityHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *ActivityHandler {
+	return &ActivityHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// ActivityFeedHandler aggregates what the authenticated user did over a date
+// range: messages and thread replies sent, reactions given and received, and
+// channels joined, newest first, followed by JSON totals.
+func (h *ActivityHandler) ActivityFeedHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("ActivityFeedHandler called", zap.Any("params", request.Params))
+
+	if ready, err := h.apiProvider.IsReady(); !ready {
+		h.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	limit := request.GetInt("limit", defaultActivityLimit)
+	if limit < 1 || limit > maxActivityLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxActivityLimit, limit)
+	}
+	sections, err := parseActivitySections(request.GetString("include", ""))
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	window, err := parseActivityWindow(request.GetString("since", ""), request.GetString("until", ""), loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	ar, err := h.apiProvider.Slack().AuthTestContext(ctx)
+	if err != nil {
+		h.logger.Error("Slack AuthTest failed", zap.Error(err))
+		return nil, err
+	}
+
+	summary := ActivitySummary{
+		Since: window.from.Format("2006-01-02"),
+		Until: window.to.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	var events []ActivityEvent
+	collectors := map[string]func(context.Context, string, activityWindow, int, *ActivitySummary) ([]ActivityEvent, bool, error){
+		activityMessages:          h.messagesSent,
+		activityReactionsGiven:    h.reactionsGiven,
+		activityReactionsReceived: h.reactionsReceived,
+		activityChannelsJoined:    h.channelsJoined,
+	}
+	for _, section := range sections {
+		found, truncated, err := collectors[section](ctx, ar.UserID, window, limit, &summary)
+		if err != nil {
+			h.logger.Error("Failed to collect activity", zap.String("section", section), zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", section, err)
+		}
+		if truncated {
+			summary.Truncated = append(summary.Truncated, section)
+		}
+		events = append(events, found...)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return slackTsLess(events[j].MsgID, events[i].MsgID)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&events)
+	if err != nil {
+		h.logger.Error("Failed to marshal activity to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), summary)
+}
+
+func (h *ActivityHandler) messagesSent(ctx context.Context, userID string, w activityWindow, limit int, summary *ActivitySummary) ([]ActivityEvent, bool, error) {
+	matches, truncated, err := h.searchAll(ctx, "from:<@"+userID+">"+w.searchFilter(), limit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	threads := make(map[string]bool)
+	events := make([]ActivityEvent, 0, len(matches))
+	for _, m := range matches {
+		if !h.apiProvider.ChannelAllowed(m.Channel.ID) {
+			continue
+		}
+		ev := h.event("message", m.Channel.ID, m.Channel.Name, m.Timestamp, m.Text, w.loc)
+		ev.Permalink = m.Permalink
+		if threadTs, _ := extractThreadTS(m.Permalink); threadTs != "" && threadTs != m.Timestamp {
+			ev.Kind = "thread_reply"
+			ev.ThreadTs = threadTs
+			summary.ThreadReplies++
+			threads[m.Channel.ID+"/"+threadTs] = true
+		}
+		events = append(events, ev)
+	}
+	summary.MessagesSent = len(events)
+	summary.ThreadsRepliedTo = len(threads)
+	return events, truncated, nil
+}
+
+// reactionsGiven walks reactions.list, which is ordered by when the reaction
+// was added. Reactions carry no time of their own, so the message time is
+// matched against the window.
+func (h *ActivityHandler) reactionsGiven(ctx context.Context, userID string, w activityWindow, limit int, summary *ActivitySummary) ([]ActivityEvent, bool, error) {
+	var events []ActivityEvent
+	for page := 1; page <= activityReactionPages; page++ {
+		items, paging, err := h.apiProvider.Slack().ListReactionsContext(ctx, slack.ListReactionsParameters{
+			User:  userID,
+			Count: activitySearchPageSize,
+			Page:  page,
+			Full:  true,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, item := range items {
+			if item.Type != slack.TYPE_MESSAGE || item.Message == nil ||
+				!w.contains(item.Message.Timestamp) || !h.apiProvider.ChannelAllowed(item.Channel) {
+				continue
+			}
+			for _, r := range item.Reactions {
+				if !slices.Contains(r.Users, userID) {
+					continue
+				}
+				ev := h.event("reaction_given", item.Channel, "", item.Message.Timestamp, item.Message.Text, w.loc)
+				ev.ThreadTs = item.Message.ThreadTimestamp
+				ev.Reaction = r.Name
+				ev.Count = 1
+				events = append(events, ev)
+			}
+		}
+		if len(events) >= limit {
+			break
+		}
+		if paging == nil || page >= paging.Pages {
+			summary.ReactionsGiven = len(events)
+			return events, false, nil
+		}
+	}
+	summary.ReactionsGiven = len(events)
+	return events, true, nil
+}
+
+// reactionsReceived looks up the reactions on the user's own messages that
+// have any, excluding the user's own reactions.
+func (h *ActivityHandler) reactionsReceived(ctx context.Context, userID string, w activityWindow, _ int, summary *ActivitySummary) ([]ActivityEvent, bool, error) {
+	matches, truncated, err := h.searchAll(ctx, "from:<@"+userID+"> has:reaction"+w.searchFilter(), activityReactionLookups)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var events []ActivityEvent
+	for _, m := range matches {
+		if !h.apiProvider.ChannelAllowed(m.Channel.ID) {
+			continue
+		}
+		reactions, err := h.apiProvider.Slack().GetReactionsContext(ctx, slack.NewRefToMessage(m.Channel.ID, m.Timestamp), slack.GetReactionsParameters{Full: true})
+		if err != nil {
+			h.logger.Warn("Failed to get reactions", zap.String("channel", m.Channel.ID), zap.String("ts", m.Timestamp), zap.Error(err))
+			continue
+		}
+		for _, r := range reactions {
+			count := r.Count
+			if slices.Contains(r.Users, userID) {
+				count--
+			}
+			if count <= 0 {
+				continue
+			}
+			ev := h.event("reaction_received", m.Channel.ID, m.Channel.Name, m.Timestamp, m.Text, w.loc)
+			ev.Reaction = r.Name
+			ev.Count = count
+			ev.Permalink = m.Permalink
+			events = append(events, ev)
+			summary.ReactionsReceived += count
+		}
+	}
+	return events, truncated, nil
+}
+
+// channelsJoined scans the window of the user's channels for their
+// channel_join messages, which search does not index.
+func (h *ActivityHandler) channelsJoined(ctx context.Context, userID string, w activityWindow, _ int, summary *ActivitySummary) ([]ActivityEvent, bool, error) {
+	var channels []slack.Channel
+	params := &slack.GetConversationsForUserParameters{
+		UserID:          userID,
+		Types:           []string{provider.PubChanType, provider.PrivateChanType},
+		Limit:           200,
+		ExcludeArchived: true,
+	}
+	for {
+		page, cursor, err := h.apiProvider.Slack().GetConversationsForUserContext(ctx, params)
+		if err != nil {
+			return nil, false, err
+		}
+		channels = append(channels, page...)
+		if cursor == "" || len(channels) > activityJoinScanChannels {
+			break
+		}
+		params.Cursor = cursor
+	}
+
+	truncated := false
+	if len(channels) > activityJoinScanChannels {
+		channels, truncated = channels[:activityJoinScanChannels], true
+	}
+
+	var events []ActivityEvent
+	for _, c := range channels {
+		if !h.apiProvider.ChannelAllowed(c.ID) {
+			continue
+		}
+		history, err := h.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: c.ID,
+			Oldest:    fmt.Sprintf("%d.000000", w.from.Unix()),
+			Latest:    fmt.Sprintf("%d.000000", w.to.Unix()),
+			Limit:     100,
+		})
+		if err != nil {
+			h.logger.Warn("Failed to scan channel for joins", zap.String("channel", c.ID), zap.Error(err))
+			continue
+		}
+		for _, msg := range history.Messages {
+			if msg.SubType == "channel_join" && msg.User == userID {
+				events = append(events, h.event("channel_joined", c.ID, c.Name, msg.Timestamp, "", w.loc))
+				break
+			}
+		}
+	}
+	summary.ChannelsJoined = len(events)
+	return events, truncated, nil
+}
+
+// searchAll pages through search.messages until maxResults matches are
+// collected, reporting whether more were available.
+func (h *ActivityHandler) searchAll(ctx context.Context, query string, maxResults int) ([]slack.SearchMessage, bool, error) {
+	h.logger.Debug("Searching activity", zap.String("query", query))
+
+	var matches []slack.SearchMessage
+	for page := 1; ; page++ {
+		res, _, err := h.apiProvider.Slack().SearchContext(ctx, query, slack.SearchParameters{
+			Sort:          "timestamp",
+			SortDirection: "desc",
+			Count:         min(maxResults, activitySearchPageSize),
+			Page:          page,
+		})
+		if err != nil {
+			h.logger.Error("Slack SearchContext failed", zap.String("query", query), zap.Error(err))
+			return nil, false, err
+		}
+		matches = append(matches, res.Matches...)
+		more := page < res.Paging.Pages
+		if len(matches) >= maxResults {
+			return matches[:maxResults], more || len(matches) > maxResults, nil
+		}
+		if !more || len(res.Matches) == 0 {
+			return matches, false, nil
+		}
+	}
+}
+
+func (h *ActivityHandler) event(kind, channelID, channelName, ts, msgText string, loc *time.Location) ActivityEvent {
+	if c, ok := h.apiProvider.ProvideChannelsMaps().Channels[channelID]; ok {
+		channelName = c.Name
+	} else if channelName != "" && !strings.HasPrefix(channelName, "#") && !strings.HasPrefix(channelName, "@") {
+		channelName = "#" + channelName
+	}
+
+	timestamp, timeHuman, err := text.FormatSlackTimestamp(ts, loc)
+	if err != nil {
+		timestamp = ts
+	}
+
+	snippet := text.ProcessText(msgText)
+	if r := []rune(snippet); len(r) > mentionSnippetLength {
+		snippet = string(r[:mentionSnippetLength]) + "…"
+	}
+
+	return ActivityEvent{
+		Kind:        kind,
+		MsgID:       ts,
+		Time:        timestamp,
+		TimeHuman:   timeHuman,
+		ChannelID:   channelID,
+		ChannelName: channelName,
+		Snippet:     snippet,
+	}
+}
+
+// parseActivitySections parses the comma-separated include parameter; empty
+// selects every section.
+func parseActivitySections(include string) ([]string, error) {
+	if strings.TrimSpace(include) == "" {
+		return activitySections, nil
+	}
+	var sections []string
+	for _, s := range strings.Split(include, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !slices.Contains(activitySections, s) {
+			return nil, fmt.Errorf("invalid include %q: allowed values are %s", s, strings.Join(activitySections, ", "))
+		}
+		if !slices.Contains(sections, s) {
+			sections = append(sections, s)
+		}
+	}
+	return sections, nil
+}
+
+// parseActivityWindow returns the days from since to until, both included, in
+// loc. since defaults to seven days ago and until to today.
+func parseActivityWindow(since, until string, loc *time.Location, now time.Time) (activityWindow, error) {
+	today := now.In(loc)
+	from := time.Date(today.Year(), today.Month(), today.Day()-defaultActivityDays+1, 0, 0, 0, 0, loc)
+	to := time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, loc)
+
+	if since = strings.TrimSpace(since); since != "" {
+		day, _, err := parseFlexibleDate(since)
+		if err != nil {
+			return activityWindow{}, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		from = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	}
+	if until = strings.TrimSpace(until); until != "" {
+		day, _, err := parseFlexibleDate(until)
+		if err != nil {
+			return activityWindow{}, fmt.Errorf("invalid until %q: %w", until, err)
+		}
+		to = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+	if !from.Before(to) {
+		return activityWindow{}, fmt.Errorf("since %s is after until %s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+
+	return activityWindow{
+		from:   from,
+		to:     to,
+		after:  from.AddDate(0, 0, -1).Format("2006-01-02"),
+		before: to.Format("2006-01-02"),
+		loc:    loc,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseActivityWindow(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 31, 23, 30, 0, 0, time.UTC) // already Feb 1 in Berlin
+
+	w, err := parseActivityWindow("", "", berlin, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 26, 0, 0, 0, 0, berlin), w.from)
+	assert.Equal(t, time.Date(2025, 2, 2, 0, 0, 0, 0, berlin), w.to)
+	assert.Equal(t, " after:2025-01-25 before:2025-02-02", w.searchFilter())
+
+	w, err = parseActivityWindow("2025-01-06", "2025-01-10", berlin, now)
+	require.NoError(t, err)
+	assert.True(t, w.contains("1736118000.000000"), "start of the first day")      // 2025-01-06 00:00 CET
+	assert.True(t, w.contains("1736549999.000000"), "end of the last day")         // 2025-01-10 23:59:59 CET
+	assert.False(t, w.contains("1736550000.000000"), "the day after is excluded")  // 2025-01-11 00:00 CET
+	assert.False(t, w.contains("1736117999.000000"), "the day before is excluded") // 2025-01-05 23:59:59 CET
+
+	_, err = parseActivityWindow("2025-01-10", "2025-01-06", berlin, now)
+	assert.ErrorContains(t, err, "is after until")
+	_, err = parseActivityWindow("someday", "", berlin, now)
+	assert.ErrorContains(t, err, "invalid since")
+}
+
+func TestUnitParseActivitySections(t *testing.T) {
+	sections, err := parseActivitySections("")
+	require.NoError(t, err)
+	assert.Equal(t, activitySections, sections)
+
+	sections, err = parseActivitySections(" reactions_given, messages,messages ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reactions_given", "messages"}, sections)
+
+	_, err = parseActivitySections("messages,files")
+	assert.ErrorContains(t, err, `invalid include "files"`)
+}
@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// AdminUserAction is the JSON result of admin_users_invite and
+// admin_users_remove.
+type AdminUserAction struct {
+	Action        string   `json:"action"`
+	TeamID        string   `json:"team_id"`
+	Email         string   `json:"email,omitempty"`
+	UserID        string   `json:"user_id,omitempty"`
+	ChannelIDs    []string `json:"channel_ids,omitempty"`
+	SessionsReset bool     `json:"sessions_reset,omitempty"`
+	Reason        string   `json:"reason"`
+}
+
+// AdminUsersInviteHandler invites someone by email to a workspace of an
+// Enterprise organization with admin.users.invite, e.g. for a joiner ticket.
+func (ch *ConversationsHandler) AdminUsersInviteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("AdminUsersInviteHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	if err := ch.adminUsersToolPolicy(); err != nil {
+		return nil, err
+	}
+	email := strings.TrimSpace(request.GetString("email", ""))
+	if _, err := mail.ParseAddress(email); err != nil || strings.Contains(email, "<") {
+		return nil, fmt.Errorf("invalid email %q", email)
+	}
+	reason, err := adminConfirmation(request, email)
+	if err != nil {
+		return nil, err
+	}
+	teamID, err := ch.adminTeamID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	channelIDs, err := ch.adminInviteChannels(ctx, request.GetString("channel_ids", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	action := AdminUserAction{Action: "invite", TeamID: teamID, Email: email, ChannelIDs: channelIDs, Reason: reason}
+	err = ch.apiProvider.AdminUsersInviteContext(ctx, provider.AdminUserInvite{
+		TeamID:        teamID,
+		Email:         email,
+		ChannelIDs:    channelIDs,
+		RealName:      request.GetString("real_name", ""),
+		CustomMessage: request.GetString("custom_message", ""),
+	})
+	ch.logAdminAction(action, err)
+	if err != nil {
+		return nil, err
+	}
+	return adminActionResult(action)
+}
+
+// AdminUsersRemoveHandler removes a user from a workspace of an Enterprise
+// organization with admin.users.remove and, unless told otherwise, signs them
+// out everywhere, e.g. for a leaver ticket. The org-wide account stays; it is
+// deactivated through SCIM or the admin console.
+func (ch *ConversationsHandler) AdminUsersRemoveHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("AdminUsersRemoveHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	if err := ch.adminUsersToolPolicy(); err != nil {
+		return nil, err
+	}
+	user := strings.TrimSpace(request.GetString("user", ""))
+	if user == "" {
+		return nil, errors.New("user is required")
+	}
+	reason, err := adminConfirmation(request, user)
+	if err != nil {
+		return nil, err
+	}
+	teamID, err := ch.adminTeamID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := ch.adminUserID(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	action := AdminUserAction{Action: "remove", TeamID: teamID, UserID: userID, Reason: reason}
+	if strings.Contains(user, "@") && !strings.HasPrefix(user, "@") {
+		action.Email = user
+	}
+	err = ch.apiProvider.AdminUsersRemoveContext(ctx, teamID, userID)
+	ch.logAdminAction(action, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.GetBool("reset_sessions", true) {
+		reset := AdminUserAction{Action: "session_reset", TeamID: teamID, UserID: userID, Email: action.Email, Reason: reason}
+		err := ch.apiProvider.AdminUsersSessionResetContext(ctx, userID)
+		ch.logAdminAction(reset, err)
+		if err != nil {
+			return nil, fmt.Errorf("user %s was removed, but resetting their sessions failed: %w", userID, err)
+		}
+		action.SessionsReset = true
+	}
+	return adminActionResult(action)
+}
+
+// adminConfirmation checks the mandatory confirmation of an admin user
+// action: confirm must repeat target exactly and reason must say why, e.g.
+// the ticket it comes from. It returns the reason.
+func adminConfirmation(request mcp.CallToolRequest, target string) (string, error) {
+	if confirm := strings.TrimSpace(request.GetString("confirm", "")); confirm != target {
+		return "", fmt.Errorf("confirm must repeat %q exactly to carry out this action", target)
+	}
+	reason := strings.TrimSpace(request.GetString("reason", ""))
+	if reason == "" {
+		return "", errors.New("reason is required, e.g. the ticket this action comes from; it is written to the audit log")
+	}
+	return reason, nil
+}
+
+// adminInviteChannels resolves the comma-separated channels invited people
+// join and checks them against the channel policy, so an invite cannot add
+// anyone to a channel the other tools are kept out of.
+func (ch *ConversationsHandler) adminInviteChannels(ctx context.Context, raw string) ([]string, error) {
+	var channelIDs []string
+	for _, c := range strings.Split(raw, ",") {
+		if c = strings.TrimSpace(c); c == "" {
+			continue
+		}
+		id, err := ch.resolveChannelID(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if err := ch.apiProvider.CheckChannel(id); err != nil {
+			ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", id), zap.Error(err))
+			return nil, err
+		}
+		channelIDs = append(channelIDs, id)
+	}
+	if len(channelIDs) == 0 {
+		return nil, errors.New("channel_ids is required: Slack adds invited people to at least one channel")
+	}
+	return channelIDs, nil
+}
+
+// adminTeamID returns the team_id argument, or the workspace of the main
+// token when it is not set.
+func (ch *ConversationsHandler) adminTeamID(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	if teamID := strings.TrimSpace(request.GetString("team_id", "")); teamID != "" {
+		return teamID, nil
+	}
+	ar, err := ch.apiProvider.Slack().AuthTestContext(ctx)
+	if err != nil {
+		ch.logger.Error("AuthTestContext failed", zap.Error(err))
+		return "", err
+	}
+	return ar.TeamID, nil
+}
+
+// adminUserID resolves a user ID, @handle or email address to a user ID.
+func (ch *ConversationsHandler) adminUserID(ctx context.Context, user string) (string, error) {
+	switch {
+	case strings.HasPrefix(user, "@"):
+		id, ok := ch.apiProvider.ProvideUsersMap().UsersInv[strings.TrimPrefix(user, "@")]
+		if !ok {
+			return "", fmt.Errorf("user %q not found", user)
+		}
+		return id, nil
+	case strings.Contains(user, "@"):
+		u, err := ch.apiProvider.Slack().GetUserByEmailContext(ctx, user)
+		if err != nil {
+			return "", fmt.Errorf("user with email %q not found: %w", user, err)
+		}
+		return u.ID, nil
+	case isSlackUserIDPrefix(user):
+		return user, nil
+	}
+	return "", fmt.Errorf("invalid user %q: use a user ID, @handle or email address", user)
+}
+
+// logAdminAction writes the audit record of an admin user action, whether
+// it succeeded or not.
+func (ch *ConversationsHandler) logAdminAction(action AdminUserAction, err error) {
+	fields := []zap.Field{
+		zap.String("context", "console"),
+		zap.String("action", action.Action),
+		zap.String("team_id", action.TeamID),
+		zap.String("user_id", action.UserID),
+		zap.String("email", action.Email),
+		zap.Strings("channel_ids", action.ChannelIDs),
+		zap.String("reason", action.Reason),
+	}
+	if err != nil {
+		ch.logger.Warn("Admin user action failed", append(fields, zap.Error(err))...)
+		return
+	}
+	ch.logger.Info("Admin user action", fields...)
+}
+
+func adminActionResult(action AdminUserAction) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.Marshal(action)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// adminUsersToolPolicy returns an error unless SLACK_MCP_ADMIN_USERS_TOOL is
+// true; listing the tools in SLACK_MCP_ENABLED_TOOLS is not enough.
+func (ch *ConversationsHandler) adminUsersToolPolicy() error {
+	if ch.apiProvider.Config().AdminUsersTool != "true" {
+		ch.logger.Error("Admin user tools disabled")
+		return errors.New(
+			"the admin_users_invite and admin_users_remove tools change workspace membership and are disabled. " +
+				"To enable them, set the SLACK_MCP_ADMIN_USERS_TOOL environment variable to true",
+		)
+	}
+	return nil
+}
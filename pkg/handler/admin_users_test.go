@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitAdminConfirmation(t *testing.T) {
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	reason, err := adminConfirmation(req(map[string]any{"confirm": "bo@example.com", "reason": " IT-1235 "}), "bo@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "IT-1235", reason)
+
+	_, err = adminConfirmation(req(map[string]any{"reason": "IT-1235"}), "bo@example.com")
+	assert.ErrorContains(t, err, "confirm must repeat")
+	_, err = adminConfirmation(req(map[string]any{"confirm": "Bo@example.com", "reason": "IT-1235"}), "bo@example.com")
+	assert.ErrorContains(t, err, "confirm must repeat", "confirmation is exact")
+	_, err = adminConfirmation(req(map[string]any{"confirm": "U0123ABCD"}), "U0123ABCD")
+	assert.ErrorContains(t, err, "reason is required")
+}
+
+func TestUnitAdminInviteChannelsPolicy(t *testing.T) {
+	ap := &provider.ApiProvider{}
+	require.NoError(t, ap.ApplyConfig(&config.Config{ChannelDenylist: []string{"C0DENIED01"}}))
+	ch := NewConversationsHandler(ap, zap.NewNop())
+
+	ids, err := ch.adminInviteChannels(context.Background(), "C0GENERAL1, C0SUPPORT1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C0GENERAL1", "C0SUPPORT1"}, ids)
+
+	_, err = ch.adminInviteChannels(context.Background(), "C0GENERAL1,C0DENIED01")
+	var policyErr *provider.ChannelPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, "C0DENIED01", policyErr.ChannelID)
+
+	_, err = ch.adminInviteChannels(context.Background(), " , ")
+	assert.ErrorContains(t, err, "channel_ids is required")
+}
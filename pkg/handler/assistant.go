@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxAssistantPrompts is the most suggested prompts Slack shows in an
+// assistant thread.
+const maxAssistantPrompts = 4
+
+// AssistantHandler serves the tools that drive a Slack assistant thread: the
+// thread a user opens with an app in the AI assistant side panel.
+type AssistantHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewAssistantHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *AssistantHandler {
+	return &AssistantHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// AssistantThreadsSetStatusHandler shows a status such as "is thinking..."
+// under the assistant thread, or clears it.
+func (h *AssistantHandler) AssistantThreadsSetStatusHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("AssistantThreadsSetStatusHandler called", zap.Any("params", request.Params))
+
+	channel, threadTs, err := h.parseThread(request)
+	if err != nil {
+		return nil, err
+	}
+	status := strings.TrimSpace(request.GetString("status", ""))
+
+	if err := h.apiProvider.Slack().SetAssistantThreadsStatusContext(ctx, slack.AssistantThreadsSetStatusParameters{
+		ChannelID: channel,
+		ThreadTS:  threadTs,
+		Status:    status,
+	}); err != nil {
+		h.logger.Error("SetAssistantThreadsStatusContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	return assistantResult(map[string]any{
+		"channel_id": channel,
+		"thread_ts":  threadTs,
+		"status":     status,
+		"cleared":    status == "",
+	})
+}
+
+// AssistantThreadsSetTitleHandler names the assistant thread in the user's
+// history of conversations with the app.
+func (h *AssistantHandler) AssistantThreadsSetTitleHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("AssistantThreadsSetTitleHandler called", zap.Any("params", request.Params))
+
+	channel, threadTs, err := h.parseThread(request)
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(request.GetString("title", ""))
+	if title == "" {
+		return nil, errors.New("title is required")
+	}
+
+	if err := h.apiProvider.Slack().SetAssistantThreadsTitleContext(ctx, slack.AssistantThreadsSetTitleParameters{
+		ChannelID: channel,
+		ThreadTS:  threadTs,
+		Title:     title,
+	}); err != nil {
+		h.logger.Error("SetAssistantThreadsTitleContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	return assistantResult(map[string]any{
+		"channel_id": channel,
+		"thread_ts":  threadTs,
+		"title":      title,
+	})
+}
+
+// AssistantThreadsSetSuggestedPromptsHandler offers prompts the user can
+// start the assistant thread with, replacing any offered before.
+func (h *AssistantHandler) AssistantThreadsSetSuggestedPromptsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("AssistantThreadsSetSuggestedPromptsHandler called", zap.Any("params", request.Params))
+
+	channel, threadTs, err := h.parseThread(request)
+	if err != nil {
+		return nil, err
+	}
+	prompts, err := parseAssistantPrompts(request.GetArguments()["prompts"])
+	if err != nil {
+		return nil, err
+	}
+	title := strings.TrimSpace(request.GetString("title", ""))
+
+	if err := h.apiProvider.Slack().SetAssistantThreadsSuggestedPromptsContext(ctx, slack.AssistantThreadsSetSuggestedPromptsParameters{
+		Title:     title,
+		ChannelID: channel,
+		ThreadTS:  threadTs,
+		Prompts:   prompts,
+	}); err != nil {
+		h.logger.Error("SetAssistantThreadsSuggestedPromptsContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	return assistantResult(map[string]any{
+		"channel_id": channel,
+		"thread_ts":  threadTs,
+		"title":      title,
+		"prompts":    prompts,
+	})
+}
+
+// parseThread reads the channel_id and thread_ts of an assistant thread and
+// checks that an app token is available to act on it.
+func (h *AssistantHandler) parseThread(request mcp.CallToolRequest) (string, string, error) {
+	if !h.apiProvider.IsBotToken() && !h.apiProvider.HasBotToken() {
+		return "", "", errors.New("assistant threads can only be updated by an app: set SLACK_MCP_XOXB_TOKEN to the bot token of an app with the Agents & AI Apps feature and the assistant:write scope")
+	}
+
+	channel := strings.TrimSpace(request.GetString("channel_id", ""))
+	if channel == "" {
+		return "", "", errors.New("channel_id is required")
+	}
+	if !strings.HasPrefix(channel, "D") {
+		return "", "", fmt.Errorf("channel_id %q must be the ID of the assistant's DM with the user, such as D0123456789", channel)
+	}
+	if err := h.apiProvider.CheckChannel(channel); err != nil {
+		h.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return "", "", err
+	}
+
+	threadTs := strings.TrimSpace(request.GetString("thread_ts", ""))
+	if threadTs == "" {
+		return "", "", errors.New("thread_ts is required")
+	}
+	if !strings.Contains(threadTs, ".") {
+		return "", "", fmt.Errorf("thread_ts %q must be a message timestamp such as 1234567890.123456", threadTs)
+	}
+	return channel, threadTs, nil
+}
+
+// parseAssistantPrompts reads the prompts parameter: up to
+// maxAssistantPrompts objects with a title shown to the user and the message
+// sent when it is picked.
+func parseAssistantPrompts(raw any) ([]slack.AssistantThreadsPrompt, error) {
+	items, ok := raw.([]any)
+	if !ok || len(items) == 0 {
+		return nil, errors.New("prompts must be a non-empty array of {title, message} objects")
+	}
+	if len(items) > maxAssistantPrompts {
+		return nil, fmt.Errorf("prompts must contain at most %d items, got %d", maxAssistantPrompts, len(items))
+	}
+
+	prompts := make([]slack.AssistantThreadsPrompt, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("prompts[%d] must be an object with title and message", i)
+		}
+		title, _ := obj["title"].(string)
+		message, _ := obj["message"].(string)
+		title, message = strings.TrimSpace(title), strings.TrimSpace(message)
+		if title == "" || message == "" {
+			return nil, fmt.Errorf("prompts[%d] needs both a title and a message", i)
+		}
+		prompts = append(prompts, slack.AssistantThreadsPrompt{Title: title, Message: message})
+	}
+	return prompts, nil
+}
+
+func assistantResult(result map[string]any) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseAssistantPrompts(t *testing.T) {
+	prompts, err := parseAssistantPrompts([]any{
+		map[string]any{"title": " Summarize ", "message": "Summarize this channel's last day"},
+		map[string]any{"title": "Open incidents", "message": "Which incidents are still open?"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []slack.AssistantThreadsPrompt{
+		{Title: "Summarize", Message: "Summarize this channel's last day"},
+		{Title: "Open incidents", Message: "Which incidents are still open?"},
+	}, prompts)
+
+	_, err = parseAssistantPrompts(nil)
+	assert.ErrorContains(t, err, "non-empty array")
+
+	_, err = parseAssistantPrompts([]any{map[string]any{"title": "Summarize"}})
+	assert.ErrorContains(t, err, "prompts[0] needs both a title and a message")
+
+	_, err = parseAssistantPrompts([]any{"Summarize"})
+	assert.ErrorContains(t, err, "prompts[0] must be an object")
+
+	five := make([]any, maxAssistantPrompts+1)
+	for i := range five {
+		five[i] = map[string]any{"title": "t", "message": "m"}
+	}
+	_, err = parseAssistantPrompts(five)
+	assert.ErrorContains(t, err, "at most 4 items, got 5")
+}
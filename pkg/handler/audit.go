@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuditSearchLimit = 100
+	// maxAuditSearchLimit is the page size limit of the audit logs API.
+	maxAuditSearchLimit = 9999
+)
+
+// AuditEntryRow is the CSV output row for admin_audit_search.
+type AuditEntryRow struct {
+	ID         string `csv:"id"`
+	Date       string `csv:"date"`
+	Action     string `csv:"action"`
+	ActorID    string `csv:"actorID"`
+	ActorName  string `csv:"actorName"`
+	ActorEmail string `csv:"actorEmail"`
+	EntityType string `csv:"entityType"`
+	EntityID   string `csv:"entityID"`
+	EntityName string `csv:"entityName"`
+	Location   string `csv:"location"`
+	IPAddress  string `csv:"ipAddress"`
+	Details    string `csv:"details"`
+	Cursor     string `csv:"cursor"`
+}
+
+// AdminAuditSearchHandler searches the Enterprise Grid audit logs by action,
+// actor, entity and date range, newest first. The audit logs API needs an
+// org-level user token with auditlogs:read, see SLACK_MCP_AUDIT_TOKEN.
+func (ch *ConversationsHandler) AdminAuditSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("AdminAuditSearchHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	limit := request.GetInt("limit", defaultAuditSearchLimit)
+	if limit < 1 || limit > maxAuditSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxAuditSearchLimit, limit)
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	params := slack.AuditLogParameters{
+		Limit:  limit,
+		Action: strings.ReplaceAll(request.GetString("action", ""), " ", ""),
+	}
+	if params.Oldest, err = auditTimeBound(request.GetString("oldest", ""), loc, false); err != nil {
+		return nil, fmt.Errorf("invalid oldest: %w", err)
+	}
+	if params.Latest, err = auditTimeBound(request.GetString("latest", ""), loc, true); err != nil {
+		return nil, fmt.Errorf("invalid latest: %w", err)
+	}
+	if params.Actor, err = ch.auditUserID(request.GetString("actor", "")); err != nil {
+		return nil, err
+	}
+	if params.Entity, err = ch.auditEntityID(ctx, request.GetString("entity", "")); err != nil {
+		return nil, err
+	}
+	if params.Cursor, err = decodeCursor("admin_audit_search", auditCursorParams(params), request.GetString("cursor", "")); err != nil {
+		return nil, err
+	}
+
+	entries, next, err := ch.apiProvider.AuditLogsContext(ctx, params)
+	if err != nil {
+		ch.logger.Error("GetAuditLogsContext failed", zap.Error(err))
+		switch slackErrorCode(err) {
+		case "not_allowed_token_type", "missing_scope", "feature_not_enabled", "invalid_auth", "not_authed":
+			return nil, fmt.Errorf("%w: %w", provider.ErrAuditLogsUnavailable, err)
+		}
+		return nil, err
+	}
+
+	rows := make([]AuditEntryRow, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, auditEntryRow(e, loc))
+	}
+	if len(rows) > 0 {
+		rows[len(rows)-1].Cursor = encodeCursor("admin_audit_search", auditCursorParams(params), next)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		ch.logger.Error("Failed to marshal audit entries to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// auditUserID resolves a user ID, <@U…> mention or @handle; empty stays
+// empty.
+func (ch *ConversationsHandler) auditUserID(raw string) (string, error) {
+	raw = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(raw), "<@"), ">")
+	if raw == "" || isSlackUserIDPrefix(raw) {
+		return raw, nil
+	}
+	uid, ok := ch.apiProvider.ProvideUsersMap().UsersInv[strings.TrimPrefix(raw, "@")]
+	if !ok {
+		return "", fmt.Errorf("user %q not found", raw)
+	}
+	return uid, nil
+}
+
+// auditEntityID resolves the entity filter: an @handle to a user ID, a
+// #channel to its ID, and anything else, such as a file or app ID, as is.
+// Channels go through the channel policy, so the audit trail of a channel
+// stays as hidden as its messages.
+func (ch *ConversationsHandler) auditEntityID(ctx context.Context, raw string) (string, error) {
+	entity := strings.TrimSpace(raw)
+	if strings.HasPrefix(entity, "@") {
+		return ch.auditUserID(entity)
+	}
+	id, err := ch.resolveChannelID(ctx, entity)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(entity, "#") || (id != "" && strings.ContainsRune("CGD", rune(id[0]))) {
+		if err := ch.apiProvider.CheckChannel(id); err != nil {
+			ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", id), zap.Error(err))
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// auditCursorParams ties an admin_audit_search cursor to the filters of the
+// call.
+func auditCursorParams(params slack.AuditLogParameters) string {
+	return cursorParams(params.Action, params.Actor, params.Entity, strconv.Itoa(params.Oldest), strconv.Itoa(params.Latest))
+}
+
+// auditTimeBound converts a date or timestamp bound into the Unix seconds
+// the audit logs API takes, 0 when empty.
+func auditTimeBound(value string, loc *time.Location, endOfDay bool) (int, error) {
+	ts, err := parseTimeBound(strings.TrimSpace(value), loc, endOfDay)
+	if err != nil || ts == "" {
+		return 0, err
+	}
+	sec, _, _ := strings.Cut(ts, ".")
+	return strconv.Atoi(sec)
+}
+
+func auditEntryRow(e slack.AuditEntry, loc *time.Location) AuditEntryRow {
+	row := AuditEntryRow{
+		ID:         e.ID,
+		Date:       time.Unix(int64(e.DateCreate), 0).In(loc).Format("2006-01-02 15:04:05 MST"),
+		Action:     e.Action,
+		ActorID:    e.Actor.User.ID,
+		ActorName:  e.Actor.User.Name,
+		ActorEmail: e.Actor.User.Email,
+		EntityType: e.Entity.Type,
+		Location:   e.Context.Location.Name,
+		IPAddress:  e.Context.IPAddress,
+	}
+	switch e.Entity.Type {
+	case "user":
+		row.EntityID, row.EntityName = e.Entity.User.ID, e.Entity.User.Name
+	case "channel":
+		row.EntityID, row.EntityName = e.Entity.Channel.ID, e.Entity.Channel.Name
+	case "file":
+		row.EntityID, row.EntityName = e.Entity.File.ID, e.Entity.File.Name
+	case "app":
+		row.EntityID, row.EntityName = e.Entity.App.ID, e.Entity.App.Name
+	case "workspace":
+		row.EntityID, row.EntityName = e.Entity.Workspace.ID, e.Entity.Workspace.Name
+	case "enterprise":
+		row.EntityID, row.EntityName = e.Entity.Enterprise.ID, e.Entity.Enterprise.Name
+	}
+	if e.Details.PreviousValue != nil || e.Details.NewValue != nil {
+		details, _ := json.Marshal(map[string]any{
+			"previous_value": e.Details.PreviousValue,
+			"new_value":      e.Details.NewValue,
+		})
+		row.Details = string(details)
+	}
+	return row
+}
@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBotSearchLimit = 20
+	maxBotSearchLimit     = 100
+	// maxBotSearchChannels bounds the channels one call scans.
+	maxBotSearchChannels = 10
+	// defaultBotSearchWindow applies when no date filter sets the start;
+	// maxBotSearchWindow is the longest range one call scans.
+	defaultBotSearchWindow = 7 * 24 * time.Hour
+	maxBotSearchWindow     = 30 * 24 * time.Hour
+	// maxBotSearchScanMessages bounds the history scanned per channel.
+	maxBotSearchScanMessages = 2000
+	botSearchPageSize        = 200
+)
+
+// BotSearchMetadata follows the rows of conversations_search_messages when
+// it scans history for a bot token.
+type BotSearchMetadata struct {
+	// Mode is "history_scan", telling the results apart from Slack search.
+	Mode     string   `json:"mode"`
+	Query    string   `json:"query"`
+	Channels []string `json:"channels"`
+	Oldest   string   `json:"oldest"`
+	Latest   string   `json:"latest,omitempty"`
+	Scanned  int      `json:"scanned"`
+	Matched  int      `json:"matched"`
+	Returned int      `json:"returned"`
+	// Truncated is set when more messages matched than limit, or a channel
+	// had more history in the range than one call scans.
+	Truncated bool `json:"truncated,omitempty"`
+	// LanguageFiltered counts the matches filter_language removed.
+	LanguageFiltered int `json:"language_filtered,omitempty"`
+}
+
+// ConversationsBotSearchHandler stands in for conversations_search_messages
+// with a bot token, which search.messages rejects. It scans a bounded window
+// of the history of the channels in filter_in_channel and matches the words
+// of search_query here, as thread_search does, so bot deployments still get
+// basic search. Thread replies are not part of channel history and are not
+// searched.
+func (ch *ConversationsHandler) ConversationsBotSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsBotSearchHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	rawQuery := strings.TrimSpace(request.GetString("search_query", ""))
+	query := parseThreadQuery(rawQuery)
+	if len(query.terms) == 0 {
+		return nil, errors.New("search_query must contain at least one word or quoted phrase to look for")
+	}
+	channels, err := ch.botSearchChannels(ctx, request.GetString("filter_in_channel", ""))
+	if err != nil {
+		return nil, err
+	}
+	limit := request.GetInt("limit", defaultBotSearchLimit)
+	if limit < 1 || limit > maxBotSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxBotSearchLimit)
+	}
+	var user string
+	if raw := request.GetString("filter_users_from", ""); raw != "" {
+		formatted, err := ch.paramFormatUser(raw)
+		if err != nil {
+			return nil, err
+		}
+		user = strings.TrimSuffix(strings.TrimPrefix(formatted, "<@"), ">")
+	}
+	render, err := parseRenderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := parseLanguageFilter(request); err != nil {
+		return nil, err
+	}
+	oldest, latest, err := botSearchWindow(request, loc, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	meta := BotSearchMetadata{
+		Mode:     "history_scan",
+		Query:    rawQuery,
+		Channels: channels,
+		Oldest:   oldest,
+		Latest:   latest,
+	}
+	progress := newProgressReporter(ctx, request)
+	var messages []Message
+	for i, channel := range channels {
+		scanned, truncated, err := ch.scanBotSearchChannel(ctx, channel, oldest, latest)
+		if err != nil {
+			return nil, err
+		}
+		meta.Scanned += len(scanned)
+		meta.Truncated = meta.Truncated || truncated
+
+		matched := botSearchMatches(scanned, query, user)
+		meta.Matched += len(matched)
+		ch.apiProvider.EnsureUsers(ctx, historyUserIDs(matched))
+		messages = append(messages, ch.convertMessagesFromHistory(matched, channel, false, request.GetBool("include_unfurls", false), false, render, loc)...)
+		progress.Report(float64(i+1), float64(len(channels)), fmt.Sprintf("scanned %d messages, %d matches", meta.Scanned, meta.Matched))
+	}
+
+	// Newest first across channels, as Slack search sorts by timestamp.
+	slices.SortStableFunc(messages, func(a, b Message) int {
+		switch {
+		case slackTsLess(b.MsgID, a.MsgID):
+			return -1
+		case slackTsLess(a.MsgID, b.MsgID):
+			return 1
+		}
+		return 0
+	})
+	if len(messages) > limit {
+		messages = messages[:limit]
+		meta.Truncated = true
+	}
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	detected := len(messages)
+	if messages, err = detectLanguages(request, messages); err != nil {
+		return nil, err
+	}
+	meta.LanguageFiltered = detected - len(messages)
+	meta.Returned = len(messages)
+
+	res, err := MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	return withJSONMetadata(res, meta)
+}
+
+// botSearchChannels resolves the comma-separated channels of
+// filter_in_channel and checks them against the channel policy.
+func (ch *ConversationsHandler) botSearchChannels(ctx context.Context, raw string) ([]string, error) {
+	var channels []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		channel, err := ch.resolveChannelID(ctx, name)
+		if err != nil {
+			ch.logger.Error("Channel not found", zap.String("channel", name), zap.Error(err))
+			return nil, err
+		}
+		if err := ch.apiProvider.CheckChannel(channel); err != nil {
+			ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+			return nil, err
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, errors.New("filter_in_channel is required with a bot token: search.messages needs a user token, so the channels to scan must be named, e.g. '#general,#support'")
+	}
+	if len(channels) > maxBotSearchChannels {
+		return nil, fmt.Errorf("filter_in_channel names %d channels, at most %d can be scanned at once", len(channels), maxBotSearchChannels)
+	}
+	return channels, nil
+}
+
+// botSearchWindow returns the oldest and latest ts to scan from the date
+// filters: by default the last week, and never more than
+// maxBotSearchWindow.
+func botSearchWindow(request mcp.CallToolRequest, loc *time.Location, now time.Time) (string, string, error) {
+	after := strings.TrimSpace(request.GetString("filter_date_after", ""))
+	before := strings.TrimSpace(request.GetString("filter_date_before", ""))
+	if on := strings.TrimSpace(request.GetString("filter_date_on", "")); on != "" {
+		if after != "" || before != "" {
+			return "", "", errors.New("filter_date_on cannot be combined with filter_date_after or filter_date_before")
+		}
+		oldest, err := parseTimeBound(on, loc, false)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid filter_date_on: %w", err)
+		}
+		latest, err := parseTimeBound(on, loc, true)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid filter_date_on: %w", err)
+		}
+		return oldest, latest, nil
+	}
+
+	// As in Slack search, after and before exclude the day they name.
+	latest, err := parseTimeBound(before, loc, false)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter_date_before: %w", err)
+	}
+	end := now
+	if latest != "" {
+		if end, err = text.SlackTimestampToTime(latest); err != nil {
+			return "", "", fmt.Errorf("invalid filter_date_before: %w", err)
+		}
+	}
+	oldest, err := parseTimeBound(after, loc, true)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter_date_after: %w", err)
+	}
+	if oldest == "" {
+		return fmt.Sprintf("%d.000000", end.Add(-defaultBotSearchWindow).Unix()), latest, nil
+	}
+	start, err := text.SlackTimestampToTime(oldest)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid filter_date_after: %w", err)
+	}
+	if !start.Before(end) {
+		return "", "", fmt.Errorf("filter_date_after must be before filter_date_before")
+	}
+	if end.Sub(start) > maxBotSearchWindow {
+		return "", "", fmt.Errorf("with a bot token, search scans channel history and covers at most %d days; narrow filter_date_after and filter_date_before", int(maxBotSearchWindow.Hours()/24))
+	}
+	return oldest, latest, nil
+}
+
+// scanBotSearchChannel returns up to maxBotSearchScanMessages messages of
+// channel between oldest and latest, newest first, and whether the range
+// held more.
+func (ch *ConversationsHandler) scanBotSearchChannel(ctx context.Context, channel, oldest, latest string) ([]slack.Message, bool, error) {
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Limit:     botSearchPageSize,
+		Oldest:    oldest,
+		Latest:    latest,
+	}
+	var scanned []slack.Message
+	for {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.String("channel", channel), zap.Error(err))
+			return nil, false, err
+		}
+		scanned = append(scanned, history.Messages...)
+		if len(scanned) >= maxBotSearchScanMessages {
+			return scanned[:maxBotSearchScanMessages], true, nil
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			return scanned, false, nil
+		}
+		historyParams.Cursor = history.ResponseMetaData.NextCursor
+	}
+}
+
+// botSearchMatches returns the messages by user, when set, that match q,
+// leaving out joins and other activity.
+func botSearchMatches(msgs []slack.Message, q threadQuery, user string) []slack.Message {
+	var matched []slack.Message
+	for _, m := range msgs {
+		if m.SubType != "" && m.SubType != "bot_message" && m.SubType != "thread_broadcast" {
+			continue
+		}
+		if user != "" && m.User != user {
+			continue
+		}
+		if q.match(threadSearchText(m)) {
+			matched = append(matched, m)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitBotSearchMatches(t *testing.T) {
+	msg := func(ts, user, text, subtype string) slack.Message {
+		return slack.Message{Msg: slack.Msg{Timestamp: ts, User: user, Text: text, SubType: subtype}}
+	}
+	msgs := []slack.Message{
+		msg("3", "U1", "Rollback of the payments deploy done", ""),
+		msg("2", "U2", "rollback on staging only", ""),
+		msg("1", "U1", "joined the channel, rollback fan", "channel_join"),
+	}
+
+	got := botSearchMatches(msgs, parseThreadQuery("rollback -staging"), "")
+	require.Len(t, got, 1)
+	assert.Equal(t, "3", got[0].Timestamp)
+
+	got = botSearchMatches(msgs, parseThreadQuery("rollback"), "U2")
+	require.Len(t, got, 1)
+	assert.Equal(t, "2", got[0].Timestamp)
+}
+
+func TestUnitBotSearchWindow(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	window := func(args map[string]any) (string, string, error) {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		return botSearchWindow(req, time.UTC, now)
+	}
+
+	oldest, latest, err := window(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1717416000.000000", oldest, "the last 7 days by default")
+	assert.Empty(t, latest)
+
+	oldest, latest, err = window(map[string]any{"filter_date_on": "2024-06-03"})
+	require.NoError(t, err)
+	assert.Equal(t, "1717372800.000000", oldest)
+	assert.Equal(t, "1717459199.999999", latest)
+
+	oldest, latest, err = window(map[string]any{"filter_date_after": "2024-05-20", "filter_date_before": "2024-06-01"})
+	require.NoError(t, err)
+	assert.Equal(t, "1716249599.999999", oldest)
+	assert.Equal(t, "1717200000.000000", latest)
+
+	_, _, err = window(map[string]any{"filter_date_after": "2024-01-01"})
+	assert.ErrorContains(t, err, "at most 30 days")
+	_, _, err = window(map[string]any{"filter_date_on": "2024-06-03", "filter_date_after": "2024-06-01"})
+	assert.ErrorContains(t, err, "cannot be combined")
+	_, _, err = window(map[string]any{"filter_date_after": "2024-06-05", "filter_date_before": "2024-06-02"})
+	assert.ErrorContains(t, err, "must be before")
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	// channelActivityDays is the window of the channel activity resource.
+	channelActivityDays = 90
+	// channelActivityTTL is how long a computed heatmap is served from cache.
+	channelActivityTTL = time.Hour
+	// channelActivityMaxPages caps the history pages one heatmap reads; a
+	// busier channel is counted from its newest messages and marked truncated.
+	channelActivityMaxPages = 50
+	channelActivityPageSize = 999
+)
+
+// ChannelActivityDay is the number of messages posted on one day.
+type ChannelActivityDay struct {
+	Date     string `json:"date"`
+	Messages int    `json:"messages"`
+}
+
+// ChannelActivity is served at slack://<workspace>/channels/<channel>/activity:
+// messages per day over the last channelActivityDays days, oldest first,
+// days without messages included.
+type ChannelActivity struct {
+	ChannelID   string               `json:"channel_id"`
+	ChannelName string               `json:"channel_name,omitempty"`
+	Timezone    string               `json:"timezone"`
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	Total       int                  `json:"total"`
+	ActiveDays  int                  `json:"active_days"`
+	LastMessage string               `json:"last_message,omitempty"`
+	Truncated   bool                 `json:"truncated,omitempty"`
+	ComputedAt  string               `json:"computed_at"`
+	Days        []ChannelActivityDay `json:"days"`
+}
+
+type channelActivityEntry struct {
+	activity   ChannelActivity
+	computedAt time.Time
+}
+
+// channelActivityCache keeps computed heatmaps for channelActivityTTL, so
+// reading the resource again does not walk 90 days of history again.
+type channelActivityCache struct {
+	mu      sync.Mutex
+	entries map[string]channelActivityEntry
+}
+
+func (c *channelActivityCache) get(channel string, now time.Time) (ChannelActivity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[channel]
+	if !ok || now.Sub(e.computedAt) > channelActivityTTL {
+		return ChannelActivity{}, false
+	}
+	return e.activity, true
+}
+
+func (c *channelActivityCache) put(channel string, activity ChannelActivity, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]channelActivityEntry)
+	}
+	for k, e := range c.entries {
+		if now.Sub(e.computedAt) > channelActivityTTL {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[channel] = channelActivityEntry{activity: activity, computedAt: now}
+}
+
+// ChannelActivityResource serves the message counts per day of a channel
+// over the last 90 days as JSON, e.g. to tell whether a channel is still in
+// use without reading its history.
+func (ch *ConversationsHandler) ChannelActivityResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ch.logger.Debug("ChannelActivityResource called", zap.Any("params", request.Params))
+
+	if authenticated, err := auth.IsAuthenticated(ctx, ch.apiProvider.ServerTransport(), ch.logger); !authenticated {
+		ch.logger.Error("Authentication failed for channel activity resource", zap.Error(err))
+		return nil, err
+	}
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	channel, err := parseChannelActivityURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	activity, ok := ch.activity.get(channel, now)
+	if !ok {
+		if activity, err = ch.computeChannelActivity(ctx, channel, now); err != nil {
+			return nil, err
+		}
+		ch.activity.put(channel, activity, now)
+	}
+
+	jsonBytes, err := json.Marshal(activity)
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonBytes),
+		},
+	}, nil
+}
+
+// computeChannelActivity counts the messages of channel per day in the
+// configured timezone, over the channelActivityDays days up to now.
+func (ch *ConversationsHandler) computeChannelActivity(ctx context.Context, channel string, now time.Time) (ChannelActivity, error) {
+	loc := ch.apiProvider.Config().Location()
+	end := now.In(loc)
+	start := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -(channelActivityDays - 1))
+
+	params := slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Limit:     channelActivityPageSize,
+		Oldest:    fmt.Sprintf("%d.000000", start.Unix()),
+	}
+	lim := limiter.Tier3.Limiter()
+	var (
+		timestamps []time.Time
+		truncated  bool
+	)
+	for page := 0; ; page++ {
+		if page == channelActivityMaxPages {
+			truncated = true
+			break
+		}
+		if err := lim.Wait(ctx); err != nil {
+			return ChannelActivity{}, err
+		}
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &params)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.String("channel", channel), zap.Error(err))
+			return ChannelActivity{}, err
+		}
+		for _, msg := range history.Messages {
+			if msg.SubType != "" && msg.SubType != "bot_message" && msg.SubType != "thread_broadcast" {
+				continue
+			}
+			if t, err := text.SlackTimestampToTime(msg.Timestamp); err == nil {
+				timestamps = append(timestamps, t)
+			}
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+
+	activity := bucketChannelActivity(start, channelActivityDays, timestamps)
+	activity.ChannelID = channel
+	if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channel]; ok {
+		activity.ChannelName = c.Name
+	}
+	activity.Timezone = loc.String()
+	activity.Truncated = truncated
+	activity.ComputedAt = now.UTC().Format(time.RFC3339)
+	return activity, nil
+}
+
+// bucketChannelActivity counts timestamps per day for the given number of
+// days from start, a midnight in the timezone the counts are for.
+func bucketChannelActivity(start time.Time, days int, timestamps []time.Time) ChannelActivity {
+	activity := ChannelActivity{
+		From: start.Format(time.DateOnly),
+		To:   start.AddDate(0, 0, days-1).Format(time.DateOnly),
+		Days: make([]ChannelActivityDay, days),
+	}
+	for i := range activity.Days {
+		activity.Days[i].Date = start.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	var last time.Time
+	for _, t := range timestamps {
+		t = t.In(start.Location())
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, start.Location())
+		i := int(day.Sub(start).Hours()+12) / 24 // rounded, DST days are 23 or 25 hours long
+		if day.Before(start) || i >= days {
+			continue
+		}
+		activity.Days[i].Messages++
+		activity.Total++
+		if t.After(last) {
+			last = t
+		}
+	}
+	for _, d := range activity.Days {
+		if d.Messages > 0 {
+			activity.ActiveDays++
+		}
+	}
+	if !last.IsZero() {
+		activity.LastMessage = last.UTC().Format(time.RFC3339)
+	}
+	return activity
+}
+
+// parseChannelActivityURI returns the channel of
+// slack://<workspace>/channels/<channel>/activity.
+func parseChannelActivityURI(uri string) (string, error) {
+	_, rest, ok := strings.Cut(uri, "/channels/")
+	if !ok {
+		return "", fmt.Errorf("invalid channel activity resource URI %q", uri)
+	}
+	channel, suffix, ok := strings.Cut(rest, "/")
+	if !ok || suffix != "activity" {
+		return "", fmt.Errorf("invalid channel activity resource URI %q, want slack://<workspace>/channels/<channel>/activity", uri)
+	}
+	if channel == "" {
+		return "", errors.New("channel activity resource URI has no channel")
+	}
+	return channel, nil
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitBucketChannelActivity(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// The window spans the end of daylight saving time on 2024-10-27.
+	start := time.Date(2024, 10, 25, 0, 0, 0, 0, berlin)
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 10, day, hour, 0, 0, 0, berlin)
+	}
+
+	activity := bucketChannelActivity(start, 5, []time.Time{
+		at(24, 23), // before the window
+		at(25, 9),
+		at(25, 17),
+		at(28, 23).UTC(), // counted in the window's timezone
+		at(29, 8),
+		at(30, 8), // after the window
+	})
+	assert.Equal(t, "2024-10-25", activity.From)
+	assert.Equal(t, "2024-10-29", activity.To)
+	assert.Equal(t, []ChannelActivityDay{
+		{Date: "2024-10-25", Messages: 2},
+		{Date: "2024-10-26"},
+		{Date: "2024-10-27"},
+		{Date: "2024-10-28", Messages: 1},
+		{Date: "2024-10-29", Messages: 1},
+	}, activity.Days)
+	assert.Equal(t, 4, activity.Total)
+	assert.Equal(t, 3, activity.ActiveDays)
+	assert.Equal(t, "2024-10-29T07:00:00Z", activity.LastMessage)
+
+	activity = bucketChannelActivity(start, 3, nil)
+	assert.Zero(t, activity.Total)
+	assert.Empty(t, activity.LastMessage)
+	assert.Len(t, activity.Days, 3, "quiet days are listed too")
+}
+
+func TestUnitParseChannelActivityURI(t *testing.T) {
+	channel, err := parseChannelActivityURI("slack://acme/channels/C0123ABCD/activity")
+	require.NoError(t, err)
+	assert.Equal(t, "C0123ABCD", channel)
+
+	for _, uri := range []string{
+		"slack://acme/channels",
+		"slack://acme/channels/C0123ABCD",
+		"slack://acme/channels/C0123ABCD/members",
+		"slack://acme/channels//activity",
+	} {
+		_, err := parseChannelActivityURI(uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestUnitChannelActivityCache(t *testing.T) {
+	var cache channelActivityCache
+	now := time.Now()
+	_, ok := cache.get("C1", now)
+	assert.False(t, ok)
+
+	cache.put("C1", ChannelActivity{ChannelID: "C1", Total: 3}, now)
+	got, ok := cache.get("C1", now.Add(channelActivityTTL/2))
+	require.True(t, ok)
+	assert.Equal(t, 3, got.Total)
+
+	_, ok = cache.get("C1", now.Add(channelActivityTTL+time.Second))
+	assert.False(t, ok, "expired")
+}
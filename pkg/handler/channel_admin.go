@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// Slack rejects longer topics and purposes with too_long.
+const (
+	maxChannelTopicLength   = 250
+	maxChannelPurposeLength = 250
+)
+
+// ChannelInfoUpdate is the CSV output row for conversations_set_topic and
+// conversations_set_purpose.
+type ChannelInfoUpdate struct {
+	ChannelID   string `csv:"channelID"`
+	ChannelName string `csv:"channelName"`
+	Field       string `csv:"field"`
+	Previous    string `csv:"previous"`
+	Value       string `csv:"value"`
+}
+
+// channelInfoField is a channel setting changed by a channel admin tool.
+type channelInfoField struct {
+	tool      string
+	name      string
+	maxLength int
+	set       func(api provider.SlackAPI, ctx context.Context, channelID, value string) (*slack.Channel, error)
+	// updated reads the new value from Slack's response, cached points at
+	// the field in a cached channel.
+	updated func(*slack.Channel) string
+	cached  func(*provider.Channel) *string
+}
+
+var (
+	channelTopicField = channelInfoField{
+		tool:      "conversations_set_topic",
+		name:      "topic",
+		maxLength: maxChannelTopicLength,
+		set:       provider.SlackAPI.SetTopicOfConversationContext,
+		updated:   func(c *slack.Channel) string { return c.Topic.Value },
+		cached:    func(c *provider.Channel) *string { return &c.Topic },
+	}
+	channelPurposeField = channelInfoField{
+		tool:      "conversations_set_purpose",
+		name:      "purpose",
+		maxLength: maxChannelPurposeLength,
+		set:       provider.SlackAPI.SetPurposeOfConversationContext,
+		updated:   func(c *slack.Channel) string { return c.Purpose.Value },
+		cached:    func(c *provider.Channel) *string { return &c.Purpose },
+	}
+)
+
+// ConversationsSetTopicHandler replaces a channel's topic, e.g. to keep the
+// current on-call person visible.
+func (ch *ConversationsHandler) ConversationsSetTopicHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsSetTopicHandler called", zap.Any("params", request.Params))
+	return ch.setChannelInfo(ctx, request, channelTopicField)
+}
+
+// ConversationsSetPurposeHandler replaces a channel's purpose, the
+// description shown in the channel details.
+func (ch *ConversationsHandler) ConversationsSetPurposeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsSetPurposeHandler called", zap.Any("params", request.Params))
+	return ch.setChannelInfo(ctx, request, channelPurposeField)
+}
+
+// setChannelInfo sets field of the channel in channel_id under the
+// SLACK_MCP_CHANNEL_ADMIN_TOOL policy and updates the cached channel.
+func (ch *ConversationsHandler) setChannelInfo(ctx context.Context, request mcp.CallToolRequest, field channelInfoField) (*mcp.CallToolResult, error) {
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.channelAdminToolPolicy(field.tool)
+	if err != nil {
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if !isChannelAllowedForConfig(channel, toolConfig) {
+		ch.logger.Warn("Channel admin tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return nil, fmt.Errorf("%s tool is not allowed for channel %q, applied policy: %s", field.tool, channel, toolConfig)
+	}
+
+	value, ok := request.GetArguments()[field.name].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s is required; pass an empty string to clear it", field.name)
+	}
+	if n := utf8.RuneCountInString(value); n > field.maxLength {
+		return nil, fmt.Errorf("%s is %d characters long, at most %d are allowed", field.name, n, field.maxLength)
+	}
+
+	row := ChannelInfoUpdate{ChannelID: channel, Field: field.name, Value: value}
+	if cached, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channel]; ok {
+		row.ChannelName, row.Previous = cached.Name, *field.cached(&cached)
+	}
+
+	updated, err := field.set(ch.apiProvider.Slack(), ctx, channel, value)
+	if err != nil {
+		ch.logger.Error("Failed to set channel "+field.name, zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if updated != nil {
+		row.Value = field.updated(updated)
+	}
+	ch.apiProvider.UpdateCachedChannel(channel, func(c *provider.Channel) { *field.cached(c) = row.Value })
+
+	csvBytes, err := gocsv.MarshalBytes([]ChannelInfoUpdate{row})
+	if err != nil {
+		ch.logger.Error("Failed to marshal channel update to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// channelAdminToolPolicy returns the SLACK_MCP_CHANNEL_ADMIN_TOOL channel
+// policy, or an error when channel administration is not enabled.
+func (ch *ConversationsHandler) channelAdminToolPolicy(tool string) (string, error) {
+	toolConfig := ch.apiProvider.Config().ChannelAdminTool
+	if toolConfig == "" {
+		if !ch.toolExplicitlyEnabled(tool) {
+			ch.logger.Error("Channel admin tools disabled by default", zap.String("tool", tool))
+			return "", fmt.Errorf(
+				"by default, the %s tool is disabled. "+
+					"To enable it, set the SLACK_MCP_CHANNEL_ADMIN_TOOL environment variable to true, 1, or comma separated list of channels "+
+					"to limit which channels the MCP can change, e.g. 'SLACK_MCP_CHANNEL_ADMIN_TOOL=C1234567890' or 'SLACK_MCP_CHANNEL_ADMIN_TOOL=!C1234567890' "+
+					"to enable all except one", tool,
+			)
+		}
+		toolConfig = "true"
+	}
+	return toolConfig, nil
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	// channelSamplingShortlist is the number of fuzzy-ranked names offered to
+	// the client's model, so large workspaces fit in one prompt.
+	channelSamplingShortlist = 200
+	channelSamplingTimeout   = 10 * time.Second
+	maxSampledSuggestions    = 3
+)
+
+var (
+	errSamplingUnsupported = errors.New("client does not support sampling")
+	// sampledListMarkerRe strips "1." or "2)" list markers from model replies.
+	sampledListMarkerRe = regexp.MustCompile(`^\d+[.)]\s*`)
+)
+
+// channelNotFound builds the error for a channel name that did not resolve.
+// With SLACK_MCP_CHANNEL_SUGGESTIONS=sampling and a client that supports MCP
+// sampling, the client's model picks the suggestions, which catches synonyms
+// such as #incidents for #outages; otherwise the fuzzy matcher does.
+func (ch *ConversationsHandler) channelNotFound(ctx context.Context, name, detail string) error {
+	notFound := ch.apiProvider.ChannelNotFound(name, detail)
+	if ch.apiProvider.Config().ChannelSuggestions != "sampling" {
+		return notFound
+	}
+
+	shortlist := ch.apiProvider.ChannelShortlist(name, channelSamplingShortlist)
+	sampled, err := sampleChannelSuggestions(ctx, name, shortlist)
+	if err != nil {
+		ch.logger.Debug("Channel suggestions via sampling unavailable, using fuzzy matches",
+			zap.String("channel", name),
+			zap.Error(err),
+		)
+		return notFound
+	}
+	if len(sampled) > 0 {
+		notFound.Suggestions = sampled
+	}
+	return notFound
+}
+
+// sampleChannelSuggestions asks the client's model which of shortlist are
+// closest to name.
+func sampleChannelSuggestions(ctx context.Context, name string, shortlist []string) ([]string, error) {
+	if len(shortlist) == 0 {
+		return nil, nil
+	}
+	srv := server.ServerFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	if srv == nil || session == nil {
+		return nil, errSamplingUnsupported
+	}
+	if info, ok := session.(server.SessionWithClientInfo); !ok || info.GetClientCapabilities().Sampling == nil {
+		return nil, errSamplingUnsupported
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, channelSamplingTimeout)
+	defer cancel()
+
+	var prompt strings.Builder
+	prompt.WriteString("A Slack tool was called with the conversation name " + name + ", which does not exist.\n")
+	prompt.WriteString("Which of these existing names did the caller most likely mean?\n\n")
+	for _, n := range shortlist {
+		prompt.WriteString(n + "\n")
+	}
+	prompt.WriteString("\nReply with up to 3 names from the list, most likely first, one per line, and nothing else.")
+
+	res, err := srv.RequestSampling(ctx, mcp.CreateMessageRequest{
+		CreateMessageParams: mcp.CreateMessageParams{
+			Messages: []mcp.SamplingMessage{{
+				Role:    mcp.RoleUser,
+				Content: mcp.NewTextContent(prompt.String()),
+			}},
+			SystemPrompt: "You match mistyped or approximate Slack channel names to existing ones.",
+			MaxTokens:    100,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseSampledChannels(samplingText(res.Content), shortlist), nil
+}
+
+// samplingText returns the text of a sampling result, which arrives either
+// typed or as the decoded JSON object depending on the transport.
+func samplingText(content any) string {
+	switch c := content.(type) {
+	case mcp.TextContent:
+		return c.Text
+	case *mcp.TextContent:
+		return c.Text
+	case map[string]any:
+		s, _ := c["text"].(string)
+		return s
+	}
+	return ""
+}
+
+// parseSampledChannels keeps the names in reply that are on shortlist, so the
+// model cannot suggest channels that do not exist or are hidden by policy.
+func parseSampledChannels(reply string, shortlist []string) []string {
+	known := make(map[string]string, len(shortlist))
+	for _, n := range shortlist {
+		known[strings.ToLower(n)] = n
+	}
+	sigil := ""
+	if len(shortlist) > 0 {
+		sigil = shortlist[0][:1]
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(reply, "\n") {
+		line = sampledListMarkerRe.ReplaceAllString(strings.TrimSpace(line), "")
+		line = strings.Trim(line, "-*`'\". ")
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "@") {
+			line = sigil + line
+		}
+		n, ok := known[strings.ToLower(line)]
+		if !ok || seen[n] {
+			continue
+		}
+		seen[n] = true
+		names = append(names, n)
+		if len(names) == maxSampledSuggestions {
+			break
+		}
+	}
+	return names
+}
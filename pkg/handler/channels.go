@@ -3,15 +3,18 @@ package handler
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +27,34 @@ type Channel struct {
 	Cursor      string `json:"cursor"`
 }
 
+// ChannelWithReadState is a channels_list row with the read marker columns
+// added by include_unread.
+type ChannelWithReadState struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Topic       string `json:"topic"`
+	Purpose     string `json:"purpose"`
+	MemberCount int    `json:"memberCount"`
+	LastRead    string `json:"lastRead"`
+	UnreadCount int    `json:"unreadCount"`
+	Cursor      string `json:"cursor"`
+}
+
+const (
+	// maxReadStateLookups caps the conversations.info calls of one
+	// channels_list call with include_unread.
+	maxReadStateLookups = 100
+	readStateWorkers    = 4
+)
+
+// ChannelsListMetadata is appended to channels_list results served while the
+// channels cache is still warming, so callers know the list is incomplete.
+type ChannelsListMetadata struct {
+	Warming        bool `json:"warming"`
+	Partial        bool `json:"partial"`
+	ChannelsLoaded int  `json:"channels_loaded"`
+}
+
 type ChannelsHandler struct {
 	apiProvider *provider.ApiProvider
 	validTypes  map[string]bool
@@ -78,6 +109,9 @@ func (ch *ChannelsHandler) ChannelsResource(ctx context.Context, request mcp.Rea
 	ch.logger.Debug("Retrieved channels from provider", zap.Int("count", len(channels)))
 
 	for _, channel := range channels {
+		if !ch.apiProvider.ChannelAllowed(channel.ID) {
+			continue
+		}
 		channelList = append(channelList, Channel{
 			ID:          channel.ID,
 			Name:        channel.Name,
@@ -105,16 +139,30 @@ func (ch *ChannelsHandler) ChannelsResource(ctx context.Context, request mcp.Rea
 func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	ch.logger.Debug("ChannelsHandler called")
 
+	// While the channels cache is warming, serve whatever pages have been
+	// fetched so far instead of failing the call.
+	warming := false
 	if ready, err := ch.apiProvider.IsReady(); !ready {
-		ch.logger.Error("API provider not ready", zap.Error(err))
-		return nil, err
+		if !errors.Is(err, provider.ErrChannelsNotReady) || !ch.apiProvider.ChannelsWarming() {
+			ch.logger.Error("API provider not ready", zap.Error(err))
+			return nil, err
+		}
+		warming = true
 	}
 
 	sortType := request.GetString("sort", "popularity")
-	types := request.GetString("channel_types", provider.PubChanType)
+	types := request.GetString("channel_types", "")
+	nameContains := request.GetString("name_contains", "")
+	namePrefix := request.GetString("name_prefix", "")
+	memberOnly := request.GetBool("member_of_only", false)
 	ch.logger.Debug("Request parameters",
 		zap.String("sort", sortType),
 		zap.String("channel_types", types),
+		zap.String("name_contains", nameContains),
+		zap.String("name_prefix", namePrefix),
+		zap.Bool("member_of_only", memberOnly),
+		zap.Bool("include_unread", request.GetBool("include_unread", false)),
+		zap.Bool("warming", warming),
 	)
 
 	// MCP Inspector v0.14.0 has issues with Slice type
@@ -141,10 +189,22 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 	ch.logger.Debug("Total channels available", zap.Int("count", len(allChannels)))
 
 	chans := filterChannelsByTypes(allChannels, channelTypes)
+	if nameContains != "" {
+		chans = filterChannelsByName(chans, nameContains)
+	}
+	if namePrefix != "" {
+		chans = filterChannelsByPrefix(chans, namePrefix)
+	}
+	if memberOnly {
+		chans = filterMemberChannels(chans)
+	}
 	ch.logger.Debug("Returning all channels of requested types", zap.Int("count", len(chans)))
 
 	var channelList []Channel
 	for _, channel := range chans {
+		if !ch.apiProvider.ChannelAllowed(channel.ID) {
+			continue
+		}
 		channelList = append(channelList, Channel{
 			ID:          channel.ID,
 			Name:        channel.Name,
@@ -164,13 +224,121 @@ func (ch *ChannelsHandler) ChannelsHandler(ctx context.Context, request mcp.Call
 		ch.logger.Debug("No sorting applied", zap.String("sort_type", sortType))
 	}
 
-	csvBytes, err := gocsv.MarshalBytes(&channelList)
+	var csvBytes []byte
+	var err error
+	if request.GetBool("include_unread", false) {
+		rows := ch.withReadState(ctx, channelList)
+		csvBytes, err = gocsv.MarshalBytes(&rows)
+	} else {
+		csvBytes, err = gocsv.MarshalBytes(&channelList)
+	}
 	if err != nil {
 		ch.logger.Error("Failed to marshal channels to CSV", zap.Error(err))
 		return nil, err
 	}
 
-	return mcp.NewToolResultText(string(csvBytes)), nil
+	res := mcp.NewToolResultText(string(csvBytes))
+	if warming {
+		return withJSONMetadata(res, ChannelsListMetadata{
+			Warming:        true,
+			Partial:        true,
+			ChannelsLoaded: len(allChannels),
+		})
+	}
+	return res, nil
+}
+
+// withReadState looks up the last_read marker and unread count of the first
+// maxReadStateLookups channels with conversations.info. Channels beyond that,
+// and channels whose lookup fails, keep empty read columns.
+func (ch *ChannelsHandler) withReadState(ctx context.Context, channels []Channel) []ChannelWithReadState {
+	rows := make([]ChannelWithReadState, len(channels))
+	for i, c := range channels {
+		rows[i] = ChannelWithReadState{
+			ID:          c.ID,
+			Name:        c.Name,
+			Topic:       c.Topic,
+			Purpose:     c.Purpose,
+			MemberCount: c.MemberCount,
+			Cursor:      c.Cursor,
+		}
+	}
+	if len(channels) > maxReadStateLookups {
+		ch.logger.Warn("Too many channels for read state lookups, only the first ones get read columns",
+			zap.Int("channels", len(channels)),
+			zap.Int("max", maxReadStateLookups),
+		)
+	}
+
+	sem := make(chan struct{}, readStateWorkers)
+	var wg sync.WaitGroup
+	for i := range rows[:min(len(rows), maxReadStateLookups)] {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(row *ChannelWithReadState) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			info, err := ch.apiProvider.Slack().GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: row.ID})
+			if err != nil {
+				ch.logger.Debug("Failed to get channel read state", zap.String("channel", row.ID), zap.Error(err))
+				return
+			}
+			row.LastRead = info.LastRead
+			row.UnreadCount = info.UnreadCountDisplay
+			if row.UnreadCount == 0 {
+				row.UnreadCount = info.UnreadCount
+			}
+		}(&rows[i])
+	}
+	wg.Wait()
+	return rows
+}
+
+// filterChannelsByName keeps channels whose name contains needle, ignoring
+// case and the leading # or @ of both the name and the needle.
+func filterChannelsByName(channels []provider.Channel, needle string) []provider.Channel {
+	needle = strings.ToLower(strings.TrimLeft(strings.TrimSpace(needle), "#@"))
+	if needle == "" {
+		return channels
+	}
+
+	var result []provider.Channel
+	for _, ch := range channels {
+		if strings.Contains(strings.ToLower(strings.TrimLeft(ch.Name, "#@")), needle) {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// filterChannelsByPrefix keeps channels whose name starts with prefix,
+// ignoring case and the leading # or @ of both.
+func filterChannelsByPrefix(channels []provider.Channel, prefix string) []provider.Channel {
+	prefix = strings.ToLower(strings.TrimLeft(strings.TrimSpace(prefix), "#@"))
+	if prefix == "" {
+		return channels
+	}
+
+	var result []provider.Channel
+	for _, ch := range channels {
+		if strings.HasPrefix(strings.ToLower(strings.TrimLeft(ch.Name, "#@")), prefix) {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// filterMemberChannels keeps the channels the authenticated user has joined,
+// and every DM and group DM.
+func filterMemberChannels(channels []provider.Channel) []provider.Channel {
+	var result []provider.Channel
+	for _, ch := range channels {
+		if ch.IsMember || ch.IsIM || ch.IsMpIM {
+			result = append(result, ch)
+		}
+	}
+	return result
 }
 
 func filterChannelsByTypes(channels map[string]provider.Channel, types []string) []provider.Channel {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/test/util"
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -161,3 +162,50 @@ func TestIntegrationPrivateChannelsList(t *testing.T) {
 
 	runChannelTest(t, env, "private_channel", expectedChannels)
 }
+
+func TestUnitFilterChannelsByName(t *testing.T) {
+	channels := []provider.Channel{
+		{ID: "C1", Name: "#engineering"},
+		{ID: "C2", Name: "#eng-oncall"},
+		{ID: "C3", Name: "#marketing"},
+		{ID: "D1", Name: "@Engel"},
+	}
+
+	ids := func(chans []provider.Channel) []string {
+		var out []string
+		for _, c := range chans {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"C1", "C2", "D1"}, ids(filterChannelsByName(channels, "ENG")))
+	assert.Equal(t, []string{"C2"}, ids(filterChannelsByName(channels, "#eng-")))
+	assert.Equal(t, []string{"C3"}, ids(filterChannelsByName(channels, "market")))
+	assert.Empty(t, filterChannelsByName(channels, "sales"))
+	assert.Len(t, filterChannelsByName(channels, "#"), len(channels))
+}
+
+func TestUnitFilterChannelsByPrefixAndMembership(t *testing.T) {
+	channels := []provider.Channel{
+		{ID: "C1", Name: "#team-payments", IsMember: true},
+		{ID: "C2", Name: "#Team-Search"},
+		{ID: "C3", Name: "#dream-team", IsMember: true},
+		{ID: "D1", Name: "@alice", IsIM: true},
+		{ID: "G1", Name: "@mpdm-alice--bob-1", IsMpIM: true},
+	}
+
+	ids := func(chans []provider.Channel) []string {
+		var out []string
+		for _, c := range chans {
+			out = append(out, c.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"C1", "C2"}, ids(filterChannelsByPrefix(channels, "#TEAM-")))
+	assert.Equal(t, []string{"D1"}, ids(filterChannelsByPrefix(channels, "@ali")))
+	assert.Len(t, filterChannelsByPrefix(channels, " "), len(channels))
+	assert.Equal(t, []string{"C1", "C3", "D1", "G1"}, ids(filterMemberChannels(channels)), "DMs always count as joined")
+	assert.Equal(t, []string{"C1"}, ids(filterMemberChannels(filterChannelsByPrefix(channels, "team-"))))
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	classifierTimeout       = 10 * time.Second
+	maxClassifierReplyBytes = 4 << 20
+)
+
+// MessageClassifier annotates the message rows a tool is about to return,
+// e.g. with needs_reply, is_question or priority, and may drop rows so they
+// never reach the model. It is called once per tool call with every row.
+type MessageClassifier interface {
+	Classify(ctx context.Context, req ClassifyRequest) ([]Classification, error)
+}
+
+// ClassifyRequest is what a classifier is asked to label. Tool is the tool
+// whose rows these are.
+type ClassifyRequest struct {
+	Tool     string    `json:"tool"`
+	Messages []Message `json:"messages"`
+}
+
+// Classification is a classifier's verdict on one row, matched by channel
+// and message timestamp. Labels become the row's labels column; rows not
+// returned are left unlabelled.
+type Classification struct {
+	MsgID   string            `json:"msgID"`
+	Channel string            `json:"channelID"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Drop    bool              `json:"drop,omitempty"`
+}
+
+var (
+	classifiersMu sync.Mutex
+	classifiers   = make(map[string]MessageClassifier)
+)
+
+// RegisterClassifier makes c available as SLACK_MCP_CLASSIFIER=name. Builds
+// that compile in their own classifier call it from an init function, as
+// database/sql drivers do. It panics if name is registered twice.
+func RegisterClassifier(name string, c MessageClassifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	if _, dup := classifiers[name]; dup {
+		panic("handler: RegisterClassifier called twice for " + name)
+	}
+	classifiers[name] = c
+}
+
+// NewClassifier returns the classifier SLACK_MCP_CLASSIFIER names: an
+// http(s) URL posted every batch of rows, or the name of a registered
+// classifier. Empty returns nil, which classifies nothing.
+func NewClassifier(spec string, logger *zap.Logger) (MessageClassifier, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://") {
+		logger.Info("Message classification enabled",
+			zap.String("context", "console"),
+			zap.Bool("http", true),
+		)
+		return &httpClassifier{url: spec, client: &http.Client{Timeout: classifierTimeout}}, nil
+	}
+	classifiersMu.Lock()
+	c, ok := classifiers[spec]
+	classifiersMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no classifier named %q is compiled in", spec)
+	}
+	logger.Info("Message classification enabled",
+		zap.String("context", "console"),
+		zap.String("classifier", spec),
+	)
+	return c, nil
+}
+
+// httpClassifier posts the rows as JSON and reads back
+// {"messages":[Classification...]}.
+type httpClassifier struct {
+	url    string
+	client *http.Client
+}
+
+func (c *httpClassifier) Classify(ctx context.Context, req ClassifyRequest) ([]Classification, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("classifier returned %s", resp.Status)
+	}
+	var reply struct {
+		Messages []Classification `json:"messages"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxClassifierReplyBytes)).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("classifier reply: %w", err)
+	}
+	return reply.Messages, nil
+}
+
+type classifierKey struct{}
+
+// WithClassifier makes c available to the message-returning handlers.
+func WithClassifier(ctx context.Context, c MessageClassifier) context.Context {
+	return context.WithValue(ctx, classifierKey{}, c)
+}
+
+func classifierFromContext(ctx context.Context) MessageClassifier {
+	c, _ := ctx.Value(classifierKey{}).(MessageClassifier)
+	return c
+}
+
+// classifyMessages labels the rows of tool with the configured classifier
+// and removes those it drops. A failing classifier fails the call, so rows
+// are never returned unfiltered.
+func classifyMessages(ctx context.Context, tool string, messages []Message) ([]Message, error) {
+	c := classifierFromContext(ctx)
+	if c == nil || len(messages) == 0 {
+		return messages, nil
+	}
+	results, err := c.Classify(ctx, ClassifyRequest{Tool: tool, Messages: messages})
+	if err != nil {
+		return nil, fmt.Errorf("message classifier failed: %w", err)
+	}
+	return applyClassifications(messages, results), nil
+}
+
+func applyClassifications(messages []Message, results []Classification) []Message {
+	byRow := make(map[string]Classification, len(results))
+	for _, r := range results {
+		byRow[r.Channel+":"+r.MsgID] = r
+	}
+	kept := messages[:0]
+	for _, m := range messages {
+		r, ok := byRow[m.Channel+":"+m.MsgID]
+		if ok && r.Drop {
+			continue
+		}
+		if ok {
+			m.Labels = formatLabels(r.Labels)
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// formatLabels joins labels like reactions: sorted, "|"-separated, as
+// name:value, or the bare name for flags whose value is empty or "true".
+// Flags set to "false" are left out.
+func formatLabels(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for name, value := range labels {
+		switch value {
+		case "", "true":
+			parts = append(parts, name)
+		case "false":
+		default:
+			parts = append(parts, name+":"+value)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "|")
+}
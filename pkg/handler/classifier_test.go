@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type staticClassifier []Classification
+
+func (c staticClassifier) Classify(context.Context, ClassifyRequest) ([]Classification, error) {
+	return c, nil
+}
+
+func TestUnitClassifyMessages(t *testing.T) {
+	messages := []Message{
+		{MsgID: "1718000000.000100", Channel: "C01", Text: "Can someone review my PR?"},
+		{MsgID: "1718000001.000100", Channel: "C01", Text: "lunch?"},
+		{MsgID: "1718000000.000100", Channel: "C02", Text: "FYI deploy done"},
+	}
+	ctx := WithClassifier(context.Background(), staticClassifier{
+		{MsgID: "1718000000.000100", Channel: "C01", Labels: map[string]string{"needs_reply": "true", "is_question": "", "priority": "high", "spam": "false"}},
+		{MsgID: "1718000001.000100", Channel: "C01", Drop: true},
+	})
+
+	got, err := classifyMessages(ctx, "conversations_history", messages)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "is_question|needs_reply|priority:high", got[0].Labels)
+	assert.Equal(t, "C02", got[1].Channel, "rows are matched by channel and ts")
+	assert.Empty(t, got[1].Labels)
+
+	unchanged, err := classifyMessages(context.Background(), "conversations_history", []Message{{MsgID: "1"}})
+	require.NoError(t, err)
+	assert.Len(t, unchanged, 1, "without a classifier rows are returned as they are")
+}
+
+func TestUnitHTTPClassifier(t *testing.T) {
+	var got ClassifyRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"messages":[{"msgID":"1718000000.000100","channelID":"C01","labels":{"needs_reply":"true"}}]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClassifier(srv.URL, zap.NewNop())
+	require.NoError(t, err)
+	ctx := WithClassifier(context.Background(), c)
+	messages, err := classifyMessages(ctx, "conversations_replies", []Message{{MsgID: "1718000000.000100", Channel: "C01", Text: "ping"}})
+	require.NoError(t, err)
+	assert.Equal(t, "needs_reply", messages[0].Labels)
+	assert.Equal(t, "conversations_replies", got.Tool)
+	assert.Equal(t, "ping", got.Messages[0].Text)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	c, err = NewClassifier(failing.URL, zap.NewNop())
+	require.NoError(t, err)
+	_, err = classifyMessages(WithClassifier(context.Background(), c), "conversations_replies", []Message{{MsgID: "1"}})
+	require.ErrorContains(t, err, "503", "rows are not returned unclassified")
+}
+
+func TestUnitNewClassifier(t *testing.T) {
+	c, err := NewClassifier("", zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	RegisterClassifier("test-static", staticClassifier{})
+	c, err = NewClassifier("test-static", zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, staticClassifier{}, c)
+	assert.Panics(t, func() { RegisterClassifier("test-static", staticClassifier{}) })
+
+	_, err = NewClassifier("missing", zap.NewNop())
+	require.ErrorContains(t, err, "compiled in")
+}
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultCleanupMaxDelete = 20
+	maxCleanupMaxDelete     = 200
+	// maxCleanupScanMessages bounds the history scanned by one call; the
+	// next_latest metadata continues from where it stopped.
+	maxCleanupScanMessages = 2000
+	cleanupScanPageSize    = 200
+	// cleanupDeleteRetries is how often a rate limited delete is retried
+	// after the wait Slack asks for.
+	cleanupDeleteRetries = 2
+)
+
+// cleanupSubtypes are the message subtypes conversations_cleanup may delete;
+// join, topic and other channel events are left alone.
+var cleanupSubtypes = map[string]bool{
+	"":                 true,
+	"bot_message":      true,
+	"me_message":       true,
+	"thread_broadcast": true,
+	"file_share":       true,
+}
+
+// CleanupResult is the CSV output row for conversations_cleanup, one per
+// matching message.
+type CleanupResult struct {
+	Channel   string `csv:"channelID"`
+	Timestamp string `csv:"timestamp"`
+	Time      string `csv:"time"`
+	Text      string `csv:"text"`
+	// Status is would_delete in a dry run, otherwise deleted or failed.
+	Status string `csv:"status"`
+	Error  string `csv:"error"`
+}
+
+// CleanupMetadata summarizes a conversations_cleanup call.
+type CleanupMetadata struct {
+	DryRun  bool `json:"dry_run"`
+	Scanned int  `json:"scanned"`
+	Matched int  `json:"matched"`
+	Deleted int  `json:"deleted"`
+	Failed  int  `json:"failed"`
+	// Truncated is set when the scan stopped at max_delete or the scan cap.
+	// Pass NextLatest as latest to continue.
+	Truncated  bool   `json:"truncated"`
+	NextLatest string `json:"next_latest,omitempty"`
+}
+
+// cleanupFilter selects the messages conversations_cleanup deletes: those
+// posted by author, matching pattern when it is set. The age bound is applied
+// by the history query.
+type cleanupFilter struct {
+	author  string
+	pattern *regexp.Regexp
+}
+
+func (f cleanupFilter) matches(msg slack.Message) bool {
+	if msg.User != f.author || !cleanupSubtypes[msg.SubType] {
+		return false
+	}
+	return f.pattern == nil || f.pattern.MatchString(msg.Text)
+}
+
+// ConversationsCleanupHandler deletes the authenticated user's or bot's own
+// messages in one channel that are older than older_than and match pattern,
+// e.g. to clear out a channel flooded by a notifier. It is a dry run unless
+// dry_run is false, and deletes at most max_delete messages per call.
+func (ch *ConversationsHandler) ConversationsCleanupHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsCleanupHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.cleanupToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if !isChannelAllowedForConfig(channel, toolConfig) {
+		ch.logger.Warn("Cleanup tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return nil, fmt.Errorf("conversations_cleanup tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+
+	olderThan := strings.TrimSpace(request.GetString("older_than", ""))
+	rawPattern := request.GetString("pattern", "")
+	if olderThan == "" && rawPattern == "" {
+		return nil, errors.New("older_than or pattern is required, so that a call never deletes every message at once")
+	}
+	latest, err := parseTimeBound(strings.TrimSpace(request.GetString("latest", "")), time.Local, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latest: %w", err)
+	}
+	if olderThan != "" {
+		age, err := parseCleanupAge(olderThan)
+		if err != nil {
+			return nil, err
+		}
+		cutoff := fmt.Sprintf("%d.000000", time.Now().Add(-age).Unix())
+		if latest == "" || slackTsLess(cutoff, latest) {
+			latest = cutoff
+		}
+	}
+
+	filter := cleanupFilter{}
+	if rawPattern != "" {
+		if filter.pattern, err = regexp.Compile(rawPattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", rawPattern, err)
+		}
+	}
+	maxDelete := request.GetInt("max_delete", defaultCleanupMaxDelete)
+	if maxDelete < 1 || maxDelete > maxCleanupMaxDelete {
+		return nil, fmt.Errorf("max_delete must be between 1 and %d, got %d", maxCleanupMaxDelete, maxDelete)
+	}
+	dryRun := request.GetBool("dry_run", true)
+
+	// Only messages posted as the identity that deletes them can be deleted,
+	// which is the bot in dual-token operation when chat.delete goes to it.
+	filter.author = ch.apiProvider.MethodUserID("chat.delete")
+	if filter.author == "" {
+		ar, err := ch.apiProvider.Slack().AuthTestContext(ctx)
+		if err != nil {
+			ch.logger.Error("AuthTestContext failed", zap.Error(err))
+			return nil, err
+		}
+		filter.author = ar.UserID
+	}
+
+	matched, meta, err := ch.scanCleanupCandidates(ctx, channel, latest, filter, maxDelete)
+	if err != nil {
+		return nil, err
+	}
+	meta.DryRun = dryRun
+
+	lim := limiter.Tier3.Limiter()
+	results := make([]CleanupResult, 0, len(matched))
+	for _, msg := range matched {
+		result := CleanupResult{Channel: channel, Timestamp: msg.Timestamp, Text: msg.Text, Status: "would_delete"}
+		if t, err := text.SlackTimestampToTime(msg.Timestamp); err == nil {
+			result.Time = t.UTC().Format(time.RFC3339)
+		}
+		if dryRun {
+			results = append(results, result)
+			continue
+		}
+
+		err := ch.deleteMessageThrottled(ctx, lim, channel, msg.Timestamp)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err == nil, slackErrorCode(err) == "message_not_found":
+			result.Status = "deleted"
+			meta.Deleted++
+		default:
+			ch.logger.Warn("Cleanup delete failed",
+				zap.String("channel", channel),
+				zap.String("timestamp", msg.Timestamp),
+				zap.Error(err),
+			)
+			result.Status, result.Error = "failed", err.Error()
+			meta.Failed++
+		}
+		results = append(results, result)
+	}
+	ch.logger.Info("Channel cleanup finished",
+		zap.String("channel", channel),
+		zap.Bool("dry_run", dryRun),
+		zap.Int("matched", meta.Matched),
+		zap.Int("deleted", meta.Deleted),
+		zap.Int("failed", meta.Failed),
+	)
+
+	csvBytes, err := gocsv.MarshalBytes(&results)
+	if err != nil {
+		ch.logger.Error("Failed to marshal cleanup results to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), meta)
+}
+
+// scanCleanupCandidates walks the channel's history back from latest and
+// returns up to limit messages filter matches, newest first.
+func (ch *ConversationsHandler) scanCleanupCandidates(ctx context.Context, channel, latest string, filter cleanupFilter, limit int) ([]slack.Message, CleanupMetadata, error) {
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Limit:     cleanupScanPageSize,
+		Latest:    latest,
+	}
+
+	var (
+		matched []slack.Message
+		meta    CleanupMetadata
+	)
+scan:
+	for {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+			return nil, meta, err
+		}
+
+		for _, msg := range history.Messages {
+			meta.Scanned++
+			if filter.matches(msg) {
+				matched = append(matched, msg)
+			}
+			if len(matched) >= limit || meta.Scanned >= maxCleanupScanMessages {
+				meta.Truncated = true
+				meta.NextLatest = msg.Timestamp
+				break scan
+			}
+		}
+
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		historyParams.Cursor = history.ResponseMetaData.NextCursor
+	}
+	meta.Matched = len(matched)
+	return matched, meta, nil
+}
+
+// deleteMessageThrottled deletes one message once lim allows it, waiting out
+// and retrying Slack's own rate limit a few times.
+func (ch *ConversationsHandler) deleteMessageThrottled(ctx context.Context, lim *rate.Limiter, channel, timestamp string) error {
+	for attempt := 0; ; attempt++ {
+		if err := lim.Wait(ctx); err != nil {
+			return err
+		}
+		_, _, err := ch.apiProvider.Slack().DeleteMessageContext(ctx, channel, timestamp)
+		var rlErr *slack.RateLimitedError
+		if !errors.As(err, &rlErr) || attempt >= cleanupDeleteRetries {
+			return err
+		}
+		ch.logger.Warn("Rate limited while deleting messages, backing off",
+			zap.Duration("retry_after", rlErr.RetryAfter),
+			zap.Int("attempt", attempt+1),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rlErr.RetryAfter):
+		}
+	}
+}
+
+// parseCleanupAge reads older_than: a Go duration such as "36h", or a whole
+// number of days or weeks such as "7d" or "2w".
+func parseCleanupAge(raw string) (time.Duration, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("older_than must be positive, got %q", raw)
+		}
+		return d, nil
+	}
+	if len(raw) >= 2 {
+		if n, err := strconv.Atoi(raw[:len(raw)-1]); err == nil && n > 0 {
+			switch raw[len(raw)-1] {
+			case 'd':
+				return time.Duration(n) * 24 * time.Hour, nil
+			case 'w':
+				return time.Duration(n) * 7 * 24 * time.Hour, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid older_than %q: use a duration such as 36h, or days or weeks such as 7d or 2w", raw)
+}
+
+// cleanupToolPolicy returns the SLACK_MCP_CLEANUP_TOOL channel policy, or an
+// error when conversations_cleanup is not enabled.
+func (ch *ConversationsHandler) cleanupToolPolicy() (string, error) {
+	toolConfig := ch.apiProvider.Config().CleanupTool
+	if toolConfig == "" {
+		if !ch.toolExplicitlyEnabled("conversations_cleanup") {
+			ch.logger.Error("Cleanup tool disabled by default")
+			return "", errors.New(
+				"by default, the conversations_cleanup tool is disabled because it deletes messages. " +
+					"To enable it, set the SLACK_MCP_CLEANUP_TOOL environment variable to true, 1, or comma separated list of channels " +
+					"to limit where the MCP can delete its own messages, e.g. 'SLACK_MCP_CLEANUP_TOOL=C1234567890'",
+			)
+		}
+		toolConfig = "true"
+	}
+	return toolConfig, nil
+}
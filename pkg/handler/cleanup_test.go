@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseCleanupAge(t *testing.T) {
+	for raw, want := range map[string]time.Duration{
+		"36h": 36 * time.Hour,
+		"90m": 90 * time.Minute,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	} {
+		got, err := parseCleanupAge(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	for _, raw := range []string{"", "0d", "-1h", "3m2", "week", "5y"} {
+		_, err := parseCleanupAge(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestUnitCleanupFilter(t *testing.T) {
+	msg := func(user, subtype, text string) slack.Message {
+		var m slack.Message
+		m.User, m.SubType, m.Text = user, subtype, text
+		return m
+	}
+
+	f := cleanupFilter{author: "U_BOT", pattern: regexp.MustCompile(`^\[deploy\]`)}
+	assert.True(t, f.matches(msg("U_BOT", "", "[deploy] api v1.2 done")))
+	assert.True(t, f.matches(msg("U_BOT", "bot_message", "[deploy] web v3 done")))
+	assert.False(t, f.matches(msg("U_BOT", "", "Build passed")), "pattern does not match")
+	assert.False(t, f.matches(msg("U_OTHER", "", "[deploy] by someone else")), "other author")
+	assert.False(t, f.matches(msg("U_BOT", "channel_join", "[deploy] joined")), "channel event")
+
+	f.pattern = nil
+	assert.True(t, f.matches(msg("U_BOT", "", "anything")))
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultContextCount is how many channel messages conversations_context
+	// returns on each side of the message.
+	defaultContextCount = 5
+	// defaultContextThreadLimit and maxContextThreadLimit bound the thread
+	// replies it returns.
+	defaultContextThreadLimit = 50
+	maxContextThreadLimit     = 1000
+)
+
+var (
+	permalinkPathRe = regexp.MustCompile(`^/archives/([CDG][A-Z0-9]+)/p(\d{7,})$`)
+	permalinkTsRe   = regexp.MustCompile(`^\d+\.\d+$`)
+)
+
+// ContextMetadata follows the rows of conversations_context: which row is
+// the linked message and where the channel context ends and the thread
+// begins.
+type ContextMetadata struct {
+	ChannelID string `json:"channel_id"`
+	// TargetTs is the ts of the linked message.
+	TargetTs string `json:"target_ts"`
+	// ThreadTs is the parent of the thread the message starts or is in.
+	ThreadTs string `json:"thread_ts,omitempty"`
+	// ContextRows are the first rows: the channel messages around the
+	// message, or around its thread's parent for a reply, newest first.
+	ContextRows int `json:"context_rows"`
+	// ThreadRows follow them: the replies of the thread, oldest first.
+	ThreadRows int `json:"thread_rows"`
+	// ThreadTruncated is set when the thread had more replies than
+	// thread_limit; the returned ones are those closest to the message.
+	ThreadTruncated bool            `json:"thread_truncated,omitempty"`
+	Thread          *ThreadMetadata `json:"thread,omitempty"`
+}
+
+// parsePermalink returns the channel and message ts of a Slack message link,
+// such as https://example.slack.com/archives/C0123ABCD/p1718000000123456,
+// and the thread_ts Slack adds to the links of replies.
+func parsePermalink(raw string) (channel, ts, threadTs string, err error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", "", "", errors.New("permalink is required, e.g. https://example.slack.com/archives/C0123ABCD/p1718000000123456")
+	}
+	u, err := url.Parse(strings.Trim(strings.TrimSpace(raw), "<>"))
+	if err != nil || u.Host == "" {
+		return "", "", "", fmt.Errorf("permalink %q is not a URL", raw)
+	}
+	m := permalinkPathRe.FindStringSubmatch(strings.TrimSuffix(u.Path, "/"))
+	if m == nil {
+		return "", "", "", fmt.Errorf("permalink %q is not a Slack message link such as https://example.slack.com/archives/C0123ABCD/p1718000000123456", raw)
+	}
+	digits := m[2]
+	threadTs = u.Query().Get("thread_ts")
+	if threadTs != "" && !permalinkTsRe.MatchString(threadTs) {
+		return "", "", "", fmt.Errorf("permalink %q has an invalid thread_ts %q", raw, threadTs)
+	}
+	return m[1], digits[:len(digits)-6] + "." + digits[len(digits)-6:], threadTs, nil
+}
+
+// threadWindow returns up to limit replies, the parent excluded, centred on
+// ts when it is one of them and the first ones otherwise, and whether any
+// were left out.
+func threadWindow(thread []slack.Message, parentTs, ts string, limit int) ([]slack.Message, bool) {
+	replies := make([]slack.Message, 0, len(thread))
+	target := -1
+	for _, m := range thread {
+		if m.Timestamp == parentTs {
+			continue
+		}
+		if m.Timestamp == ts {
+			target = len(replies)
+		}
+		replies = append(replies, m)
+	}
+	if len(replies) <= limit {
+		return replies, false
+	}
+	start := 0
+	if target >= 0 {
+		start = min(max(0, target-limit/2), len(replies)-limit)
+	}
+	return replies[start : start+limit], true
+}
+
+// ConversationsContextHandler answers "what's going on here" for a pasted
+// permalink in one call: the channel messages around the message, or around
+// the parent of its thread, followed by the thread's replies, instead of a
+// history, a replies and a search call.
+func (ch *ConversationsHandler) ConversationsContextHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsContextHandler called", zap.Any("params", request.Params))
+
+	channel, ts, threadTs, err := parsePermalink(request.GetString("permalink", ""))
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	count := request.GetInt("context", defaultContextCount)
+	if count < 1 || count > maxAroundCount {
+		return nil, fmt.Errorf("context must be between 1 and %d, got %d", maxAroundCount, count)
+	}
+	includeThread := request.GetBool("include_thread", true)
+	threadLimit := request.GetInt("thread_limit", defaultContextThreadLimit)
+	if threadLimit < 1 || threadLimit > maxContextThreadLimit {
+		return nil, fmt.Errorf("thread_limit must be between 1 and %d, got %d", maxContextThreadLimit, threadLimit)
+	}
+	render, err := parseRenderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var around, thread []slack.Message
+	if threadTs == "" {
+		if around, err = ch.fetchAround(ctx, channel, ts, count); err != nil {
+			return nil, err
+		}
+		switch target := findMessage(around, ts); {
+		case target == nil:
+			// Replies are not in channel history; links to them normally
+			// carry thread_ts, so look the thread up for those that do not.
+			if thread, err = ch.fetchThread(ctx, channel, ts, nil); err != nil {
+				return nil, err
+			}
+			if threadTs = threadParent(thread, ts); threadTs == ts {
+				return nil, fmt.Errorf("message %s not found in %s", ts, channel)
+			}
+			around = nil
+		case target.ReplyCount > 0:
+			threadTs = ts
+		}
+	}
+	if around == nil {
+		if around, err = ch.fetchAround(ctx, channel, threadTs, count); err != nil {
+			return nil, err
+		}
+	}
+
+	meta := ContextMetadata{ChannelID: channel, TargetTs: ts, ThreadTs: threadTs}
+	if threadTs == "" || !includeThread {
+		return ch.contextResult(ctx, request, meta, around, nil, render, loc)
+	}
+	if thread == nil {
+		if thread, err = ch.fetchThread(ctx, channel, threadTs, newProgressReporter(ctx, request)); err != nil {
+			return nil, err
+		}
+	}
+	threadMeta := ch.buildThreadMetadata(channel, threadTs, thread)
+	meta.Thread = &threadMeta
+	replies, truncated := threadWindow(thread, threadTs, ts, threadLimit)
+	meta.ThreadTruncated = truncated
+	return ch.contextResult(ctx, request, meta, around, replies, render, loc)
+}
+
+// contextResult writes the channel context and thread replies as one CSV
+// followed by meta.
+func (ch *ConversationsHandler) contextResult(ctx context.Context, request mcp.CallToolRequest, meta ContextMetadata, around, replies []slack.Message, render string, loc *time.Location) (*mcp.CallToolResult, error) {
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(append(append([]slack.Message(nil), around...), replies...)))
+	contextRows := ch.convertMessagesFromHistory(around, meta.ChannelID, false, false, false, render, loc)
+	threadRows := ch.convertMessagesFromHistory(replies, meta.ChannelID, false, false, false, render, loc)
+	var err error
+	if contextRows, err = classifyMessages(ctx, request.Params.Name, contextRows); err != nil {
+		return nil, err
+	}
+	if threadRows, err = classifyMessages(ctx, request.Params.Name, threadRows); err != nil {
+		return nil, err
+	}
+	meta.ContextRows, meta.ThreadRows = len(contextRows), len(threadRows)
+	if meta.Thread != nil {
+		meta.Thread.Returned = len(threadRows)
+	}
+
+	res, err := MarshalMessagesToCSV(append(contextRows, threadRows...), messageColumnsOf(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	return withJSONMetadata(res, meta)
+}
+
+func findMessage(msgs []slack.Message, ts string) *slack.Message {
+	for i := range msgs {
+		if msgs[i].Timestamp == ts {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParsePermalink(t *testing.T) {
+	channel, ts, threadTs, err := parsePermalink("https://example.slack.com/archives/C0123ABCD/p1718000000123456")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C0123ABCD", "1718000000.123456", ""}, []string{channel, ts, threadTs})
+
+	channel, ts, threadTs, err = parsePermalink("<https://example.slack.com/archives/C0123ABCD/p1718000300000400?thread_ts=1718000000.123456&cid=C0123ABCD>")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"C0123ABCD", "1718000300.000400", "1718000000.123456"}, []string{channel, ts, threadTs})
+
+	for raw, want := range map[string]string{
+		"":                                    "permalink is required",
+		"C0123ABCD/p1718000000123456":         "is not a URL",
+		"https://example.slack.com/team/U012": "is not a Slack message link",
+		"https://example.slack.com/archives/C0123ABCD/p1718000000123456?thread_ts=x": "invalid thread_ts",
+	} {
+		_, _, _, err := parsePermalink(raw)
+		assert.ErrorContains(t, err, want, raw)
+	}
+}
+
+func TestUnitThreadWindow(t *testing.T) {
+	thread := make([]slack.Message, 0, 11)
+	for _, ts := range []string{"100.0", "101.0", "102.0", "103.0", "104.0", "105.0", "106.0", "107.0", "108.0", "109.0", "110.0"} {
+		thread = append(thread, slack.Message{Msg: slack.Msg{Timestamp: ts}})
+	}
+	stamps := func(msgs []slack.Message) []string {
+		out := make([]string, 0, len(msgs))
+		for _, m := range msgs {
+			out = append(out, m.Timestamp)
+		}
+		return out
+	}
+
+	all, truncated := threadWindow(thread, "100.0", "105.0", 50)
+	assert.False(t, truncated)
+	assert.Len(t, all, 10, "the parent is left out")
+
+	around, truncated := threadWindow(thread, "100.0", "105.0", 4)
+	assert.True(t, truncated)
+	assert.Equal(t, []string{"103.0", "104.0", "105.0", "106.0"}, stamps(around))
+
+	last, _ := threadWindow(thread, "100.0", "110.0", 4)
+	assert.Equal(t, []string{"107.0", "108.0", "109.0", "110.0"}, stamps(last))
+
+	first, _ := threadWindow(thread, "100.0", "100.0", 3)
+	assert.Equal(t, []string{"101.0", "102.0", "103.0"}, stamps(first), "the first replies for the parent")
+}
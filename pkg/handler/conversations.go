@@ -4,16 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
-	"os"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
 	"github.com/korotovsky/slack-mcp-server/pkg/text"
@@ -21,18 +25,29 @@ import (
 	"github.com/slack-go/slack"
 	slackGoUtil "github.com/takara2314/slack-go-util"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 const (
 	defaultConversationsNumericLimit    = 50
+	fetchAllRepliesPageSize             = 200
+	maxBatchMessages                    = 50
+	searchNarrowHintPages               = 5
+	maxBotUsernameLength                = 80
+	maxGroupDMUsers                     = 8
 	defaultConversationsExpressionLimit = "1d"
 	maxFileSizeBytes                    = 5 * 1024 * 1024 // 5MB limit
+	// contentTypeSlackMrkdwn posts text that is already Slack mrkdwn as is.
+	// Tools build it internally; content_type does not accept it.
+	contentTypeSlackMrkdwn = "text/x-slack-mrkdwn"
 )
 
 var validFilterKeys = map[string]struct{}{
 	"is":     {},
+	"has":    {},
 	"in":     {},
 	"from":   {},
+	"to":     {},
 	"with":   {},
 	"before": {},
 	"after":  {},
@@ -40,6 +55,46 @@ var validFilterKeys = map[string]struct{}{
 	"during": {},
 }
 
+// filterKeysOrder is the order in which search modifiers are rendered into the final query.
+var filterKeysOrder = []string{"is", "has", "in", "from", "to", "with", "before", "after", "on", "during"}
+
+// searchFlagFilters maps the boolean parameters of conversations_search_messages
+// to the modifier they add.
+var searchFlagFilters = []struct {
+	param, key, value string
+}{
+	{"filter_threads_only", "is", "thread"},
+	{"is_thread", "is", "thread"},
+	{"has_reactions", "has", "reaction"},
+	{"has_files", "has", "file"},
+	{"has_links", "has", "link"},
+}
+
+// validFilterValues lists the accepted values for modifiers with a closed set of values.
+// Reaction modifiers in the form has::emoji: are validated separately.
+var validFilterValues = map[string]map[string]struct{}{
+	"is": {
+		"thread": {},
+		"saved":  {},
+		"dm":     {},
+	},
+	"has": {
+		"file":     {},
+		"link":     {},
+		"pin":      {},
+		"reaction": {},
+		"star":     {},
+	},
+}
+
+var (
+	emojiModifierRe    = regexp.MustCompile(`^:[a-z0-9_+'-]+:$`)
+	searchDatePeriodRe = regexp.MustCompile(`^\d{4}(-\d{2})?$`)
+	// searchChannelIDRe tells channel IDs in in: apart from bare channel
+	// names such as in:customers, which Slack resolves itself.
+	searchChannelIDRe = regexp.MustCompile(`^[CG][A-Z0-9]{8,}$`)
+)
+
 type Message struct {
 	MsgID     string `json:"msgID"`
 	UserID    string `json:"userID"`
@@ -49,43 +104,134 @@ type Message struct {
 	ThreadTs  string `json:"ThreadTs"`
 	Text      string `json:"text"`
 	Time      string `json:"time"`
+	TimeHuman string `json:"timeHuman"`
 	Reactions string `json:"reactions,omitempty"`
+	// ReactionCounts summarizes the reactions as "thumbsup:3,eyes:1".
+	ReactionCounts string `json:"reactionCounts,omitempty"`
+	ReplyCount int   `json:"replyCount,omitempty"`
+	IsEdited  bool   `json:"isEdited,omitempty"`
 	BotName   string `json:"botName,omitempty"`
 	FileCount int    `json:"fileCount,omitempty"`
 	AttachmentIDs   string `json:"attachmentIDs,omitempty"`
 	HasMedia  bool   `json:"hasMedia,omitempty"`
+	// Unfurl* are only filled with include_unfurls; several links are "|"-joined.
+	UnfurlURLs         string `json:"unfurlURLs,omitempty"`
+	UnfurlTitles       string `json:"unfurlTitles,omitempty"`
+	UnfurlDescriptions string `json:"unfurlDescriptions,omitempty"`
+	UnfurlServices     string `json:"unfurlServices,omitempty"`
+	// Metadata is the message's event_type and event_payload as JSON.
+	Metadata string `json:"metadata,omitempty"`
+	// Tombstone is "edited" or "deleted" with include_tombstones, and
+	// TombstoneTime when that happened, if Slack recorded it.
+	Tombstone     string `json:"tombstone,omitempty"`
+	TombstoneTime string `json:"tombstoneTime,omitempty"`
+	// Labels are set by SLACK_MCP_CLASSIFIER, "|"-joined.
+	Labels string `json:"labels,omitempty"`
+	// Language is the ISO 639-1 code of the text with include_language or
+	// filter_language, "und" when it cannot be told.
+	Language string `json:"language,omitempty"`
 	Cursor    string `json:"cursor"`
 }
 
+// BatchMessageResult is the per-item CSV output row for conversations_add_messages.
+type BatchMessageResult struct {
+	Index    int    `csv:"index"`
+	Channel  string `csv:"channelID"`
+	ThreadTs string `csv:"threadTs"`
+	OK       bool   `csv:"ok"`
+	MsgID    string `csv:"msgID"`
+	Error    string `csv:"error"`
+}
+
+// OpenedDM is the row returned by conversations_open_dm.
+type OpenedDM struct {
+	Channel     string `csv:"channelID"`
+	Name        string `csv:"name"`
+	Type        string `csv:"type"`
+	Users       string `csv:"users"`
+	AlreadyOpen bool   `csv:"alreadyOpen"`
+}
+
 type User struct {
 	UserID   string `json:"userID"`
 	UserName string `json:"userName"`
 	RealName string `json:"realName"`
+	TZ       string `json:"tz"`
+	TZOffset string `json:"tzOffset"`
+	Locale   string `json:"locale"`
+	// WorkingHours is derived from the user's DND schedule, e.g. "08:00-22:00".
+	WorkingHours string `json:"workingHours"`
 }
 
 type UserSearchResult struct {
-	UserID      string `csv:"UserID"`
-	UserName    string `csv:"UserName"`
-	RealName    string `csv:"RealName"`
-	DisplayName string `csv:"DisplayName"`
-	Email       string `csv:"Email"`
-	Title       string `csv:"Title"`
-	DMChannelID string `csv:"DMChannelID"`
+	UserID       string `csv:"UserID"`
+	UserName     string `csv:"UserName"`
+	RealName     string `csv:"RealName"`
+	DisplayName  string `csv:"DisplayName"`
+	Email        string `csv:"Email"`
+	Title        string `csv:"Title"`
+	AvatarURL    string `csv:"AvatarURL"`
+	StatusEmoji  string `csv:"StatusEmoji"`
+	StatusText   string `csv:"StatusText"`
+	IsBot        bool   `csv:"IsBot"`
+	IsDeleted    bool   `csv:"IsDeleted"`
+	DMChannelID  string `csv:"DMChannelID"`
+	TZ           string `csv:"TZ"`
+	TZOffset     string `csv:"TZOffset"`
+	Locale       string `csv:"Locale"`
+	WorkingHours string `csv:"WorkingHours"`
+	Presence     string `csv:"Presence"`
+	InDND        bool   `csv:"InDND"`
+	OutOfOffice  bool   `csv:"OutOfOffice"`
 }
 
 type conversationParams struct {
-	channel  string
-	limit    int
-	oldest   string
-	latest   string
-	cursor   string
-	activity bool
+	channel   string
+	limit     int
+	oldest    string
+	latest    string
+	inclusive bool
+	cursor    string
+	activity  bool
+	unfurls   bool
+	tombstones bool
+	render    string
+	loc       *time.Location
 }
 
 type searchParams struct {
-	query string
-	limit int
-	page  int
+	query   string
+	limit   int
+	page    int
+	sort    string
+	sortDir string
+	unfurls bool
+	render  string
+	loc     *time.Location
+	// postFilter, when set, keeps only the matches whose text it matches.
+	postFilter *regexp.Regexp
+}
+
+// SearchMetadata describes the Slack search pagination state and is returned
+// alongside the CSV rows of conversations_search_messages.
+type SearchMetadata struct {
+	Query      string `json:"query"`
+	Total      int    `json:"total"`
+	Page       int    `json:"page"`
+	PageCount  int    `json:"page_count"`
+	PerPage    int    `json:"per_page"`
+	Returned   int    `json:"returned"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// PostFiltered counts the matches of the page that post_filter_regex
+	// removed; Total and the pages still count them.
+	PostFiltered int `json:"post_filtered,omitempty"`
+	// LanguageFiltered counts the matches of the page that filter_language
+	// removed, as PostFiltered does.
+	LanguageFiltered int `json:"language_filtered,omitempty"`
+	// Hint suggests narrowing the query when paging through every match
+	// would take many calls.
+	Hint string `json:"hint,omitempty"`
 }
 
 type addMessageParams struct {
@@ -93,6 +239,26 @@ type addMessageParams struct {
 	threadTs    string
 	text        string
 	contentType string
+	identity    botIdentity
+	metadata    *slack.SlackMetadata
+	// broadcast also shows a thread reply in the channel.
+	broadcast bool
+	// postAt schedules the message for this Unix time; 0 posts it now.
+	postAt int64
+	// attachments are posted below the text, e.g. a forwarded message.
+	attachments []slack.Attachment
+	// attached are the links of existing files and pages appended to text;
+	// they are unfurled whatever SLACK_MCP_ADD_MESSAGE_UNFURLING says about
+	// the rest of the text.
+	attached []string
+}
+
+// botIdentity overrides the name and icon a bot token posts with. It needs
+// the chat:write.customize scope.
+type botIdentity struct {
+	username  string
+	iconEmoji string
+	iconURL   string
 }
 
 type addReactionParams struct {
@@ -103,26 +269,42 @@ type addReactionParams struct {
 
 type filesGetParams struct {
 	fileID string
+	// previewMaxDim is the longest side of an image preview; 0 returns the
+	// full file.
+	previewMaxDim int
 }
 
 type usersSearchParams struct {
-	query string
-	limit int
+	query           string
+	limit           int
+	workingHours    bool
+	includeDeleted  bool
+	openDMs         bool
+	includePresence bool
+	availability    availabilityFilter
 }
 
 type ConversationsHandler struct {
 	apiProvider *provider.ApiProvider
 	logger      *zap.Logger
+	activity    channelActivityCache
+	// threadParents are the resolved thread_ts of posted replies.
+	threadParents threadParentCache
+	// repliesLimiter paces conversations.replies pages across all calls, so
+	// concurrent thread fetches share one Tier 3 budget.
+	repliesLimiter *rate.Limiter
 }
 
 func NewConversationsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *ConversationsHandler {
 	return &ConversationsHandler{
-		apiProvider: apiProvider,
-		logger:      logger,
+		apiProvider:    apiProvider,
+		logger:         logger,
+		repliesLimiter: limiter.Tier3.Limiter(),
 	}
 }
 
-// UsersResource streams a CSV of all users
+// UsersResource streams a CSV of the users directory. Query flags add
+// deactivated users, bots and custom profile field values.
 func (ch *ConversationsHandler) UsersResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
 	ch.logger.Debug("UsersResource called", zap.Any("params", request.Params))
 
@@ -155,16 +337,28 @@ func (ch *ConversationsHandler) UsersResource(ctx context.Context, request mcp.R
 	}
 
 	// collect users
-	usersMaps := ch.apiProvider.ProvideUsersMap()
-	users := usersMaps.Users
-	usersList := make([]User, 0, len(users))
-	for _, user := range users {
-		usersList = append(usersList, User{
-			UserID:   user.ID,
-			UserName: user.Name,
-			RealName: user.RealName,
-		})
+	opts := parseUsersResourceOptions(request.Params.URI)
+	users := ch.apiProvider.ProvideUsersMap().Users
+	ids := make([]string, 0, len(users))
+	for id := range users {
+		ids = append(ids, id)
+	}
+	// Looking up DND for the whole directory would take minutes, so only
+	// schedules already fetched by users_search are included.
+	dnd := ch.apiProvider.CachedUsersDND(ids)
+	var labels map[string]string
+	if opts.includeCustomFields {
+		schema, err := ch.apiProvider.Slack().GetTeamProfileContext(ctx)
+		if err != nil {
+			ch.logger.Error("GetTeamProfileContext failed", zap.Error(err))
+			return nil, fmt.Errorf("failed to read the custom profile fields of the workspace: %w", err)
+		}
+		labels = make(map[string]string, len(schema.Fields))
+		for _, f := range schema.Fields {
+			labels[f.ID] = f.Label
+		}
 	}
+	usersList := usersExport(users, dnd, opts, labels)
 
 	// marshal CSV
 	csvBytes, err := gocsv.MarshalBytes(&usersList)
@@ -173,9 +367,13 @@ func (ch *ConversationsHandler) UsersResource(ctx context.Context, request mcp.R
 		return nil, err
 	}
 
+	uri := "slack://" + ws + "/users"
+	if u, err := url.Parse(request.Params.URI); err == nil && u.RawQuery != "" {
+		uri += "?" + u.RawQuery
+	}
 	return []mcp.ResourceContents{
 		mcp.TextResourceContents{
-			URI:      "slack://" + ws + "/users",
+			URI:      uri,
 			MIMEType: "text/csv",
 			Text:     string(csvBytes),
 		},
@@ -198,17 +396,276 @@ func (ch *ConversationsHandler) ConversationsAddMessageHandler(ctx context.Conte
 		return nil, err
 	}
 
+	respChannel, respTimestamp, err := ch.postMessage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	if params.postAt != 0 {
+		scheduled := []ScheduledMessage{{
+			Channel:            respChannel,
+			ScheduledMessageID: respTimestamp,
+			ThreadTs:           params.threadTs,
+			PostAt:             time.Unix(params.postAt, 0).In(loc).Format(time.RFC3339),
+		}}
+		csvBytes, err := gocsv.MarshalBytes(&scheduled)
+		if err != nil {
+			ch.logger.Error("Failed to marshal scheduled message to CSV", zap.Error(err))
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(csvBytes)), nil
+	}
+
+	// fetch the single message we just posted
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: respChannel,
+		Limit:     1,
+		Oldest:    respTimestamp,
+		Latest:    respTimestamp,
+		Inclusive: true,
+
+		IncludeAllMetadata: true,
+	}
+	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+	if err != nil {
+		ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+		return nil, err
+	}
+	ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
+
+	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false, false, false, renderPlain, loc)
+	return MarshalMessagesToCSV(messages, MessageColumns{Metadata: params.metadata != nil})
+}
+
+// ConversationsAddMessagesHandler posts a batch of messages, rate limited, and
+// reports success or failure per item. A failing item does not stop the batch.
+func (ch *ConversationsHandler) ConversationsAddMessagesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsAddMessagesHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	rawItems, ok := request.GetArguments()["messages"].([]any)
+	if !ok || len(rawItems) == 0 {
+		return nil, errors.New("messages must be a non-empty array of {channel_id, thread_ts, text} objects")
+	}
+	if len(rawItems) > maxBatchMessages {
+		return nil, fmt.Errorf("messages must contain at most %d items, got %d", maxBatchMessages, len(rawItems))
+	}
+	if _, err := ch.addMessageToolPolicy(); err != nil {
+		return nil, err
+	}
+	contentType := request.GetString("content_type", "text/markdown")
+	resolveMentions := request.GetBool("resolve_mentions", true)
+
+	lim := limiter.Tier3.Limiter()
+	results := make([]BatchMessageResult, 0, len(rawItems))
+	for i, raw := range rawItems {
+		result := BatchMessageResult{Index: i}
+
+		item, ok := raw.(map[string]any)
+		if !ok {
+			result.Error = "item must be an object with channel_id, thread_ts and text"
+			results = append(results, result)
+			continue
+		}
+		if _, ok := item["content_type"]; !ok {
+			item["content_type"] = contentType
+		}
+		if _, ok := item["resolve_mentions"]; !ok {
+			item["resolve_mentions"] = resolveMentions
+		}
+		result.Channel, _ = item["channel_id"].(string)
+		result.ThreadTs, _ = item["thread_ts"].(string)
+
+		var itemRequest mcp.CallToolRequest
+		itemRequest.Params.Arguments = item
+		params, err := ch.parseParamsToolAddMessage(ctx, itemRequest)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Channel = params.channel
+		if params.postAt != 0 {
+			result.Error = "post_at is not supported in batches, use conversations_add_message"
+			results = append(results, result)
+			continue
+		}
+
+		if err := lim.Wait(ctx); err != nil {
+			return nil, err
+		}
+		_, ts, err := ch.postMessage(ctx, params)
+		if err != nil {
+			ch.logger.Warn("Batch message failed",
+				zap.Int("index", i),
+				zap.String("channel", params.channel),
+				zap.Error(err),
+			)
+			result.Error = err.Error()
+		} else {
+			result.OK = true
+			result.MsgID = ts
+			journalWriteStep(ctx, WriteStep{Index: i, Channel: params.channel, Ts: ts})
+		}
+		results = append(results, result)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&results)
+	if err != nil {
+		ch.logger.Error("Failed to marshal batch results to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// ConversationsOpenDMHandler opens, or returns the existing, DM with one user
+// or group DM with several, so conversations_add_message can post to users
+// that have no DM in the channels cache yet.
+func (ch *ConversationsHandler) ConversationsOpenDMHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsOpenDMHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	if _, err := ch.addMessageToolPolicy(); err != nil {
+		return nil, err
+	}
+
+	users, err := ch.parseDMUsers(request.GetString("users", ""))
+	if err != nil {
+		ch.logger.Error("Invalid users for open-dm", zap.Error(err))
+		return nil, err
+	}
+
+	self := ""
+	if ar, err := ch.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		self = ar.UserID
+	}
+	if policy := newDMPolicy(ch.apiProvider.Config().DMAllowlist); policy != nil {
+		if err := policy.check(strings.Join(users, ","), users, self, ch.apiProvider.ProvideUsersMap().UsersInv); err != nil {
+			ch.logger.Warn("Open-dm blocked by DM allowlist", zap.Strings("users", users), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	channel, _, alreadyOpen, err := ch.apiProvider.Slack().OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users:    users,
+		ReturnIM: true,
+	})
+	if err != nil {
+		ch.logger.Error("Slack OpenConversationContext failed", zap.Strings("users", users), zap.Error(err))
+		return nil, err
+	}
+	if channel == nil || channel.ID == "" {
+		return nil, errors.New("conversations.open returned no channel")
+	}
+
+	// conversations.open returns little more than the ID for group DMs; fill
+	// in what is known so the cached entry resolves like one from a refresh.
+	if len(users) == 1 {
+		channel.IsIM = true
+		if channel.User == "" {
+			channel.User = users[0]
+		}
+	} else {
+		channel.IsMpIM = true
+		if len(channel.Members) == 0 {
+			channel.Members = users
+			if self != "" && !slices.Contains(users, self) {
+				channel.Members = append(slices.Clone(users), self)
+			}
+		}
+		if channel.NameNormalized == "" {
+			channel.NameNormalized = channel.Name
+		}
+	}
+
+	row := OpenedDM{
+		Channel:     channel.ID,
+		Type:        "im",
+		Users:       strings.Join(users, ","),
+		AlreadyOpen: alreadyOpen,
+	}
+	if channel.IsMpIM {
+		row.Type = "mpim"
+	}
+	if channel.IsIM || channel.NameNormalized != "" {
+		row.Name = ch.apiProvider.RememberChannel(*channel).Name
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&[]OpenedDM{row})
+	if err != nil {
+		ch.logger.Error("Failed to marshal opened DM to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// parseDMUsers resolves a comma separated list of user IDs, @handles or
+// <@U…> mentions into unique user IDs.
+func (ch *ConversationsHandler) parseDMUsers(raw string) ([]string, error) {
+	usersInv := ch.apiProvider.ProvideUsersMap().UsersInv
+
+	var users []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(item), "<@"), ">")
+		if item == "" {
+			continue
+		}
+		uid := item
+		if !isSlackUserIDPrefix(item) {
+			id, ok := usersInv[strings.TrimPrefix(item, "@")]
+			if !ok {
+				return nil, fmt.Errorf("user %q not found", item)
+			}
+			uid = id
+		}
+		if !slices.Contains(users, uid) {
+			users = append(users, uid)
+		}
+	}
+
+	if len(users) == 0 {
+		return nil, errors.New("users must list at least one user ID or @handle")
+	}
+	if len(users) > maxGroupDMUsers {
+		return nil, fmt.Errorf("users must list at most %d users for a group DM, got %d", maxGroupDMUsers, len(users))
+	}
+	return users, nil
+}
+
+// postMessage builds the message options for params, posts it and, when
+// SLACK_MCP_ADD_MESSAGE_MARK is set, marks the conversation as read. A
+// thread_ts of a reply is replaced with that of its thread in params.
+func (ch *ConversationsHandler) postMessage(ctx context.Context, params *addMessageParams) (string, string, error) {
 	var options []slack.MsgOption
 	if params.threadTs != "" {
+		threadTs, err := ch.resolveThreadTs(ctx, params.channel, params.threadTs)
+		if err != nil {
+			return "", "", err
+		}
+		params.threadTs = threadTs
 		options = append(options, slack.MsgOptionTS(params.threadTs))
 	}
+	if params.broadcast {
+		options = append(options, slack.MsgOptionBroadcast())
+	}
 
 	switch params.contentType {
 	case "text/plain":
 		options = append(options, slack.MsgOptionDisableMarkdown())
 		options = append(options, slack.MsgOptionText(params.text, false))
 	case "text/markdown":
-		blocks, err := slackGoUtil.ConvertMarkdownTextToBlocks(params.text)
+		blocks, err := slackGoUtil.ConvertMarkdownTextToBlocks(text.MarkdownTablesToCode(params.text))
 		if err != nil {
 			ch.logger.Warn("Markdown parsing error", zap.Error(err))
 			options = append(options, slack.MsgOptionDisableMarkdown())
@@ -216,12 +673,30 @@ func (ch *ConversationsHandler) ConversationsAddMessageHandler(ctx context.Conte
 		} else {
 			options = append(options, slack.MsgOptionBlocks(blocks...))
 		}
+	case contentTypeSlackMrkdwn:
+		options = append(options, slack.MsgOptionText(params.text, false))
 	default:
-		return nil, errors.New("content_type must be either 'text/plain' or 'text/markdown'")
+		return "", "", errors.New("content_type must be either 'text/plain' or 'text/markdown'")
+	}
+	if len(params.attachments) > 0 {
+		options = append(options, slack.MsgOptionAttachments(params.attachments...))
 	}
 
-	unfurlOpt := os.Getenv("SLACK_MCP_ADD_MESSAGE_UNFURLING")
-	if text.IsUnfurlingEnabled(params.text, unfurlOpt, ch.logger) {
+	if params.identity.username != "" {
+		options = append(options, slack.MsgOptionUsername(params.identity.username))
+	}
+	if params.identity.iconEmoji != "" {
+		options = append(options, slack.MsgOptionIconEmoji(":"+params.identity.iconEmoji+":"))
+	}
+	if params.identity.iconURL != "" {
+		options = append(options, slack.MsgOptionIconURL(params.identity.iconURL))
+	}
+	if params.metadata != nil {
+		options = append(options, slack.MsgOptionMetadata(*params.metadata))
+	}
+
+	cfg := ch.apiProvider.Config()
+	if len(params.attached) > 0 || text.IsUnfurlingEnabled(params.text, cfg.AddMessageUnfurling, ch.logger) {
 		options = append(options, slack.MsgOptionEnableLinkUnfurl())
 	} else {
 		options = append(options, slack.MsgOptionDisableLinkUnfurl())
@@ -232,39 +707,31 @@ func (ch *ConversationsHandler) ConversationsAddMessageHandler(ctx context.Conte
 		zap.String("channel", params.channel),
 		zap.String("thread_ts", params.threadTs),
 		zap.String("content_type", params.contentType),
+		zap.Int64("post_at", params.postAt),
 	)
+	if params.postAt != 0 {
+		respChannel, scheduledID, err := ch.apiProvider.Slack().ScheduleMessageContext(ctx, params.channel, strconv.FormatInt(params.postAt, 10), options...)
+		if err != nil {
+			ch.logger.Error("Slack ScheduleMessageContext failed", zap.Error(err))
+			return "", "", err
+		}
+		return respChannel, scheduledID, nil
+	}
 	respChannel, respTimestamp, err := ch.apiProvider.Slack().PostMessageContext(ctx, params.channel, options...)
 	if err != nil {
 		ch.logger.Error("Slack PostMessageContext failed", zap.Error(err))
-		return nil, err
+		return "", "", err
 	}
 
-	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_MARK")
-	if toolConfig == "1" || toolConfig == "true" || toolConfig == "yes" {
+	if mark := cfg.AddMessageMark; mark == "1" || mark == "true" || mark == "yes" {
 		err := ch.apiProvider.Slack().MarkConversationContext(ctx, params.channel, respTimestamp)
 		if err != nil {
 			ch.logger.Error("Slack MarkConversationContext failed", zap.Error(err))
-			return nil, err
+			return "", "", err
 		}
 	}
 
-	// fetch the single message we just posted
-	historyParams := slack.GetConversationHistoryParameters{
-		ChannelID: respChannel,
-		Limit:     1,
-		Oldest:    respTimestamp,
-		Latest:    respTimestamp,
-		Inclusive: true,
-	}
-	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
-	if err != nil {
-		ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
-		return nil, err
-	}
-	ch.logger.Debug("Fetched conversation history", zap.Int("message_count", len(history.Messages)))
-
-	messages := ch.convertMessagesFromHistory(history.Messages, historyParams.ChannelID, false)
-	return marshalMessagesToCSV(messages)
+	return respChannel, respTimestamp, nil
 }
 
 // ReactionsAddHandler adds an emoji reaction to a message
@@ -358,17 +825,65 @@ func (ch *ConversationsHandler) UsersSearchHandler(ctx context.Context, request
 		zap.Int("limit", params.limit),
 	)
 
-	users, err := ch.apiProvider.SearchUsers(ctx, params.query, params.limit)
+	// Availability filters drop users after the search, so search wider to
+	// still fill the page.
+	searchLimit := params.limit
+	if params.availability.active() {
+		searchLimit = maxUsersSearchLimit
+	}
+	users, err := ch.apiProvider.SearchUsers(ctx, params.query, searchLimit)
 	if err != nil {
 		ch.logger.Error("UsersSearch failed", zap.Error(err))
 		return nil, fmt.Errorf("users search failed: %w", err)
 	}
 
 	channelsMap := ch.apiProvider.ProvideChannelsMaps()
+	now := time.Now()
 
-	results := make([]UserSearchResult, 0, len(users))
+	var dnd map[string]slack.DNDStatus
+	if params.workingHours || params.availability.dnd != nil {
+		ids := make([]string, 0, len(users))
+		for _, user := range users {
+			if !user.Deleted && !user.IsBot {
+				ids = append(ids, user.ID)
+			}
+		}
+		dnd = ch.apiProvider.UsersDND(ctx, ids)
+	}
+
+	// Presence is looked up one user per call, so only for the users that
+	// pass the other filters.
+	var presence map[string]string
+	if params.includePresence || params.availability.presence != "" {
+		var ids []string
+		pre := availabilityFilter{dnd: params.availability.dnd, outOfOffice: params.availability.outOfOffice}
+		for _, user := range users {
+			if user.Deleted || user.IsBot {
+				continue
+			}
+			if pre.match("", inDND(dnd[user.ID], now), isOutOfOffice(user.Profile, now)) {
+				ids = append(ids, user.ID)
+			}
+		}
+		presence = ch.apiProvider.UsersPresence(ctx, ids, maxUsersSearchPresenceLookups)
+	}
+
+	var dms *usersSearchDMOpener
+	if params.openDMs {
+		dms = ch.newUsersSearchDMOpener(ctx)
+	}
+
+	results := make([]UserSearchResult, 0, params.limit)
 	for _, user := range users {
-		if user.Deleted {
+		if len(results) >= params.limit {
+			break
+		}
+		if user.Deleted && !params.includeDeleted {
+			continue
+		}
+		userInDND := inDND(dnd[user.ID], now)
+		userOutOfOffice := isOutOfOffice(user.Profile, now)
+		if !params.availability.match(presence[user.ID], userInDND, userOutOfOffice) {
 			continue
 		}
 
@@ -379,15 +894,30 @@ func (ch *ConversationsHandler) UsersSearchHandler(ctx context.Context, request
 				break
 			}
 		}
+		if dmChannelID == "" {
+			dmChannelID = dms.open(ctx, user)
+		}
 
 		results = append(results, UserSearchResult{
-			UserID:      user.ID,
-			UserName:    user.Name,
-			RealName:    user.RealName,
-			DisplayName: user.Profile.DisplayName,
-			Email:       user.Profile.Email,
-			Title:       user.Profile.Title,
-			DMChannelID: dmChannelID,
+			UserID:       user.ID,
+			UserName:     user.Name,
+			RealName:     user.RealName,
+			DisplayName:  user.Profile.DisplayName,
+			Email:        user.Profile.Email,
+			Title:        user.Profile.Title,
+			AvatarURL:    userAvatarURL(user.Profile),
+			StatusEmoji:  user.Profile.StatusEmoji,
+			StatusText:   user.Profile.StatusText,
+			IsBot:        user.IsBot,
+			IsDeleted:    user.Deleted,
+			DMChannelID:  dmChannelID,
+			TZ:           user.TZ,
+			TZOffset:     userTZOffset(user),
+			Locale:       user.Locale,
+			WorkingHours: workingHoursHint(dnd[user.ID], user),
+			Presence:     presence[user.ID],
+			InDND:        userInDND,
+			OutOfOffice:  userOutOfOffice,
 		})
 	}
 
@@ -427,6 +957,9 @@ func (ch *ConversationsHandler) FilesGetHandler(ctx context.Context, request mcp
 	if fileInfo.Size > maxFileSizeBytes {
 		return nil, fmt.Errorf("file size %d bytes exceeds maximum allowed size of %d bytes", fileInfo.Size, maxFileSizeBytes)
 	}
+	if params.previewMaxDim > 0 && !isPreviewableMimetype(fileInfo.Mimetype) {
+		return nil, fmt.Errorf("preview is only available for PNG, JPEG and GIF images, file %s is %s", fileInfo.ID, fileInfo.Mimetype)
+	}
 
 	var buf bytes.Buffer
 	downloadURL := fileInfo.URLPrivateDownload
@@ -444,6 +977,10 @@ func (ch *ConversationsHandler) FilesGetHandler(ctx context.Context, request mcp
 	}
 
 	content := buf.Bytes()
+	if params.previewMaxDim > 0 {
+		return ch.imagePreviewResult(fileInfo, content, params.previewMaxDim)
+	}
+
 	encoding := "none"
 	var contentStr string
 
@@ -465,6 +1002,38 @@ func (ch *ConversationsHandler) FilesGetHandler(ctx context.Context, request mcp
 	return mcp.NewToolResultText(result), nil
 }
 
+// imagePreviewResult returns the preview metadata as JSON followed by the
+// preview itself as image content, so clients can show it to the model.
+func (ch *ConversationsHandler) imagePreviewResult(fileInfo *slack.File, content []byte, maxDim int) (*mcp.CallToolResult, error) {
+	preview, err := makeImagePreview(content, fileInfo.Mimetype, maxDim)
+	if err != nil {
+		ch.logger.Error("Failed to build image preview", zap.String("file_id", fileInfo.ID), zap.Error(err))
+		return nil, err
+	}
+
+	meta, err := json.Marshal(AttachmentPreview{
+		FileID:           fileInfo.ID,
+		Filename:         fileInfo.Name,
+		Mimetype:         preview.mimeType,
+		Size:             len(preview.data),
+		Width:            preview.width,
+		Height:           preview.height,
+		OriginalMimetype: fileInfo.Mimetype,
+		OriginalSize:     len(content),
+		OriginalWidth:    preview.originalWidth,
+		OriginalHeight:   preview.originalHeight,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(string(meta)),
+			mcp.NewImageContent(base64.StdEncoding.EncodeToString(preview.data), preview.mimeType),
+		},
+	}, nil
+}
+
 func isTextMimetype(mimetype string) bool {
 	if strings.HasPrefix(mimetype, "text/") {
 		return true
@@ -503,15 +1072,43 @@ func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context,
 		zap.String("oldest", params.oldest),
 		zap.String("latest", params.latest),
 		zap.Bool("include_activity", params.activity),
+		zap.Bool("include_tombstones", params.tombstones),
 	)
 
+	aroundTs, aroundCount, err := parseAroundParams(request)
+	if err != nil {
+		return nil, err
+	}
+	if aroundTs != "" {
+		around, err := ch.fetchAround(ctx, params.channel, aroundTs, aroundCount)
+		if err != nil {
+			return nil, err
+		}
+		ch.apiProvider.EnsureUsers(ctx, historyUserIDs(around))
+		ch.emitEmbeddings(ctx, "conversations_history", params.channel, around)
+		messages := ch.convertMessagesFromHistory(around, params.channel, params.activity, params.unfurls, params.tombstones, params.render, params.loc)
+		if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+			return nil, err
+		}
+		if messages, err = detectLanguages(request, messages); err != nil {
+			return nil, err
+		}
+		return MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	}
+
+	cursor, err := decodeCursor("conversations_history", cursorParams(params.channel), params.cursor)
+	if err != nil {
+		return nil, err
+	}
 	historyParams := slack.GetConversationHistoryParameters{
 		ChannelID: params.channel,
 		Limit:     params.limit,
 		Oldest:    params.oldest,
 		Latest:    params.latest,
-		Cursor:    params.cursor,
-		Inclusive: false,
+		Cursor:    cursor,
+		Inclusive: params.inclusive,
+
+		IncludeAllMetadata: true,
 	}
 
 	var allSlackMessages []slack.Message
@@ -530,8 +1127,16 @@ func (ch *ConversationsHandler) ConversationsHistoryHandler(ctx context.Context,
 	}
 
 	ch.logger.Debug("Fetched all conversation history", zap.Int("total_message_count", len(allSlackMessages)))
-	messages := ch.convertMessagesFromHistory(allSlackMessages, params.channel, params.activity)
-	return marshalMessagesToCSV(messages)
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(allSlackMessages))
+	ch.emitEmbeddings(ctx, "conversations_history", params.channel, allSlackMessages)
+	messages := ch.convertMessagesFromHistory(allSlackMessages, params.channel, params.activity, params.unfurls, params.tombstones, params.render, params.loc)
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	if messages, err = detectLanguages(request, messages); err != nil {
+		return nil, err
+	}
+	return MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
 }
 
 // ConversationsRepliesHandler streams thread replies as CSV
@@ -549,14 +1154,24 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 		return nil, errors.New("thread_ts must be a string")
 	}
 
+	if request.GetBool("fetch_all", false) {
+		return ch.fetchAllReplies(ctx, request, params, threadTs)
+	}
+
+	cursor, err := decodeCursor("conversations_replies", cursorParams(params.channel, threadTs), params.cursor)
+	if err != nil {
+		return nil, err
+	}
 	repliesParams := slack.GetConversationRepliesParameters{
 		ChannelID: params.channel,
 		Timestamp: threadTs,
 		Limit:     params.limit,
 		Oldest:    params.oldest,
 		Latest:    params.latest,
-		Cursor:    params.cursor,
+		Cursor:    cursor,
 		Inclusive: false,
+
+		IncludeAllMetadata: true,
 	}
 
 	var allReplies []slack.Message
@@ -575,13 +1190,203 @@ func (ch *ConversationsHandler) ConversationsRepliesHandler(ctx context.Context,
 	}
 
 	ch.logger.Debug("Fetched all conversation replies", zap.Int("total_count", len(allReplies)))
-	messages := ch.convertMessagesFromHistory(allReplies, params.channel, params.activity)
-	return marshalMessagesToCSV(messages)
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(allReplies))
+	ch.emitEmbeddings(ctx, "conversations_replies", params.channel, allReplies)
+	messages := ch.convertMessagesFromHistory(allReplies, params.channel, params.activity, params.unfurls, false, params.render, params.loc)
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	if messages, err = detectLanguages(request, messages); err != nil {
+		return nil, err
+	}
+	return MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+}
+
+// fetchAllReplies pages through the complete thread regardless of limit and
+// cursor, deduplicates the parent message Slack repeats on every page, and
+// returns the replies in chronological order followed by thread metadata.
+func (ch *ConversationsHandler) fetchAllReplies(ctx context.Context, request mcp.CallToolRequest, params *conversationParams, threadTs string) (*mcp.CallToolResult, error) {
+	allReplies, err := ch.fetchThread(ctx, params.channel, threadTs, newProgressReporter(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(allReplies))
+	ch.emitEmbeddings(ctx, "conversations_replies", params.channel, allReplies)
+	messages := ch.convertMessagesFromHistory(allReplies, params.channel, params.activity, params.unfurls, false, params.render, params.loc)
+	if messages, err = classifyMessages(ctx, "conversations_replies", messages); err != nil {
+		return nil, err
+	}
+	if messages, err = detectLanguages(request, messages); err != nil {
+		return nil, err
+	}
+	res, err := MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+
+	meta := ch.buildThreadMetadata(params.channel, threadTs, allReplies)
+	meta.Returned = len(messages)
+	return withJSONMetadata(res, meta)
+}
+
+// fetchThread returns the parent message and every reply of a thread in
+// chronological order, reporting the messages fetched out of the reply count
+// to progress.
+func (ch *ConversationsHandler) fetchThread(ctx context.Context, channel, threadTs string, progress *progressReporter) ([]slack.Message, error) {
+	repliesParams := slack.GetConversationRepliesParameters{
+		ChannelID: channel,
+		Timestamp: threadTs,
+		Limit:     fetchAllRepliesPageSize,
+
+		IncludeAllMetadata: true,
+	}
+
+	seen := make(map[string]struct{})
+	var (
+		allReplies []slack.Message
+		total      float64
+	)
+	for {
+		if err := ch.repliesLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		replies, hasMore, nextCursor, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &repliesParams)
+		if err != nil {
+			ch.logger.Error("GetConversationRepliesContext failed", zap.Error(err))
+			return nil, err
+		}
+		for _, r := range replies {
+			if _, ok := seen[r.Timestamp]; ok {
+				continue
+			}
+			seen[r.Timestamp] = struct{}{}
+			allReplies = append(allReplies, r)
+		}
+		ch.logger.Debug("Fetched thread page", zap.Int("count", len(replies)), zap.Int("total", len(allReplies)))
+		if total == 0 && len(replies) > 0 {
+			// The parent, repeated on every page, carries the reply count.
+			total = float64(replies[0].ReplyCount + 1)
+		}
+		progress.Report(float64(len(allReplies)), max(total, float64(len(allReplies))), fmt.Sprintf("fetched %d messages of the thread", len(allReplies)))
+		if !hasMore || nextCursor == "" {
+			break
+		}
+		repliesParams.Cursor = nextCursor
+	}
+
+	sort.SliceStable(allReplies, func(i, j int) bool {
+		return slackTsLess(allReplies[i].Timestamp, allReplies[j].Timestamp)
+	})
+	return allReplies, nil
+}
+
+// ThreadParticipant is a user who posted in a thread.
+type ThreadParticipant struct {
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name,omitempty"`
+	Messages int    `json:"messages"`
+}
+
+// ThreadMetadata describes the parent message of a thread fetched with fetch_all.
+type ThreadMetadata struct {
+	ChannelID       string              `json:"channel_id"`
+	ThreadTs        string              `json:"thread_ts"`
+	ParentUserID    string              `json:"parent_user_id,omitempty"`
+	ParentUserName  string              `json:"parent_user_name,omitempty"`
+	ReplyCount      int                 `json:"reply_count"`
+	ReplyUsersCount int                 `json:"reply_users_count"`
+	LatestReply     string              `json:"latest_reply,omitempty"`
+	Reactions       map[string]int      `json:"reactions,omitempty"`
+	ReactionTotal   int                 `json:"reaction_total"`
+	Participants    []ThreadParticipant `json:"participants"`
+	Returned        int                 `json:"returned"`
+}
+
+func (ch *ConversationsHandler) buildThreadMetadata(channel, threadTs string, msgs []slack.Message) ThreadMetadata {
+	users := ch.apiProvider.ProvideUsersMap().Users
+	meta := ThreadMetadata{
+		ChannelID:    channel,
+		ThreadTs:     threadTs,
+		Participants: []ThreadParticipant{},
+	}
+
+	index := make(map[string]int)
+	for _, m := range msgs {
+		if m.Timestamp == threadTs {
+			meta.ParentUserID = m.User
+			meta.ReplyCount = m.ReplyCount
+			meta.ReplyUsersCount = len(m.ReplyUsers)
+			meta.LatestReply = m.LatestReply
+			for _, r := range m.Reactions {
+				if meta.Reactions == nil {
+					meta.Reactions = make(map[string]int)
+				}
+				meta.Reactions[r.Name] = r.Count
+				meta.ReactionTotal += r.Count
+			}
+		}
+
+		if m.User == "" {
+			continue
+		}
+		if i, ok := index[m.User]; ok {
+			meta.Participants[i].Messages++
+			continue
+		}
+		userName, _, _ := getUserInfo(m.User, users)
+		index[m.User] = len(meta.Participants)
+		meta.Participants = append(meta.Participants, ThreadParticipant{
+			UserID:   m.User,
+			UserName: userName,
+			Messages: 1,
+		})
+	}
+	if meta.ParentUserID != "" {
+		meta.ParentUserName, _, _ = getUserInfo(meta.ParentUserID, users)
+	}
+	return meta
+}
+
+// slackTsLess orders Slack message timestamps ("1700000000.123456") chronologically.
+func slackTsLess(a, b string) bool {
+	aSec, aMicro, okA := splitSlackTs(a)
+	bSec, bMicro, okB := splitSlackTs(b)
+	if !okA || !okB {
+		return a < b
+	}
+	if aSec != bSec {
+		return aSec < bSec
+	}
+	return aMicro < bMicro
+}
+
+// splitSlackTs returns the seconds and microseconds of a Slack timestamp as
+// integers, so that comparing them never loses precision.
+func splitSlackTs(ts string) (sec, micro int64, ok bool) {
+	secPart, fracPart, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil || len(fracPart) > 6 {
+		return 0, 0, false
+	}
+	if fracPart == "" {
+		return sec, 0, true
+	}
+	micro, err = strconv.ParseInt(fracPart+strings.Repeat("0", 6-len(fracPart)), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return sec, micro, true
 }
 
 func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	ch.logger.Debug("ConversationsSearchHandler called", zap.Any("params", request.Params))
 
+	request, err := ch.applySearchTemplate(request)
+	if err != nil {
+		ch.logger.Error("Invalid search template", zap.Error(err))
+		return nil, err
+	}
 	params, err := ch.parseParamsToolSearch(request)
 	if err != nil {
 		ch.logger.Error("Failed to parse search params", zap.Error(err))
@@ -590,35 +1395,98 @@ func (ch *ConversationsHandler) ConversationsSearchHandler(ctx context.Context,
 	ch.logger.Debug("Search params parsed", zap.String("query", params.query), zap.Int("limit", params.limit), zap.Int("page", params.page))
 
 	searchParams := slack.SearchParameters{
-		Sort:          slack.DEFAULT_SEARCH_SORT,
-		SortDirection: slack.DEFAULT_SEARCH_SORT_DIR,
+		Sort:          params.sort,
+		SortDirection: params.sortDir,
 		Highlight:     false,
 		Count:         params.limit,
 		Page:          params.page,
 	}
 
-	var allMatches []slack.SearchMessage
-	for {
-		messagesRes, _, err := ch.apiProvider.Slack().SearchContext(ctx, params.query, searchParams)
-		if err != nil {
-			ch.logger.Error("Slack SearchContext failed", zap.Error(err))
-			return nil, err
+	messagesRes, _, err := ch.apiProvider.Slack().SearchContext(ctx, params.query, searchParams)
+	if err != nil {
+		ch.logger.Error("Slack SearchContext failed", zap.Error(err))
+		return nil, err
+	}
+	ch.logger.Debug("Search completed",
+		zap.Int("matches", len(messagesRes.Matches)),
+		zap.Int("total", messagesRes.Pagination.TotalCount),
+		zap.Int("page", messagesRes.Pagination.Page),
+		zap.Int("page_count", messagesRes.Pagination.PageCount),
+	)
+
+	matches := messagesRes.Matches
+	filtered := 0
+	if params.postFilter != nil {
+		matches = postFilterSearch(matches, params.postFilter)
+		filtered = len(messagesRes.Matches) - len(matches)
+	}
+
+	ch.apiProvider.EnsureUsers(ctx, searchUserIDs(matches))
+	messages := ch.convertMessagesFromSearch(matches, params.unfurls, params.render, params.loc)
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	detected := len(messages)
+	if messages, err = detectLanguages(request, messages); err != nil {
+		return nil, err
+	}
+	meta := newSearchMetadata(params, messagesRes.Pagination, len(messages))
+	meta.PostFiltered = filtered
+	meta.LanguageFiltered = detected - len(messages)
+	if meta.HasMore && len(messages) > 0 {
+		messages[len(messages)-1].Cursor = meta.NextCursor
+	}
+
+	res, err := MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	return withJSONMetadata(res, meta)
+}
+
+// postFilterSearch keeps the matches whose text or attachments re matches,
+// in Slack's markup as search returns it. Slack search matches words only,
+// so exact patterns such as error codes are checked here.
+func postFilterSearch(matches []slack.SearchMessage, re *regexp.Regexp) []slack.SearchMessage {
+	kept := make([]slack.SearchMessage, 0, len(matches))
+	for _, m := range matches {
+		parts := []string{m.Text}
+		for _, a := range m.Attachments {
+			parts = append(parts, a.Pretext, a.Title, a.Text, a.Fallback)
 		}
-		ch.logger.Debug("Search page completed",
-			zap.Int("matches", len(messagesRes.Matches)),
-			zap.Int("page", messagesRes.Pagination.Page),
-			zap.Int("page_count", messagesRes.Pagination.PageCount),
-		)
-		allMatches = append(allMatches, messagesRes.Matches...)
-		if messagesRes.Pagination.Page >= messagesRes.Pagination.PageCount {
-			break
+		if slices.ContainsFunc(parts, re.MatchString) {
+			kept = append(kept, m)
 		}
-		searchParams.Page = messagesRes.Pagination.Page + 1
 	}
+	return kept
+}
+
+// searchCursorParams ties a search cursor to the final query and its order;
+// limit may change between pages.
+func searchCursorParams(query, sort, sortDir string) string {
+	return cursorParams(query, sort, sortDir)
+}
 
-	ch.logger.Debug("Search completed", zap.Int("total_matches", len(allMatches)))
-	messages := ch.convertMessagesFromSearch(allMatches)
-	return marshalMessagesToCSV(messages)
+// newSearchMetadata describes one page of search results. Past
+// searchNarrowHintPages pages it adds a hint to narrow the query, which is
+// usually cheaper than paging through every match.
+func newSearchMetadata(params *searchParams, p slack.Pagination, returned int) SearchMetadata {
+	meta := SearchMetadata{
+		Query:     params.query,
+		Total:     p.TotalCount,
+		Page:      p.Page,
+		PageCount: p.PageCount,
+		PerPage:   p.PerPage,
+		Returned:  returned,
+		HasMore:   p.Page < p.PageCount,
+	}
+	if meta.HasMore {
+		meta.NextCursor = encodeCursor("conversations_search_messages", searchCursorParams(params.query, params.sort, params.sortDir), strconv.Itoa(p.Page+1))
+	}
+	if remaining := p.PageCount - p.Page; remaining > searchNarrowHintPages {
+		meta.Hint = fmt.Sprintf("%d matches, %d more pages; consider narrowing the query with filter_in_channel, filter_users_from or a date filter instead of paging through all of them", p.TotalCount, remaining)
+	}
+	return meta
 }
 
 func isChannelAllowedForConfig(channel, config string) bool {
@@ -642,10 +1510,6 @@ func isChannelAllowedForConfig(channel, config string) bool {
 	return isNegated
 }
 
-func isChannelAllowed(channel string) bool {
-	return isChannelAllowedForConfig(channel, os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL"))
-}
-
 func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel string) (string, error) {
 	if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
 		return channel, nil
@@ -676,7 +1540,7 @@ func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel st
 	if wasRateLimited {
 		ch.logger.Warn("Channel not found; cache refresh was rate-limited",
 			zap.String("channel", channel))
-		return "", fmt.Errorf("channel %q not found (cache refresh was rate-limited, try again later)", channel)
+		return "", ch.channelNotFound(ctx, channel, "cache refresh was rate-limited, try again later")
 	}
 
 	// Second attempt after successful refresh
@@ -685,7 +1549,7 @@ func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel st
 	if !ok {
 		ch.logger.Error("Channel not found even after cache refresh",
 			zap.String("channel", channel))
-		return "", fmt.Errorf("channel %q not found", channel)
+		return "", ch.channelNotFound(ctx, channel, "")
 	}
 
 	ch.logger.Debug("Channel found after cache refresh",
@@ -695,17 +1559,24 @@ func (ch *ConversationsHandler) resolveChannelID(ctx context.Context, channel st
 	return channelsMaps.Channels[chn].ID, nil
 }
 
-func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack.Message, channel string, includeActivity bool) []Message {
-	usersMap := ch.apiProvider.ProvideUsersMap()
+func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack.Message, channel string, includeActivity, includeUnfurls, includeTombstones bool, render string, loc *time.Location) []Message {
+	var users map[string]slack.User
+	if usersMap := ch.apiProvider.ProvideUsersMap(); usersMap != nil {
+		users = usersMap.Users
+	}
 	var messages []Message
 	warn := false
 
 	for _, msg := range slackMessages {
-		if (msg.SubType != "" && msg.SubType != "bot_message" && msg.SubType != "thread_broadcast") && !includeActivity {
+		var tombstone, tombstoneTs string
+		if includeTombstones {
+			msg, tombstone, tombstoneTs = asTombstone(msg)
+		}
+		if tombstone == "" && (msg.SubType != "" && msg.SubType != "bot_message" && msg.SubType != "thread_broadcast") && !includeActivity {
 			continue
 		}
 
-		userName, realName, ok := getUserInfo(msg.User, usersMap.Users)
+		userName, realName, ok := getUserInfo(msg.User, users)
 
 		if !ok && msg.SubType == "bot_message" {
 			userName, realName, ok = getBotInfo(msg.Username)
@@ -715,7 +1586,7 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 			warn = true
 		}
 
-		timestamp, err := text.TimestampToIsoRFC3339(msg.Timestamp)
+		timestamp, timeHuman, err := text.FormatSlackTimestamp(msg.Timestamp, loc)
 		if err != nil {
 			ch.logger.Error("Failed to convert timestamp to RFC3339", zap.Error(err))
 			continue
@@ -728,6 +1599,7 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 			reactionParts = append(reactionParts, fmt.Sprintf("%s:%d", r.Name, r.Count))
 		}
 		reactionsString := strings.Join(reactionParts, "|")
+		reactionCounts := strings.Join(reactionParts, ",")
 
 		botName := ""
 		if msg.BotProfile != nil && msg.BotProfile.Name != "" {
@@ -743,21 +1615,34 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 		}
 		attachmentIDsStr := strings.Join(attachmentIDs, ",")
 
-		messages = append(messages, Message{
+		m := Message{
 			MsgID:     msg.Timestamp,
 			UserID:    msg.User,
 			UserName:  userName,
 			RealName:  realName,
-			Text:      text.ProcessText(msgText),
+			Text:      renderMessageText(ch.apiProvider, msgText, render),
 			Channel:   channel,
 			ThreadTs:  msg.ThreadTimestamp,
 			Time:      timestamp,
+			TimeHuman: timeHuman,
 			Reactions: reactionsString,
+			ReactionCounts: reactionCounts,
+			ReplyCount: msg.ReplyCount,
+			IsEdited:  msg.Edited != nil,
 			BotName:   botName,
 			FileCount: fileCount,
 			AttachmentIDs:   attachmentIDsStr,
 			HasMedia:  hasMedia,
-		})
+			Metadata:  formatMessageMetadata(msg.Metadata),
+			Tombstone: tombstone,
+		}
+		if tombstoneTs != "" {
+			m.TombstoneTime, _, _ = text.FormatSlackTimestamp(tombstoneTs, loc)
+		}
+		if includeUnfurls {
+			setUnfurls(&m, msg.Attachments)
+		}
+		messages = append(messages, m)
 	}
 
 	if ready, err := ch.apiProvider.IsReady(); !ready {
@@ -771,12 +1656,49 @@ func (ch *ConversationsHandler) convertMessagesFromHistory(slackMessages []slack
 	return messages
 }
 
-func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.SearchMessage) []Message {
+// asTombstone turns the edit and deletion records Slack keeps in history into
+// the message they are about, and reports which change happened and its
+// timestamp. Slack does not keep the text a message had before an edit, and
+// a deleted parent with replies stays as a "tombstone" without its text or
+// time of deletion.
+func asTombstone(msg slack.Message) (slack.Message, string, string) {
+	switch msg.SubType {
+	case "message_changed":
+		if msg.SubMessage == nil {
+			return msg, "", ""
+		}
+		changed := slack.Message{Msg: *msg.SubMessage}
+		if changed.Edited != nil && changed.Edited.Timestamp != "" {
+			return changed, "edited", changed.Edited.Timestamp
+		}
+		return changed, "edited", msg.Timestamp
+	case "message_deleted":
+		var deleted slack.Message
+		if msg.PreviousMessage != nil {
+			deleted.Msg = *msg.PreviousMessage
+		}
+		deleted.Timestamp = msg.DeletedTimestamp
+		return deleted, "deleted", msg.Timestamp
+	case "tombstone":
+		msg.Text = ""
+		return msg, "deleted", ""
+	}
+	if msg.Edited != nil {
+		return msg, "edited", msg.Edited.Timestamp
+	}
+	return msg, "", ""
+}
+
+func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.SearchMessage, includeUnfurls bool, render string, loc *time.Location) []Message {
 	usersMap := ch.apiProvider.ProvideUsersMap()
 	var messages []Message
 	warn := false
 
 	for _, msg := range slackMessages {
+		if !ch.apiProvider.ChannelAllowed(msg.Channel.ID) {
+			continue
+		}
+
 		userName, realName, ok := getUserInfo(msg.User, usersMap.Users)
 
 		if !ok && msg.User == "" && msg.Username != "" {
@@ -787,7 +1709,7 @@ func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.
 
 		threadTs, _ := extractThreadTS(msg.Permalink)
 
-		timestamp, err := text.TimestampToIsoRFC3339(msg.Timestamp)
+		timestamp, timeHuman, err := text.FormatSlackTimestamp(msg.Timestamp, loc)
 		if err != nil {
 			ch.logger.Error("Failed to convert timestamp to RFC3339", zap.Error(err))
 			continue
@@ -797,18 +1719,23 @@ func (ch *ConversationsHandler) convertMessagesFromSearch(slackMessages []slack.
 
 		hasMedia := hasImageBlocks(msg.Blocks)
 
-		messages = append(messages, Message{
+		m := Message{
 			MsgID:     msg.Timestamp,
 			UserID:    msg.User,
 			UserName:  userName,
 			RealName:  realName,
-			Text:      text.ProcessText(msgText),
+			Text:      renderMessageText(ch.apiProvider, msgText, render),
 			Channel:   fmt.Sprintf("#%s", msg.Channel.Name),
 			ThreadTs:  threadTs,
 			Time:      timestamp,
+			TimeHuman: timeHuman,
 			Reactions: "",
 			HasMedia:  hasMedia,
-		})
+		}
+		if includeUnfurls {
+			setUnfurls(&m, msg.Attachments)
+		}
+		messages = append(messages, m)
 	}
 
 	if ready, err := ch.apiProvider.IsReady(); !ready {
@@ -832,20 +1759,53 @@ func (ch *ConversationsHandler) parseParamsToolConversations(ctx context.Context
 	limit := request.GetString("limit", "")
 	cursor := request.GetString("cursor", "")
 	activity := request.GetBool("include_activity_messages", false)
+	if _, err := parseLanguageFilter(request); err != nil {
+		ch.logger.Error("Invalid filter_language", zap.Error(err))
+		return nil, err
+	}
+	render, err := parseRenderParam(request)
+	if err != nil {
+		ch.logger.Error("Invalid render option", zap.Error(err))
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		ch.logger.Error("Invalid tz option", zap.Error(err))
+		return nil, err
+	}
 
 	var (
 		paramLimit  int
 		paramOldest string
 		paramLatest string
-		err         error
 	)
-	if strings.HasSuffix(limit, "d") || strings.HasSuffix(limit, "w") || strings.HasSuffix(limit, "m") {
+	oldest := strings.TrimSpace(request.GetString("oldest", ""))
+	latest := strings.TrimSpace(request.GetString("latest", ""))
+	explicitWindow := oldest != "" || latest != ""
+	if explicitWindow {
+		if paramOldest, err = parseTimeBound(oldest, loc, false); err != nil {
+			return nil, fmt.Errorf("invalid oldest: %w", err)
+		}
+		if paramLatest, err = parseTimeBound(latest, loc, true); err != nil {
+			return nil, fmt.Errorf("invalid latest: %w", err)
+		}
+		if paramOldest != "" && paramLatest != "" && !slackTsLess(paramOldest, paramLatest) {
+			return nil, fmt.Errorf("oldest %q must be before latest %q", oldest, latest)
+		}
+	}
+
+	durationLimit := strings.HasSuffix(limit, "d") || strings.HasSuffix(limit, "w") || strings.HasSuffix(limit, "m")
+	if durationLimit && !explicitWindow {
 		paramLimit, paramOldest, paramLatest, err = limitByExpression(limit, defaultConversationsExpressionLimit)
 		if err != nil {
 			ch.logger.Error("Invalid duration limit", zap.String("limit", limit), zap.Error(err))
 			return nil, err
 		}
 	} else if cursor == "" {
+		if durationLimit {
+			// oldest and latest replace a duration limit.
+			limit = ""
+		}
 		paramLimit, err = limitByNumeric(limit, defaultConversationsNumericLimit)
 		if err != nil {
 			ch.logger.Error("Invalid numeric limit", zap.String("limit", limit), zap.Error(err))
@@ -876,25 +1836,47 @@ func (ch *ConversationsHandler) parseParamsToolConversations(ctx context.Context
 		}
 		channel = resolvedChannel
 	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
 
 	return &conversationParams{
-		channel:  channel,
-		limit:    paramLimit,
-		oldest:   paramOldest,
-		latest:   paramLatest,
-		cursor:   cursor,
-		activity: activity,
+		channel:   channel,
+		limit:     paramLimit,
+		oldest:    paramOldest,
+		latest:    paramLatest,
+		inclusive: explicitWindow,
+		cursor:    cursor,
+		activity:  activity,
+		unfurls:   request.GetBool("include_unfurls", false),
+		tombstones: request.GetBool("include_tombstones", false),
+		render:    render,
+		loc:       loc,
 	}, nil
 }
 
-func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, request mcp.CallToolRequest) (*addMessageParams, error) {
-	toolConfig := os.Getenv("SLACK_MCP_ADD_MESSAGE_TOOL")
-	enabledTools := os.Getenv("SLACK_MCP_ENABLED_TOOLS")
+// toolExplicitlyEnabled reports whether any of tools was enabled by name,
+// through --enabled-tools, SLACK_MCP_ENABLED_TOOLS or the tools config.
+func (ch *ConversationsHandler) toolExplicitlyEnabled(tools ...string) bool {
+	enabled := ch.apiProvider.Config().EnabledTools
+	for _, tool := range tools {
+		if slices.Contains(enabled, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// addMessageToolPolicy returns the SLACK_MCP_ADD_MESSAGE_TOOL channel policy,
+// or an error when posting messages is not enabled.
+func (ch *ConversationsHandler) addMessageToolPolicy() (string, error) {
+	toolConfig := ch.apiProvider.Config().AddMessageTool
 
 	if toolConfig == "" {
-		if !strings.Contains(enabledTools, "conversations_add_message") {
+		if !ch.toolExplicitlyEnabled("conversations_add_message", "conversations_add_messages", "conversations_open_dm") {
 			ch.logger.Error("Add-message tool disabled by default")
-			return nil, errors.New(
+			return "", errors.New(
 				"by default, the conversations_add_message tool is disabled to guard Slack workspaces against accidental spamming. " +
 					"To enable it, set the SLACK_MCP_ADD_MESSAGE_TOOL environment variable to true, 1, or comma separated list of channels " +
 					"to limit where the MCP can post messages, e.g. 'SLACK_MCP_ADD_MESSAGE_TOOL=C1234567890,D0987654321', 'SLACK_MCP_ADD_MESSAGE_TOOL=!C1234567890' " +
@@ -904,19 +1886,61 @@ func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, r
 		toolConfig = "true"
 	}
 
+	return toolConfig, nil
+}
+
+// checkAddMessageTarget is the pre-flight check shared by the add-message
+// tools: the channel policy, SLACK_MCP_ADD_MESSAGE_TOOL and the DM allowlist
+// must all accept the resolved channel.
+func (ch *ConversationsHandler) checkAddMessageTarget(ctx context.Context, channel, toolConfig string) error {
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return err
+	}
+	if !isChannelAllowedForConfig(channel, ch.apiProvider.Config().AddMessageTool) {
+		ch.logger.Warn("Add-message tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return fmt.Errorf("conversations_add_message tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+	if err := ch.checkDMPolicy(ctx, channel); err != nil {
+		ch.logger.Warn("Add-message tool blocked by DM allowlist", zap.String("channel", channel), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CheckAddMessage runs the checks conversations_add_message makes before
+// posting, without posting. With an empty channel only the tool policy is
+// checked.
+func (ch *ConversationsHandler) CheckAddMessage(ctx context.Context, channel string) error {
+	toolConfig, err := ch.addMessageToolPolicy()
+	if err != nil || channel == "" {
+		return err
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		return err
+	}
+	return ch.checkAddMessageTarget(ctx, channel, toolConfig)
+}
+
+func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, request mcp.CallToolRequest) (*addMessageParams, error) {
+	toolConfig, err := ch.addMessageToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
 	channel := request.GetString("channel_id", "")
 	if channel == "" {
 		ch.logger.Error("channel_id missing in add-message params")
 		return nil, errors.New("channel_id must be a string")
 	}
-	channel, err := ch.resolveChannelID(ctx, channel)
+	channel, err = ch.resolveChannelID(ctx, channel)
 	if err != nil {
 		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
 		return nil, err
 	}
-	if !isChannelAllowed(channel) {
-		ch.logger.Warn("Add-message tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
-		return nil, fmt.Errorf("conversations_add_message tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	if err := ch.checkAddMessageTarget(ctx, channel, toolConfig); err != nil {
+		return nil, err
 	}
 
 	threadTs := request.GetString("thread_ts", "")
@@ -930,10 +1954,21 @@ func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, r
 		// Backward compatibility with "payload" parameter
 		msgText = request.GetString("payload", "")
 	}
-	if msgText == "" {
+	attached, err := ch.parseMessageAttachments(ctx, request.GetString("attach", ""))
+	if err != nil {
+		ch.logger.Error("Invalid attachments", zap.Error(err))
+		return nil, err
+	}
+	if msgText == "" && len(attached) == 0 {
 		ch.logger.Error("Message text missing")
 		return nil, errors.New("text must be a string")
 	}
+	if request.GetBool("resolve_mentions", true) {
+		msgText = text.ResolveMentions(msgText, newMentionResolver(ch.apiProvider))
+	}
+	if msgText, err = withAttachments(msgText, attached, ch.apiProvider.Config().AddMessageUnfurling); err != nil {
+		return nil, err
+	}
 
 	contentType := request.GetString("content_type", "text/markdown")
 	if contentType != "text/plain" && contentType != "text/markdown" {
@@ -941,22 +1976,101 @@ func (ch *ConversationsHandler) parseParamsToolAddMessage(ctx context.Context, r
 		return nil, errors.New("content_type must be either 'text/plain' or 'text/markdown'")
 	}
 
+	identity, err := ch.parseBotIdentity(request)
+	if err != nil {
+		ch.logger.Error("Invalid bot identity", zap.Error(err))
+		return nil, err
+	}
+
+	metadata, err := parseMessageMetadata(request)
+	if err != nil {
+		ch.logger.Error("Invalid message metadata", zap.Error(err))
+		return nil, err
+	}
+
+	broadcast := request.GetBool("reply_broadcast", false)
+	if broadcast && threadTs == "" {
+		return nil, errors.New("reply_broadcast requires thread_ts")
+	}
+
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	postAt, err := parsePostAt(request.GetString("post_at", ""), loc, time.Now())
+	if err != nil {
+		ch.logger.Error("Invalid post_at", zap.Error(err))
+		return nil, err
+	}
+
 	return &addMessageParams{
 		channel:     channel,
 		threadTs:    threadTs,
 		text:        msgText,
 		contentType: contentType,
+		identity:    identity,
+		metadata:    metadata,
+		broadcast:   broadcast,
+		postAt:      postAt,
+		attached:    attached,
 	}, nil
 }
 
-func (ch *ConversationsHandler) parseParamsToolReaction(ctx context.Context, request mcp.CallToolRequest) (*addReactionParams, error) {
-	toolConfig := os.Getenv("SLACK_MCP_REACTION_TOOL")
-	enabledTools := os.Getenv("SLACK_MCP_ENABLED_TOOLS")
+// parseBotIdentity reads the username, icon_emoji and icon_url overrides.
+// They are only accepted with a bot token and SLACK_MCP_ADD_MESSAGE_IDENTITY.
+func (ch *ConversationsHandler) parseBotIdentity(request mcp.CallToolRequest) (botIdentity, error) {
+	identity := botIdentity{
+		username:  strings.TrimSpace(request.GetString("username", "")),
+		iconEmoji: strings.Trim(strings.TrimSpace(request.GetString("icon_emoji", "")), ":"),
+		iconURL:   strings.TrimSpace(request.GetString("icon_url", "")),
+	}
+	if identity == (botIdentity{}) {
+		return identity, nil
+	}
+
+	if v := ch.apiProvider.Config().AddMessageIdentity; v != "1" && v != "true" && v != "yes" {
+		return botIdentity{}, errors.New(
+			"username, icon_emoji and icon_url are disabled by default. " +
+				"To post as a custom bot identity, set the SLACK_MCP_ADD_MESSAGE_IDENTITY environment variable to true",
+		)
+	}
+	if !ch.apiProvider.IsBotToken() {
+		return botIdentity{}, errors.New("username, icon_emoji and icon_url can only be used with a bot token (xoxb)")
+	}
+	if err := validateBotIdentity(identity); err != nil {
+		return botIdentity{}, err
+	}
+	return identity, nil
+}
+
+func validateBotIdentity(identity botIdentity) error {
+	if utf8.RuneCountInString(identity.username) > maxBotUsernameLength {
+		return fmt.Errorf("username must be at most %d characters", maxBotUsernameLength)
+	}
+	if identity.iconEmoji != "" && identity.iconURL != "" {
+		return errors.New("icon_emoji and icon_url cannot be used together")
+	}
+	if identity.iconEmoji != "" && strings.ContainsAny(identity.iconEmoji, " :") {
+		return fmt.Errorf("icon_emoji %q must be a single emoji name such as 'robot_face'", identity.iconEmoji)
+	}
+	if identity.iconURL != "" {
+		u, err := url.Parse(identity.iconURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("icon_url %q must be an absolute http or https URL", identity.iconURL)
+		}
+	}
+	return nil
+}
+
+// reactionToolPolicy returns the SLACK_MCP_REACTION_TOOL channel policy, or
+// an error when the reactions tools are not enabled.
+func (ch *ConversationsHandler) reactionToolPolicy() (string, error) {
+	toolConfig := ch.apiProvider.Config().ReactionTool
 
 	if toolConfig == "" {
-		if !strings.Contains(enabledTools, "reactions_add") && !strings.Contains(enabledTools, "reactions_remove") {
+		if !ch.toolExplicitlyEnabled("reactions_add", "reactions_remove", "reactions_add_bulk") {
 			ch.logger.Error("Reactions tool disabled by default")
-			return nil, errors.New(
+			return "", errors.New(
 				"by default, the reactions tools are disabled to guard Slack workspaces against accidental spamming. " +
 					"To enable them, set the SLACK_MCP_REACTION_TOOL environment variable to true, 1, or comma separated list of channels " +
 					"to limit where the MCP can manage reactions, e.g. 'SLACK_MCP_REACTION_TOOL=C1234567890,D0987654321', 'SLACK_MCP_REACTION_TOOL=!C1234567890' " +
@@ -965,16 +2079,28 @@ func (ch *ConversationsHandler) parseParamsToolReaction(ctx context.Context, req
 		}
 		toolConfig = "true"
 	}
+	return toolConfig, nil
+}
+
+func (ch *ConversationsHandler) parseParamsToolReaction(ctx context.Context, request mcp.CallToolRequest) (*addReactionParams, error) {
+	toolConfig, err := ch.reactionToolPolicy()
+	if err != nil {
+		return nil, err
+	}
 
 	channel := request.GetString("channel_id", "")
 	if channel == "" {
 		return nil, errors.New("channel_id is required")
 	}
-	channel, err := ch.resolveChannelID(ctx, channel)
+	channel, err = ch.resolveChannelID(ctx, channel)
 	if err != nil {
 		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
 		return nil, err
 	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
 	if !isChannelAllowedForConfig(channel, toolConfig) {
 		ch.logger.Warn("Reactions tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
 		return nil, fmt.Errorf("reactions tools are not allowed for channel %q, applied policy: %s", channel, toolConfig)
@@ -998,11 +2124,10 @@ func (ch *ConversationsHandler) parseParamsToolReaction(ctx context.Context, req
 }
 
 func (ch *ConversationsHandler) parseParamsToolFilesGet(request mcp.CallToolRequest) (*filesGetParams, error) {
-	toolConfig := os.Getenv("SLACK_MCP_ATTACHMENT_TOOL")
-	enabledTools := os.Getenv("SLACK_MCP_ENABLED_TOOLS")
+	toolConfig := ch.apiProvider.Config().AttachmentTool
 
 	if toolConfig == "" {
-		if !strings.Contains(enabledTools, "attachment_get_data") {
+		if !ch.toolExplicitlyEnabled("attachment_get_data") {
 			ch.logger.Error("Attachment tool disabled by default")
 			return nil, errors.New(
 				"by default, the attachment_get_data tool is disabled. " +
@@ -1021,8 +2146,17 @@ func (ch *ConversationsHandler) parseParamsToolFilesGet(request mcp.CallToolRequ
 		return nil, errors.New("file_id is required")
 	}
 
+	var previewMaxDim int
+	if request.GetBool("preview", false) {
+		previewMaxDim = request.GetInt("preview_max_dimension", defaultPreviewMaxDimension)
+		if previewMaxDim < minPreviewMaxDimension || previewMaxDim > maxPreviewMaxDimension {
+			return nil, fmt.Errorf("preview_max_dimension must be between %d and %d", minPreviewMaxDimension, maxPreviewMaxDimension)
+		}
+	}
+
 	return &filesGetParams{
-		fileID: fileID,
+		fileID:        fileID,
+		previewMaxDim: previewMaxDim,
 	}, nil
 }
 
@@ -1036,22 +2170,56 @@ func (ch *ConversationsHandler) parseParamsToolUsersSearch(request mcp.CallToolR
 	if limit <= 0 {
 		limit = 10
 	}
-	if limit > 100 {
-		limit = 100
+	if limit > maxUsersSearchLimit {
+		limit = maxUsersSearchLimit
+	}
+
+	availability, err := parseAvailabilityFilter(request.GetArguments())
+	if err != nil {
+		return nil, err
 	}
 
 	return &usersSearchParams{
-		query: query,
-		limit: limit,
+		query:           query,
+		limit:           limit,
+		workingHours:    request.GetBool("include_working_hours", true),
+		includeDeleted:  request.GetBool("include_deleted", false),
+		openDMs:         request.GetBool("include_dm_channel", false),
+		includePresence: request.GetBool("include_presence", false),
+		availability:    availability,
 	}, nil
 }
 
 func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (*searchParams, error) {
 	rawQuery := strings.TrimSpace(req.GetString("search_query", ""))
 	freeText, filters := splitQuery(rawQuery)
+	if err := ch.normalizeSearchFilters(filters, false); err != nil {
+		ch.logger.Error("Invalid search modifiers", zap.Error(err))
+		return nil, err
+	}
+
+	if rawModifiers := strings.TrimSpace(req.GetString("search_modifiers", "")); rawModifiers != "" {
+		invalid, modifierFilters := splitQuery(rawModifiers)
+		if len(invalid) > 0 {
+			ch.logger.Error("Invalid search modifiers", zap.Strings("invalid", invalid))
+			return nil, fmt.Errorf("invalid search modifier(s) %q: expected key:value pairs with key one of %s",
+				strings.Join(invalid, " "), strings.Join(filterKeysOrder, ", "))
+		}
+		if err := ch.normalizeSearchFilters(modifierFilters, true); err != nil {
+			ch.logger.Error("Invalid search modifiers", zap.Error(err))
+			return nil, err
+		}
+		for key, vals := range modifierFilters {
+			for _, val := range vals {
+				addFilter(filters, key, val)
+			}
+		}
+	}
 
-	if req.GetBool("filter_threads_only", false) {
-		addFilter(filters, "is", "thread")
+	for _, f := range searchFlagFilters {
+		if req.GetBool(f.param, false) {
+			addFilter(filters, f.key, f.value)
+		}
 	}
 	if chName := req.GetString("filter_in_channel", ""); chName != "" {
 		f, err := ch.paramFormatChannel(chName)
@@ -1099,32 +2267,65 @@ func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (
 		addFilter(filters, key, val)
 	}
 
+	sortBy, sortDir, err := parseSearchSort(req.GetString("sort", ""), req.GetString("sort_dir", ""))
+	if err != nil {
+		ch.logger.Error("Invalid sort option", zap.Error(err))
+		return nil, err
+	}
+	render, err := parseRenderParam(req)
+	if err != nil {
+		ch.logger.Error("Invalid render option", zap.Error(err))
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, req)
+	if err != nil {
+		ch.logger.Error("Invalid tz option", zap.Error(err))
+		return nil, err
+	}
+
+	if _, err := parseLanguageFilter(req); err != nil {
+		ch.logger.Error("Invalid filter_language", zap.Error(err))
+		return nil, err
+	}
+
+	var postFilter *regexp.Regexp
+	if raw := req.GetString("post_filter_regex", ""); raw != "" {
+		if postFilter, err = regexp.Compile(raw); err != nil {
+			ch.logger.Error("Invalid post_filter_regex", zap.String("post_filter_regex", raw), zap.Error(err))
+			return nil, fmt.Errorf("invalid post_filter_regex %q: %w", raw, err)
+		}
+	}
+
 	finalQuery := buildQuery(freeText, filters)
 	limit := req.GetInt("limit", 100)
 	cursor := req.GetString("cursor", "")
 
-	var (
-		page          int
-		decodedCursor []byte
-	)
+	page := 1
 	if cursor != "" {
-		decodedCursor, err = base64.StdEncoding.DecodeString(cursor)
+		inner, err := decodeCursor("conversations_search_messages", searchCursorParams(finalQuery, sortBy, sortDir), cursor)
 		if err != nil {
-			ch.logger.Error("Invalid cursor decoding", zap.String("cursor", cursor), zap.Error(err))
-			return nil, fmt.Errorf("invalid cursor: %v", err)
+			ch.logger.Error("Invalid cursor", zap.String("cursor", cursor), zap.Error(err))
+			return nil, err
 		}
-		parts := strings.Split(string(decodedCursor), ":")
-		if len(parts) != 2 {
-			ch.logger.Error("Invalid cursor format", zap.String("cursor", cursor))
-			return nil, fmt.Errorf("invalid cursor: %v", cursor)
+		if inner == cursor {
+			// The base64 page:N cursor of earlier versions.
+			decodedCursor, err := base64.StdEncoding.DecodeString(cursor)
+			if err != nil {
+				ch.logger.Error("Invalid cursor decoding", zap.String("cursor", cursor), zap.Error(err))
+				return nil, fmt.Errorf("invalid cursor: %v", err)
+			}
+			parts := strings.Split(string(decodedCursor), ":")
+			if len(parts) != 2 {
+				ch.logger.Error("Invalid cursor format", zap.String("cursor", cursor))
+				return nil, fmt.Errorf("invalid cursor: %v", cursor)
+			}
+			inner = parts[1]
 		}
-		page, err = strconv.Atoi(parts[1])
+		page, err = strconv.Atoi(inner)
 		if err != nil || page < 1 {
 			ch.logger.Error("Invalid cursor page", zap.String("cursor", cursor), zap.Error(err))
 			return nil, fmt.Errorf("invalid cursor page: %v", err)
 		}
-	} else {
-		page = 1
 	}
 
 	ch.logger.Debug("Search parameters built",
@@ -1133,9 +2334,15 @@ func (ch *ConversationsHandler) parseParamsToolSearch(req mcp.CallToolRequest) (
 		zap.Int("page", page),
 	)
 	return &searchParams{
-		query: finalQuery,
-		limit: limit,
-		page:  page,
+		query:      finalQuery,
+		limit:      limit,
+		page:       page,
+		sort:       sortBy,
+		sortDir:    sortDir,
+		unfurls:    req.GetBool("include_unfurls", false),
+		render:     render,
+		loc:        loc,
+		postFilter: postFilter,
 	}, nil
 }
 
@@ -1167,18 +2374,209 @@ func (ch *ConversationsHandler) paramFormatUser(raw string) (string, error) {
 	return fmt.Sprintf("<@%s>", uid), nil
 }
 
+// normalizeSearchFilters rewrites channel and user references in search modifiers
+// into the form Slack expects. Values that are not recognizable IDs or handles are
+// passed through unchanged. With strict, as for search_modifiers, invalid is:, has:
+// and date values are rejected; modifiers typed into search_query are passed to Slack
+// as they are when they cannot be normalized, as they always were, except that the
+// channel policy still applies.
+func (ch *ConversationsHandler) normalizeSearchFilters(filters map[string][]string, strict bool) error {
+	for key, vals := range filters {
+		normalized := make([]string, 0, len(vals))
+		for _, val := range vals {
+			if val == "" {
+				if !strict {
+					normalized = append(normalized, val)
+					continue
+				}
+				return fmt.Errorf("search modifier %q has an empty value", key+":")
+			}
+
+			var (
+				out string
+				err error
+			)
+			switch key {
+			case "is", "has":
+				out, err = validateSearchFilterValue(key, val)
+			case "in":
+				out, err = ch.normalizeInFilter(val)
+			case "from", "to", "with":
+				out, err = ch.normalizeUserFilter(val)
+			case "before", "after", "on", "during":
+				out, err = normalizeSearchDate(key, val)
+			default:
+				out = val
+			}
+			if err != nil {
+				var policyErr *provider.ChannelPolicyError
+				if strict || errors.As(err, &policyErr) {
+					return err
+				}
+				out = val
+			}
+
+			dup := false
+			for _, existing := range normalized {
+				if existing == out {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				normalized = append(normalized, out)
+			}
+		}
+		filters[key] = normalized
+	}
+	return nil
+}
+
+func (ch *ConversationsHandler) normalizeInFilter(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "<#"):
+		id := strings.TrimSuffix(strings.TrimPrefix(raw, "<#"), ">")
+		if i := strings.Index(id, "|"); i >= 0 {
+			id = id[:i]
+		}
+		return ch.paramFormatChannel(id)
+	case strings.HasPrefix(raw, "#"):
+		name, ok, err := ch.resolveInChannelName(raw)
+		if err == nil && !ok {
+			err = ch.apiProvider.ChannelNotFound(raw, "")
+		}
+		return name, err
+	case searchChannelIDRe.MatchString(raw):
+		return ch.paramFormatChannel(raw)
+	case strings.HasPrefix(raw, "@"), strings.HasPrefix(raw, "<@"):
+		return ch.normalizeUserFilter(raw)
+	}
+	// Slack also reads a bare name as a channel, so it gets the same checks;
+	// one the cache does not know is passed through unchanged.
+	name, ok, err := ch.resolveInChannelName("#" + raw)
+	if err == nil && !ok {
+		return raw, nil
+	}
+	return name, err
+}
+
+// resolveInChannelName resolves a "#name" of an in: filter through the
+// channels cache and applies the channel policy to it. A name the cache does
+// not know is checked against the policy by name alone, so a denied channel
+// cannot be searched before the cache has it; ok is false for those names.
+func (ch *ConversationsHandler) resolveInChannelName(name string) (string, bool, error) {
+	if cms := ch.apiProvider.ProvideChannelsMaps(); cms != nil {
+		if id, found := cms.ChannelsInv[name]; found {
+			if err := ch.apiProvider.CheckChannel(id); err != nil {
+				return "", false, err
+			}
+			return cms.Channels[id].Name, true, nil
+		}
+	}
+	if err := ch.apiProvider.ChannelPolicy().Check("", name); err != nil {
+		return "", false, err
+	}
+	return "", false, nil
+}
+
+func (ch *ConversationsHandler) normalizeUserFilter(raw string) (string, error) {
+	switch {
+	case strings.EqualFold(raw, "me"), strings.EqualFold(raw, "@me"):
+		return "me", nil
+	case strings.HasPrefix(raw, "<@"):
+		id := strings.TrimSuffix(strings.TrimPrefix(raw, "<@"), ">")
+		if i := strings.Index(id, "|"); i >= 0 {
+			id = id[:i]
+		}
+		return ch.paramFormatUser(id)
+	case strings.HasPrefix(raw, "@"), isSlackUserIDPrefix(raw):
+		return ch.paramFormatUser(raw)
+	}
+	return raw, nil
+}
+
+// normalizeSearchDate accepts everything parseFlexibleDate does plus the relative
+// forms Slack search understands natively (today, yesterday, a month name, YYYY or YYYY-MM).
+func normalizeSearchDate(key, val string) (string, error) {
+	if _, normalized, err := parseFlexibleDate(val); err == nil {
+		return normalized, nil
+	}
+	v := strings.ToLower(val)
+	if v == "today" || v == "yesterday" || searchDatePeriodRe.MatchString(v) {
+		return v, nil
+	}
+	if _, err := time.Parse("January", strings.ToUpper(v[:1])+v[1:]); err == nil {
+		return v, nil
+	}
+	return "", fmt.Errorf("invalid %q date: %q", key, val)
+}
+
+// parseSearchSort validates the sort and sort_dir parameters of
+// conversations_search_messages, defaulting to Slack's relevance order.
+func parseSearchSort(sortBy, sortDir string) (string, string, error) {
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	sortDir = strings.ToLower(strings.TrimSpace(sortDir))
+	switch sortBy {
+	case "":
+		sortBy = slack.DEFAULT_SEARCH_SORT
+	case "score", "timestamp":
+	default:
+		return "", "", fmt.Errorf("invalid sort %q: must be 'score' or 'timestamp'", sortBy)
+	}
+	switch sortDir {
+	case "":
+		sortDir = slack.DEFAULT_SEARCH_SORT_DIR
+	case "asc", "desc":
+	default:
+		return "", "", fmt.Errorf("invalid sort_dir %q: must be 'asc' or 'desc'", sortDir)
+	}
+	return sortBy, sortDir, nil
+}
+
+// validateSearchFilterValue checks is: and has: modifier values against the
+// values Slack search understands and returns the normalized (lower-cased) value.
+func validateSearchFilterValue(key, val string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(val))
+	if key == "has" && emojiModifierRe.MatchString(v) {
+		return v, nil
+	}
+	allowed, ok := validFilterValues[key]
+	if !ok {
+		return v, nil
+	}
+	if _, ok := allowed[v]; ok {
+		return v, nil
+	}
+
+	accepted := make([]string, 0, len(allowed))
+	for a := range allowed {
+		accepted = append(accepted, a)
+	}
+	sort.Strings(accepted)
+	if key == "has" {
+		accepted = append(accepted, ":emoji:")
+	}
+	return "", fmt.Errorf("invalid search modifier %s:%s, accepted values: %s", key, val, strings.Join(accepted, ", "))
+}
+
 func (ch *ConversationsHandler) paramFormatChannel(raw string) (string, error) {
 	raw = strings.TrimSpace(raw)
 	cms := ch.apiProvider.ProvideChannelsMaps()
 	if strings.HasPrefix(raw, "#") {
 		if id, ok := cms.ChannelsInv[raw]; ok {
+			if err := ch.apiProvider.CheckChannel(id); err != nil {
+				return "", err
+			}
 			return cms.Channels[id].Name, nil
 		}
-		return "", fmt.Errorf("channel %q not found", raw)
+		return "", ch.apiProvider.ChannelNotFound(raw, "")
 	}
 	// Handle both C (standard channels) and G (private groups/channels) prefixes
 	if strings.HasPrefix(raw, "C") || strings.HasPrefix(raw, "G") {
 		if chn, ok := cms.Channels[raw]; ok {
+			if err := ch.apiProvider.CheckChannel(raw); err != nil {
+				return "", err
+			}
 			return chn.Name, nil
 		}
 		return "", fmt.Errorf("channel %q not found", raw)
@@ -1186,14 +2584,44 @@ func (ch *ConversationsHandler) paramFormatChannel(raw string) (string, error) {
 	return "", fmt.Errorf("invalid channel format: %q", raw)
 }
 
-func marshalMessagesToCSV(messages []Message) (*mcp.CallToolResult, error) {
-	csvBytes, err := gocsv.MarshalBytes(&messages)
+// historyUserIDs returns the authors of messages, for EnsureUsers.
+func historyUserIDs(messages []slack.Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.User)
+	}
+	return ids
+}
+
+func searchUserIDs(messages []slack.SearchMessage) []string {
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.User)
+	}
+	return ids
+}
+
+// MarshalMessagesToCSV writes messages as the CSV result of the tools that
+// return message rows, with the opt-in columns in columns.
+func MarshalMessagesToCSV(messages []Message, columns MessageColumns) (*mcp.CallToolResult, error) {
+	csvBytes, err := marshalMessages(messages, columns)
 	if err != nil {
 		return nil, err
 	}
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
 
+// withJSONMetadata appends meta as a separate JSON content block so the CSV
+// payload stays machine-parseable.
+func withJSONMetadata(res *mcp.CallToolResult, meta any) (*mcp.CallToolResult, error) {
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	res.Content = append(res.Content, mcp.NewTextContent(string(metaBytes)))
+	return res, nil
+}
+
 func getUserInfo(userID string, usersMap map[string]slack.User) (userName, realName string, ok bool) {
 	if u, ok := usersMap[userID]; ok {
 		return u.Name, u.RealName, true
@@ -1249,6 +2677,44 @@ func limitByExpression(limit, defaultLimit string) (slackLimit int, oldest, late
 	return 100, oldest, latest, nil
 }
 
+var slackTsRe = regexp.MustCompile(`^\d{9,10}(\.\d{1,6})?$`)
+
+// parseTimeBound converts an oldest or latest bound into a Slack timestamp.
+// It accepts Slack timestamps, Unix seconds, RFC 3339 times, "YYYY-MM-DD HH:MM"
+// local times and the dates parseFlexibleDate understands, in loc. A date
+// alone is the start of that day, or its end when endOfDay is set, so a
+// latest date includes the whole day. Empty stays empty.
+func parseTimeBound(value string, loc *time.Location, endOfDay bool) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if slackTsRe.MatchString(value) {
+		if !strings.Contains(value, ".") {
+			value += ".000000"
+		}
+		return value, nil
+	}
+
+	var t time.Time
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		t = parsed
+	} else if parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc); err == nil {
+		t = parsed
+	} else if parsed, err := time.ParseInLocation("2006-01-02T15:04", value, loc); err == nil {
+		t = parsed
+	} else {
+		date, _, err := parseFlexibleDate(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a Slack timestamp, date or time", value)
+		}
+		t = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		if endOfDay {
+			return fmt.Sprintf("%d.999999", t.AddDate(0, 0, 1).Unix()-1), nil
+		}
+	}
+	return fmt.Sprintf("%d.000000", t.Unix()), nil
+}
+
 func extractThreadTS(rawurl string) (string, error) {
 	u, err := url.Parse(rawurl)
 	if err != nil {
@@ -1454,7 +2920,7 @@ func addFilter(filters map[string][]string, key, val string) {
 func buildQuery(freeText []string, filters map[string][]string) string {
 	var out []string
 	out = append(out, freeText...)
-	for _, key := range []string{"is", "in", "from", "with", "before", "after", "on", "during"} {
+	for _, key := range filterKeysOrder {
 		for _, val := range filters[key] {
 			out = append(out, fmt.Sprintf("%s:%s", key, val))
 		}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -12,13 +13,19 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/test/util"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/packages/param"
 	"github.com/openai/openai-go/responses"
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestIntegrationConversations(t *testing.T) {
@@ -591,6 +598,42 @@ func TestUnitLimitByExpression_Invalid(t *testing.T) {
 	}
 }
 
+func TestUnitParseTimeBound(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	march1 := time.Date(2024, 3, 1, 0, 0, 0, 0, berlin).Unix()
+
+	tests := []struct {
+		input    string
+		endOfDay bool
+		want     string
+		wantErr  bool
+	}{
+		{"", false, "", false},
+		{"1709251200.123456", false, "1709251200.123456", false},
+		{"1709251200", false, "1709251200.000000", false},
+		{"2024-03-01T10:00:00Z", false, "1709287200.000000", false},
+		{"2024-03-01 09:30", false, strconv.FormatInt(march1+9*3600+30*60, 10) + ".000000", false},
+		{"2024-03-01", false, strconv.FormatInt(march1, 10) + ".000000", false},
+		{"2024-03-01", true, strconv.FormatInt(march1+86400-1, 10) + ".999999", false},
+		{"March 1, 2024", false, strconv.FormatInt(march1, 10) + ".000000", false},
+		{"yesterday-ish", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseTimeBound(tt.input, berlin, tt.endOfDay)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTimeBound(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseTimeBound(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestUnitIsChannelAllowedForConfig(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -653,3 +696,598 @@ func TestUnitIsSlackUserIDPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitValidateSearchFilterValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		val     string
+		want    string
+		wantErr bool
+	}{
+		{"is thread", "is", "thread", "thread", false},
+		{"is saved mixed case", "is", "Saved", "saved", false},
+		{"is unknown", "is", "starred", "", true},
+		{"has link", "has", "link", "link", false},
+		{"has pin", "has", "pin", "pin", false},
+		{"has file", "has", "file", "file", false},
+		{"has emoji reaction", "has", ":eyes:", ":eyes:", false},
+		{"has emoji with plus", "has", ":+1:", ":+1:", false},
+		{"has unknown", "has", "attachment", "", true},
+		{"has malformed emoji", "has", ":eyes", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateSearchFilterValue(tt.key, tt.val)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitSearchQueryModifiersPassThrough(t *testing.T) {
+	ap := &provider.ApiProvider{}
+	require.NoError(t, ap.ApplyConfig(config.Default()))
+	ch := NewConversationsHandler(ap, zap.NewNop())
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	params, err := ch.parseParamsToolSearch(req(map[string]any{"search_query": "renewal in:customers in:general is:starred has:attachment"}))
+	require.NoError(t, err)
+	assert.Equal(t, "renewal is:starred has:attachment in:customers in:general", params.query,
+		"modifiers in search_query that cannot be normalized go to Slack as they are")
+
+	_, err = ch.parseParamsToolSearch(req(map[string]any{"search_query": "renewal", "search_modifiers": "is:starred"}))
+	assert.ErrorContains(t, err, "invalid search modifier is:starred")
+}
+
+func TestUnitSearchInFilterPolicy(t *testing.T) {
+	ap := &provider.ApiProvider{}
+	require.NoError(t, ap.ApplyConfig(&config.Config{ChannelDenylist: []string{"#finance"}}))
+	ch := NewConversationsHandler(ap, zap.NewNop())
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	var policyErr *provider.ChannelPolicyError
+	_, err := ch.parseParamsToolSearch(req(map[string]any{"search_query": "renewal in:finance"}))
+	require.ErrorAs(t, err, &policyErr, "a bare in: name is checked like #name")
+	assert.EqualError(t, err, "channel #finance is not accessible: matches SLACK_MCP_CHANNEL_DENYLIST entry #finance")
+
+	_, err = ch.parseParamsToolSearch(req(map[string]any{"search_query": "renewal", "search_modifiers": "in:finance"}))
+	assert.ErrorAs(t, err, &policyErr)
+
+	params, err := ch.parseParamsToolSearch(req(map[string]any{"search_query": "renewal in:general"}))
+	require.NoError(t, err)
+	assert.Equal(t, "renewal in:general", params.query)
+}
+
+func TestUnitParseSearchSort(t *testing.T) {
+	sortBy, sortDir, err := parseSearchSort("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "score", sortBy)
+	assert.Equal(t, "desc", sortDir)
+
+	sortBy, sortDir, err = parseSearchSort("Timestamp", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, "timestamp", sortBy)
+	assert.Equal(t, "asc", sortDir)
+
+	_, _, err = parseSearchSort("date", "")
+	assert.ErrorContains(t, err, "invalid sort")
+	_, _, err = parseSearchSort("", "newest")
+	assert.ErrorContains(t, err, "invalid sort_dir")
+}
+
+func TestUnitNormalizeSearchDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     string
+		want    string
+		wantErr bool
+	}{
+		{"iso date", "2024-07-15", "2024-07-15", false},
+		{"year", "2024", "2024", false},
+		{"year and month", "2024-07", "2024-07", false},
+		{"garbage", "not-a-date", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeSearchDate("during", tt.val)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitSplitQueryModifiers(t *testing.T) {
+	freeText, filters := splitQuery("release notes has:link is:thread To:me https://example.com/a:b")
+	assert.Equal(t, []string{"release", "notes", "https://example.com/a:b"}, freeText)
+	assert.Equal(t, []string{"link"}, filters["has"])
+	assert.Equal(t, []string{"thread"}, filters["is"])
+	assert.Equal(t, []string{"me"}, filters["to"])
+
+	query := buildQuery(freeText, filters)
+	assert.Equal(t, "release notes https://example.com/a:b is:thread has:link to:me", query)
+}
+
+func TestUnitConvertMessagesFromHistory(t *testing.T) {
+	ch := NewConversationsHandler(&provider.ApiProvider{}, zap.NewNop())
+	msgs := []slack.Message{
+		{Msg: slack.Msg{
+			Timestamp:  "1700000100.000200",
+			User:       "U01",
+			Text:       "Deploy is failing",
+			ReplyCount: 4,
+			Reactions: []slack.ItemReaction{
+				{Name: "thumbsup", Count: 3},
+				{Name: "eyes", Count: 1},
+			},
+		}},
+		{Msg: slack.Msg{
+			Timestamp: "1700000000.000100",
+			User:      "U02",
+			Text:      "fixed typo",
+			Edited:    &slack.Edited{User: "U02", Timestamp: "1700000050.000000"},
+		}},
+		{Msg: slack.Msg{Timestamp: "1699999999.000000", User: "U03", SubType: "channel_join"}},
+	}
+
+	rows := ch.convertMessagesFromHistory(msgs, "C0123ABCD", false, false, false, "plain", time.UTC)
+	require.Len(t, rows, 2, "activity is left out")
+
+	assert.Equal(t, "thumbsup:3,eyes:1", rows[0].ReactionCounts)
+	assert.Equal(t, "thumbsup:3|eyes:1", rows[0].Reactions)
+	assert.Equal(t, 4, rows[0].ReplyCount)
+	assert.False(t, rows[0].IsEdited)
+
+	assert.Empty(t, rows[1].ReactionCounts)
+	assert.Zero(t, rows[1].ReplyCount)
+	assert.True(t, rows[1].IsEdited)
+}
+
+func TestUnitSlackTsLess(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"earlier second", "1700000000.000001", "1700000001.000000", true},
+		{"same second, earlier micro", "1700000000.000100", "1700000000.000200", true},
+		{"later", "1700000002.000000", "1700000001.999999", false},
+		{"equal", "1700000000.000001", "1700000000.000001", false},
+		{"shorter integer part", "999999999.000000", "1000000000.000000", true},
+		{"short fraction", "1700000000.000600", "1700000000.5", true},
+		{"no fraction", "1700000000", "1700000000.000001", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, slackTsLess(tt.a, tt.b))
+		})
+	}
+}
+
+func TestUnitValidateBotIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity botIdentity
+		wantErr  string
+	}{
+		{name: "username only", identity: botIdentity{username: "Release Bot"}},
+		{name: "emoji icon", identity: botIdentity{username: "Release Bot", iconEmoji: "rocket"}},
+		{name: "url icon", identity: botIdentity{iconURL: "https://example.com/icon.png"}},
+		{name: "both icons", identity: botIdentity{iconEmoji: "rocket", iconURL: "https://example.com/icon.png"}, wantErr: "cannot be used together"},
+		{name: "emoji with spaces", identity: botIdentity{iconEmoji: "rocket ship"}, wantErr: "single emoji name"},
+		{name: "relative url", identity: botIdentity{iconURL: "/icon.png"}, wantErr: "absolute http or https URL"},
+		{name: "non-http url", identity: botIdentity{iconURL: "ftp://example.com/icon.png"}, wantErr: "absolute http or https URL"},
+		{name: "long username", identity: botIdentity{username: strings.Repeat("a", 81)}, wantErr: "at most 80 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBotIdentity(tt.identity)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnitParseInviteEmails(t *testing.T) {
+	emails, err := parseInviteEmails("ana@example.com, Bo <BO@example.com>;ana@example.com\ncy@example.org")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ana@example.com", "bo@example.com", "cy@example.org"}, emails)
+
+	_, err = parseInviteEmails(" , ")
+	assert.ErrorContains(t, err, "at least one")
+	_, err = parseInviteEmails("ana@example.com, not-an-email")
+	assert.ErrorContains(t, err, `invalid email "not-an-email"`)
+
+	many := make([]string, maxInviteEmails+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("u%d@example.com", i)
+	}
+	_, err = parseInviteEmails(strings.Join(many, ","))
+	assert.ErrorContains(t, err, "too many emails")
+}
+
+func TestUnitSlackErrorCode(t *testing.T) {
+	assert.Equal(t, "users_not_found", slackErrorCode(fmt.Errorf("lookup: %w", slack.SlackErrorResponse{Err: "users_not_found"})))
+	assert.Equal(t, "timeout", slackErrorCode(errors.New("timeout")))
+}
+
+func TestUnitSetUnfurls(t *testing.T) {
+	var m Message
+	setUnfurls(&m, []slack.Attachment{
+		{Title: "Deploy finished", Text: "bot attachment, not a link preview"},
+		{
+			OriginalURL: "https://github.com/org/repo/pull/1",
+			ServiceName: "GitHub",
+			Title:       "Fix | escape",
+			Text:        "Line one\nline two",
+		},
+		{FromURL: "https://example.com/post", Title: "Post", Text: strings.Repeat("a", maxUnfurlDescription+10)},
+	})
+
+	assert.Equal(t, "https://github.com/org/repo/pull/1|https://example.com/post", m.UnfurlURLs)
+	assert.Equal(t, "Fix / escape|Post", m.UnfurlTitles)
+	assert.Equal(t, "GitHub|", m.UnfurlServices)
+	descriptions := strings.Split(m.UnfurlDescriptions, "|")
+	require.Len(t, descriptions, 2)
+	assert.Equal(t, "Line one line two", descriptions[0])
+	assert.Len(t, []rune(descriptions[1]), maxUnfurlDescription)
+	assert.True(t, strings.HasSuffix(descriptions[1], "…"))
+
+	var none Message
+	setUnfurls(&none, []slack.Attachment{{Title: "Deploy finished"}})
+	assert.Empty(t, none.UnfurlURLs)
+}
+
+func TestUnitParseMessageMetadata(t *testing.T) {
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	meta, err := parseMessageMetadata(req(map[string]any{}))
+	require.NoError(t, err)
+	assert.Nil(t, meta)
+
+	meta, err = parseMessageMetadata(req(map[string]any{
+		"metadata_event_type":    "ticket_linked",
+		"metadata_event_payload": map[string]any{"ticket_id": "OPS-123"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &slack.SlackMetadata{EventType: "ticket_linked", EventPayload: map[string]any{"ticket_id": "OPS-123"}}, meta)
+
+	meta, err = parseMessageMetadata(req(map[string]any{
+		"metadata_event_type":    "ticket_linked",
+		"metadata_event_payload": `{"ticket_id": "OPS-7"}`,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "OPS-7", meta.EventPayload["ticket_id"])
+	assert.Equal(t, `{"event_type":"ticket_linked","event_payload":{"ticket_id":"OPS-7"}}`, formatMessageMetadata(*meta))
+
+	meta, err = parseMessageMetadata(req(map[string]any{"metadata_event_type": "ticket_linked"}))
+	require.NoError(t, err)
+	assert.Empty(t, meta.EventPayload)
+
+	_, err = parseMessageMetadata(req(map[string]any{"metadata_event_payload": map[string]any{"a": 1}}))
+	assert.ErrorContains(t, err, "requires metadata_event_type")
+	_, err = parseMessageMetadata(req(map[string]any{"metadata_event_type": "Ticket Linked"}))
+	assert.ErrorContains(t, err, "must be lowercase")
+	_, err = parseMessageMetadata(req(map[string]any{"metadata_event_type": "t", "metadata_event_payload": "[1]"}))
+	assert.ErrorContains(t, err, "must be a JSON object")
+
+	assert.Empty(t, formatMessageMetadata(slack.SlackMetadata{}))
+}
+
+func TestUnitParsePostAt(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    int64
+		wantErr string
+	}{
+		{name: "empty posts now", raw: "", want: 0},
+		{name: "duration", raw: "30m", want: now.Add(30 * time.Minute).Unix()},
+		{name: "rfc3339", raw: "2025-06-03T09:00:00Z", want: time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC).Unix()},
+		{name: "local time in tz", raw: "2025-06-02 12:00", want: time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC).Unix()},
+		{name: "unix seconds", raw: "1748865600", want: 1748865600},
+		{name: "past", raw: "2025-06-01 09:00", wantErr: "in the past"},
+		{name: "negative duration", raw: "-5m", wantErr: "in the past"},
+		{name: "too far ahead", raw: "3000h", wantErr: "120 days"},
+		{name: "garbage", raw: "tomorrow-ish", wantErr: "invalid post_at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePostAt(tt.raw, loc, now)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUnitHasReaction(t *testing.T) {
+	reactions := []slack.ItemReaction{
+		{Name: "white_check_mark", Count: 1},
+		{Name: "+1::skin-tone-3", Count: 2},
+		{Name: "thumbsup", Count: 1},
+		{Name: "custom_done", Count: 1},
+	}
+
+	assert.True(t, hasReaction(reactions, reactionKey(":white_check_mark:"), 1))
+	assert.True(t, hasReaction(reactions, reactionKey("✅"), 1))
+	assert.True(t, hasReaction(reactions, reactionKey("👍"), 3), "aliases and skin tones add up")
+	assert.False(t, hasReaction(reactions, reactionKey("thumbsup"), 4))
+	assert.True(t, hasReaction(reactions, reactionKey("custom_done"), 1))
+	assert.False(t, hasReaction(reactions, reactionKey("eyes"), 1))
+	assert.Equal(t, reactionKey("heart"), reactionKey("❤"), "variation selectors are ignored")
+}
+
+func TestUnitWorkingHoursHint(t *testing.T) {
+	user := slack.User{TZ: "Europe/Berlin", TZOffset: 7200}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 2, 22, 0, 0, 0, berlin)
+	end := time.Date(2025, 6, 3, 8, 0, 0, 0, berlin)
+
+	st := slack.DNDStatus{Enabled: true, NextStartTimestamp: int(start.Unix()), NextEndTimestamp: int(end.Unix())}
+	assert.Equal(t, "08:00-22:00", workingHoursHint(st, user))
+
+	st.Enabled = false
+	assert.Empty(t, workingHoursHint(st, user))
+	assert.Empty(t, workingHoursHint(slack.DNDStatus{Enabled: true}, user))
+
+	// Unknown IANA names fall back to tz_offset.
+	fixed := slack.User{TZ: "Mars/Olympus", TZOffset: -5 * 3600}
+	st = slack.DNDStatus{Enabled: true, NextStartTimestamp: int(start.Unix()), NextEndTimestamp: int(end.Unix())}
+	assert.Equal(t, "01:00-15:00", workingHoursHint(st, fixed))
+	assert.Equal(t, "-05:00", userTZOffset(fixed))
+	assert.Empty(t, userTZOffset(slack.User{}))
+}
+
+func TestUnitParseSampledChannels(t *testing.T) {
+	shortlist := []string{"#ops-incidents", "#incident-review", "#general"}
+
+	assert.Equal(t,
+		[]string{"#ops-incidents", "#incident-review"},
+		parseSampledChannels("1. #ops-incidents\n2) incident-review\n#made-up\n#ops-incidents", shortlist),
+	)
+	assert.Equal(t, []string{"#general"}, parseSampledChannels("`#General`", shortlist))
+	assert.Empty(t, parseSampledChannels("I am not sure.", shortlist))
+	assert.Equal(t, "hi", samplingText(map[string]any{"type": "text", "text": "hi"}))
+	assert.Equal(t, "hi", samplingText(mcp.NewTextContent("hi")))
+}
+
+func TestUnitTranscriptRender(t *testing.T) {
+	doc := transcript{
+		title: "Transcript of #incidents",
+		from:  time.Unix(1709251200, 0),
+		to:    time.Unix(1709337600, 0),
+		loc:   time.UTC,
+		users: map[string]slack.User{
+			"U1": {ID: "U1", Name: "alice"},
+			"U2": {ID: "U2", Name: "bob", Profile: slack.UserProfile{DisplayName: "Bob"}},
+		},
+		resolver: text.MrkdwnResolver{User: func(id string) (string, bool) { return "alice", id == "U1" }},
+		entries: []transcriptEntry{{
+			msg: slack.Message{Msg: slack.Msg{User: "U1", Timestamp: "1709280000.000100", Text: "Outage <https://status.example.com|status>\nsecond line",
+				Files: []slack.File{{ID: "F1", Name: "log.txt", Permalink: "https://files.example.com/F1"}}}},
+			replies: []slack.Message{{Msg: slack.Msg{User: "U2", Timestamp: "1709280060.000200", Text: "on it <@U1> <b>"}}},
+		}},
+	}
+
+	md := doc.markdown()
+	assert.Contains(t, md, "# Transcript of #incidents\n\n2024-03-01 00:00 to 2024-03-02 00:00 (UTC)\n")
+	assert.Contains(t, md, "**alice** · 2024-03-01 08:00:00\nOutage [status](https://status.example.com)\nsecond line\nAttachment: [log.txt](https://files.example.com/F1)\n")
+	assert.Contains(t, md, ">\n> **Bob** · 2024-03-01 08:01:00\n> on it @alice <b>\n")
+
+	page := doc.html()
+	assert.Contains(t, page, `<a href="https://status.example.com">status</a>`)
+	assert.Contains(t, page, "<div class=\"thread\">\n<div class=\"message\">")
+	assert.Contains(t, page, "on it @alice &lt;b&gt;")
+	assert.Contains(t, page, `<time datetime="2024-03-01T08:01:00Z">2024-03-01 08:01:00</time>`)
+}
+
+func TestUnitRangePercent(t *testing.T) {
+	assert.Equal(t, 0.0, rangePercent("1000.000000", "2000.000000", "2000.000000"))
+	assert.Equal(t, 25.0, rangePercent("1000.000000", "2000.000000", "1750.000000"))
+	assert.Equal(t, 100.0, rangePercent("1000.000000", "2000.000000", "900.000000"), "clamped below oldest")
+	assert.Equal(t, 0.0, rangePercent("1000.000000", "2000.000000", "bad"))
+	assert.Equal(t, 0.0, rangePercent("2000.000000", "1000.000000", "1500.000000"))
+
+	var p *progressReporter
+	p.Report(50, 100, "a nil reporter does nothing")
+}
+
+func TestUnitAuditEntryRow(t *testing.T) {
+	var e slack.AuditEntry
+	e.ID = "0123a45b-6c7d"
+	e.DateCreate = 1709283600
+	e.Action = "channel_archive"
+	e.Actor.User = slack.AuditUser{ID: "W123", Name: "alice", Email: "alice@example.com"}
+	e.Entity.Type = "channel"
+	e.Entity.Channel = slack.AuditChannel{ID: "C42", Name: "finance"}
+	e.Context.Location.Name = "Acme"
+	e.Context.IPAddress = "10.0.0.1"
+
+	row := auditEntryRow(e, time.UTC)
+	assert.Equal(t, AuditEntryRow{
+		ID:         "0123a45b-6c7d",
+		Date:       "2024-03-01 09:00:00 UTC",
+		Action:     "channel_archive",
+		ActorID:    "W123",
+		ActorName:  "alice",
+		ActorEmail: "alice@example.com",
+		EntityType: "channel",
+		EntityID:   "C42",
+		EntityName: "finance",
+		Location:   "Acme",
+		IPAddress:  "10.0.0.1",
+	}, row)
+
+	e.Details.PreviousValue = "general"
+	e.Details.NewValue = "general-old"
+	assert.JSONEq(t, `{"previous_value":"general","new_value":"general-old"}`, auditEntryRow(e, time.UTC).Details)
+
+	sec, err := auditTimeBound("2024-03-01", time.UTC, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1709337599, sec)
+	sec, err = auditTimeBound("", time.UTC, false)
+	require.NoError(t, err)
+	assert.Zero(t, sec)
+}
+
+func TestUnitAuditEntityPolicy(t *testing.T) {
+	ap := &provider.ApiProvider{}
+	require.NoError(t, ap.ApplyConfig(&config.Config{ChannelDenylist: []string{"C0FINANCE1"}}))
+	ch := NewConversationsHandler(ap, zap.NewNop())
+
+	id, err := ch.auditEntityID(context.Background(), "C0GENERAL1")
+	require.NoError(t, err)
+	assert.Equal(t, "C0GENERAL1", id)
+	id, err = ch.auditEntityID(context.Background(), "F0123ABCD")
+	require.NoError(t, err)
+	assert.Equal(t, "F0123ABCD", id, "files and other entities are not channels")
+
+	_, err = ch.auditEntityID(context.Background(), " C0FINANCE1 ")
+	var policyErr *provider.ChannelPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, "C0FINANCE1", policyErr.ChannelID)
+}
+
+func TestUnitUserAvatarURL(t *testing.T) {
+	assert.Equal(t, "https://a/192.png", userAvatarURL(slack.UserProfile{Image72: "https://a/72.png", Image192: "https://a/192.png"}))
+	assert.Equal(t, "https://a/72.png", userAvatarURL(slack.UserProfile{Image24: "https://a/24.png", Image72: "https://a/72.png"}))
+	assert.Empty(t, userAvatarURL(slack.UserProfile{}))
+
+	var dms *usersSearchDMOpener
+	assert.Empty(t, dms.open(context.Background(), slack.User{ID: "U1"}), "a nil opener opens nothing")
+}
+
+func TestUnitForwardedQuote(t *testing.T) {
+	assert.Equal(t, "https://acme.slack.com/archives/C1/p1700000000000200",
+		messagePermalink("https://acme.slack.com", "C1", "1700000000.000200", ""))
+	assert.Equal(t, "https://acme.slack.com/archives/C1/p1700000000000200?thread_ts=1700000000.000100&cid=C1",
+		messagePermalink("https://acme.slack.com", "C1", "1700000000.000200", "1700000000.000100"))
+	assert.Empty(t, messagePermalink("", "C1", "1700000000.000200", ""))
+
+	q := forwardedQuote{
+		channel:   "C1",
+		author:    "alice",
+		ts:        "1700000000.000200",
+		time:      "Nov 14, 2023 22:13",
+		permalink: "https://acme.slack.com/archives/C1/p1700000000000200",
+		text:      "deploy is done\nall green",
+	}
+	assert.Equal(t, "FYI\n> *alice* in <#C1> · <https://acme.slack.com/archives/C1/p1700000000000200|Nov 14, 2023 22:13>\n> deploy is done\n> all green", q.mrkdwn("FYI"))
+
+	q.permalink = ""
+	assert.Equal(t, "> *alice* in <#C1> · Nov 14, 2023 22:13\n> deploy is done\n> all green", q.mrkdwn(""))
+
+	a := q.attachment()
+	assert.Equal(t, "alice", a.AuthorName)
+	assert.Equal(t, "deploy is done\nall green", a.Text)
+	assert.Equal(t, "1700000000.000200", a.Ts.String())
+}
+
+func TestUnitAsTombstone(t *testing.T) {
+	changed := slack.Message{
+		Msg: slack.Msg{SubType: "message_changed", Timestamp: "1700000100.000000"},
+		SubMessage: &slack.Msg{
+			User:      "U1",
+			Text:      "fixed typo",
+			Timestamp: "1700000000.000100",
+			Edited:    &slack.Edited{User: "U1", Timestamp: "1700000050.000000"},
+		},
+	}
+	msg, kind, at := asTombstone(changed)
+	assert.Equal(t, "edited", kind)
+	assert.Equal(t, "1700000050.000000", at)
+	assert.Equal(t, "1700000000.000100", msg.Timestamp)
+	assert.Equal(t, "fixed typo", msg.Text)
+	assert.Empty(t, msg.SubType)
+
+	msg, kind, at = asTombstone(slack.Message{Msg: slack.Msg{
+		SubType:          "message_deleted",
+		Timestamp:        "1700000200.000000",
+		DeletedTimestamp: "1700000000.000100",
+	}})
+	assert.Equal(t, "deleted", kind)
+	assert.Equal(t, "1700000200.000000", at)
+	assert.Equal(t, "1700000000.000100", msg.Timestamp)
+
+	msg, kind, at = asTombstone(slack.Message{Msg: slack.Msg{SubType: "tombstone", Text: "This message was deleted.", Timestamp: "1700000000.000100"}})
+	assert.Equal(t, "deleted", kind)
+	assert.Empty(t, at, "Slack does not record when a tombstoned parent was deleted")
+	assert.Empty(t, msg.Text)
+
+	_, kind, at = asTombstone(slack.Message{Msg: slack.Msg{Timestamp: "1700000000.000100", Edited: &slack.Edited{Timestamp: "1700000060.000000"}}})
+	assert.Equal(t, "edited", kind)
+	assert.Equal(t, "1700000060.000000", at)
+
+	_, kind, _ = asTombstone(slack.Message{Msg: slack.Msg{SubType: "channel_join", Timestamp: "1700000000.000100"}})
+	assert.Empty(t, kind, "other activity messages are left to include_activity_messages")
+}
+
+func TestUnitNewSearchMetadata(t *testing.T) {
+	deploy := &searchParams{query: "deploy", sort: "score", sortDir: "desc"}
+	meta := newSearchMetadata(deploy, slack.Pagination{TotalCount: 45, Page: 1, PerPage: 20, PageCount: 3}, 20)
+	assert.True(t, meta.HasMore)
+	page, err := decodeCursor("conversations_search_messages", searchCursorParams("deploy", "score", "desc"), meta.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "2", page)
+	assert.Empty(t, meta.Hint)
+
+	meta = newSearchMetadata(deploy, slack.Pagination{TotalCount: 45, Page: 3, PerPage: 20, PageCount: 3}, 5)
+	assert.False(t, meta.HasMore)
+	assert.Empty(t, meta.NextCursor)
+
+	meta = newSearchMetadata(deploy, slack.Pagination{TotalCount: 0, Page: 1, PerPage: 20, PageCount: 0}, 0)
+	assert.False(t, meta.HasMore, "no results means no more pages")
+
+	meta = newSearchMetadata(&searchParams{query: "error"}, slack.Pagination{TotalCount: 2400, Page: 2, PerPage: 100, PageCount: 24}, 100)
+	assert.True(t, meta.HasMore)
+	assert.Equal(t, "2400 matches, 22 more pages; consider narrowing the query with filter_in_channel, filter_users_from or a date filter instead of paging through all of them", meta.Hint)
+}
+
+func TestUnitPostFilterSearch(t *testing.T) {
+	matches := []slack.SearchMessage{
+		{Timestamp: "1", Text: "deploy failed with ERR-4012"},
+		{Timestamp: "2", Text: "deploy failed with ERR-12"},
+		{Timestamp: "3", Text: "see alert", Attachments: []slack.Attachment{{Text: "code ERR-5001 on db-2"}}},
+		{Timestamp: "4", Text: "err-4012 lower case"},
+	}
+	kept := postFilterSearch(matches, regexp.MustCompile(`ERR-\d{4}\b`))
+	var ts []string
+	for _, m := range kept {
+		ts = append(ts, m.Timestamp)
+	}
+	assert.Equal(t, []string{"1", "3"}, ts)
+	assert.Len(t, postFilterSearch(matches, regexp.MustCompile(`(?i)err-4012`)), 2)
+}
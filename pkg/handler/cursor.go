@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// cursorVersion is the version of the cursor envelope the tools hand out.
+const cursorVersion = 1
+
+// cursorEnvelope is the opaque cursor of every paginating tool: base64 of
+// this JSON. Tool and Params tie it to the call it continues, so a cursor of
+// one tool, or of another query, is rejected instead of silently paging
+// through the wrong results. Cursor is the tool's own position, e.g. a Slack
+// cursor or a page number.
+type cursorEnvelope struct {
+	V      int    `json:"v"`
+	Tool   string `json:"tool"`
+	Params string `json:"params,omitempty"`
+	Cursor string `json:"cursor"`
+}
+
+// cursorParams hashes the arguments that select what a tool pages through,
+// e.g. the channel and time window. Arguments that only shape the output,
+// such as limit, fields or render, are left out, so changing them between
+// pages keeps the cursor valid.
+func cursorParams(values ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(values, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeCursor wraps the position inner of tool in a cursor envelope. An
+// empty inner, meaning there are no more pages, stays empty.
+func encodeCursor(tool, params, inner string) string {
+	if inner == "" {
+		return ""
+	}
+	b, err := json.Marshal(cursorEnvelope{V: cursorVersion, Tool: tool, Params: params, Cursor: inner})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor returns the position in raw, a cursor tool handed out for the
+// same params. Cursors from before the envelope are returned unchanged for
+// the tool to parse as it always has.
+func decodeCursor(tool, params, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	env, ok := parseCursorEnvelope(raw)
+	if !ok {
+		return raw, nil
+	}
+	if env.V != cursorVersion {
+		return "", fmt.Errorf("invalid cursor: unsupported cursor version %d, start again without cursor", env.V)
+	}
+	if env.Tool != tool {
+		return "", fmt.Errorf("invalid cursor: it was returned by %s and cannot be used with %s", env.Tool, tool)
+	}
+	if env.Params != params {
+		return "", fmt.Errorf("invalid cursor: it belongs to a %s call with different filters; repeat that call's filters or start again without cursor", tool)
+	}
+	if env.Cursor == "" {
+		return "", fmt.Errorf("invalid cursor: %q", raw)
+	}
+	return env.Cursor, nil
+}
+
+func parseCursorEnvelope(raw string) (cursorEnvelope, bool) {
+	var env cursorEnvelope
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil || len(b) == 0 || b[0] != '{' {
+		return env, false
+	}
+	if err := json.Unmarshal(b, &env); err != nil || env.V == 0 {
+		return env, false
+	}
+	return env, true
+}
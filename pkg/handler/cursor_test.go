@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitCursorEnvelope(t *testing.T) {
+	params := cursorParams("C123", "1700000000.000100")
+	cursor := encodeCursor("conversations_replies", params, "bmV4dF90czox")
+	require.NotEmpty(t, cursor)
+	assert.Empty(t, encodeCursor("conversations_replies", params, ""), "no more pages")
+
+	inner, err := decodeCursor("conversations_replies", params, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "bmV4dF90czox", inner)
+
+	_, err = decodeCursor("conversations_search_messages", params, cursor)
+	assert.ErrorContains(t, err, "returned by conversations_replies and cannot be used with conversations_search_messages")
+
+	_, err = decodeCursor("conversations_replies", cursorParams("C999", "1700000000.000100"), cursor)
+	assert.ErrorContains(t, err, "different filters")
+
+	future := base64.RawURLEncoding.EncodeToString([]byte(`{"v":2,"tool":"conversations_replies","cursor":"x"}`))
+	_, err = decodeCursor("conversations_replies", params, future)
+	assert.ErrorContains(t, err, "unsupported cursor version 2")
+}
+
+func TestUnitCursorLegacyPassThrough(t *testing.T) {
+	for _, raw := range []string{"", "3", "cGFnZToy", "bmV4dF90czoxNzAwMDAwMDAw", "1700000000.000100"} {
+		inner, err := decodeCursor("files_list", cursorParams("C123"), raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, raw, inner, "cursors from before the envelope are left to the tool")
+	}
+}
+
+func TestUnitCursorParams(t *testing.T) {
+	assert.Equal(t, cursorParams("a", "b"), cursorParams("a", "b"))
+	assert.NotEqual(t, cursorParams("a", "b"), cursorParams("ab", ""), "values are separated")
+	assert.NotEqual(t, cursorParams("a"), cursorParams("b"))
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/cron"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// digestSnippetLength caps each message in a digest, in runes.
+const digestSnippetLength = 200
+
+// digestSubtypes are the message subtypes kept in digests besides plain
+// messages; joins, leaves, topic changes and the like are skipped.
+var digestSubtypes = []string{"bot_message", "thread_broadcast", "file_share", "me_message"}
+
+type digestLine struct {
+	at   time.Time
+	user string
+	text string
+}
+
+type digestSection struct {
+	channel  string
+	messages []digestLine
+	err      error
+}
+
+// DigestScheduler builds the digests configured under "digests" on their cron
+// schedules, posts them to their target channel and keeps the latest one of
+// each for the digests resource.
+type DigestScheduler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+	parent      context.Context
+	now         func() time.Time
+
+	mu      sync.Mutex
+	digests map[string]config.DigestConfig
+	latest  map[string]string
+	stop    context.CancelFunc
+}
+
+// NewDigestScheduler returns a scheduler whose jobs run until ctx ends.
+func NewDigestScheduler(ctx context.Context, apiProvider *provider.ApiProvider, logger *zap.Logger) *DigestScheduler {
+	return &DigestScheduler{
+		apiProvider: apiProvider,
+		logger:      logger,
+		parent:      ctx,
+		now:         time.Now,
+		digests:     make(map[string]config.DigestConfig),
+		latest:      make(map[string]string),
+	}
+}
+
+// Configure replaces the scheduled digests, stopping the jobs of the previous
+// configuration. Latest results are kept for digests that are still configured.
+func (ds *DigestScheduler) Configure(digests []config.DigestConfig) {
+	if ds == nil {
+		return
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.stop != nil {
+		ds.stop()
+	}
+	ctx, stop := context.WithCancel(ds.parent)
+	ds.stop = stop
+
+	configured := make(map[string]config.DigestConfig, len(digests))
+	for _, d := range digests {
+		configured[d.Name] = d
+	}
+	for name := range ds.latest {
+		if _, ok := configured[name]; !ok {
+			delete(ds.latest, name)
+		}
+	}
+	ds.digests = configured
+
+	for _, d := range digests {
+		schedule, err := cron.Parse(d.Schedule)
+		if err != nil {
+			// Config.Validate rejects these before they get here.
+			ds.logger.Error("Invalid digest schedule", zap.String("digest", d.Name), zap.Error(err))
+			continue
+		}
+		go ds.loop(ctx, d, schedule)
+	}
+}
+
+func (ds *DigestScheduler) loop(ctx context.Context, d config.DigestConfig, schedule *cron.Schedule) {
+	for {
+		next := schedule.Next(ds.now().In(ds.apiProvider.Config().Location()))
+		if next.IsZero() {
+			ds.logger.Warn("Digest schedule never fires", zap.String("digest", d.Name), zap.String("schedule", d.Schedule))
+			return
+		}
+		ds.logger.Debug("Next digest scheduled", zap.String("digest", d.Name), zap.Time("at", next))
+
+		timer := time.NewTimer(next.Sub(ds.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := ds.Run(ctx, d); err != nil {
+			ds.logger.Error("Failed to run digest", zap.String("digest", d.Name), zap.Error(err))
+		}
+	}
+}
+
+// Run builds digest d now, stores it as the latest and posts it to PostTo
+// when set.
+func (ds *DigestScheduler) Run(ctx context.Context, d config.DigestConfig) error {
+	digest, err := ds.generate(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	if d.PostTo != "" {
+		channel, err := ds.resolveChannel(d.PostTo)
+		if err != nil {
+			return fmt.Errorf("post_to: %w", err)
+		}
+		_, ts, err := ds.apiProvider.Slack().PostMessageContext(ctx, channel,
+			slack.MsgOptionText(digest, false),
+			slack.MsgOptionDisableLinkUnfurl(),
+		)
+		if err != nil {
+			return fmt.Errorf("post digest to %s: %w", d.PostTo, err)
+		}
+		ds.logger.Info("Digest posted",
+			zap.String("digest", d.Name),
+			zap.String("channel", channel),
+			zap.String("ts", ts),
+		)
+	}
+	return nil
+}
+
+// generate fetches the messages of the lookback window and stores the
+// formatted digest as the latest one.
+func (ds *DigestScheduler) generate(ctx context.Context, d config.DigestConfig) (string, error) {
+	if ready, err := ds.apiProvider.IsReady(); !ready {
+		return "", err
+	}
+
+	to := ds.now()
+	from := to.Add(-d.LookbackDuration())
+	usersMap := ds.apiProvider.ProvideUsersMap()
+
+	sections := make([]digestSection, 0, len(d.Channels))
+	for _, name := range d.Channels {
+		section := digestSection{channel: name}
+		section.messages, section.err = ds.fetchChannel(ctx, name, from, d.Limit(), usersMap.Users)
+		if section.err != nil {
+			ds.logger.Warn("Failed to fetch digest channel",
+				zap.String("digest", d.Name),
+				zap.String("channel", name),
+				zap.Error(section.err),
+			)
+		}
+		sections = append(sections, section)
+	}
+
+	digest := formatDigest(d.Name, from, to, ds.apiProvider.Config().Location(), sections)
+
+	ds.mu.Lock()
+	ds.latest[d.Name] = digest
+	ds.mu.Unlock()
+	return digest, nil
+}
+
+func (ds *DigestScheduler) fetchChannel(ctx context.Context, name string, from time.Time, limit int, users map[string]slack.User) ([]digestLine, error) {
+	channel, err := ds.resolveChannel(name)
+	if err != nil {
+		return nil, err
+	}
+	history, err := ds.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Oldest:    strconv.FormatInt(from.Unix(), 10) + ".000000",
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]digestLine, 0, len(history.Messages))
+	for _, msg := range history.Messages {
+		if msg.SubType != "" && !slices.Contains(digestSubtypes, msg.SubType) {
+			continue
+		}
+		at, err := text.SlackTimestampToTime(msg.Timestamp)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, digestLine{at: at, user: digestAuthor(msg, users), text: text.ProcessText(msg.Text)})
+	}
+	// History is newest first; digests read top to bottom.
+	slices.Reverse(lines)
+	return lines, nil
+}
+
+// resolveChannel turns a #channel or @user name into an ID and checks it
+// against the channel policy.
+func (ds *DigestScheduler) resolveChannel(channel string) (string, error) {
+	if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+		id, ok := ds.apiProvider.ProvideChannelsMaps().ChannelsInv[channel]
+		if !ok {
+			return "", ds.apiProvider.ChannelNotFound(channel, "")
+		}
+		channel = id
+	}
+	if err := ds.apiProvider.CheckChannel(channel); err != nil {
+		return "", err
+	}
+	return channel, nil
+}
+
+func digestAuthor(msg slack.Message, users map[string]slack.User) string {
+	if u, ok := users[msg.User]; ok {
+		if u.Profile.DisplayName != "" {
+			return u.Profile.DisplayName
+		}
+		return u.Name
+	}
+	if msg.Username != "" {
+		return msg.Username
+	}
+	if msg.BotProfile != nil && msg.BotProfile.Name != "" {
+		return msg.BotProfile.Name
+	}
+	if msg.User != "" {
+		return msg.User
+	}
+	return msg.BotID
+}
+
+// formatDigest renders sections as Slack mrkdwn. Message texts are plain, so
+// mentions in them do not notify anyone again when the digest is posted.
+func formatDigest(name string, from, to time.Time, loc *time.Location, sections []digestSection) string {
+	from, to = from.In(loc), to.In(loc)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*Digest %s* (%s to %s %s)\n",
+		name, from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"), loc.String())
+
+	for _, section := range sections {
+		sb.WriteString("\n")
+		switch {
+		case section.err != nil:
+			fmt.Fprintf(&sb, "*%s*: unavailable (%s)\n", section.channel, section.err)
+			continue
+		case len(section.messages) == 0:
+			fmt.Fprintf(&sb, "*%s*: no new messages\n", section.channel)
+			continue
+		case len(section.messages) == 1:
+			fmt.Fprintf(&sb, "*%s*: 1 message\n", section.channel)
+		default:
+			fmt.Fprintf(&sb, "*%s*: %d messages\n", section.channel, len(section.messages))
+		}
+		for _, line := range section.messages {
+			fmt.Fprintf(&sb, "• %s %s: %s\n", line.at.In(loc).Format("15:04"), line.user, digestSnippet(line.text))
+		}
+	}
+	return sb.String()
+}
+
+func digestSnippet(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > digestSnippetLength {
+		return string(r[:digestSnippetLength]) + "…"
+	}
+	return s
+}
+
+// DigestsResource serves the latest digest named by the last URI segment,
+// building it on demand when it has not run yet. On-demand digests are not
+// posted.
+func (ds *DigestScheduler) DigestsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ds.logger.Debug("DigestsResource called", zap.Any("params", request.Params))
+
+	if authenticated, err := auth.IsAuthenticated(ctx, ds.apiProvider.ServerTransport(), ds.logger); !authenticated {
+		ds.logger.Error("Authentication failed for digests resource", zap.Error(err))
+		return nil, err
+	}
+
+	name := request.Params.URI[strings.LastIndex(request.Params.URI, "/")+1:]
+	ds.mu.Lock()
+	d, configured := ds.digests[name]
+	digest, ok := ds.latest[name]
+	ds.mu.Unlock()
+	if !configured {
+		return nil, fmt.Errorf("digest %q is not configured", name)
+	}
+
+	if !ok {
+		var err error
+		if digest, err = ds.generate(ctx, d); err != nil {
+			return nil, fmt.Errorf("digest %q has not run yet and could not be built: %w", name, err)
+		}
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     digest,
+		},
+	}, nil
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestUnitFormatDigest(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	to := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+
+	got := formatDigest("daily", from, to, loc, []digestSection{
+		{channel: "#general", messages: []digestLine{
+			{at: to.Add(-2 * time.Hour), user: "alice", text: "Release is out\nplease   test"},
+			{at: to.Add(-time.Hour), user: "bob", text: strings.Repeat("x", digestSnippetLength+5)},
+		}},
+		{channel: "#random"},
+		{channel: "#secret", err: errors.New("channel not allowed")},
+	})
+
+	assert.Equal(t, "*Digest daily* (2025-01-14 09:00 to 2025-01-15 09:00 CET)\n"+
+		"\n*#general*: 2 messages\n"+
+		"• 07:00 alice: Release is out please test\n"+
+		"• 08:00 bob: "+strings.Repeat("x", digestSnippetLength)+"…\n"+
+		"\n*#random*: no new messages\n"+
+		"\n*#secret*: unavailable (channel not allowed)\n", got)
+}
+
+func TestUnitDigestSchedulerConfigure(t *testing.T) {
+	ds := NewDigestScheduler(t.Context(), nil, zap.NewNop())
+	ds.latest["old"] = "previous digest"
+	ds.latest["daily"] = "kept"
+
+	ds.Configure([]config.DigestConfig{{Name: "daily", Schedule: "0 0 30 2 *", Channels: []string{"#general"}}})
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	assert.Contains(t, ds.digests, "daily")
+	assert.Equal(t, map[string]string{"daily": "kept"}, ds.latest)
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDirectoryLimit = 100
+	maxDirectoryLimit     = 1000
+)
+
+// DirectoryUser is a directory_list row of the users directory: the columns
+// of the slack://<workspace>/users resource plus the pagination cursor.
+type DirectoryUser struct {
+	User
+	Cursor string
+}
+
+// DirectoryMetadata follows the directory_list rows.
+type DirectoryMetadata struct {
+	Kind       string `json:"kind"`
+	Total      int    `json:"total"`
+	Returned   int    `json:"returned"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Warming is set while the cache behind the directory is still being
+	// filled, so the list may be incomplete.
+	Warming bool `json:"warming,omitempty"`
+}
+
+type directoryParams struct {
+	kind         string
+	query        string
+	channelTypes []string
+	limit        int
+	offset       int
+}
+
+type DirectoryHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewDirectoryHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *DirectoryHandler {
+	return &DirectoryHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// DirectoryListHandler pages through the users or channels directory, the
+// data of the slack://<workspace>/users and slack://<workspace>/channels
+// resources, for clients that do not show resources. Rows are sorted by
+// name and filtered from the caches, so no Slack API call is made.
+func (dh *DirectoryHandler) DirectoryListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	dh.logger.Debug("DirectoryListHandler called", zap.Any("params", request.Params))
+
+	params, err := parseDirectoryParams(request)
+	if err != nil {
+		dh.logger.Error("Invalid directory_list params", zap.Error(err))
+		return nil, err
+	}
+
+	var (
+		csvBytes []byte
+		meta     = DirectoryMetadata{Kind: params.kind}
+	)
+	switch params.kind {
+	case "users":
+		rows := directoryUsers(dh.apiProvider.ProvideUsersMap().Users, params.query)
+		meta.Warming = dh.apiProvider.UsersWarming()
+		page, next := directoryPage(rows, params)
+		if next != "" {
+			page[len(page)-1].Cursor = next
+		}
+		meta.Total, meta.Returned, meta.NextCursor = len(rows), len(page), next
+		csvBytes, err = gocsv.MarshalBytes(&page)
+	case "channels":
+		rows := directoryChannels(dh.apiProvider.ProvideChannelsMaps().Channels, params.query, params.channelTypes, dh.apiProvider.ChannelAllowed)
+		meta.Warming = dh.apiProvider.ChannelsWarming()
+		page, next := directoryPage(rows, params)
+		if next != "" {
+			page[len(page)-1].Cursor = next
+		}
+		meta.Total, meta.Returned, meta.NextCursor = len(rows), len(page), next
+		csvBytes, err = gocsv.MarshalBytes(&page)
+	}
+	if err != nil {
+		dh.logger.Error("Failed to marshal directory to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), meta)
+}
+
+func parseDirectoryParams(request mcp.CallToolRequest) (directoryParams, error) {
+	params := directoryParams{
+		kind:  request.GetString("kind", ""),
+		query: strings.ToLower(strings.TrimPrefix(strings.TrimSpace(request.GetString("query", "")), "@")),
+		limit: request.GetInt("limit", defaultDirectoryLimit),
+	}
+	if params.kind != "users" && params.kind != "channels" {
+		return directoryParams{}, fmt.Errorf("kind must be users or channels, got %q", params.kind)
+	}
+	if params.limit < 1 || params.limit > maxDirectoryLimit {
+		return directoryParams{}, fmt.Errorf("limit must be between 1 and %d", maxDirectoryLimit)
+	}
+	if params.kind == "channels" {
+		params.query = strings.TrimPrefix(params.query, "#")
+		for _, t := range strings.Split(request.GetString("channel_types", ""), ",") {
+			t = strings.TrimSpace(t)
+			if t == "" {
+				continue
+			}
+			if !slices.Contains(provider.AllChanTypes, t) {
+				return directoryParams{}, fmt.Errorf("invalid channel type %q, allowed: %s", t, strings.Join(provider.AllChanTypes, ", "))
+			}
+			params.channelTypes = append(params.channelTypes, t)
+		}
+	}
+
+	if raw := request.GetString("cursor", ""); raw != "" {
+		inner, err := decodeCursor("directory_list", directoryCursorParams(params), raw)
+		if err != nil {
+			return directoryParams{}, err
+		}
+		if params.offset, err = strconv.Atoi(inner); err != nil || params.offset < 0 {
+			return directoryParams{}, fmt.Errorf("invalid cursor %q", raw)
+		}
+	}
+	return params, nil
+}
+
+func directoryCursorParams(params directoryParams) string {
+	return cursorParams(params.kind, params.query, strings.Join(params.channelTypes, ","))
+}
+
+// directoryPage cuts the page of rows the params ask for and returns the
+// cursor of the next page, empty on the last one.
+func directoryPage[T any](rows []T, params directoryParams) ([]T, string) {
+	start := min(params.offset, len(rows))
+	end := min(start+params.limit, len(rows))
+	next := ""
+	if end < len(rows) {
+		next = encodeCursor("directory_list", directoryCursorParams(params), strconv.Itoa(end))
+	}
+	return slices.Clone(rows[start:end]), next
+}
+
+// directoryUsers returns the users whose handle, real name or display name
+// contains query, sorted by handle.
+func directoryUsers(users map[string]slack.User, query string) []DirectoryUser {
+	ids := make([]string, 0, len(users))
+	for id, u := range users {
+		if query == "" ||
+			strings.Contains(strings.ToLower(u.Name), query) ||
+			strings.Contains(strings.ToLower(u.RealName), query) ||
+			strings.Contains(strings.ToLower(u.Profile.DisplayName), query) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := users[ids[i]], users[ids[j]]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+
+	rows := make([]DirectoryUser, 0, len(ids))
+	for _, id := range ids {
+		u := users[id]
+		rows = append(rows, DirectoryUser{User: User{
+			UserID:   u.ID,
+			UserName: u.Name,
+			RealName: u.RealName,
+			TZ:       u.TZ,
+			TZOffset: userTZOffset(u),
+			Locale:   u.Locale,
+		}})
+	}
+	return rows
+}
+
+// directoryChannels returns the channels of the given types, all when
+// empty, that the channel policy allows and whose name, topic or purpose
+// contains query, sorted by name.
+func directoryChannels(channels map[string]provider.Channel, query string, types []string, allowed func(string) bool) []Channel {
+	rows := make([]Channel, 0, len(channels))
+	for _, c := range channels {
+		if len(types) > 0 && !slices.Contains(types, directoryChannelType(c)) {
+			continue
+		}
+		if query != "" &&
+			!strings.Contains(strings.ToLower(c.Name), query) &&
+			!strings.Contains(strings.ToLower(c.Topic), query) &&
+			!strings.Contains(strings.ToLower(c.Purpose), query) {
+			continue
+		}
+		if !allowed(c.ID) {
+			continue
+		}
+		rows = append(rows, Channel{
+			ID:          c.ID,
+			Name:        c.Name,
+			Topic:       c.Topic,
+			Purpose:     c.Purpose,
+			MemberCount: c.MemberCount,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Name != rows[j].Name {
+			return rows[i].Name < rows[j].Name
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	return rows
+}
+
+func directoryChannelType(c provider.Channel) string {
+	switch {
+	case c.IsIM:
+		return "im"
+	case c.IsMpIM:
+		return "mpim"
+	case c.IsPrivate:
+		return provider.PrivateChanType
+	}
+	return provider.PubChanType
+}
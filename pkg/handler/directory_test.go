@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func directoryRequest(args map[string]any) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestUnitDirectoryUsers(t *testing.T) {
+	users := map[string]slack.User{
+		"U3": {ID: "U3", Name: "carol", RealName: "Carol Diaz"},
+		"U1": {ID: "U1", Name: "alice", RealName: "Alice Park", Profile: slack.UserProfile{DisplayName: "ally"}},
+		"U2": {ID: "U2", Name: "bob", RealName: "Bob Kim"},
+	}
+	rows := directoryUsers(users, "")
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"U1", "U2", "U3"}, []string{rows[0].UserID, rows[1].UserID, rows[2].UserID}, "sorted by handle")
+
+	assert.Len(t, directoryUsers(users, "ally"), 1, "display names match")
+	assert.Len(t, directoryUsers(users, "kim"), 1, "real names match")
+
+	csv, err := gocsv.MarshalString(&rows)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(csv, "UserID,UserName,RealName,TZ,TZOffset,Locale,WorkingHours,Cursor\n"), csv)
+}
+
+func TestUnitDirectoryChannels(t *testing.T) {
+	channels := map[string]provider.Channel{
+		"C2": {ID: "C2", Name: "#support", Purpose: "Customer escalations"},
+		"C1": {ID: "C1", Name: "#general"},
+		"G1": {ID: "G1", Name: "#ops-private", IsPrivate: true},
+		"D1": {ID: "D1", Name: "@alice", IsIM: true, User: "U1"},
+	}
+	all := func(string) bool { return true }
+
+	rows := directoryChannels(channels, "", nil, all)
+	assert.Equal(t, []string{"#general", "#ops-private", "#support", "@alice"}, []string{rows[0].Name, rows[1].Name, rows[2].Name, rows[3].Name})
+
+	rows = directoryChannels(channels, "", []string{"public_channel", "private_channel"}, all)
+	assert.Len(t, rows, 3)
+
+	rows = directoryChannels(channels, "escalation", nil, all)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "C2", rows[0].ID, "purposes match")
+
+	rows = directoryChannels(channels, "", nil, func(id string) bool { return id != "G1" })
+	assert.Len(t, rows, 3, "the channel policy applies")
+}
+
+func TestUnitDirectoryPagination(t *testing.T) {
+	rows := []Channel{{ID: "C1"}, {ID: "C2"}, {ID: "C3"}, {ID: "C4"}, {ID: "C5"}}
+
+	params, err := parseDirectoryParams(directoryRequest(map[string]any{"kind": "channels", "limit": 2}))
+	require.NoError(t, err)
+	page, next := directoryPage(rows, params)
+	assert.Equal(t, []Channel{{ID: "C1"}, {ID: "C2"}}, page)
+	require.NotEmpty(t, next)
+
+	params, err = parseDirectoryParams(directoryRequest(map[string]any{"kind": "channels", "limit": 2, "cursor": next}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, params.offset)
+
+	params.offset = 4
+	page, next = directoryPage(rows, params)
+	assert.Equal(t, []Channel{{ID: "C5"}}, page)
+	assert.Empty(t, next, "last page")
+
+	_, err = parseDirectoryParams(directoryRequest(map[string]any{"kind": "channels", "query": "support", "cursor": encodeCursor("directory_list", cursorParams("channels", "", ""), "2")}))
+	assert.ErrorContains(t, err, "different filters")
+
+	_, err = parseDirectoryParams(directoryRequest(map[string]any{"kind": "files"}))
+	assert.ErrorContains(t, err, "kind must be users or channels")
+	_, err = parseDirectoryParams(directoryRequest(map[string]any{"kind": "channels", "channel_types": "dm"}))
+	assert.ErrorContains(t, err, "invalid channel type")
+}
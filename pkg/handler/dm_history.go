@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// defaultDMHistoryLimit is the history conversations_dm_history returns
+// when the call sets no limit or window; DMs are quieter than channels.
+const defaultDMHistoryLimit = "1w"
+
+// ConversationsDMHistoryHandler returns the recent messages of the DM with
+// one user, resolving the DM itself, so "my recent conversation with Alice"
+// is one call instead of channels_list followed by conversations_history.
+// Every other argument is that of conversations_history.
+func (ch *ConversationsHandler) ConversationsDMHistoryHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsDMHistoryHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	users, err := ch.parseDMUsers(request.GetString("user", ""))
+	if err != nil {
+		ch.logger.Error("Invalid user for DM history", zap.Error(err))
+		return nil, err
+	}
+	if len(users) != 1 {
+		return nil, errors.New("user must name exactly one user; for a group DM use conversations_history with its channel ID")
+	}
+
+	channel, err := ch.dmChannel(ctx, users[0])
+	if err != nil {
+		return nil, err
+	}
+
+	args := maps.Clone(request.GetArguments())
+	delete(args, "user")
+	args["channel_id"] = channel
+	if args["limit"] == nil && args["cursor"] == nil && args["oldest"] == nil && args["latest"] == nil {
+		args["limit"] = defaultDMHistoryLimit
+	}
+	request.Params.Arguments = args
+	return ch.ConversationsHistoryHandler(ctx, request)
+}
+
+// dmChannel returns the ID of the DM with user: from the channels cache, or
+// from conversations.open when the cache does not know it yet.
+func (ch *ConversationsHandler) dmChannel(ctx context.Context, user string) (string, error) {
+	if id := findDMChannel(ch.apiProvider.ProvideChannelsMaps().Channels, user); id != "" {
+		return id, nil
+	}
+
+	channel, _, _, err := ch.apiProvider.Slack().OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users:    []string{user},
+		ReturnIM: true,
+	})
+	if err != nil {
+		ch.logger.Error("Slack OpenConversationContext failed", zap.String("user", user), zap.Error(err))
+		return "", fmt.Errorf("no DM with user %s found: %w", user, err)
+	}
+	if channel == nil || channel.ID == "" {
+		return "", fmt.Errorf("no DM with user %s found", user)
+	}
+	channel.IsIM = true
+	if channel.User == "" {
+		channel.User = user
+	}
+	ch.apiProvider.RememberChannel(*channel)
+	return channel.ID, nil
+}
+
+// findDMChannel returns the ID of the cached DM with user, empty when there
+// is none.
+func findDMChannel(channels map[string]provider.Channel, user string) string {
+	for id, c := range channels {
+		if c.IsIM && c.User == user {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitFindDMChannel(t *testing.T) {
+	channels := map[string]provider.Channel{
+		"C0123ABCD": {ID: "C0123ABCD", Name: "#general"},
+		"G0123ABCD": {ID: "G0123ABCD", Name: "@mpdm-alice--bob-1", IsMpIM: true, Members: []string{"U0ALICE", "U0BOB"}},
+		"D0123ABCD": {ID: "D0123ABCD", Name: "@alice", IsIM: true, User: "U0ALICE"},
+	}
+	assert.Equal(t, "D0123ABCD", findDMChannel(channels, "U0ALICE"))
+	assert.Empty(t, findDMChannel(channels, "U0BOB"), "group DMs are not the DM with a user")
+	assert.Empty(t, findDMChannel(nil, "U0ALICE"))
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+)
+
+// dmPolicy restricts which direct messages the add-message tools may post to,
+// configured by SLACK_MCP_DM_ALLOWLIST: "none" blocks all IMs and MPIMs, a
+// comma separated list of user IDs or @handles allows only DMs whose other
+// members are all listed. Unset means no restriction.
+type dmPolicy struct {
+	blockAll bool
+	users    []string
+}
+
+func newDMPolicy(raw string) *dmPolicy {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	switch strings.ToLower(raw) {
+	case "none", "false", "0":
+		return &dmPolicy{blockAll: true}
+	}
+	p := &dmPolicy{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			p.users = append(p.users, item)
+		}
+	}
+	return p
+}
+
+// allows reports whether userID is on the allowlist, resolving @handles
+// through the users cache.
+func (p *dmPolicy) allows(userID string, usersInv map[string]string) bool {
+	for _, entry := range p.users {
+		if entry == userID {
+			return true
+		}
+		if handle, ok := strings.CutPrefix(entry, "@"); ok && usersInv[handle] == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// dmRecipients returns the users other than self who would receive a message
+// posted to channel, and whether channel is a DM at all. User IDs count as DMs
+// because chat.postMessage opens an IM for them.
+func dmRecipients(ap *provider.ApiProvider, channel, self string) ([]string, bool, error) {
+	if isSlackUserIDPrefix(channel) {
+		return []string{channel}, true, nil
+	}
+
+	c, cached := ap.ProvideChannelsMaps().Channels[channel]
+	switch {
+	case cached && c.IsIM:
+		return []string{c.User}, true, nil
+	case cached && c.IsMpIM:
+		if len(c.Members) == 0 {
+			return nil, true, fmt.Errorf("members of group DM %q are unknown", channel)
+		}
+		return slices.DeleteFunc(slices.Clone(c.Members), func(u string) bool { return u == self }), true, nil
+	case strings.HasPrefix(channel, "D"):
+		return nil, true, fmt.Errorf("recipient of DM %q is unknown", channel)
+	}
+	return nil, false, nil
+}
+
+// checkDMPolicy refuses posting to DMs outside SLACK_MCP_DM_ALLOWLIST. DMs
+// whose recipients cannot be determined are refused; a DM to yourself is
+// always allowed.
+func (ch *ConversationsHandler) checkDMPolicy(ctx context.Context, channel string) error {
+	policy := newDMPolicy(ch.apiProvider.Config().DMAllowlist)
+	if policy == nil {
+		return nil
+	}
+
+	self := ""
+	if ar, err := ch.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		self = ar.UserID
+	}
+
+	recipients, isDM, err := dmRecipients(ch.apiProvider, channel, self)
+	if !isDM {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("posting to DMs is restricted by SLACK_MCP_DM_ALLOWLIST: %w", err)
+	}
+	return policy.check(channel, recipients, self, ch.apiProvider.ProvideUsersMap().UsersInv)
+}
+
+// check refuses a DM named target with recipients unless all of them other
+// than self are allowed.
+func (p *dmPolicy) check(target string, recipients []string, self string, usersInv map[string]string) error {
+	if p.blockAll {
+		if len(recipients) == 1 && recipients[0] == self {
+			return nil
+		}
+		return fmt.Errorf("posting to DM %q is not allowed: SLACK_MCP_DM_ALLOWLIST blocks all DMs", target)
+	}
+
+	for _, u := range recipients {
+		if u != self && !p.allows(u, usersInv) {
+			return fmt.Errorf("posting to DM %q is not allowed: user %s is not in SLACK_MCP_DM_ALLOWLIST", target, u)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitNewDMPolicy(t *testing.T) {
+	assert.Nil(t, newDMPolicy(" "))
+
+	p := newDMPolicy("none")
+	require.NotNil(t, p)
+	assert.True(t, p.blockAll)
+
+	p = newDMPolicy("U0123456789, @alice,")
+	require.NotNil(t, p)
+	assert.False(t, p.blockAll)
+	assert.Equal(t, []string{"U0123456789", "@alice"}, p.users)
+}
+
+func TestUnitDMPolicyAllows(t *testing.T) {
+	p := &dmPolicy{users: []string{"U0123456789", "@alice"}}
+	usersInv := map[string]string{"alice": "U0ALICE0001", "ceo": "U0CEO000001"}
+
+	assert.True(t, p.allows("U0123456789", usersInv))
+	assert.True(t, p.allows("U0ALICE0001", usersInv))
+	assert.False(t, p.allows("U0CEO000001", usersInv))
+	assert.False(t, p.allows("U0ALICE0001", nil), "handles need the users cache to resolve")
+}
+
+func TestUnitDMPolicyCheck(t *testing.T) {
+	usersInv := map[string]string{"alice": "U0ALICE0001"}
+
+	blockAll := &dmPolicy{blockAll: true}
+	assert.NoError(t, blockAll.check("D0SELF00001", []string{"U0SELF00001"}, "U0SELF00001", usersInv), "a DM to yourself is allowed")
+	assert.ErrorContains(t, blockAll.check("U0ALICE0001", []string{"U0ALICE0001"}, "U0SELF00001", usersInv), "blocks all DMs")
+
+	p := &dmPolicy{users: []string{"@alice"}}
+	assert.NoError(t, p.check("G0GROUP0001", []string{"U0ALICE0001", "U0SELF00001"}, "U0SELF00001", usersInv))
+	assert.ErrorContains(t, p.check("G0GROUP0001", []string{"U0ALICE0001", "U0CEO000001"}, "U0SELF00001", usersInv), "user U0CEO000001 is not in SLACK_MCP_DM_ALLOWLIST")
+}
@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	embeddingQueueSize   = 100
+	embeddingTimeout     = 30 * time.Second
+	embeddingMaxAttempts = 3
+)
+
+// EmbeddingRecord is one message as written to the embedding sink, one JSON
+// object per line. ID is stable, so re-fetching a message upserts it.
+type EmbeddingRecord struct {
+	ID       string            `json:"id"`
+	Text     string            `json:"text"`
+	Metadata EmbeddingMetadata `json:"metadata"`
+}
+
+// EmbeddingMetadata describes where an EmbeddingRecord comes from. Source is
+// the tool whose fetch emitted it.
+type EmbeddingMetadata struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name,omitempty"`
+	Ts          string `json:"ts"`
+	ThreadTs    string `json:"thread_ts,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	UserName    string `json:"user_name,omitempty"`
+	Time        string `json:"time"`
+	Source      string `json:"source"`
+}
+
+// EmbeddingSink receives the messages fetched by conversations_history,
+// conversations_replies and conversations_transcript, for building search
+// indexes over Slack content. Batches are appended to a JSONL file or posted
+// as NDJSON to an HTTP endpoint by a background worker, so a slow sink never
+// delays tool calls; batches that do not fit the queue are dropped and
+// counted.
+type EmbeddingSink struct {
+	target string
+	isHTTP bool
+	client *http.Client
+	logger *zap.Logger
+
+	retryBackoff time.Duration
+	queue        chan []EmbeddingRecord
+	pending      sync.WaitGroup // queued batches not yet written or given up on
+
+	mu              sync.Mutex // protects dropped, droppedReported
+	dropped         int
+	droppedReported time.Time
+}
+
+// NewEmbeddingSink returns nil when target, SLACK_MCP_EMBEDDING_SINK, is
+// empty. An http or https URL is posted to; anything else is a file path.
+func NewEmbeddingSink(target string, logger *zap.Logger) *EmbeddingSink {
+	if target == "" {
+		return nil
+	}
+	isHTTP := strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+	logger.Info("Embedding export enabled",
+		zap.String("context", "console"),
+		zap.Bool("http", isHTTP),
+	)
+	return &EmbeddingSink{
+		target:       target,
+		isHTTP:       isHTTP,
+		client:       &http.Client{Timeout: embeddingTimeout},
+		logger:       logger,
+		retryBackoff: time.Second,
+		queue:        make(chan []EmbeddingRecord, embeddingQueueSize),
+	}
+}
+
+type embeddingSinkKey struct{}
+
+// WithEmbeddingSink makes s available to the message-fetching handlers.
+func WithEmbeddingSink(ctx context.Context, s *EmbeddingSink) context.Context {
+	return context.WithValue(ctx, embeddingSinkKey{}, s)
+}
+
+func embeddingSinkFromContext(ctx context.Context) *EmbeddingSink {
+	s, _ := ctx.Value(embeddingSinkKey{}).(*EmbeddingSink)
+	return s
+}
+
+// Emit hands records to the worker without blocking.
+func (s *EmbeddingSink) Emit(records []EmbeddingRecord) {
+	if s == nil || len(records) == 0 {
+		return
+	}
+	s.pending.Add(1)
+	select {
+	case s.queue <- records:
+	default:
+		s.pending.Done()
+		s.mu.Lock()
+		s.dropped += len(records)
+		dropped := s.dropped
+		report := time.Since(s.droppedReported) > time.Minute
+		if report {
+			s.droppedReported = time.Now()
+		}
+		s.mu.Unlock()
+		if report {
+			s.logger.Warn("Embedding sink queue full, dropping messages", zap.Int("dropped_total", dropped))
+		}
+	}
+}
+
+// Run writes queued batches until ctx is cancelled.
+func (s *EmbeddingSink) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case batch := <-s.queue:
+			if err := s.write(ctx, batch); err != nil {
+				s.logger.Warn("Failed to export messages to the embedding sink",
+					zap.Int("messages", len(batch)),
+					zap.Error(err),
+				)
+			}
+			s.pending.Done()
+		}
+	}
+}
+
+// Flush waits until queued batches are written or ctx expires.
+func (s *EmbeddingSink) Flush(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (s *EmbeddingSink) write(ctx context.Context, batch []EmbeddingRecord) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range batch {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	if !s.isHTTP {
+		f, err := os.OpenFile(s.target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return s.post(ctx, buf.Bytes())
+}
+
+// post sends body, retrying network errors and 5xx/429 responses.
+func (s *EmbeddingSink) post(ctx context.Context, body []byte) error {
+	var lastErr error
+	for attempt := 1; attempt <= embeddingMaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.retryBackoff * time.Duration(attempt-1)):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("embedding sink returned %s", resp.Status)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// emitEmbeddings sends msgs of channel to the embedding sink, if one is set.
+// Channel events are skipped; text is rendered as Markdown with names
+// resolved, which reads better to embedding models than raw mrkdwn.
+func (ch *ConversationsHandler) emitEmbeddings(ctx context.Context, source, channel string, msgs []slack.Message) {
+	sink := embeddingSinkFromContext(ctx)
+	if sink == nil || len(msgs) == 0 {
+		return
+	}
+
+	var channelName string
+	if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channel]; ok {
+		channelName = c.Name
+	}
+	users := ch.apiProvider.ProvideUsersMap().Users
+	resolver := newMrkdwnResolver(ch.apiProvider)
+	sink.Emit(embeddingRecords(source, channel, channelName, msgs, users, resolver))
+}
+
+func embeddingRecords(source, channel, channelName string, msgs []slack.Message, users map[string]slack.User, resolver text.MrkdwnResolver) []EmbeddingRecord {
+	records := make([]EmbeddingRecord, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.SubType != "" && msg.SubType != "bot_message" && msg.SubType != "thread_broadcast" {
+			continue
+		}
+		body := strings.TrimSpace(text.MrkdwnToMarkdown(msg.Text+text.AttachmentsTo2CSV(msg.Text, msg.Attachments), resolver))
+		if body == "" {
+			continue
+		}
+		t, err := text.SlackTimestampToTime(msg.Timestamp)
+		if err != nil {
+			continue
+		}
+
+		userName, _, ok := getUserInfo(msg.User, users)
+		if !ok && msg.SubType == "bot_message" {
+			userName, _, _ = getBotInfo(msg.Username)
+		}
+		records = append(records, EmbeddingRecord{
+			ID:   channel + ":" + msg.Timestamp,
+			Text: body,
+			Metadata: EmbeddingMetadata{
+				ChannelID:   channel,
+				ChannelName: channelName,
+				Ts:          msg.Timestamp,
+				ThreadTs:    msg.ThreadTimestamp,
+				UserID:      msg.User,
+				UserName:    userName,
+				Time:        t.UTC().Format(time.RFC3339),
+				Source:      source,
+			},
+		})
+	}
+	return records
+}
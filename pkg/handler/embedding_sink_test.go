@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitEmbeddingRecords(t *testing.T) {
+	msg := func(user, subtype, ts, threadTs, body string) slack.Message {
+		var m slack.Message
+		m.User, m.SubType, m.Timestamp, m.ThreadTimestamp, m.Text = user, subtype, ts, threadTs, body
+		return m
+	}
+	users := map[string]slack.User{"U01": {ID: "U01", Name: "ana"}}
+	resolver := text.MrkdwnResolver{
+		User: func(id string) (string, bool) {
+			u, ok := users[id]
+			return u.Name, ok
+		},
+	}
+
+	records := embeddingRecords("conversations_history", "C01", "general", []slack.Message{
+		msg("U01", "", "1718000000.000100", "", "Deploy is *done*, thanks <@U01>"),
+		msg("U02", "channel_join", "1718000001.000100", "", "<@U02> has joined the channel"),
+		msg("U01", "", "1718000002.000100", "1718000000.000100", "   "),
+		msg("U02", "thread_broadcast", "1718000003.000100", "1718000000.000100", "Confirmed"),
+	}, users, resolver)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, EmbeddingRecord{
+		ID:   "C01:1718000000.000100",
+		Text: "Deploy is **done**, thanks @ana",
+		Metadata: EmbeddingMetadata{
+			ChannelID:   "C01",
+			ChannelName: "general",
+			Ts:          "1718000000.000100",
+			UserID:      "U01",
+			UserName:    "ana",
+			Time:        "2024-06-10T06:13:20Z",
+			Source:      "conversations_history",
+		},
+	}, records[0])
+	assert.Equal(t, "1718000000.000100", records[1].Metadata.ThreadTs)
+	assert.Equal(t, "U02", records[1].Metadata.UserID)
+	assert.Equal(t, "U02", records[1].Metadata.UserName, "unknown users keep their ID")
+}
+
+func TestUnitEmbeddingSinkFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slack.jsonl")
+	sink := NewEmbeddingSink(path, zap.NewNop())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sink.Run(ctx)
+
+	sink.Emit([]EmbeddingRecord{{ID: "C01:1", Text: "one"}, {ID: "C01:2", Text: "two"}})
+	sink.Emit([]EmbeddingRecord{{ID: "C02:3", Text: "three"}})
+	sink.Emit(nil)
+	sink.Flush(ctx)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r EmbeddingRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"C01:1", "C01:2", "C02:3"}, ids)
+
+	assert.Nil(t, NewEmbeddingSink("", zap.NewNop()))
+}
@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFilesLimit = 20
+	maxFilesLimit     = 100
+	// maxFilePreviewRunes caps the text preview files_info returns.
+	maxFilePreviewRunes = 1000
+)
+
+var validFileTypes = map[string]struct{}{
+	"all":      {},
+	"spaces":   {},
+	"snippets": {},
+	"images":   {},
+	"gdocs":    {},
+	"zips":     {},
+	"pdfs":     {},
+}
+
+// FileRow is the CSV output row for files_list.
+type FileRow struct {
+	ID           string `csv:"id"`
+	Name         string `csv:"name"`
+	Title        string `csv:"title"`
+	Type         string `csv:"type"`
+	Mimetype     string `csv:"mimetype"`
+	Size         int    `csv:"size"`
+	UploaderID   string `csv:"uploaderID"`
+	UploaderName string `csv:"uploaderName"`
+	Created      string `csv:"created"`
+	Channels     string `csv:"channels"`
+	Permalink    string `csv:"permalink"`
+	Cursor       string `csv:"cursor"`
+}
+
+// FileInfo is the files_info result: what is known about a file without
+// downloading it.
+type FileInfo struct {
+	ID           string      `json:"file_id"`
+	Name         string      `json:"name"`
+	Title        string      `json:"title,omitempty"`
+	Type         string      `json:"type"`
+	PrettyType   string      `json:"pretty_type,omitempty"`
+	Mimetype     string      `json:"mimetype"`
+	Size         int         `json:"size"`
+	UploaderID   string      `json:"uploader_id"`
+	UploaderName string      `json:"uploader_name"`
+	Created      string      `json:"created"`
+	IsPublic     bool        `json:"is_public"`
+	Shares       []FileShare `json:"shares"`
+	Permalink    string      `json:"permalink"`
+	// Downloadable is set when attachment_get_data can fetch the file, which
+	// it cannot above 5MB.
+	Downloadable bool `json:"downloadable"`
+	// Preview is the start of a text file, as Slack keeps it.
+	Preview          string `json:"preview,omitempty"`
+	PreviewTruncated bool   `json:"preview_truncated,omitempty"`
+	Lines            int    `json:"lines,omitempty"`
+}
+
+// FileShare is a message a file was shared in.
+type FileShare struct {
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name,omitempty"`
+	Ts          string `json:"ts"`
+	ThreadTs    string `json:"thread_ts,omitempty"`
+	ReplyCount  int    `json:"reply_count,omitempty"`
+}
+
+type FilesHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewFilesHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *FilesHandler {
+	return &FilesHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// FilesListHandler lists files shared in a channel and/or by a user, newest
+// first, so their IDs can be passed to attachment_get_data.
+func (h *FilesHandler) FilesListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("FilesListHandler called", zap.Any("params", request.Params))
+
+	params, err := h.parseFilesListParams(request)
+	if err != nil {
+		h.logger.Error("Failed to parse files_list params", zap.Error(err))
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	files, paging, err := h.apiProvider.Slack().GetFilesContext(ctx, params)
+	if err != nil {
+		h.logger.Error("Slack GetFilesContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	users := h.apiProvider.ProvideUsersMap().Users
+	rows := make([]FileRow, 0, len(files))
+	for _, f := range files {
+		shared := slices.Concat(f.Channels, f.Groups, f.IMs)
+		if h.apiProvider.ChannelPolicy() != nil && !slices.ContainsFunc(shared, h.apiProvider.ChannelAllowed) {
+			continue
+		}
+		uploaderName, _, _ := getUserInfo(f.User, users)
+		created, _ := text.FormatUnix(int64(f.Created), loc)
+		rows = append(rows, FileRow{
+			ID:           f.ID,
+			Name:         f.Name,
+			Title:        f.Title,
+			Type:         f.Filetype,
+			Mimetype:     f.Mimetype,
+			Size:         f.Size,
+			UploaderID:   f.User,
+			UploaderName: uploaderName,
+			Created:      created,
+			Channels:     strings.Join(shared, ","),
+			Permalink:    f.Permalink,
+		})
+	}
+
+	if len(rows) > 0 && paging != nil && paging.Page < paging.Pages {
+		rows[len(rows)-1].Cursor = encodeCursor("files_list", filesCursorParams(params), strconv.Itoa(paging.Page+1))
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		h.logger.Error("Failed to marshal files to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+func (h *FilesHandler) parseFilesListParams(request mcp.CallToolRequest) (slack.GetFilesParameters, error) {
+	params := slack.GetFilesParameters{Page: 1}
+
+	params.Count = request.GetInt("limit", defaultFilesLimit)
+	if params.Count < 1 || params.Count > maxFilesLimit {
+		return params, fmt.Errorf("limit must be between 1 and %d, got %d", maxFilesLimit, params.Count)
+	}
+
+	if channel := strings.TrimSpace(request.GetString("channel_id", "")); channel != "" {
+		if strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@") {
+			id, ok := h.apiProvider.ProvideChannelsMaps().ChannelsInv[channel]
+			if !ok {
+				return params, h.apiProvider.ChannelNotFound(channel, "")
+			}
+			channel = id
+		}
+		if err := h.apiProvider.CheckChannel(channel); err != nil {
+			return params, err
+		}
+		params.Channel = channel
+	}
+
+	if user := strings.TrimSpace(request.GetString("user", "")); user != "" {
+		user = strings.TrimSuffix(strings.TrimPrefix(user, "<@"), ">")
+		if !isSlackUserIDPrefix(user) {
+			uid, ok := h.apiProvider.ProvideUsersMap().UsersInv[strings.TrimPrefix(user, "@")]
+			if !ok {
+				return params, fmt.Errorf("user %q not found", user)
+			}
+			user = uid
+		}
+		params.User = user
+	}
+
+	if since := strings.TrimSpace(request.GetString("since", "")); since != "" {
+		day, _, err := parseFlexibleDate(since)
+		if err != nil {
+			return params, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		params.TimestampFrom = slack.JSONTime(day.Unix())
+	}
+	if until := strings.TrimSpace(request.GetString("until", "")); until != "" {
+		day, _, err := parseFlexibleDate(until)
+		if err != nil {
+			return params, fmt.Errorf("invalid until %q: %w", until, err)
+		}
+		// until is inclusive of the whole day.
+		params.TimestampTo = slack.JSONTime(day.AddDate(0, 0, 1).Unix() - 1)
+	}
+	if params.TimestampFrom != 0 && params.TimestampTo != 0 && params.TimestampFrom > params.TimestampTo {
+		return params, errors.New("since must not be after until")
+	}
+
+	if types := strings.TrimSpace(request.GetString("types", "")); types != "" {
+		var normalized []string
+		for _, t := range strings.Split(types, ",") {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" {
+				continue
+			}
+			if _, ok := validFileTypes[t]; !ok {
+				return params, fmt.Errorf("invalid file type %q: allowed values are all, spaces, snippets, images, gdocs, zips, pdfs", t)
+			}
+			normalized = append(normalized, t)
+		}
+		params.Types = strings.Join(normalized, ",")
+	}
+
+	if cursor := strings.TrimSpace(request.GetString("cursor", "")); cursor != "" {
+		inner, err := decodeCursor("files_list", filesCursorParams(params), cursor)
+		if err != nil {
+			return params, err
+		}
+		page, err := strconv.Atoi(inner)
+		if err != nil || page < 1 {
+			return params, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		params.Page = page
+	}
+
+	return params, nil
+}
+
+// filesCursorParams ties a files_list cursor to the filters of the call.
+func filesCursorParams(params slack.GetFilesParameters) string {
+	return cursorParams(params.Channel, params.User, params.Types,
+		strconv.Itoa(int(params.TimestampFrom)), strconv.Itoa(int(params.TimestampTo)))
+}
+
+// FilesInfoHandler returns the metadata of one file, with the start of text
+// files, so an agent can decide whether to download it with
+// attachment_get_data.
+func (h *FilesHandler) FilesInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("FilesInfoHandler called", zap.Any("params", request.Params))
+
+	fileID := strings.TrimSpace(request.GetString("file_id", ""))
+	if !strings.HasPrefix(fileID, "F") {
+		return nil, fmt.Errorf("file_id must be a Slack file ID in format Fxxxxxxxxxx, got %q", fileID)
+	}
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	f, _, _, err := h.apiProvider.Slack().GetFileInfoContext(ctx, fileID, 0, 0)
+	if err != nil {
+		h.logger.Error("Slack GetFileInfoContext failed", zap.String("file_id", fileID), zap.Error(err))
+		return nil, err
+	}
+	// Hidden as files_list hides it.
+	shared := slices.Concat(f.Channels, f.Groups, f.IMs)
+	if h.apiProvider.ChannelPolicy() != nil && !slices.ContainsFunc(shared, h.apiProvider.ChannelAllowed) {
+		return nil, fmt.Errorf("file %s is only shared in conversations the channel policy does not allow", fileID)
+	}
+
+	h.apiProvider.EnsureUsers(ctx, []string{f.User})
+	info := fileInfo(f, h.apiProvider.ProvideUsersMap().Users, h.apiProvider.ProvideChannelsMaps().Channels, loc)
+	out, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(out)), nil
+}
+
+func fileInfo(f *slack.File, users map[string]slack.User, channels map[string]provider.Channel, loc *time.Location) FileInfo {
+	uploaderName, _, _ := getUserInfo(f.User, users)
+	created, _ := text.FormatUnix(int64(f.Created), loc)
+	info := FileInfo{
+		ID:           f.ID,
+		Name:         f.Name,
+		Title:        f.Title,
+		Type:         f.Filetype,
+		PrettyType:   f.PrettyType,
+		Mimetype:     f.Mimetype,
+		Size:         f.Size,
+		UploaderID:   f.User,
+		UploaderName: uploaderName,
+		Created:      created,
+		IsPublic:     f.IsPublic,
+		Shares:       fileShares(f, channels),
+		Permalink:    f.Permalink,
+		Downloadable: f.Size <= maxFileSizeBytes,
+	}
+	if isTextMimetype(f.Mimetype) && f.Preview != "" {
+		info.Preview = f.Preview
+		info.PreviewTruncated = f.LinesMore > 0
+		if runes := []rune(f.Preview); len(runes) > maxFilePreviewRunes {
+			info.Preview = string(runes[:maxFilePreviewRunes])
+			info.PreviewTruncated = true
+		}
+		info.Lines = f.Lines
+	}
+	return info
+}
+
+// fileShares lists the messages f was shared in, by channel and time. Files
+// shared before Slack reported shares only list their conversations.
+func fileShares(f *slack.File, channels map[string]provider.Channel) []FileShare {
+	shares := []FileShare{}
+	seen := make(map[string]bool)
+	for _, byChannel := range []map[string][]slack.ShareFileInfo{f.Shares.Public, f.Shares.Private} {
+		for id, infos := range byChannel {
+			seen[id] = true
+			for _, s := range infos {
+				shares = append(shares, FileShare{
+					ChannelID:   id,
+					ChannelName: cmp.Or(s.ChannelName, channels[id].Name),
+					Ts:          s.Ts,
+					ThreadTs:    s.ThreadTs,
+					ReplyCount:  s.ReplyCount,
+				})
+			}
+		}
+	}
+	for _, id := range slices.Concat(f.Channels, f.Groups, f.IMs) {
+		if !seen[id] {
+			seen[id] = true
+			shares = append(shares, FileShare{ChannelID: id, ChannelName: channels[id].Name})
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].ChannelID != shares[j].ChannelID {
+			return shares[i].ChannelID < shares[j].ChannelID
+		}
+		return slackTsLess(shares[i].Ts, shares[j].Ts)
+	})
+	return shares
+}
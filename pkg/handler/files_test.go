@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitParseFilesListParams(t *testing.T) {
+	h := NewFilesHandler(nil, zap.NewNop())
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	params, err := h.parseFilesListParams(req(map[string]any{
+		"channel_id": "C123",
+		"user":       "<@U123>",
+		"since":      "2025-01-01",
+		"until":      "2025-01-31",
+		"types":      "Images, pdfs",
+		"cursor":     "3",
+		"limit":      float64(50),
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "C123", params.Channel)
+	assert.Equal(t, "U123", params.User)
+	assert.Equal(t, slack.JSONTime(1735689600), params.TimestampFrom)
+	assert.Equal(t, slack.JSONTime(1738367999), params.TimestampTo)
+	assert.Equal(t, "images,pdfs", params.Types)
+	assert.Equal(t, 3, params.Page)
+	assert.Equal(t, 50, params.Count)
+
+	params, err = h.parseFilesListParams(req(nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1, params.Page)
+	assert.Equal(t, defaultFilesLimit, params.Count)
+
+	for name, args := range map[string]map[string]any{
+		"limit":  {"limit": float64(500)},
+		"types":  {"types": "videos"},
+		"cursor": {"cursor": "abc"},
+		"range":  {"since": "2025-02-01", "until": "2025-01-01"},
+	} {
+		_, err := h.parseFilesListParams(req(args))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestUnitFileInfo(t *testing.T) {
+	f := &slack.File{
+		ID:        "F123",
+		Name:      "deploy.log",
+		Filetype:  "text",
+		Mimetype:  "text/plain",
+		Size:      48213,
+		User:      "U123",
+		Created:   slack.JSONTime(1717405964),
+		Channels:  []string{"C2", "C1"},
+		Permalink: "https://example.slack.com/files/U123/F123/deploy.log",
+		Preview:   "starting deploy",
+		Lines:     812,
+		LinesMore: 810,
+		Shares: slack.Share{Public: map[string][]slack.ShareFileInfo{
+			"C1": {{Ts: "1717405999.000100"}, {Ts: "1717405964.000200", ReplyCount: 3}},
+		}},
+	}
+	users := map[string]slack.User{"U123": {ID: "U123", Name: "ana"}}
+	channels := map[string]provider.Channel{"C1": {ID: "C1", Name: "#eng"}, "C2": {ID: "C2", Name: "#ops"}}
+
+	info := fileInfo(f, users, channels, time.UTC)
+	assert.Equal(t, "ana", info.UploaderName)
+	assert.Equal(t, "2024-06-03T09:12:44Z", info.Created)
+	assert.True(t, info.Downloadable)
+	assert.Equal(t, "starting deploy", info.Preview)
+	assert.True(t, info.PreviewTruncated)
+	assert.Equal(t, 812, info.Lines)
+	assert.Equal(t, []FileShare{
+		{ChannelID: "C1", ChannelName: "#eng", Ts: "1717405964.000200", ReplyCount: 3},
+		{ChannelID: "C1", ChannelName: "#eng", Ts: "1717405999.000100"},
+		{ChannelID: "C2", ChannelName: "#ops"},
+	}, info.Shares)
+
+	f.Mimetype = "application/pdf"
+	f.Size = 6 << 20
+	info = fileInfo(f, users, channels, time.UTC)
+	assert.Empty(t, info.Preview)
+	assert.False(t, info.Downloadable)
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	forwardFormatQuote      = "quote"
+	forwardFormatAttachment = "attachment"
+)
+
+// ForwardedMessage is the CSV output row for conversations_forward_message.
+type ForwardedMessage struct {
+	ChannelID       string `csv:"channelID"`
+	MsgID           string `csv:"msgID"`
+	ThreadTs        string `csv:"threadTs"`
+	SourceChannelID string `csv:"sourceChannelID"`
+	SourceMsgID     string `csv:"sourceMsgID"`
+	Permalink       string `csv:"permalink"`
+}
+
+// forwardedQuote is what a forward says about the source message.
+type forwardedQuote struct {
+	channel   string
+	author    string
+	avatarURL string
+	ts        string
+	time      string
+	permalink string
+	text      string
+}
+
+// ConversationsForwardMessageHandler posts a quote of one message into
+// another conversation, crediting the author and linking back to the
+// original. Posting is subject to SLACK_MCP_ADD_MESSAGE_TOOL like
+// conversations_add_message.
+func (ch *ConversationsHandler) ConversationsForwardMessageHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsForwardMessageHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.addMessageToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(request.GetString("format", forwardFormatQuote)))
+	if format != forwardFormatQuote && format != forwardFormatAttachment {
+		return nil, fmt.Errorf("format must be %q or %q, got %q", forwardFormatQuote, forwardFormatAttachment, format)
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	source := request.GetString("channel_id", "")
+	if source == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	if source, err = ch.resolveChannelID(ctx, source); err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", source), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(source); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", source), zap.Error(err))
+		return nil, err
+	}
+	ts := strings.TrimSpace(request.GetString("message_ts", ""))
+	if ts == "" {
+		return nil, errors.New("message_ts is required")
+	}
+	if !strings.Contains(ts, ".") {
+		return nil, fmt.Errorf("message_ts must be a Slack timestamp in format 1234567890.123456, got %q", ts)
+	}
+
+	target := request.GetString("target_channel_id", "")
+	if target == "" {
+		return nil, errors.New("target_channel_id is required")
+	}
+	if target, err = ch.resolveChannelID(ctx, target); err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", target), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.checkAddMessageTarget(ctx, target, toolConfig); err != nil {
+		return nil, err
+	}
+	threadTs := strings.TrimSpace(request.GetString("thread_ts", ""))
+	if threadTs != "" && !strings.Contains(threadTs, ".") {
+		return nil, fmt.Errorf("thread_ts must be a Slack timestamp in format 1234567890.123456, got %q", threadTs)
+	}
+
+	msg, err := ch.fetchMessage(ctx, source, ts)
+	if err != nil {
+		return nil, err
+	}
+	quote := ch.forwardedQuote(ctx, source, *msg, loc)
+
+	params := &addMessageParams{
+		channel:     target,
+		threadTs:    threadTs,
+		contentType: contentTypeSlackMrkdwn,
+	}
+	comment := strings.TrimSpace(request.GetString("comment", ""))
+	if format == forwardFormatAttachment {
+		params.text = comment
+		params.attachments = []slack.Attachment{quote.attachment()}
+	} else {
+		params.text = quote.mrkdwn(comment)
+	}
+
+	respChannel, respTimestamp, err := ch.postMessage(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	csvBytes, err := gocsv.MarshalBytes([]ForwardedMessage{{
+		ChannelID:       respChannel,
+		MsgID:           respTimestamp,
+		ThreadTs:        threadTs,
+		SourceChannelID: source,
+		SourceMsgID:     ts,
+		Permalink:       quote.permalink,
+	}})
+	if err != nil {
+		ch.logger.Error("Failed to marshal forwarded message to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// fetchMessage returns the message at ts in channel, looking among thread
+// replies when it is not a top-level message.
+func (ch *ConversationsHandler) fetchMessage(ctx context.Context, channel, ts string) (*slack.Message, error) {
+	history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Oldest:    ts,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     1,
+	})
+	if err != nil {
+		ch.logger.Error("GetConversationHistoryContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if len(history.Messages) > 0 && history.Messages[0].Timestamp == ts {
+		return &history.Messages[0], nil
+	}
+
+	replies, _, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channel,
+		Timestamp: ts,
+		Oldest:    ts,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     2,
+	})
+	if err != nil && slackErrorCode(err) != "thread_not_found" {
+		ch.logger.Error("GetConversationRepliesContext failed", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	for i := range replies {
+		if replies[i].Timestamp == ts {
+			return &replies[i], nil
+		}
+	}
+	return nil, fmt.Errorf("message %s not found in channel %s", ts, channel)
+}
+
+func (ch *ConversationsHandler) forwardedQuote(ctx context.Context, channel string, msg slack.Message, loc *time.Location) forwardedQuote {
+	users := ch.apiProvider.ProvideUsersMap().Users
+	q := forwardedQuote{
+		channel: channel,
+		author:  digestAuthor(msg, users),
+		ts:      msg.Timestamp,
+		text:    msg.Text,
+	}
+	if u, ok := users[msg.User]; ok {
+		q.avatarURL = userAvatarURL(u.Profile)
+	} else if msg.BotProfile != nil {
+		q.avatarURL = msg.BotProfile.Icons.Image72
+	}
+	if _, human, err := text.FormatSlackTimestamp(msg.Timestamp, loc); err == nil {
+		q.time = human
+	}
+	if q.text == "" {
+		for _, f := range msg.Files {
+			q.text = strings.TrimPrefix(q.text+"\n(file: "+f.Name+")", "\n")
+		}
+	}
+	if ar, err := ch.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		q.permalink = messagePermalink(strings.TrimRight(ar.URL, "/"), channel, msg.Timestamp, msg.ThreadTimestamp)
+	} else {
+		ch.logger.Warn("Failed to get workspace URL for permalink", zap.Error(err))
+	}
+	return q
+}
+
+// messagePermalink builds the link Slack's "Copy link" gives for a message;
+// replies carry their thread so the link opens it.
+func messagePermalink(workspaceURL, channel, ts, threadTs string) string {
+	if workspaceURL == "" || channel == "" || ts == "" {
+		return ""
+	}
+	link := workspaceURL + "/archives/" + channel + "/p" + strings.ReplaceAll(ts, ".", "")
+	if threadTs != "" && threadTs != ts {
+		link += "?thread_ts=" + threadTs + "&cid=" + channel
+	}
+	return link
+}
+
+// mrkdwn renders the quote as a block quote under an optional comment:
+//
+//	comment
+//	> *author* in <#C…> · <permalink|time>
+//	> text
+func (q forwardedQuote) mrkdwn(comment string) string {
+	var sb strings.Builder
+	if comment != "" {
+		sb.WriteString(comment)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("> *" + q.author + "* in <#" + q.channel + ">")
+	switch {
+	case q.permalink != "" && q.time != "":
+		sb.WriteString(" · <" + q.permalink + "|" + q.time + ">")
+	case q.permalink != "":
+		sb.WriteString(" · <" + q.permalink + "|original message>")
+	case q.time != "":
+		sb.WriteString(" · " + q.time)
+	}
+	for _, line := range strings.Split(q.text, "\n") {
+		sb.WriteString("\n> " + line)
+	}
+	return sb.String()
+}
+
+// attachment renders the quote as a legacy attachment, which Slack shows as
+// a card with the author's avatar and the original time in the footer.
+func (q forwardedQuote) attachment() slack.Attachment {
+	return slack.Attachment{
+		Fallback:   q.author + ": " + q.text,
+		AuthorName: q.author,
+		AuthorIcon: q.avatarURL,
+		AuthorLink: q.permalink,
+		Text:       q.text,
+		Footer:     "<#" + q.channel + ">",
+		Ts:         json.Number(q.ts),
+		MarkdownIn: []string{"text", "footer"},
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultAroundCount is how many messages conversations_history returns
+	// on each side of around_ts when around_count is not given.
+	defaultAroundCount = 5
+	// maxAroundCount caps around_count so each side fits one history page.
+	maxAroundCount = 100
+)
+
+// parseAroundParams reads around_ts and around_count. around_ts is empty when
+// the call is a regular history request.
+func parseAroundParams(request mcp.CallToolRequest) (string, int, error) {
+	aroundTs := strings.TrimSpace(request.GetString("around_ts", ""))
+	if aroundTs == "" {
+		return "", 0, nil
+	}
+	if !strings.Contains(aroundTs, ".") {
+		return "", 0, fmt.Errorf("around_ts %q must be a message timestamp such as 1234567890.123456", aroundTs)
+	}
+	for _, p := range []string{"cursor", "oldest", "latest"} {
+		if request.GetString(p, "") != "" {
+			return "", 0, fmt.Errorf("around_ts cannot be combined with %s", p)
+		}
+	}
+	count := request.GetInt("around_count", defaultAroundCount)
+	if count < 1 || count > maxAroundCount {
+		return "", 0, fmt.Errorf("around_count must be between 1 and %d, got %d", maxAroundCount, count)
+	}
+	return aroundTs, count, nil
+}
+
+// fetchAround returns the message at aroundTs with up to count messages
+// before and after it, using one bounded history call for each side.
+func (ch *ConversationsHandler) fetchAround(ctx context.Context, channel, aroundTs string, count int) ([]slack.Message, error) {
+	before, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Latest:    aroundTs,
+		Inclusive: true,
+		Limit:     count + 1,
+
+		IncludeAllMetadata: true,
+	})
+	if err != nil {
+		ch.logger.Error("GetConversationHistoryContext failed", zap.String("side", "before"), zap.Error(err))
+		return nil, err
+	}
+	after, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Oldest:    aroundTs,
+		Inclusive: false,
+		Limit:     count,
+
+		IncludeAllMetadata: true,
+	})
+	if err != nil {
+		ch.logger.Error("GetConversationHistoryContext failed", zap.String("side", "after"), zap.Error(err))
+		return nil, err
+	}
+
+	messages := mergeAround(aroundTs, before.Messages, after.Messages, count)
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no messages found around %s in %s; for a thread reply use conversations_replies with the thread_ts of its parent", aroundTs, channel)
+	}
+	return messages, nil
+}
+
+// mergeAround joins the two sides of an around_ts window: at most count
+// messages before aroundTs, the message itself, and the count messages
+// closest to it after. The result is newest first, like the rest of
+// conversations_history, and has no duplicates.
+func mergeAround(aroundTs string, before, after []slack.Message, count int) []slack.Message {
+	byTs := make(map[string]slack.Message, len(before)+len(after))
+	var older, newer []string
+	for _, m := range before {
+		if _, dup := byTs[m.Timestamp]; dup || slackTsLess(aroundTs, m.Timestamp) {
+			continue
+		}
+		byTs[m.Timestamp] = m
+		older = append(older, m.Timestamp)
+	}
+	for _, m := range after {
+		if _, dup := byTs[m.Timestamp]; dup || !slackTsLess(aroundTs, m.Timestamp) {
+			continue
+		}
+		byTs[m.Timestamp] = m
+		newer = append(newer, m.Timestamp)
+	}
+
+	sort.Slice(older, func(i, j int) bool { return slackTsLess(older[j], older[i]) })
+	limit := count
+	if len(older) > 0 && older[0] == aroundTs {
+		limit++
+	}
+	older = older[:min(len(older), limit)]
+	sort.Slice(newer, func(i, j int) bool { return slackTsLess(newer[i], newer[j]) })
+	newer = newer[:min(len(newer), count)]
+
+	messages := make([]slack.Message, 0, len(older)+len(newer))
+	for i := len(newer) - 1; i >= 0; i-- {
+		messages = append(messages, byTs[newer[i]])
+	}
+	for _, ts := range older {
+		messages = append(messages, byTs[ts])
+	}
+	return messages
+}
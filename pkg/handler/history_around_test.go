@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitMergeAround(t *testing.T) {
+	msg := func(ts string) slack.Message {
+		return slack.Message{Msg: slack.Msg{Timestamp: ts}}
+	}
+	timestamps := func(messages []slack.Message) []string {
+		var out []string
+		for _, m := range messages {
+			out = append(out, m.Timestamp)
+		}
+		return out
+	}
+
+	before := []slack.Message{msg("100.000005"), msg("100.000004"), msg("100.000003"), msg("100.000002")}
+	after := []slack.Message{msg("100.000009"), msg("100.000008"), msg("100.000007"), msg("100.000006"), msg("100.000005")}
+	assert.Equal(t,
+		[]string{"100.000007", "100.000006", "100.000005", "100.000004", "100.000003"},
+		timestamps(mergeAround("100.000005", before, after, 2)),
+		"newest first, closest messages on each side, no duplicates",
+	)
+
+	assert.Equal(t,
+		[]string{"100.000006", "100.000004"},
+		timestamps(mergeAround("100.000005", before[1:], after, 1)),
+		"a missing message still gets its neighbours",
+	)
+	assert.Empty(t, mergeAround("100.000005", nil, nil, 5))
+}
+
+func TestUnitParseAroundParams(t *testing.T) {
+	req := func(args map[string]any) mcp.CallToolRequest {
+		var r mcp.CallToolRequest
+		r.Params.Arguments = args
+		return r
+	}
+
+	ts, count, err := parseAroundParams(req(map[string]any{"limit": "1d"}))
+	require.NoError(t, err)
+	assert.Empty(t, ts)
+	assert.Zero(t, count)
+
+	ts, count, err = parseAroundParams(req(map[string]any{"around_ts": " 1700000000.000100 "}))
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000.000100", ts)
+	assert.Equal(t, defaultAroundCount, count)
+
+	_, count, err = parseAroundParams(req(map[string]any{"around_ts": "1700000000.000100", "around_count": float64(20)}))
+	require.NoError(t, err)
+	assert.Equal(t, 20, count)
+
+	_, _, err = parseAroundParams(req(map[string]any{"around_ts": "yesterday"}))
+	assert.ErrorContains(t, err, "must be a message timestamp")
+
+	_, _, err = parseAroundParams(req(map[string]any{"around_ts": "1700000000.000100", "cursor": "abc"}))
+	assert.ErrorContains(t, err, "cannot be combined with cursor")
+
+	_, _, err = parseAroundParams(req(map[string]any{"around_ts": "1700000000.000100", "around_count": float64(101)}))
+	assert.ErrorContains(t, err, "between 1 and 100")
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// maxHuddleInvitees caps the people and user groups one huddles_start call
+// mentions.
+const maxHuddleInvitees = 30
+
+// usergroupIDRe matches user group IDs, so that handles starting with S,
+// such as "sam", stay users.
+var usergroupIDRe = regexp.MustCompile(`^S[A-Z0-9]{8,}$`)
+
+// HuddleStarted is the JSON result of huddles_start.
+type HuddleStarted struct {
+	ChannelID string   `json:"channel_id"`
+	HuddleURL string   `json:"huddle_url"`
+	Invited   []string `json:"invited,omitempty"`
+	// MessageTs is the invitation message, empty when nobody was invited.
+	MessageTs string `json:"message_ts,omitempty"`
+}
+
+// HuddlesStartHandler returns the link of a channel's or DM's huddle and
+// invites people to it by posting the link with them mentioned. It does not
+// use the calls.* or huddle endpoints: calls.add only registers calls of
+// third-party apps and Slack has no API that opens a huddle, so the huddle
+// starts when the first person follows the link and everyone else joins the
+// same one.
+func (ch *ConversationsHandler) HuddlesStartHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("HuddlesStartHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.addMessageToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.checkAddMessageTarget(ctx, channel, toolConfig); err != nil {
+		return nil, err
+	}
+
+	var invitees []string
+	if raw := strings.TrimSpace(request.GetString("invite", "")); raw != "" {
+		if invitees, err = parseHuddleInvitees(raw, ch.apiProvider.ProvideUsersMap().UsersInv); err != nil {
+			return nil, err
+		}
+	}
+
+	ar, err := ch.apiProvider.Slack().AuthTestContext(ctx)
+	if err != nil {
+		ch.logger.Error("AuthTestContext failed", zap.Error(err))
+		return nil, err
+	}
+	result := HuddleStarted{
+		ChannelID: channel,
+		HuddleURL: huddleURL(ar.TeamID, channel),
+	}
+
+	if len(invitees) > 0 {
+		_, ts, err := ch.postMessage(ctx, &addMessageParams{
+			channel:     channel,
+			text:        huddleInvitation(result.HuddleURL, invitees, request.GetString("message", "")),
+			contentType: contentTypeSlackMrkdwn,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Invited, result.MessageTs = invitees, ts
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseHuddleInvitees resolves a comma separated list of user IDs, @handles
+// and user group IDs (S…) into the mentions of the invitation, looking
+// handles up in usersInv.
+func parseHuddleInvitees(raw string, usersInv map[string]string) ([]string, error) {
+	var mentions []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(item), "<@"), ">")
+		var mention string
+		switch {
+		case item == "":
+			continue
+		case usergroupIDRe.MatchString(item):
+			mention = "<!subteam^" + item + ">"
+		case isSlackUserIDPrefix(item):
+			mention = "<@" + item + ">"
+		default:
+			id, ok := usersInv[strings.TrimPrefix(item, "@")]
+			if !ok {
+				return nil, fmt.Errorf("user %q not found", item)
+			}
+			mention = "<@" + id + ">"
+		}
+		if !slices.Contains(mentions, mention) {
+			mentions = append(mentions, mention)
+		}
+	}
+	if len(mentions) > maxHuddleInvitees {
+		return nil, fmt.Errorf("invite must list at most %d users or user groups, got %d", maxHuddleInvitees, len(mentions))
+	}
+	return mentions, nil
+}
+
+// huddleURL is the link that starts or joins the huddle of a conversation.
+func huddleURL(teamID, channel string) string {
+	return "https://app.slack.com/huddle/" + teamID + "/" + channel
+}
+
+// huddleInvitation is the message inviting mentions to the huddle at url,
+// with an optional note, e.g. what the huddle is about.
+func huddleInvitation(url string, mentions []string, note string) string {
+	text := ":headphones: " + strings.Join(mentions, " ") + " please join the huddle: <" + url + "|Join huddle>"
+	if note = strings.TrimSpace(note); note != "" {
+		text += "\n" + note
+	}
+	return text
+}
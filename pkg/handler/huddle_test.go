@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitHuddleInvitation(t *testing.T) {
+	url := huddleURL("T0123ABCD", "C0123ABCD")
+	assert.Equal(t, "https://app.slack.com/huddle/T0123ABCD/C0123ABCD", url)
+
+	assert.Equal(t,
+		":headphones: <@U01> <!subteam^S01> please join the huddle: <https://app.slack.com/huddle/T0123ABCD/C0123ABCD|Join huddle>",
+		huddleInvitation(url, []string{"<@U01>", "<!subteam^S01>"}, "  "),
+	)
+	assert.Equal(t,
+		":headphones: <@U01> please join the huddle: <https://app.slack.com/huddle/T0123ABCD/C0123ABCD|Join huddle>\nCheckout is down",
+		huddleInvitation(url, []string{"<@U01>"}, "Checkout is down"),
+	)
+}
+
+func TestUnitParseHuddleInvitees(t *testing.T) {
+	usersInv := map[string]string{"Sam": "U0SAM0001", "sarah.k": "U0SARAH01"}
+
+	mentions, err := parseHuddleInvitees("S0614TZR7, <@U0123ABCD>, @Sam, sarah.k, Sam", usersInv)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"<!subteam^S0614TZR7>", "<@U0123ABCD>", "<@U0SAM0001>", "<@U0SARAH01>"}, mentions,
+		"handles starting with S are users, not user groups")
+
+	_, err = parseHuddleInvitees("Sandra", usersInv)
+	assert.ErrorContains(t, err, `user "Sandra" not found`)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxInviteEmails caps one conversations_invite call; each email costs a
+// users.lookupByEmail and a conversations.invite call.
+const maxInviteEmails = 50
+
+// InviteResult is the CSV output row for conversations_invite, one per email.
+type InviteResult struct {
+	Email    string `csv:"email"`
+	UserID   string `csv:"userID"`
+	UserName string `csv:"userName"`
+	// Status is invited, already_in_channel, not_found or failed.
+	Status string `csv:"status"`
+	Error  string `csv:"error"`
+}
+
+// ConversationsInviteHandler resolves emails to workspace members and invites
+// them to a channel one by one, so one unknown address does not fail the rest.
+func (ch *ConversationsHandler) ConversationsInviteHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsInviteHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.inviteToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if !isChannelAllowedForConfig(channel, toolConfig) {
+		ch.logger.Warn("Invite tool not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return nil, fmt.Errorf("conversations_invite tool is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+
+	emails, err := parseInviteEmails(request.GetString("emails", ""))
+	if err != nil {
+		ch.logger.Error("Invalid emails for invite", zap.Error(err))
+		return nil, err
+	}
+
+	results := make([]InviteResult, 0, len(emails))
+	for _, email := range emails {
+		results = append(results, ch.inviteByEmail(ctx, channel, email))
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&results)
+	if err != nil {
+		ch.logger.Error("Failed to marshal invite results to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+func (ch *ConversationsHandler) inviteByEmail(ctx context.Context, channel, email string) InviteResult {
+	row := InviteResult{Email: email}
+
+	user, err := ch.apiProvider.Slack().GetUserByEmailContext(ctx, email)
+	if err != nil {
+		if slackErrorCode(err) == "users_not_found" {
+			row.Status = "not_found"
+			return row
+		}
+		ch.logger.Warn("users.lookupByEmail failed", zap.String("email", email), zap.Error(err))
+		row.Status, row.Error = "failed", err.Error()
+		return row
+	}
+	row.UserID, row.UserName = user.ID, user.Name
+
+	if _, err := ch.apiProvider.Slack().InviteUsersToConversationContext(ctx, channel, user.ID); err != nil {
+		if slackErrorCode(err) == "already_in_channel" {
+			row.Status = "already_in_channel"
+			return row
+		}
+		ch.logger.Warn("conversations.invite failed", zap.String("channel", channel), zap.String("user", user.ID), zap.Error(err))
+		row.Status, row.Error = "failed", err.Error()
+		return row
+	}
+	row.Status = "invited"
+	return row
+}
+
+// inviteToolPolicy returns the SLACK_MCP_INVITE_TOOL channel policy, or an
+// error when inviting is not enabled.
+func (ch *ConversationsHandler) inviteToolPolicy() (string, error) {
+	toolConfig := ch.apiProvider.Config().InviteTool
+	if toolConfig == "" {
+		if !ch.toolExplicitlyEnabled("conversations_invite") {
+			ch.logger.Error("Invite tool disabled by default")
+			return "", errors.New(
+				"by default, the conversations_invite tool is disabled. " +
+					"To enable it, set the SLACK_MCP_INVITE_TOOL environment variable to true, 1, or comma separated list of channels " +
+					"to limit which channels the MCP can invite people to, e.g. 'SLACK_MCP_INVITE_TOOL=C1234567890' or 'SLACK_MCP_INVITE_TOOL=!C1234567890' " +
+					"to enable all except one",
+			)
+		}
+		toolConfig = "true"
+	}
+	return toolConfig, nil
+}
+
+// parseInviteEmails splits a comma, semicolon or whitespace separated list of
+// addresses, accepting "Name <addr>" forms and dropping duplicates.
+func parseInviteEmails(raw string) ([]string, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+
+	seen := make(map[string]bool, len(fields))
+	var emails []string
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email %q", f)
+		}
+		email := strings.ToLower(addr.Address)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+
+	if len(emails) == 0 {
+		return nil, errors.New("emails must list at least one email address")
+	}
+	if len(emails) > maxInviteEmails {
+		return nil, fmt.Errorf("too many emails: %d, at most %d per call", len(emails), maxInviteEmails)
+	}
+	return emails, nil
+}
+
+// slackErrorCode returns the Slack API error code of err, e.g. "users_not_found".
+func slackErrorCode(err error) string {
+	var slackErr slack.SlackErrorResponse
+	if errors.As(err, &slackErr) {
+		return slackErr.Err
+	}
+	return err.Error()
+}
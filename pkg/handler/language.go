@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// languageUndetermined is the language column of rows DetectLanguage cannot
+// tell, the ISO 639-2 code for undetermined.
+const languageUndetermined = "und"
+
+var languageCodeRe = regexp.MustCompile(`^[a-z]{2}$`)
+
+// parseLanguageFilter returns the codes of filter_language, lower-cased.
+func parseLanguageFilter(request mcp.CallToolRequest) ([]string, error) {
+	var codes []string
+	for _, code := range strings.Split(request.GetString("filter_language", ""), ",") {
+		code = strings.ToLower(strings.TrimSpace(code))
+		if code == "" {
+			continue
+		}
+		if code != languageUndetermined && !languageCodeRe.MatchString(code) {
+			return nil, fmt.Errorf("invalid filter_language %q: use ISO 639-1 codes such as en or ja, or und for messages whose language cannot be told", code)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// detectLanguages fills the language column with include_language or
+// filter_language, and with filter_language keeps only the rows in one of
+// its languages. Detection is local, so it costs no Slack calls.
+func detectLanguages(request mcp.CallToolRequest, messages []Message) ([]Message, error) {
+	filter, err := parseLanguageFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	if len(filter) == 0 && !request.GetBool("include_language", false) {
+		return messages, nil
+	}
+
+	kept := messages[:0]
+	for _, m := range messages {
+		m.Language = text.DetectLanguage(m.Text)
+		if m.Language == "" {
+			m.Language = languageUndetermined
+		}
+		if len(filter) > 0 && !slices.Contains(filter, m.Language) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept, nil
+}
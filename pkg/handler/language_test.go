@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitDetectLanguages(t *testing.T) {
+	rows := func() []Message {
+		return []Message{
+			{MsgID: "1", Text: "The deploy is failing on staging, can you take a look?"},
+			{MsgID: "2", Text: "デプロイが失敗しています。確認してもらえますか？"},
+			{MsgID: "3", Text: ":+1:"},
+		}
+	}
+	request := func(args map[string]any) mcp.CallToolRequest {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		return req
+	}
+
+	unchanged, err := detectLanguages(request(nil), rows())
+	require.NoError(t, err)
+	assert.Equal(t, rows(), unchanged)
+
+	all, err := detectLanguages(request(map[string]any{"include_language": true}), rows())
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{"en", "ja", "und"}, []string{all[0].Language, all[1].Language, all[2].Language})
+
+	ja, err := detectLanguages(request(map[string]any{"filter_language": "JA"}), rows())
+	require.NoError(t, err)
+	require.Len(t, ja, 1)
+	assert.Equal(t, "2", ja[0].MsgID)
+
+	enOrUnknown, err := detectLanguages(request(map[string]any{"filter_language": "en, und"}), rows())
+	require.NoError(t, err)
+	require.Len(t, enOrUnknown, 2)
+	assert.Equal(t, "3", enOrUnknown[1].MsgID)
+
+	_, err = detectLanguages(request(map[string]any{"filter_language": "english"}), rows())
+	assert.ErrorContains(t, err, `invalid filter_language "english"`)
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMentionsLimit = 20
+	maxMentionsLimit     = 100
+	mentionSnippetLength = 280
+)
+
+// Mention is the CSV output row for activity_mentions.
+type Mention struct {
+	MsgID       string `csv:"msgID"`
+	Time        string `csv:"time"`
+	TimeHuman   string `csv:"timeHuman"`
+	ChannelID   string `csv:"channelID"`
+	ChannelName string `csv:"channelName"`
+	UserID      string `csv:"userID"`
+	UserName    string `csv:"userName"`
+	RealName    string `csv:"realName"`
+	ThreadTs    string `csv:"threadTs"`
+	Kind        string `csv:"kind"`
+	Snippet     string `csv:"snippet"`
+	Permalink   string `csv:"permalink"`
+}
+
+type MentionsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewMentionsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *MentionsHandler {
+	return &MentionsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// MentionsHandler returns recent messages that mention the authenticated user
+// and, optionally, direct messages sent to them, newest first.
+func (h *MentionsHandler) MentionsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("MentionsHandler called", zap.Any("params", request.Params))
+
+	limit := request.GetInt("limit", defaultMentionsLimit)
+	if limit < 1 || limit > maxMentionsLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxMentionsLimit, limit)
+	}
+	includeDMs := request.GetBool("include_dms", true)
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var dateFilter string
+	if since := strings.TrimSpace(request.GetString("since", "")); since != "" {
+		day, _, err := parseFlexibleDate(since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since %q: %w", since, err)
+		}
+		// after: excludes the given day itself.
+		dateFilter = " after:" + day.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+
+	ar, err := h.apiProvider.Slack().AuthTestContext(ctx)
+	if err != nil {
+		h.logger.Error("Slack AuthTest failed", zap.Error(err))
+		return nil, err
+	}
+
+	queries := []struct{ kind, query string }{
+		{"mention", "<@" + ar.UserID + ">" + dateFilter},
+	}
+	if includeDMs {
+		queries = append(queries, struct{ kind, query string }{"dm", "to:<@" + ar.UserID + ">" + dateFilter})
+	}
+
+	seen := make(map[string]bool)
+	var mentions []Mention
+	for _, q := range queries {
+		matches, err := h.search(ctx, q.query, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			key := m.Channel.ID + "/" + m.Timestamp
+			if seen[key] || m.User == ar.UserID || !h.apiProvider.ChannelAllowed(m.Channel.ID) {
+				continue
+			}
+			seen[key] = true
+			mentions = append(mentions, h.toMention(m, q.kind, loc))
+		}
+	}
+
+	sort.SliceStable(mentions, func(i, j int) bool {
+		return slackTsLess(mentions[j].MsgID, mentions[i].MsgID)
+	})
+	if len(mentions) > limit {
+		mentions = mentions[:limit]
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&mentions)
+	if err != nil {
+		h.logger.Error("Failed to marshal mentions to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+func (h *MentionsHandler) search(ctx context.Context, query string, limit int) ([]slack.SearchMessage, error) {
+	h.logger.Debug("Searching mentions", zap.String("query", query))
+	res, _, err := h.apiProvider.Slack().SearchContext(ctx, query, slack.SearchParameters{
+		Sort:          "timestamp",
+		SortDirection: "desc",
+		Count:         limit,
+		Page:          1,
+	})
+	if err != nil {
+		h.logger.Error("Slack SearchContext failed", zap.String("query", query), zap.Error(err))
+		return nil, err
+	}
+	return res.Matches, nil
+}
+
+func (h *MentionsHandler) toMention(m slack.SearchMessage, kind string, loc *time.Location) Mention {
+	userName, realName, ok := getUserInfo(m.User, h.apiProvider.ProvideUsersMap().Users)
+	if !ok && m.User == "" && m.Username != "" {
+		userName, realName, _ = getBotInfo(m.Username)
+	}
+
+	channelName := m.Channel.Name
+	if c, ok := h.apiProvider.ProvideChannelsMaps().Channels[m.Channel.ID]; ok {
+		channelName = c.Name
+	} else if channelName != "" && !strings.HasPrefix(channelName, "#") && !strings.HasPrefix(channelName, "@") {
+		channelName = "#" + channelName
+	}
+
+	threadTs, _ := extractThreadTS(m.Permalink)
+	timestamp, timeHuman, err := text.FormatSlackTimestamp(m.Timestamp, loc)
+	if err != nil {
+		timestamp = m.Timestamp
+	}
+
+	snippet := text.ProcessText(m.Text)
+	if r := []rune(snippet); len(r) > mentionSnippetLength {
+		snippet = string(r[:mentionSnippetLength]) + "…"
+	}
+
+	return Mention{
+		MsgID:       m.Timestamp,
+		Time:        timestamp,
+		TimeHuman:   timeHuman,
+		ChannelID:   m.Channel.ID,
+		ChannelName: channelName,
+		UserID:      m.User,
+		UserName:    userName,
+		RealName:    realName,
+		ThreadTs:    threadTs,
+		Kind:        kind,
+		Snippet:     snippet,
+		Permalink:   m.Permalink,
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxMessageAttachments caps the files and links one message attaches; Slack
+// shows at most 10 files per message.
+const maxMessageAttachments = 10
+
+// parseMessageAttachments resolves the attach argument of
+// conversations_add_message, a comma separated list of existing Slack file
+// IDs (F…) and http(s) URLs, into the links posted below the text. Slack
+// renders a file's permalink as the file itself, so a report uploaded once
+// can be shared again without uploading it anew.
+func (ch *ConversationsHandler) parseMessageAttachments(ctx context.Context, raw string) ([]string, error) {
+	unfurling := ch.apiProvider.Config().AddMessageUnfurling
+	var links []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		var (
+			link string
+			err  error
+		)
+		if strings.Contains(item, "://") {
+			link, err = attachmentURL(item, unfurling)
+		} else {
+			link, err = ch.attachmentFile(ctx, item)
+		}
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	if len(links) > maxMessageAttachments {
+		return nil, fmt.Errorf("attach must list at most %d files or URLs, got %d", maxMessageAttachments, len(links))
+	}
+	return links, nil
+}
+
+// attachmentFile returns the permalink of a Slack file, provided the channel
+// policy allows at least one conversation it is shared in.
+func (ch *ConversationsHandler) attachmentFile(ctx context.Context, fileID string) (string, error) {
+	if !strings.HasPrefix(fileID, "F") {
+		return "", fmt.Errorf("invalid attachment %q: use a Slack file ID (F…) or an http(s) URL", fileID)
+	}
+	file, _, _, err := ch.apiProvider.Slack().GetFileInfoContext(ctx, fileID, 0, 0)
+	if err != nil {
+		ch.logger.Error("Slack GetFileInfoContext failed", zap.String("file_id", fileID), zap.Error(err))
+		return "", fmt.Errorf("file %s: %w", fileID, err)
+	}
+	if !ch.fileAllowed(file) {
+		ch.logger.Warn("Attachment rejected by channel policy", zap.String("file_id", fileID))
+		return "", fmt.Errorf("file %s is only shared in conversations the channel policy does not allow", fileID)
+	}
+	if file.Permalink == "" {
+		return "", fmt.Errorf("file %s has no permalink to share", fileID)
+	}
+	return file.Permalink, nil
+}
+
+// fileAllowed reports whether a file may be shared on: it is not shared
+// anywhere yet, or shared in at least one conversation the channel policy
+// allows.
+func (ch *ConversationsHandler) fileAllowed(file *slack.File) bool {
+	shared := append(append(append([]string{}, file.Channels...), file.Groups...), file.IMs...)
+	if len(shared) == 0 {
+		return true
+	}
+	for _, c := range shared {
+		if ch.apiProvider.ChannelAllowed(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// attachmentURL checks an external link: it is attached as an unfurl, so its
+// host must pass unfurling, SLACK_MCP_ADD_MESSAGE_UNFURLING.
+func attachmentURL(raw, unfurling string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("invalid attachment %q: URLs must be absolute http or https URLs", raw)
+	}
+	if !text.IsUnfurlingEnabled(raw, unfurling, nil) {
+		return "", fmt.Errorf(
+			"attaching %s needs link unfurling for %s. "+
+				"To allow it, set the SLACK_MCP_ADD_MESSAGE_UNFURLING environment variable to true or to a comma separated list of domains including it",
+			raw, u.Hostname(),
+		)
+	}
+	return raw, nil
+}
+
+// withAttachments appends the attachment links to msgText, one per line.
+// Attaching unfurls every link of the message, so the text itself must
+// only link to hosts unfurling allows.
+func withAttachments(msgText string, links []string, unfurling string) (string, error) {
+	if len(links) == 0 {
+		return msgText, nil
+	}
+	if len(text.LinkHosts(msgText)) > 0 && !text.IsUnfurlingEnabled(msgText, unfurling, nil) {
+		return "", errors.New("attach unfurls every link of the message, but text links to hosts SLACK_MCP_ADD_MESSAGE_UNFURLING does not allow")
+	}
+	return strings.TrimSpace(msgText + "\n" + strings.Join(links, "\n")), nil
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitAttachmentURL(t *testing.T) {
+	link, err := attachmentURL("https://docs.example.com/q2", "example.com,docs.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "https://docs.example.com/q2", link)
+
+	_, err = attachmentURL("https://docs.example.com/q2", "true")
+	assert.NoError(t, err)
+
+	_, err = attachmentURL("https://evil.example.org/x", "docs.example.com")
+	assert.ErrorContains(t, err, "needs link unfurling for evil.example.org")
+	_, err = attachmentURL("https://docs.example.com/q2", "")
+	assert.ErrorContains(t, err, "SLACK_MCP_ADD_MESSAGE_UNFURLING", "unfurling is off by default")
+	_, err = attachmentURL("ftp://docs.example.com/q2", "true")
+	assert.ErrorContains(t, err, "absolute http or https")
+}
+
+func TestUnitWithAttachments(t *testing.T) {
+	msg, err := withAttachments("Q2 report", []string{"https://acme.slack.com/files/U1/F1/q2.pdf"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Q2 report\nhttps://acme.slack.com/files/U1/F1/q2.pdf", msg)
+
+	msg, err = withAttachments("", []string{"https://acme.slack.com/files/U1/F1/q2.pdf"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "https://acme.slack.com/files/U1/F1/q2.pdf", msg, "text may be empty")
+
+	msg, err = withAttachments("see https://evil.example.org", nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "see https://evil.example.org", msg, "nothing attached, nothing unfurled")
+
+	_, err = withAttachments("see https://evil.example.org", []string{"https://acme.slack.com/files/U1/F1/q2.pdf"}, "docs.example.com")
+	assert.ErrorContains(t, err, "unfurls every link of the message")
+}
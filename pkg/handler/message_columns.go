@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+
+	"github.com/gocarina/gocsv"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MessageColumns are the opt-in columns of Message a call asked for. The
+// others are left out of the CSV, so rows only grow with the flags set.
+type MessageColumns struct {
+	// Unfurls are the Unfurl* columns of include_unfurls.
+	Unfurls bool
+	// Metadata is the Metadata column of include_metadata.
+	Metadata bool
+	// Tombstones are the Tombstone and TombstoneTime columns of
+	// include_tombstones.
+	Tombstones bool
+	// Labels is the Labels column, written when a classifier is configured.
+	Labels bool
+	// Language is the Language column of include_language and
+	// filter_language.
+	Language bool
+}
+
+// messageColumnsOf returns the opt-in columns request asks for.
+func messageColumnsOf(ctx context.Context, request mcp.CallToolRequest) MessageColumns {
+	return MessageColumns{
+		Unfurls:    request.GetBool("include_unfurls", false),
+		Metadata:   request.GetBool("include_metadata", false),
+		Tombstones: request.GetBool("include_tombstones", false),
+		Labels:     classifierFromContext(ctx) != nil,
+		Language:   request.GetBool("include_language", false) || request.GetString("filter_language", "") != "",
+	}
+}
+
+// includes reports whether the column with the CSV header name is written.
+func (c MessageColumns) includes(name string) bool {
+	switch name {
+	case "UnfurlURLs", "UnfurlTitles", "UnfurlDescriptions", "UnfurlServices":
+		return c.Unfurls
+	case "Metadata":
+		return c.Metadata
+	case "Tombstone", "TombstoneTime":
+		return c.Tombstones
+	case "Labels":
+		return c.Labels
+	case "Language":
+		return c.Language
+	}
+	return true
+}
+
+// messageCSVWriter writes the columns of the header row that columns
+// includes, and the same columns of the rows after it.
+type messageCSVWriter struct {
+	*csv.Writer
+	columns MessageColumns
+	keep    []int
+}
+
+func (w *messageCSVWriter) Write(row []string) error {
+	if w.keep == nil {
+		w.keep = make([]int, 0, len(row))
+		for i, name := range row {
+			if w.columns.includes(name) {
+				w.keep = append(w.keep, i)
+			}
+		}
+	}
+	out := make([]string, len(w.keep))
+	for i, j := range w.keep {
+		out[i] = row[j]
+	}
+	return w.Writer.Write(out)
+}
+
+func marshalMessages(messages []Message, columns MessageColumns) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gocsv.MarshalCSV(&messages, &messageCSVWriter{Writer: csv.NewWriter(&buf), columns: columns}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitMarshalMessagesColumns(t *testing.T) {
+	messages := []Message{{
+		MsgID:      "1718000000.000100",
+		Text:       "see https://github.com/acme/api/pull/7",
+		UnfurlURLs: "https://github.com/acme/api/pull/7",
+		Metadata:   `{"event_type":"ticket_linked"}`,
+		Tombstone:  "edited",
+		Labels:     "needs_reply",
+		Language:   "en",
+		Cursor:     "c1",
+	}}
+	header := func(columns MessageColumns) string {
+		t.Helper()
+		res, err := MarshalMessagesToCSV(messages, columns)
+		require.NoError(t, err)
+		out := res.Content[0].(mcp.TextContent).Text
+		return out[:strings.Index(out, "\n")]
+	}
+
+	assert.Equal(t,
+		"MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,TimeHuman,Reactions,ReactionCounts,ReplyCount,IsEdited,BotName,FileCount,AttachmentIDs,HasMedia,Cursor",
+		header(MessageColumns{}), "opt-in columns are left out by default")
+	assert.Equal(t,
+		"MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,TimeHuman,Reactions,ReactionCounts,ReplyCount,IsEdited,BotName,FileCount,AttachmentIDs,HasMedia,UnfurlURLs,UnfurlTitles,UnfurlDescriptions,UnfurlServices,Language,Cursor",
+		header(MessageColumns{Unfurls: true, Language: true}))
+	assert.Equal(t,
+		"MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,TimeHuman,Reactions,ReactionCounts,ReplyCount,IsEdited,BotName,FileCount,AttachmentIDs,HasMedia,Metadata,Tombstone,TombstoneTime,Labels,Cursor",
+		header(MessageColumns{Metadata: true, Tombstones: true, Labels: true}))
+
+	res, err := MarshalMessagesToCSV(messages, MessageColumns{Metadata: true})
+	require.NoError(t, err)
+	assert.Contains(t, res.Content[0].(mcp.TextContent).Text, `,"{""event_type"":""ticket_linked""}",c1`+"\n", "rows keep the same columns as the header")
+}
+
+func TestUnitMessageColumnsOf(t *testing.T) {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = map[string]any{"include_unfurls": true, "filter_language": "ja"}
+	assert.Equal(t, MessageColumns{Unfurls: true, Language: true}, messageColumnsOf(context.Background(), req))
+
+	req.Params.Arguments = map[string]any{"include_metadata": true, "include_tombstones": true}
+	ctx := WithClassifier(context.Background(), &httpClassifier{})
+	assert.Equal(t, MessageColumns{Metadata: true, Tombstones: true, Labels: true}, messageColumnsOf(ctx, req), "labels follow the configured classifier")
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+)
+
+// metadataEventTypeRe follows Slack's convention for message metadata event
+// types: lowercase words joined by underscores, e.g. "ticket_linked".
+var metadataEventTypeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_.]{0,254}$`)
+
+// parseMessageMetadata reads metadata_event_type and metadata_event_payload.
+// The payload may be a JSON object or a string holding one, since some clients
+// cannot send nested objects.
+func parseMessageMetadata(request mcp.CallToolRequest) (*slack.SlackMetadata, error) {
+	eventType := request.GetString("metadata_event_type", "")
+	rawPayload, hasPayload := request.GetArguments()["metadata_event_payload"]
+	if eventType == "" {
+		if hasPayload && rawPayload != nil && rawPayload != "" {
+			return nil, errors.New("metadata_event_payload requires metadata_event_type")
+		}
+		return nil, nil
+	}
+	if !metadataEventTypeRe.MatchString(eventType) {
+		return nil, fmt.Errorf("metadata_event_type %q must be lowercase letters, digits and underscores, e.g. 'ticket_linked'", eventType)
+	}
+
+	payload := map[string]any{}
+	switch p := rawPayload.(type) {
+	case nil:
+	case map[string]any:
+		payload = p
+	case string:
+		if p != "" {
+			if err := json.Unmarshal([]byte(p), &payload); err != nil {
+				return nil, fmt.Errorf("metadata_event_payload must be a JSON object: %w", err)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("metadata_event_payload must be a JSON object, got %T", rawPayload)
+	}
+	return &slack.SlackMetadata{EventType: eventType, EventPayload: payload}, nil
+}
+
+// formatMessageMetadata renders the metadata of a fetched message as compact
+// JSON, or "" when it has none.
+func formatMessageMetadata(meta slack.SlackMetadata) string {
+	if meta.EventType == "" {
+		return ""
+	}
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
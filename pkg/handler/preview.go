@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register decoders for image.Decode
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	defaultPreviewMaxDimension = 768
+	minPreviewMaxDimension     = 32
+	maxPreviewMaxDimension     = 2048
+	// maxPreviewSourcePixels guards against decompression bombs: a small file
+	// can declare a huge canvas that would be allocated on decode.
+	maxPreviewSourcePixels = 40_000_000
+	previewJPEGQuality     = 80
+)
+
+// AttachmentPreview describes the image preview returned by
+// attachment_get_data with preview=true.
+type AttachmentPreview struct {
+	FileID           string `json:"file_id"`
+	Filename         string `json:"filename"`
+	Mimetype         string `json:"mimetype"`
+	Size             int    `json:"size"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	OriginalMimetype string `json:"original_mimetype"`
+	OriginalSize     int    `json:"original_size"`
+	OriginalWidth    int    `json:"original_width"`
+	OriginalHeight   int    `json:"original_height"`
+}
+
+// imagePreview is an image downscaled for attachment_get_data.
+type imagePreview struct {
+	data           []byte
+	mimeType       string
+	width, height  int
+	originalWidth  int
+	originalHeight int
+}
+
+// isPreviewableMimetype reports whether makeImagePreview can decode mimetype.
+func isPreviewableMimetype(mimetype string) bool {
+	switch mimetype {
+	case "image/png", "image/jpeg", "image/jpg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// makeImagePreview scales a PNG, JPEG or GIF down so that neither side exceeds
+// maxDim and re-encodes it as JPEG, flattening transparency onto white. Images
+// that already fit are returned unchanged. Only the first frame of an
+// animated GIF is kept.
+func makeImagePreview(data []byte, mimetype string, maxDim int) (*imagePreview, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s image: %w", mimetype, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, errors.New("image has no pixels")
+	}
+	if cfg.Width*cfg.Height > maxPreviewSourcePixels {
+		return nil, fmt.Errorf("image is %dx%d pixels, too large to preview", cfg.Width, cfg.Height)
+	}
+
+	if cfg.Width <= maxDim && cfg.Height <= maxDim {
+		return &imagePreview{
+			data:           data,
+			mimeType:       "image/" + format,
+			width:          cfg.Width,
+			height:         cfg.Height,
+			originalWidth:  cfg.Width,
+			originalHeight: cfg.Height,
+		}, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s image: %w", mimetype, err)
+	}
+	width, height := previewSize(cfg.Width, cfg.Height, maxDim)
+	dst := downscale(src, width, height)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: previewJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("cannot encode preview: %w", err)
+	}
+	return &imagePreview{
+		data:           buf.Bytes(),
+		mimeType:       "image/jpeg",
+		width:          width,
+		height:         height,
+		originalWidth:  cfg.Width,
+		originalHeight: cfg.Height,
+	}, nil
+}
+
+// previewSize fits w x h into a maxDim square, keeping the aspect ratio.
+func previewSize(w, h, maxDim int) (int, int) {
+	if w >= h {
+		return maxDim, max(1, (h*maxDim+w/2)/w)
+	}
+	return max(1, (w*maxDim+h/2)/h), maxDim
+}
+
+// downscale resizes src to width x height by averaging the source pixels
+// covered by each destination pixel, which keeps text in screenshots legible
+// where nearest-neighbour sampling would drop strokes.
+func downscale(src image.Image, width, height int) *image.RGBA {
+	b := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, b.Min, draw.Over)
+
+	sw, sh := b.Dx(), b.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := flat.Pix[sy*flat.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+3]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					n++
+				}
+			}
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
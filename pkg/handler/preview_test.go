@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, color.NRGBA{R: 0xff, A: 0xff})
+			} // right half stays transparent
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestUnitMakeImagePreview(t *testing.T) {
+	data := encodeTestPNG(t, 400, 200)
+
+	p, err := makeImagePreview(data, "image/png", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", p.mimeType)
+	assert.Equal(t, 100, p.width)
+	assert.Equal(t, 50, p.height)
+	assert.Equal(t, 400, p.originalWidth)
+	assert.Equal(t, 200, p.originalHeight)
+
+	img, err := jpeg.Decode(bytes.NewReader(p.data))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+	r, g, b, _ := img.At(10, 25).RGBA()
+	assert.Greater(t, r>>8, uint32(200), "left half stays red")
+	assert.Less(t, g>>8, uint32(60))
+	r, g, b, _ = img.At(90, 25).RGBA()
+	assert.Greater(t, min(r, g, b)>>8, uint32(200), "transparency is flattened onto white")
+
+	small, err := makeImagePreview(data, "image/png", 512)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", small.mimeType)
+	assert.Equal(t, data, small.data, "images within the limit are returned unchanged")
+
+	_, err = makeImagePreview([]byte("not an image"), "image/png", 100)
+	assert.ErrorContains(t, err, "cannot read image/png image")
+}
+
+func TestUnitPreviewSize(t *testing.T) {
+	tests := []struct {
+		w, h, maxDim int
+		wantW, wantH int
+	}{
+		{1920, 1080, 768, 768, 432},
+		{1080, 1920, 768, 432, 768},
+		{1000, 1000, 100, 100, 100},
+		{5000, 10, 100, 100, 1},
+	}
+	for _, tt := range tests {
+		w, h := previewSize(tt.w, tt.h, tt.maxDim)
+		assert.Equal(t, tt.wantW, w, "%dx%d", tt.w, tt.h)
+		assert.Equal(t, tt.wantH, h, "%dx%d", tt.w, tt.h)
+	}
+}
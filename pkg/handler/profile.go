@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// profileSetParams maps the standard users_profile_set parameters to the
+// profile fields they set.
+var profileSetParams = []struct{ param, field string }{
+	{"title", "title"},
+	{"pronouns", "pronouns"},
+	{"phone", "phone"},
+	{"display_name", "display_name"},
+}
+
+type ProfileHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewProfileHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// UsersProfileSetHandler updates text fields of the authenticated user's
+// profile. Only parameters that are passed are changed; an empty value
+// clears the field. The photo cannot be changed.
+func (h *ProfileHandler) UsersProfileSetHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("UsersProfileSetHandler called", zap.Any("params", request.Params))
+
+	if h.apiProvider.IsBotToken() {
+		return nil, errors.New("users_profile_set requires a user token (xoxp or xoxc/xoxd); bot tokens cannot change a user's profile")
+	}
+
+	args := request.GetArguments()
+	profile := map[string]any{}
+	for _, p := range profileSetParams {
+		raw, ok := args[p.param]
+		if !ok || raw == nil {
+			continue
+		}
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string, got %T", p.param, raw)
+		}
+		profile[p.field] = strings.TrimSpace(value)
+	}
+
+	custom, err := parseCustomFieldValues(args["custom_fields"])
+	if err != nil {
+		return nil, err
+	}
+	var labels map[string]string
+	if len(custom) > 0 {
+		schema, err := h.apiProvider.Slack().GetTeamProfileContext(ctx)
+		if err != nil {
+			h.logger.Error("GetTeamProfileContext failed", zap.Error(err))
+			return nil, fmt.Errorf("failed to read the custom profile fields of the workspace: %w", err)
+		}
+		var fields map[string]slack.UserProfileCustomField
+		if fields, labels, err = matchProfileFields(schema.Fields, custom); err != nil {
+			return nil, err
+		}
+		profile["fields"] = fields
+	}
+	if len(profile) == 0 {
+		return nil, errors.New("nothing to update: pass title, pronouns, phone, display_name or custom_fields")
+	}
+
+	updated, err := h.apiProvider.Slack().SetUserProfileContext(ctx, profile)
+	if err != nil {
+		h.logger.Error("SetUserProfileContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	result := map[string]any{
+		"title":        updated.Title,
+		"pronouns":     updated.Pronouns,
+		"phone":        updated.Phone,
+		"display_name": updated.DisplayName,
+	}
+	if len(labels) > 0 {
+		saved := updated.Fields.ToMap()
+		customResult := make(map[string]string, len(labels))
+		for id, label := range labels {
+			customResult[label] = saved[id].Value
+		}
+		result["custom_fields"] = customResult
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// parseCustomFieldValues reads custom_fields, a JSON object of field ID or
+// label to value, also accepted as a string holding one.
+func parseCustomFieldValues(raw any) (map[string]string, error) {
+	var values map[string]any
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		values = v
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		if err := json.Unmarshal([]byte(v), &values); err != nil {
+			return nil, fmt.Errorf("custom_fields must be a JSON object: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("custom_fields must be a JSON object, got %T", raw)
+	}
+
+	out := make(map[string]string, len(values))
+	for key, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("custom_fields: value of %q must be a string, got %T", key, value)
+		}
+		out[key] = strings.TrimSpace(s)
+	}
+	return out, nil
+}
+
+// matchProfileFields resolves custom field keys, an ID such as "Xf0123" or a
+// label matched case-insensitively, against the workspace's profile fields.
+// It returns the values by field ID and the labels of the fields set.
+func matchProfileFields(schema []slack.TeamProfileField, values map[string]string) (map[string]slack.UserProfileCustomField, map[string]string, error) {
+	fields := make(map[string]slack.UserProfileCustomField, len(values))
+	labels := make(map[string]string, len(values))
+	for key, value := range values {
+		var field *slack.TeamProfileField
+		for i := range schema {
+			if schema[i].ID == key || strings.EqualFold(schema[i].Label, key) {
+				field = &schema[i]
+				break
+			}
+		}
+		if field == nil {
+			known := make([]string, 0, len(schema))
+			for _, f := range schema {
+				known = append(known, f.Label)
+			}
+			sort.Strings(known)
+			return nil, nil, fmt.Errorf("unknown custom profile field %q, available fields: %s", key, strings.Join(known, ", "))
+		}
+		if field.Options["is_protected"] {
+			return nil, nil, fmt.Errorf("custom profile field %q is managed by the identity provider and cannot be changed", field.Label)
+		}
+		if _, dup := fields[field.ID]; dup {
+			return nil, nil, fmt.Errorf("custom profile field %q is set twice", field.Label)
+		}
+		fields[field.ID] = slack.UserProfileCustomField{Value: value}
+		labels[field.ID] = field.Label
+	}
+	return fields, labels, nil
+}
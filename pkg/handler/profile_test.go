@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseCustomFieldValues(t *testing.T) {
+	values, err := parseCustomFieldValues(map[string]any{"Team": " Payments "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Team": "Payments"}, values)
+
+	values, err = parseCustomFieldValues(`{"Xf01": "2025-06-02"}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Xf01": "2025-06-02"}, values)
+
+	values, err = parseCustomFieldValues(nil)
+	require.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = parseCustomFieldValues(map[string]any{"Team": 3})
+	assert.ErrorContains(t, err, `value of "Team" must be a string`)
+	_, err = parseCustomFieldValues("[1]")
+	assert.ErrorContains(t, err, "must be a JSON object")
+}
+
+func TestUnitMatchProfileFields(t *testing.T) {
+	schema := []slack.TeamProfileField{
+		{ID: "Xf01", Label: "Team"},
+		{ID: "Xf02", Label: "Start date"},
+		{ID: "Xf03", Label: "Employee ID", Options: map[string]bool{"is_protected": true}},
+	}
+
+	fields, labels, err := matchProfileFields(schema, map[string]string{"team": "Payments", "Xf02": ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]slack.UserProfileCustomField{
+		"Xf01": {Value: "Payments"},
+		"Xf02": {Value: ""},
+	}, fields)
+	assert.Equal(t, map[string]string{"Xf01": "Team", "Xf02": "Start date"}, labels)
+
+	_, _, err = matchProfileFields(schema, map[string]string{"Manager": "U1"})
+	assert.ErrorContains(t, err, `unknown custom profile field "Manager", available fields: Employee ID, Start date, Team`)
+
+	_, _, err = matchProfileFields(schema, map[string]string{"Employee ID": "42"})
+	assert.ErrorContains(t, err, "managed by the identity provider")
+
+	_, _, err = matchProfileFields(schema, map[string]string{"Team": "a", "Xf01": "b"})
+	assert.ErrorContains(t, err, "set twice")
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressInterval is the minimum time between two progress notifications
+// of one call, so busy pagination loops do not flood the client.
+const progressInterval = 500 * time.Millisecond
+
+// progressReporter sends MCP progress notifications for a tool call whose
+// client passed a progress token in _meta. A nil reporter does nothing, so
+// pagination loops can report unconditionally.
+type progressReporter struct {
+	ctx   context.Context
+	srv   *server.MCPServer
+	token mcp.ProgressToken
+	last  time.Time
+	sent  float64
+}
+
+// newProgressReporter returns a reporter for request, or nil when the client
+// did not ask for progress.
+func newProgressReporter(ctx context.Context, request mcp.CallToolRequest) *progressReporter {
+	if request.Params.Meta == nil || request.Params.Meta.ProgressToken == nil {
+		return nil
+	}
+	srv := server.ServerFromContext(ctx)
+	if srv == nil {
+		return nil
+	}
+	return &progressReporter{ctx: ctx, srv: srv, token: request.Params.Meta.ProgressToken}
+}
+
+// Report notifies the client that progress out of total is done, e.g. 40 of
+// 100 percent; total is 0 when unknown. Progress that does not increase, and
+// updates within progressInterval of the previous one, are dropped unless
+// they complete the operation.
+func (p *progressReporter) Report(progress, total float64, message string) {
+	if p == nil || progress <= p.sent {
+		return
+	}
+	now := time.Now()
+	if now.Sub(p.last) < progressInterval && (total == 0 || progress < total) {
+		return
+	}
+	p.last, p.sent = now, progress
+
+	params := map[string]any{
+		"progressToken": p.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	// Progress is best effort; a client that went away shows up as a
+	// cancelled context in the caller.
+	_ = p.srv.SendNotificationToClient(p.ctx, "notifications/progress", params)
+}
+
+// rangePercent returns how far ts is into the range from oldest to latest,
+// from 0 to 100, for scans that walk history newest first. An empty latest
+// means now.
+func rangePercent(oldest, latest, ts string) float64 {
+	from, err := text.SlackTimestampToTime(oldest)
+	if err != nil {
+		return 0
+	}
+	to := time.Now()
+	if latest != "" {
+		if to, err = text.SlackTimestampToTime(latest); err != nil {
+			return 0
+		}
+	}
+	at, err := text.SlackTimestampToTime(ts)
+	if err != nil || !to.After(from) {
+		return 0
+	}
+	pct := 100 * to.Sub(at).Seconds() / to.Sub(from).Seconds()
+	return min(max(pct, 0), 100)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReactionSearchLimit = 50
+	maxReactionSearchLimit     = 200
+	// defaultReactionSearchWindow applies when oldest is not set.
+	defaultReactionSearchWindow = 7 * 24 * time.Hour
+	// maxReactionScanMessages bounds the history scanned by one call; the
+	// next_latest metadata continues from where it stopped.
+	maxReactionScanMessages = 5000
+	reactionScanPageSize    = 200
+)
+
+// ReactionSearchMetadata describes how much history reactions_search scanned.
+type ReactionSearchMetadata struct {
+	Scanned int `json:"scanned"`
+	Matched int `json:"matched"`
+	// Truncated is set when the scan stopped at the result limit or the scan
+	// cap before reaching oldest. Pass NextLatest as latest to continue.
+	Truncated  bool   `json:"truncated"`
+	NextLatest string `json:"next_latest,omitempty"`
+}
+
+// ReactionsSearchHandler scans a channel's history between oldest and latest
+// and returns the messages carrying a given reaction. search.messages cannot
+// filter on a specific reaction, so the filtering happens here.
+func (ch *ConversationsHandler) ReactionsSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ReactionsSearchHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channel, err := ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	reaction := reactionKey(request.GetString("reaction", ""))
+	if reaction == "" {
+		return nil, errors.New("reaction is required, e.g. 'white_check_mark'")
+	}
+	exclude := reactionKey(request.GetString("exclude_reaction", ""))
+	minCount := request.GetInt("min_count", 1)
+	if minCount < 1 {
+		return nil, fmt.Errorf("min_count must be at least 1, got %d", minCount)
+	}
+	limit := request.GetInt("limit", defaultReactionSearchLimit)
+	if limit < 1 || limit > maxReactionSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxReactionSearchLimit, limit)
+	}
+	render, err := parseRenderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	oldest, err := parseTimeBound(strings.TrimSpace(request.GetString("oldest", "")), loc, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oldest: %w", err)
+	}
+	if oldest == "" {
+		oldest = fmt.Sprintf("%d.000000", time.Now().Add(-defaultReactionSearchWindow).Unix())
+	}
+	latest, err := parseTimeBound(strings.TrimSpace(request.GetString("latest", "")), loc, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latest: %w", err)
+	}
+	if latest != "" && !slackTsLess(oldest, latest) {
+		return nil, fmt.Errorf("oldest %q must be before latest %q", oldest, latest)
+	}
+
+	historyParams := slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Limit:     reactionScanPageSize,
+		Oldest:    oldest,
+		Latest:    latest,
+	}
+
+	var (
+		matched  []slack.Message
+		meta     ReactionSearchMetadata
+		progress = newProgressReporter(ctx, request)
+	)
+scan:
+	for {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &historyParams)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+			return nil, err
+		}
+
+		// Messages come newest first.
+		for _, msg := range history.Messages {
+			meta.Scanned++
+			if hasReaction(msg.Reactions, reaction, minCount) && (exclude == "" || !hasReaction(msg.Reactions, exclude, 1)) {
+				matched = append(matched, msg)
+			}
+			if len(matched) >= limit || meta.Scanned >= maxReactionScanMessages {
+				meta.Truncated = true
+				meta.NextLatest = msg.Timestamp
+				break scan
+			}
+		}
+
+		if len(history.Messages) > 0 {
+			last := history.Messages[len(history.Messages)-1].Timestamp
+			progress.Report(rangePercent(oldest, latest, last), 100, fmt.Sprintf("scanned %d messages, %d matches", meta.Scanned, len(matched)))
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		historyParams.Cursor = history.ResponseMetaData.NextCursor
+	}
+	meta.Matched = len(matched)
+	ch.logger.Debug("Scanned history for reaction",
+		zap.String("channel", channel),
+		zap.String("reaction", reaction),
+		zap.Int("scanned", meta.Scanned),
+		zap.Int("matched", meta.Matched),
+	)
+
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(matched))
+	messages := ch.convertMessagesFromHistory(matched, channel, false, false, false, render, loc)
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	res, err := MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	if err != nil {
+		ch.logger.Error("Failed to marshal messages to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(res, meta)
+}
+
+// hasReaction reports whether reactions include key, in any skin tone, with
+// at least minCount reactors.
+func hasReaction(reactions []slack.ItemReaction, key string, minCount int) bool {
+	count := 0
+	for _, r := range reactions {
+		if reactionKey(r.Name) == key {
+			count += r.Count
+		}
+	}
+	return count >= minCount
+}
+
+// reactionKey normalizes a reaction so aliases compare equal: colons and
+// skin tones are dropped and known short codes become their Unicode
+// character, so "+1", ":thumbsup::skin-tone-3:" and "👍" share a key.
+func reactionKey(name string) string {
+	name = strings.Trim(strings.TrimSpace(name), ":")
+	if i := strings.Index(name, "::skin-tone-"); i >= 0 {
+		name = name[:i]
+	}
+	if u, ok := text.EmojiUnicode(name); ok {
+		name = u
+	}
+	return strings.ReplaceAll(name, "\uFE0F", "")
+}
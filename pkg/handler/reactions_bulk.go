@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// maxBulkReactions caps the reactions one reactions_add_bulk call adds.
+	maxBulkReactions = 100
+	// bulkReactionRetries is how often a rate limited reaction is retried
+	// after the wait Slack asks for.
+	bulkReactionRetries = 2
+)
+
+// BulkReactionResult is the CSV output row for reactions_add_bulk, one per
+// reaction.
+type BulkReactionResult struct {
+	Index     int    `csv:"index"`
+	Channel   string `csv:"channelID"`
+	Timestamp string `csv:"timestamp"`
+	Emoji     string `csv:"emoji"`
+	// Status is added, already_reacted or failed.
+	Status string `csv:"status"`
+	Error  string `csv:"error"`
+}
+
+// bulkReaction is one reaction to add. err is set for items that are
+// invalid as given.
+type bulkReaction struct {
+	channel   string
+	timestamp string
+	emoji     string
+	err       string
+}
+
+// ReactionsAddBulkHandler adds one emoji to many messages, or several emojis
+// to one message, rate limited, and reports the outcome per reaction. A
+// failing reaction does not stop the rest.
+func (ch *ConversationsHandler) ReactionsAddBulkHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ReactionsAddBulkHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	if _, err := ch.reactionToolPolicy(); err != nil {
+		return nil, err
+	}
+
+	reactions, err := parseBulkReactions(request.GetArguments())
+	if err != nil {
+		ch.logger.Error("Invalid reactions_add_bulk params", zap.Error(err))
+		return nil, err
+	}
+
+	lim := limiter.Tier3.Limiter()
+	results := make([]BulkReactionResult, 0, len(reactions))
+	for i, r := range reactions {
+		result := BulkReactionResult{Index: i, Channel: r.channel, Timestamp: r.timestamp, Emoji: r.emoji}
+		if r.err != "" {
+			result.Status, result.Error = "failed", r.err
+			results = append(results, result)
+			continue
+		}
+
+		var itemRequest mcp.CallToolRequest
+		itemRequest.Params.Arguments = map[string]any{
+			"channel_id": r.channel,
+			"timestamp":  r.timestamp,
+			"emoji":      r.emoji,
+		}
+		params, err := ch.parseParamsToolReaction(ctx, itemRequest)
+		if err != nil {
+			result.Status, result.Error = "failed", err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Channel = params.channel
+
+		err = ch.addReactionThrottled(ctx, lim, params)
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case err == nil:
+			result.Status = "added"
+		case slackErrorCode(err) == "already_reacted":
+			result.Status = "already_reacted"
+		default:
+			ch.logger.Warn("Bulk reaction failed",
+				zap.Int("index", i),
+				zap.String("channel", params.channel),
+				zap.String("timestamp", params.timestamp),
+				zap.Error(err),
+			)
+			result.Status, result.Error = "failed", err.Error()
+		}
+		results = append(results, result)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&results)
+	if err != nil {
+		ch.logger.Error("Failed to marshal bulk reaction results to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// addReactionThrottled adds one reaction once lim allows it, waiting out and
+// retrying Slack's own rate limit a few times.
+func (ch *ConversationsHandler) addReactionThrottled(ctx context.Context, lim *rate.Limiter, params *addReactionParams) error {
+	itemRef := slack.ItemRef{Channel: params.channel, Timestamp: params.timestamp}
+	for attempt := 0; ; attempt++ {
+		if err := lim.Wait(ctx); err != nil {
+			return err
+		}
+		err := ch.apiProvider.Slack().AddReactionContext(ctx, params.emoji, itemRef)
+		var rlErr *slack.RateLimitedError
+		if !errors.As(err, &rlErr) || attempt >= bulkReactionRetries {
+			return err
+		}
+		ch.logger.Warn("Rate limited while adding reactions, backing off",
+			zap.Duration("retry_after", rlErr.RetryAfter),
+			zap.Int("attempt", attempt+1),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rlErr.RetryAfter):
+		}
+	}
+}
+
+// parseBulkReactions expands the reactions_add_bulk arguments into one
+// reaction per message and emoji: emoji lists one or more emoji names, added
+// either to each of messages or to the single message given by channel_id and
+// timestamp. Several emojis can only go to one message.
+func parseBulkReactions(args map[string]any) ([]bulkReaction, error) {
+	rawEmoji, _ := args["emoji"].(string)
+	var emojis []string
+	for _, e := range strings.Split(rawEmoji, ",") {
+		if e = strings.Trim(strings.TrimSpace(e), ":"); e != "" {
+			emojis = append(emojis, e)
+		}
+	}
+	if len(emojis) == 0 {
+		return nil, errors.New("emoji is required")
+	}
+
+	channel, _ := args["channel_id"].(string)
+	timestamp, _ := args["timestamp"].(string)
+	rawMessages, _ := args["messages"].([]any)
+
+	var targets []bulkReaction
+	switch {
+	case len(rawMessages) > 0 && (channel != "" || timestamp != ""):
+		return nil, errors.New("pass either messages or channel_id and timestamp, not both")
+	case len(rawMessages) > 0:
+		if len(emojis) > 1 && len(rawMessages) > 1 {
+			return nil, errors.New("several emojis can only be added to one message; pass a single emoji for a list of messages")
+		}
+		for _, raw := range rawMessages {
+			item, ok := raw.(map[string]any)
+			if !ok {
+				targets = append(targets, bulkReaction{err: "item must be an object with channel_id and timestamp"})
+				continue
+			}
+			t := bulkReaction{}
+			t.channel, _ = item["channel_id"].(string)
+			t.timestamp, _ = item["timestamp"].(string)
+			targets = append(targets, t)
+		}
+	case channel != "" || timestamp != "":
+		targets = []bulkReaction{{channel: channel, timestamp: timestamp}}
+	default:
+		return nil, errors.New("messages, or channel_id and timestamp, are required")
+	}
+
+	reactions := make([]bulkReaction, 0, len(targets)*len(emojis))
+	for _, t := range targets {
+		if t.err == "" {
+			switch {
+			case t.channel == "":
+				t.err = "channel_id is required"
+			case !strings.Contains(t.timestamp, "."):
+				t.err = fmt.Sprintf("timestamp %q must be a message timestamp such as 1234567890.123456", t.timestamp)
+			}
+		}
+		for _, emoji := range emojis {
+			t.emoji = emoji
+			reactions = append(reactions, t)
+		}
+	}
+	if len(reactions) > maxBulkReactions {
+		return nil, fmt.Errorf("too many reactions: %d, at most %d per call", len(reactions), maxBulkReactions)
+	}
+	return reactions, nil
+}
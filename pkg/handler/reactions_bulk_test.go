@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseBulkReactions(t *testing.T) {
+	reactions, err := parseBulkReactions(map[string]any{
+		"emoji": ":eyes:",
+		"messages": []any{
+			map[string]any{"channel_id": "C1", "timestamp": "1700000000.000100"},
+			map[string]any{"channel_id": "#ops", "timestamp": "1700000000.000200"},
+			map[string]any{"channel_id": "C1", "timestamp": "yesterday"},
+			map[string]any{"timestamp": "1700000000.000300"},
+			"C1/1700000000.000400",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []bulkReaction{
+		{channel: "C1", timestamp: "1700000000.000100", emoji: "eyes"},
+		{channel: "#ops", timestamp: "1700000000.000200", emoji: "eyes"},
+		{channel: "C1", timestamp: "yesterday", emoji: "eyes", err: `timestamp "yesterday" must be a message timestamp such as 1234567890.123456`},
+		{timestamp: "1700000000.000300", emoji: "eyes", err: "channel_id is required"},
+		{emoji: "eyes", err: "item must be an object with channel_id and timestamp"},
+	}, reactions, "invalid items are reported per item")
+
+	reactions, err = parseBulkReactions(map[string]any{
+		"emoji":      "eyes, white_check_mark,",
+		"channel_id": "C1",
+		"timestamp":  "1700000000.000100",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []bulkReaction{
+		{channel: "C1", timestamp: "1700000000.000100", emoji: "eyes"},
+		{channel: "C1", timestamp: "1700000000.000100", emoji: "white_check_mark"},
+	}, reactions)
+
+	twoMessages := []any{
+		map[string]any{"channel_id": "C1", "timestamp": "1700000000.000100"},
+		map[string]any{"channel_id": "C2", "timestamp": "1700000000.000200"},
+	}
+	_, err = parseBulkReactions(map[string]any{"emoji": "eyes,tada", "messages": twoMessages})
+	assert.ErrorContains(t, err, "several emojis can only be added to one message")
+
+	_, err = parseBulkReactions(map[string]any{"emoji": "eyes", "messages": twoMessages, "channel_id": "C1"})
+	assert.ErrorContains(t, err, "not both")
+
+	_, err = parseBulkReactions(map[string]any{"emoji": "eyes"})
+	assert.ErrorContains(t, err, "are required")
+
+	_, err = parseBulkReactions(map[string]any{"emoji": " : ", "channel_id": "C1", "timestamp": "1700000000.000100"})
+	assert.ErrorContains(t, err, "emoji is required")
+
+	many := make([]any, maxBulkReactions+1)
+	for i := range many {
+		many[i] = map[string]any{"channel_id": "C1", "timestamp": "1700000000.000100"}
+	}
+	_, err = parseBulkReactions(map[string]any{"emoji": "eyes", "messages": many})
+	assert.ErrorContains(t, err, "too many reactions: 101, at most 100 per call")
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	renderPlain    = "plain"
+	renderMarkdown = "markdown"
+)
+
+// parseRenderParam reads the optional render parameter shared by message-returning tools.
+func parseRenderParam(request mcp.CallToolRequest) (string, error) {
+	render := strings.ToLower(strings.TrimSpace(request.GetString("render", renderPlain)))
+	switch render {
+	case "", renderPlain:
+		return renderPlain, nil
+	case renderMarkdown:
+		return renderMarkdown, nil
+	}
+	return "", fmt.Errorf("invalid render %q: must be %q or %q", render, renderPlain, renderMarkdown)
+}
+
+// renderMessageText converts raw Slack message text for output. Plain rendering
+// keeps the historical behaviour of stripping markup; markdown rendering
+// translates mrkdwn into standard Markdown with names resolved from the caches.
+func renderMessageText(ap *provider.ApiProvider, raw, render string) string {
+	if render != renderMarkdown {
+		return text.ProcessText(raw)
+	}
+	return text.MrkdwnToMarkdown(raw, newMrkdwnResolver(ap))
+}
+
+func newMrkdwnResolver(ap *provider.ApiProvider) text.MrkdwnResolver {
+	users := ap.ProvideUsersMap()
+	channels := ap.ProvideChannelsMaps()
+
+	return text.MrkdwnResolver{
+		User: func(id string) (string, bool) {
+			u, ok := users.Users[id]
+			if !ok {
+				return "", false
+			}
+			if u.Profile.DisplayName != "" {
+				return u.Profile.DisplayName, true
+			}
+			return u.Name, true
+		},
+		Channel: func(id string) (string, bool) {
+			c, ok := channels.Channels[id]
+			if !ok {
+				return "", false
+			}
+			return c.Name, true
+		},
+	}
+}
+
+// newMentionResolver resolves outgoing @names by handle, then by display or
+// real name when exactly one active user has it, and #names by channel name.
+func newMentionResolver(ap *provider.ApiProvider) text.MentionResolver {
+	users := ap.ProvideUsersMap()
+	channels := ap.ProvideChannelsMaps()
+
+	var byName map[string][]string
+	return text.MentionResolver{
+		User: func(name string) (string, bool) {
+			if id, ok := users.UsersInv[name]; ok {
+				return id, true
+			}
+			if byName == nil {
+				byName = make(map[string][]string)
+				for id, u := range users.Users {
+					if u.Deleted {
+						continue
+					}
+					for _, n := range []string{u.Profile.DisplayName, u.RealName} {
+						if n = strings.ToLower(n); n != "" && !slices.Contains(byName[n], id) {
+							byName[n] = append(byName[n], id)
+						}
+					}
+				}
+			}
+			if ids := byName[strings.ToLower(name)]; len(ids) == 1 {
+				return ids[0], true
+			}
+			return "", false
+		},
+		Channel: func(name string) (string, bool) {
+			id, ok := channels.ChannelsInv["#"+name]
+			return id, ok
+		},
+	}
+}
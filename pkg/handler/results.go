@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultResultTTL             = 30 * time.Minute
+	defaultResultCleanupInterval = time.Minute
+	defaultResultMaxBytes        = 64 << 20
+)
+
+type storedResult struct {
+	text      string
+	mimeType  string
+	expiresAt time.Time
+}
+
+// ResultStore keeps oversized tool results in memory so they can be served as
+// MCP resources instead of being inlined into the tool response. It holds at
+// most maxBytes of result text and evicts the oldest results to make room.
+type ResultStore struct {
+	mu       sync.RWMutex
+	entries  map[string]storedResult
+	order    []string // IDs of entries, oldest first
+	size     int      // bytes of text in entries
+	ttl      time.Duration
+	maxBytes int
+	now      func() time.Time
+}
+
+func NewResultStore(ttl time.Duration, maxBytes int) *ResultStore {
+	if ttl <= 0 {
+		ttl = defaultResultTTL
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultResultMaxBytes
+	}
+	return &ResultStore{
+		entries:  make(map[string]storedResult),
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		now:      time.Now,
+	}
+}
+
+// Put stores text and returns its ID together with the expiry time. The
+// oldest results are evicted when the store would exceed its size; text
+// larger than the whole store is refused.
+func (rs *ResultStore) Put(text, mimeType string) (string, time.Time, error) {
+	if len(text) > rs.maxBytes {
+		return "", time.Time{}, fmt.Errorf("result of %d bytes exceeds the %d bytes of the result store", len(text), rs.maxBytes)
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, err
+	}
+	id := hex.EncodeToString(buf)
+	expiresAt := rs.now().Add(rs.ttl)
+
+	rs.mu.Lock()
+	for rs.size+len(text) > rs.maxBytes {
+		rs.evictOldest()
+	}
+	rs.entries[id] = storedResult{text: text, mimeType: mimeType, expiresAt: expiresAt}
+	rs.order = append(rs.order, id)
+	rs.size += len(text)
+	rs.mu.Unlock()
+
+	return id, expiresAt, nil
+}
+
+func (rs *ResultStore) Get(id string) (storedResult, bool) {
+	rs.mu.RLock()
+	entry, ok := rs.entries[id]
+	rs.mu.RUnlock()
+	if !ok || rs.now().After(entry.expiresAt) {
+		return storedResult{}, false
+	}
+	return entry, true
+}
+
+// Cleanup drops expired entries and returns how many were removed. Every
+// entry has the same TTL, so they expire oldest first.
+func (rs *ResultStore) Cleanup() int {
+	now := rs.now()
+	removed := 0
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for len(rs.order) > 0 && now.After(rs.entries[rs.order[0]].expiresAt) {
+		rs.evictOldest()
+		removed++
+	}
+	return removed
+}
+
+// evictOldest drops the oldest entry. The caller holds mu.
+func (rs *ResultStore) evictOldest() {
+	id := rs.order[0]
+	rs.order = rs.order[1:]
+	rs.size -= len(rs.entries[id].text)
+	delete(rs.entries, id)
+}
+
+// RunCleanup periodically evicts expired entries until ctx is cancelled.
+func (rs *ResultStore) RunCleanup(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = defaultResultCleanupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed := rs.Cleanup(); removed > 0 {
+				logger.Debug("Evicted expired result resources", zap.Int("removed", removed))
+			}
+		}
+	}
+}
+
+type ResultsHandler struct {
+	apiProvider *provider.ApiProvider
+	store       *ResultStore
+	threshold   int
+	baseURI     string
+	logger      *zap.Logger
+}
+
+func NewResultsHandler(apiProvider *provider.ApiProvider, store *ResultStore, threshold int, logger *zap.Logger) *ResultsHandler {
+	return &ResultsHandler{
+		apiProvider: apiProvider,
+		store:       store,
+		threshold:   threshold,
+		logger:      logger,
+	}
+}
+
+// SetWorkspace sets the workspace used to build slack://{ws}/results/{id} URIs.
+func (rh *ResultsHandler) SetWorkspace(ws string) {
+	rh.baseURI = "slack://" + ws + "/results/"
+}
+
+// Enabled reports whether oversized results should be offloaded to resources.
+func (rh *ResultsHandler) Enabled() bool {
+	return rh.threshold > 0 && rh.baseURI != ""
+}
+
+// Offload replaces the primary text payload of res with a summary row and a
+// resource link when it exceeds the configured threshold. Any additional
+// content blocks (e.g. metadata) are kept inline.
+func (rh *ResultsHandler) Offload(toolName string, res *mcp.CallToolResult) *mcp.CallToolResult {
+	if !rh.Enabled() || res == nil || res.IsError || len(res.Content) == 0 {
+		return res
+	}
+	payload, ok := res.Content[0].(mcp.TextContent)
+	if !ok || len(payload.Text) <= rh.threshold {
+		return res
+	}
+
+	mimeType := "text/csv"
+	rows := strings.Count(strings.TrimRight(payload.Text, "\n"), "\n")
+	if trimmed := strings.TrimSpace(payload.Text); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		mimeType = "application/json"
+		rows = 0
+	}
+
+	id, expiresAt, err := rh.store.Put(payload.Text, mimeType)
+	if err != nil {
+		rh.logger.Warn("Failed to store large result, returning it inline",
+			zap.String("tool", toolName),
+			zap.Error(err),
+		)
+		return res
+	}
+	uri := rh.baseURI + id
+
+	rh.logger.Debug("Offloaded large tool result to resource",
+		zap.String("tool", toolName),
+		zap.String("uri", uri),
+		zap.Int("bytes", len(payload.Text)),
+	)
+
+	summary := fmt.Sprintf("resourceURI,mimeType,bytes,rows,expiresAt\n%s,%s,%d,%d,%s\n",
+		uri, mimeType, len(payload.Text), rows, expiresAt.UTC().Format(time.RFC3339))
+
+	content := make([]mcp.Content, 0, len(res.Content)+1)
+	content = append(content, mcp.NewTextContent(summary))
+	content = append(content, mcp.NewResourceLink(uri, toolName+" result",
+		fmt.Sprintf("Full %s result (%d bytes), available until %s", toolName, len(payload.Text), expiresAt.UTC().Format(time.RFC3339)),
+		mimeType))
+	content = append(content, res.Content[1:]...)
+
+	return &mcp.CallToolResult{
+		Result:  res.Result,
+		Content: content,
+	}
+}
+
+func (rh *ResultsHandler) ResultsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	rh.logger.Debug("ResultsResource called", zap.Any("params", request.Params))
+
+	if authenticated, err := auth.IsAuthenticated(ctx, rh.apiProvider.ServerTransport(), rh.logger); !authenticated {
+		rh.logger.Error("Authentication failed for results resource", zap.Error(err))
+		return nil, err
+	}
+
+	id := request.Params.URI[strings.LastIndex(request.Params.URI, "/")+1:]
+	entry, ok := rh.store.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("result %q not found or expired", id)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: entry.mimeType,
+			Text:     entry.text,
+		},
+	}, nil
+}
+
+// ResultThreshold returns the payload size in bytes above which tool results
+// are offloaded, set by SLACK_MCP_RESULT_RESOURCE_THRESHOLD. Zero (the
+// default) disables offloading.
+func ResultThreshold(cfg *config.Config) int {
+	return max(cfg.ResultResourceThreshold, 0)
+}
+
+// ResultMaxBytes returns how many bytes of offloaded results are kept, set by
+// SLACK_MCP_RESULT_RESOURCE_MAX_BYTES; 64 MiB when unset.
+func ResultMaxBytes(cfg *config.Config) int {
+	if cfg.ResultResourceMaxBytes <= 0 {
+		return defaultResultMaxBytes
+	}
+	return cfg.ResultResourceMaxBytes
+}
+
+// ResultTTL returns how long offloaded results are kept, set by
+// SLACK_MCP_RESULT_RESOURCE_TTL. Supports formats: "30m", "1h", "1800" (seconds).
+func ResultTTL(cfg *config.Config) time.Duration {
+	if cfg.ResultResourceTTL == "" {
+		return defaultResultTTL
+	}
+	d, err := config.ParseDuration(cfg.ResultResourceTTL)
+	if err != nil || d == 0 {
+		return defaultResultTTL
+	}
+	return d
+}
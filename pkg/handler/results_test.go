@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitResultStoreExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewResultStore(time.Minute, 0)
+	store.now = func() time.Time { return now }
+
+	id, expiresAt, err := store.Put("a,b\n1,2\n", "text/csv")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+
+	entry, ok := store.Get(id)
+	require.True(t, ok)
+	assert.Equal(t, "a,b\n1,2\n", entry.text)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = store.Get(id)
+	assert.False(t, ok, "expired entries must not be served")
+	assert.Equal(t, 1, store.Cleanup())
+	assert.Equal(t, 0, store.Cleanup())
+	assert.Equal(t, 0, store.size, "evicted bytes are released")
+}
+
+func TestUnitResultStoreMaxBytes(t *testing.T) {
+	store := NewResultStore(time.Minute, 10)
+
+	first, _, err := store.Put("aaaa", "text/csv")
+	require.NoError(t, err)
+	second, _, err := store.Put("bbbb", "text/csv")
+	require.NoError(t, err)
+	third, _, err := store.Put("cccccc", "text/csv")
+	require.NoError(t, err)
+
+	_, ok := store.Get(first)
+	assert.False(t, ok, "the oldest result is evicted to make room")
+	_, ok = store.Get(second)
+	assert.True(t, ok)
+	_, ok = store.Get(third)
+	assert.True(t, ok)
+	assert.Equal(t, 10, store.size)
+
+	_, _, err = store.Put(strings.Repeat("d", 11), "text/csv")
+	assert.ErrorContains(t, err, "exceeds the 10 bytes")
+	_, ok = store.Get(second)
+	assert.True(t, ok, "a refused result evicts nothing")
+}
+
+func TestUnitResultStoreRunCleanupStops(t *testing.T) {
+	store := NewResultStore(time.Minute, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.RunCleanup(ctx, time.Millisecond, zap.NewNop())
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunCleanup kept running after its context was cancelled")
+	}
+}
+
+func TestUnitResultSettings(t *testing.T) {
+	assert.Equal(t, defaultResultTTL, ResultTTL(&config.Config{}))
+	assert.Equal(t, 10*time.Minute, ResultTTL(&config.Config{ResultResourceTTL: "10m"}))
+	assert.Equal(t, 90*time.Second, ResultTTL(&config.Config{ResultResourceTTL: "90"}))
+	assert.Equal(t, defaultResultTTL, ResultTTL(&config.Config{ResultResourceTTL: "0"}))
+	assert.Equal(t, 0, ResultThreshold(&config.Config{}))
+	assert.Equal(t, 4096, ResultThreshold(&config.Config{ResultResourceThreshold: 4096}))
+	assert.Equal(t, defaultResultMaxBytes, ResultMaxBytes(&config.Config{}))
+	assert.Equal(t, 1<<20, ResultMaxBytes(&config.Config{ResultResourceMaxBytes: 1 << 20}))
+}
+
+func TestUnitResultsHandlerOffload(t *testing.T) {
+	store := NewResultStore(time.Minute, 0)
+	rh := NewResultsHandler(nil, store, 16, zap.NewNop())
+	rh.SetWorkspace("acme")
+
+	small := mcp.NewToolResultText("a,b\n1,2\n")
+	assert.Same(t, small, rh.Offload("channels_list", small))
+
+	big := mcp.NewToolResultText("id,name\n" + strings.Repeat("C123,general\n", 10))
+	big.Content = append(big.Content, mcp.NewTextContent(`{"total":10}`))
+	out := rh.Offload("channels_list", big)
+
+	require.Len(t, out.Content, 3)
+	summary, ok := out.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, summary.Text, "slack://acme/results/")
+	assert.Contains(t, summary.Text, ",text/csv,")
+
+	link, ok := out.Content[1].(mcp.ResourceLink)
+	require.True(t, ok)
+	id := strings.TrimPrefix(link.URI, "slack://acme/results/")
+	entry, found := store.Get(id)
+	require.True(t, found)
+	assert.Equal(t, big.Content[0].(mcp.TextContent).Text, entry.text)
+
+	assert.Equal(t, mcp.NewTextContent(`{"total":10}`), out.Content[2])
+}
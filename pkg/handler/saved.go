@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/slack-go/slack"
 	"go.uber.org/zap"
@@ -17,11 +20,21 @@ import (
 // slackLinkRe matches Slack link markup: <url|label> or <url>
 var slackLinkRe = regexp.MustCompile(`<([^|>]+)\|?[^>]*>`)
 
+const (
+	savedStateInProgress = "in_progress"
+	savedStateCompleted  = "completed"
+	savedStateArchived   = "archived"
+
+	savedSortDateDue     = "date_due"
+	savedSortDateCreated = "date_created"
+)
+
 // SavedItemRow is the CSV output row for a saved item.
 type SavedItemRow struct {
 	Channel     string `csv:"channel"`
 	ChannelName string `csv:"channel_name"`
 	Ts          string `csv:"ts"`
+	ThreadTs    string `csv:"thread_ts"`
 	State       string `csv:"state"`
 	DateSaved   string `csv:"date_saved"`
 	DateDue     string `csv:"date_due"`
@@ -31,6 +44,14 @@ type SavedItemRow struct {
 	Cursor      string `csv:"cursor"`
 }
 
+// savedListFilter holds the server-side filters and ordering for saved_list.
+type savedListFilter struct {
+	state     string
+	dueBefore int64
+	dueAfter  int64
+	sortBy    string
+}
+
 type SavedHandler struct {
 	apiProvider *provider.ApiProvider
 	logger      *zap.Logger
@@ -46,7 +67,22 @@ func NewSavedHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *Sav
 func (h *SavedHandler) SavedListHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	h.logger.Debug("SavedListHandler called", zap.Any("params", request.Params))
 
-	cursor := request.GetString("cursor", "")
+	cursor, err := decodeCursor("saved_list", "", request.GetString("cursor", ""))
+	if err != nil {
+		return nil, err
+	}
+	render, err := parseRenderParam(request)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := parseSavedListFilter(request)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
 
 	// Fetch all pages of saved items transparently
 	var allSavedItems []provider.SavedItem
@@ -65,6 +101,16 @@ func (h *SavedHandler) SavedListHandler(ctx context.Context, request mcp.CallToo
 	}
 	h.logger.Debug("Fetched all saved items", zap.Int("total_count", len(allSavedItems)))
 
+	allSavedItems = filter.apply(allSavedItems)
+	allSavedItems = slices.DeleteFunc(allSavedItems, func(item provider.SavedItem) bool {
+		return !h.apiProvider.ChannelAllowed(item.ItemID)
+	})
+	h.logger.Debug("Filtered saved items",
+		zap.Int("count", len(allSavedItems)),
+		zap.String("state", filter.state),
+		zap.String("sort", filter.sortBy),
+	)
+
 	// Get workspace URL for building permalinks
 	workspaceURL := ""
 	if authResp, err := h.apiProvider.Slack().AuthTest(); err == nil {
@@ -84,18 +130,11 @@ func (h *SavedHandler) SavedListHandler(ctx context.Context, request mcp.CallToo
 			}
 		}
 
-		dateSaved := ""
-		if item.DateCreated > 0 {
-			dateSaved = time.Unix(item.DateCreated, 0).UTC().Format(time.RFC3339)
-		}
-
-		dateDue := ""
-		if item.DateDue > 0 {
-			dateDue = time.Unix(item.DateDue, 0).UTC().Format(time.RFC3339)
-		}
+		dateSaved, _ := text.FormatUnix(item.DateCreated, loc)
+		dateDue, _ := text.FormatUnix(item.DateDue, loc)
 
 		// Fetch the actual message text
-		msgUser, msgText := h.fetchMessageText(ctx, item.ItemID, item.Ts, usersCache)
+		msgUser, msgText, threadTs := h.fetchMessageText(ctx, item.ItemID, item.Ts, usersCache, render)
 
 		// Build permalink: https://workspace.slack.com/archives/{channel}/p{ts_without_dot}
 		link := ""
@@ -107,7 +146,8 @@ func (h *SavedHandler) SavedListHandler(ctx context.Context, request mcp.CallToo
 			Channel:     item.ItemID,
 			ChannelName: channelName,
 			Ts:          item.Ts,
-			State:       item.State,
+			ThreadTs:    threadTs,
+			State:       savedItemState(item),
 			DateSaved:   dateSaved,
 			DateDue:     dateDue,
 			User:        msgUser,
@@ -138,7 +178,9 @@ func (h *SavedHandler) SavedCompleteHandler(ctx context.Context, request mcp.Cal
 		return nil, fmt.Errorf("ts is required")
 	}
 
-	if err := h.apiProvider.Slack().SavedCompleteContext(ctx, channel, ts); err != nil {
+	threadTs := request.GetString("thread_ts", "")
+
+	if err := h.apiProvider.Slack().SavedCompleteContext(ctx, channel, ts, threadTs); err != nil {
 		h.logger.Error("SavedCompleteContext failed", zap.Error(err))
 		return nil, err
 	}
@@ -146,8 +188,10 @@ func (h *SavedHandler) SavedCompleteHandler(ctx context.Context, request mcp.Cal
 	return mcp.NewToolResultText("Item marked as complete."), nil
 }
 
-// fetchMessageText retrieves a single message by channel + ts and returns (username, text).
-func (h *SavedHandler) fetchMessageText(ctx context.Context, channelID, ts string, usersCache *provider.UsersCache) (string, string) {
+// fetchMessageText retrieves a single message by channel + ts and returns
+// (username, text, thread_ts). Thread replies are not returned by
+// conversations.history, so those are looked up through conversations.replies.
+func (h *SavedHandler) fetchMessageText(ctx context.Context, channelID, ts string, usersCache *provider.UsersCache, render string) (string, string, string) {
 	params := &slack.GetConversationHistoryParameters{
 		ChannelID: channelID,
 		Latest:    ts,
@@ -159,14 +203,18 @@ func (h *SavedHandler) fetchMessageText(ctx context.Context, channelID, ts strin
 	history, err := h.apiProvider.Slack().GetConversationHistoryContext(ctx, params)
 	if err != nil {
 		h.logger.Debug("Failed to fetch saved message", zap.String("channel", channelID), zap.String("ts", ts), zap.Error(err))
-		return "", ""
+		return "", "", ""
 	}
 
-	if len(history.Messages) == 0 {
-		return "", ""
+	var msg *slack.Message
+	if len(history.Messages) > 0 && history.Messages[0].Timestamp == ts {
+		msg = &history.Messages[0]
+	} else {
+		msg = h.fetchThreadReply(ctx, channelID, ts)
+	}
+	if msg == nil {
+		return "", "", ""
 	}
-
-	msg := history.Messages[0]
 
 	// Resolve user name
 	userName := msg.User
@@ -176,9 +224,138 @@ func (h *SavedHandler) fetchMessageText(ctx context.Context, channelID, ts strin
 		}
 	}
 
+	if render == renderMarkdown {
+		return userName, renderMessageText(h.apiProvider, msg.Text, render), msg.ThreadTimestamp
+	}
+
 	// Convert Slack link markup <url|label> or <url> to plain URLs
 	text := slackLinkRe.ReplaceAllString(msg.Text, "$1")
 	text = strings.ReplaceAll(text, "\n", " ")
 
-	return userName, text
+	return userName, text, msg.ThreadTimestamp
+}
+
+func (h *SavedHandler) fetchThreadReply(ctx context.Context, channelID, ts string) *slack.Message {
+	msgs, _, _, err := h.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: ts,
+		Oldest:    ts,
+		Latest:    ts,
+		Inclusive: true,
+		Limit:     2,
+	})
+	if err != nil {
+		h.logger.Debug("Failed to fetch saved thread reply", zap.String("channel", channelID), zap.String("ts", ts), zap.Error(err))
+		return nil
+	}
+	for i := range msgs {
+		if msgs[i].Timestamp == ts {
+			return &msgs[i]
+		}
+	}
+	return nil
+}
+
+func parseSavedListFilter(request mcp.CallToolRequest) (savedListFilter, error) {
+	var f savedListFilter
+
+	f.state = strings.ToLower(strings.TrimSpace(request.GetString("state", "")))
+	switch f.state {
+	case "", savedStateInProgress, savedStateCompleted, savedStateArchived:
+	default:
+		return f, fmt.Errorf("invalid state %q: must be one of %s, %s, %s", f.state, savedStateInProgress, savedStateCompleted, savedStateArchived)
+	}
+
+	f.sortBy = strings.ToLower(strings.TrimSpace(request.GetString("sort", "")))
+	switch f.sortBy {
+	case "", savedSortDateDue, savedSortDateCreated:
+	default:
+		return f, fmt.Errorf("invalid sort %q: must be %s or %s", f.sortBy, savedSortDateDue, savedSortDateCreated)
+	}
+
+	var err error
+	if f.dueBefore, err = parseSavedDueBound(request.GetString("due_before", ""), false); err != nil {
+		return f, fmt.Errorf("invalid due_before: %w", err)
+	}
+	if f.dueAfter, err = parseSavedDueBound(request.GetString("due_after", ""), true); err != nil {
+		return f, fmt.Errorf("invalid due_after: %w", err)
+	}
+	if f.dueBefore > 0 && f.dueAfter > 0 && f.dueAfter >= f.dueBefore {
+		return f, fmt.Errorf("due_after must be earlier than due_before")
+	}
+
+	return f, nil
+}
+
+// parseSavedDueBound converts a due_before/due_after value into Unix seconds.
+// RFC3339 timestamps are used as-is; calendar dates mean the start of that day
+// for due_before and the end of that day for due_after, matching Slack search.
+func parseSavedDueBound(raw string, after bool) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.Unix(), nil
+	}
+	day, _, err := parseFlexibleDate(raw)
+	if err != nil {
+		return 0, err
+	}
+	if after {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.Unix(), nil
+}
+
+// savedItemState normalizes the item state, taking the archived flag and
+// completion date into account since saved.list does not always set state.
+func savedItemState(item provider.SavedItem) string {
+	switch {
+	case item.IsArchived:
+		return savedStateArchived
+	case item.State == savedStateCompleted || item.DateCompleted > 0:
+		return savedStateCompleted
+	case item.State == "":
+		return savedStateInProgress
+	}
+	return item.State
+}
+
+func (f savedListFilter) apply(items []provider.SavedItem) []provider.SavedItem {
+	result := make([]provider.SavedItem, 0, len(items))
+	for _, item := range items {
+		if f.state != "" && savedItemState(item) != f.state {
+			continue
+		}
+		if (f.dueBefore > 0 || f.dueAfter > 0) && item.DateDue == 0 {
+			continue
+		}
+		if f.dueBefore > 0 && item.DateDue >= f.dueBefore {
+			continue
+		}
+		if f.dueAfter > 0 && item.DateDue < f.dueAfter {
+			continue
+		}
+		result = append(result, item)
+	}
+
+	switch f.sortBy {
+	case savedSortDateDue:
+		// Soonest due first, items without a due date last.
+		sort.SliceStable(result, func(i, j int) bool {
+			a, b := result[i].DateDue, result[j].DateDue
+			if a == 0 || b == 0 {
+				return a != 0 && b == 0
+			}
+			return a < b
+		})
+	case savedSortDateCreated:
+		// Most recently saved first.
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].DateCreated > result[j].DateCreated
+		})
+	}
+
+	return result
 }
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitSavedItemState(t *testing.T) {
+	assert.Equal(t, "archived", savedItemState(provider.SavedItem{State: "in_progress", IsArchived: true}))
+	assert.Equal(t, "completed", savedItemState(provider.SavedItem{DateCompleted: 1700000000}))
+	assert.Equal(t, "in_progress", savedItemState(provider.SavedItem{}))
+	assert.Equal(t, "in_progress", savedItemState(provider.SavedItem{State: "in_progress"}))
+}
+
+func TestUnitParseSavedDueBound(t *testing.T) {
+	before, err := parseSavedDueBound("2025-03-10", false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC).Unix(), before)
+
+	after, err := parseSavedDueBound("2025-03-10", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC).Unix(), after)
+
+	exact, err := parseSavedDueBound("2025-03-10T12:00:00Z", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC).Unix(), exact)
+
+	empty, err := parseSavedDueBound("", false)
+	require.NoError(t, err)
+	assert.Zero(t, empty)
+
+	_, err = parseSavedDueBound("not a date", false)
+	assert.Error(t, err)
+}
+
+func TestUnitSavedListFilterApply(t *testing.T) {
+	items := []provider.SavedItem{
+		{Ts: "1", State: "in_progress", DateCreated: 100, DateDue: 3000},
+		{Ts: "2", State: "in_progress", DateCreated: 300},
+		{Ts: "3", State: "completed", DateCreated: 200, DateDue: 1000},
+		{Ts: "4", State: "in_progress", DateCreated: 400, DateDue: 2000, IsArchived: true},
+	}
+
+	ts := func(items []provider.SavedItem) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.Ts)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter savedListFilter
+		want   []string
+	}{
+		{"no filter keeps order", savedListFilter{}, []string{"1", "2", "3", "4"}},
+		{"state in_progress", savedListFilter{state: "in_progress"}, []string{"1", "2"}},
+		{"state archived", savedListFilter{state: "archived"}, []string{"4"}},
+		{"due before excludes undated", savedListFilter{dueBefore: 2500}, []string{"3", "4"}},
+		{"due after", savedListFilter{dueAfter: 2000}, []string{"1", "4"}},
+		{"sort by due date", savedListFilter{sortBy: "date_due"}, []string{"3", "4", "1", "2"}},
+		{"sort by created", savedListFilter{sortBy: "date_created"}, []string{"4", "2", "3", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ts(tt.filter.apply(items)))
+		})
+	}
+}
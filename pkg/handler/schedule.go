@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleAhead is how far ahead chat.scheduleMessage accepts post_at.
+const maxScheduleAhead = 120 * 24 * time.Hour
+
+// ScheduledMessage is the CSV row returned by conversations_add_message when
+// post_at is set: the message does not exist yet, so there is no history row.
+type ScheduledMessage struct {
+	Channel            string `csv:"channelID"`
+	ScheduledMessageID string `csv:"scheduledMessageID"`
+	ThreadTs           string `csv:"threadTs"`
+	PostAt             string `csv:"postAt"`
+}
+
+// parsePostAt converts the post_at parameter into Unix seconds. It accepts a
+// duration from now ("30m", "2h"), an RFC 3339 time, a local "YYYY-MM-DD HH:MM"
+// time in loc, or Unix seconds. Empty means post now and returns 0.
+func parsePostAt(raw string, loc *time.Location, now time.Time) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+
+	var at time.Time
+	if d, err := time.ParseDuration(raw); err == nil {
+		at = now.Add(d)
+	} else if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		at = t
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04", raw, loc); err == nil {
+		at = t
+	} else if t, err := time.ParseInLocation("2006-01-02T15:04", raw, loc); err == nil {
+		at = t
+	} else if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		at = time.Unix(secs, 0)
+	} else {
+		return 0, fmt.Errorf("invalid post_at %q: use a duration (e.g. 30m), a local time (e.g. 2025-06-02 09:00), an RFC 3339 time or Unix seconds", raw)
+	}
+
+	if !at.After(now) {
+		return 0, fmt.Errorf("post_at %q is in the past", raw)
+	}
+	if at.Sub(now) > maxScheduleAhead {
+		return 0, fmt.Errorf("post_at %q is more than 120 days ahead, which Slack does not allow", raw)
+	}
+	return at.Unix(), nil
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// searchTemplateGroups are arguments that only make sense together: when a
+// call sets one of a group, the template's arguments of that group are
+// dropped instead of being combined with it.
+var searchTemplateGroups = [][]string{
+	{"filter_in_channel", "filter_in_im_or_mpim"},
+	{"filter_date_before", "filter_date_after", "filter_date_on", "filter_date_during"},
+}
+
+// SearchTemplateNames returns the names of templates, for descriptions and errors.
+func SearchTemplateNames(templates []config.SearchTemplateConfig) []string {
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// applySearchTemplate expands the template argument of
+// conversations_search_messages into the arguments configured for it.
+// Arguments of the call win over the template's, except search_query and
+// search_modifiers, which are appended to the template's so a call can
+// narrow a template down, e.g. to a keyword.
+func (ch *ConversationsHandler) applySearchTemplate(req mcp.CallToolRequest) (mcp.CallToolRequest, error) {
+	name := strings.TrimSpace(req.GetString("template", ""))
+	if name == "" {
+		return req, nil
+	}
+	templates := ch.apiProvider.Config().SearchTemplates
+	i := slices.IndexFunc(templates, func(t config.SearchTemplateConfig) bool { return t.Name == name })
+	if i < 0 {
+		return req, fmt.Errorf("unknown search template %q, configured templates: %s", name, strings.Join(SearchTemplateNames(templates), ", "))
+	}
+	req.Params.Arguments = mergeSearchTemplate(templates[i].Args, req.GetArguments())
+	return req, nil
+}
+
+// mergeSearchTemplate returns the arguments of a call to a template: the
+// template's args overlaid with the call's non-empty ones.
+func mergeSearchTemplate(template, call map[string]any) map[string]any {
+	merged := maps.Clone(template)
+	if merged == nil {
+		merged = make(map[string]any)
+	}
+	isSet := func(v any) bool { return v != nil && v != "" }
+	for _, group := range searchTemplateGroups {
+		if slices.ContainsFunc(group, func(k string) bool { return isSet(call[k]) }) {
+			for _, k := range group {
+				delete(merged, k)
+			}
+		}
+	}
+	for k, v := range call {
+		if k == "template" || !isSet(v) {
+			continue
+		}
+		switch k {
+		case "search_query", "search_modifiers":
+			if base, ok := merged[k].(string); ok && base != "" {
+				if s, ok := v.(string); ok {
+					v = base + " " + s
+				}
+			}
+		}
+		merged[k] = v
+	}
+	for k, v := range merged {
+		// YAML decodes whole numbers as int; tool arguments are JSON numbers.
+		if n, ok := v.(int); ok {
+			merged[k] = float64(n)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitMergeSearchTemplate(t *testing.T) {
+	template := map[string]any{
+		"search_query":      "escalation",
+		"filter_in_channel": "#support",
+		"filter_date_on":    "Today",
+		"has_reactions":     true,
+		"limit":             50,
+	}
+
+	got := mergeSearchTemplate(template, map[string]any{"template": "support_escalations"})
+	assert.Equal(t, map[string]any{
+		"search_query":      "escalation",
+		"filter_in_channel": "#support",
+		"filter_date_on":    "Today",
+		"has_reactions":     true,
+		"limit":             float64(50),
+	}, got)
+
+	got = mergeSearchTemplate(template, map[string]any{
+		"template":          "support_escalations",
+		"search_query":      "outage",
+		"filter_date_after": "2026-03-01",
+		"filter_in_channel": "",
+		"has_reactions":     false,
+		"limit":             float64(10),
+		"filter_users_from": "@alice",
+	})
+	assert.Equal(t, "escalation outage", got["search_query"], "queries are combined")
+	assert.Equal(t, "2026-03-01", got["filter_date_after"])
+	assert.NotContains(t, got, "filter_date_on", "a date filter of the call replaces the template's")
+	assert.Equal(t, "#support", got["filter_in_channel"], "empty arguments keep the template's")
+	assert.Equal(t, false, got["has_reactions"])
+	assert.Equal(t, float64(10), got["limit"])
+	assert.Equal(t, "@alice", got["filter_users_from"])
+	assert.NotContains(t, got, "template")
+
+	assert.Equal(t, "escalation", template["search_query"], "the template is not modified")
+}
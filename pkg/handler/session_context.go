@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// maxSessionContexts bounds the remembered sessions; transports that never
+// unregister sessions would otherwise grow the store forever.
+const maxSessionContexts = 1000
+
+// sharedSessionContextTTL bounds how long a session context is kept in the
+// shared store, where sessions that end with a crashed replica are never
+// forgotten.
+const sharedSessionContextTTL = 24 * time.Hour
+
+// SessionContext holds the defaults a client set with context_set.
+type SessionContext struct {
+	ChannelID   string `csv:"channelID" json:"channel_id"`
+	ChannelName string `csv:"channelName" json:"channel_name,omitempty"`
+	ThreadTs    string `csv:"threadTs" json:"thread_ts,omitempty"`
+	updated     time.Time
+}
+
+// SessionContexts keeps a SessionContext per MCP session ID. With a shared
+// store, SLACK_MCP_STORE, contexts are kept there instead of in memory, so
+// a session whose calls a load balancer spreads over replicas keeps its
+// defaults.
+type SessionContexts struct {
+	mu       sync.Mutex
+	sessions map[string]SessionContext
+	shared   store.Store
+	now      func() time.Time
+	logger   *zap.Logger
+}
+
+// NewSessionContexts keeps contexts in memory when shared is nil.
+func NewSessionContexts(shared store.Store, logger *zap.Logger) *SessionContexts {
+	return &SessionContexts{
+		sessions: make(map[string]SessionContext),
+		shared:   shared,
+		now:      time.Now,
+		logger:   logger,
+	}
+}
+
+func sessionStoreKey(session string) string {
+	return "session/" + session
+}
+
+// Get returns the context of session, if one was set.
+func (sc *SessionContexts) Get(session string) (SessionContext, bool) {
+	if sc.shared == nil {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		c, ok := sc.sessions[session]
+		return c, ok
+	}
+
+	e, ok, err := sc.shared.Get(context.Background(), sessionStoreKey(session))
+	if err != nil {
+		sc.logger.Warn("Failed to read shared session context", zap.String("session", session), zap.Error(err))
+		return SessionContext{}, false
+	}
+	if !ok {
+		return SessionContext{}, false
+	}
+	var c SessionContext
+	if err := json.Unmarshal(e.Value, &c); err != nil {
+		sc.logger.Warn("Ignoring unreadable shared session context", zap.String("session", session), zap.Error(err))
+		return SessionContext{}, false
+	}
+	return c, true
+}
+
+// Set replaces the context of session. An empty context forgets it.
+func (sc *SessionContexts) Set(session string, c SessionContext) {
+	if c.ChannelID == "" && c.ThreadTs == "" {
+		sc.Forget(session)
+		return
+	}
+
+	if sc.shared == nil {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		if _, ok := sc.sessions[session]; !ok && len(sc.sessions) >= maxSessionContexts {
+			sc.evictOldest()
+		}
+		c.updated = sc.now()
+		sc.sessions[session] = c
+		return
+	}
+
+	data, err := json.Marshal(c)
+	if err == nil {
+		err = sc.shared.Put(context.Background(), sessionStoreKey(session), data, sharedSessionContextTTL)
+	}
+	if err != nil {
+		sc.logger.Warn("Failed to share session context", zap.String("session", session), zap.Error(err))
+	}
+}
+
+// Forget drops the context of a session that ended.
+func (sc *SessionContexts) Forget(session string) {
+	if sc.shared == nil {
+		sc.mu.Lock()
+		defer sc.mu.Unlock()
+		delete(sc.sessions, session)
+		return
+	}
+	if err := sc.shared.Delete(context.Background(), sessionStoreKey(session)); err != nil {
+		sc.logger.Warn("Failed to forget shared session context", zap.String("session", session), zap.Error(err))
+	}
+}
+
+func (sc *SessionContexts) evictOldest() {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for k, c := range sc.sessions {
+		if oldestKey == "" || c.updated.Before(oldest) {
+			oldestKey, oldest = k, c.updated
+		}
+	}
+	delete(sc.sessions, oldestKey)
+}
+
+type sessionContextsKey struct{}
+
+// WithSessionContexts makes sc available to the context_set handler.
+func WithSessionContexts(ctx context.Context, sc *SessionContexts) context.Context {
+	return context.WithValue(ctx, sessionContextsKey{}, sc)
+}
+
+func sessionContextsFromContext(ctx context.Context) *SessionContexts {
+	sc, _ := ctx.Value(sessionContextsKey{}).(*SessionContexts)
+	return sc
+}
+
+// SessionID returns the MCP session ID of the call, or "" outside a session.
+func SessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// ContextSetHandler sets the session's default channel and thread, which
+// later calls use when they omit channel_id or thread_ts. Without arguments
+// it returns the current context.
+func (ch *ConversationsHandler) ContextSetHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ContextSetHandler called", zap.Any("params", request.Params))
+
+	sc := sessionContextsFromContext(ctx)
+	session := SessionID(ctx)
+	if sc == nil || session == "" {
+		return nil, errors.New("session context is not available: the client did not start an MCP session")
+	}
+
+	current, _ := sc.Get(session)
+	args := request.GetArguments()
+	_, hasChannel := args["channel_id"]
+	_, hasThread := args["thread_ts"]
+
+	if request.GetBool("clear", false) {
+		current = SessionContext{}
+	}
+
+	if hasChannel {
+		channel := strings.TrimSpace(request.GetString("channel_id", ""))
+		if channel == "" {
+			current = SessionContext{}
+		} else {
+			if ready, err := ch.apiProvider.IsReady(); !ready {
+				ch.logger.Error("API provider not ready", zap.Error(err))
+				return nil, err
+			}
+			id, err := ch.resolveChannelID(ctx, channel)
+			if err != nil {
+				ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+				return nil, err
+			}
+			if err := ch.apiProvider.CheckChannel(id); err != nil {
+				ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", id), zap.Error(err))
+				return nil, err
+			}
+			if id != current.ChannelID {
+				// A thread belongs to its channel.
+				current.ThreadTs = ""
+			}
+			current.ChannelID = id
+			current.ChannelName = ""
+			if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[id]; ok {
+				current.ChannelName = c.Name
+			}
+		}
+	}
+
+	if hasThread {
+		threadTs := strings.TrimSpace(request.GetString("thread_ts", ""))
+		if threadTs != "" {
+			if !strings.Contains(threadTs, ".") {
+				return nil, fmt.Errorf("thread_ts must be a message timestamp such as 1234567890.123456, got %q", threadTs)
+			}
+			if current.ChannelID == "" {
+				return nil, errors.New("thread_ts needs a channel: pass channel_id as well or set it first")
+			}
+		}
+		current.ThreadTs = threadTs
+	}
+
+	if hasChannel || hasThread || request.GetBool("clear", false) {
+		sc.Set(session, current)
+		ch.logger.Debug("Session context updated",
+			zap.String("session", session),
+			zap.String("channel", current.ChannelID),
+			zap.String("thread_ts", current.ThreadTs),
+		)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes([]SessionContext{current})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// UserStatus is the CSV output row for users_status_get.
+type UserStatus struct {
+	UserID           string `csv:"userID"`
+	UserName         string `csv:"userName"`
+	RealName         string `csv:"realName"`
+	StatusText       string `csv:"statusText"`
+	StatusEmoji      string `csv:"statusEmoji"`
+	StatusExpiration string `csv:"statusExpiration"`
+}
+
+type StatusHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+func NewStatusHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *StatusHandler {
+	return &StatusHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// UsersStatusGetHandler returns the custom status of a user, defaulting to the authenticated user.
+func (h *StatusHandler) UsersStatusGetHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("UsersStatusGetHandler called", zap.Any("params", request.Params))
+
+	if ready, err := h.apiProvider.IsReady(); !ready {
+		h.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	userID, err := h.resolveUser(ctx, request.GetString("user", ""))
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := h.apiProvider.Slack().GetUserProfileContext(ctx, &slack.GetUserProfileParameters{UserID: userID})
+	if err != nil {
+		h.logger.Error("GetUserProfileContext failed", zap.String("user", userID), zap.Error(err))
+		return nil, err
+	}
+
+	userName, realName, _ := getUserInfo(userID, h.apiProvider.ProvideUsersMap().Users)
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+	expiration, _ := text.FormatUnix(int64(profile.StatusExpiration), loc)
+
+	csvBytes, err := gocsv.MarshalBytes([]UserStatus{{
+		UserID:           userID,
+		UserName:         userName,
+		RealName:         realName,
+		StatusText:       profile.StatusText,
+		StatusEmoji:      profile.StatusEmoji,
+		StatusExpiration: expiration,
+	}})
+	if err != nil {
+		h.logger.Error("Failed to marshal status to CSV", zap.Error(err))
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(csvBytes)), nil
+}
+
+// UsersStatusSetHandler sets or clears the custom status of the authenticated user.
+func (h *StatusHandler) UsersStatusSetHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("UsersStatusSetHandler called", zap.Any("params", request.Params))
+
+	if h.apiProvider.IsBotToken() {
+		return nil, errors.New("users_status_set requires a user token (xoxp or xoxc/xoxd); bot tokens cannot change a user's status")
+	}
+
+	statusText := strings.TrimSpace(request.GetString("status_text", ""))
+	statusEmoji := strings.TrimSpace(request.GetString("status_emoji", ""))
+	if statusEmoji != "" && !strings.HasPrefix(statusEmoji, ":") {
+		statusEmoji = ":" + strings.Trim(statusEmoji, ":") + ":"
+	}
+
+	expiration, err := parseStatusExpiration(request.GetString("expiration", ""), h.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.apiProvider.Slack().SetUserCustomStatusContext(ctx, statusText, statusEmoji, expiration); err != nil {
+		h.logger.Error("SetUserCustomStatusContext failed", zap.Error(err))
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"status_text":  statusText,
+		"status_emoji": statusEmoji,
+		"cleared":      statusText == "" && statusEmoji == "",
+	}
+	if expiration > 0 {
+		result["status_expiration"], _ = text.FormatUnix(expiration, h.apiProvider.Config().Location())
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func (h *StatusHandler) resolveUser(ctx context.Context, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.EqualFold(raw, "me") || strings.EqualFold(raw, "@me") {
+		ar, err := h.apiProvider.Slack().AuthTestContext(ctx)
+		if err != nil {
+			h.logger.Error("Slack AuthTest failed", zap.Error(err))
+			return "", err
+		}
+		return ar.UserID, nil
+	}
+
+	raw = strings.TrimSuffix(strings.TrimPrefix(raw, "<@"), ">")
+	if isSlackUserIDPrefix(raw) {
+		return raw, nil
+	}
+
+	uid, ok := h.apiProvider.ProvideUsersMap().UsersInv[strings.TrimPrefix(raw, "@")]
+	if !ok {
+		return "", fmt.Errorf("user %q not found", raw)
+	}
+	return uid, nil
+}
+
+// parseStatusExpiration converts the expiration parameter into a Unix timestamp.
+// Accepted forms: empty or "0" (never), a duration ("30m", "2h"), a clock time
+// ("15:00", "3pm") meaning its next occurrence, an RFC3339 timestamp, or Unix seconds.
+func parseStatusExpiration(raw string, now time.Time) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "0" {
+		return 0, nil
+	}
+
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("expiration duration must be positive, got %q", raw)
+		}
+		return now.Add(d).Unix(), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		if !t.After(now) {
+			return 0, fmt.Errorf("expiration %q is in the past", raw)
+		}
+		return t.Unix(), nil
+	}
+
+	for _, layout := range []string{"15:04", "3pm", "3:04pm", "3PM", "3:04PM"} {
+		t, err := time.ParseInLocation(layout, raw, now.Location())
+		if err != nil {
+			continue
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at.Unix(), nil
+	}
+
+	if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		if secs <= now.Unix() {
+			return 0, fmt.Errorf("expiration %q is in the past", raw)
+		}
+		return secs, nil
+	}
+
+	return 0, fmt.Errorf("invalid expiration %q: use a duration (e.g. 30m), a time of day (e.g. 15:00 or 3pm), an RFC3339 timestamp or Unix seconds", raw)
+}
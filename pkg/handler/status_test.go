@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseStatusExpiration(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    time.Time
+		never   bool
+		wantErr bool
+	}{
+		{name: "empty never expires", raw: "", never: true},
+		{name: "zero never expires", raw: "0", never: true},
+		{name: "duration", raw: "30m", want: now.Add(30 * time.Minute)},
+		{name: "clock time later today", raw: "15:00", want: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)},
+		{name: "kitchen time later today", raw: "3pm", want: time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)},
+		{name: "clock time already passed rolls to tomorrow", raw: "09:00", want: time.Date(2025, 3, 11, 9, 0, 0, 0, time.UTC)},
+		{name: "rfc3339", raw: "2025-03-10T18:00:00Z", want: time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)},
+		{name: "unix seconds", raw: "1741640400", want: time.Unix(1741640400, 0)},
+		{name: "negative duration", raw: "-5m", wantErr: true},
+		{name: "past timestamp", raw: "2025-03-09T18:00:00Z", wantErr: true},
+		{name: "garbage", raw: "soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatusExpiration(tt.raw, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.never {
+				assert.Equal(t, int64(0), got)
+				return
+			}
+			assert.Equal(t, tt.want.Unix(), got)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// TeamProfileField is the CSV output row for team_info: one custom profile
+// field, whose ID keys the user's profile.fields map.
+type TeamProfileField struct {
+	ID             string `csv:"id"`
+	Label          string `csv:"label"`
+	Hint           string `csv:"hint"`
+	Type           string `csv:"type"`
+	Ordering       int    `csv:"ordering"`
+	PossibleValues string `csv:"possibleValues"`
+	IsHidden       bool   `csv:"isHidden"`
+}
+
+// TeamChannel is a default channel of the workspace.
+type TeamChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// TeamSummary is returned as JSON alongside the profile fields of team_info.
+type TeamSummary struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	Domain           string        `json:"domain"`
+	EmailDomain      string        `json:"email_domain,omitempty"`
+	URL              string        `json:"url,omitempty"`
+	Icon             string        `json:"icon,omitempty"`
+	IsVerified       bool          `json:"is_verified"`
+	EnterpriseID     string        `json:"enterprise_id,omitempty"`
+	EnterpriseName   string        `json:"enterprise_name,omitempty"`
+	EnterpriseDomain string        `json:"enterprise_domain,omitempty"`
+	DefaultChannels  []TeamChannel `json:"default_channels,omitempty"`
+	// DefaultChannelsError explains why the default channels are missing;
+	// Slack only shows them to Enterprise Grid admin tokens.
+	DefaultChannelsError string `json:"default_channels_error,omitempty"`
+	ProfileFieldsError   string `json:"profile_fields_error,omitempty"`
+}
+
+type TeamHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewTeamHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *TeamHandler {
+	return &TeamHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// TeamInfoHandler returns the custom profile field schema of the workspace as
+// CSV, followed by the workspace itself as JSON.
+func (h *TeamHandler) TeamInfoHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("TeamInfoHandler called", zap.Any("params", request.Params))
+
+	if ready, err := h.apiProvider.IsReady(); !ready {
+		h.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	team, err := h.apiProvider.Slack().GetTeamInfoContext(ctx)
+	if err != nil {
+		h.logger.Error("GetTeamInfoContext failed", zap.Error(err))
+		return nil, err
+	}
+	summary := TeamSummary{
+		ID:               team.ID,
+		Name:             team.Name,
+		Domain:           team.Domain,
+		EmailDomain:      team.EmailDomain,
+		URL:              team.URL,
+		Icon:             teamIcon(team.Icon),
+		IsVerified:       team.IsVerified,
+		EnterpriseID:     team.EnterpriseID,
+		EnterpriseName:   team.EnterpriseName,
+		EnterpriseDomain: team.EnterpriseDomain,
+	}
+
+	defaults, err := h.apiProvider.Slack().GetTeamDefaultChannelsContext(ctx, team.ID)
+	if err != nil {
+		h.logger.Debug("Default channels unavailable", zap.Error(err))
+		summary.DefaultChannelsError = err.Error()
+	}
+	channels := h.apiProvider.ProvideChannelsMaps().Channels
+	for _, id := range defaults {
+		summary.DefaultChannels = append(summary.DefaultChannels, TeamChannel{ID: id, Name: channels[id].Name})
+	}
+
+	includeHidden := request.GetBool("include_hidden", false)
+	fields := []TeamProfileField{}
+	profile, err := h.apiProvider.Slack().GetTeamProfileContext(ctx)
+	if err != nil {
+		h.logger.Warn("GetTeamProfileContext failed", zap.Error(err))
+		summary.ProfileFieldsError = err.Error()
+	} else {
+		for _, f := range profile.Fields {
+			if f.IsHidden && !includeHidden {
+				continue
+			}
+			fields = append(fields, TeamProfileField{
+				ID:             f.ID,
+				Label:          f.Label,
+				Hint:           f.Hint,
+				Type:           f.Type,
+				Ordering:       f.Ordering,
+				PossibleValues: strings.Join(f.PossibleValues, ";"),
+				IsHidden:       f.IsHidden,
+			})
+		}
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Ordering < fields[j].Ordering })
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&fields)
+	if err != nil {
+		h.logger.Error("Failed to marshal profile fields to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), summary)
+}
+
+// teamIcon picks the largest icon URL team.info returned.
+func teamIcon(icon map[string]any) string {
+	if url, ok := icon["image_original"].(string); ok && url != "" {
+		return url
+	}
+	for _, size := range []string{"image_230", "image_132", "image_102", "image_88", "image_68", "image_44", "image_34"} {
+		if url, ok := icon[size].(string); ok && url != "" {
+			return url
+		}
+	}
+	return ""
+}
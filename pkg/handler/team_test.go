@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitTeamIcon(t *testing.T) {
+	assert.Equal(t, "https://a/original.png", teamIcon(map[string]any{
+		"image_34":       "https://a/34.png",
+		"image_original": "https://a/original.png",
+	}))
+	assert.Equal(t, "https://a/132.png", teamIcon(map[string]any{
+		"image_34":      "https://a/34.png",
+		"image_132":     "https://a/132.png",
+		"image_default": true,
+	}))
+	assert.Empty(t, teamIcon(nil))
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// ThreadFollowResult is the JSON result of threads_follow and
+// threads_unfollow.
+type ThreadFollowResult struct {
+	ChannelID string `json:"channel_id"`
+	ThreadTs  string `json:"thread_ts"`
+	Following bool   `json:"following"`
+}
+
+// ThreadsFollowHandler subscribes the user to a thread, so its replies show
+// up in the Threads view and notify them.
+func (ch *ConversationsHandler) ThreadsFollowHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ThreadsFollowHandler called", zap.Any("params", request.Params))
+	return ch.setThreadSubscription(ctx, request, true)
+}
+
+// ThreadsUnfollowHandler unsubscribes the user from a thread, including one
+// they took part in, to stop notifications about its replies.
+func (ch *ConversationsHandler) ThreadsUnfollowHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ThreadsUnfollowHandler called", zap.Any("params", request.Params))
+	return ch.setThreadSubscription(ctx, request, false)
+}
+
+func (ch *ConversationsHandler) setThreadSubscription(ctx context.Context, request mcp.CallToolRequest, follow bool) (*mcp.CallToolResult, error) {
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+	toolConfig, err := ch.threadFollowToolPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	threadTs := request.GetString("thread_ts", "")
+	if threadTs == "" {
+		return nil, errors.New("thread_ts is required")
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if !isChannelAllowedForConfig(channel, toolConfig) {
+		ch.logger.Warn("Thread follow tools not allowed for channel", zap.String("channel", channel), zap.String("policy", toolConfig))
+		return nil, fmt.Errorf("following threads is not allowed for channel %q, applied policy: %s", channel, toolConfig)
+	}
+
+	if err := ch.apiProvider.Slack().ThreadSubscriptionContext(ctx, channel, threadTs, follow); err != nil {
+		ch.logger.Error("ThreadSubscriptionContext failed",
+			zap.String("channel", channel),
+			zap.String("thread_ts", threadTs),
+			zap.Bool("follow", follow),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(ThreadFollowResult{ChannelID: channel, ThreadTs: threadTs, Following: follow})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// threadFollowToolPolicy returns the SLACK_MCP_THREAD_FOLLOW_TOOL channel
+// policy, or an error when neither thread follow tool is enabled.
+func (ch *ConversationsHandler) threadFollowToolPolicy() (string, error) {
+	toolConfig := ch.apiProvider.Config().ThreadFollowTool
+	if toolConfig == "" {
+		if !ch.toolExplicitlyEnabled("threads_follow") && !ch.toolExplicitlyEnabled("threads_unfollow") {
+			ch.logger.Error("Thread follow tools disabled by default")
+			return "", errors.New(
+				"by default, the threads_follow and threads_unfollow tools are disabled because they change the user's notifications. " +
+					"To enable them, set the SLACK_MCP_THREAD_FOLLOW_TOOL environment variable to true, 1, or comma separated list of channels " +
+					"to limit where the MCP can follow threads, e.g. 'SLACK_MCP_THREAD_FOLLOW_TOOL=C1234567890'",
+			)
+		}
+		toolConfig = "true"
+	}
+	return toolConfig, nil
+}
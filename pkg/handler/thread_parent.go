@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxThreadParents bounds threadParentCache; it is emptied when full.
+const maxThreadParents = 1000
+
+// threadParentCache remembers the thread a message belongs to, which never
+// changes, so replying to the same thread again needs no lookup.
+type threadParentCache struct {
+	mu      sync.Mutex
+	parents map[string]string
+}
+
+func (c *threadParentCache) get(channel, ts string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	parent, ok := c.parents[channel+":"+ts]
+	return parent, ok
+}
+
+func (c *threadParentCache) put(channel, ts, parent string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.parents == nil || len(c.parents) >= maxThreadParents {
+		c.parents = make(map[string]string)
+	}
+	c.parents[channel+":"+ts] = parent
+}
+
+// threadParent returns the ts of the parent of the thread ts is in, given
+// the conversations.replies of ts: the parent's when ts is a reply, ts itself
+// when it is a parent or starts no thread yet.
+func threadParent(replies []slack.Message, ts string) string {
+	for _, m := range replies {
+		if m.Timestamp == ts && m.ThreadTimestamp != "" {
+			return m.ThreadTimestamp
+		}
+	}
+	// A reply's replies start with the thread's parent.
+	if len(replies) > 0 && replies[0].ThreadTimestamp != "" && replies[0].Timestamp != ts {
+		return replies[0].ThreadTimestamp
+	}
+	return ts
+}
+
+// resolveThreadTs returns the thread_ts to post a reply to ts with. Agents
+// often pass the ts of a reply, which Slack rejects or turns into a reply
+// nobody sees; the reply goes to the thread of that message instead. When
+// the lookup fails for another reason than the message not existing, such
+// as a missing history scope, ts is used as given.
+func (ch *ConversationsHandler) resolveThreadTs(ctx context.Context, channel, ts string) (string, error) {
+	if parent, ok := ch.threadParents.get(channel, ts); ok {
+		return parent, nil
+	}
+	replies, _, _, err := ch.apiProvider.Slack().GetConversationRepliesContext(ctx, &slack.GetConversationRepliesParameters{
+		ChannelID: channel,
+		Timestamp: ts,
+		Limit:     1,
+	})
+	if err != nil {
+		switch slackErrorCode(err) {
+		case "thread_not_found", "message_not_found":
+			return "", fmt.Errorf("thread_ts %s is not a message in %s", ts, channel)
+		}
+		ch.logger.Warn("Could not look up the thread of thread_ts, posting to it as given",
+			zap.String("channel", channel),
+			zap.String("thread_ts", ts),
+			zap.Error(err),
+		)
+		return ts, nil
+	}
+
+	parent := threadParent(replies, ts)
+	if parent != ts {
+		ch.logger.Debug("thread_ts is a reply, posting to its thread",
+			zap.String("channel", channel),
+			zap.String("thread_ts", ts),
+			zap.String("parent_ts", parent),
+		)
+	}
+	ch.threadParents.put(channel, ts, parent)
+	return parent, nil
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func msgAt(ts, threadTs string) slack.Message {
+	var m slack.Message
+	m.Timestamp, m.ThreadTimestamp = ts, threadTs
+	return m
+}
+
+func TestUnitThreadParent(t *testing.T) {
+	parent := msgAt("1700000000.000100", "1700000000.000100")
+	reply := msgAt("1700000050.000200", "1700000000.000100")
+
+	assert.Equal(t, parent.Timestamp, threadParent([]slack.Message{parent, reply}, reply.Timestamp), "reply goes to its thread")
+	assert.Equal(t, parent.Timestamp, threadParent([]slack.Message{parent}, reply.Timestamp), "only the parent returned")
+	assert.Equal(t, parent.Timestamp, threadParent([]slack.Message{reply}, reply.Timestamp), "only the reply returned")
+	assert.Equal(t, parent.Timestamp, threadParent([]slack.Message{parent}, parent.Timestamp))
+
+	plain := msgAt("1700000100.000300", "")
+	assert.Equal(t, plain.Timestamp, threadParent([]slack.Message{plain}, plain.Timestamp), "a message without replies starts a thread")
+	assert.Equal(t, plain.Timestamp, threadParent(nil, plain.Timestamp))
+}
+
+func TestUnitThreadParentCache(t *testing.T) {
+	var c threadParentCache
+	_, ok := c.get("C1", "1.2")
+	assert.False(t, ok)
+
+	c.put("C1", "1.2", "1.1")
+	parent, ok := c.get("C1", "1.2")
+	assert.True(t, ok)
+	assert.Equal(t, "1.1", parent)
+	_, ok = c.get("C2", "1.2")
+	assert.False(t, ok, "keyed by channel")
+
+	for i := range maxThreadParents {
+		c.put("C3", strconv.Itoa(i)+".0", "1.0")
+	}
+	assert.LessOrEqual(t, len(c.parents), maxThreadParents)
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// ThreadParticipantRow is a thread_participants row: a user who posted in
+// the thread or, with include_reactors, reacted to one of its messages.
+type ThreadParticipantRow struct {
+	UserID      string `csv:"UserID"`
+	UserName    string `csv:"UserName"`
+	RealName    string `csv:"RealName"`
+	DisplayName string `csv:"DisplayName"`
+	Title       string `csv:"Title"`
+	TZ          string `csv:"TZ"`
+	IsBot       bool   `csv:"IsBot"`
+	IsDeleted   bool   `csv:"IsDeleted"`
+	// Mention is the mrkdwn that at-mentions the user in a reply.
+	Mention string `csv:"Mention"`
+	// IsAuthor is set for the user who started the thread.
+	IsAuthor  bool   `csv:"IsAuthor"`
+	Messages  int    `csv:"Messages"`
+	Reactions int    `csv:"Reactions"`
+	FirstTime string `csv:"FirstTime"`
+	LastTime  string `csv:"LastTime"`
+}
+
+// ThreadParticipantsMetadata follows the thread_participants rows.
+type ThreadParticipantsMetadata struct {
+	ChannelID    string `json:"channel_id"`
+	ThreadTs     string `json:"thread_ts"`
+	Messages     int    `json:"messages"`
+	Participants int    `json:"participants"`
+}
+
+// threadParticipant is what is counted for one user of a thread.
+type threadParticipant struct {
+	userID      string
+	author      bool
+	messages    int
+	reactions   int
+	first, last string
+}
+
+// threadParticipants counts the messages and reactions of every user in
+// msgs, the parent first. Users who only reacted are left out unless
+// reactors is set. They are ordered by messages, then by first message.
+func threadParticipants(msgs []slack.Message, threadTs string, reactors bool) []*threadParticipant {
+	byUser := make(map[string]*threadParticipant)
+	get := func(id string) *threadParticipant {
+		p, ok := byUser[id]
+		if !ok {
+			p = &threadParticipant{userID: id}
+			byUser[id] = p
+		}
+		return p
+	}
+	for _, m := range msgs {
+		if m.User != "" {
+			p := get(m.User)
+			p.messages++
+			if m.Timestamp == threadTs {
+				p.author = true
+			}
+			if p.first == "" || slackTsLess(m.Timestamp, p.first) {
+				p.first = m.Timestamp
+			}
+			if p.last == "" || slackTsLess(p.last, m.Timestamp) {
+				p.last = m.Timestamp
+			}
+		}
+		for _, r := range m.Reactions {
+			for _, id := range r.Users {
+				get(id).reactions++
+			}
+		}
+	}
+
+	out := make([]*threadParticipant, 0, len(byUser))
+	for _, p := range byUser {
+		if p.messages == 0 && !reactors {
+			continue
+		}
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.messages != b.messages {
+			return a.messages > b.messages
+		}
+		if a.first != b.first {
+			// Reactors without messages have no first message and go last.
+			return b.first == "" || (a.first != "" && slackTsLess(a.first, b.first))
+		}
+		if a.reactions != b.reactions {
+			return a.reactions > b.reactions
+		}
+		return a.userID < b.userID
+	})
+	return out
+}
+
+func threadParticipantRow(p *threadParticipant, users map[string]slack.User, loc *time.Location) ThreadParticipantRow {
+	row := ThreadParticipantRow{
+		UserID:    p.userID,
+		UserName:  p.userID,
+		RealName:  p.userID,
+		Mention:   "<@" + p.userID + ">",
+		IsAuthor:  p.author,
+		Messages:  p.messages,
+		Reactions: p.reactions,
+	}
+	if u, ok := users[p.userID]; ok {
+		row.UserName = u.Name
+		row.RealName = u.RealName
+		row.DisplayName = u.Profile.DisplayName
+		row.Title = u.Profile.Title
+		row.TZ = u.TZ
+		row.IsBot = u.IsBot
+		row.IsDeleted = u.Deleted
+	}
+	if p.first != "" {
+		row.FirstTime, _, _ = text.FormatSlackTimestamp(p.first, loc)
+		row.LastTime, _, _ = text.FormatSlackTimestamp(p.last, loc)
+	}
+	return row
+}
+
+// ThreadParticipantsHandler lists who posted in a thread, with message
+// counts and profiles, computed from the whole thread so an agent can pick
+// whom to mention without reading every reply.
+func (ch *ConversationsHandler) ThreadParticipantsHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ThreadParticipantsHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolConversations(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse thread_participants params", zap.Error(err))
+		return nil, err
+	}
+	threadTs := strings.TrimSpace(request.GetString("thread_ts", ""))
+	if threadTs == "" {
+		return nil, errors.New("thread_ts must be a string")
+	}
+
+	msgs, err := ch.fetchThread(ctx, params.channel, threadTs, newProgressReporter(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	// Joins and other channel events are not participation.
+	posted := make([]slack.Message, 0, len(msgs))
+	for _, m := range msgs {
+		if m.SubType == "" || m.SubType == "bot_message" || m.SubType == "thread_broadcast" {
+			posted = append(posted, m)
+		}
+	}
+	// A reply's ts identifies its thread too; the parent is the author's.
+	parentTs := threadTs
+	if len(posted) > 0 && posted[0].ThreadTimestamp != "" {
+		parentTs = posted[0].ThreadTimestamp
+	}
+
+	participants := threadParticipants(posted, parentTs, request.GetBool("include_reactors", false))
+	ids := make([]string, 0, len(participants))
+	for _, p := range participants {
+		ids = append(ids, p.userID)
+	}
+	ch.apiProvider.EnsureUsers(ctx, ids)
+	users := ch.apiProvider.ProvideUsersMap().Users
+	rows := make([]ThreadParticipantRow, 0, len(participants))
+	for _, p := range participants {
+		rows = append(rows, threadParticipantRow(p, users, params.loc))
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&rows)
+	if err != nil {
+		ch.logger.Error("Failed to marshal thread participants to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), ThreadParticipantsMetadata{
+		ChannelID:    params.channel,
+		ThreadTs:     parentTs,
+		Messages:     len(posted),
+		Participants: len(rows),
+	})
+}
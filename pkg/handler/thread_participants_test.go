@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitThreadParticipants(t *testing.T) {
+	msg := func(user, ts string, reactors ...string) slack.Message {
+		m := slack.Message{Msg: slack.Msg{User: user, Timestamp: ts, ThreadTimestamp: "1718000000.000100"}}
+		if len(reactors) > 0 {
+			m.Reactions = []slack.ItemReaction{{Name: "eyes", Count: len(reactors), Users: reactors}}
+		}
+		return m
+	}
+	msgs := []slack.Message{
+		msg("U1", "1718000000.000100", "U3", "U4"),
+		msg("U2", "1718000100.000100"),
+		msg("U1", "1718000200.000100"),
+		msg("U2", "1718000300.000100", "U1"),
+		msg("U3", "1718000400.000100"),
+	}
+
+	got := threadParticipants(msgs, "1718000000.000100", false)
+	require.Len(t, got, 3)
+	assert.Equal(t, "U1", got[0].userID, "ties on messages go to who posted first")
+	assert.True(t, got[0].author)
+	assert.Equal(t, 2, got[0].messages)
+	assert.Equal(t, 1, got[0].reactions)
+	assert.Equal(t, "1718000000.000100", got[0].first)
+	assert.Equal(t, "1718000200.000100", got[0].last)
+	assert.Equal(t, "U2", got[1].userID)
+	assert.False(t, got[1].author)
+	assert.Equal(t, "U3", got[2].userID)
+	assert.Equal(t, 1, got[2].reactions)
+
+	got = threadParticipants(msgs, "1718000000.000100", true)
+	require.Len(t, got, 4)
+	assert.Equal(t, "U4", got[3].userID, "reactors without messages go last")
+	assert.Equal(t, 0, got[3].messages)
+
+	users := map[string]slack.User{"U1": {ID: "U1", Name: "ana", RealName: "Ana Lee", TZ: "Europe/Berlin", Profile: slack.UserProfile{Title: "SRE"}}}
+	row := threadParticipantRow(got[0], users, time.UTC)
+	assert.Equal(t, "ana", row.UserName)
+	assert.Equal(t, "SRE", row.Title)
+	assert.Equal(t, "<@U1>", row.Mention)
+	assert.Equal(t, "2024-06-10T06:13:20Z", row.FirstTime)
+	row = threadParticipantRow(got[3], users, time.UTC)
+	assert.Equal(t, "U4", row.UserName, "unknown users keep their ID")
+	assert.Empty(t, row.FirstTime)
+}
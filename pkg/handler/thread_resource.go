@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// threadResourcePageSize is the number of messages per thread resource page.
+// Pages are cut from the start of the thread, so replies posted later only
+// change the last page and earlier page URIs keep their contents.
+const threadResourcePageSize = 100
+
+// ThreadResourcePage describes one page of a thread resource.
+type ThreadResourcePage struct {
+	URI      string `json:"uri"`
+	Page     int    `json:"page"`
+	FirstTs  string `json:"first_ts"`
+	LastTs   string `json:"last_ts"`
+	Messages int    `json:"messages"`
+}
+
+// ThreadResourceIndex is served at slack://<workspace>/threads/<channel>/<ts>:
+// the thread summary plus the URIs of its pages.
+type ThreadResourceIndex struct {
+	ThreadMetadata
+	PageSize int                  `json:"page_size"`
+	Pages    []ThreadResourcePage `json:"pages"`
+}
+
+// ThreadsResource serves a thread as a JSON index at
+// slack://<workspace>/threads/<channel>/<ts> and its messages as CSV pages at
+// slack://<workspace>/threads/<channel>/<ts>/pages/<n>, numbered from 1.
+func (ch *ConversationsHandler) ThreadsResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ch.logger.Debug("ThreadsResource called", zap.Any("params", request.Params))
+
+	if authenticated, err := auth.IsAuthenticated(ctx, ch.apiProvider.ServerTransport(), ch.logger); !authenticated {
+		ch.logger.Error("Authentication failed for threads resource", zap.Error(err))
+		return nil, err
+	}
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	base, channel, threadTs, page, err := parseThreadResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	channel, err = ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+
+	msgs, err := ch.fetchThread(ctx, channel, threadTs, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("thread %s not found in channel %s", threadTs, channel)
+	}
+	pageCount := (len(msgs) + threadResourcePageSize - 1) / threadResourcePageSize
+
+	if page == 0 {
+		index := ThreadResourceIndex{
+			ThreadMetadata: ch.buildThreadMetadata(channel, threadTs, msgs),
+			PageSize:       threadResourcePageSize,
+			Pages:          make([]ThreadResourcePage, 0, pageCount),
+		}
+		index.Returned = len(msgs)
+		for p := 1; p <= pageCount; p++ {
+			chunk := threadResourceChunk(len(msgs), p)
+			index.Pages = append(index.Pages, ThreadResourcePage{
+				URI:      base + "/pages/" + strconv.Itoa(p),
+				Page:     p,
+				FirstTs:  msgs[chunk.from].Timestamp,
+				LastTs:   msgs[chunk.to-1].Timestamp,
+				Messages: chunk.to - chunk.from,
+			})
+		}
+
+		indexBytes, err := json.Marshal(index)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(indexBytes),
+			},
+		}, nil
+	}
+
+	if page > pageCount {
+		return nil, fmt.Errorf("thread %s has %d pages, page %d does not exist", threadTs, pageCount, page)
+	}
+	chunk := threadResourceChunk(len(msgs), page)
+	messages := ch.convertMessagesFromHistory(msgs[chunk.from:chunk.to], channel, true, false, false, renderPlain, ch.apiProvider.Config().Location())
+	csvBytes, err := marshalMessages(messages, MessageColumns{})
+	if err != nil {
+		return nil, err
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/csv",
+			Text:     string(csvBytes),
+		},
+	}, nil
+}
+
+type threadChunk struct {
+	from, to int
+}
+
+// threadResourceChunk returns the message range of page (1-based) in a thread
+// of total messages.
+func threadResourceChunk(total, page int) threadChunk {
+	from := (page - 1) * threadResourcePageSize
+	return threadChunk{from: from, to: min(from+threadResourcePageSize, total)}
+}
+
+// parseThreadResourceURI splits slack://<ws>/threads/<channel>/<ts>[/pages/<n>]
+// into the thread URI, channel, thread ts and page, which is 0 for the index.
+func parseThreadResourceURI(uri string) (base, channel, threadTs string, page int, err error) {
+	prefix, rest, ok := strings.Cut(uri, "/threads/")
+	if !ok {
+		return "", "", "", 0, fmt.Errorf("invalid thread resource URI %q", uri)
+	}
+	parts := strings.Split(rest, "/")
+	switch {
+	case len(parts) == 2:
+	case len(parts) == 4 && parts[2] == "pages":
+		page, err = strconv.Atoi(parts[3])
+		if err != nil || page < 1 {
+			return "", "", "", 0, fmt.Errorf("invalid page %q in thread resource URI, pages are numbered from 1", parts[3])
+		}
+	default:
+		return "", "", "", 0, fmt.Errorf("invalid thread resource URI %q, want slack://<workspace>/threads/<channel>/<ts>[/pages/<n>]", uri)
+	}
+
+	channel, threadTs = parts[0], parts[1]
+	if channel == "" {
+		return "", "", "", 0, errors.New("thread resource URI has no channel")
+	}
+	if !strings.Contains(threadTs, ".") {
+		return "", "", "", 0, fmt.Errorf("thread ts %q must be a timestamp in format 1234567890.123456", threadTs)
+	}
+	return prefix + "/threads/" + channel + "/" + threadTs, channel, threadTs, page, nil
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseThreadResourceURI(t *testing.T) {
+	base, channel, ts, page, err := parseThreadResourceURI("slack://acme/threads/C0123456789/1700000000.000100")
+	require.NoError(t, err)
+	assert.Equal(t, "slack://acme/threads/C0123456789/1700000000.000100", base)
+	assert.Equal(t, "C0123456789", channel)
+	assert.Equal(t, "1700000000.000100", ts)
+	assert.Equal(t, 0, page)
+
+	base, _, _, page, err = parseThreadResourceURI("slack://acme/threads/C0123456789/1700000000.000100/pages/3")
+	require.NoError(t, err)
+	assert.Equal(t, "slack://acme/threads/C0123456789/1700000000.000100", base)
+	assert.Equal(t, 3, page)
+
+	for _, uri := range []string{
+		"slack://acme/threads/C0123456789",
+		"slack://acme/threads/C0123456789/1700000000",
+		"slack://acme/threads/C0123456789/1700000000.000100/pages/0",
+		"slack://acme/threads/C0123456789/1700000000.000100/pages/x",
+		"slack://acme/threads/C0123456789/1700000000.000100/other/1",
+		"slack://acme/digests/daily",
+	} {
+		_, _, _, _, err := parseThreadResourceURI(uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestUnitThreadResourceChunk(t *testing.T) {
+	assert.Equal(t, threadChunk{from: 0, to: 100}, threadResourceChunk(250, 1))
+	assert.Equal(t, threadChunk{from: 200, to: 250}, threadResourceChunk(250, 3))
+	assert.Equal(t, threadChunk{from: 0, to: 1}, threadResourceChunk(1, 1))
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultThreadSearchLimit = 20
+	maxThreadSearchLimit     = 100
+	maxThreadSearchContext   = 5
+)
+
+// ThreadSearchMetadata follows the thread_search rows.
+type ThreadSearchMetadata struct {
+	ChannelID string `json:"channel_id"`
+	ThreadTs  string `json:"thread_ts"`
+	// Scanned counts the messages of the thread, the parent included.
+	Scanned int `json:"scanned"`
+	Matched int `json:"matched"`
+	// Matches are the ts of the returned matching messages; the other rows
+	// are context around them.
+	Matches []string `json:"matches"`
+	// Truncated is set when more messages matched than limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// threadQuery is a parsed thread_search query: every term must appear in a
+// message and no excluded one may.
+type threadQuery struct {
+	terms    []string
+	excluded []string
+}
+
+// parseThreadQuery splits q into lower-cased words and "quoted phrases";
+// a leading - excludes a word or phrase.
+func parseThreadQuery(q string) threadQuery {
+	var query threadQuery
+	add := func(term string, exclude bool) {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "" {
+			return
+		}
+		if exclude {
+			query.excluded = append(query.excluded, term)
+		} else {
+			query.terms = append(query.terms, term)
+		}
+	}
+
+	for rest := strings.TrimSpace(q); rest != ""; rest = strings.TrimSpace(rest) {
+		exclude := false
+		if strings.HasPrefix(rest, "-") && len(rest) > 1 {
+			exclude, rest = true, rest[1:]
+		}
+		if strings.HasPrefix(rest, `"`) {
+			if end := strings.Index(rest[1:], `"`); end >= 0 {
+				add(rest[1:end+1], exclude)
+				rest = rest[end+2:]
+				continue
+			}
+			rest = rest[1:]
+		}
+		end := strings.IndexFunc(rest, func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' })
+		if end < 0 {
+			end = len(rest)
+		}
+		add(rest[:end], exclude)
+		rest = rest[end:]
+	}
+	return query
+}
+
+func (q threadQuery) match(text string) bool {
+	for _, t := range q.excluded {
+		if strings.Contains(text, t) {
+			return false
+		}
+	}
+	for _, t := range q.terms {
+		if !strings.Contains(text, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// threadSearchText is the lower-cased text a thread message is matched on:
+// its text, its attachments and the names of its files.
+func threadSearchText(m slack.Message) string {
+	parts := []string{m.Text}
+	for _, a := range m.Attachments {
+		parts = append(parts, a.Pretext, a.Title, a.Text, a.Fallback)
+	}
+	for _, f := range m.Files {
+		parts = append(parts, f.Title, f.Name)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+// threadSearch returns the indexes into msgs of the first limit messages by
+// user, when set, that match q, with context messages before and after
+// each, in order, and the number of messages that matched in all.
+func threadSearch(msgs []slack.Message, q threadQuery, user string, contextSize, limit int) (rows []int, matches []int, matched int) {
+	include := make([]bool, len(msgs))
+	for i, m := range msgs {
+		if user != "" && m.User != user {
+			continue
+		}
+		if !q.match(threadSearchText(m)) {
+			continue
+		}
+		matched++
+		if len(matches) == limit {
+			continue
+		}
+		matches = append(matches, i)
+		for j := max(0, i-contextSize); j <= min(len(msgs)-1, i+contextSize); j++ {
+			include[j] = true
+		}
+	}
+	for i, ok := range include {
+		if ok {
+			rows = append(rows, i)
+		}
+	}
+	return rows, matches, matched
+}
+
+// ThreadSearchHandler finds the messages of one thread that contain every
+// word of query, fetching the whole thread and filtering it here, so a long
+// incident thread can be searched without loading every reply into the
+// conversation. Slack's search cannot be limited to a thread.
+func (ch *ConversationsHandler) ThreadSearchHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ThreadSearchHandler called", zap.Any("params", request.Params))
+
+	params, err := ch.parseParamsToolConversations(ctx, request)
+	if err != nil {
+		ch.logger.Error("Failed to parse thread_search params", zap.Error(err))
+		return nil, err
+	}
+	threadTs := strings.TrimSpace(request.GetString("thread_ts", ""))
+	if threadTs == "" {
+		return nil, errors.New("thread_ts must be a string")
+	}
+	query := parseThreadQuery(request.GetString("query", ""))
+	if len(query.terms) == 0 {
+		return nil, errors.New("query must contain at least one word or quoted phrase to look for")
+	}
+	limit := request.GetInt("limit", defaultThreadSearchLimit)
+	if limit < 1 || limit > maxThreadSearchLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxThreadSearchLimit)
+	}
+	contextSize := request.GetInt("context", 0)
+	if contextSize < 0 || contextSize > maxThreadSearchContext {
+		return nil, fmt.Errorf("context must be between 0 and %d", maxThreadSearchContext)
+	}
+	var user string
+	if raw := request.GetString("user", ""); raw != "" {
+		formatted, err := ch.paramFormatUser(raw)
+		if err != nil {
+			return nil, err
+		}
+		user = strings.TrimSuffix(strings.TrimPrefix(formatted, "<@"), ">")
+	}
+
+	msgs, err := ch.fetchThread(ctx, params.channel, threadTs, newProgressReporter(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	if !params.activity {
+		// Drop joins and other activity up front, so it is neither matched
+		// nor shown as context.
+		msgs = slices.DeleteFunc(msgs, func(m slack.Message) bool {
+			return m.SubType != "" && m.SubType != "bot_message" && m.SubType != "thread_broadcast"
+		})
+	}
+
+	rows, matches, matched := threadSearch(msgs, query, user, contextSize, limit)
+	found := make([]slack.Message, 0, len(rows))
+	for _, i := range rows {
+		found = append(found, msgs[i])
+	}
+	meta := ThreadSearchMetadata{
+		ChannelID: params.channel,
+		ThreadTs:  threadTs,
+		Scanned:   len(msgs),
+		Matched:   matched,
+		Matches:   make([]string, 0, len(matches)),
+		Truncated: matched > len(matches),
+	}
+	for _, i := range matches {
+		meta.Matches = append(meta.Matches, msgs[i].Timestamp)
+	}
+	ch.logger.Debug("Searched thread",
+		zap.String("channel", params.channel),
+		zap.String("thread_ts", threadTs),
+		zap.Int("scanned", meta.Scanned),
+		zap.Int("matched", matched),
+	)
+
+	ch.apiProvider.EnsureUsers(ctx, historyUserIDs(found))
+	messages := ch.convertMessagesFromHistory(found, params.channel, params.activity, params.unfurls, false, params.render, params.loc)
+	if messages, err = classifyMessages(ctx, request.Params.Name, messages); err != nil {
+		return nil, err
+	}
+	res, err := MarshalMessagesToCSV(messages, messageColumnsOf(ctx, request))
+	if err != nil {
+		return nil, err
+	}
+	return withJSONMetadata(res, meta)
+}
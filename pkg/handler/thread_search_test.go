@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitParseThreadQuery(t *testing.T) {
+	q := parseThreadQuery(`Rollback "root cause" -staging -"false alarm"`)
+	assert.Equal(t, []string{"rollback", "root cause"}, q.terms)
+	assert.Equal(t, []string{"staging", "false alarm"}, q.excluded)
+
+	assert.Empty(t, parseThreadQuery("  ").terms)
+	assert.Equal(t, []string{"unterminated", "phrase"}, parseThreadQuery(`"unterminated phrase`).terms, "an unclosed quote is dropped")
+	assert.Equal(t, []string{"-"}, parseThreadQuery(`-`).terms, "a lone dash is a word")
+}
+
+func TestUnitThreadSearch(t *testing.T) {
+	msg := func(user, text string) slack.Message {
+		return slack.Message{Msg: slack.Msg{User: user, Text: text}}
+	}
+	withFile := msg("U2", "see attached")
+	withFile.Files = []slack.File{{Name: "rollback-plan.pdf"}}
+	msgs := []slack.Message{
+		msg("U1", "Checkout errors are spiking"),
+		msg("U2", "Starting rollback of 2024.06.1"),
+		msg("U3", "Rollback on staging is done"),
+		withFile,
+		msg("U1", "Root cause: the ROLLBACK missed a migration"),
+		msg("U3", "Closing the incident"),
+	}
+
+	rows, matches, matched := threadSearch(msgs, parseThreadQuery("rollback"), "", 0, 20)
+	assert.Equal(t, []int{1, 2, 3, 4}, matches, "text and file names match, case-insensitively")
+	assert.Equal(t, matches, rows)
+	assert.Equal(t, 4, matched)
+
+	_, matches, _ = threadSearch(msgs, parseThreadQuery("rollback -staging"), "", 0, 20)
+	assert.Equal(t, []int{1, 3, 4}, matches)
+
+	_, matches, _ = threadSearch(msgs, parseThreadQuery("rollback"), "U1", 0, 20)
+	assert.Equal(t, []int{4}, matches)
+
+	rows, matches, matched = threadSearch(msgs, parseThreadQuery(`"root cause"`), "", 1, 20)
+	assert.Equal(t, []int{4}, matches)
+	assert.Equal(t, []int{3, 4, 5}, rows, "context adds the neighbouring messages")
+	assert.Equal(t, 1, matched)
+
+	rows, matches, matched = threadSearch(msgs, parseThreadQuery("rollback"), "", 1, 2)
+	assert.Equal(t, []int{1, 2}, matches)
+	assert.Equal(t, []int{0, 1, 2, 3}, rows)
+	assert.Equal(t, 4, matched, "matches beyond the limit are still counted")
+}
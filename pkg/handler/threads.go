@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultThreadsLimit = 20
+	maxThreadsLimit     = 50
+	// maxThreadsPages bounds how far unread_only pages through the Threads view.
+	maxThreadsPages = 10
+)
+
+// ThreadDigest is the CSV output row for activity_threads.
+type ThreadDigest struct {
+	ChannelID        string `csv:"channelID"`
+	ChannelName      string `csv:"channelName"`
+	ThreadTs         string `csv:"threadTs"`
+	Time             string `csv:"time"`
+	TimeHuman        string `csv:"timeHuman"`
+	UserName         string `csv:"userName"`
+	Snippet          string `csv:"snippet"`
+	ReplyCount       int    `csv:"replyCount"`
+	LatestReply      string `csv:"latestReply"`
+	LastReplyUser    string `csv:"lastReplyUser"`
+	LastReplySnippet string `csv:"lastReplySnippet"`
+	UnreadReplies    int    `csv:"unreadReplies"`
+	HasUnread        bool   `csv:"hasUnread"`
+	Permalink        string `csv:"permalink"`
+	Cursor           string `csv:"cursor"`
+}
+
+// ThreadsDigestMetadata summarises the Threads view beyond the returned page.
+type ThreadsDigestMetadata struct {
+	TotalUnreadReplies int `json:"total_unread_replies"`
+	NewThreadsCount    int `json:"new_threads_count"`
+}
+
+type ThreadsHandler struct {
+	apiProvider *provider.ApiProvider
+	logger      *zap.Logger
+}
+
+func NewThreadsHandler(apiProvider *provider.ApiProvider, logger *zap.Logger) *ThreadsHandler {
+	return &ThreadsHandler{
+		apiProvider: apiProvider,
+		logger:      logger,
+	}
+}
+
+// ThreadsDigestHandler lists the threads the authenticated user participates
+// in, most recently active first, flagging those with replies since last read.
+func (h *ThreadsHandler) ThreadsDigestHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("ThreadsDigestHandler called", zap.Any("params", request.Params))
+
+	limit := request.GetInt("limit", defaultThreadsLimit)
+	if limit < 1 || limit > maxThreadsLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d, got %d", maxThreadsLimit, limit)
+	}
+	unreadOnly := request.GetBool("unread_only", false)
+	cursor, err := decodeCursor("activity_threads", "", request.GetString("cursor", ""))
+	if err != nil {
+		return nil, err
+	}
+	loc, err := parseTimezoneParam(h.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceURL := ""
+	if ar, err := h.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		workspaceURL = strings.TrimRight(ar.URL, "/")
+	}
+
+	var (
+		digests []ThreadDigest
+		meta    ThreadsDigestMetadata
+		next    string
+	)
+	for page := 0; page < maxThreadsPages; page++ {
+		view, err := h.apiProvider.Slack().ThreadsViewContext(ctx, cursor, limit)
+		if err != nil {
+			h.logger.Error("ThreadsViewContext failed", zap.Error(err))
+			return nil, err
+		}
+		if page == 0 {
+			meta = ThreadsDigestMetadata{
+				TotalUnreadReplies: view.TotalUnreadReplies,
+				NewThreadsCount:    view.NewThreadsCount,
+			}
+		}
+
+		for _, t := range view.Threads {
+			if !h.apiProvider.ChannelAllowed(t.RootMsg.Channel) {
+				continue
+			}
+			d := h.toDigest(t, workspaceURL, loc)
+			if unreadOnly && !d.HasUnread {
+				continue
+			}
+			digests = append(digests, d)
+		}
+
+		next = ""
+		if view.HasMore && view.MaxTs != "" {
+			next = view.MaxTs
+		}
+		if !unreadOnly || len(digests) >= limit || next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if len(digests) > limit {
+		digests = digests[:limit]
+	}
+	if len(digests) > 0 && next != "" {
+		digests[len(digests)-1].Cursor = encodeCursor("activity_threads", "", next)
+	}
+
+	csvBytes, err := gocsv.MarshalBytes(&digests)
+	if err != nil {
+		h.logger.Error("Failed to marshal threads to CSV", zap.Error(err))
+		return nil, err
+	}
+	return withJSONMetadata(mcp.NewToolResultText(string(csvBytes)), meta)
+}
+
+func (h *ThreadsHandler) toDigest(t provider.ThreadView, workspaceURL string, loc *time.Location) ThreadDigest {
+	root := t.RootMsg
+	users := h.apiProvider.ProvideUsersMap().Users
+
+	channelName := ""
+	if c, ok := h.apiProvider.ProvideChannelsMaps().Channels[root.Channel]; ok {
+		channelName = c.Name
+	}
+
+	rootUser, _, _ := getUserInfo(root.User, users)
+	rootTime, rootTimeHuman, err := text.FormatSlackTimestamp(root.Timestamp, loc)
+	if err != nil {
+		rootTime = root.Timestamp
+	}
+
+	latestReply := ""
+	if root.LatestReply != "" {
+		if latestReply, _, err = text.FormatSlackTimestamp(root.LatestReply, loc); err != nil {
+			latestReply = root.LatestReply
+		}
+	}
+
+	var lastReplyUser, lastReplySnippet string
+	if last, ok := latestThreadReply(t.LatestReplies); ok {
+		lastReplyUser, _, _ = getUserInfo(last.User, users)
+		lastReplySnippet = threadSnippet(last.Text)
+	}
+
+	unread := len(t.UnreadReplies)
+	hasUnread := unread > 0 ||
+		(root.LastRead != "" && root.LatestReply != "" && slackTsLess(root.LastRead, root.LatestReply))
+
+	permalink := ""
+	if workspaceURL != "" && root.Channel != "" && root.Timestamp != "" {
+		permalink = workspaceURL + "/archives/" + root.Channel + "/p" + strings.ReplaceAll(root.Timestamp, ".", "")
+	}
+
+	return ThreadDigest{
+		ChannelID:        root.Channel,
+		ChannelName:      channelName,
+		ThreadTs:         root.Timestamp,
+		Time:             rootTime,
+		TimeHuman:        rootTimeHuman,
+		UserName:         rootUser,
+		Snippet:          threadSnippet(root.Text),
+		ReplyCount:       root.ReplyCount,
+		LatestReply:      latestReply,
+		LastReplyUser:    lastReplyUser,
+		LastReplySnippet: lastReplySnippet,
+		UnreadReplies:    unread,
+		HasUnread:        hasUnread,
+		Permalink:        permalink,
+	}
+}
+
+func latestThreadReply(replies []slack.Msg) (slack.Msg, bool) {
+	if len(replies) == 0 {
+		return slack.Msg{}, false
+	}
+	latest := replies[0]
+	for _, r := range replies[1:] {
+		if slackTsLess(latest.Timestamp, r.Timestamp) {
+			latest = r
+		}
+	}
+	return latest, true
+}
+
+func threadSnippet(raw string) string {
+	snippet := text.ProcessText(raw)
+	if r := []rune(snippet); len(r) > mentionSnippetLength {
+		snippet = string(r[:mentionSnippetLength]) + "…"
+	}
+	return snippet
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitLatestThreadReply(t *testing.T) {
+	_, ok := latestThreadReply(nil)
+	assert.False(t, ok)
+
+	last, ok := latestThreadReply([]slack.Msg{
+		{Timestamp: "1700000002.000100", User: "U2"},
+		{Timestamp: "1700000010.000000", User: "U3"},
+		{Timestamp: "1700000009.999999", User: "U1"},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "U3", last.User)
+}
+
+func TestUnitThreadSnippet(t *testing.T) {
+	assert.Equal(t, "short reply", threadSnippet("short reply"))
+
+	long := threadSnippet(strings.Repeat("é", mentionSnippetLength+10))
+	assert.Equal(t, mentionSnippetLength+1, len([]rune(long)))
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
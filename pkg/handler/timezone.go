@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// parseTimezoneParam reads the optional tz parameter shared by tools that
+// return timestamps, falling back to the configured SLACK_MCP_TIMEZONE.
+func parseTimezoneParam(ap *provider.ApiProvider, request mcp.CallToolRequest) (*time.Location, error) {
+	name := strings.TrimSpace(request.GetString("tz", ""))
+	if name == "" {
+		return ap.Config().Location(), nil
+	}
+	return text.LoadTimezone(name)
+}
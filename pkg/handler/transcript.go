@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	transcriptFormatMarkdown = "markdown"
+	transcriptFormatHTML     = "html"
+	transcriptOutputInline   = "inline"
+	transcriptOutputFile     = "file"
+	// defaultTranscriptWindow applies when oldest is not set.
+	defaultTranscriptWindow = 7 * 24 * time.Hour
+	// maxTranscriptMessages bounds one transcript, thread replies included.
+	maxTranscriptMessages = 10000
+	transcriptPageSize    = 200
+)
+
+var (
+	// transcriptFileNameRe keeps file names to a safe character set.
+	transcriptFileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	markdownLinkRe       = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+)
+
+// TranscriptMetadata describes a rendered transcript.
+type TranscriptMetadata struct {
+	Channel  string `json:"channel"`
+	Format   string `json:"format"`
+	Messages int    `json:"messages"`
+	Threads  int    `json:"threads"`
+	// Truncated is set when the transcript stopped at the message cap; the
+	// rest of the range is missing.
+	Truncated bool   `json:"truncated"`
+	Path      string `json:"path,omitempty"`
+	Bytes     int    `json:"bytes"`
+}
+
+type transcriptEntry struct {
+	msg     slack.Message
+	replies []slack.Message
+}
+
+type transcript struct {
+	title    string
+	from, to time.Time
+	loc      *time.Location
+	entries  []transcriptEntry
+	users    map[string]slack.User
+	resolver text.MrkdwnResolver
+}
+
+// ConversationsTranscriptHandler renders a channel over a date range, or a
+// single thread, as a readable Markdown or HTML document with names resolved,
+// thread replies indented under their parent and attachment links. The
+// document is returned inline or written to SLACK_MCP_TRANSCRIPT_DIR.
+func (ch *ConversationsHandler) ConversationsTranscriptHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("ConversationsTranscriptHandler called", zap.Any("params", request.Params))
+
+	if ready, err := ch.apiProvider.IsReady(); !ready {
+		ch.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	channel := request.GetString("channel_id", "")
+	if channel == "" {
+		return nil, errors.New("channel_id is required")
+	}
+	channelName := channel
+	channel, err := ch.resolveChannelID(ctx, channel)
+	if err != nil {
+		ch.logger.Error("Channel not found", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if err := ch.apiProvider.CheckChannel(channel); err != nil {
+		ch.logger.Warn("Channel rejected by channel policy", zap.String("channel", channel), zap.Error(err))
+		return nil, err
+	}
+	if c, ok := ch.apiProvider.ProvideChannelsMaps().Channels[channel]; ok && c.Name != "" {
+		channelName = c.Name
+	}
+
+	format := strings.ToLower(strings.TrimSpace(request.GetString("format", transcriptFormatMarkdown)))
+	if format != transcriptFormatMarkdown && format != transcriptFormatHTML {
+		return nil, fmt.Errorf("invalid format %q: must be %q or %q", format, transcriptFormatMarkdown, transcriptFormatHTML)
+	}
+	output := strings.ToLower(strings.TrimSpace(request.GetString("output", transcriptOutputInline)))
+	if output != transcriptOutputInline && output != transcriptOutputFile {
+		return nil, fmt.Errorf("invalid output %q: must be %q or %q", output, transcriptOutputInline, transcriptOutputFile)
+	}
+	var path string
+	if output == transcriptOutputFile {
+		if path, err = ch.transcriptPath(request.GetString("filename", ""), format); err != nil {
+			return nil, err
+		}
+	}
+	loc, err := parseTimezoneParam(ch.apiProvider, request)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := transcript{loc: loc}
+	meta := TranscriptMetadata{Channel: channel, Format: format}
+	if threadTs := strings.TrimSpace(request.GetString("thread_ts", "")); threadTs != "" {
+		msgs, err := ch.fetchThread(ctx, channel, threadTs, newProgressReporter(ctx, request))
+		if err != nil {
+			return nil, err
+		}
+		if len(msgs) == 0 {
+			return nil, fmt.Errorf("thread %s not found in %s", threadTs, channelName)
+		}
+		if len(msgs) > maxTranscriptMessages {
+			msgs, meta.Truncated = msgs[:maxTranscriptMessages], true
+		}
+		doc.title = "Thread in " + channelName
+		doc.entries = []transcriptEntry{{msg: msgs[0], replies: msgs[1:]}}
+		doc.from, _ = text.SlackTimestampToTime(msgs[0].Timestamp)
+		doc.to, _ = text.SlackTimestampToTime(msgs[len(msgs)-1].Timestamp)
+	} else {
+		oldest, err := parseTimeBound(strings.TrimSpace(request.GetString("oldest", "")), loc, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid oldest: %w", err)
+		}
+		if oldest == "" {
+			oldest = fmt.Sprintf("%d.000000", time.Now().Add(-defaultTranscriptWindow).Unix())
+		}
+		latest, err := parseTimeBound(strings.TrimSpace(request.GetString("latest", "")), loc, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid latest: %w", err)
+		}
+		if latest != "" && !slackTsLess(oldest, latest) {
+			return nil, fmt.Errorf("oldest %q must be before latest %q", oldest, latest)
+		}
+
+		doc.title = "Transcript of " + channelName
+		doc.from, _ = text.SlackTimestampToTime(oldest)
+		doc.to = time.Now()
+		if latest != "" {
+			doc.to, _ = text.SlackTimestampToTime(latest)
+		}
+		progress := newProgressReporter(ctx, request)
+		doc.entries, meta.Truncated, err = ch.fetchTranscript(ctx, channel, oldest, latest, request.GetBool("include_threads", true), progress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		userIDs []string
+		fetched []slack.Message
+	)
+	for _, e := range doc.entries {
+		meta.Messages += 1 + len(e.replies)
+		if len(e.replies) > 0 {
+			meta.Threads++
+		}
+		userIDs = append(userIDs, e.msg.User)
+		userIDs = append(userIDs, historyUserIDs(e.replies)...)
+		fetched = append(append(fetched, e.msg), e.replies...)
+	}
+	ch.apiProvider.EnsureUsers(ctx, userIDs)
+	ch.emitEmbeddings(ctx, "conversations_transcript", channel, fetched)
+	doc.users = ch.apiProvider.ProvideUsersMap().Users
+	doc.resolver = newMrkdwnResolver(ch.apiProvider)
+
+	var rendered string
+	if format == transcriptFormatHTML {
+		rendered = doc.html()
+	} else {
+		rendered = doc.markdown()
+	}
+	meta.Bytes = len(rendered)
+
+	if path == "" {
+		return withJSONMetadata(mcp.NewToolResultText(rendered), meta)
+	}
+	if err := os.WriteFile(path, []byte(rendered), 0644); err != nil {
+		ch.logger.Error("Failed to write transcript", zap.String("path", path), zap.Error(err))
+		return nil, fmt.Errorf("failed to write transcript: %w", err)
+	}
+	meta.Path = path
+	ch.logger.Info("Transcript written",
+		zap.String("channel", channel),
+		zap.String("path", path),
+		zap.Int("messages", meta.Messages),
+	)
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(metaBytes)), nil
+}
+
+// fetchTranscript reads the channel history between oldest and latest,
+// oldest first, with the replies of each thread when includeThreads is set.
+// progress gets the share of the range covered so far.
+func (ch *ConversationsHandler) fetchTranscript(ctx context.Context, channel, oldest, latest string, includeThreads bool, progress *progressReporter) ([]transcriptEntry, bool, error) {
+	params := slack.GetConversationHistoryParameters{
+		ChannelID: channel,
+		Limit:     transcriptPageSize,
+		Oldest:    oldest,
+		Latest:    latest,
+	}
+
+	var (
+		entries []transcriptEntry
+		total   int
+	)
+	for {
+		history, err := ch.apiProvider.Slack().GetConversationHistoryContext(ctx, &params)
+		if err != nil {
+			ch.logger.Error("GetConversationHistoryContext failed", zap.Error(err))
+			return nil, false, err
+		}
+		for _, msg := range history.Messages {
+			entry := transcriptEntry{msg: msg}
+			if includeThreads && msg.ReplyCount > 0 && msg.ThreadTimestamp == msg.Timestamp {
+				thread, err := ch.fetchThread(ctx, channel, msg.Timestamp, nil)
+				if err != nil {
+					return nil, false, err
+				}
+				for _, r := range thread {
+					if r.Timestamp != msg.Timestamp {
+						entry.replies = append(entry.replies, r)
+					}
+				}
+			}
+			entries = append(entries, entry)
+			total += 1 + len(entry.replies)
+			progress.Report(rangePercent(oldest, latest, msg.Timestamp), 100, fmt.Sprintf("exported %d messages", total))
+			if total >= maxTranscriptMessages {
+				// Keep the newest part of the range; history comes newest first.
+				slices.Reverse(entries)
+				return entries, true, nil
+			}
+		}
+		if !history.HasMore || history.ResponseMetaData.NextCursor == "" {
+			break
+		}
+		params.Cursor = history.ResponseMetaData.NextCursor
+	}
+	progress.Report(100, 100, fmt.Sprintf("exported %d messages", total))
+	slices.Reverse(entries)
+	return entries, false, nil
+}
+
+// transcriptPath returns where output=file writes, inside
+// SLACK_MCP_TRANSCRIPT_DIR. A name without the format's extension gets it.
+func (ch *ConversationsHandler) transcriptPath(name, format string) (string, error) {
+	dir := ch.apiProvider.Config().TranscriptDir
+	if dir == "" {
+		return "", errors.New("output=file needs SLACK_MCP_TRANSCRIPT_DIR to be set; use output=inline instead")
+	}
+	ext := ".md"
+	if format == transcriptFormatHTML {
+		ext = ".html"
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "transcript-" + time.Now().UTC().Format("20060102-150405")
+	}
+	if !transcriptFileNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid filename %q: use letters, digits, '.', '_' and '-' only, without directories", name)
+	}
+	if filepath.Ext(name) != ext {
+		name += ext
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func (t transcript) author(msg slack.Message) string {
+	return digestAuthor(msg, t.users)
+}
+
+func (t transcript) timestamp(msg slack.Message) (time.Time, string) {
+	at, err := text.SlackTimestampToTime(msg.Timestamp)
+	if err != nil {
+		return time.Time{}, msg.Timestamp
+	}
+	at = at.In(t.loc)
+	return at, at.Format("2006-01-02 15:04:05")
+}
+
+func (t transcript) period() string {
+	return fmt.Sprintf("%s to %s (%s)",
+		t.from.In(t.loc).Format("2006-01-02 15:04"), t.to.In(t.loc).Format("2006-01-02 15:04"), t.loc.String())
+}
+
+// markdown renders the transcript as Markdown, thread replies as quotes
+// under their parent.
+func (t transcript) markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n%s\n", t.title, t.period())
+	if len(t.entries) == 0 {
+		sb.WriteString("\nNo messages.\n")
+	}
+
+	write := func(msg slack.Message, prefix string) {
+		_, at := t.timestamp(msg)
+		fmt.Fprintf(&sb, "%s\n%s**%s** · %s\n", strings.TrimSpace(prefix), prefix, t.author(msg), at)
+		body := text.MrkdwnToMarkdown(msg.Text, t.resolver)
+		for _, line := range strings.Split(body, "\n") {
+			sb.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+		}
+		for _, f := range msg.Files {
+			fmt.Fprintf(&sb, "%sAttachment: [%s](%s)\n", prefix, fileLabel(f), f.Permalink)
+		}
+	}
+	for _, e := range t.entries {
+		write(e.msg, "")
+		for _, r := range e.replies {
+			write(r, "> ")
+		}
+	}
+	return sb.String()
+}
+
+// html renders the transcript as a standalone HTML page.
+func (t transcript) html() string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&sb, "<title>%s</title>\n", html.EscapeString(t.title))
+	sb.WriteString("<style>body{font-family:sans-serif;max-width:60em;margin:2em auto}" +
+		".message{margin:1em 0}.meta{color:#555}.author{font-weight:bold;color:#000}" +
+		".text{white-space:pre-wrap}.thread{margin-left:2em;padding-left:1em;border-left:3px solid #ddd}</style>\n")
+	sb.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&sb, "<h1>%s</h1>\n<p>%s</p>\n", html.EscapeString(t.title), html.EscapeString(t.period()))
+	if len(t.entries) == 0 {
+		sb.WriteString("<p>No messages.</p>\n")
+	}
+
+	write := func(msg slack.Message) {
+		at, label := t.timestamp(msg)
+		sb.WriteString("<div class=\"message\">\n")
+		fmt.Fprintf(&sb, "<div class=\"meta\"><span class=\"author\">%s</span> <time datetime=\"%s\">%s</time></div>\n",
+			html.EscapeString(t.author(msg)), at.Format(time.RFC3339), label)
+		body := html.EscapeString(text.MrkdwnToMarkdown(msg.Text, t.resolver))
+		body = markdownLinkRe.ReplaceAllString(body, `<a href="$2">$1</a>`)
+		fmt.Fprintf(&sb, "<div class=\"text\">%s</div>\n", body)
+		for _, f := range msg.Files {
+			fmt.Fprintf(&sb, "<div class=\"file\">Attachment: <a href=\"%s\">%s</a></div>\n",
+				html.EscapeString(f.Permalink), html.EscapeString(fileLabel(f)))
+		}
+		sb.WriteString("</div>\n")
+	}
+	for _, e := range t.entries {
+		write(e.msg)
+		if len(e.replies) > 0 {
+			sb.WriteString("<div class=\"thread\">\n")
+			for _, r := range e.replies {
+				write(r)
+			}
+			sb.WriteString("</div>\n")
+		}
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+func fileLabel(f slack.File) string {
+	if f.Title != "" {
+		return f.Title
+	}
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.ID
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// maxUnfurlDescription keeps long article previews from dominating a row.
+const maxUnfurlDescription = 300
+
+// unfurl is the link preview Slack attached to a message for a URL in its text.
+type unfurl struct {
+	url         string
+	title       string
+	description string
+	service     string
+}
+
+// extractUnfurls returns the link previews among attachments. Slack marks them
+// with original_url (from_url on older messages); other attachments are bot
+// content and are left to the message text.
+func extractUnfurls(attachments []slack.Attachment) []unfurl {
+	var unfurls []unfurl
+	for _, att := range attachments {
+		u := att.OriginalURL
+		if u == "" {
+			u = att.FromURL
+		}
+		if u == "" {
+			continue
+		}
+		unfurls = append(unfurls, unfurl{
+			url:         u,
+			title:       unfurlField(att.Title, 0),
+			description: unfurlField(att.Text, maxUnfurlDescription),
+			service:     unfurlField(att.ServiceName, 0),
+		})
+	}
+	return unfurls
+}
+
+// setUnfurls fills the unfurl columns of m. Several links are joined with
+// "|" in the same order in every column.
+func setUnfurls(m *Message, attachments []slack.Attachment) {
+	unfurls := extractUnfurls(attachments)
+	if len(unfurls) == 0 {
+		return
+	}
+	urls := make([]string, len(unfurls))
+	titles := make([]string, len(unfurls))
+	descriptions := make([]string, len(unfurls))
+	services := make([]string, len(unfurls))
+	for i, u := range unfurls {
+		urls[i], titles[i], descriptions[i], services[i] = u.url, u.title, u.description, u.service
+	}
+	m.UnfurlURLs = strings.Join(urls, "|")
+	m.UnfurlTitles = strings.Join(titles, "|")
+	m.UnfurlDescriptions = strings.Join(descriptions, "|")
+	m.UnfurlServices = strings.Join(services, "|")
+}
+
+// unfurlField flattens s to one line without the "|" separator and, when
+// limit is positive, truncates it to limit runes.
+func unfurlField(s string, limit int) string {
+	s = strings.ReplaceAll(s, "|", "/")
+	s = strings.Join(strings.Fields(s), " ")
+	if limit > 0 {
+		if r := []rune(s); len(r) > limit {
+			s = strings.TrimSpace(string(r[:limit-1])) + "…"
+		}
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// maxUsersSearchPresenceLookups bounds the users.getPresence calls of one
+// users_search; the method takes one user per call.
+const maxUsersSearchPresenceLookups = 30
+
+// outOfOfficeEmojis are status emojis that mean the user is away for the
+// day or longer, including Slack's default "Out sick" and "Vacationing".
+var outOfOfficeEmojis = []string{
+	":palm_tree:", ":desert_island:", ":beach_with_umbrella:", ":face_with_thermometer:",
+	":thermometer:", ":face_with_head_bandage:", ":sick:", ":mask:", ":ooo:", ":out_of_office:",
+}
+
+// outOfOfficeText matches status texts like "OOO until Monday", "On
+// vacation" or "Parental leave".
+var outOfOfficeText = regexp.MustCompile(`(?i)\b(ooo|out of (the )?office|pto|vacation(ing)?|holidays?|on leave|parental leave|annual leave|sick|off today|off work)\b`)
+
+// availabilityFilter holds the users_search filters on whether a user can
+// be reached now. A nil pointer or empty presence does not filter.
+type availabilityFilter struct {
+	presence    string
+	dnd         *bool
+	outOfOffice *bool
+}
+
+func (f availabilityFilter) active() bool {
+	return f.presence != "" || f.dnd != nil || f.outOfOffice != nil
+}
+
+func parseAvailabilityFilter(args map[string]any) (availabilityFilter, error) {
+	var f availabilityFilter
+	if p, _ := args["presence"].(string); p != "" {
+		if p != "active" && p != "away" {
+			return f, errors.New("presence must be 'active' or 'away'")
+		}
+		f.presence = p
+	}
+	if b, ok := args["dnd"].(bool); ok {
+		f.dnd = &b
+	}
+	if b, ok := args["out_of_office"].(bool); ok {
+		f.outOfOffice = &b
+	}
+	return f, nil
+}
+
+// match reports whether a user with the given availability passes f. A
+// user whose presence is unknown does not pass a presence filter.
+func (f availabilityFilter) match(presence string, inDND, outOfOffice bool) bool {
+	return (f.presence == "" || presence == f.presence) &&
+		(f.dnd == nil || inDND == *f.dnd) &&
+		(f.outOfOffice == nil || outOfOffice == *f.outOfOffice)
+}
+
+// inDND reports whether the user's Do Not Disturb schedule covers now.
+// dnd.teamInfo only reports schedules, so a manual snooze is not seen.
+func inDND(st slack.DNDStatus, now time.Time) bool {
+	if !st.Enabled || st.NextStartTimestamp == 0 || st.NextEndTimestamp == 0 {
+		return false
+	}
+	t := now.Unix()
+	return int64(st.NextStartTimestamp) <= t && t < int64(st.NextEndTimestamp)
+}
+
+// isOutOfOffice guesses from the custom status whether the user is away
+// for the day or longer: a sick or vacation emoji, or text such as "OOO".
+// An expired status does not count.
+func isOutOfOffice(p slack.UserProfile, now time.Time) bool {
+	if p.StatusExpiration != 0 && int64(p.StatusExpiration) <= now.Unix() {
+		return false
+	}
+	return slices.Contains(outOfOfficeEmojis, p.StatusEmoji) || outOfOfficeText.MatchString(p.StatusText)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitIsOutOfOffice(t *testing.T) {
+	now := time.Unix(1718000000, 0)
+	tests := []struct {
+		name    string
+		profile slack.UserProfile
+		want    bool
+	}{
+		{"vacation emoji", slack.UserProfile{StatusEmoji: ":palm_tree:", StatusText: "Vacationing"}, true},
+		{"sick emoji without text", slack.UserProfile{StatusEmoji: ":face_with_thermometer:"}, true},
+		{"OOO text", slack.UserProfile{StatusEmoji: ":calendar:", StatusText: "OOO until Monday"}, true},
+		{"parental leave", slack.UserProfile{StatusText: "On parental leave"}, true},
+		{"working status", slack.UserProfile{StatusEmoji: ":bus:", StatusText: "Commuting"}, false},
+		{"word inside another", slack.UserProfile{StatusText: "Shipping the holidaysale banner"}, false},
+		{"expired status", slack.UserProfile{StatusEmoji: ":palm_tree:", StatusExpiration: int(now.Unix()) - 60}, false},
+		{"status expiring later", slack.UserProfile{StatusText: "PTO", StatusExpiration: int(now.Unix()) + 3600}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isOutOfOffice(tt.profile, now))
+		})
+	}
+}
+
+func TestUnitInDND(t *testing.T) {
+	now := time.Unix(1718000000, 0)
+	start, end := int(now.Unix())-3600, int(now.Unix())+3600
+	assert.True(t, inDND(slack.DNDStatus{Enabled: true, NextStartTimestamp: start, NextEndTimestamp: end}, now))
+	assert.False(t, inDND(slack.DNDStatus{Enabled: false, NextStartTimestamp: start, NextEndTimestamp: end}, now))
+	assert.False(t, inDND(slack.DNDStatus{Enabled: true, NextStartTimestamp: end, NextEndTimestamp: end + 3600}, now))
+	assert.False(t, inDND(slack.DNDStatus{}, now))
+}
+
+func TestUnitAvailabilityFilter(t *testing.T) {
+	f, err := parseAvailabilityFilter(map[string]any{"presence": "active", "dnd": false, "out_of_office": false})
+	require.NoError(t, err)
+	assert.True(t, f.active())
+	assert.True(t, f.match("active", false, false))
+	assert.False(t, f.match("away", false, false))
+	assert.False(t, f.match("", false, false), "unknown presence does not pass")
+	assert.False(t, f.match("active", true, false))
+	assert.False(t, f.match("active", false, true))
+
+	f, err = parseAvailabilityFilter(map[string]any{"out_of_office": true})
+	require.NoError(t, err)
+	assert.True(t, f.match("", true, true))
+	assert.False(t, f.match("active", false, false))
+
+	f, err = parseAvailabilityFilter(map[string]any{})
+	require.NoError(t, err)
+	assert.False(t, f.active())
+	assert.True(t, f.match("", true, true))
+
+	_, err = parseAvailabilityFilter(map[string]any{"presence": "busy"})
+	assert.ErrorContains(t, err, "presence")
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// userLocation returns the user's timezone, falling back to a fixed zone
+// built from tz_offset when the IANA name is unknown here.
+func userLocation(u slack.User) *time.Location {
+	if u.TZ != "" {
+		if loc, err := time.LoadLocation(u.TZ); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(u.TZLabel, u.TZOffset)
+}
+
+// userTZOffset formats the user's current UTC offset as "+02:00", or "" when
+// Slack has no timezone for them (e.g. bots).
+func userTZOffset(u slack.User) string {
+	if u.TZ == "" && u.TZOffset == 0 {
+		return ""
+	}
+	_, offset := time.Now().In(userLocation(u)).Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// workingHoursHint derives when a user is reachable from their Do Not
+// Disturb schedule: from the end of one DND period to the start of the next,
+// in the user's timezone, e.g. "08:00-22:00". It is a hint only, and empty
+// when DND is off or the schedule is unknown.
+func workingHoursHint(st slack.DNDStatus, u slack.User) string {
+	if !st.Enabled || st.NextStartTimestamp == 0 || st.NextEndTimestamp == 0 || st.NextStartTimestamp == st.NextEndTimestamp {
+		return ""
+	}
+	loc := userLocation(u)
+	start := time.Unix(int64(st.NextStartTimestamp), 0).In(loc)
+	end := time.Unix(int64(st.NextEndTimestamp), 0).In(loc)
+	if start.Format("15:04") == end.Format("15:04") {
+		return ""
+	}
+	return end.Format("15:04") + "-" + start.Format("15:04")
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/gocarina/gocsv"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/slack-go/slack"
 	"go.uber.org/zap"
@@ -23,6 +25,7 @@ type UserGroup struct {
 	IsExternal  bool   `csv:"is_external" json:"is_external"`
 	DateCreate  string `csv:"date_create" json:"date_create,omitempty"`
 	DateUpdate  string `csv:"date_update" json:"date_update,omitempty"`
+	DateDelete  string `csv:"-" json:"date_delete,omitempty"`
 	Users       string `csv:"-" json:"users,omitempty"`
 }
 
@@ -80,8 +83,8 @@ func (h *UsergroupsHandler) UsergroupsListHandler(ctx context.Context, request m
 			Description: g.Description,
 			UserCount:   g.UserCount,
 			IsExternal:  g.IsExternal,
-			DateCreate:  formatJSONTime(g.DateCreate),
-			DateUpdate:  formatJSONTime(g.DateUpdate),
+			DateCreate:  h.formatJSONTime(g.DateCreate),
+			DateUpdate:  h.formatJSONTime(g.DateUpdate),
 		}
 		userGroupList = append(userGroupList, ug)
 	}
@@ -146,8 +149,8 @@ func (h *UsergroupsHandler) UsergroupsCreateHandler(ctx context.Context, request
 		Description: created.Description,
 		UserCount:   created.UserCount,
 		IsExternal:  created.IsExternal,
-		DateCreate:  formatJSONTime(created.DateCreate),
-		DateUpdate:  formatJSONTime(created.DateUpdate),
+		DateCreate:  h.formatJSONTime(created.DateCreate),
+		DateUpdate:  h.formatJSONTime(created.DateUpdate),
 	}
 
 	jsonBytes, err := json.Marshal(result)
@@ -221,8 +224,8 @@ func (h *UsergroupsHandler) UsergroupsUpdateHandler(ctx context.Context, request
 		Description: updated.Description,
 		UserCount:   updated.UserCount,
 		IsExternal:  updated.IsExternal,
-		DateCreate:  formatJSONTime(updated.DateCreate),
-		DateUpdate:  formatJSONTime(updated.DateUpdate),
+		DateCreate:  h.formatJSONTime(updated.DateCreate),
+		DateUpdate:  h.formatJSONTime(updated.DateUpdate),
 	}
 
 	jsonBytes, err := json.Marshal(result)
@@ -278,8 +281,8 @@ func (h *UsergroupsHandler) UsergroupsUsersUpdateHandler(ctx context.Context, re
 		Description: updated.Description,
 		UserCount:   updated.UserCount,
 		IsExternal:  updated.IsExternal,
-		DateCreate:  formatJSONTime(updated.DateCreate),
-		DateUpdate:  formatJSONTime(updated.DateUpdate),
+		DateCreate:  h.formatJSONTime(updated.DateCreate),
+		DateUpdate:  h.formatJSONTime(updated.DateUpdate),
 		Users:       strings.Join(updated.Users, ","),
 	}
 
@@ -292,6 +295,210 @@ func (h *UsergroupsHandler) UsergroupsUsersUpdateHandler(ctx context.Context, re
 	return mcp.NewToolResultText(string(jsonBytes)), nil
 }
 
+// UserGroupMembersModified is the JSON result of usergroups_users_modify:
+// the group after the update and what the call changed.
+type UserGroupMembersModified struct {
+	UserGroup
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	// AlreadyMembers and NotMembers are the add_users and remove_users that
+	// needed no change.
+	AlreadyMembers []string `json:"already_members,omitempty"`
+	NotMembers     []string `json:"not_members,omitempty"`
+}
+
+// memberChange is the outcome of applying add and remove lists to the
+// current members of a group.
+type memberChange struct {
+	members        []string
+	added          []string
+	removed        []string
+	alreadyMembers []string
+	notMembers     []string
+}
+
+// UsergroupsUsersModifyHandler adds and removes members of a user group
+// without replacing the whole list: it reads the current members, applies
+// the changes and writes the result back, so members the caller did not
+// mention are kept. Nothing is written when no member changes.
+func (h *UsergroupsHandler) UsergroupsUsersModifyHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("UsergroupsUsersModifyHandler called", zap.Any("params", request.Params))
+
+	if ready, err := h.apiProvider.IsReady(); !ready {
+		h.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	usergroupID := request.GetString("usergroup_id", "")
+	if usergroupID == "" {
+		return nil, errors.New("usergroup_id is required")
+	}
+	add := parseCommaSeparatedList(request.GetString("add_users", ""))
+	remove := parseCommaSeparatedList(request.GetString("remove_users", ""))
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, errors.New("add_users or remove_users is required")
+	}
+	for _, u := range add {
+		if slices.Contains(remove, u) {
+			return nil, fmt.Errorf("user %s is in both add_users and remove_users", u)
+		}
+	}
+
+	current, err := h.apiProvider.Slack().GetUserGroupMembersContext(ctx, usergroupID)
+	if err != nil {
+		h.logger.Error("GetUserGroupMembersContext failed", zap.Error(err))
+		return nil, err
+	}
+	change := modifyMembers(current, add, remove)
+	if len(change.members) == 0 {
+		return nil, errors.New("a user group must keep at least one member; to retire the group use usergroups_disable")
+	}
+
+	h.logger.Debug("Request parameters",
+		zap.String("usergroup_id", usergroupID),
+		zap.Strings("added", change.added),
+		zap.Strings("removed", change.removed),
+	)
+
+	result := UserGroupMembersModified{
+		UserGroup:      UserGroup{ID: usergroupID, UserCount: len(change.members), Users: strings.Join(change.members, ",")},
+		Added:          change.added,
+		Removed:        change.removed,
+		AlreadyMembers: change.alreadyMembers,
+		NotMembers:     change.notMembers,
+	}
+	if len(change.added) > 0 || len(change.removed) > 0 {
+		updated, err := h.apiProvider.Slack().UpdateUserGroupMembersContext(ctx, usergroupID, strings.Join(change.members, ","))
+		if err != nil {
+			h.logger.Error("UpdateUserGroupMembersContext failed", zap.Error(err))
+			return nil, err
+		}
+
+		h.logger.Debug("Updated user group members",
+			zap.String("id", updated.ID),
+			zap.String("name", updated.Name),
+			zap.Int("user_count", updated.UserCount),
+		)
+
+		result.UserGroup = UserGroup{
+			ID:          updated.ID,
+			Name:        updated.Name,
+			Handle:      updated.Handle,
+			Description: updated.Description,
+			UserCount:   updated.UserCount,
+			IsExternal:  updated.IsExternal,
+			DateCreate:  h.formatJSONTime(updated.DateCreate),
+			DateUpdate:  h.formatJSONTime(updated.DateUpdate),
+			Users:       strings.Join(updated.Users, ","),
+		}
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		h.logger.Error("Failed to marshal user group change to JSON", zap.Error(err))
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// modifyMembers applies add and remove to current, keeping the order of
+// the current members and appending new ones in the order given.
+func modifyMembers(current, add, remove []string) memberChange {
+	change := memberChange{added: []string{}, removed: []string{}}
+	for _, u := range current {
+		if slices.Contains(remove, u) {
+			if !slices.Contains(change.removed, u) {
+				change.removed = append(change.removed, u)
+			}
+			continue
+		}
+		if !slices.Contains(change.members, u) {
+			change.members = append(change.members, u)
+		}
+	}
+	for _, u := range add {
+		switch {
+		case slices.Contains(current, u):
+			if !slices.Contains(change.alreadyMembers, u) {
+				change.alreadyMembers = append(change.alreadyMembers, u)
+			}
+		case !slices.Contains(change.added, u):
+			change.added = append(change.added, u)
+			change.members = append(change.members, u)
+		}
+	}
+	for _, u := range remove {
+		if !slices.Contains(current, u) && !slices.Contains(change.notMembers, u) {
+			change.notMembers = append(change.notMembers, u)
+		}
+	}
+	return change
+}
+
+// UsergroupsDisableHandler disables a user group or re-enables a disabled
+// one. Disabling requires confirm=true because the group's @handle stops
+// working for everyone in the workspace.
+func (h *UsergroupsHandler) UsergroupsDisableHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	h.logger.Debug("UsergroupsDisableHandler called", zap.Any("params", request.Params))
+
+	if ready, err := h.apiProvider.IsReady(); !ready {
+		h.logger.Error("API provider not ready", zap.Error(err))
+		return nil, err
+	}
+
+	usergroupID := request.GetString("usergroup_id", "")
+	if usergroupID == "" {
+		return nil, errors.New("usergroup_id is required")
+	}
+
+	var (
+		updated slack.UserGroup
+		err     error
+	)
+	switch action := request.GetString("action", "disable"); action {
+	case "disable":
+		if !request.GetBool("confirm", false) {
+			return nil, errors.New("disabling a user group requires confirm=true; its members are kept and it can be re-enabled with action=enable")
+		}
+		updated, err = h.apiProvider.Slack().DisableUserGroupContext(ctx, usergroupID)
+	case "enable":
+		updated, err = h.apiProvider.Slack().EnableUserGroupContext(ctx, usergroupID)
+	default:
+		return nil, fmt.Errorf("invalid action %q: must be \"disable\" or \"enable\"", action)
+	}
+	if err != nil {
+		h.logger.Error("Changing user group state failed", zap.String("usergroup_id", usergroupID), zap.Error(err))
+		return nil, err
+	}
+
+	h.logger.Debug("Changed user group state",
+		zap.String("id", updated.ID),
+		zap.String("name", updated.Name),
+		zap.Bool("disabled", updated.DateDelete != 0),
+	)
+
+	result := UserGroup{
+		ID:          updated.ID,
+		Name:        updated.Name,
+		Handle:      updated.Handle,
+		Description: updated.Description,
+		UserCount:   updated.UserCount,
+		IsExternal:  updated.IsExternal,
+		DateCreate:  h.formatJSONTime(updated.DateCreate),
+		DateUpdate:  h.formatJSONTime(updated.DateUpdate),
+		DateDelete:  h.formatJSONTime(updated.DateDelete),
+	}
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		h.logger.Error("Failed to marshal user group to JSON", zap.Error(err))
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
 // UsergroupsMeHandler allows the current user to list their groups, join or leave a user group
 func (h *UsergroupsHandler) UsergroupsMeHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	h.logger.Debug("UsergroupsMeHandler called", zap.Any("params", request.Params))
@@ -440,8 +647,8 @@ func (h *UsergroupsHandler) handleListMyGroups(ctx context.Context, currentUserI
 			Description: g.Description,
 			UserCount:   g.UserCount,
 			IsExternal:  g.IsExternal,
-			DateCreate:  formatJSONTime(g.DateCreate),
-			DateUpdate:  formatJSONTime(g.DateUpdate),
+			DateCreate:  h.formatJSONTime(g.DateCreate),
+			DateUpdate:  h.formatJSONTime(g.DateUpdate),
 		}
 		userGroupList = append(userGroupList, ug)
 	}
@@ -457,13 +664,11 @@ func (h *UsergroupsHandler) handleListMyGroups(ctx context.Context, currentUserI
 	return mcp.NewToolResultText(string(csvBytes)), nil
 }
 
-// formatJSONTime converts slack.JSONTime (Unix timestamp) to a readable string
-func formatJSONTime(jt slack.JSONTime) string {
-	if int64(jt) == 0 {
-		return ""
-	}
-	t := time.Unix(int64(jt), 0)
-	return t.Format(time.RFC3339)
+// formatJSONTime converts slack.JSONTime (Unix timestamp) to RFC3339 in the
+// configured timezone
+func (h *UsergroupsHandler) formatJSONTime(jt slack.JSONTime) string {
+	iso, _ := text.FormatUnix(int64(jt), h.apiProvider.Config().Location())
+	return iso
 }
 
 // parseCommaSeparatedList splits a comma-separated string into a slice of trimmed strings
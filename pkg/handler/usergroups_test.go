@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitModifyMembers(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		add     []string
+		remove  []string
+		want    memberChange
+	}{
+		{
+			name:    "add and remove keep the other members",
+			current: []string{"U01", "U02", "U03"},
+			add:     []string{"U04"},
+			remove:  []string{"U02"},
+			want: memberChange{
+				members: []string{"U01", "U03", "U04"},
+				added:   []string{"U04"},
+				removed: []string{"U02"},
+			},
+		},
+		{
+			name:    "existing members and non-members are reported, not changed",
+			current: []string{"U01", "U02"},
+			add:     []string{"U01", "U05", "U05"},
+			remove:  []string{"U09"},
+			want: memberChange{
+				members:        []string{"U01", "U02", "U05"},
+				added:          []string{"U05"},
+				removed:        []string{},
+				alreadyMembers: []string{"U01"},
+				notMembers:     []string{"U09"},
+			},
+		},
+		{
+			name:    "removing everyone leaves no members",
+			current: []string{"U01"},
+			remove:  []string{"U01"},
+			want: memberChange{
+				added:   []string{},
+				removed: []string{"U01"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, modifyMembers(tt.current, tt.add, tt.remove))
+		})
+	}
+}
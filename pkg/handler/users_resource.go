@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+
+	"github.com/slack-go/slack"
+)
+
+// UserExport is a row of the slack://<workspace>/users resource.
+type UserExport struct {
+	User
+	IsBot     bool
+	IsDeleted bool
+	// CustomFields is a JSON object of custom profile field label to value,
+	// filled with include_custom_fields.
+	CustomFields string
+}
+
+// usersResourceOptions are the query flags of the users resource, e.g.
+// slack://<workspace>/users?include_deleted=true&include_bots=true.
+type usersResourceOptions struct {
+	includeDeleted      bool
+	includeBots         bool
+	includeCustomFields bool
+}
+
+func parseUsersResourceOptions(uri string) usersResourceOptions {
+	var opts usersResourceOptions
+	u, err := url.Parse(uri)
+	if err != nil {
+		return opts
+	}
+	q := u.Query()
+	flag := func(name string) bool {
+		v := q.Get(name)
+		return v == "true" || v == "1"
+	}
+	opts.includeDeleted = flag("include_deleted")
+	opts.includeBots = flag("include_bots")
+	opts.includeCustomFields = flag("include_custom_fields")
+	return opts
+}
+
+// usersExport returns the rows of the users resource, sorted by handle.
+// Deactivated users and bots are left out unless opts include them.
+// labels maps custom profile field IDs to their labels; fields without a
+// label in it are skipped.
+func usersExport(users map[string]slack.User, dnd map[string]slack.DNDStatus, opts usersResourceOptions, labels map[string]string) []UserExport {
+	rows := make([]UserExport, 0, len(users))
+	for _, u := range users {
+		if u.Deleted && !opts.includeDeleted {
+			continue
+		}
+		if u.IsBot && !opts.includeBots {
+			continue
+		}
+		row := UserExport{
+			User: User{
+				UserID:       u.ID,
+				UserName:     u.Name,
+				RealName:     u.RealName,
+				TZ:           u.TZ,
+				TZOffset:     userTZOffset(u),
+				Locale:       u.Locale,
+				WorkingHours: workingHoursHint(dnd[u.ID], u),
+			},
+			IsBot:     u.IsBot,
+			IsDeleted: u.Deleted,
+		}
+		if opts.includeCustomFields {
+			row.CustomFields = customFieldsJSON(u.Profile.Fields.ToMap(), labels)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].UserName != rows[j].UserName {
+			return rows[i].UserName < rows[j].UserName
+		}
+		return rows[i].UserID < rows[j].UserID
+	})
+	return rows
+}
+
+// customFieldsJSON encodes the non-empty custom field values by label, or
+// returns "" when there are none.
+func customFieldsJSON(fields map[string]slack.UserProfileCustomField, labels map[string]string) string {
+	values := make(map[string]string, len(fields))
+	for id, f := range fields {
+		label, ok := labels[id]
+		if !ok || f.Value == "" {
+			continue
+		}
+		values[label] = f.Value
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(values)
+	return string(b)
+}
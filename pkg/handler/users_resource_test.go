@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitParseUsersResourceOptions(t *testing.T) {
+	assert.Equal(t, usersResourceOptions{}, parseUsersResourceOptions("slack://acme/users"))
+	assert.Equal(t, usersResourceOptions{includeDeleted: true, includeCustomFields: true},
+		parseUsersResourceOptions("slack://acme/users?include_custom_fields=1&include_deleted=true&include_bots=false"))
+}
+
+func TestUnitUsersExport(t *testing.T) {
+	var fields slack.UserProfile
+	fields.SetFieldsMap(map[string]slack.UserProfileCustomField{
+		"Xf01": {Value: "Finance"},
+		"Xf02": {Value: ""},
+		"Xf99": {Value: "not in the schema"},
+	})
+	users := map[string]slack.User{
+		"U1": {ID: "U1", Name: "alice", Profile: fields},
+		"U2": {ID: "U2", Name: "bob", Deleted: true},
+		"B1": {ID: "B1", Name: "deploybot", IsBot: true},
+	}
+	labels := map[string]string{"Xf01": "Department", "Xf02": "Cost center"}
+
+	rows := usersExport(users, nil, usersResourceOptions{}, labels)
+	require.Len(t, rows, 1, "deactivated users and bots are left out by default")
+	assert.Equal(t, "U1", rows[0].UserID)
+	assert.Empty(t, rows[0].CustomFields)
+
+	rows = usersExport(users, nil, usersResourceOptions{includeDeleted: true, includeBots: true, includeCustomFields: true}, labels)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"U1", "U2", "B1"}, []string{rows[0].UserID, rows[1].UserID, rows[2].UserID}, "sorted by handle")
+	assert.Equal(t, `{"Department":"Finance"}`, rows[0].CustomFields)
+	assert.True(t, rows[1].IsDeleted)
+	assert.True(t, rows[2].IsBot)
+
+	csv, err := gocsv.MarshalString(&rows)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(csv, "UserID,UserName,RealName,TZ,TZOffset,Locale,WorkingHours,IsBot,IsDeleted,CustomFields\n"), csv)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// maxUsersSearchLimit is the most results one users_search returns.
+const maxUsersSearchLimit = 100
+
+// maxUsersSearchDMOpens bounds the conversations.open calls of one
+// users_search with include_dm_channel; the method is rate limited to about
+// 50 calls a minute.
+const maxUsersSearchDMOpens = 20
+
+// usersSearchDMOpener opens missing DMs for users_search results. A nil
+// opener opens nothing.
+type usersSearchDMOpener struct {
+	ch     *ConversationsHandler
+	self   string
+	policy *dmPolicy
+	opened int
+}
+
+func (ch *ConversationsHandler) newUsersSearchDMOpener(ctx context.Context) *usersSearchDMOpener {
+	o := &usersSearchDMOpener{ch: ch, policy: newDMPolicy(ch.apiProvider.Config().DMAllowlist)}
+	if ar, err := ch.apiProvider.Slack().AuthTestContext(ctx); err == nil {
+		o.self = ar.UserID
+	}
+	return o
+}
+
+// open returns the ID of the DM with user, opening it unless the user is a
+// bot, deactivated, the caller, or outside SLACK_MCP_DM_ALLOWLIST. Failures
+// only leave the ID empty.
+func (o *usersSearchDMOpener) open(ctx context.Context, user slack.User) string {
+	if o == nil || user.IsBot || user.Deleted || user.ID == o.self || o.opened >= maxUsersSearchDMOpens {
+		return ""
+	}
+	if o.policy != nil && o.policy.check(user.ID, []string{user.ID}, o.self, o.ch.apiProvider.ProvideUsersMap().UsersInv) != nil {
+		return ""
+	}
+
+	o.opened++
+	channel, _, _, err := o.ch.apiProvider.Slack().OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users:    []string{user.ID},
+		ReturnIM: true,
+	})
+	if err != nil || channel == nil || channel.ID == "" {
+		o.ch.logger.Warn("Failed to open DM for users_search", zap.String("user", user.ID), zap.Error(err))
+		return ""
+	}
+	channel.IsIM = true
+	if channel.User == "" {
+		channel.User = user.ID
+	}
+	o.ch.apiProvider.RememberChannel(*channel)
+	return channel.ID
+}
+
+// userAvatarURL picks a mid-sized profile photo, falling back to smaller ones.
+func userAvatarURL(p slack.UserProfile) string {
+	for _, url := range []string{p.Image192, p.Image512, p.Image72, p.Image48, p.Image32, p.Image24} {
+		if url != "" {
+			return url
+		}
+	}
+	return ""
+}
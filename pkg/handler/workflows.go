@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+const workflowTriggerTimeout = 30 * time.Second
+
+// workflowHTTPClient posts to webhook triggers. The trigger URL carries its
+// own secret, so no Slack token is sent.
+var workflowHTTPClient = &http.Client{Timeout: workflowTriggerTimeout}
+
+// WorkflowTriggered is the JSON result of workflows_trigger.
+type WorkflowTriggered struct {
+	Workflow string   `json:"workflow"`
+	Inputs   []string `json:"inputs,omitempty"`
+	Started  bool     `json:"started"`
+}
+
+// WorkflowsTriggerHandler starts a workflow configured under workflows in
+// the config file by posting inputs to its webhook trigger, so an agent can
+// hand off to an existing workflow, e.g. an access request, instead of
+// reimplementing it.
+func (ch *ConversationsHandler) WorkflowsTriggerHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("WorkflowsTriggerHandler called", zap.Any("params", request.Params))
+
+	name := strings.TrimSpace(request.GetString("workflow", ""))
+	if name == "" {
+		return nil, errors.New("workflow is required")
+	}
+	workflows := ch.apiProvider.Config().Workflows
+	i := slices.IndexFunc(workflows, func(w config.WorkflowConfig) bool { return w.Name == name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown workflow %q, configured workflows: %s", name, strings.Join(WorkflowNames(workflows), ", "))
+	}
+	workflow := workflows[i]
+
+	payload, err := workflowPayload(workflow, request.GetArguments()["inputs"])
+	if err != nil {
+		return nil, err
+	}
+	err = postWorkflowTrigger(ctx, workflow.TriggerURL, payload)
+	fields := []zap.Field{
+		zap.String("context", "console"),
+		zap.String("workflow", workflow.Name),
+		zap.Strings("inputs", sortedKeys(payload)),
+	}
+	if err != nil {
+		ch.logger.Warn("Workflow trigger failed", append(fields, zap.Error(err))...)
+		return nil, fmt.Errorf("workflow %q: %w", workflow.Name, err)
+	}
+	ch.logger.Info("Workflow triggered", fields...)
+
+	jsonBytes, err := json.Marshal(WorkflowTriggered{Workflow: workflow.Name, Inputs: sortedKeys(payload), Started: true})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// WorkflowNames returns the names of workflows, for descriptions and errors.
+func WorkflowNames(workflows []config.WorkflowConfig) []string {
+	names := make([]string, 0, len(workflows))
+	for _, w := range workflows {
+		names = append(names, w.Name)
+	}
+	return names
+}
+
+// workflowPayload checks the inputs argument, a JSON object or a string
+// holding one, against workflow.Inputs and returns the variables to post.
+// Webhook triggers take text variables, so numbers and booleans are sent as
+// their text.
+func workflowPayload(workflow config.WorkflowConfig, raw any) (map[string]string, error) {
+	if s, ok := raw.(string); ok {
+		if strings.TrimSpace(s) == "" {
+			raw = nil
+		} else if err := json.Unmarshal([]byte(s), &raw); err != nil {
+			return nil, fmt.Errorf("inputs must be a JSON object: %w", err)
+		}
+	}
+	var inputs map[string]any
+	switch v := raw.(type) {
+	case nil:
+	case map[string]any:
+		inputs = v
+	default:
+		return nil, errors.New("inputs must be a JSON object of variable names to values")
+	}
+
+	payload := make(map[string]string, len(inputs))
+	for k, v := range inputs {
+		if len(workflow.Inputs) > 0 && !slices.Contains(workflow.Inputs, k) {
+			return nil, fmt.Errorf("workflow %q has no input %q, its inputs are: %s", workflow.Name, k, strings.Join(workflow.Inputs, ", "))
+		}
+		switch v := v.(type) {
+		case string:
+			payload[k] = v
+		case float64:
+			payload[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			payload[k] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("input %q must be a string, number or boolean", k)
+		}
+	}
+	for _, k := range workflow.Inputs {
+		if _, ok := payload[k]; !ok {
+			return nil, fmt.Errorf("workflow %q needs input %q, its inputs are: %s", workflow.Name, k, strings.Join(workflow.Inputs, ", "))
+		}
+	}
+	return payload, nil
+}
+
+// postWorkflowTrigger posts payload to a webhook trigger and checks Slack's
+// answer.
+func postWorkflowTrigger(ctx context.Context, triggerURL string, payload map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, triggerURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := workflowHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("trigger returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	var result struct {
+		OK    *bool  `json:"ok"`
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(respBody, &result) == nil && result.OK != nil && !*result.OK {
+		return fmt.Errorf("trigger failed: %s", result.Error)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitWorkflowPayload(t *testing.T) {
+	wf := config.WorkflowConfig{Name: "access_request", Inputs: []string{"system", "reason", "days"}}
+
+	payload, err := workflowPayload(wf, map[string]any{"system": "grafana", "reason": "on-call", "days": float64(7)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"system": "grafana", "reason": "on-call", "days": "7"}, payload)
+
+	payload, err = workflowPayload(wf, `{"system":"vault","reason":"audit","days":1.5}`)
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", payload["days"], "inputs may arrive as a JSON string")
+
+	_, err = workflowPayload(wf, map[string]any{"system": "grafana", "reason": "x", "days": "1", "team": "sre"})
+	assert.ErrorContains(t, err, `has no input "team"`)
+
+	_, err = workflowPayload(wf, map[string]any{"system": "grafana"})
+	assert.ErrorContains(t, err, `needs input "reason"`)
+
+	_, err = workflowPayload(wf, []any{"grafana"})
+	assert.ErrorContains(t, err, "inputs must be a JSON object")
+
+	_, err = workflowPayload(wf, map[string]any{"system": map[string]any{"name": "grafana"}})
+	assert.ErrorContains(t, err, "must be a string, number or boolean")
+
+	free := config.WorkflowConfig{Name: "ping"}
+	payload, err = workflowPayload(free, map[string]any{"urgent": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"urgent": "true"}, payload, "any inputs are passed when none are configured")
+
+	payload, err = workflowPayload(free, nil)
+	require.NoError(t, err)
+	assert.Empty(t, payload)
+}
+
+func TestUnitPostWorkflowTrigger(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/rejected":
+			_, _ = w.Write([]byte(`{"ok":false,"error":"invalid_workflow_input"}`))
+		default:
+			http.Error(w, "no_such_trigger", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	require.NoError(t, postWorkflowTrigger(ctx, srv.URL+"/ok", map[string]string{"system": "grafana"}))
+	assert.Equal(t, map[string]string{"system": "grafana"}, got)
+
+	assert.ErrorContains(t, postWorkflowTrigger(ctx, srv.URL+"/rejected", nil), "invalid_workflow_input")
+	assert.ErrorContains(t, postWorkflowTrigger(ctx, srv.URL+"/gone", nil), "404")
+}
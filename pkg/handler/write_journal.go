@@ -0,0 +1,372 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.uber.org/zap"
+)
+
+// Write journal record events.
+const (
+	journalStarted      = "started"
+	journalStep         = "step"
+	journalDone         = "done"
+	journalFailed       = "failed"
+	journalAcknowledged = "acknowledged"
+)
+
+// journalRecord is one line of the write journal.
+type journalRecord struct {
+	ID    string         `json:"id"`
+	Event string         `json:"event"`
+	Time  time.Time      `json:"time"`
+	Tool  string         `json:"tool,omitempty"`
+	Args  map[string]any `json:"args,omitempty"`
+	Step  *WriteStep     `json:"step,omitempty"`
+	Error string         `json:"error,omitempty"`
+	// Caller is the hash of the per-client Slack token the call ran with,
+	// empty for the server's own token.
+	Caller string `json:"caller,omitempty"`
+}
+
+// WriteStep is one item of a batch write that Slack accepted.
+type WriteStep struct {
+	Index   int    `json:"index"`
+	Channel string `json:"channel,omitempty"`
+	Ts      string `json:"ts,omitempty"`
+}
+
+// PendingWrite is a write tool call that started before the server last
+// stopped and never finished, so Slack may or may not have applied it.
+type PendingWrite struct {
+	ID        string         `json:"id"`
+	Tool      string         `json:"tool"`
+	StartedAt time.Time      `json:"started_at"`
+	Args      map[string]any `json:"args"`
+	// Completed lists the batch items Slack accepted before the stop;
+	// the other items were not sent or their outcome is unknown.
+	Completed []WriteStep `json:"completed,omitempty"`
+	// Caller is the identity the call ran as; only that caller sees it.
+	Caller string `json:"-"`
+}
+
+// WriteJournal appends every write tool call to a JSONL file before it runs
+// and again when it finishes, syncing each line to disk, so that after a
+// crash or a killed container the calls that were cut short are known
+// instead of guessed. Those are loaded on startup as pending writes until
+// acknowledged.
+type WriteJournal struct {
+	path   string
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	file    *os.File
+	pending map[string]*PendingWrite
+}
+
+// NewWriteJournal returns nil when path, SLACK_MCP_WRITE_JOURNAL, is empty.
+// The journal is compacted to the calls still pending from earlier runs.
+func NewWriteJournal(path string, logger *zap.Logger) (*WriteJournal, error) {
+	if path == "" {
+		return nil, nil
+	}
+	pending, err := loadWriteJournal(path, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := compactWriteJournal(path, pending); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []zap.Field{zap.String("context", "console"), zap.Int("pending", len(pending))}
+	if len(pending) > 0 {
+		logger.Warn("Write tool calls were interrupted by the last shutdown, see pending_writes", fields...)
+	} else {
+		logger.Info("Write journal enabled", fields...)
+	}
+	return &WriteJournal{path: path, logger: logger, file: f, pending: pending}, nil
+}
+
+// loadWriteJournal replays the journal at path and returns the calls that
+// started but neither finished nor were acknowledged. A torn last line, left
+// by a crash mid-write, is skipped.
+func loadWriteJournal(path string, logger *zap.Logger) (map[string]*PendingWrite, error) {
+	pending := make(map[string]*PendingWrite)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return pending, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var r journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			logger.Warn("Skipping unreadable write journal line", zap.Int("line", line), zap.Error(err))
+			continue
+		}
+		switch r.Event {
+		case journalStarted:
+			pending[r.ID] = &PendingWrite{ID: r.ID, Tool: r.Tool, StartedAt: r.Time, Args: r.Args, Caller: r.Caller}
+		case journalStep:
+			if p, ok := pending[r.ID]; ok && r.Step != nil {
+				p.Completed = append(p.Completed, *r.Step)
+			}
+		case journalDone, journalFailed, journalAcknowledged:
+			delete(pending, r.ID)
+		}
+	}
+	return pending, scanner.Err()
+}
+
+// compactWriteJournal rewrites the journal with only the pending calls, so
+// it does not grow with every write ever made.
+func compactWriteJournal(path string, pending map[string]*PendingWrite) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".write-journal-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	enc := json.NewEncoder(tmp)
+	for _, p := range sortedPendingWrites(pending) {
+		if err := enc.Encode(journalRecord{ID: p.ID, Event: journalStarted, Time: p.StartedAt, Tool: p.Tool, Caller: p.Caller, Args: p.Args}); err != nil {
+			tmp.Close()
+			return err
+		}
+		for i := range p.Completed {
+			if err := enc.Encode(journalRecord{ID: p.ID, Event: journalStep, Time: p.StartedAt, Step: &p.Completed[i]}); err != nil {
+				tmp.Close()
+				return err
+			}
+		}
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// append writes r and syncs it to disk.
+func (j *WriteJournal) append(r journalRecord) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// Begin records that a call of tool with args by caller, as for Pending, is
+// about to run and returns its journal ID. A call that cannot be journaled
+// is not run: it could not be accounted for after a crash.
+func (j *WriteJournal) Begin(caller, tool string, args map[string]any) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(buf)
+	if err := j.append(journalRecord{ID: id, Event: journalStarted, Time: time.Now().UTC(), Tool: tool, Caller: caller, Args: args}); err != nil {
+		j.logger.Error("Failed to write to the write journal", zap.String("tool", tool), zap.Error(err))
+		return "", fmt.Errorf("write journal unavailable, not running %s: %w", tool, err)
+	}
+	return id, nil
+}
+
+// Finish records the outcome of the call id.
+func (j *WriteJournal) Finish(id string, callErr error) {
+	r := journalRecord{ID: id, Event: journalDone, Time: time.Now().UTC()}
+	if callErr != nil {
+		r.Event, r.Error = journalFailed, callErr.Error()
+	}
+	if err := j.append(r); err != nil {
+		j.logger.Error("Failed to write to the write journal", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// step records that item step of the batch call id was accepted by Slack.
+func (j *WriteJournal) step(id string, step WriteStep) {
+	if err := j.append(journalRecord{ID: id, Event: journalStep, Time: time.Now().UTC(), Step: &step}); err != nil {
+		j.logger.Error("Failed to write to the write journal", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// Pending returns the calls of caller interrupted by an earlier shutdown,
+// oldest first. caller identifies the Slack token the calls ran with, empty
+// for the server's own, so that in multi-user mode no client sees, or
+// redoes, the writes of another identity.
+func (j *WriteJournal) Pending(caller string) []PendingWrite {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	list := sortedPendingWrites(j.pending)
+	return slices.DeleteFunc(list, func(p PendingWrite) bool { return p.Caller != caller })
+}
+
+// Acknowledge drops the pending calls ids of caller, or all of them for
+// "all", once they have been checked, and returns how many were dropped.
+func (j *WriteJournal) Acknowledge(caller string, ids []string) (int, error) {
+	j.mu.Lock()
+	if len(ids) == 1 && ids[0] == "all" {
+		ids = make([]string, 0, len(j.pending))
+		for id, p := range j.pending {
+			if p.Caller == caller {
+				ids = append(ids, id)
+			}
+		}
+	}
+	var known []string
+	for _, id := range ids {
+		if p, ok := j.pending[id]; !ok || p.Caller != caller {
+			j.mu.Unlock()
+			return 0, fmt.Errorf("no pending write %q", id)
+		}
+		known = append(known, id)
+	}
+	j.mu.Unlock()
+
+	for _, id := range known {
+		if err := j.append(journalRecord{ID: id, Event: journalAcknowledged, Time: time.Now().UTC()}); err != nil {
+			return 0, err
+		}
+		j.mu.Lock()
+		delete(j.pending, id)
+		j.mu.Unlock()
+	}
+	return len(known), nil
+}
+
+// Close closes the journal file.
+func (j *WriteJournal) Close() error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+func sortedPendingWrites(pending map[string]*PendingWrite) []PendingWrite {
+	list := make([]PendingWrite, 0, len(pending))
+	for _, p := range pending {
+		list = append(list, *p)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if !list[a].StartedAt.Equal(list[b].StartedAt) {
+			return list[a].StartedAt.Before(list[b].StartedAt)
+		}
+		return list[a].ID < list[b].ID
+	})
+	return list
+}
+
+type writeJournalKey struct{}
+
+type journalCall struct {
+	journal *WriteJournal
+	caller  string
+	id      string
+}
+
+// WithWriteJournal makes j available to the pending_writes handler, which
+// shows caller its own entries, and, with the journal ID of the running
+// call, empty for calls that are not journaled, to the batch write handlers.
+func WithWriteJournal(ctx context.Context, j *WriteJournal, caller, id string) context.Context {
+	return context.WithValue(ctx, writeJournalKey{}, journalCall{journal: j, caller: caller, id: id})
+}
+
+func writeJournalFromContext(ctx context.Context) journalCall {
+	c, _ := ctx.Value(writeJournalKey{}).(journalCall)
+	return c
+}
+
+// journalWriteStep records that an item of the running batch call was
+// accepted by Slack, if the call is journaled.
+func journalWriteStep(ctx context.Context, step WriteStep) {
+	if c := writeJournalFromContext(ctx); c.journal != nil && c.id != "" {
+		c.journal.step(c.id, step)
+	}
+}
+
+// PendingWritesResult is the JSON result of pending_writes.
+type PendingWritesResult struct {
+	Pending      []PendingWrite `json:"pending"`
+	Acknowledged int            `json:"acknowledged,omitempty"`
+}
+
+// PendingWritesHandler lists the write tool calls that were cut short by a
+// crash or restart, with their arguments and, for batches, the items that
+// went through, so a client can check Slack and redo only what is missing
+// instead of reposting everything. acknowledge drops checked entries.
+func (ch *ConversationsHandler) PendingWritesHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ch.logger.Debug("PendingWritesHandler called", zap.Any("params", request.Params))
+
+	call := writeJournalFromContext(ctx)
+	j := call.journal
+	if j == nil {
+		return nil, errors.New("the write journal is not enabled, set SLACK_MCP_WRITE_JOURNAL")
+	}
+
+	var result PendingWritesResult
+	if raw := strings.TrimSpace(request.GetString("acknowledge", "")); raw != "" {
+		var ids []string
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		n, err := j.Acknowledge(call.caller, ids)
+		if err != nil {
+			return nil, err
+		}
+		ch.logger.Info("Pending writes acknowledged",
+			zap.String("context", "console"),
+			zap.Strings("ids", ids),
+			zap.Int("acknowledged", n),
+		)
+		result.Acknowledged = n
+	}
+	result.Pending = j.Pending(call.caller)
+
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
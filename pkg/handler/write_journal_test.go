@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUnitWriteJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "write-journal.jsonl")
+
+	j, err := NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+	assert.Empty(t, j.Pending(""))
+
+	done, err := j.Begin("", "conversations_add_message", map[string]any{"channel_id": "C01", "text": "hi"})
+	require.NoError(t, err)
+	j.Finish(done, nil)
+
+	batch, err := j.Begin("", "conversations_add_messages", map[string]any{"messages": []any{"a", "b"}})
+	require.NoError(t, err)
+	journalWriteStep(WithWriteJournal(context.Background(), j, "", batch), WriteStep{Index: 0, Channel: "C01", Ts: "1718000000.000100"})
+
+	other, err := j.Begin("", "reactions_add", map[string]any{"channel_id": "C02"})
+	require.NoError(t, err)
+	require.NoError(t, j.Close())
+
+	// A crash while writing leaves a torn last line.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":"` + other + `","event":"do`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, err = NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+	pending := j.Pending("")
+	require.Len(t, pending, 2)
+	assert.Equal(t, batch, pending[0].ID)
+	assert.Equal(t, "conversations_add_messages", pending[0].Tool)
+	assert.Equal(t, []WriteStep{{Index: 0, Channel: "C01", Ts: "1718000000.000100"}}, pending[0].Completed)
+	assert.Equal(t, other, pending[1].ID)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), done, "finished calls are compacted away")
+	assert.Equal(t, 3, strings.Count(string(data), "\n"))
+
+	_, err = j.Acknowledge("", []string{"unknown"})
+	assert.ErrorContains(t, err, "no pending write")
+	n, err := j.Acknowledge("", []string{other})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.NoError(t, j.Close())
+
+	j, err = NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+	require.Len(t, j.Pending(""), 1)
+	n, err = j.Acknowledge("", []string{"all"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, j.Pending(""))
+	require.NoError(t, j.Close())
+}
+
+func TestUnitWriteJournalCallers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "write-journal.jsonl")
+	j, err := NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+	own, err := j.Begin("", "conversations_add_message", map[string]any{"channel_id": "C01"})
+	require.NoError(t, err)
+	svc, err := j.Begin("a1b2", "conversations_add_message", map[string]any{"channel_id": "C02"})
+	require.NoError(t, err)
+	require.NoError(t, j.Close())
+
+	j, err = NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+	require.Len(t, j.Pending(""), 1)
+	assert.Equal(t, own, j.Pending("")[0].ID)
+	require.Len(t, j.Pending("a1b2"), 1)
+	assert.Equal(t, svc, j.Pending("a1b2")[0].ID)
+	assert.Empty(t, j.Pending("c3d4"))
+
+	_, err = j.Acknowledge("", []string{svc})
+	assert.ErrorContains(t, err, "no pending write", "another identity's write cannot be acknowledged")
+	n, err := j.Acknowledge("", []string{"all"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, j.Pending("a1b2"), 1, "all only covers the caller's own writes")
+	require.NoError(t, j.Close())
+}
+
+func TestUnitWriteJournalDisabled(t *testing.T) {
+	j, err := NewWriteJournal("", zap.NewNop())
+	require.NoError(t, err)
+	assert.Nil(t, j)
+	assert.NoError(t, j.Close())
+}
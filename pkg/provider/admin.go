@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/slack-go/slack"
+)
+
+// adminAPIURL is where the admin.* methods are served to org-level tokens,
+// which have no workspace URL of their own.
+const adminAPIURL = "https://slack.com/api/"
+
+// ErrAdminUsersUnavailable is returned when no user token can call the
+// admin.users methods.
+var ErrAdminUsersUnavailable = errors.New("admin user tools need an Enterprise org-level user token with the admin.users:write scope: set SLACK_MCP_ADMIN_TOKEN or SLACK_MCP_XOXP_TOKEN")
+
+// AdminUserInvite are the admin.users.invite arguments.
+type AdminUserInvite struct {
+	TeamID     string
+	Email      string
+	ChannelIDs []string
+	// RealName and CustomMessage are optional.
+	RealName      string
+	CustomMessage string
+}
+
+// AdminUsersInviteContext invites someone to a workspace of the organization.
+func (ap *ApiProvider) AdminUsersInviteContext(ctx context.Context, inv AdminUserInvite) error {
+	form := url.Values{}
+	form.Set("team_id", inv.TeamID)
+	form.Set("email", inv.Email)
+	form.Set("channel_ids", strings.Join(inv.ChannelIDs, ","))
+	if inv.RealName != "" {
+		form.Set("real_name", inv.RealName)
+	}
+	if inv.CustomMessage != "" {
+		form.Set("custom_message", inv.CustomMessage)
+	}
+	return ap.adminCall(ctx, "admin.users.invite", form)
+}
+
+// AdminUsersRemoveContext removes a user from a workspace of the
+// organization.
+func (ap *ApiProvider) AdminUsersRemoveContext(ctx context.Context, teamID, userID string) error {
+	form := url.Values{}
+	form.Set("team_id", teamID)
+	form.Set("user_id", userID)
+	return ap.adminCall(ctx, "admin.users.remove", form)
+}
+
+// AdminUsersSessionResetContext signs a user out of every session on every
+// device.
+func (ap *ApiProvider) AdminUsersSessionResetContext(ctx context.Context, userID string) error {
+	form := url.Values{}
+	form.Set("user_id", userID)
+	return ap.adminCall(ctx, "admin.users.session.reset", form)
+}
+
+// adminCall posts form to an admin method with SLACK_MCP_ADMIN_TOKEN, or
+// with the user token when it is not set.
+func (ap *ApiProvider) adminCall(ctx context.Context, method string, form url.Values) error {
+	ap.mu.RLock()
+	token := ap.tokens.admin
+	if token == "" {
+		token = ap.tokens.xoxp
+	}
+	ap.mu.RUnlock()
+	if token == "" {
+		return ErrAdminUsersUnavailable
+	}
+
+	// The admin methods share the audit logs API's HTTP client; both only
+	// talk to slack.com with org-level tokens.
+	ap.audit.once.Do(func() {
+		ap.audit.http = transport.ProvideHTTPClient(nil, ap.Config().LogAPICallsEnabled(), ap.logger)
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, adminAPIURL+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := ap.audit.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: slack returned %s", method, resp.Status)
+	}
+	var result slack.SlackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if err := result.Err(); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -16,8 +17,10 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
 	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider/edge"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
 	"github.com/korotovsky/slack-mcp-server/pkg/transport"
 	"github.com/rusq/slackdump/v3/auth"
 	"github.com/slack-go/slack"
@@ -59,11 +62,32 @@ type SavedListResponse struct {
 	} `json:"response_metadata"`
 }
 
+// ThreadsViewResponse is the response from Slack's internal
+// subscriptions.thread.getView API, which backs the client's "Threads" view.
+type ThreadsViewResponse struct {
+	Ok                 bool         `json:"ok"`
+	Error              string       `json:"error,omitempty"`
+	TotalUnreadReplies int          `json:"total_unread_replies"`
+	NewThreadsCount    int          `json:"new_threads_count"`
+	HasMore            bool         `json:"has_more"`
+	MaxTs              string       `json:"max_ts"`
+	Threads            []ThreadView `json:"threads"`
+}
+
+// ThreadView is a single subscribed thread. RootMsg carries the channel,
+// reply_count, latest_reply and last_read markers.
+type ThreadView struct {
+	RootMsg       slack.Msg   `json:"root_msg"`
+	LatestReplies []slack.Msg `json:"latest_replies"`
+	UnreadReplies []slack.Msg `json:"unread_replies"`
+}
+
 const usersNotReadyMsg = "users cache is not ready yet, sync process is still running... please wait"
 const channelsNotReadyMsg = "channels cache is not ready yet, sync process is still running... please wait"
 const defaultUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36"
 const defaultCacheTTL = 1 * time.Hour
 const defaultMinRefreshInterval = 30 * time.Second
+const channelsPageMaxRetries = 5
 
 var AllChanTypes = []string{"mpim", "im", "public_channel", "private_channel"}
 var PrivateChanType = "private_channel"
@@ -89,76 +113,45 @@ func getCacheDir() string {
 	return dir
 }
 
-// getCacheTTL returns the cache TTL from SLACK_MCP_CACHE_TTL env var or default (1 hour).
+// getCacheTTL returns the cache TTL set by SLACK_MCP_CACHE_TTL or the default (1 hour).
 // Supports formats: "1h", "30m", "3600" (seconds), "0" (disable TTL, cache forever)
 // Negative values are rejected and fall back to default.
-func getCacheTTL() time.Duration {
-	ttlStr := os.Getenv("SLACK_MCP_CACHE_TTL")
-	if ttlStr == "" {
+func getCacheTTL(cfg *config.Config) time.Duration {
+	if cfg.CacheTTL == "" {
 		return defaultCacheTTL
 	}
-
-	// Try parsing as duration first (e.g., "1h", "30m")
-	if d, err := time.ParseDuration(ttlStr); err == nil {
-		if d < 0 {
-			return defaultCacheTTL // Reject negative TTL
-		}
-		return d
-	}
-
-	// Try parsing as seconds (e.g., "3600")
-	if secs, err := strconv.ParseInt(ttlStr, 10, 64); err == nil {
-		if secs < 0 {
-			return defaultCacheTTL // Reject negative TTL
-		}
-		return time.Duration(secs) * time.Second
+	d, err := config.ParseDuration(cfg.CacheTTL)
+	if err != nil {
+		return defaultCacheTTL
 	}
-
-	return defaultCacheTTL
+	return d
 }
 
-// getMinRefreshInterval returns the minimum interval between forced refreshes from
-// SLACK_MCP_MIN_REFRESH_INTERVAL env var or default (30s).
+// getMinRefreshInterval returns the minimum interval between forced refreshes set by
+// SLACK_MCP_MIN_REFRESH_INTERVAL or the default (30s).
 // Supports formats: "30s", "1m", "60" (seconds), "0" (disable rate limiting)
 // Negative values are rejected and fall back to default.
-func getMinRefreshInterval() time.Duration {
-	intervalStr := os.Getenv("SLACK_MCP_MIN_REFRESH_INTERVAL")
-	if intervalStr == "" {
+func getMinRefreshInterval(cfg *config.Config) time.Duration {
+	if cfg.MinRefreshInterval == "" {
 		return defaultMinRefreshInterval
 	}
-
-	// Try parsing as duration first (e.g., "30s", "1m")
-	if d, err := time.ParseDuration(intervalStr); err == nil {
-		if d < 0 {
-			return defaultMinRefreshInterval // Reject negative interval
-		}
-		return d
-	}
-
-	// Try parsing as seconds (e.g., "60")
-	if secs, err := strconv.ParseInt(intervalStr, 10, 64); err == nil {
-		if secs < 0 {
-			return defaultMinRefreshInterval // Reject negative interval
-		}
-		return time.Duration(secs) * time.Second
+	d, err := config.ParseDuration(cfg.MinRefreshInterval)
+	if err != nil {
+		return defaultMinRefreshInterval
 	}
-
-	return defaultMinRefreshInterval
+	return d
 }
 
 // validateAuthAndGetTeamID performs auth validation on startup and returns the TeamID.
 // This ensures tokens are valid before proceeding and enables cache namespacing
 // to prevent cache contamination when using multiple Slack workspaces.
 // Returns an error if authentication fails - the server should not start with invalid credentials.
-func validateAuthAndGetTeamID(authProvider auth.Provider, logger *zap.Logger) (string, error) {
-	xoxpToken := os.Getenv("SLACK_MCP_XOXP_TOKEN")
-	xoxcToken := os.Getenv("SLACK_MCP_XOXC_TOKEN")
-	xoxdToken := os.Getenv("SLACK_MCP_XOXD_TOKEN")
-	if xoxpToken == "demo" || (xoxcToken == "demo" && xoxdToken == "demo") {
+func validateAuthAndGetTeamID(cfg *config.Config, authProvider auth.Provider, logger *zap.Logger) (string, error) {
+	if cfg.Demo() {
 		return "demo", nil
 	}
 
-	httpClient := transport.ProvideHTTPClient(authProvider.Cookies(), logger)
+	httpClient := transport.ProvideHTTPClient(authProvider.Cookies(), cfg.LogAPICallsEnabled(), logger)
 	slackOpts := []slack.Option{slack.OptionHTTPClient(httpClient)}
 	if os.Getenv("SLACK_MCP_GOVSLACK") == "true" {
 		slackOpts = append(slackOpts, slack.OptionAPIURL("https://slack-gov.com/api/"))
@@ -207,6 +200,9 @@ type Channel struct {
 	IsMpIM      bool     `json:"mpim"`
 	IsIM        bool     `json:"im"`
 	IsPrivate   bool     `json:"private"`
+	// IsMember is whether the authenticated user has joined the channel.
+	// DMs may leave it unset; the user is always a member of those.
+	IsMember    bool     `json:"member,omitempty"`
 	User        string   `json:"user,omitempty"`    // User ID for IM channels
 	Members     []string `json:"members,omitempty"` // Member IDs for the channel
 }
@@ -216,11 +212,24 @@ type SlackAPI interface {
 	AuthTest() (*slack.AuthTestResponse, error)
 	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
 	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error)
+	GetUsersPaginated(options ...slack.GetUsersOption) slack.UserPagination
 	GetUsersInfo(users ...string) (*[]slack.User, error)
 	PostMessageContext(ctx context.Context, channel string, options ...slack.MsgOption) (string, string, error)
+	ScheduleMessageContext(ctx context.Context, channel, postAt string, options ...slack.MsgOption) (string, string, error)
+	DeleteMessageContext(ctx context.Context, channel, messageTimestamp string) (string, string, error)
 	MarkConversationContext(ctx context.Context, channel, ts string) error
 	AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error
 	RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error
+	GetReactionsContext(ctx context.Context, item slack.ItemRef, params slack.GetReactionsParameters) ([]slack.ItemReaction, error)
+	ListReactionsContext(ctx context.Context, params slack.ListReactionsParameters) ([]slack.ReactedItem, *slack.Paging, error)
+	GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
+	GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error)
+	GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error)
+	SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error
+	SetUserProfileContext(ctx context.Context, profile map[string]any) (*UpdatedUserProfile, error)
+	SetAssistantThreadsStatusContext(ctx context.Context, params slack.AssistantThreadsSetStatusParameters) error
+	SetAssistantThreadsTitleContext(ctx context.Context, params slack.AssistantThreadsSetTitleParameters) error
+	SetAssistantThreadsSuggestedPromptsContext(ctx context.Context, params slack.AssistantThreadsSetSuggestedPromptsParameters) error
 
 	// Used to get messages
 	GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
@@ -229,10 +238,18 @@ type SlackAPI interface {
 
 	// Used to get files
 	GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error)
+	GetFilesContext(ctx context.Context, params slack.GetFilesParameters) ([]slack.File, *slack.Paging, error)
 	GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error
 
 	// Used to get channels list from both Slack and Enterprise Grid versions
 	GetConversationsContext(ctx context.Context, params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+	GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error)
+	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
+	SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error)
+	SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error)
 
 	// Edge API methods
 	ClientUserBoot(ctx context.Context) (*edge.ClientUserBootResponse, error)
@@ -244,15 +261,30 @@ type SlackAPI interface {
 	CreateUserGroupContext(ctx context.Context, userGroup slack.UserGroup, options ...slack.CreateUserGroupOption) (slack.UserGroup, error)
 	UpdateUserGroupContext(ctx context.Context, userGroupID string, options ...slack.UpdateUserGroupsOption) (slack.UserGroup, error)
 	UpdateUserGroupMembersContext(ctx context.Context, userGroup string, members string, options ...slack.UpdateUserGroupMembersOption) (slack.UserGroup, error)
+	DisableUserGroupContext(ctx context.Context, userGroup string, options ...slack.DisableUserGroupOption) (slack.UserGroup, error)
+	EnableUserGroupContext(ctx context.Context, userGroup string, options ...slack.EnableUserGroupOption) (slack.UserGroup, error)
 
 	// Saved items (undocumented internal API)
 	SavedListContext(ctx context.Context, cursor string) (*SavedListResponse, error)
-	SavedCompleteContext(ctx context.Context, channel, ts string) error
+	SavedCompleteContext(ctx context.Context, channel, ts, threadTs string) error
+
+	// Threads the user participates in (undocumented internal API)
+	ThreadsViewContext(ctx context.Context, maxTs string, limit int) (*ThreadsViewResponse, error)
+	ThreadSubscriptionContext(ctx context.Context, channel, threadTs string, follow bool) error
+
+	// OAuth scopes granted to the token, nil when they cannot be determined
+	GrantedScopesContext(ctx context.Context) ([]string, error)
+
+	// Workspace info and custom profile fields
+	GetTeamInfoContext(ctx context.Context) (*TeamInfo, error)
+	GetTeamProfileContext(ctx context.Context) (*slack.TeamProfile, error)
+	GetTeamDefaultChannelsContext(ctx context.Context, teamID string) ([]string, error)
 }
 
 type MCPSlackClient struct {
 	slackClient *slack.Client
 	edgeClient  *edge.Client
+	httpClient  *http.Client
 
 	authResponse *slack.AuthTestResponse
 	authProvider auth.Provider
@@ -261,16 +293,30 @@ type MCPSlackClient struct {
 	isOAuth      bool
 	isBotToken   bool
 	teamEndpoint string
+
+	// bot serves botMethods when a bot token is configured next to a user
+	// token; see config.BotRoutableMethods.
+	bot        *MCPSlackClient
+	botMethods map[string]bool
 }
 
 type ApiProvider struct {
 	transport string
-	client    SlackAPI
 	logger    *zap.Logger
 
-	rateLimiter        *rate.Limiter
-	cacheTTL           time.Duration
-	minRefreshInterval time.Duration
+	// mu protects config, client, tokens and channelPolicy, which ApplyConfig
+	// replaces on reload and WatchSecrets when secrets change.
+	mu            sync.RWMutex
+	config        *config.Config
+	client        SlackAPI
+	tokens        slackTokens
+	channelPolicy *ChannelPolicy
+
+	rateLimiter *rate.Limiter
+
+	// store keeps the users and channels caches instead of their files
+	// when SLACK_MCP_STORE is set, so replicas share them
+	store store.Store
 
 	// Users cache: atomic pointer to immutable snapshot (no copy on read)
 	usersSnapshot atomic.Pointer[UsersCache]
@@ -278,6 +324,9 @@ type ApiProvider struct {
 	usersReady     bool
 	lastForcedUsersRefresh time.Time
 	usersMu                sync.RWMutex // protects usersReady, lastForcedUsersRefresh
+	usersMergeMu           sync.Mutex   // serializes read-modify-write of usersSnapshot
+	usersWarming           atomic.Bool  // set while pages from the Slack API are being streamed into the snapshot
+	usersProgress          cacheProgress
 
 	// Channels cache: atomic pointer to immutable snapshot (no copy on read)
 	channelsSnapshot atomic.Pointer[ChannelsCache]
@@ -285,10 +334,25 @@ type ApiProvider struct {
 	channelsReady     bool
 	lastForcedChannelsRefresh time.Time
 	channelsMu                sync.RWMutex // protects channelsReady, lastForcedChannelsRefresh
+	channelsWarming           atomic.Bool  // set while pages from the Slack API are being streamed into the snapshot
+	channelsMergeMu           sync.Mutex   // serializes read-modify-write of channelsSnapshot
+	channelsProgress          cacheProgress
+
+	// DND schedules, looked up on demand for working-hours hints
+	dnd dndCache
+
+	// Presence, looked up on demand for users_search availability filters
+	presence presenceCache
+
+	// Outcome of the latest WatchTokenHealth check
+	tokenHealth tokenHealthState
+
+	// HTTP client of the Enterprise audit logs and admin APIs, created on first use
+	audit auditClient
 }
 
-func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlackClient, error) {
-	httpClient := transport.ProvideHTTPClient(authProvider.Cookies(), logger)
+func NewMCPSlackClient(cfg *config.Config, authProvider auth.Provider, logger *zap.Logger) (*MCPSlackClient, error) {
+	httpClient := transport.ProvideHTTPClient(authProvider.Cookies(), cfg.LogAPICallsEnabled(), logger)
 
 	slackOpts := []slack.Option{slack.OptionHTTPClient(httpClient)}
 	if os.Getenv("SLACK_MCP_GOVSLACK") == "true" {
@@ -335,6 +399,7 @@ func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlac
 	return &MCPSlackClient{
 		slackClient:  slackClient,
 		edgeClient:   edgeClient,
+		httpClient:   httpClient,
 		authResponse: authResponse,
 		authProvider: authProvider,
 		isEnterprise: isEnterprise,
@@ -345,7 +410,8 @@ func NewMCPSlackClient(authProvider auth.Provider, logger *zap.Logger) (*MCPSlac
 }
 
 func (c *MCPSlackClient) AuthTest() (*slack.AuthTestResponse, error) {
-	if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+	// The client is only nil with the demo tokens, see config.Config.Demo.
+	if c == nil {
 		return &slack.AuthTestResponse{
 			URL:          "https://_.slack.com",
 			Team:         "Demo Team",
@@ -372,6 +438,10 @@ func (c *MCPSlackClient) GetUsersContext(ctx context.Context, options ...slack.G
 	return c.slackClient.GetUsersContext(ctx, options...)
 }
 
+func (c *MCPSlackClient) GetUsersPaginated(options ...slack.GetUsersOption) slack.UserPagination {
+	return c.slackClient.GetUsersPaginated(options...)
+}
+
 func (c *MCPSlackClient) GetUsersInfo(users ...string) (*[]slack.User, error) {
 	return c.slackClient.GetUsersInfo(users...)
 }
@@ -402,6 +472,7 @@ func (c *MCPSlackClient) GetConversationsContext(ctx context.Context, params *sl
 
 				channels = append(channels, slack.Channel{
 					IsGeneral: ec.IsGeneral,
+					IsMember:  ec.IsMember,
 					GroupConversation: slack.GroupConversation{
 						Conversation: slack.Conversation{
 							ID:                 ec.ID,
@@ -437,6 +508,34 @@ func (c *MCPSlackClient) GetConversationsContext(ctx context.Context, params *sl
 	return c.slackClient.GetConversationsContext(ctx, params)
 }
 
+func (c *MCPSlackClient) GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	return c.slackClient.GetConversationInfoContext(ctx, input)
+}
+
+func (c *MCPSlackClient) GetConversationsForUserContext(ctx context.Context, params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	return c.slackClient.GetConversationsForUserContext(ctx, params)
+}
+
+func (c *MCPSlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	return c.slackClient.OpenConversationContext(ctx, params)
+}
+
+func (c *MCPSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	return c.route("conversations.invite").slackClient.InviteUsersToConversationContext(ctx, channelID, users...)
+}
+
+func (c *MCPSlackClient) SetTopicOfConversationContext(ctx context.Context, channelID, topic string) (*slack.Channel, error) {
+	return c.slackClient.SetTopicOfConversationContext(ctx, channelID, topic)
+}
+
+func (c *MCPSlackClient) SetPurposeOfConversationContext(ctx context.Context, channelID, purpose string) (*slack.Channel, error) {
+	return c.slackClient.SetPurposeOfConversationContext(ctx, channelID, purpose)
+}
+
+func (c *MCPSlackClient) GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error) {
+	return c.slackClient.GetUserByEmailContext(ctx, email)
+}
+
 func (c *MCPSlackClient) GetConversationHistoryContext(ctx context.Context, params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
 	return c.slackClient.GetConversationHistoryContext(ctx, params)
 }
@@ -450,21 +549,92 @@ func (c *MCPSlackClient) SearchContext(ctx context.Context, query string, params
 }
 
 func (c *MCPSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
-	return c.slackClient.PostMessageContext(ctx, channelID, options...)
+	return c.route("chat.postMessage").slackClient.PostMessageContext(ctx, channelID, options...)
+}
+
+func (c *MCPSlackClient) ScheduleMessageContext(ctx context.Context, channelID, postAt string, options ...slack.MsgOption) (string, string, error) {
+	return c.route("chat.scheduleMessage").slackClient.ScheduleMessageContext(ctx, channelID, postAt, options...)
+}
+
+func (c *MCPSlackClient) DeleteMessageContext(ctx context.Context, channelID, messageTimestamp string) (string, string, error) {
+	return c.route("chat.delete").slackClient.DeleteMessageContext(ctx, channelID, messageTimestamp)
+}
+
+func (c *MCPSlackClient) GetDNDTeamInfoContext(ctx context.Context, users []string) (map[string]slack.DNDStatus, error) {
+	return c.slackClient.GetDNDTeamInfoContext(ctx, users)
+}
+
+func (c *MCPSlackClient) GetUserPresenceContext(ctx context.Context, user string) (*slack.UserPresence, error) {
+	return c.slackClient.GetUserPresenceContext(ctx, user)
 }
 
 func (c *MCPSlackClient) AddReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
-	return c.slackClient.AddReactionContext(ctx, name, item)
+	return c.route("reactions.add").slackClient.AddReactionContext(ctx, name, item)
 }
 
 func (c *MCPSlackClient) RemoveReactionContext(ctx context.Context, name string, item slack.ItemRef) error {
-	return c.slackClient.RemoveReactionContext(ctx, name, item)
+	return c.route("reactions.remove").slackClient.RemoveReactionContext(ctx, name, item)
+}
+
+func (c *MCPSlackClient) GetReactionsContext(ctx context.Context, item slack.ItemRef, params slack.GetReactionsParameters) ([]slack.ItemReaction, error) {
+	return c.slackClient.GetReactionsContext(ctx, item, params)
+}
+
+func (c *MCPSlackClient) ListReactionsContext(ctx context.Context, params slack.ListReactionsParameters) ([]slack.ReactedItem, *slack.Paging, error) {
+	return c.slackClient.ListReactionsContext(ctx, params)
+}
+
+// GrantedScopesContext returns the scopes listed in the X-OAuth-Scopes header
+// of an auth.test call. Session tokens (xoxc/xoxd) are not scoped, so nil is
+// returned for them, meaning "unknown, assume everything is allowed".
+func (c *MCPSlackClient) GrantedScopesContext(ctx context.Context) ([]string, error) {
+	if c == nil || !c.isOAuth {
+		return nil, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.teamEndpoint+"api/auth.test", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.authProvider.SlackToken())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if header == "" {
+		return nil, nil
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return c.grantedBotScopes(ctx, scopes)
+}
+
+func (c *MCPSlackClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	return c.slackClient.GetUserProfileContext(ctx, params)
+}
+
+func (c *MCPSlackClient) SetUserCustomStatusContext(ctx context.Context, statusText, statusEmoji string, statusExpiration int64) error {
+	return c.slackClient.SetUserCustomStatusContext(ctx, statusText, statusEmoji, statusExpiration)
 }
 
 func (c *MCPSlackClient) GetFileInfoContext(ctx context.Context, fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
 	return c.slackClient.GetFileInfoContext(ctx, fileID, count, page)
 }
 
+func (c *MCPSlackClient) GetFilesContext(ctx context.Context, params slack.GetFilesParameters) ([]slack.File, *slack.Paging, error) {
+	return c.slackClient.GetFilesContext(ctx, params)
+}
+
 func (c *MCPSlackClient) GetFileContext(ctx context.Context, downloadURL string, writer io.Writer) error {
 	return c.slackClient.GetFileContext(ctx, downloadURL, writer)
 }
@@ -497,6 +667,14 @@ func (c *MCPSlackClient) UpdateUserGroupMembersContext(ctx context.Context, user
 	return c.slackClient.UpdateUserGroupMembersContext(ctx, userGroup, members, options...)
 }
 
+func (c *MCPSlackClient) DisableUserGroupContext(ctx context.Context, userGroup string, options ...slack.DisableUserGroupOption) (slack.UserGroup, error) {
+	return c.slackClient.DisableUserGroupContext(ctx, userGroup, options...)
+}
+
+func (c *MCPSlackClient) EnableUserGroupContext(ctx context.Context, userGroup string, options ...slack.EnableUserGroupOption) (slack.UserGroup, error) {
+	return c.slackClient.EnableUserGroupContext(ctx, userGroup, options...)
+}
+
 func (c *MCPSlackClient) SavedListContext(ctx context.Context, cursor string) (*SavedListResponse, error) {
 	form := url.Values{}
 	if cursor != "" {
@@ -522,11 +700,14 @@ type SavedCompleteResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
-func (c *MCPSlackClient) SavedCompleteContext(ctx context.Context, channel, ts string) error {
+func (c *MCPSlackClient) SavedCompleteContext(ctx context.Context, channel, ts, threadTs string) error {
 	form := url.Values{}
 	form.Set("item_type", "message")
 	form.Set("item_id", channel)
 	form.Set("ts", ts)
+	if threadTs != "" && threadTs != ts {
+		form.Set("thread_ts", threadTs)
+	}
 	form.Set("date_due", "0")
 	form.Set("mark", "completed")
 	form.Set("_x_reason", "manually_mark_completed")
@@ -547,6 +728,60 @@ func (c *MCPSlackClient) SavedCompleteContext(ctx context.Context, channel, ts s
 	return nil
 }
 
+// ThreadsViewContext returns a page of threads the user is subscribed to,
+// most recently active first. maxTs is the max_ts of the previous page.
+func (c *MCPSlackClient) ThreadsViewContext(ctx context.Context, maxTs string, limit int) (*ThreadsViewResponse, error) {
+	form := url.Values{}
+	form.Set("current_ts", strconv.FormatInt(time.Now().Unix(), 10)+".000000")
+	form.Set("limit", strconv.Itoa(limit))
+	if maxTs != "" {
+		form.Set("max_ts", maxTs)
+	}
+	form.Set("_x_reason", "fetch-threads-via-refresh")
+	form.Set("_x_mode", "online")
+	form.Set("_x_sonic", "true")
+	form.Set("_x_app_name", "client")
+	resp, err := c.edgeClient.PostForm(ctx, "subscriptions.thread.getView", form)
+	if err != nil {
+		return nil, fmt.Errorf("subscriptions.thread.getView request failed: %w", err)
+	}
+	var result ThreadsViewResponse
+	if err := c.edgeClient.ParseResponse(&result, resp); err != nil {
+		return nil, fmt.Errorf("subscriptions.thread.getView parse failed: %w", err)
+	}
+	if !result.Ok {
+		return nil, fmt.Errorf("subscriptions.thread.getView API error: %s", result.Error)
+	}
+	return &result, nil
+}
+
+// ThreadSubscriptionContext follows or unfollows a thread for the user, as
+// the "Get notified about new replies" menu item does.
+func (c *MCPSlackClient) ThreadSubscriptionContext(ctx context.Context, channel, threadTs string, follow bool) error {
+	method := "subscriptions.thread.remove"
+	if follow {
+		method = "subscriptions.thread.add"
+	}
+	form := url.Values{}
+	form.Set("channel", channel)
+	form.Set("thread_ts", threadTs)
+	form.Set("_x_mode", "online")
+	form.Set("_x_sonic", "true")
+	form.Set("_x_app_name", "client")
+	resp, err := c.edgeClient.PostForm(ctx, method, form)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", method, err)
+	}
+	var result slack.SlackResponse
+	if err := c.edgeClient.ParseResponse(&result, resp); err != nil {
+		return fmt.Errorf("%s parse failed: %w", method, err)
+	}
+	if !result.Ok {
+		return fmt.Errorf("%s API error: %s", method, result.Error)
+	}
+	return nil
+}
+
 func (c *MCPSlackClient) IsEnterprise() bool {
 	return c.isEnterprise
 }
@@ -576,36 +811,42 @@ func (c *MCPSlackClient) Raw() struct {
 	}
 }
 
-func New(transport string, logger *zap.Logger) *ApiProvider {
+// New builds the provider for the tokens, transport and channel policy in cfg.
+func New(cfg *config.Config, logger *zap.Logger) *ApiProvider {
 	var (
 		authProvider auth.ValueAuth
 		err          error
 	)
 
-	// Read all environment variables
-	xoxpToken := os.Getenv("SLACK_MCP_XOXP_TOKEN")
-	xoxbToken := os.Getenv("SLACK_MCP_XOXB_TOKEN")
-	xoxcToken := os.Getenv("SLACK_MCP_XOXC_TOKEN")
-	xoxdToken := os.Getenv("SLACK_MCP_XOXD_TOKEN")
-
-	// Warn if both user and bot tokens are set
-	if xoxpToken != "" && xoxbToken != "" {
-		logger.Warn(
-			"Both SLACK_MCP_XOXP_TOKEN and SLACK_MCP_XOXB_TOKEN are set. "+
-				"Using User token (xoxp) for full features. "+
-				"Bot token will be ignored.",
-			zap.String("context", "console"),
-		)
+	tokens, err := resolveTokens(context.Background(), cfg)
+	if err != nil {
+		logger.Fatal("Failed to load Slack tokens from secret backend", zap.Error(err))
 	}
+	xoxpToken := tokens.xoxp
+	xoxbToken := tokens.xoxb
+	xoxcToken := tokens.xoxc
+	xoxdToken := tokens.xoxd
 
-	// Priority 1: XOXP token (User OAuth)
+	// Priority 1: XOXP token (User OAuth), with the bot token for the
+	// methods in SLACK_MCP_BOT_TOKEN_METHODS when both are set
 	if xoxpToken != "" {
 		authProvider, err = auth.NewValueAuth(xoxpToken, "")
 		if err != nil {
 			logger.Fatal("Failed to create auth provider with XOXP token", zap.Error(err))
 		}
 
-		return newWithXOXP(transport, authProvider, logger)
+		ap := newWithXOXP(cfg, authProvider, logger)
+		ap.tokens = tokens
+		if client, ok := ap.client.(*MCPSlackClient); ok && client != nil && xoxbToken != "" {
+			if err := client.attachBotToken(cfg, xoxbToken, logger); err != nil {
+				logger.Fatal("Failed to use SLACK_MCP_XOXB_TOKEN next to SLACK_MCP_XOXP_TOKEN", zap.Error(err))
+			}
+			logger.Info("Using User token with Bot token for selected methods",
+				zap.String("context", "console"),
+				zap.Strings("bot_methods", client.BotTokenMethods()),
+			)
+		}
+		return ap
 	}
 
 	// Priority 2: XOXB token (Bot)
@@ -620,7 +861,9 @@ func New(transport string, logger *zap.Logger) *ApiProvider {
 			zap.String("token_type", "xoxb"),
 		)
 
-		return newWithXOXB(transport, authProvider, logger)
+		ap := newWithXOXB(cfg, authProvider, logger)
+		ap.tokens = tokens
+		return ap
 	}
 
 	// Priority 3: XOXC/XOXD tokens (session-based)
@@ -633,48 +876,56 @@ func New(transport string, logger *zap.Logger) *ApiProvider {
 		logger.Fatal("Failed to create auth provider with XOXC/XOXD tokens", zap.Error(err))
 	}
 
-	return newWithXOXC(transport, authProvider, logger)
+	ap := newWithXOXC(cfg, authProvider, logger)
+	ap.tokens = tokens
+	return ap
 }
 
-func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
+func newWithXOXP(cfg *config.Config, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
 	var (
 		client *MCPSlackClient
 		err    error
 	)
 
-	teamID, err := validateAuthAndGetTeamID(authProvider, logger)
+	teamID, err := validateAuthAndGetTeamID(cfg, authProvider, logger)
 	if err != nil {
 		logger.Fatal("Authentication failed - check your Slack tokens", zap.Error(err))
 	}
 
-	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
+	usersCache := cfg.UsersCache
 	if usersCache == "" {
 		usersCache = getCachePathWithTeamID(teamID, "users_cache.json")
 	}
 
-	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
+	channelsCache := cfg.ChannelsCache
 	if channelsCache == "" {
 		channelsCache = getCachePathWithTeamID(teamID, "channels_cache_v2.json")
 	}
 
-	if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+	st, err := sharedStore(cfg)
+	if err != nil {
+		logger.Fatal("Failed to open SLACK_MCP_STORE", zap.Error(err))
+	}
+
+	if cfg.Demo() {
 		logger.Info("Demo credentials are set, skip.")
 	} else {
-		client, err = NewMCPSlackClient(authProvider, logger)
+		client, err = NewMCPSlackClient(cfg, authProvider, logger)
 		if err != nil {
 			logger.Fatal("Failed to create MCP Slack client", zap.Error(err))
 		}
 	}
 
 	ap := &ApiProvider{
-		transport: transport,
+		transport: cfg.Transport,
+		config:    cfg,
 		client:    client,
 		logger:    logger,
 
-		rateLimiter:        limiter.Tier2.Limiter(),
-		cacheTTL:           getCacheTTL(),
-		minRefreshInterval: getMinRefreshInterval(),
+		rateLimiter:   limiter.Tier2.Limiter(),
+		channelPolicy: NewChannelPolicy(cfg.ChannelAllowlist, cfg.ChannelDenylist),
 
+		store:             st,
 		usersCachePath:    usersCache,
 		channelsCachePath: channelsCache,
 	}
@@ -690,51 +941,106 @@ func newWithXOXP(transport string, authProvider auth.ValueAuth, logger *zap.Logg
 	return ap
 }
 
-func newWithXOXB(transport string, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
+func newWithXOXB(cfg *config.Config, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
 	// Bot tokens do not support demo mode, but otherwise share the same
 	// initialization logic as user OAuth tokens.
-	return newWithXOXP(transport, authProvider, logger)
+	return newWithXOXP(cfg, authProvider, logger)
 }
 
-func newWithXOXC(transport string, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
+// NewForToken builds a provider for a Slack token supplied by an individual
+// HTTP/SSE client in multi-user mode. Unlike New it returns errors instead of
+// exiting, and its caches are namespaced by team and user so users never see
+// each other's private channels.
+func NewForToken(cfg *config.Config, token string, logger *zap.Logger) (*ApiProvider, error) {
+	if !strings.HasPrefix(token, "xoxp-") && !strings.HasPrefix(token, "xoxb-") {
+		return nil, errors.New("only xoxp and xoxb tokens are accepted from clients")
+	}
+
+	authProvider, err := auth.NewValueAuth(token, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewMCPSlackClient(cfg, authProvider, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := sharedStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := client.authResponse.TeamID + "_" + client.authResponse.UserID
+	ap := &ApiProvider{
+		transport: cfg.Transport,
+		config:    cfg,
+		client:    client,
+		logger:    logger,
+
+		rateLimiter:   limiter.Tier2.Limiter(),
+		channelPolicy: NewChannelPolicy(cfg.ChannelAllowlist, cfg.ChannelDenylist),
+
+		store:             st,
+		usersCachePath:    getCachePathWithTeamID(cacheKey, "users_cache.json"),
+		channelsCachePath: getCachePathWithTeamID(cacheKey, "channels_cache_v2.json"),
+	}
+	ap.usersSnapshot.Store(&UsersCache{
+		Users:    make(map[string]slack.User),
+		UsersInv: make(map[string]string),
+	})
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels:    make(map[string]Channel),
+		ChannelsInv: make(map[string]string),
+	})
+	return ap, nil
+}
+
+func newWithXOXC(cfg *config.Config, authProvider auth.ValueAuth, logger *zap.Logger) *ApiProvider {
 	var (
 		client *MCPSlackClient
 		err    error
 	)
 
-	teamID, err := validateAuthAndGetTeamID(authProvider, logger)
+	teamID, err := validateAuthAndGetTeamID(cfg, authProvider, logger)
 	if err != nil {
 		logger.Fatal("Authentication failed - check your Slack tokens", zap.Error(err))
 	}
 
-	usersCache := os.Getenv("SLACK_MCP_USERS_CACHE")
+	usersCache := cfg.UsersCache
 	if usersCache == "" {
 		usersCache = getCachePathWithTeamID(teamID, "users_cache.json")
 	}
 
-	channelsCache := os.Getenv("SLACK_MCP_CHANNELS_CACHE")
+	channelsCache := cfg.ChannelsCache
 	if channelsCache == "" {
 		channelsCache = getCachePathWithTeamID(teamID, "channels_cache_v2.json")
 	}
 
-	if os.Getenv("SLACK_MCP_XOXP_TOKEN") == "demo" || (os.Getenv("SLACK_MCP_XOXC_TOKEN") == "demo" && os.Getenv("SLACK_MCP_XOXD_TOKEN") == "demo") {
+	st, err := sharedStore(cfg)
+	if err != nil {
+		logger.Fatal("Failed to open SLACK_MCP_STORE", zap.Error(err))
+	}
+
+	if cfg.Demo() {
 		logger.Info("Demo credentials are set, skip.")
 	} else {
-		client, err = NewMCPSlackClient(authProvider, logger)
+		client, err = NewMCPSlackClient(cfg, authProvider, logger)
 		if err != nil {
 			logger.Fatal("Failed to create MCP Slack client", zap.Error(err))
 		}
 	}
 
 	ap := &ApiProvider{
-		transport: transport,
+		transport: cfg.Transport,
+		config:    cfg,
 		client:    client,
 		logger:    logger,
 
-		rateLimiter:        limiter.Tier2.Limiter(),
-		cacheTTL:           getCacheTTL(),
-		minRefreshInterval: getMinRefreshInterval(),
+		rateLimiter:   limiter.Tier2.Limiter(),
+		channelPolicy: NewChannelPolicy(cfg.ChannelAllowlist, cfg.ChannelDenylist),
 
+		store:             st,
 		usersCachePath:    usersCache,
 		channelsCachePath: channelsCache,
 	}
@@ -758,15 +1064,15 @@ func (ap *ApiProvider) RefreshUsers(ctx context.Context) error {
 // Rate limited by SLACK_MCP_MIN_REFRESH_INTERVAL (default 30s) to prevent API abuse.
 // Returns ErrRefreshRateLimited if refresh is skipped due to rate limiting.
 func (ap *ApiProvider) ForceRefreshUsers(ctx context.Context) error {
-	if ap.minRefreshInterval > 0 {
+	if minInterval := getMinRefreshInterval(ap.Config()); minInterval > 0 {
 		// Use single lock scope for check-and-update to prevent TOCTOU race
 		ap.usersMu.Lock()
 		sinceLast := time.Since(ap.lastForcedUsersRefresh)
-		if sinceLast < ap.minRefreshInterval {
+		if sinceLast < minInterval {
 			ap.usersMu.Unlock()
 			ap.logger.Debug("Skipping forced users refresh, within rate limit",
 				zap.Duration("since_last", sinceLast),
-				zap.Duration("min_interval", ap.minRefreshInterval))
+				zap.Duration("min_interval", minInterval))
 			return ErrRefreshRateLimited
 		}
 		// Update timestamp before refresh to prevent concurrent forced refreshes
@@ -783,34 +1089,37 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 	defer ap.usersMu.Unlock()
 
 	var (
-		list        []slack.User
-		optionLimit = slack.GetUsersOptionLimit(1000)
+		list     []slack.User
+		expected int
 	)
 
 	// Check if we should use cache (not forced, cache exists, and within TTL)
 	if !force {
-		if data, err := os.ReadFile(ap.usersCachePath); err == nil {
+		if data, storedAt, err := ap.readCache(ctx, ap.usersCachePath); err == nil {
 			var cachedUsers []slack.User
 			if err := json.Unmarshal(data, &cachedUsers); err != nil {
 				ap.logger.Warn("Failed to unmarshal users cache, will refetch",
 					zap.String("cache_file", ap.usersCachePath),
 					zap.Error(err))
 			} else {
-				// Check cache TTL using file modification time
+				// Check cache TTL using the time the cache was written
 				cacheValid := true
-				if ap.cacheTTL > 0 {
-					if fileInfo, err := os.Stat(ap.usersCachePath); err == nil {
-						cacheAge := time.Since(fileInfo.ModTime())
-						if cacheAge > ap.cacheTTL {
-							ap.logger.Info("Users cache expired, will refetch",
-								zap.Duration("cache_age", cacheAge),
-								zap.Duration("ttl", ap.cacheTTL),
-								zap.String("cache_file", ap.usersCachePath))
-							cacheValid = false
-						}
+				if ttl := getCacheTTL(ap.Config()); ttl > 0 {
+					if cacheAge := time.Since(storedAt); cacheAge > ttl {
+						ap.logger.Info("Users cache expired, will refetch",
+							zap.Duration("cache_age", cacheAge),
+							zap.Duration("ttl", ttl),
+							zap.String("cache_file", ap.usersCachePath))
+						cacheValid = false
 					}
 				}
 
+				if cacheValid && ap.profileFieldsEnabled() && !hasProfileFields(cachedUsers) {
+					ap.logger.Info("Users cache has no custom profile fields, will refetch",
+						zap.String("cache_file", ap.usersCachePath))
+					cacheValid = false
+				}
+
 				if cacheValid {
 					// Build new snapshot from cache
 					newSnapshot := &UsersCache{
@@ -825,19 +1134,26 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 					ap.logger.Info("Loaded users from cache",
 						zap.Int("count", len(cachedUsers)),
 						zap.String("cache_file", ap.usersCachePath))
+					ap.usersProgress.start("file", 0)
+					ap.usersProgress.finish(len(cachedUsers), nil)
 					ap.usersReady = true
 					return nil
 				}
+				expected = len(cachedUsers)
 			}
 		}
 	}
 
-	// Fetch fresh data from Slack API
-	users, err := ap.client.GetUsersContext(ctx,
-		optionLimit,
-	)
+	// Fetch fresh data from Slack API. Pages are merged into the snapshot as
+	// they arrive so callers can resolve users while warming.
+	ap.usersProgress.start("api", expected)
+	ap.usersWarming.Store(true)
+	defer ap.usersWarming.Store(false)
+
+	users, err := ap.fetchUsers(ctx)
 	if err != nil {
 		ap.logger.Error("Failed to fetch users", zap.Error(err))
+		ap.usersProgress.finish(0, err)
 		return err
 	}
 	list = append(list, users...)
@@ -852,17 +1168,27 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 		newSnapshot.UsersInv[user.Name] = user.ID
 	}
 	// Store intermediate snapshot so GetSlackConnect can read current users
+	ap.usersMergeMu.Lock()
 	ap.usersSnapshot.Store(newSnapshot)
+	ap.usersMergeMu.Unlock()
 
 	connectUsers, err := ap.GetSlackConnect(ctx)
 	if err != nil {
 		ap.logger.Error("Failed to fetch users from Slack Connect", zap.Error(err))
+		ap.usersProgress.finish(0, err)
 		return err
 	}
 	list = append(list, connectUsers...)
 
-	// Add Slack Connect users to a new snapshot (since maps are shared)
-	if len(connectUsers) > 0 {
+	if ap.profileFieldsEnabled() {
+		if err := ap.fetchProfileFields(ctx, list); err != nil {
+			ap.logger.Warn("Failed to fetch custom profile fields", zap.Error(err))
+		}
+	}
+
+	// Add Slack Connect users and profile fields to a new snapshot (since
+	// maps are shared)
+	if len(connectUsers) > 0 || ap.profileFieldsEnabled() {
 		finalSnapshot := &UsersCache{
 			Users:    make(map[string]slack.User, len(newSnapshot.Users)+len(connectUsers)),
 			UsersInv: make(map[string]string, len(newSnapshot.UsersInv)+len(connectUsers)),
@@ -873,17 +1199,19 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 		for k, v := range newSnapshot.UsersInv {
 			finalSnapshot.UsersInv[k] = v
 		}
-		for _, user := range connectUsers {
+		for _, user := range list {
 			finalSnapshot.Users[user.ID] = user
 			finalSnapshot.UsersInv[user.Name] = user.ID
 		}
+		ap.usersMergeMu.Lock()
 		ap.usersSnapshot.Store(finalSnapshot)
+		ap.usersMergeMu.Unlock()
 	}
 
 	if data, err := json.MarshalIndent(list, "", "  "); err != nil {
 		ap.logger.Error("Failed to marshal users for cache", zap.Error(err))
 	} else {
-		if err := os.WriteFile(ap.usersCachePath, data, 0644); err != nil {
+		if err := ap.writeCache(ctx, ap.usersCachePath, data); err != nil {
 			ap.logger.Error("Failed to write cache file",
 				zap.String("cache_file", ap.usersCachePath),
 				zap.Error(err))
@@ -895,6 +1223,10 @@ func (ap *ApiProvider) refreshUsersInternal(ctx context.Context, force bool) err
 	}
 
 	ap.usersReady = true
+	ap.usersProgress.finish(len(list), nil)
+	if ap.channelsReady || ap.channelsWarming.Load() {
+		ap.remapIMChannels()
+	}
 
 	return nil
 }
@@ -908,15 +1240,15 @@ func (ap *ApiProvider) RefreshChannels(ctx context.Context) error {
 // Rate limited by SLACK_MCP_MIN_REFRESH_INTERVAL (default 30s) to prevent API abuse.
 // Returns ErrRefreshRateLimited if refresh is skipped due to rate limiting.
 func (ap *ApiProvider) ForceRefreshChannels(ctx context.Context) error {
-	if ap.minRefreshInterval > 0 {
+	if minInterval := getMinRefreshInterval(ap.Config()); minInterval > 0 {
 		// Use single lock scope for check-and-update to prevent TOCTOU race
 		ap.channelsMu.Lock()
 		sinceLast := time.Since(ap.lastForcedChannelsRefresh)
-		if sinceLast < ap.minRefreshInterval {
+		if sinceLast < minInterval {
 			ap.channelsMu.Unlock()
 			ap.logger.Debug("Skipping forced channels refresh, within rate limit",
 				zap.Duration("since_last", sinceLast),
-				zap.Duration("min_interval", ap.minRefreshInterval))
+				zap.Duration("min_interval", minInterval))
 			return ErrRefreshRateLimited
 		}
 		// Update timestamp before refresh to prevent concurrent forced refreshes
@@ -932,30 +1264,35 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 	ap.channelsMu.Lock()
 	defer ap.channelsMu.Unlock()
 
+	var expected int
+
 	// Check if we should use cache (not forced, cache exists, and within TTL)
 	if !force {
-		if data, err := os.ReadFile(ap.channelsCachePath); err == nil {
+		if data, storedAt, err := ap.readCache(ctx, ap.channelsCachePath); err == nil {
 			var cachedChannels []Channel
 			if err := json.Unmarshal(data, &cachedChannels); err != nil {
 				ap.logger.Warn("Failed to unmarshal channels cache, will refetch",
 					zap.String("cache_file", ap.channelsCachePath),
 					zap.Error(err))
 			} else {
-				// Check cache TTL using file modification time
+				// Check cache TTL using the time the cache was written
 				cacheValid := true
-				if ap.cacheTTL > 0 {
-					if fileInfo, err := os.Stat(ap.channelsCachePath); err == nil {
-						cacheAge := time.Since(fileInfo.ModTime())
-						if cacheAge > ap.cacheTTL {
-							ap.logger.Info("Channels cache expired, will refetch",
-								zap.Duration("cache_age", cacheAge),
-								zap.Duration("ttl", ap.cacheTTL),
-								zap.String("cache_file", ap.channelsCachePath))
-							cacheValid = false
-						}
+				if ttl := getCacheTTL(ap.Config()); ttl > 0 {
+					if cacheAge := time.Since(storedAt); cacheAge > ttl {
+						ap.logger.Info("Channels cache expired, will refetch",
+							zap.Duration("cache_age", cacheAge),
+							zap.Duration("ttl", ttl),
+							zap.String("cache_file", ap.channelsCachePath))
+						cacheValid = false
 					}
 				}
 
+				if cacheValid && !hasMembership(cachedChannels) {
+					ap.logger.Info("Channels cache has no channel membership, will refetch",
+						zap.String("cache_file", ap.channelsCachePath))
+					cacheValid = false
+				}
+
 				if cacheValid {
 					// Re-map channels with current users cache to ensure DM names are populated
 					usersMap := ap.ProvideUsersMap().Users
@@ -984,20 +1321,32 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 					ap.logger.Info("Loaded channels from cache and re-mapped DM names",
 						zap.Int("count", len(cachedChannels)),
 						zap.String("cache_file", ap.channelsCachePath))
+					ap.channelsProgress.start("file", 0)
+					ap.channelsProgress.finish(len(cachedChannels), nil)
 					ap.channelsReady = true
 					return nil
 				}
+				expected = len(cachedChannels)
 			}
 		}
 	}
 
-	// Fetch fresh data from Slack API
+	// Fetch fresh data from Slack API. Pages are merged into the snapshot as
+	// they arrive so callers can serve partial results while warming.
+	ap.channelsProgress.start("api", expected)
+	ap.channelsWarming.Store(true)
+	defer ap.channelsWarming.Store(false)
+
 	channels := ap.GetChannels(ctx, AllChanTypes)
+	if ap.usersReady {
+		// DMs fetched while users were still warming may be named by ID.
+		ap.remapIMChannels()
+	}
 
 	if data, err := json.MarshalIndent(channels, "", "  "); err != nil {
 		ap.logger.Error("Failed to marshal channels for cache", zap.Error(err))
 	} else {
-		if err := os.WriteFile(ap.channelsCachePath, data, 0644); err != nil {
+		if err := ap.writeCache(ctx, ap.channelsCachePath, data); err != nil {
 			ap.logger.Error("Failed to write cache file",
 				zap.String("cache_file", ap.channelsCachePath),
 				zap.Error(err))
@@ -1009,12 +1358,13 @@ func (ap *ApiProvider) refreshChannelsInternal(ctx context.Context, force bool)
 	}
 
 	ap.channelsReady = true
+	ap.channelsProgress.finish(len(channels), nil)
 
 	return nil
 }
 
 func (ap *ApiProvider) GetSlackConnect(ctx context.Context) ([]slack.User, error) {
-	boot, err := ap.client.ClientUserBoot(ctx)
+	boot, err := ap.Slack().ClientUserBoot(ctx)
 	if err != nil {
 		ap.logger.Error("Failed to fetch client user boot", zap.Error(err))
 		return nil, err
@@ -1035,7 +1385,7 @@ func (ap *ApiProvider) GetSlackConnect(ctx context.Context) ([]slack.User, error
 
 	res := make([]slack.User, 0, len(collectedIDs))
 	if len(collectedIDs) > 0 {
-		usersInfo, err := ap.client.GetUsersInfo(strings.Join(collectedIDs, ","))
+		usersInfo, err := ap.Slack().GetUsersInfo(strings.Join(collectedIDs, ","))
 		if err != nil {
 			ap.logger.Error("Failed to fetch users info for shared IMs", zap.Error(err))
 			return nil, err
@@ -1064,22 +1414,40 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 		err     error
 	)
 
+	retries := 0
 	for {
 		if err := ap.rateLimiter.Wait(ctx); err != nil {
 			ap.logger.Error("Rate limiter wait failed", zap.Error(err))
-			return nil
+			return chans
 		}
 
-		channels, nextcur, err = ap.client.GetConversationsContext(ctx, params)
+		channels, nextcur, err = ap.Slack().GetConversationsContext(ctx, params)
 		ap.logger.Debug("Fetched channels for ",
 			zap.String("channelType", channelType),
 			zap.Int("count", len(channels)),
 		)
 		if err != nil {
+			var rlErr *slack.RateLimitedError
+			if errors.As(err, &rlErr) && retries < channelsPageMaxRetries {
+				retries++
+				ap.logger.Warn("Rate limited while fetching channels, backing off",
+					zap.String("channelType", channelType),
+					zap.Duration("retry_after", rlErr.RetryAfter),
+					zap.Int("attempt", retries),
+				)
+				select {
+				case <-ctx.Done():
+					return chans
+				case <-time.After(rlErr.RetryAfter):
+				}
+				continue
+			}
 			ap.logger.Error("Failed to fetch channels", zap.Error(err))
 			break
 		}
+		retries = 0
 
+		page := make([]Channel, 0, len(channels))
 		for _, channel := range channels {
 			ch := mapChannel(
 				channel.ID,
@@ -1095,7 +1463,13 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 				channel.IsPrivate,
 				ap.ProvideUsersMap().Users,
 			)
-			chans = append(chans, ch)
+			ch.IsMember = channel.IsMember
+			page = append(page, ch)
+		}
+		chans = append(chans, page...)
+		if ap.channelsWarming.Load() {
+			ap.mergeChannelsSnapshot(page)
+			ap.channelsProgress.page(len(page))
 		}
 
 		if nextcur == "" {
@@ -1107,14 +1481,83 @@ func (ap *ApiProvider) GetChannelsType(ctx context.Context, channelType string)
 	return chans
 }
 
+// mergeChannelsSnapshot publishes a new snapshot containing the current
+// channels plus page, so partially fetched results become visible.
+func (ap *ApiProvider) mergeChannelsSnapshot(page []Channel) {
+	ap.channelsMergeMu.Lock()
+	defer ap.channelsMergeMu.Unlock()
+
+	current := ap.channelsSnapshot.Load()
+	newSnapshot := &ChannelsCache{
+		Channels:    make(map[string]Channel, len(current.Channels)+len(page)),
+		ChannelsInv: make(map[string]string, len(current.ChannelsInv)+len(page)),
+	}
+	for id, ch := range current.Channels {
+		newSnapshot.Channels[id] = ch
+	}
+	for name, id := range current.ChannelsInv {
+		newSnapshot.ChannelsInv[name] = id
+	}
+	for _, ch := range page {
+		newSnapshot.Channels[ch.ID] = ch
+		newSnapshot.ChannelsInv[ch.Name] = ch.ID
+	}
+	ap.channelsSnapshot.Store(newSnapshot)
+}
+
+// RememberChannel adds a conversation opened or joined at runtime to the
+// channels cache, so it resolves by name before the next refresh.
+func (ap *ApiProvider) RememberChannel(channel slack.Channel) Channel {
+	ch := mapChannel(
+		channel.ID,
+		channel.Name,
+		channel.NameNormalized,
+		channel.Topic.Value,
+		channel.Purpose.Value,
+		channel.User,
+		channel.Members,
+		channel.NumMembers,
+		channel.IsIM,
+		channel.IsMpIM,
+		channel.IsPrivate,
+		ap.ProvideUsersMap().Users,
+	)
+	ch.IsMember = true
+	ap.mergeChannelsSnapshot([]Channel{ch})
+	return ch
+}
+
+// UpdateCachedChannel applies update to the cached copy of a channel, e.g.
+// after its topic changed; channels not in the cache are ignored.
+func (ap *ApiProvider) UpdateCachedChannel(id string, update func(*Channel)) {
+	ch, ok := ap.ProvideChannelsMaps().Channels[id]
+	if !ok {
+		return
+	}
+	update(&ch)
+	ap.mergeChannelsSnapshot([]Channel{ch})
+}
+
 func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) []Channel {
 	if len(channelTypes) == 0 {
 		channelTypes = AllChanTypes
 	}
 
+	// Each type has its own cursor, so the types are paged concurrently; they
+	// share the conversations.list rate limiter.
+	byType := make([][]Channel, len(AllChanTypes))
+	var wg sync.WaitGroup
+	for i, t := range AllChanTypes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			byType[i] = ap.GetChannelsType(ctx, t)
+		}()
+	}
+	wg.Wait()
+
 	var chans []Channel
-	for _, t := range AllChanTypes {
-		var typeChannels = ap.GetChannelsType(ctx, t)
+	for _, typeChannels := range byType {
 		chans = append(chans, typeChannels...)
 	}
 
@@ -1127,7 +1570,9 @@ func (ap *ApiProvider) GetChannels(ctx context.Context, channelTypes []string) [
 		newSnapshot.Channels[ch.ID] = ch
 		newSnapshot.ChannelsInv[ch.Name] = ch.ID
 	}
+	ap.channelsMergeMu.Lock()
 	ap.channelsSnapshot.Store(newSnapshot)
+	ap.channelsMergeMu.Unlock()
 
 	// Filter by requested channel types
 	var res []Channel
@@ -1161,8 +1606,11 @@ func (ap *ApiProvider) ProvideChannelsMaps() *ChannelsCache {
 	return ap.channelsSnapshot.Load()
 }
 
+// IsReady reports whether tools can be served. A users cache that is still
+// warming counts as ready: handlers see the users fetched so far and can
+// look up the rest with EnsureUsers.
 func (ap *ApiProvider) IsReady() (bool, error) {
-	if !ap.usersReady {
+	if !ap.usersReady && !ap.usersWarming.Load() {
 		return false, ErrUsersNotReady
 	}
 	if !ap.channelsReady {
@@ -1171,21 +1619,62 @@ func (ap *ApiProvider) IsReady() (bool, error) {
 	return true, nil
 }
 
+// ChannelsWarming reports whether the channels cache is still being populated
+// from the Slack API. While warming, ProvideChannelsMaps returns the pages
+// fetched so far.
+func (ap *ApiProvider) ChannelsWarming() bool {
+	return ap.channelsWarming.Load()
+}
+
 func (ap *ApiProvider) ServerTransport() string {
 	return ap.transport
 }
 
+// Config returns the configuration the provider was built with, or the one
+// last applied by ApplyConfig. Providers built without one, as in tests, get
+// the defaults.
+func (ap *ApiProvider) Config() *config.Config {
+	if ap != nil {
+		ap.mu.RLock()
+		cfg := ap.config
+		ap.mu.RUnlock()
+		if cfg != nil {
+			return cfg
+		}
+	}
+	return config.Default()
+}
+
 func (ap *ApiProvider) Slack() SlackAPI {
+	ap.mu.RLock()
+	defer ap.mu.RUnlock()
 	return ap.client
 }
 
 func (ap *ApiProvider) IsBotToken() bool {
-	client, ok := ap.client.(*MCPSlackClient)
+	client, ok := ap.Slack().(*MCPSlackClient)
 	return ok && client != nil && client.IsBotToken()
 }
 
+// HasBotToken reports whether a bot token serves some methods next to the
+// user token.
+func (ap *ApiProvider) HasBotToken() bool {
+	client, ok := ap.Slack().(*MCPSlackClient)
+	return ok && client != nil && client.HasBotToken()
+}
+
+// MethodUserID returns the ID of the user the Slack API method acts as, which
+// is the bot's user when the method is sent with the bot token.
+func (ap *ApiProvider) MethodUserID(method string) string {
+	client, ok := ap.Slack().(*MCPSlackClient)
+	if !ok || client == nil {
+		return ""
+	}
+	return client.MethodUserID(method)
+}
+
 func (ap *ApiProvider) IsOAuth() bool {
-	client, ok := ap.client.(*MCPSlackClient)
+	client, ok := ap.Slack().(*MCPSlackClient)
 	return ok && client != nil && client.IsOAuth()
 }
 
@@ -1197,7 +1686,7 @@ func (ap *ApiProvider) SearchUsers(ctx context.Context, query string, limit int)
 		return ap.searchUsersInCache(query, limit)
 	}
 
-	return ap.client.UsersSearch(ctx, query, limit)
+	return ap.Slack().UsersSearch(ctx, query, limit)
 }
 
 // searchUsersInCache performs a case-insensitive regex search on cached users.
@@ -1234,6 +1723,18 @@ func (ap *ApiProvider) searchUsersInCache(query string, limit int) ([]slack.User
 	return results, nil
 }
 
+// hasMembership reports whether any channel other than a DM is marked as
+// joined. Caches written before membership was recorded have none, so they
+// are refetched rather than hiding every channel from member_of_only.
+func hasMembership(channels []Channel) bool {
+	for _, c := range channels {
+		if c.IsMember {
+			return true
+		}
+	}
+	return false
+}
+
 func mapChannel(
 	id, name, nameNormalized, topic, purpose, user string,
 	members []string,
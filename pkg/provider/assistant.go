@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/slack-go/slack"
+)
+
+// assistant returns the client for the assistant.threads methods. Only apps
+// can call them, so in dual-token operation they always use the bot token.
+func (c *MCPSlackClient) assistant() *MCPSlackClient {
+	if c.bot != nil {
+		return c.bot
+	}
+	return c
+}
+
+func (c *MCPSlackClient) SetAssistantThreadsStatusContext(ctx context.Context, params slack.AssistantThreadsSetStatusParameters) error {
+	return c.assistant().slackClient.SetAssistantThreadsStatusContext(ctx, params)
+}
+
+func (c *MCPSlackClient) SetAssistantThreadsTitleContext(ctx context.Context, params slack.AssistantThreadsSetTitleParameters) error {
+	return c.assistant().slackClient.SetAssistantThreadsTitleContext(ctx, params)
+}
+
+// SetAssistantThreadsSuggestedPromptsContext calls
+// assistant.threads.setSuggestedPrompts itself, as slack-go drops the title
+// shown above the prompts.
+func (c *MCPSlackClient) SetAssistantThreadsSuggestedPromptsContext(ctx context.Context, params slack.AssistantThreadsSetSuggestedPromptsParameters) error {
+	prompts, err := json.Marshal(params.Prompts)
+	if err != nil {
+		return err
+	}
+	form := url.Values{
+		"channel_id": {params.ChannelID},
+		"thread_ts":  {params.ThreadTS},
+		"prompts":    {string(prompts)},
+	}
+	if params.Title != "" {
+		form.Set("title", params.Title)
+	}
+	var resp slack.SlackResponse
+	return c.assistant().callAPI(ctx, "assistant.threads.setSuggestedPrompts", form, &resp)
+}
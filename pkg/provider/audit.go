@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/slack-go/slack"
+)
+
+// auditAPIURL is where Slack serves the audit logs API; unlike the Web API it
+// is not reachable under the workspace URL.
+const auditAPIURL = "https://api.slack.com/"
+
+// ErrAuditLogsUnavailable is returned when no user token can call the audit
+// logs API.
+var ErrAuditLogsUnavailable = errors.New("audit logs need an Enterprise org-level user token with the auditlogs:read scope: set SLACK_MCP_AUDIT_TOKEN or SLACK_MCP_XOXP_TOKEN")
+
+type auditClient struct {
+	once sync.Once
+	http *http.Client
+}
+
+// AuditLogsContext reads one page of the Enterprise audit logs with
+// SLACK_MCP_AUDIT_TOKEN, or with the user token when it is not set.
+func (ap *ApiProvider) AuditLogsContext(ctx context.Context, params slack.AuditLogParameters) ([]slack.AuditEntry, string, error) {
+	ap.mu.RLock()
+	token := ap.tokens.audit
+	if token == "" {
+		token = ap.tokens.xoxp
+	}
+	ap.mu.RUnlock()
+	if token == "" {
+		return nil, "", ErrAuditLogsUnavailable
+	}
+
+	ap.audit.once.Do(func() {
+		ap.audit.http = transport.ProvideHTTPClient(nil, ap.Config().LogAPICallsEnabled(), ap.logger)
+	})
+	client := slack.New(token, slack.OptionHTTPClient(ap.audit.http), slack.OptionAPIURL(auditAPIURL))
+	return client.GetAuditLogsContext(ctx, params)
+}
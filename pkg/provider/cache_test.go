@@ -1,76 +1,70 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 // TestGetCacheTTL tests the app-specific logic in getCacheTTL:
-// - Default when env not set
+// - Default when not set
 // - Numeric seconds fallback (app-specific parsing path)
 // - Invalid input handling
 // - Negative value rejection (P1 bug fix)
 func TestGetCacheTTL(t *testing.T) {
 	tests := []struct {
 		name     string
-		envValue string
+		value    string
 		expected time.Duration
 	}{
 		{
-			name:     "default when env not set",
-			envValue: "",
+			name:     "default when unset",
+			value:    "",
 			expected: defaultCacheTTL,
 		},
 		{
 			name:     "valid duration passes through",
-			envValue: "2h",
+			value:    "2h",
 			expected: 2 * time.Hour,
 		},
 		{
 			name:     "numeric seconds fallback path",
-			envValue: "3600",
+			value:    "3600",
 			expected: 3600 * time.Second,
 		},
 		{
 			name:     "zero disables TTL",
-			envValue: "0",
+			value:    "0",
 			expected: 0,
 		},
 		{
 			name:     "invalid input falls back to default",
-			envValue: "invalid",
+			value:    "invalid",
 			expected: defaultCacheTTL,
 		},
 		{
 			name:     "negative duration rejected - falls back to default",
-			envValue: "-1h",
+			value:    "-1h",
 			expected: defaultCacheTTL,
 		},
 		{
 			name:     "negative seconds rejected - falls back to default",
-			envValue: "-3600",
+			value:    "-3600",
 			expected: defaultCacheTTL,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oldVal := os.Getenv("SLACK_MCP_CACHE_TTL")
-			defer os.Setenv("SLACK_MCP_CACHE_TTL", oldVal)
-
-			if tt.envValue == "" {
-				os.Unsetenv("SLACK_MCP_CACHE_TTL")
-			} else {
-				os.Setenv("SLACK_MCP_CACHE_TTL", tt.envValue)
-			}
-
-			result := getCacheTTL()
+			result := getCacheTTL(&config.Config{CacheTTL: tt.value})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -143,13 +137,51 @@ func TestCacheExpiry(t *testing.T) {
 		require.NoError(t, err)
 
 		cacheAge := time.Since(fileInfo.ModTime())
-		ttl := getCacheTTL() // default 1 hour
+		ttl := getCacheTTL(config.Default()) // default 1 hour
 
 		assert.True(t, cacheAge > ttl,
 			"cache from 3 days ago (age=%v) should exceed default TTL (%v)", cacheAge, ttl)
 	})
 }
 
+// TestCacheStore verifies that the caches round-trip through files by
+// default and through SLACK_MCP_STORE when it is set, with the time they
+// were written for the TTL check.
+func TestCacheStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "T123_users_cache.json")
+
+	t.Run("file", func(t *testing.T) {
+		ap := &ApiProvider{}
+		_, _, err := ap.readCache(ctx, path)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		require.NoError(t, ap.writeCache(ctx, path, []byte(`[]`)))
+		data, storedAt, err := ap.readCache(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, `[]`, string(data))
+		assert.WithinDuration(t, time.Now(), storedAt, time.Minute)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		st := store.NewMemory()
+		ap := &ApiProvider{store: st}
+		_, _, err := ap.readCache(ctx, path)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+
+		require.NoError(t, ap.writeCache(ctx, path, []byte(`[{"id":"U1"}]`)))
+		data, storedAt, err := ap.readCache(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, `[{"id":"U1"}]`, string(data))
+		assert.WithinDuration(t, time.Now(), storedAt, time.Minute)
+
+		e, ok, err := st.Get(ctx, "cache/T123_users_cache.json")
+		require.NoError(t, err)
+		require.True(t, ok, "the cache is keyed by its file name, which carries the team")
+		assert.Equal(t, data, e.Value)
+	})
+}
+
 // TestChannelCacheRoundTrip verifies that Channel structs survive JSON serialization.
 // This catches bugs in struct tags or field types that would corrupt cache data.
 func TestChannelCacheRoundTrip(t *testing.T) {
@@ -161,6 +193,7 @@ func TestChannelCacheRoundTrip(t *testing.T) {
 			Purpose:     "Company-wide announcements",
 			MemberCount: 100,
 			IsPrivate:   false,
+			IsMember:    true,
 		},
 		{
 			ID:        "D456",
@@ -192,6 +225,9 @@ func TestChannelCacheRoundTrip(t *testing.T) {
 	assert.Equal(t, "#general", loaded[0].Name)
 	assert.Equal(t, 100, loaded[0].MemberCount)
 	assert.False(t, loaded[0].IsPrivate)
+	assert.True(t, loaded[0].IsMember)
+	assert.True(t, hasMembership(loaded))
+	assert.False(t, hasMembership(loaded[1:]), "caches without joined channels are refetched")
 
 	// Verify IM channel
 	assert.Equal(t, "D456", loaded[1].ID)
@@ -256,12 +292,12 @@ func TestChannelIDPatterns(t *testing.T) {
 		channel string
 		needs   bool
 	}{
-		{"C1234567890", false},  // Standard channel ID
-		{"G1234567890", false},  // Private channel ID (legacy)
-		{"D1234567890", false},  // DM ID
-		{"#general", true},      // Channel name - needs lookup
-		{"@john.doe", true},     // User DM name - needs lookup
-		{"", false},             // Empty - no lookup
+		{"C1234567890", false}, // Standard channel ID
+		{"G1234567890", false}, // Private channel ID (legacy)
+		{"D1234567890", false}, // DM ID
+		{"#general", true},     // Channel name - needs lookup
+		{"@john.doe", true},    // User DM name - needs lookup
+		{"", false},            // Empty - no lookup
 	}
 
 	for _, tt := range tests {
@@ -324,59 +360,87 @@ func TestGetCacheDir(t *testing.T) {
 func TestGetMinRefreshInterval(t *testing.T) {
 	tests := []struct {
 		name     string
-		envValue string
+		value    string
 		expected time.Duration
 	}{
 		{
-			name:     "default when env not set",
-			envValue: "",
+			name:     "default when unset",
+			value:    "",
 			expected: defaultMinRefreshInterval,
 		},
 		{
 			name:     "valid duration",
-			envValue: "1m",
+			value:    "1m",
 			expected: 1 * time.Minute,
 		},
 		{
 			name:     "numeric seconds",
-			envValue: "60",
+			value:    "60",
 			expected: 60 * time.Second,
 		},
 		{
 			name:     "zero disables rate limiting",
-			envValue: "0",
+			value:    "0",
 			expected: 0,
 		},
 		{
 			name:     "invalid input falls back to default",
-			envValue: "invalid",
+			value:    "invalid",
 			expected: defaultMinRefreshInterval,
 		},
 		{
 			name:     "negative duration rejected",
-			envValue: "-30s",
+			value:    "-30s",
 			expected: defaultMinRefreshInterval,
 		},
 		{
 			name:     "negative seconds rejected",
-			envValue: "-60",
+			value:    "-60",
 			expected: defaultMinRefreshInterval,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oldVal := os.Getenv("SLACK_MCP_MIN_REFRESH_INTERVAL")
-			defer os.Setenv("SLACK_MCP_MIN_REFRESH_INTERVAL", oldVal)
-
-			if tt.envValue == "" {
-				os.Unsetenv("SLACK_MCP_MIN_REFRESH_INTERVAL")
-			} else {
-				os.Setenv("SLACK_MCP_MIN_REFRESH_INTERVAL", tt.envValue)
-			}
-
-			result := getMinRefreshInterval()
+			result := getMinRefreshInterval(&config.Config{MinRefreshInterval: tt.value})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+// TestMergeChannelsSnapshot verifies pages are merged into the published
+// snapshot while the channels cache is warming.
+func TestMergeChannelsSnapshot(t *testing.T) {
+	ap := &ApiProvider{}
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels:    map[string]Channel{"C1": {ID: "C1", Name: "#general"}},
+		ChannelsInv: map[string]string{"#general": "C1"},
+	})
+	before := ap.ProvideChannelsMaps()
+
+	ap.mergeChannelsSnapshot([]Channel{
+		{ID: "C2", Name: "#random"},
+		{ID: "C1", Name: "#general-renamed"},
+	})
+
+	after := ap.ProvideChannelsMaps()
+	require.Len(t, after.Channels, 2)
+	assert.Equal(t, "#general-renamed", after.Channels["C1"].Name)
+	assert.Equal(t, "C2", after.ChannelsInv["#random"])
+	assert.Len(t, before.Channels, 1, "previous snapshot must not be mutated")
+}
+
+func TestUpdateCachedChannel(t *testing.T) {
+	ap := &ApiProvider{}
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels:    map[string]Channel{"C1": {ID: "C1", Name: "#oncall", Topic: "alice", MemberCount: 12}},
+		ChannelsInv: map[string]string{"#oncall": "C1"},
+	})
+
+	ap.UpdateCachedChannel("C1", func(c *Channel) { c.Topic = "bob" })
+	ap.UpdateCachedChannel("C2", func(c *Channel) { c.Topic = "ignored" })
+
+	channels := ap.ProvideChannelsMaps().Channels
+	assert.Equal(t, Channel{ID: "C1", Name: "#oncall", Topic: "bob", MemberCount: 12}, channels["C1"])
+	assert.NotContains(t, channels, "C2")
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ChannelPolicy limits the conversations every tool may read from or write
+// to. Entries are channel IDs or name globs such as "#support-*"; DMs and
+// group DMs use their "@name" form.
+type ChannelPolicy struct {
+	allow []string
+	deny  []string
+}
+
+// ChannelPolicyError is returned when a tool targets a channel outside the
+// configured policy.
+type ChannelPolicyError struct {
+	ChannelID   string
+	ChannelName string
+	Rule        string
+}
+
+func (e *ChannelPolicyError) Error() string {
+	target := e.ChannelID
+	switch {
+	case e.ChannelName != "" && e.ChannelID == "":
+		target = e.ChannelName
+	case e.ChannelName != "":
+		target = e.ChannelName + " (" + e.ChannelID + ")"
+	}
+	return fmt.Sprintf("channel %s is not accessible: %s", target, e.Rule)
+}
+
+// NewChannelPolicy builds a policy from allowlist and denylist entries,
+// configured by SLACK_MCP_CHANNEL_ALLOWLIST and SLACK_MCP_CHANNEL_DENYLIST.
+// It returns nil when both are empty.
+func NewChannelPolicy(allow, deny []string) *ChannelPolicy {
+	p := &ChannelPolicy{
+		allow: normalizeChannelPatterns(allow),
+		deny:  normalizeChannelPatterns(deny),
+	}
+	if len(p.allow) == 0 && len(p.deny) == 0 {
+		return nil
+	}
+	return p
+}
+
+func normalizeChannelPatterns(items []string) []string {
+	var patterns []string
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !isChannelIDPattern(item) && !strings.HasPrefix(item, "#") && !strings.HasPrefix(item, "@") {
+			item = "#" + item
+		}
+		patterns = append(patterns, item)
+	}
+	return patterns
+}
+
+// isChannelIDPattern reports whether item looks like a conversation ID
+// (C…, G…, D…) rather than a channel name.
+func isChannelIDPattern(item string) bool {
+	if len(item) < 9 || !strings.ContainsRune("CGD", rune(item[0])) {
+		return false
+	}
+	for _, r := range item {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '*' && r != '?' {
+			return false
+		}
+	}
+	return true
+}
+
+func matchChannel(patterns []string, id, name string) (string, bool) {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, id); ok {
+			return p, true
+		}
+		if name != "" {
+			if ok, _ := path.Match(p, name); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Check returns a *ChannelPolicyError when the channel is denied, or not
+// allowed while an allowlist is set. A nil policy allows everything.
+func (p *ChannelPolicy) Check(id, name string) error {
+	if p == nil {
+		return nil
+	}
+	if pattern, ok := matchChannel(p.deny, id, name); ok {
+		return &ChannelPolicyError{ChannelID: id, ChannelName: name, Rule: "matches SLACK_MCP_CHANNEL_DENYLIST entry " + pattern}
+	}
+	if len(p.allow) > 0 {
+		if _, ok := matchChannel(p.allow, id, name); !ok {
+			return &ChannelPolicyError{ChannelID: id, ChannelName: name, Rule: "not in SLACK_MCP_CHANNEL_ALLOWLIST"}
+		}
+	}
+	return nil
+}
+
+// ChannelPolicy returns the channel policy, nil when none is configured.
+func (ap *ApiProvider) ChannelPolicy() *ChannelPolicy {
+	if ap == nil {
+		return nil
+	}
+	ap.mu.RLock()
+	defer ap.mu.RUnlock()
+	return ap.channelPolicy
+}
+
+// CheckChannel applies the channel policy to a conversation ID, resolving its
+// name from the channels cache so name globs can match.
+func (ap *ApiProvider) CheckChannel(id string) error {
+	policy := ap.ChannelPolicy()
+	if policy == nil {
+		return nil
+	}
+	name := ""
+	if cache := ap.ProvideChannelsMaps(); cache != nil {
+		if c, ok := cache.Channels[id]; ok {
+			name = c.Name
+		}
+	}
+	return policy.Check(id, name)
+}
+
+// ChannelAllowed is CheckChannel for filtering result sets.
+func (ap *ApiProvider) ChannelAllowed(id string) bool {
+	return ap.CheckChannel(id) == nil
+}
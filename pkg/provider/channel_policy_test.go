@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChannelPolicy(t *testing.T) {
+	assert.Nil(t, NewChannelPolicy(nil, []string{" "}))
+
+	p := NewChannelPolicy([]string{"support-*", " C0123456789 ", "@alice"}, []string{"#support-internal"})
+	require.NotNil(t, p)
+	assert.Equal(t, []string{"#support-*", "C0123456789", "@alice"}, p.allow)
+	assert.Equal(t, []string{"#support-internal"}, p.deny)
+}
+
+func TestChannelPolicyCheck(t *testing.T) {
+	p := &ChannelPolicy{
+		allow: []string{"#support-*", "C0123456789", "@alice"},
+		deny:  []string{"#support-internal", "C0DENIED01"},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		chName  string
+		allowed bool
+	}{
+		{"glob match", "C1111111111", "#support-eu", true},
+		{"id match", "C0123456789", "#random", true},
+		{"dm match", "D2222222222", "@alice", true},
+		{"outside allowlist", "C3333333333", "#general", false},
+		{"unknown name fails closed", "C4444444444", "", false},
+		{"denied by name", "C5555555555", "#support-internal", false},
+		{"denied by id", "C0DENIED01", "#support-x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Check(tt.id, tt.chName)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var policyErr *ChannelPolicyError
+			require.True(t, errors.As(err, &policyErr))
+			assert.Equal(t, tt.id, policyErr.ChannelID)
+		})
+	}
+
+	var nilPolicy *ChannelPolicy
+	assert.NoError(t, nilPolicy.Check("C3333333333", "#general"))
+}
+
+func TestChannelPolicyDenyOnly(t *testing.T) {
+	p := &ChannelPolicy{deny: []string{"#hr-*"}}
+	assert.NoError(t, p.Check("C1111111111", "#general"))
+	assert.NoError(t, p.Check("C2222222222", ""))
+	assert.Error(t, p.Check("C3333333333", "#hr-private"))
+}
+
+func TestIsChannelIDPattern(t *testing.T) {
+	assert.True(t, isChannelIDPattern("C0123456789"))
+	assert.True(t, isChannelIDPattern("G01234567*"))
+	assert.True(t, isChannelIDPattern("D0123456789"))
+	assert.False(t, isChannelIDPattern("general"))
+	assert.False(t, isChannelIDPattern("Customers"))
+	assert.False(t, isChannelIDPattern("C0123"))
+}
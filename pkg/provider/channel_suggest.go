@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxChannelSuggestions is the number of names offered when a channel
+	// does not resolve.
+	maxChannelSuggestions = 3
+	// minChannelSuggestionScore drops names too different to be useful.
+	minChannelSuggestionScore = 0.45
+)
+
+// ChannelNotFoundError is returned when a #channel or @user name is not in
+// the channels cache. Suggestions lists the closest known names.
+type ChannelNotFoundError struct {
+	Name string
+	// Detail explains why a retry did not help, e.g. a rate-limited refresh.
+	Detail      string
+	Suggestions []string
+}
+
+func (e *ChannelNotFoundError) Error() string {
+	msg := fmt.Sprintf("channel %q not found", e.Name)
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	if len(e.Suggestions) > 0 {
+		msg += "; did you mean " + strings.Join(e.Suggestions, ", ") + "?"
+	}
+	return msg
+}
+
+// ChannelNotFound returns a ChannelNotFoundError for name with the closest
+// cached names as suggestions, unless SLACK_MCP_CHANNEL_SUGGESTIONS is off.
+func (ap *ApiProvider) ChannelNotFound(name, detail string) *ChannelNotFoundError {
+	err := &ChannelNotFoundError{Name: name, Detail: detail}
+	if ap.Config().ChannelSuggestions != "off" {
+		err.Suggestions = ap.SuggestChannels(name, maxChannelSuggestions)
+	}
+	return err
+}
+
+// SuggestChannels returns up to n cached names of the same kind as name
+// (#channel or @user DM) that look like it, closest first. Channels hidden
+// by the channel policy are never suggested.
+func (ap *ApiProvider) SuggestChannels(name string, n int) []string {
+	return ap.rankChannelNames(name, n, minChannelSuggestionScore)
+}
+
+// ChannelShortlist is SuggestChannels without the similarity cut-off, for
+// callers that rerank the names themselves.
+func (ap *ApiProvider) ChannelShortlist(name string, n int) []string {
+	return ap.rankChannelNames(name, n, 0)
+}
+
+func (ap *ApiProvider) rankChannelNames(name string, n int, minScore float64) []string {
+	if len(name) < 2 || (name[0] != '#' && name[0] != '@') || n <= 0 {
+		return nil
+	}
+	sigil, query := name[:1], strings.ToLower(name[1:])
+
+	type match struct {
+		name  string
+		score float64
+	}
+	var matches []match
+	for candidate, id := range ap.ProvideChannelsMaps().ChannelsInv {
+		if !strings.HasPrefix(candidate, sigil) || !ap.ChannelAllowed(id) {
+			continue
+		}
+		if score := channelNameScore(query, strings.ToLower(candidate[1:])); score >= minScore {
+			matches = append(matches, match{candidate, score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	names := make([]string, 0, min(n, len(matches)))
+	for _, m := range matches[:min(n, len(matches))] {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// channelNameScore rates how close candidate is to query, from 0 upwards:
+// edit-distance similarity, plus bonuses when one contains the other and for
+// shared words, so "incidents" ranks "ops-incidents" above "accidents".
+func channelNameScore(query, candidate string) float64 {
+	if query == "" || candidate == "" {
+		return 0
+	}
+	longest := max(len([]rune(query)), len([]rune(candidate)))
+	score := 1 - float64(levenshtein(query, candidate))/float64(longest)
+
+	if strings.Contains(candidate, query) || strings.Contains(query, candidate) {
+		score += 0.5
+	}
+
+	split := func(r rune) bool { return r == '-' || r == '_' || r == '.' }
+	queryWords := strings.FieldsFunc(query, split)
+	candidateWords := strings.FieldsFunc(candidate, split)
+	shared := 0
+	for _, qw := range queryWords {
+		for _, cw := range candidateWords {
+			// Words sharing a stem count, e.g. "incident" and "incidents".
+			if qw == cw || (min(len(qw), len(cw)) >= 4 && (strings.HasPrefix(qw, cw) || strings.HasPrefix(cw, qw))) {
+				shared++
+				break
+			}
+		}
+	}
+	if len(queryWords) > 0 {
+		score += 0.3 * float64(shared) / float64(len(queryWords))
+	}
+	return score
+}
+
+// levenshtein returns the edit distance between a and b in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestChannels(t *testing.T) {
+	ap := &ApiProvider{}
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels: map[string]Channel{},
+		ChannelsInv: map[string]string{
+			"#ops-incidents":   "C1",
+			"#incident-review": "C2",
+			"#accidents":       "C3",
+			"#general":         "C4",
+			"#random":          "C5",
+			"@incidents-bot":   "D1",
+		},
+	})
+
+	assert.Equal(t, []string{"#ops-incidents", "#incident-review", "#accidents"}, ap.SuggestChannels("#incidents", 3))
+	assert.Equal(t, []string{"#general"}, ap.SuggestChannels("#genral", 3))
+	assert.Equal(t, []string{"@incidents-bot"}, ap.SuggestChannels("@incident-bot", 3), "DMs are only matched against DMs")
+	assert.Empty(t, ap.SuggestChannels("#zzzzzz", 3))
+	assert.Len(t, ap.ChannelShortlist("#zzzzzz", 10), 5)
+
+	err := ap.ChannelNotFound("#genral", "")
+	assert.EqualError(t, err, `channel "#genral" not found; did you mean #general?`)
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("general", "general"))
+	assert.Equal(t, 1, levenshtein("genral", "general"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 5, levenshtein("", "héllo"))
+}
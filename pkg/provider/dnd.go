@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	// dndTTL is how long a user's DND schedule is reused. Schedules rarely
+	// change, while dnd.teamInfo is a Tier 2 method.
+	dndTTL = 15 * time.Minute
+	// dndBatchSize is the number of users asked for per dnd.teamInfo call.
+	dndBatchSize = 50
+)
+
+type dndEntry struct {
+	status    slack.DNDStatus
+	fetchedAt time.Time
+}
+
+type dndCache struct {
+	mu      sync.Mutex
+	entries map[string]dndEntry
+}
+
+// UsersDND returns the Do Not Disturb settings of ids, calling dnd.teamInfo
+// for users without a fresh cached entry. Users Slack does not report on, or
+// whose lookup failed, are missing from the result.
+func (ap *ApiProvider) UsersDND(ctx context.Context, ids []string) map[string]slack.DNDStatus {
+	result := ap.CachedUsersDND(ids)
+
+	var missing []string
+	for _, id := range ids {
+		if _, ok := result[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	lim := limiter.Tier2.Limiter()
+	for start := 0; start < len(missing); start += dndBatchSize {
+		batch := missing[start:min(start+dndBatchSize, len(missing))]
+		if err := lim.Wait(ctx); err != nil {
+			break
+		}
+		statuses, err := ap.Slack().GetDNDTeamInfoContext(ctx, batch)
+		if err != nil {
+			ap.logger.Debug("dnd.teamInfo failed", zap.Int("count", len(batch)), zap.Error(err))
+			break
+		}
+
+		now := time.Now()
+		ap.dnd.mu.Lock()
+		if ap.dnd.entries == nil {
+			ap.dnd.entries = make(map[string]dndEntry)
+		}
+		for id, st := range statuses {
+			ap.dnd.entries[id] = dndEntry{status: st, fetchedAt: now}
+			result[id] = st
+		}
+		ap.dnd.mu.Unlock()
+	}
+	return result
+}
+
+// CachedUsersDND returns the fresh cached DND settings among ids without
+// calling Slack, for listings too large to look up.
+func (ap *ApiProvider) CachedUsersDND(ids []string) map[string]slack.DNDStatus {
+	result := make(map[string]slack.DNDStatus, len(ids))
+	ap.dnd.mu.Lock()
+	defer ap.dnd.mu.Unlock()
+	for _, id := range ids {
+		if e, ok := ap.dnd.entries[id]; ok && time.Since(e.fetchedAt) < dndTTL {
+			result[id] = e.status
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/rusq/slackdump/v3/auth"
+	"go.uber.org/zap"
+)
+
+// attachBot makes c send methods with bot instead of its own user token. The
+// bot must belong to the same team.
+func (c *MCPSlackClient) attachBot(bot *MCPSlackClient, methods []string) error {
+	if c.authResponse != nil && bot.authResponse != nil && c.authResponse.TeamID != bot.authResponse.TeamID {
+		return fmt.Errorf("bot token belongs to team %s, user token to %s", bot.authResponse.TeamID, c.authResponse.TeamID)
+	}
+	if len(methods) == 0 {
+		methods = config.DefaultBotTokenMethods
+	}
+	c.bot = bot
+	c.botMethods = make(map[string]bool, len(methods))
+	for _, m := range methods {
+		c.botMethods[m] = true
+	}
+	return nil
+}
+
+// route returns the client that serves the Slack API method.
+func (c *MCPSlackClient) route(method string) *MCPSlackClient {
+	if c.bot != nil && c.botMethods[method] {
+		return c.bot
+	}
+	return c
+}
+
+// HasBotToken reports whether a bot token serves some methods next to the
+// user token.
+func (c *MCPSlackClient) HasBotToken() bool {
+	return c.bot != nil
+}
+
+// MethodUserID returns the ID of the user the Slack API method acts as: the
+// bot's user when the method is sent with the bot token. Empty when unknown.
+func (c *MCPSlackClient) MethodUserID(method string) string {
+	if ar := c.route(method).authResponse; ar != nil {
+		return ar.UserID
+	}
+	return ""
+}
+
+// BotTokenMethods lists the methods sent with the bot token, sorted.
+func (c *MCPSlackClient) BotTokenMethods() []string {
+	methods := make([]string, 0, len(c.botMethods))
+	for m := range c.botMethods {
+		methods = append(methods, m)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// newClientForTokens authenticates the token New would pick and, when both a
+// user and a bot token are set, attaches the bot for cfg.BotTokenMethods.
+func newClientForTokens(cfg *config.Config, tokens slackTokens, logger *zap.Logger) (*MCPSlackClient, error) {
+	authProvider, err := tokens.auth()
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewMCPSlackClient(cfg, authProvider, logger)
+	if err != nil {
+		return nil, err
+	}
+	if tokens.xoxp == "" || tokens.xoxb == "" {
+		return client, nil
+	}
+	if err := client.attachBotToken(cfg, tokens.xoxb, logger); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *MCPSlackClient) attachBotToken(cfg *config.Config, token string, logger *zap.Logger) error {
+	botAuth, err := auth.NewValueAuth(token, "")
+	if err != nil {
+		return fmt.Errorf("bot token: %w", err)
+	}
+	bot, err := NewMCPSlackClient(cfg, botAuth, logger)
+	if err != nil {
+		return fmt.Errorf("authenticate bot token: %w", err)
+	}
+	return c.attachBot(bot, cfg.BotTokenMethods)
+}
+
+// grantedBotScopes adds the scopes of the attached bot to scopes, since
+// tools whose methods go to the bot can work with the bot's scopes.
+func (c *MCPSlackClient) grantedBotScopes(ctx context.Context, scopes []string) ([]string, error) {
+	if c.bot == nil || scopes == nil {
+		return scopes, nil
+	}
+	botScopes, err := c.bot.GrantedScopesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range botScopes {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
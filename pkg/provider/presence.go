@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"go.uber.org/zap"
+)
+
+// presenceTTL is how long a user's presence is reused. Presence changes
+// within minutes, while users.getPresence is asked one user at a time.
+const presenceTTL = time.Minute
+
+type presenceEntry struct {
+	presence  string
+	fetchedAt time.Time
+}
+
+type presenceCache struct {
+	mu      sync.Mutex
+	entries map[string]presenceEntry
+}
+
+// UsersPresence returns the presence, "active" or "away", of up to max of
+// ids, calling users.getPresence for users without a fresh cached entry.
+// Users beyond max, and users whose lookup failed, are missing from the
+// result.
+func (ap *ApiProvider) UsersPresence(ctx context.Context, ids []string, max int) map[string]string {
+	result := make(map[string]string, len(ids))
+	var missing []string
+	ap.presence.mu.Lock()
+	for _, id := range ids {
+		if e, ok := ap.presence.entries[id]; ok && time.Since(e.fetchedAt) < presenceTTL {
+			result[id] = e.presence
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	ap.presence.mu.Unlock()
+
+	lim := limiter.Tier3.Limiter()
+	for i, id := range missing {
+		if i >= max {
+			break
+		}
+		if err := lim.Wait(ctx); err != nil {
+			break
+		}
+		p, err := ap.Slack().GetUserPresenceContext(ctx, id)
+		if err != nil {
+			ap.logger.Debug("users.getPresence failed", zap.String("user", id), zap.Error(err))
+			continue
+		}
+
+		ap.presence.mu.Lock()
+		if ap.presence.entries == nil {
+			ap.presence.entries = make(map[string]presenceEntry)
+		}
+		ap.presence.entries[id] = presenceEntry{presence: p.Presence, fetchedAt: time.Now()}
+		ap.presence.mu.Unlock()
+		result[id] = p.Presence
+	}
+	return result
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/limiter"
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// UpdatedUserProfile is the profile users.profile.set returns. Unlike
+// slack.UserProfile it keeps the pronouns.
+type UpdatedUserProfile struct {
+	slack.UserProfile
+	Pronouns string `json:"pronouns"`
+}
+
+// SetUserProfileContext updates the authenticated user's profile. profile
+// holds the fields to change by their API name, such as "title", and custom
+// fields under "fields" keyed by field ID.
+func (c *MCPSlackClient) SetUserProfileContext(ctx context.Context, profile map[string]any) (*UpdatedUserProfile, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		slack.SlackResponse
+		Profile UpdatedUserProfile `json:"profile"`
+	}
+	if err := c.callAPI(ctx, "users.profile.set", url.Values{"profile": {string(data)}}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Profile, nil
+}
+
+// profileFieldsEnabled reports whether SLACK_MCP_USERS_PROFILE_FIELDS asks
+// for custom profile field values in the users cache.
+func (ap *ApiProvider) profileFieldsEnabled() bool {
+	return ap.config != nil && ap.config.UsersProfileFields == "true"
+}
+
+// hasProfileFields reports whether any user has custom profile field
+// values. Caches written before SLACK_MCP_USERS_PROFILE_FIELDS was set have
+// none, so they are refetched.
+func hasProfileFields(users []slack.User) bool {
+	for _, u := range users {
+		if u.Profile.Fields.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchProfileFields fills in the custom profile fields of the active
+// members of users, which users.list leaves out, with one users.profile.get
+// call each. Members whose profile cannot be read keep none. Nothing is
+// fetched when the workspace defines no custom fields.
+func (ap *ApiProvider) fetchProfileFields(ctx context.Context, users []slack.User) error {
+	schema, err := ap.Slack().GetTeamProfileContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(schema.Fields) == 0 {
+		return nil
+	}
+
+	lim := limiter.Tier4.Limiter()
+	var fetched, failed int
+	for i := range users {
+		u := &users[i]
+		if u.Deleted || u.IsBot || u.ID == "USLACKBOT" {
+			continue
+		}
+		for attempt := 0; ; attempt++ {
+			if err := lim.Wait(ctx); err != nil {
+				return err
+			}
+			profile, err := ap.Slack().GetUserProfileContext(ctx, &slack.GetUserProfileParameters{UserID: u.ID})
+			var rlErr *slack.RateLimitedError
+			if errors.As(err, &rlErr) && attempt < channelsPageMaxRetries {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(rlErr.RetryAfter):
+				}
+				continue
+			}
+			if err != nil {
+				failed++
+				ap.logger.Debug("Failed to fetch user profile fields", zap.String("user", u.ID), zap.Error(err))
+			} else {
+				u.Profile.Fields = profile.Fields
+				fetched++
+			}
+			break
+		}
+	}
+	ap.logger.Info("Fetched custom profile fields",
+		zap.Int("users", fetched),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"go.uber.org/zap"
+)
+
+// ApplyConfig switches the provider to cfg at runtime. When the Slack tokens
+// changed, the new ones are authenticated first and the current client is
+// kept if that fails. Tokens for another team or user are refused because the
+// caches belong to the current identity; switching those needs a restart.
+func (ap *ApiProvider) ApplyConfig(cfg *config.Config) error {
+	var (
+		client *MCPSlackClient
+		tokens slackTokens
+	)
+	if tokensChanged(ap.Config(), cfg) {
+		var err error
+		tokens, err = resolveTokens(context.Background(), cfg)
+		if err != nil {
+			return err
+		}
+		client, err = newClientForTokens(cfg, tokens, ap.logger)
+		if err != nil {
+			return fmt.Errorf("authenticate reloaded tokens: %w", err)
+		}
+		if err := ap.checkSameIdentity(client); err != nil {
+			return err
+		}
+	}
+
+	ap.mu.Lock()
+	ap.config = cfg
+	ap.channelPolicy = NewChannelPolicy(cfg.ChannelAllowlist, cfg.ChannelDenylist)
+	if client != nil {
+		ap.client = client
+		ap.tokens = tokens
+	}
+	ap.mu.Unlock()
+
+	if client != nil {
+		ap.logger.Info("Slack tokens reloaded", zap.String("context", "console"))
+	}
+	return nil
+}
+
+func tokensChanged(a, b *config.Config) bool {
+	return a.XOXPToken != b.XOXPToken || a.XOXBToken != b.XOXBToken ||
+		a.XOXCToken != b.XOXCToken || a.XOXDToken != b.XOXDToken || a.AuditToken != b.AuditToken ||
+		a.AdminToken != b.AdminToken ||
+		!slices.Equal(a.BotTokenMethods, b.BotTokenMethods)
+}
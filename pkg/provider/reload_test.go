@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConfigSwapsPolicy(t *testing.T) {
+	ap := &ApiProvider{config: config.Default()}
+	assert.Nil(t, ap.ChannelPolicy())
+
+	cfg := config.Default()
+	cfg.ChannelAllowlist = []string{"#support-*"}
+	require.NoError(t, ap.ApplyConfig(cfg))
+
+	assert.Same(t, cfg, ap.Config())
+	assert.Error(t, ap.ChannelPolicy().Check("C1111111111", "#general"))
+	assert.Nil(t, ap.Slack(), "client is kept when tokens are unchanged")
+}
+
+func TestApplyConfigRequiresToken(t *testing.T) {
+	ap := &ApiProvider{config: &config.Config{XOXPToken: "xoxp-old"}}
+
+	err := ap.ApplyConfig(config.Default())
+	assert.ErrorContains(t, err, "no usable Slack token")
+	assert.Equal(t, "xoxp-old", ap.Config().XOXPToken, "config is kept on failure")
+}
@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// awsBackend reads "aws-sm:<secret-id>[#<field>]" from AWS Secrets Manager.
+// The secret id is a name or ARN; with a field, the secret string is parsed
+// as JSON and that key is returned. The region comes from the ARN, or else
+// from the SDK's default configuration (AWS_REGION, AWS_DEFAULT_REGION or
+// the profile), as do the credentials and AWS_ENDPOINT_URL overrides.
+type awsBackend struct {
+	mu sync.Mutex
+	// clients are keyed by the region of the secret ARN, "" for the
+	// default region.
+	clients map[string]*secretsmanager.Client
+}
+
+func newAWSBackend() *awsBackend {
+	return &awsBackend{clients: make(map[string]*secretsmanager.Client)}
+}
+
+func (b *awsBackend) Fetch(ctx context.Context, ref string) (Secret, error) {
+	secretID, field := splitField(ref)
+	if secretID == "" {
+		return Secret{}, errors.New("want aws-sm:<secret-id>[#<field>]")
+	}
+	client, err := b.client(ctx, arnRegion(secretID))
+	if err != nil {
+		return Secret{}, err
+	}
+
+	out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return Secret{}, err
+	}
+	value := aws.ToString(out.SecretString)
+	if value == "" {
+		return Secret{}, errors.New("secret has no string value")
+	}
+	if field == "" {
+		return Secret{Value: value}, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return Secret{}, fmt.Errorf("secret is not a JSON object, cannot read field %q", field)
+	}
+	value, ok := fields[field].(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("field %q not found", field)
+	}
+	return Secret{Value: value}, nil
+}
+
+// client returns the Secrets Manager client of region, loading the default
+// configuration on first use. The SDK caches and refreshes the credentials.
+func (b *awsBackend) client(ctx context.Context, region string) (*secretsmanager.Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.clients[region]; ok {
+		return c, nil
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		return nil, errors.New("no region: set AWS_REGION or use the secret ARN")
+	}
+	c := secretsmanager.NewFromConfig(cfg)
+	b.clients[region] = c
+	return c, nil
+}
+
+// arnRegion returns the region of a secret ARN, or "" for a secret name.
+func arnRegion(secretID string) string {
+	// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if parts := strings.Split(secretID, ":"); len(parts) > 3 && parts[0] == "arn" {
+		return parts[3]
+	}
+	return ""
+}
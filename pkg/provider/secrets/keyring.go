@@ -0,0 +1,55 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// runCommand runs a keyring CLI and returns its standard output.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// keyringBackend reads "keyring:<service>/<account>" from the OS keyring: the
+// login keychain on macOS (security) and the Secret Service on Linux
+// (secret-tool, from libsecret).
+type keyringBackend struct{}
+
+func (keyringBackend) Fetch(ctx context.Context, ref string) (Secret, error) {
+	service, account, ok := strings.Cut(ref, "/")
+	if !ok || service == "" || account == "" {
+		return Secret{}, errors.New("want keyring:<service>/<account>")
+	}
+
+	var (
+		out []byte
+		err error
+	)
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = runCommand(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		out, err = runCommand(ctx, "secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return Secret{}, fmt.Errorf("the OS keyring is not supported on %s", runtime.GOOS)
+	}
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: string(out)}, nil
+}
@@ -0,0 +1,100 @@
+// Package secrets resolves Slack tokens kept in a secret store instead of the
+// environment. A token setting holding a reference such as
+// "vault:secret/data/slack#xoxp" is replaced by the secret it points to;
+// any other value is used as is.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret is a value read from a backend.
+type Secret struct {
+	Value string
+	// ExpiresAt is when the backend expects the value to stop working, e.g.
+	// the end of a Vault lease. Zero when unknown.
+	ExpiresAt time.Time
+}
+
+// Backend reads secrets for the references of one scheme. ref is the part of
+// the reference after "<scheme>:".
+type Backend interface {
+	Fetch(ctx context.Context, ref string) (Secret, error)
+}
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]Backend{
+		"file":    fileBackend{},
+		"keyring": keyringBackend{},
+		"vault":   newVaultBackend(),
+		"aws-sm":  newAWSBackend(),
+	}
+)
+
+// Register adds or replaces the backend for scheme.
+func Register(scheme string, b Backend) {
+	mu.Lock()
+	defer mu.Unlock()
+	backends[scheme] = b
+}
+
+func lookup(v string) (Backend, string, string, bool) {
+	scheme, ref, ok := strings.Cut(v, ":")
+	if !ok {
+		return nil, "", "", false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	b, ok := backends[scheme]
+	return b, scheme, ref, ok
+}
+
+// IsReference reports whether v names a secret of a registered backend.
+func IsReference(v string) bool {
+	_, _, _, ok := lookup(v)
+	return ok
+}
+
+// Resolve returns the secret v refers to, or v itself when it is not a
+// reference.
+func Resolve(ctx context.Context, v string) (Secret, error) {
+	b, scheme, ref, ok := lookup(v)
+	if !ok {
+		return Secret{Value: v}, nil
+	}
+
+	s, err := b.Fetch(ctx, ref)
+	if err != nil {
+		return Secret{}, fmt.Errorf("%s secret %q: %w", scheme, ref, err)
+	}
+	s.Value = strings.TrimSpace(s.Value)
+	if s.Value == "" {
+		return Secret{}, fmt.Errorf("%s secret %q is empty", scheme, ref)
+	}
+	return s, nil
+}
+
+// splitField splits "path#field" into its path and optional field.
+func splitField(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// fileBackend reads "file:/path/to/secret", e.g. a mounted Kubernetes secret.
+type fileBackend struct{}
+
+func (fileBackend) Fetch(_ context.Context, ref string) (Secret, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: string(data)}, nil
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// vaultBackend reads "vault:<path>#<field>" from HashiCorp Vault, e.g.
+// "vault:secret/data/slack#xoxp_token" for a KV v2 engine mounted at secret/.
+// It uses VAULT_ADDR, VAULT_NAMESPACE and the token in VAULT_TOKEN or
+// ~/.vault-token, as written by vault login and Vault Agent.
+type vaultBackend struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func newVaultBackend() *vaultBackend {
+	return &vaultBackend{
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+func (b *vaultBackend) Fetch(ctx context.Context, ref string) (Secret, error) {
+	path, field := splitField(ref)
+	if path == "" || field == "" {
+		return Secret{}, errors.New("want vault:<path>#<field>")
+	}
+	addr := strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return Secret{}, errors.New("VAULT_ADDR is not set")
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return Secret{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return Secret{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		LeaseDuration int            `json:"lease_duration"`
+		Data          map[string]any `json:"data"`
+		Errors        []string       `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return Secret{}, fmt.Errorf("decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(body.Errors) > 0 {
+			return Secret{}, fmt.Errorf("vault returned %s: %s", resp.Status, strings.Join(body.Errors, "; "))
+		}
+		return Secret{}, fmt.Errorf("vault returned %s", resp.Status)
+	}
+
+	data := body.Data
+	// KV v2 nests the secret under data.data, next to its metadata.
+	if inner, ok := data["data"].(map[string]any); ok {
+		data = inner
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("field %q not found", field)
+	}
+
+	s := Secret{Value: value}
+	if body.LeaseDuration > 0 {
+		s.ExpiresAt = b.now().Add(time.Duration(body.LeaseDuration) * time.Second)
+	}
+	return s, nil
+}
+
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.New("VAULT_TOKEN is not set")
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", errors.New("VAULT_TOKEN is not set and ~/.vault-token is not readable")
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+)
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]store.Store)
+)
+
+// sharedStore opens the store SLACK_MCP_STORE names once per process, so
+// the per-user providers of multi-user mode share one connection. Without
+// SLACK_MCP_STORE it returns nil and the caches stay in their files.
+func sharedStore(cfg *config.Config) (store.Store, error) {
+	if cfg == nil || cfg.Store == "" {
+		return nil, nil
+	}
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if st, ok := stores[cfg.Store]; ok {
+		return st, nil
+	}
+	st, err := store.Open(cfg.Store)
+	if err != nil {
+		return nil, err
+	}
+	stores[cfg.Store] = st
+	return st, nil
+}
+
+// Store returns the store shared state is kept in, nil when SLACK_MCP_STORE
+// is not set.
+func (ap *ApiProvider) Store() store.Store {
+	if ap == nil {
+		return nil
+	}
+	return ap.store
+}
+
+// cacheStoreKey is the store key of the cache file path: its name, which
+// carries the team ID and, in multi-user mode, the user ID.
+func cacheStoreKey(path string) string {
+	return "cache/" + filepath.Base(path)
+}
+
+// readCache returns the users or channels cache at path and when it was
+// written, from the store when one is configured. A missing cache returns
+// an os.ErrNotExist error.
+func (ap *ApiProvider) readCache(ctx context.Context, path string) ([]byte, time.Time, error) {
+	if ap.store == nil {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return data, info.ModTime(), nil
+	}
+	e, ok, err := ap.store.Get(ctx, cacheStoreKey(path))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !ok {
+		return nil, time.Time{}, os.ErrNotExist
+	}
+	return e.Value, e.StoredAt, nil
+}
+
+// writeCache saves the users or channels cache at path. Expiry is left to
+// the readers' SLACK_MCP_CACHE_TTL, so an expired cache still tells the
+// next refresh how many entries to expect.
+func (ap *ApiProvider) writeCache(ctx context.Context, path string, data []byte) error {
+	if ap.store == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	return ap.store.Put(ctx, cacheStoreKey(path), data, 0)
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// TeamInfo is the team.info response. Unlike slack.TeamInfo it keeps the
+// workspace URL and the Enterprise Grid organization.
+type TeamInfo struct {
+	ID               string         `json:"id"`
+	Name             string         `json:"name"`
+	Domain           string         `json:"domain"`
+	EmailDomain      string         `json:"email_domain"`
+	URL              string         `json:"url"`
+	Icon             map[string]any `json:"icon"`
+	IsVerified       bool           `json:"is_verified"`
+	EnterpriseID     string         `json:"enterprise_id"`
+	EnterpriseName   string         `json:"enterprise_name"`
+	EnterpriseDomain string         `json:"enterprise_domain"`
+}
+
+// GetTeamInfoContext returns the workspace of the token.
+func (c *MCPSlackClient) GetTeamInfoContext(ctx context.Context) (*TeamInfo, error) {
+	var resp struct {
+		slack.SlackResponse
+		Team TeamInfo `json:"team"`
+	}
+	if err := c.callAPI(ctx, "team.info", url.Values{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Team, nil
+}
+
+// GetTeamProfileContext returns the custom profile fields of the workspace.
+func (c *MCPSlackClient) GetTeamProfileContext(ctx context.Context) (*slack.TeamProfile, error) {
+	return c.slackClient.GetTeamProfileContext(ctx)
+}
+
+// GetTeamDefaultChannelsContext returns the IDs of the channels new members
+// join automatically. Slack only exposes them to Enterprise Grid admin tokens
+// (admin.teams:read), so callers should treat an error as "unknown".
+func (c *MCPSlackClient) GetTeamDefaultChannelsContext(ctx context.Context, teamID string) ([]string, error) {
+	var resp struct {
+		slack.SlackResponse
+		Team struct {
+			DefaultChannels []string `json:"default_channels"`
+		} `json:"team"`
+	}
+	if err := c.callAPI(ctx, "admin.teams.settings.info", url.Values{"team_id": {teamID}}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Team.DefaultChannels, nil
+}
+
+// callAPI posts form to a Web API method slack-go does not cover, or covers
+// without fields we need, and decodes the response into out, which must embed
+// slack.SlackResponse.
+func (c *MCPSlackClient) callAPI(ctx context.Context, method string, form url.Values, out interface{ Err() error }) error {
+	form.Set("token", c.authProvider.SlackToken())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.teamEndpoint+"api/"+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: slack returned %s", method, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return out.Err()
+}
@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+// Token health states.
+const (
+	TokenHealthUnknown  = "unknown"
+	TokenHealthOK       = "ok"
+	TokenHealthExpiring = "expiring"
+	TokenHealthFailing  = "failing"
+)
+
+// tokenAuthErrors are the auth.test errors meaning the token itself no
+// longer works, as opposed to Slack being unreachable.
+var tokenAuthErrors = []string{"invalid_auth", "not_authed", "token_expired", "token_revoked", "account_inactive"}
+
+// TokenHealth is the outcome of the latest token check, reported by the
+// capabilities resource.
+type TokenHealth struct {
+	// State is unknown until the first check succeeds in reaching Slack,
+	// then ok, expiring when SLACK_MCP_TOKEN_EXPIRES_AT is within the
+	// warning window, or failing when auth.test rejects the token.
+	State     string     `json:"state"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+type tokenHealthState struct {
+	mu sync.Mutex
+	h  TokenHealth
+}
+
+// set stores h and returns the previous health.
+func (s *tokenHealthState) set(h TokenHealth) TokenHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.h
+	s.h = h
+	return prev
+}
+
+func (s *tokenHealthState) snapshot() TokenHealth {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.h.State == "" {
+		return TokenHealth{State: TokenHealthUnknown}
+	}
+	return s.h
+}
+
+// TokenHealth returns the outcome of the latest token check.
+func (ap *ApiProvider) TokenHealth() TokenHealth {
+	return ap.tokenHealth.snapshot()
+}
+
+// WatchTokenHealth calls auth.test every SLACK_MCP_TOKEN_CHECK_INTERVAL and
+// warns, in the log and by DM to SLACK_MCP_TOKEN_ALERT_USER, when the token
+// stops working or SLACK_MCP_TOKEN_EXPIRES_AT comes within
+// SLACK_MCP_TOKEN_EXPIRY_WARNING, so an expired session is noticed before
+// tools start failing. It returns when ctx ends.
+func (ap *ApiProvider) WatchTokenHealth(ctx context.Context) {
+	for {
+		interval := ap.Config().TokenCheckDuration()
+		if interval > 0 {
+			ap.checkTokenHealth(ctx, time.Now())
+		} else {
+			// Turned off; look again later in case a reload turns it on.
+			interval = time.Hour
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (ap *ApiProvider) checkTokenHealth(ctx context.Context, now time.Time) {
+	cfg := ap.Config()
+	_, err := ap.Slack().AuthTestContext(ctx)
+	h := evaluateTokenHealth(now, cfg.TokenExpiryTime(), cfg.TokenWarningDuration(), err)
+	prev := ap.tokenHealth.set(h)
+
+	fields := []zap.Field{zap.String("context", "console"), zap.String("state", h.State)}
+	if h.ExpiresAt != nil {
+		fields = append(fields, zap.Time("expires_at", *h.ExpiresAt))
+	}
+	switch h.State {
+	case TokenHealthOK:
+		if prev.State == TokenHealthExpiring || prev.State == TokenHealthFailing {
+			ap.logger.Info("Slack token works again", fields...)
+		}
+		return
+	case TokenHealthUnknown:
+		ap.logger.Warn("Token check could not reach Slack", append(fields, zap.Error(err))...)
+		return
+	case TokenHealthExpiring:
+		ap.logger.Warn("Slack token expires soon, replace it before tools start failing", fields...)
+	case TokenHealthFailing:
+		ap.logger.Error("Slack token no longer works, tools will fail until it is replaced", append(fields, zap.Error(err))...)
+	}
+
+	if h.State != prev.State {
+		ap.sendTokenAlert(ctx, cfg.TokenAlertUser, tokenAlertText(h, now))
+	}
+}
+
+// evaluateTokenHealth classifies the result of auth.test at now, given the
+// configured expiry, zero when unknown, and how long before it to warn.
+func evaluateTokenHealth(now, expiresAt time.Time, warning time.Duration, authErr error) TokenHealth {
+	h := TokenHealth{State: TokenHealthOK, CheckedAt: &now}
+	if !expiresAt.IsZero() {
+		h.ExpiresAt = &expiresAt
+	}
+	switch {
+	case authErr != nil && isTokenAuthError(authErr):
+		h.State, h.Error = TokenHealthFailing, authErr.Error()
+	case authErr != nil:
+		h.State, h.Error = TokenHealthUnknown, authErr.Error()
+	case !expiresAt.IsZero() && expiresAt.Sub(now) <= warning:
+		h.State = TokenHealthExpiring
+	}
+	return h
+}
+
+func isTokenAuthError(err error) bool {
+	msg := err.Error()
+	for _, e := range tokenAuthErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenAlertText(h TokenHealth, now time.Time) string {
+	if h.State == TokenHealthFailing {
+		return fmt.Sprintf(":warning: The Slack MCP server's token no longer works (%s). Tools will fail until it is replaced.", h.Error)
+	}
+	left := h.ExpiresAt.Sub(now).Round(time.Hour)
+	if left <= 0 {
+		return fmt.Sprintf(":warning: The Slack MCP server's token was due to expire at %s. Replace it before tools start failing.", h.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf(":hourglass: The Slack MCP server's token expires at %s, in about %s. Replace it before tools start failing.", h.ExpiresAt.UTC().Format(time.RFC3339), left)
+}
+
+// sendTokenAlert DMs text to user. A token that no longer works usually
+// cannot send the DM either, unless a bot token posts messages, so failures
+// are only logged.
+func (ap *ApiProvider) sendTokenAlert(ctx context.Context, user, text string) {
+	if user == "" {
+		return
+	}
+	client := ap.Slack()
+	channel, _, _, err := client.OpenConversationContext(ctx, &slack.OpenConversationParameters{Users: []string{user}})
+	if err == nil {
+		_, _, err = client.PostMessageContext(ctx, channel.ID, slack.MsgOptionText(text, false))
+	}
+	if err != nil {
+		ap.logger.Warn("Failed to send token alert",
+			zap.String("context", "console"),
+			zap.String("user", user),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateTokenHealth(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	warning := 72 * time.Hour
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		err       error
+		want      string
+	}{
+		{"no expiry known", time.Time{}, nil, TokenHealthOK},
+		{"expiry far off", now.Add(30 * 24 * time.Hour), nil, TokenHealthOK},
+		{"expiry within warning", now.Add(48 * time.Hour), nil, TokenHealthExpiring},
+		{"expiry passed but token works", now.Add(-time.Hour), nil, TokenHealthExpiring},
+		{"revoked", time.Time{}, slack.SlackErrorResponse{Err: "token_revoked"}, TokenHealthFailing},
+		{"expired session", now.Add(48 * time.Hour), errors.New("invalid_auth"), TokenHealthFailing},
+		{"Slack unreachable", time.Time{}, errors.New("dial tcp: i/o timeout"), TokenHealthUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := evaluateTokenHealth(now, tt.expiresAt, warning, tt.err)
+			assert.Equal(t, tt.want, h.State)
+			assert.Equal(t, now, *h.CheckedAt)
+			assert.Equal(t, !tt.expiresAt.IsZero(), h.ExpiresAt != nil)
+			assert.Equal(t, tt.err != nil, h.Error != "")
+		})
+	}
+}
+
+func TestTokenAlertText(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	expires := now.Add(49 * time.Hour)
+
+	text := tokenAlertText(evaluateTokenHealth(now, expires, 72*time.Hour, nil), now)
+	assert.Contains(t, text, "expires at 2026-03-04T11:00:00Z, in about 49h0m0s")
+
+	text = tokenAlertText(evaluateTokenHealth(now, time.Time{}, 72*time.Hour, errors.New("token_expired")), now)
+	assert.Contains(t, text, "no longer works (token_expired)")
+}
+
+func TestTokenHealthSnapshot(t *testing.T) {
+	ap := &ApiProvider{}
+	assert.Equal(t, TokenHealthUnknown, ap.TokenHealth().State, "before the first check")
+
+	now := time.Now()
+	prev := ap.tokenHealth.set(evaluateTokenHealth(now, time.Time{}, time.Hour, nil))
+	assert.Empty(t, prev.State)
+	assert.Equal(t, TokenHealthOK, ap.TokenHealth().State)
+}
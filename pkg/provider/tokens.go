@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider/secrets"
+	"github.com/rusq/slackdump/v3/auth"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSecretsRefreshInterval = 15 * time.Minute
+	// secretsExpiryMargin is how long before a lease ends tokens are re-read.
+	secretsExpiryMargin = time.Minute
+	// minSecretsRefreshWait keeps failing or already expired secrets from
+	// being re-read in a tight loop.
+	minSecretsRefreshWait = 30 * time.Second
+)
+
+// slackTokens are the tokens of a config with secret references resolved.
+type slackTokens struct {
+	xoxp, xoxb, xoxc, xoxd string
+	// audit is SLACK_MCP_AUDIT_TOKEN, only used for the audit logs API.
+	audit string
+	// admin is SLACK_MCP_ADMIN_TOKEN, only used for admin.users methods.
+	admin string
+	// expiresAt is the earliest expiry reported by a secret backend.
+	expiresAt time.Time
+}
+
+// resolveTokens reads the tokens in cfg, fetching those given as secret
+// references such as "vault:secret/data/slack#xoxp".
+func resolveTokens(ctx context.Context, cfg *config.Config) (slackTokens, error) {
+	var t slackTokens
+	for _, f := range []struct {
+		name string
+		ref  string
+		dst  *string
+	}{
+		{"SLACK_MCP_XOXP_TOKEN", cfg.XOXPToken, &t.xoxp},
+		{"SLACK_MCP_XOXB_TOKEN", cfg.XOXBToken, &t.xoxb},
+		{"SLACK_MCP_XOXC_TOKEN", cfg.XOXCToken, &t.xoxc},
+		{"SLACK_MCP_XOXD_TOKEN", cfg.XOXDToken, &t.xoxd},
+		{"SLACK_MCP_AUDIT_TOKEN", cfg.AuditToken, &t.audit},
+		{"SLACK_MCP_ADMIN_TOKEN", cfg.AdminToken, &t.admin},
+	} {
+		if f.ref == "" {
+			continue
+		}
+		s, err := secrets.Resolve(ctx, f.ref)
+		if err != nil {
+			return slackTokens{}, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = s.Value
+		if !s.ExpiresAt.IsZero() && (t.expiresAt.IsZero() || s.ExpiresAt.Before(t.expiresAt)) {
+			t.expiresAt = s.ExpiresAt
+		}
+	}
+	return t, nil
+}
+
+// usesSecretBackends reports whether any token in cfg is a secret reference.
+func usesSecretBackends(cfg *config.Config) bool {
+	for _, v := range []string{cfg.XOXPToken, cfg.XOXBToken, cfg.XOXCToken, cfg.XOXDToken, cfg.AuditToken, cfg.AdminToken} {
+		if secrets.IsReference(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t slackTokens) equal(o slackTokens) bool {
+	return t.xoxp == o.xoxp && t.xoxb == o.xoxb && t.xoxc == o.xoxc && t.xoxd == o.xoxd && t.audit == o.audit && t.admin == o.admin
+}
+
+// auth picks the token New would use, returning an error instead of exiting
+// when none is usable.
+func (t slackTokens) auth() (auth.ValueAuth, error) {
+	switch {
+	case t.xoxp != "":
+		return auth.NewValueAuth(t.xoxp, "")
+	case t.xoxb != "":
+		return auth.NewValueAuth(t.xoxb, "")
+	case t.xoxc != "" && t.xoxd != "":
+		return auth.NewValueAuth(t.xoxc, t.xoxd)
+	}
+	return auth.ValueAuth{}, errors.New("no usable Slack token: set SLACK_MCP_XOXP_TOKEN, SLACK_MCP_XOXB_TOKEN, or both SLACK_MCP_XOXC_TOKEN and SLACK_MCP_XOXD_TOKEN")
+}
+
+// WatchSecrets re-reads tokens loaded from secret backends shortly before
+// their lease ends, and at least every SLACK_MCP_SECRETS_REFRESH_INTERVAL,
+// switching to a new client when they changed. It returns at once when no
+// token is a secret reference, otherwise when ctx ends.
+func (ap *ApiProvider) WatchSecrets(ctx context.Context) {
+	if !usesSecretBackends(ap.Config()) {
+		return
+	}
+	interval := secretsRefreshInterval(ap.Config(), ap.logger)
+
+	for {
+		ap.mu.RLock()
+		expiresAt := ap.tokens.expiresAt
+		ap.mu.RUnlock()
+
+		timer := time.NewTimer(nextSecretsRefresh(time.Now(), expiresAt, interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := ap.refreshSecrets(ctx); err != nil {
+			ap.logger.Warn("Failed to refresh Slack tokens from secret backend",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func (ap *ApiProvider) refreshSecrets(ctx context.Context) error {
+	tokens, err := resolveTokens(ctx, ap.Config())
+	if err != nil {
+		return err
+	}
+
+	ap.mu.Lock()
+	if tokens.equal(ap.tokens) {
+		ap.tokens.expiresAt = tokens.expiresAt
+		ap.mu.Unlock()
+		return nil
+	}
+	ap.mu.Unlock()
+
+	client, err := newClientForTokens(ap.Config(), tokens, ap.logger)
+	if err != nil {
+		return fmt.Errorf("authenticate refreshed tokens: %w", err)
+	}
+	if err := ap.checkSameIdentity(client); err != nil {
+		return err
+	}
+
+	ap.mu.Lock()
+	ap.client = client
+	ap.tokens = tokens
+	ap.mu.Unlock()
+
+	ap.logger.Info("Slack tokens refreshed from secret backend", zap.String("context", "console"))
+	return nil
+}
+
+// checkSameIdentity refuses a client for another team or user than the
+// current one, because the caches belong to the current identity.
+func (ap *ApiProvider) checkSameIdentity(client *MCPSlackClient) error {
+	current, ok := ap.Slack().(*MCPSlackClient)
+	if !ok || current == nil || current.authResponse == nil {
+		return nil
+	}
+	was, now := current.authResponse, client.authResponse
+	if was.TeamID != now.TeamID || was.UserID != now.UserID {
+		return fmt.Errorf("new tokens belong to %s/%s instead of %s/%s; restart to switch workspace or user",
+			now.TeamID, now.UserID, was.TeamID, was.UserID)
+	}
+	return nil
+}
+
+// nextSecretsRefresh returns how long to wait before re-reading secrets.
+func nextSecretsRefresh(now, expiresAt time.Time, interval time.Duration) time.Duration {
+	wait := interval
+	if !expiresAt.IsZero() {
+		if untilExpiry := expiresAt.Sub(now) - secretsExpiryMargin; untilExpiry < wait {
+			wait = untilExpiry
+		}
+	}
+	return max(wait, minSecretsRefreshWait)
+}
+
+func secretsRefreshInterval(cfg *config.Config, logger *zap.Logger) time.Duration {
+	raw := cfg.SecretsRefreshInterval
+	if raw == "" {
+		return defaultSecretsRefreshInterval
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		logger.Warn("Invalid SLACK_MCP_SECRETS_REFRESH_INTERVAL, using default",
+			zap.String("context", "console"),
+			zap.String("value", raw),
+			zap.Duration("default", defaultSecretsRefreshInterval),
+		)
+		return defaultSecretsRefreshInterval
+	}
+	return d
+}
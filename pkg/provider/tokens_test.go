@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "xoxd")
+	require.NoError(t, os.WriteFile(path, []byte("xoxd-from-file\n"), 0o600))
+
+	tokens, err := resolveTokens(context.Background(), &config.Config{
+		XOXCToken: "xoxc-plain",
+		XOXDToken: "file:" + path,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "xoxc-plain", tokens.xoxc)
+	assert.Equal(t, "xoxd-from-file", tokens.xoxd)
+	assert.True(t, tokens.expiresAt.IsZero())
+
+	_, err = resolveTokens(context.Background(), &config.Config{XOXPToken: "file:" + filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorContains(t, err, "SLACK_MCP_XOXP_TOKEN")
+
+	assert.True(t, usesSecretBackends(&config.Config{XOXDToken: "file:" + path}))
+	assert.False(t, usesSecretBackends(&config.Config{XOXPToken: "xoxp-plain"}))
+}
+
+func TestNextSecretsRefresh(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      time.Duration
+	}{
+		{"no lease", time.Time{}, 15 * time.Minute},
+		{"lease longer than interval", now.Add(time.Hour), 15 * time.Minute},
+		{"lease shorter than interval", now.Add(5 * time.Minute), 4 * time.Minute},
+		{"lease about to end", now.Add(time.Minute), minSecretsRefreshWait},
+		{"lease ended", now.Add(-time.Hour), minSecretsRefreshWait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextSecretsRefresh(now, tt.expiresAt, 15*time.Minute))
+		})
+	}
+}
+
+func TestBotTokenRouting(t *testing.T) {
+	user := &MCPSlackClient{authResponse: &slack.AuthTestResponse{TeamID: "T1", UserID: "U1"}}
+	bot := &MCPSlackClient{authResponse: &slack.AuthTestResponse{TeamID: "T1", UserID: "UBOT"}, isBotToken: true}
+
+	assert.Same(t, user, user.route("chat.postMessage"), "without a bot every method uses the user token")
+
+	require.NoError(t, user.attachBot(bot, nil))
+	assert.True(t, user.HasBotToken())
+	assert.Equal(t, []string{"chat.postMessage", "chat.scheduleMessage"}, user.BotTokenMethods())
+	assert.Same(t, bot, user.route("chat.postMessage"))
+	assert.Same(t, user, user.route("search.messages"))
+	assert.Same(t, user, user.route("reactions.add"))
+
+	require.NoError(t, user.attachBot(bot, []string{"reactions.add"}))
+	assert.Same(t, bot, user.route("reactions.add"))
+	assert.Same(t, user, user.route("chat.postMessage"))
+
+	other := &MCPSlackClient{authResponse: &slack.AuthTestResponse{TeamID: "T2"}}
+	assert.ErrorContains(t, (&MCPSlackClient{authResponse: user.authResponse}).attachBot(other, nil), "team T2")
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"go.uber.org/zap"
+)
+
+const (
+	usersPageSize = 1000
+	// maxLazyUserLookups caps the users fetched on demand for one call while
+	// the users cache is warming.
+	maxLazyUserLookups = 100
+)
+
+// CacheProgress reports how far one cache is in warming up.
+type CacheProgress struct {
+	// State is pending, warming, ready or failed.
+	State string `json:"state"`
+	// Source is file when the cache was loaded from disk, api otherwise.
+	Source string `json:"source,omitempty"`
+	Count  int    `json:"count"`
+	// Expected is the size of the previous, expired cache file, as an
+	// estimate of the total while warming from the API.
+	Expected   int        `json:"expected,omitempty"`
+	Pages      int        `json:"pages,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// CacheStatus is served by the cache-status resource.
+type CacheStatus struct {
+	Ready    bool          `json:"ready"`
+	Users    CacheProgress `json:"users"`
+	Channels CacheProgress `json:"channels"`
+}
+
+type cacheProgress struct {
+	mu sync.Mutex
+	p  CacheProgress
+}
+
+func (c *cacheProgress) start(source string, expected int) {
+	now := time.Now()
+	c.mu.Lock()
+	c.p = CacheProgress{State: "warming", Source: source, Expected: expected, StartedAt: &now}
+	c.mu.Unlock()
+}
+
+func (c *cacheProgress) page(n int) {
+	c.mu.Lock()
+	c.p.Pages++
+	c.p.Count += n
+	c.mu.Unlock()
+}
+
+func (c *cacheProgress) finish(count int, err error) {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.p.StartedAt == nil {
+		c.p.StartedAt = &now
+	}
+	c.p.FinishedAt = &now
+	if err != nil {
+		c.p.State, c.p.Error = "failed", err.Error()
+		return
+	}
+	c.p.State, c.p.Count = "ready", count
+}
+
+func (c *cacheProgress) snapshot() CacheProgress {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := c.p
+	if p.State == "" {
+		p.State = "pending"
+	}
+	return p
+}
+
+// CacheStatus reports the warm-up progress of the users and channels caches.
+func (ap *ApiProvider) CacheStatus() CacheStatus {
+	return CacheStatus{
+		Ready:    ap.usersReady && ap.channelsReady,
+		Users:    ap.usersProgress.snapshot(),
+		Channels: ap.channelsProgress.snapshot(),
+	}
+}
+
+// UsersWarming reports whether the users cache is still being populated from
+// the Slack API. While warming, ProvideUsersMap returns the pages fetched so
+// far and EnsureUsers fills in users as they are needed.
+func (ap *ApiProvider) UsersWarming() bool {
+	return ap.usersWarming.Load()
+}
+
+// fetchUsers pages through users.list, publishing every page to the snapshot
+// so lookups work while the rest is fetched. users.list pages are chained by
+// cursor, so they are fetched one after another; the channels are fetched
+// concurrently under their own rate limit.
+func (ap *ApiProvider) fetchUsers(ctx context.Context) ([]slack.User, error) {
+	p := ap.Slack().GetUsersPaginated(slack.GetUsersOptionLimit(usersPageSize))
+
+	var (
+		users   []slack.User
+		err     error
+		retries int
+	)
+	for {
+		p, err = p.Next(ctx)
+		if p.Done(err) {
+			return users, nil
+		}
+		if err != nil {
+			var rlErr *slack.RateLimitedError
+			if errors.As(err, &rlErr) && retries < channelsPageMaxRetries {
+				retries++
+				ap.logger.Warn("Rate limited while fetching users, backing off",
+					zap.Duration("retry_after", rlErr.RetryAfter),
+					zap.Int("attempt", retries),
+				)
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(rlErr.RetryAfter):
+				}
+				continue
+			}
+			return nil, err
+		}
+		retries = 0
+
+		users = append(users, p.Users...)
+		ap.mergeUsersSnapshot(p.Users)
+		ap.usersProgress.page(len(p.Users))
+		ap.logger.Debug("Fetched users page", zap.Int("count", len(p.Users)), zap.Int("total", len(users)))
+	}
+}
+
+// mergeUsersSnapshot publishes a new snapshot containing the current users
+// plus page.
+func (ap *ApiProvider) mergeUsersSnapshot(page []slack.User) {
+	ap.usersMergeMu.Lock()
+	defer ap.usersMergeMu.Unlock()
+
+	current := ap.usersSnapshot.Load()
+	newSnapshot := &UsersCache{
+		Users:    make(map[string]slack.User, len(current.Users)+len(page)),
+		UsersInv: make(map[string]string, len(current.UsersInv)+len(page)),
+	}
+	for id, u := range current.Users {
+		newSnapshot.Users[id] = u
+	}
+	for name, id := range current.UsersInv {
+		newSnapshot.UsersInv[name] = id
+	}
+	for _, u := range page {
+		newSnapshot.Users[u.ID] = u
+		newSnapshot.UsersInv[u.Name] = u.ID
+	}
+	ap.usersSnapshot.Store(newSnapshot)
+}
+
+// EnsureUsers looks up the given users with users.info when the users cache
+// is still warming and has not reached them yet, so early calls resolve
+// names without waiting for the full list. It does nothing once the cache is
+// ready. Lookup failures only cost the names.
+func (ap *ApiProvider) EnsureUsers(ctx context.Context, ids []string) {
+	if ap.usersReady || !ap.usersWarming.Load() {
+		return
+	}
+
+	cache := ap.usersSnapshot.Load()
+	seen := make(map[string]bool, len(ids))
+	var missing []string
+	for _, id := range ids {
+		if id == "" || seen[id] || !(strings.HasPrefix(id, "U") || strings.HasPrefix(id, "W")) {
+			continue
+		}
+		seen[id] = true
+		if _, ok := cache.Users[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	if len(missing) > maxLazyUserLookups {
+		missing = missing[:maxLazyUserLookups]
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+	users, err := ap.Slack().GetUsersInfo(missing...)
+	if err != nil {
+		ap.logger.Debug("Lazy users lookup failed", zap.Int("count", len(missing)), zap.Error(err))
+		return
+	}
+	ap.mergeUsersSnapshot(*users)
+	ap.logger.Debug("Looked up users while cache is warming", zap.Int("count", len(*users)))
+}
+
+// remapIMChannels renames DMs after the users cache is complete: channels are
+// fetched concurrently with users, so early DMs may be named after user IDs.
+func (ap *ApiProvider) remapIMChannels() {
+	ap.channelsMergeMu.Lock()
+	defer ap.channelsMergeMu.Unlock()
+
+	current := ap.channelsSnapshot.Load()
+	users := ap.ProvideUsersMap().Users
+	newSnapshot := &ChannelsCache{
+		Channels:    make(map[string]Channel, len(current.Channels)),
+		ChannelsInv: make(map[string]string, len(current.ChannelsInv)),
+	}
+	for id, c := range current.Channels {
+		if c.IsIM {
+			c = mapChannel(
+				c.ID, "", "", c.Topic, c.Purpose,
+				c.User, c.Members, c.MemberCount,
+				c.IsIM, c.IsMpIM, c.IsPrivate,
+				users,
+			)
+		}
+		newSnapshot.Channels[id] = c
+		newSnapshot.ChannelsInv[c.Name] = id
+	}
+	ap.channelsSnapshot.Store(newSnapshot)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheProgress(t *testing.T) {
+	var c cacheProgress
+	assert.Equal(t, "pending", c.snapshot().State)
+
+	c.start("api", 2500)
+	c.page(1000)
+	c.page(1000)
+	p := c.snapshot()
+	assert.Equal(t, "warming", p.State)
+	assert.Equal(t, 2000, p.Count)
+	assert.Equal(t, 2, p.Pages)
+	assert.Equal(t, 2500, p.Expected)
+	assert.NotNil(t, p.StartedAt)
+	assert.Nil(t, p.FinishedAt)
+
+	c.finish(2431, nil)
+	p = c.snapshot()
+	assert.Equal(t, "ready", p.State)
+	assert.Equal(t, 2431, p.Count)
+	assert.NotNil(t, p.FinishedAt)
+
+	c.start("api", 0)
+	c.finish(0, errors.New("invalid_auth"))
+	p = c.snapshot()
+	assert.Equal(t, "failed", p.State)
+	assert.Equal(t, "invalid_auth", p.Error)
+	assert.Zero(t, p.Pages)
+}
+
+// TestUsersWarmingIsReady verifies tools are served while the users cache is
+// warming but CacheStatus still reports it incomplete.
+func TestUsersWarmingIsReady(t *testing.T) {
+	ap := &ApiProvider{channelsReady: true}
+	ready, err := ap.IsReady()
+	assert.False(t, ready)
+	assert.ErrorIs(t, err, ErrUsersNotReady)
+
+	ap.usersWarming.Store(true)
+	ready, err = ap.IsReady()
+	assert.True(t, ready)
+	assert.NoError(t, err)
+	assert.False(t, ap.CacheStatus().Ready)
+}
+
+func TestMergeUsersSnapshot(t *testing.T) {
+	ap := &ApiProvider{}
+	ap.usersSnapshot.Store(&UsersCache{
+		Users:    map[string]slack.User{"U1": {ID: "U1", Name: "ana"}},
+		UsersInv: map[string]string{"ana": "U1"},
+	})
+	before := ap.ProvideUsersMap()
+
+	ap.mergeUsersSnapshot([]slack.User{{ID: "U2", Name: "bo"}})
+
+	after := ap.ProvideUsersMap()
+	require.Len(t, after.Users, 2)
+	assert.Equal(t, "U2", after.UsersInv["bo"])
+	assert.Len(t, before.Users, 1, "previous snapshot must not be mutated")
+}
+
+func TestEnsureUsersNoopWhenNotWarming(t *testing.T) {
+	// No Slack client is configured, so any lookup would panic.
+	ap := &ApiProvider{}
+	ap.usersSnapshot.Store(&UsersCache{Users: map[string]slack.User{}, UsersInv: map[string]string{}})
+	ap.EnsureUsers(context.Background(), []string{"U1"})
+
+	ap.usersWarming.Store(true)
+	ap.usersSnapshot.Store(&UsersCache{Users: map[string]slack.User{"U1": {ID: "U1"}}, UsersInv: map[string]string{}})
+	ap.EnsureUsers(context.Background(), []string{"U1", "", "B123"})
+}
+
+// TestRemapIMChannels verifies DMs fetched before their user was cached are
+// renamed once users are complete.
+func TestRemapIMChannels(t *testing.T) {
+	ap := &ApiProvider{}
+	ap.usersSnapshot.Store(&UsersCache{
+		Users:    map[string]slack.User{"U1": {ID: "U1", Name: "ana"}},
+		UsersInv: map[string]string{"ana": "U1"},
+	})
+	ap.channelsSnapshot.Store(&ChannelsCache{
+		Channels: map[string]Channel{
+			"D1": {ID: "D1", Name: "U1", User: "U1", IsIM: true},
+			"C1": {ID: "C1", Name: "#general"},
+		},
+		ChannelsInv: map[string]string{"U1": "D1", "#general": "C1"},
+	})
+
+	ap.remapIMChannels()
+
+	cache := ap.ProvideChannelsMaps()
+	assert.Equal(t, "@ana", cache.Channels["D1"].Name)
+	assert.Equal(t, "D1", cache.ChannelsInv["@ana"])
+	assert.NotContains(t, cache.ChannelsInv, "U1")
+	assert.Equal(t, "C1", cache.ChannelsInv["#general"])
+}
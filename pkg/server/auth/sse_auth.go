@@ -5,8 +5,8 @@ import (
 	"crypto/subtle"
 	"fmt"
 	"net/http"
-	"os"
 	"strings"
+	"sync/atomic"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -16,6 +16,51 @@ import (
 // authKey is a custom context key for storing the auth token.
 type authKey struct{}
 
+// slackTokenKey is a custom context key for a per-client Slack token used in multi-user mode.
+type slackTokenKey struct{}
+
+// SlackTokenHeader is the request header HTTP, SSE and WebSocket clients use to supply their own Slack token.
+const SlackTokenHeader = "X-Slack-User-Token"
+
+// SlackTokenFromContext returns the per-client Slack token, if the request carried one.
+func SlackTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(slackTokenKey{}).(string)
+	return token
+}
+
+// apiKey is SLACK_MCP_API_KEY, the bearer token clients must send.
+var apiKey atomic.Pointer[string]
+
+// SetAPIKey replaces the bearer token required from clients of the sse, http
+// and ws transports; an empty key turns authentication off.
+func SetAPIKey(key string) {
+	apiKey.Store(&key)
+}
+
+func configuredAPIKey() string {
+	if key := apiKey.Load(); key != nil {
+		return *key
+	}
+	return ""
+}
+
+// clientKeys are the bearer tokens of the quota clients, accepted besides
+// SLACK_MCP_API_KEY.
+var clientKeys atomic.Pointer[[]string]
+
+// SetClientKeys replaces the additional bearer tokens accepted when
+// SLACK_MCP_API_KEY is set.
+func SetClientKeys(keys []string) {
+	clientKeys.Store(&keys)
+}
+
+// BearerFromContext returns the bearer token the request carried, without
+// the "Bearer " prefix.
+func BearerFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(authKey{}).(string)
+	return strings.TrimPrefix(key, "Bearer ")
+}
+
 // withAuthKey adds an auth key to the context.
 func withAuthKey(ctx context.Context, auth string) context.Context {
 	return context.WithValue(ctx, authKey{}, auth)
@@ -24,14 +69,7 @@ func withAuthKey(ctx context.Context, auth string) context.Context {
 // Authenticate checks if the request is authenticated based on the provided context.
 func validateToken(ctx context.Context, logger *zap.Logger) (bool, error) {
 	// no configured token means no authentication
-	keyA := os.Getenv("SLACK_MCP_API_KEY")
-	if keyA == "" {
-		keyA = os.Getenv("SLACK_MCP_SSE_API_KEY")
-		if keyA != "" {
-			logger.Warn("SLACK_MCP_SSE_API_KEY is deprecated, please use SLACK_MCP_API_KEY")
-		}
-	}
-
+	keyA := configuredAPIKey()
 	if keyA == "" {
 		logger.Debug("No SSE API key configured, skipping authentication",
 			zap.String("context", "http"),
@@ -56,7 +94,7 @@ func validateToken(ctx context.Context, logger *zap.Logger) (bool, error) {
 		keyB = strings.TrimPrefix(keyB, "Bearer ")
 	}
 
-	if subtle.ConstantTimeCompare([]byte(keyA), []byte(keyB)) != 1 {
+	if !keyMatches(keyB, keyA) {
 		logger.Warn("Invalid auth token provided",
 			zap.String("context", "http"),
 		)
@@ -69,11 +107,32 @@ func validateToken(ctx context.Context, logger *zap.Logger) (bool, error) {
 	return true, nil
 }
 
-// AuthFromRequest extracts the auth token from the request headers.
+// keyMatches compares key with the API key and the client keys in constant
+// time.
+func keyMatches(key, apiKey string) bool {
+	match := subtle.ConstantTimeCompare([]byte(apiKey), []byte(key))
+	if keys := clientKeys.Load(); keys != nil {
+		for _, k := range *keys {
+			match |= subtle.ConstantTimeCompare([]byte(k), []byte(key))
+		}
+	}
+	return match == 1
+}
+
+// APIKeyConfigured reports whether an API key is set.
+func APIKeyConfigured() bool {
+	return configuredAPIKey() != ""
+}
+
+// AuthFromRequest extracts the auth token and the optional per-client Slack token from the request headers.
 func AuthFromRequest(logger *zap.Logger) func(context.Context, *http.Request) context.Context {
 	return func(ctx context.Context, r *http.Request) context.Context {
 		authHeader := r.Header.Get("Authorization")
-		return withAuthKey(ctx, authHeader)
+		ctx = withAuthKey(ctx, authHeader)
+		if token := strings.TrimSpace(r.Header.Get(SlackTokenHeader)); token != "" {
+			ctx = context.WithValue(ctx, slackTokenKey{}, token)
+		}
+		return ctx
 	}
 }
 
@@ -114,7 +173,7 @@ func IsAuthenticated(ctx context.Context, transport string, logger *zap.Logger)
 	case "stdio":
 		return true, nil
 
-	case "sse", "http":
+	case "sse", "http", "ws":
 		authenticated, err := validateToken(ctx, logger)
 
 		if err != nil {
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// buildCacheStatusResource serves the warm-up progress of the users and
+// channels caches, so clients can tell why early calls are slow or return
+// partial results.
+func buildCacheStatusResource(ap *provider.ApiProvider, logger *zap.Logger) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Debug("CacheStatusResource called", zap.Any("params", request.Params))
+
+		if authenticated, err := auth.IsAuthenticated(ctx, ap.ServerTransport(), logger); !authenticated {
+			logger.Error("Authentication failed for cache-status resource", zap.Error(err))
+			return nil, err
+		}
+
+		data, err := json.Marshal(ap.CacheStatus())
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
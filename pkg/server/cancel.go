@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// requestIDMetaKey carries the JSON-RPC id of a tool call from the
+// BeforeCallTool hook to the cancellation middleware; handlers do not see
+// the id otherwise.
+const requestIDMetaKey = "slack-mcp-server/requestId"
+
+var errCancelledByClient = errors.New("tool call cancelled by the client")
+
+// callCancels cancels in-flight tool calls when the client sends
+// notifications/cancelled for them, so long pagination loops stop calling
+// Slack instead of running to completion.
+type callCancels struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelCauseFunc
+	logger  *zap.Logger
+}
+
+func newCallCancels(logger *zap.Logger) *callCancels {
+	return &callCancels{cancels: make(map[string]context.CancelCauseFunc), logger: logger}
+}
+
+// callKey identifies a call by session and request id; ids are only unique
+// within a session.
+func callKey(session string, id any) string {
+	b, err := json.Marshal(id)
+	if err != nil {
+		return ""
+	}
+	return session + "|" + string(b)
+}
+
+// hooks records each call's request id in its _meta.
+func (c *callCancels) hooks(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(func(_ context.Context, id any, req *mcp.CallToolRequest) {
+		if req.Params.Meta == nil {
+			req.Params.Meta = &mcp.Meta{}
+		}
+		if req.Params.Meta.AdditionalFields == nil {
+			req.Params.Meta.AdditionalFields = make(map[string]any)
+		}
+		req.Params.Meta.AdditionalFields[requestIDMetaKey] = id
+	})
+}
+
+func (c *callCancels) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if req.Params.Meta == nil || req.Params.Meta.AdditionalFields[requestIDMetaKey] == nil {
+				return next(ctx, req)
+			}
+			key := callKey(handler.SessionID(ctx), req.Params.Meta.AdditionalFields[requestIDMetaKey])
+			if key == "" {
+				return next(ctx, req)
+			}
+
+			ctx, cancel := context.WithCancelCause(ctx)
+			c.mu.Lock()
+			c.cancels[key] = cancel
+			c.mu.Unlock()
+			defer func() {
+				c.mu.Lock()
+				delete(c.cancels, key)
+				c.mu.Unlock()
+				cancel(nil)
+			}()
+
+			res, err := next(ctx, req)
+			if errors.Is(context.Cause(ctx), errCancelledByClient) {
+				return nil, errCancelledByClient
+			}
+			return res, err
+		}
+	}
+}
+
+// handleCancelled is the notifications/cancelled handler.
+func (c *callCancels) handleCancelled(ctx context.Context, n mcp.JSONRPCNotification) {
+	id, ok := n.Params.AdditionalFields["requestId"]
+	if !ok {
+		return
+	}
+	key := callKey(handler.SessionID(ctx), id)
+	c.mu.Lock()
+	cancel, ok := c.cancels[key]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	reason, _ := n.Params.AdditionalFields["reason"].(string)
+	c.logger.Info("Tool call cancelled by the client", zap.Any("request_id", id), zap.String("reason", reason))
+	cancel(errCancelledByClient)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCallCancels(t *testing.T) {
+	c := newCallCancels(zap.NewNop())
+	hooks := &server.Hooks{}
+	c.hooks(hooks)
+	srv := server.NewMCPServer("test", "0")
+	ctx := srv.WithContext(context.Background(), testSession{id: "s1"})
+
+	started := make(chan struct{})
+	next := c.middleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = ToolConversationsHistory
+	hooks.OnBeforeCallTool[0](ctx, mcp.NewRequestId(int64(7)), &req)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := next(ctx, req)
+		done <- err
+	}()
+	<-started
+
+	notification := func(session string, id any) {
+		var n mcp.JSONRPCNotification
+		n.Method = "notifications/cancelled"
+		n.Params.AdditionalFields = map[string]any{"requestId": id}
+		c.handleCancelled(srv.WithContext(context.Background(), testSession{id: session}), n)
+	}
+	notification("s2", float64(7))
+	notification("s1", float64(8))
+	select {
+	case <-done:
+		t.Fatal("call cancelled by another session or request id")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	notification("s1", float64(7))
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, errCancelledByClient)
+	case <-time.After(time.Second):
+		t.Fatal("call not cancelled")
+	}
+	assert.Empty(t, c.cancels, "finished calls are unregistered")
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// toolScopes lists the OAuth scopes each tool relies on. A tool is usable when
+// the token holds at least one of them. Tools backed by undocumented internal
+// APIs (e.g. saved items) are not listed and are never hidden, nor is
+// admin_audit_search, which may use SLACK_MCP_AUDIT_TOKEN instead of the
+// token checked here.
+var toolScopes = map[string][]string{
+	ToolConversationsHistory:        {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolConversationsReplies:        {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolThreadSearch:                {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolThreadParticipants:          {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolConversationsDMHistory:      {"im:history"},
+	ToolConversationsAddMessage:     {"chat:write"},
+	ToolConversationsAddMessages:    {"chat:write"},
+	ToolConversationsForwardMessage: {"chat:write"},
+	ToolConversationsCleanup:        {"chat:write"},
+	ToolHuddlesStart:                {"chat:write"},
+	ToolConversationsOpenDM:         {"im:write", "mpim:write"},
+	ToolConversationsInvite:         {"channels:write", "groups:write", "channels:manage"},
+	ToolConversationsSetTopic:       {"channels:write.topic", "groups:write.topic", "channels:manage", "groups:write"},
+	ToolConversationsSetPurpose:     {"channels:write.topic", "groups:write.topic", "channels:manage", "groups:write"},
+	ToolReactionsAdd:                {"reactions:write"},
+	ToolReactionsRemove:             {"reactions:write"},
+	ToolReactionsSearch:             {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolConversationsTranscript:     {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolConversationsContext:        {"channels:history", "groups:history", "im:history", "mpim:history"},
+	ToolAttachmentGetData:           {"files:read"},
+	ToolFilesList:                   {"files:read"},
+	ToolFilesInfo:                   {"files:read"},
+	ToolConversationsSearchMessages: {"search:read"},
+	ToolActivityMentions:            {"search:read"},
+	ToolActivityFeed:                {"search:read"},
+	ToolChannelsList:                {"channels:read", "groups:read", "im:read", "mpim:read"},
+	"users_search":                  {"users:read"},
+	ToolUsergroupsList:              {"usergroups:read"},
+	ToolUsergroupsMe:                {"usergroups:read"},
+	ToolUsergroupsCreate:            {"usergroups:write"},
+	ToolUsergroupsUpdate:            {"usergroups:write"},
+	ToolUsergroupsUsersUpdate:       {"usergroups:write"},
+	ToolUsergroupsUsersModify:       {"usergroups:write"},
+	ToolUsergroupsDisable:           {"usergroups:write"},
+	ToolUsersStatusGet:              {"users.profile:read"},
+	ToolUsersStatusSet:              {"users.profile:write"},
+	ToolUsersProfileSet:             {"users.profile:write"},
+	ToolReactionsAddBulk:            {"reactions:write"},
+	ToolAssistantSetStatus:          {"assistant:write"},
+	ToolAssistantSetTitle:           {"assistant:write"},
+	ToolAssistantSetPrompts:         {"assistant:write"},
+	ToolTeamInfo:                    {"team:read"},
+}
+
+// botToolScopes replaces the toolScopes of tools that a bot token serves
+// differently: search scans channel history instead of using search:read.
+var botToolScopes = map[string][]string{
+	ToolConversationsSearchMessages: {"channels:history", "groups:history", "im:history", "mpim:history"},
+}
+
+// requiredScopes returns the scopes of which tool needs one, for a bot token
+// when bot is set.
+func requiredScopes(tool string, bot bool) []string {
+	if scopes, ok := botToolScopes[tool]; ok && bot {
+		return scopes
+	}
+	return toolScopes[tool]
+}
+
+// SkippedTool is a tool that was not registered because the token lacks its scopes.
+type SkippedTool struct {
+	Name          string   `json:"name"`
+	RequiredAnyOf []string `json:"required_any_of"`
+}
+
+// Capabilities describes what the configured token can do; served as the
+// slack://<workspace>/capabilities resource.
+type Capabilities struct {
+	TokenType    string        `json:"token_type"`
+	ScopesKnown  bool          `json:"scopes_known"`
+	Scopes       []string      `json:"scopes,omitempty"`
+	Tools        []string      `json:"tools"`
+	SkippedTools []SkippedTool `json:"skipped_tools,omitempty"`
+	// TokenHealth is filled when the resource is read.
+	TokenHealth *provider.TokenHealth `json:"token_health,omitempty"`
+}
+
+// toolAllowedByScopes reports whether granted satisfies the scopes needed by tool.
+// A nil granted set means the scopes are unknown and every tool is allowed.
+func toolAllowedByScopes(tool string, granted map[string]bool) bool {
+	return scopesAllowed(toolScopes[tool], granted)
+}
+
+// scopesAllowed reports whether granted holds one of required. Nil granted
+// or required allows everything.
+func scopesAllowed(required []string, granted map[string]bool) bool {
+	if granted == nil || required == nil {
+		return true
+	}
+	for _, scope := range required {
+		if granted[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// applyScopeFilter detects the scopes granted to the token, removes registered
+// tools the token can't use and returns the resulting capabilities. Detection
+// can be turned off with SLACK_MCP_SCOPE_CHECK=false.
+func applyScopeFilter(s *server.MCPServer, ap *provider.ApiProvider, logger *zap.Logger) *Capabilities {
+	caps := &Capabilities{TokenType: "session"}
+	switch {
+	case ap.HasBotToken():
+		caps.TokenType = "user+bot"
+	case ap.IsBotToken():
+		caps.TokenType = "bot"
+	case ap.IsOAuth():
+		caps.TokenType = "user"
+	}
+
+	var granted map[string]bool
+	if ap.Config().ScopeCheckEnabled() {
+		scopes, err := ap.Slack().GrantedScopesContext(context.Background())
+		if err != nil {
+			logger.Warn("Failed to detect token scopes, registering all tools",
+				zap.String("context", "console"),
+				zap.Error(err),
+			)
+		} else if scopes != nil {
+			sort.Strings(scopes)
+			caps.ScopesKnown = true
+			caps.Scopes = scopes
+			granted = make(map[string]bool, len(scopes))
+			for _, scope := range scopes {
+				granted[scope] = true
+			}
+		}
+	}
+
+	names := make([]string, 0)
+	for name := range s.ListTools() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		required := requiredScopes(name, ap.IsBotToken())
+		if scopesAllowed(required, granted) {
+			caps.Tools = append(caps.Tools, name)
+			continue
+		}
+		s.DeleteTools(name)
+		caps.SkippedTools = append(caps.SkippedTools, SkippedTool{
+			Name:          name,
+			RequiredAnyOf: required,
+		})
+		logger.Warn("Tool skipped, token is missing required scopes",
+			zap.String("context", "console"),
+			zap.String("tool", name),
+			zap.String("required_any_of", strings.Join(required, ",")),
+		)
+	}
+
+	return caps
+}
+
+func buildCapabilitiesResource(caps func() *Capabilities, ap *provider.ApiProvider, logger *zap.Logger) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Debug("CapabilitiesResource called", zap.Any("params", request.Params))
+
+		if authenticated, err := auth.IsAuthenticated(ctx, ap.ServerTransport(), logger); !authenticated {
+			logger.Error("Authentication failed for capabilities resource", zap.Error(err))
+			return nil, err
+		}
+
+		var c Capabilities
+		if current := caps(); current != nil {
+			c = *current
+		}
+		health := ap.TokenHealth()
+		c.TokenHealth = &health
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
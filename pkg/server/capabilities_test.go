@@ -0,0 +1,43 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolAllowedByScopes(t *testing.T) {
+	t.Run("unknown scopes allow every tool", func(t *testing.T) {
+		for _, tool := range ValidToolNames {
+			assert.True(t, toolAllowedByScopes(tool, nil), "%s should be allowed when scopes are unknown", tool)
+		}
+	})
+
+	t.Run("any of the listed scopes is enough", func(t *testing.T) {
+		granted := map[string]bool{"im:history": true, "chat:write": true}
+		assert.True(t, toolAllowedByScopes(ToolConversationsHistory, granted))
+		assert.True(t, toolAllowedByScopes(ToolConversationsReplies, granted))
+		assert.True(t, toolAllowedByScopes(ToolConversationsAddMessage, granted))
+	})
+
+	t.Run("missing scopes hide the tool", func(t *testing.T) {
+		granted := map[string]bool{"channels:read": true}
+		assert.True(t, toolAllowedByScopes(ToolChannelsList, granted))
+		assert.False(t, toolAllowedByScopes(ToolConversationsHistory, granted))
+		assert.False(t, toolAllowedByScopes(ToolConversationsSearchMessages, granted))
+		assert.False(t, toolAllowedByScopes(ToolUsergroupsCreate, granted))
+	})
+
+	t.Run("bot tokens search with history scopes", func(t *testing.T) {
+		granted := map[string]bool{"channels:history": true}
+		assert.True(t, scopesAllowed(requiredScopes(ToolConversationsSearchMessages, true), granted))
+		assert.False(t, scopesAllowed(requiredScopes(ToolConversationsSearchMessages, false), granted))
+		assert.Equal(t, toolScopes[ToolConversationsHistory], requiredScopes(ToolConversationsHistory, true))
+	})
+
+	t.Run("tools without known scopes are never hidden", func(t *testing.T) {
+		granted := map[string]bool{}
+		assert.True(t, toolAllowedByScopes(ToolSavedList, granted))
+		assert.True(t, toolAllowedByScopes(ToolSavedComplete, granted))
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"go.uber.org/zap"
+)
+
+const (
+	corsAllowMethods = "GET, POST, DELETE, OPTIONS"
+	corsMaxAge       = "600"
+)
+
+var corsAllowHeaders = strings.Join([]string{
+	"Authorization",
+	"Content-Type",
+	"Accept",
+	"Last-Event-ID",
+	"Mcp-Session-Id",
+	"Mcp-Protocol-Version",
+	auth.SlackTokenHeader,
+}, ", ")
+
+// originPolicy decides which browser origins may call the HTTP/SSE endpoints.
+// Allowed origins get CORS headers; with enforcement on, requests from any
+// other origin are rejected to guard against DNS rebinding.
+type originPolicy struct {
+	origins []string
+	enforce bool
+	logger  *zap.Logger
+}
+
+// newOriginPolicy reads SLACK_MCP_CORS_ALLOWED_ORIGINS and
+// SLACK_MCP_ENFORCE_ORIGIN from cfg. It returns nil when neither is set.
+func newOriginPolicy(cfg *config.Config, logger *zap.Logger) *originPolicy {
+	var origins []string
+	for _, o := range cfg.CORSAllowedOrigins {
+		if o = strings.TrimRight(strings.TrimSpace(o), "/"); o != "" {
+			origins = append(origins, strings.ToLower(o))
+		}
+	}
+	if len(origins) == 0 && !cfg.OriginEnforced() {
+		return nil
+	}
+
+	return &originPolicy{
+		origins: origins,
+		enforce: cfg.OriginEnforced(),
+		logger:  logger,
+	}
+}
+
+// allows reports whether origin matches the configured list. Entries may be an
+// exact origin ("https://app.example.com"), a subdomain wildcard
+// ("https://*.example.com") or "*". Without a list only loopback origins are
+// allowed.
+func (p *originPolicy) allows(origin string) bool {
+	origin = strings.ToLower(strings.TrimRight(origin, "/"))
+	if len(p.origins) == 0 {
+		return isLoopbackOrigin(origin)
+	}
+
+	for _, allowed := range p.origins {
+		switch {
+		case allowed == "*", allowed == origin:
+			return true
+		case strings.Contains(allowed, "://*."):
+			scheme, suffix, _ := strings.Cut(allowed, "://*")
+			if strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isLoopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+func (p *originPolicy) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Not a browser request; nothing to validate.
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		if !p.allows(origin) {
+			if p.enforce {
+				p.logger.Warn("Rejected request from disallowed origin",
+					zap.String("context", "http"),
+					zap.String("origin", origin),
+					zap.String("path", r.URL.Path),
+				)
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestOriginPolicyAllows(t *testing.T) {
+	p := &originPolicy{origins: []string{"https://app.example.com", "https://*.corp.example"}}
+	assert.True(t, p.allows("https://app.example.com"))
+	assert.True(t, p.allows("https://APP.example.com/"))
+	assert.True(t, p.allows("https://tools.corp.example"))
+	assert.False(t, p.allows("http://tools.corp.example"))
+	assert.False(t, p.allows("https://evil.example.com"))
+
+	loopback := &originPolicy{}
+	assert.True(t, loopback.allows("http://localhost:3000"))
+	assert.True(t, loopback.allows("http://127.0.0.1:6274"))
+	assert.False(t, loopback.allows("http://attacker.test"))
+
+	wildcard := &originPolicy{origins: []string{"*"}}
+	assert.True(t, wildcard.allows("https://anything.test"))
+}
+
+func TestOriginPolicyMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	do := func(p *originPolicy, method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/mcp", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		p.middleware(next).ServeHTTP(rec, req)
+		return rec
+	}
+
+	p := &originPolicy{origins: []string{"https://app.example.com"}, enforce: true, logger: zap.NewNop()}
+
+	t.Run("preflight from allowed origin", func(t *testing.T) {
+		rec := do(p, http.MethodOptions, "https://app.example.com")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Mcp-Session-Id")
+	})
+
+	t.Run("request from allowed origin", func(t *testing.T) {
+		rec := do(p, http.MethodPost, "https://app.example.com")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Mcp-Session-Id", rec.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("disallowed origin is rejected when enforcing", func(t *testing.T) {
+		rec := do(p, http.MethodPost, "https://evil.example.com")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("disallowed origin passes without CORS headers when not enforcing", func(t *testing.T) {
+		lax := &originPolicy{origins: p.origins, logger: zap.NewNop()}
+		rec := do(lax, http.MethodPost, "https://evil.example.com")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("requests without origin are not affected", func(t *testing.T) {
+		rec := do(p, http.MethodPost, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
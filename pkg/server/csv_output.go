@@ -0,0 +1,211 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	csvFieldsParam = "fields"
+	// csvFieldsExample is a selection of the message columns of
+	// conversations_history, as named in its CSV header.
+	csvFieldsExample    = "MsgID,UserName,Text,Time"
+	csvFieldsParamUsage = "Comma-separated CSV columns to return, in this order, e.g. '" + csvFieldsExample + "'; names match the header case-insensitively. Defaults to every column. Leaving out the Cursor column drops the pagination cursor."
+)
+
+// csvOutputTools are the read tools whose result is CSV. They accept the
+// fields parameter and their output follows SLACK_MCP_CSV_DELIMITER and
+// SLACK_MCP_CSV_QUOTING.
+var csvOutputTools = map[string]bool{
+	ToolConversationsHistory:        true,
+	ToolConversationsDMHistory:      true,
+	ToolConversationsReplies:        true,
+	ToolThreadSearch:                true,
+	ToolThreadParticipants:          true,
+	ToolConversationsContext:        true,
+	ToolConversationsSearchMessages: true,
+	ToolChannelsList:                true,
+	ToolDirectoryList:               true,
+	"users_search":                  true,
+	ToolUsergroupsList:              true,
+	ToolSavedList:                   true,
+	ToolUsersStatusGet:              true,
+	ToolActivityMentions:            true,
+	ToolActivityThreads:             true,
+	ToolActivityFeed:                true,
+	ToolFilesList:                   true,
+	ToolTeamInfo:                    true,
+	ToolReactionsSearch:             true,
+	ToolAdminAuditSearch:            true,
+}
+
+// declareCSVFields adds the fields parameter to the registered tools in
+// csvOutputTools.
+func declareCSVFields(s *server.MCPServer) {
+	for name := range csvOutputTools {
+		tool := s.GetTool(name)
+		if tool == nil {
+			continue
+		}
+		schema := &tool.Tool.InputSchema
+		schema.Properties = maps.Clone(schema.Properties)
+		if schema.Properties == nil {
+			schema.Properties = make(map[string]any)
+		}
+		schema.Properties[csvFieldsParam] = map[string]any{
+			"type":        "string",
+			"description": csvFieldsParamUsage,
+		}
+		s.AddTools(*tool)
+	}
+}
+
+// csvDialect is how CSV results are written.
+type csvDialect struct {
+	comma    rune
+	quoteAll bool
+}
+
+func csvDialectOf(cfg *config.Config) csvDialect {
+	comma, err := cfg.CSVComma()
+	if err != nil {
+		// Validate rejects such a config before it is applied.
+		comma = ','
+	}
+	return csvDialect{comma: comma, quoteAll: cfg.CSVQuoting == "all"}
+}
+
+func (d csvDialect) isDefault() bool {
+	return d.comma == ',' && !d.quoteAll
+}
+
+func (d csvDialect) write(records [][]string) (string, error) {
+	var sb strings.Builder
+	if !d.quoteAll {
+		w := csv.NewWriter(&sb)
+		w.Comma = d.comma
+		err := w.WriteAll(records)
+		return sb.String(), err
+	}
+	for _, record := range records {
+		for i, field := range record {
+			if i > 0 {
+				sb.WriteRune(d.comma)
+			}
+			sb.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// buildCSVOutputMiddleware cuts CSV results down to the requested fields,
+// taken from the call or else the tools config, and writes them in the
+// configured dialect. Both are read on every call so reloads apply
+// immediately.
+func buildCSVOutputMiddleware(toolsConfig func() *ToolsConfig, cfg func() *config.Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !csvOutputTools[req.Params.Name] {
+				return next(ctx, req)
+			}
+			fields := splitFields(req.GetString(csvFieldsParam, ""))
+			if tc := toolsConfig(); len(fields) == 0 && tc != nil {
+				fields = tc.Tools[req.Params.Name].Fields
+			}
+			dialect := csvDialectOf(cfg())
+
+			res, err := next(ctx, req)
+			if err != nil || res == nil || res.IsError || (len(fields) == 0 && dialect.isDefault()) {
+				return res, err
+			}
+			return rewriteCSVResult(res, fields, dialect)
+		}
+	}
+}
+
+func splitFields(raw string) []string {
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// rewriteCSVResult rewrites the first text content of res, the CSV part of
+// every tool in csvOutputTools; metadata blocks after it are kept as they
+// are. Text that is not CSV, such as a "no results" message, is left alone.
+func rewriteCSVResult(res *mcp.CallToolResult, fields []string, dialect csvDialect) (*mcp.CallToolResult, error) {
+	for i, content := range res.Content {
+		tc, ok := content.(mcp.TextContent)
+		if !ok {
+			continue
+		}
+		r := csv.NewReader(strings.NewReader(tc.Text))
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil || len(records) == 0 {
+			return res, nil
+		}
+		if len(fields) > 0 {
+			if records, err = selectCSVFields(records, fields); err != nil {
+				if len(records) == 1 {
+					return res, nil
+				}
+				return nil, err
+			}
+		}
+		text, err := dialect.write(records)
+		if err != nil {
+			return nil, err
+		}
+
+		out := *res
+		out.Content = append([]mcp.Content(nil), res.Content...)
+		tc.Text = text
+		out.Content[i] = tc
+		return &out, nil
+	}
+	return res, nil
+}
+
+// selectCSVFields keeps the columns named in fields, in that order. On an
+// unknown field it returns the records unchanged with an error listing the
+// available columns.
+func selectCSVFields(records [][]string, fields []string) ([][]string, error) {
+	header := records[0]
+	index := make([]int, len(fields))
+	for i, field := range fields {
+		index[i] = -1
+		for j, name := range header {
+			if strings.EqualFold(name, field) {
+				index[i] = j
+				break
+			}
+		}
+		if index[i] < 0 {
+			return records, fmt.Errorf("unknown field %q, available fields: %s", field, strings.Join(header, ", "))
+		}
+	}
+
+	out := make([][]string, len(records))
+	for r, record := range records {
+		row := make([]string, len(index))
+		for i, j := range index {
+			if j < len(record) {
+				row[i] = record[j]
+			}
+		}
+		out[r] = row
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVOutputMiddleware(t *testing.T) {
+	historyRes, err := handler.MarshalMessagesToCSV([]handler.Message{
+		{MsgID: "1700000000.000100", UserName: "alice", Text: "hello, world", Time: "2023-11-14T22:13:20Z", Cursor: "c1"},
+		{MsgID: "1700000000.000200", UserName: "bob", Text: `say "hi"`, Time: "2023-11-14T22:13:20Z"},
+	}, handler.MessageColumns{})
+	require.NoError(t, err)
+	history := historyRes.Content[0].(mcp.TextContent).Text
+
+	cfg := config.Default()
+	toolsConfig := &ToolsConfig{Tools: map[string]ToolConfig{
+		ToolChannelsList: {Fields: []string{"name"}},
+	}}
+	output := history
+	middleware := buildCSVOutputMiddleware(
+		func() *ToolsConfig { return toolsConfig },
+		func() *config.Config { return cfg },
+	)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return csvResultWithMetadata(output), nil
+	})
+
+	call := func(tool string, args map[string]any) (string, error) {
+		t.Helper()
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		res, err := middleware(context.Background(), req)
+		if err != nil {
+			return "", err
+		}
+		require.Len(t, res.Content, 2, "metadata blocks are kept")
+		return res.Content[0].(mcp.TextContent).Text, nil
+	}
+
+	out, err := call(ToolConversationsHistory, nil)
+	require.NoError(t, err)
+	assert.Equal(t, history, out, "the default dialect without fields leaves results as they are")
+
+	out, err = call(ToolConversationsHistory, map[string]any{"fields": csvFieldsExample})
+	require.NoError(t, err, "the documented example selects real columns")
+	assert.Equal(t, "MsgID,UserName,Text,Time\n1700000000.000100,alice,\"hello, world\",2023-11-14T22:13:20Z\n1700000000.000200,bob,\"say \"\"hi\"\"\",2023-11-14T22:13:20Z\n", out)
+
+	out, err = call(ToolConversationsHistory, map[string]any{"fields": "text, MSGID, cursor"})
+	require.NoError(t, err)
+	assert.Equal(t, "Text,MsgID,Cursor\n\"hello, world\",1700000000.000100,c1\n\"say \"\"hi\"\"\",1700000000.000200,\n", out)
+
+	_, err = call(ToolConversationsHistory, map[string]any{"fields": "MsgID,userUser"})
+	assert.ErrorContains(t, err, `unknown field "userUser", available fields: MsgID, UserID, UserName, RealName, Channel,`)
+
+	cfg.CSVDelimiter = "tab"
+	out, err = call(ToolConversationsHistory, map[string]any{"fields": "UserName,Text"})
+	require.NoError(t, err)
+	assert.Equal(t, "UserName\tText\nalice\thello, world\nbob\t\"say \"\"hi\"\"\"\n", out)
+
+	cfg.CSVDelimiter, cfg.CSVQuoting = ";", "all"
+	output = "id,name\nC1,general\n"
+	out, err = call(ToolChannelsList, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "\"name\"\n\"general\"\n", out, "fields fall back to the tools config")
+
+	output = "No users found matching the query."
+	out, err = call("users_search", map[string]any{"fields": "UserID"})
+	require.NoError(t, err)
+	assert.Equal(t, output, out, "messages that are not CSV are left alone")
+
+	out, err = call(ToolConversationsAddMessage, map[string]any{"fields": "MsgID"})
+	require.NoError(t, err)
+	assert.Equal(t, output, out, "write tools are not rewritten")
+}
+
+func csvResultWithMetadata(text string) *mcp.CallToolResult {
+	res := mcp.NewToolResultText(text)
+	res.Content = append(res.Content, mcp.NewTextContent(`{"total":2}`))
+	return res
+}
+
+func TestDeclareCSVFields(t *testing.T) {
+	s := server.NewMCPServer("test", "0")
+	s.AddTool(mcp.NewTool(ToolTeamInfo), nil)
+	s.AddTool(mcp.NewTool(ToolConversationsAddMessage, mcp.WithString("text")), nil)
+
+	declareCSVFields(s)
+
+	assert.Contains(t, s.GetTool(ToolTeamInfo).Tool.InputSchema.Properties, csvFieldsParam)
+	assert.NotContains(t, s.GetTool(ToolConversationsAddMessage).Tool.InputSchema.Properties, csvFieldsParam)
+	assert.Nil(t, s.GetTool(ToolChannelsList), "tools that are not registered are not added")
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// buildEmbeddingSinkMiddleware hands sink to the handlers that fetch
+// messages, which export what they fetched to it.
+func buildEmbeddingSinkMiddleware(sink *handler.EmbeddingSink) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if sink == nil {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return next(handler.WithEmbeddingSink(ctx, sink), req)
+		}
+	}
+}
+
+// buildClassifierMiddleware hands c to the handlers that return messages,
+// which label and filter their rows with it.
+func buildClassifierMiddleware(c handler.MessageClassifier) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if c == nil {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return next(handler.WithClassifier(ctx, c), req)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	httpSessionIDPrefix = "mcp-session-"
+	// httpSessionTTL is how long an idle session stays valid; every request
+	// of the session more than httpSessionRefresh after the last renewal
+	// extends it.
+	httpSessionTTL     = 24 * time.Hour
+	httpSessionRefresh = time.Hour
+
+	httpSessionActive     = "active"
+	httpSessionTerminated = "terminated"
+)
+
+// storeSessionIDManager issues the session IDs of the http transport and
+// records them in the shared store, so a session started on one replica is
+// accepted by every other and survives a replica restart. A session
+// terminated by a DELETE on one replica is terminated on all of them.
+type storeSessionIDManager struct {
+	store  store.Store
+	logger *zap.Logger
+	now    func() time.Time
+	// onTerminate drops the state kept for a session, such as its
+	// context_set defaults.
+	onTerminate func(sessionID string)
+}
+
+func newStoreSessionIDManager(st store.Store, onTerminate func(string), logger *zap.Logger) *storeSessionIDManager {
+	return &storeSessionIDManager{store: st, logger: logger, now: time.Now, onTerminate: onTerminate}
+}
+
+func httpSessionKey(sessionID string) string {
+	return "http-session/" + sessionID
+}
+
+func (m *storeSessionIDManager) Generate() string {
+	id := httpSessionIDPrefix + uuid.New().String()
+	if err := m.store.Put(context.Background(), httpSessionKey(id), []byte(httpSessionActive), httpSessionTTL); err != nil {
+		// The client is told to start over on its next request.
+		m.logger.Error("Failed to record HTTP session", zap.String("session", id), zap.Error(err))
+	}
+	return id
+}
+
+// Validate reports unknown and expired sessions as terminated, so the
+// client gets a 404 and starts a new session as the MCP spec asks.
+func (m *storeSessionIDManager) Validate(sessionID string) (bool, error) {
+	if !strings.HasPrefix(sessionID, httpSessionIDPrefix) {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	if _, err := uuid.Parse(sessionID[len(httpSessionIDPrefix):]); err != nil {
+		return false, fmt.Errorf("invalid session id: %s", sessionID)
+	}
+	ctx := context.Background()
+	e, ok, err := m.store.Get(ctx, httpSessionKey(sessionID))
+	if err != nil {
+		m.logger.Error("Failed to look up HTTP session", zap.String("session", sessionID), zap.Error(err))
+		return false, err
+	}
+	if !ok || string(e.Value) == httpSessionTerminated {
+		return true, nil
+	}
+	if m.now().Sub(e.StoredAt) > httpSessionRefresh {
+		if err := m.store.Put(ctx, httpSessionKey(sessionID), []byte(httpSessionActive), httpSessionTTL); err != nil {
+			m.logger.Warn("Failed to renew HTTP session", zap.String("session", sessionID), zap.Error(err))
+		}
+	}
+	return false, nil
+}
+
+func (m *storeSessionIDManager) Terminate(sessionID string) (bool, error) {
+	if err := m.store.Put(context.Background(), httpSessionKey(sessionID), []byte(httpSessionTerminated), httpSessionTTL); err != nil {
+		return false, err
+	}
+	if m.onTerminate != nil {
+		m.onTerminate(sessionID)
+	}
+	return false, nil
+}
+
+// httpSessionOptions returns the session handling of the http transport
+// for SLACK_MCP_HTTP_SESSIONS and SLACK_MCP_STORE.
+func (s *MCPServer) httpSessionOptions() []server.StreamableHTTPOption {
+	if s.provider.Config().HTTPSessions == "stateless" {
+		s.logger.Info("HTTP sessions are stateless, context_set is unavailable",
+			zap.String("context", "console"),
+		)
+		return []server.StreamableHTTPOption{server.WithStateLess(true)}
+	}
+	st := s.provider.Store()
+	if st == nil {
+		return nil
+	}
+	s.logger.Info("HTTP sessions are kept in the shared store",
+		zap.String("context", "console"),
+	)
+	return []server.StreamableHTTPOption{
+		server.WithSessionIdManager(newStoreSessionIDManager(st, s.sessions.Forget, s.logger)),
+	}
+}
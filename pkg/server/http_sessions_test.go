@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStoreSessionIDManager(t *testing.T) {
+	shared := store.NewMemory()
+	var terminated []string
+	replicaA := newStoreSessionIDManager(shared, func(id string) { terminated = append(terminated, id) }, zap.NewNop())
+	replicaB := newStoreSessionIDManager(shared, nil, zap.NewNop())
+
+	id := replicaA.Generate()
+	require.Contains(t, id, httpSessionIDPrefix)
+
+	ended, err := replicaB.Validate(id)
+	require.NoError(t, err)
+	assert.False(t, ended, "a session started on one replica is valid on the others")
+
+	ended, err = replicaA.Validate(httpSessionIDPrefix + "9b2f0d0e-3a8c-4a51-a4d8-0d1c6d1e2f30")
+	require.NoError(t, err)
+	assert.True(t, ended, "unknown sessions get a 404 so the client starts over")
+
+	_, err = replicaA.Validate("not-a-session")
+	assert.Error(t, err)
+
+	notAllowed, err := replicaA.Terminate(id)
+	require.NoError(t, err)
+	assert.False(t, notAllowed)
+	assert.Equal(t, []string{id}, terminated)
+	ended, err = replicaB.Validate(id)
+	require.NoError(t, err)
+	assert.True(t, ended, "a session terminated on one replica is terminated on all")
+}
+
+func TestStoreSessionIDManagerRenewal(t *testing.T) {
+	shared := store.NewMemory()
+	m := newStoreSessionIDManager(shared, nil, zap.NewNop())
+	id := m.Generate()
+
+	key := httpSessionKey(id)
+	before, ok, err := shared.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	m.now = func() time.Time { return time.Now().Add(httpSessionRefresh + time.Minute) }
+	_, err = m.Validate(id)
+	require.NoError(t, err)
+	after, _, err := shared.Get(context.Background(), key)
+	require.NoError(t, err)
+	assert.True(t, after.ExpiresAt.After(before.ExpiresAt), "a session in use is renewed")
+}
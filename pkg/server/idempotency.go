@@ -0,0 +1,244 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyParam      = "idempotency_key"
+	idempotencyTTL           = 15 * time.Minute
+	maxIdempotencyEntries    = 1000
+	maxIdempotencyKeyLength  = 255
+	idempotencyKeyParamUsage = "Optional client-chosen key, e.g. a UUID, unique to this write. If a call with the same key already succeeded in the last 15 minutes, its original result is returned instead of writing again; retry with the same key after a timeout or network error."
+)
+
+// idempotentTools are the write tools that accept an idempotency_key. They
+// create something new on every call, so a blind retry would duplicate it.
+var idempotentTools = map[string]bool{
+	ToolConversationsAddMessage:     true,
+	ToolConversationsAddMessages:    true,
+	ToolConversationsForwardMessage: true,
+	ToolUsergroupsCreate:            true,
+}
+
+// withIdempotencyKey declares the idempotency_key parameter on a tool.
+func withIdempotencyKey() mcp.ToolOption {
+	return mcp.WithString(idempotencyKeyParam,
+		mcp.MaxLength(maxIdempotencyKeyLength),
+		mcp.Description(idempotencyKeyParamUsage),
+	)
+}
+
+type idempotencyEntry struct {
+	args      string
+	done      chan struct{}
+	result    *mcp.CallToolResult
+	expiresAt time.Time
+}
+
+// idempotencyStore remembers recent successful write results by tool,
+// Slack identity and client-supplied key. A call still in flight holds its
+// entry, so a retry racing the original waits for it instead of writing twice.
+// With a shared store, SLACK_MCP_STORE, results are also recorded there, so
+// a retry routed to another replica gets the original result too; calls
+// still in flight are only known to their own replica.
+type idempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	shared  store.Store
+	now     func() time.Time
+	logger  *zap.Logger
+}
+
+// sharedIdempotencyResult is the record of a result in the shared store.
+type sharedIdempotencyResult struct {
+	Args   string          `json:"args"`
+	Result json.RawMessage `json:"result"`
+}
+
+func newIdempotencyStore(logger *zap.Logger, shared store.Store) *idempotencyStore {
+	return &idempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		shared:  shared,
+		now:     time.Now,
+		logger:  logger,
+	}
+}
+
+// acquire returns the entry for key and whether the caller owns it and must
+// run the call. Expired entries are replaced.
+func (st *idempotencyStore) acquire(key, args string) (*idempotencyEntry, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	now := st.now()
+	if e, ok := st.entries[key]; ok && (e.result == nil || now.Before(e.expiresAt)) {
+		return e, false
+	}
+
+	if len(st.entries) >= maxIdempotencyEntries {
+		for k, e := range st.entries {
+			if e.result != nil && !now.Before(e.expiresAt) {
+				delete(st.entries, k)
+			}
+		}
+	}
+	e := &idempotencyEntry{args: args, done: make(chan struct{})}
+	st.entries[key] = e
+	return e, true
+}
+
+// release records the outcome of an owned entry. Failed calls are forgotten
+// so that a retry with the same key runs again.
+func (st *idempotencyStore) release(key string, e *idempotencyEntry, res *mcp.CallToolResult, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if err != nil || res == nil || res.IsError {
+		delete(st.entries, key)
+	} else {
+		e.result = res
+		e.expiresAt = st.now().Add(idempotencyTTL)
+	}
+	close(e.done)
+}
+
+// run executes an owned call. The entry is released even if next panics, so
+// waiting retries are not stuck.
+func (st *idempotencyStore) run(ctx context.Context, req mcp.CallToolRequest, next server.ToolHandlerFunc, key string, e *idempotencyEntry) (res *mcp.CallToolResult, err error) {
+	defer func() {
+		st.release(key, e, res, err)
+		if err == nil && res != nil && !res.IsError {
+			st.share(context.WithoutCancel(ctx), key, e.args, res)
+		}
+	}()
+	return next(ctx, req)
+}
+
+// sharedKey is the shared store key of key, hashed since key holds the
+// client's idempotency key verbatim.
+func sharedKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "idempotency/" + hex.EncodeToString(sum[:])
+}
+
+// share records a successful result in the shared store for the other
+// replicas. Failing to is logged: the local entry still deduplicates.
+func (st *idempotencyStore) share(ctx context.Context, key, args string, res *mcp.CallToolResult) {
+	if st.shared == nil {
+		return
+	}
+	result, err := json.Marshal(res)
+	if err == nil {
+		var record []byte
+		record, err = json.Marshal(sharedIdempotencyResult{Args: args, Result: result})
+		if err == nil {
+			err = st.shared.Put(ctx, sharedKey(key), record, idempotencyTTL)
+		}
+	}
+	if err != nil {
+		st.logger.Warn("Failed to share idempotency result", zap.Error(err))
+	}
+}
+
+// lookupShared returns the result another replica recorded for key, with
+// the arguments of its call.
+func (st *idempotencyStore) lookupShared(ctx context.Context, key string) (string, *mcp.CallToolResult, bool) {
+	if st.shared == nil {
+		return "", nil, false
+	}
+	e, ok, err := st.shared.Get(ctx, sharedKey(key))
+	if err != nil {
+		st.logger.Warn("Failed to read shared idempotency result", zap.Error(err))
+		return "", nil, false
+	}
+	if !ok {
+		return "", nil, false
+	}
+	var record sharedIdempotencyResult
+	if err := json.Unmarshal(e.Value, &record); err != nil {
+		st.logger.Warn("Ignoring unreadable shared idempotency result", zap.Error(err))
+		return "", nil, false
+	}
+	res, err := mcp.ParseCallToolResult(&record.Result)
+	if err != nil {
+		st.logger.Warn("Ignoring unreadable shared idempotency result", zap.Error(err))
+		return "", nil, false
+	}
+	return record.Args, res, true
+}
+
+func buildIdempotencyMiddleware(st *idempotencyStore) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := req.Params.Name
+			key := req.GetString(idempotencyKeyParam, "")
+			if key == "" || !idempotentTools[tool] {
+				return next(ctx, req)
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				return nil, fmt.Errorf("%s must be at most %d characters", idempotencyKeyParam, maxIdempotencyKeyLength)
+			}
+
+			args := make(map[string]any, len(req.GetArguments()))
+			for k, v := range req.GetArguments() {
+				if k != idempotencyKeyParam {
+					args[k] = v
+				}
+			}
+			argsJSON, err := json.Marshal(args)
+			if err != nil {
+				return next(ctx, req)
+			}
+
+			storeKey := tool + "\x00" + callerIdentity(ctx) + "\x00" + key
+			for {
+				e, owned := st.acquire(storeKey, string(argsJSON))
+				if owned {
+					if args, res, ok := st.lookupShared(ctx, storeKey); ok {
+						if args != string(argsJSON) {
+							err := fmt.Errorf("%s %q was already used for a %s call with different arguments", idempotencyKeyParam, key, tool)
+							st.release(storeKey, e, nil, err)
+							return nil, err
+						}
+						st.release(storeKey, e, res, nil)
+						st.logger.Info("Returning result of earlier call with the same idempotency key from another replica",
+							zap.String("tool", tool),
+							zap.String("idempotency_key", key),
+						)
+						return res, nil
+					}
+					return st.run(ctx, req, next, storeKey, e)
+				}
+				if e.args != string(argsJSON) {
+					return nil, fmt.Errorf("%s %q was already used for a %s call with different arguments", idempotencyKeyParam, key, tool)
+				}
+
+				select {
+				case <-e.done:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				if e.result != nil {
+					st.logger.Info("Returning result of earlier call with the same idempotency key",
+						zap.String("tool", tool),
+						zap.String("idempotency_key", key),
+					)
+					return e.result, nil
+				}
+				// The earlier attempt failed and was forgotten; try again.
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	st := newIdempotencyStore(zap.NewNop(), nil)
+	st.now = func() time.Time { return now }
+
+	calls := 0
+	fail := false
+	handler := buildIdempotencyMiddleware(st)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		if fail {
+			return nil, errors.New("timeout")
+		}
+		return mcp.NewToolResultText(req.GetString("text", "")), nil
+	})
+
+	call := func(tool string, args map[string]any) (*mcp.CallToolResult, error) {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		return handler(context.Background(), req)
+	}
+	post := map[string]any{"channel_id": "C1", "text": "hello", "idempotency_key": "k1"}
+
+	res, err := call(ToolConversationsAddMessage, post)
+	require.NoError(t, err)
+	again, err := call(ToolConversationsAddMessage, post)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "a retry with the same key should not post again")
+	assert.Same(t, res, again)
+
+	_, err = call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "other", "idempotency_key": "k1"})
+	assert.ErrorContains(t, err, "different arguments")
+
+	_, err = call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "calls without a key are never deduplicated")
+
+	_, err = call(ToolReactionsAdd, map[string]any{"channel_id": "C1", "idempotency_key": "k1"})
+	require.NoError(t, err)
+	_, err = call(ToolReactionsAdd, map[string]any{"channel_id": "C1", "idempotency_key": "k1"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls, "tools without idempotency support pass through")
+
+	fail = true
+	_, err = call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "hello", "idempotency_key": "k2"})
+	require.Error(t, err)
+	fail = false
+	_, err = call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "hello", "idempotency_key": "k2"})
+	require.NoError(t, err)
+	assert.Equal(t, 6, calls, "a failed call is forgotten so its retry runs")
+
+	now = now.Add(idempotencyTTL + time.Second)
+	_, err = call(ToolConversationsAddMessage, post)
+	require.NoError(t, err)
+	assert.Equal(t, 7, calls, "keys expire after the TTL")
+}
+
+func TestIdempotencyMiddlewareConcurrentRetry(t *testing.T) {
+	st := newIdempotencyStore(zap.NewNop(), nil)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	handler := buildIdempotencyMiddleware(st)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		close(started)
+		<-finish
+		return mcp.NewToolResultText("posted"), nil
+	})
+
+	var req mcp.CallToolRequest
+	req.Params.Name = ToolConversationsAddMessage
+	req.Params.Arguments = map[string]any{"channel_id": "C1", "text": "hello", "idempotency_key": "k1"}
+
+	var wg sync.WaitGroup
+	results := make([]*mcp.CallToolResult, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = handler(context.Background(), req)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1], _ = handler(context.Background(), req)
+	}()
+	close(finish)
+	wg.Wait()
+
+	assert.Equal(t, 1, calls, "a retry racing the original waits for it")
+	require.NotNil(t, results[1])
+	assert.Same(t, results[0], results[1])
+}
+
+func TestIdempotencyMiddlewareSharedStore(t *testing.T) {
+	shared := store.NewMemory()
+	calls := 0
+	next := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("posted " + req.GetString("text", "")), nil
+	}
+	replicaA := buildIdempotencyMiddleware(newIdempotencyStore(zap.NewNop(), shared))(next)
+	replicaB := buildIdempotencyMiddleware(newIdempotencyStore(zap.NewNop(), shared))(next)
+
+	call := func(handler server.ToolHandlerFunc, text string) (*mcp.CallToolResult, error) {
+		var req mcp.CallToolRequest
+		req.Params.Name = ToolConversationsAddMessage
+		req.Params.Arguments = map[string]any{"channel_id": "C1", "text": text, "idempotency_key": "k1"}
+		return handler(context.Background(), req)
+	}
+
+	_, err := call(replicaA, "hello")
+	require.NoError(t, err)
+	res, err := call(replicaB, "hello")
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "a retry on another replica returns the shared result")
+	require.Len(t, res.Content, 1)
+	assert.Equal(t, "posted hello", res.Content[0].(mcp.TextContent).Text)
+
+	_, err = call(replicaB, "other")
+	assert.ErrorContains(t, err, "different arguments")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const defaultShutdownGracePeriod = 30 * time.Second
+
+var errShuttingDown = errors.New("server is shutting down, retry the call later")
+
+// drainTracker counts in-flight tool calls so shutdown can wait for them, and
+// rejects calls that arrive once draining has started.
+type drainTracker struct {
+	mu       sync.Mutex
+	draining bool
+	inflight int
+	wg       sync.WaitGroup
+}
+
+func (d *drainTracker) middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			if d.draining {
+				d.mu.Unlock()
+				return nil, errShuttingDown
+			}
+			d.inflight++
+			d.wg.Add(1)
+			d.mu.Unlock()
+
+			defer func() {
+				d.mu.Lock()
+				d.inflight--
+				d.mu.Unlock()
+				d.wg.Done()
+			}()
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// drain stops accepting tool calls and waits for in-flight ones to finish or
+// for ctx to expire, returning how many calls were still running.
+func (d *drainTracker) drain(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return d.inflight, ctx.Err()
+	}
+}
+
+// Run serves the configured transport until ctx is cancelled or the process
+// receives SIGINT/SIGTERM, then shuts down gracefully within the grace period
+// set by SLACK_MCP_SHUTDOWN_GRACE_PERIOD. When reload is enabled, SIGHUP
+// reloads the configuration. addr is ignored for stdio.
+func (s *MCPServer) Run(ctx context.Context, addr string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if s.loadConfig != nil {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		defer signal.Stop(hup)
+		go s.reloadOnSignal(ctx, hup)
+	}
+
+	serve, err := s.prepareTransport(addr)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- serve() }()
+
+	select {
+	case err := <-errCh:
+		// The transport stopped on its own; release background work.
+		_ = s.Shutdown(context.Background())
+		return ignoreClosed(err)
+	case <-ctx.Done():
+	}
+
+	grace := shutdownGracePeriod(s.provider.Config())
+	s.logger.Info("Shutdown requested, draining in-flight tool calls",
+		zap.String("context", "console"),
+		zap.Duration("grace_period", grace),
+	)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+
+	shutdownErr := s.Shutdown(shutdownCtx)
+	select {
+	case err := <-errCh:
+		if err = ignoreClosed(err); err != nil && shutdownErr == nil {
+			shutdownErr = err
+		}
+	case <-shutdownCtx.Done():
+	}
+	return shutdownErr
+}
+
+// Shutdown stops accepting tool calls, waits for in-flight calls to finish,
+// closes SSE streams, WebSocket connections and the HTTP listener, delivers queued webhook events and embedding exports,
+// closes the write journal and the shared store, stops background workers and flushes logs. It is
+// safe to call more than once; only the first call acts.
+func (s *MCPServer) Shutdown(ctx context.Context) error {
+	var shutdownErr error
+	s.shutdownOnce.Do(func() {
+		if remaining, err := s.drain.drain(ctx); err != nil {
+			s.logger.Warn("Grace period expired with tool calls still in flight",
+				zap.String("context", "console"),
+				zap.Int("in_flight", remaining),
+			)
+			shutdownErr = err
+		}
+
+		s.mu.Lock()
+		stopTransport := s.stopTransport
+		s.mu.Unlock()
+		if stopTransport != nil {
+			if err := stopTransport(ctx); err != nil && shutdownErr == nil {
+				shutdownErr = err
+			}
+		}
+
+		if s.webhook != nil {
+			s.webhook.flush(ctx)
+		}
+		if s.embeddings != nil {
+			s.embeddings.Flush(ctx)
+		}
+		if err := s.journal.Close(); err != nil {
+			s.logger.Warn("Failed to close the write journal", zap.Error(err))
+		}
+		if st := s.provider.Store(); st != nil {
+			if err := st.Close(); err != nil {
+				s.logger.Warn("Failed to close the store", zap.Error(err))
+			}
+		}
+		s.stopBackground()
+
+		s.logger.Info("Slack MCP Server stopped",
+			zap.String("context", "console"),
+		)
+		_ = s.logger.Sync()
+	})
+	return shutdownErr
+}
+
+// prepareTransport builds the server for the provider's transport and returns
+// the blocking serve function, recording how to stop it.
+func (s *MCPServer) prepareTransport(addr string) (func() error, error) {
+	switch s.transport {
+	case "stdio":
+		listenCtx, cancel := context.WithCancel(context.Background())
+		s.setStopTransport(func(context.Context) error {
+			cancel()
+			return nil
+		})
+		return func() error {
+			return server.NewStdioServer(s.server).Listen(listenCtx, os.Stdin, os.Stdout)
+		}, nil
+	case "sse":
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		sseServer := s.ServeSSE(":" + port)
+		s.setStopTransport(sseServer.Shutdown)
+		return func() error { return sseServer.Start(addr) }, nil
+	case "http":
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		httpServer := s.ServeHTTP(":" + port)
+		s.setStopTransport(httpServer.Shutdown)
+		return func() error { return httpServer.Start(addr) }, nil
+	case "ws":
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		wsServer := s.ServeWebSocket(":" + port)
+		s.setStopTransport(wsServer.Shutdown)
+		return func() error { return wsServer.Start(addr) }, nil
+	}
+	return nil, errors.New("invalid transport type " + strconv.Quote(s.transport) + ", allowed: stdio, sse, http, ws")
+}
+
+func (s *MCPServer) setStopTransport(fn func(context.Context) error) {
+	s.mu.Lock()
+	s.stopTransport = fn
+	s.mu.Unlock()
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) || errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// shutdownGracePeriod returns how long shutdown waits for in-flight tool
+// calls, set by SLACK_MCP_SHUTDOWN_GRACE_PERIOD. Supports formats: "30s",
+// "1m", "30" (seconds).
+func shutdownGracePeriod(cfg *config.Config) time.Duration {
+	if cfg.ShutdownGracePeriod == "" {
+		return defaultShutdownGracePeriod
+	}
+	d, err := config.ParseDuration(cfg.ShutdownGracePeriod)
+	if err != nil {
+		return defaultShutdownGracePeriod
+	}
+	return d
+}
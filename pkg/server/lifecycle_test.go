@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDrainTracker(t *testing.T) {
+	t.Run("waits for in-flight calls and rejects new ones", func(t *testing.T) {
+		d := &drainTracker{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		handler := d.middleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-release
+			return mcp.NewToolResultText("ok"), nil
+		})
+
+		go func() { _, _ = handler(context.Background(), mcp.CallToolRequest{}) }()
+		<-started
+
+		drained := make(chan error, 1)
+		go func() {
+			_, err := d.drain(context.Background())
+			drained <- err
+		}()
+
+		require.Eventually(t, func() bool {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return d.draining
+		}, time.Second, 10*time.Millisecond)
+
+		_, err := handler(context.Background(), mcp.CallToolRequest{})
+		assert.ErrorIs(t, err, errShuttingDown)
+
+		close(release)
+		select {
+		case err := <-drained:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("drain did not return after in-flight call finished")
+		}
+	})
+
+	t.Run("reports remaining calls when the grace period expires", func(t *testing.T) {
+		d := &drainTracker{}
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{})
+		handler := d.middleware()(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		go func() { _, _ = handler(context.Background(), mcp.CallToolRequest{}) }()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		remaining, err := d.drain(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, remaining)
+	})
+}
+
+func TestShutdownStopsResultCleanup(t *testing.T) {
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	s := &MCPServer{
+		provider:       &provider.ApiProvider{},
+		logger:         zap.NewNop(),
+		drain:          &drainTracker{},
+		stopBackground: stopBackground,
+	}
+	done := make(chan struct{})
+	go func() {
+		handler.NewResultStore(time.Minute, 0).RunCleanup(bgCtx, time.Millisecond, zap.NewNop())
+		close(done)
+	}()
+
+	require.NoError(t, s.Shutdown(context.Background()))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("result cleanup kept running after Shutdown")
+	}
+}
+
+func TestShutdownGracePeriod(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", defaultShutdownGracePeriod},
+		{"10s", 10 * time.Second},
+		{"45", 45 * time.Second},
+		{"0", 0},
+		{"bogus", defaultShutdownGracePeriod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, shutdownGracePeriod(&config.Config{ShutdownGracePeriod: tt.value}))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/version"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+type userServer struct {
+	server   *server.MCPServer
+	lastUsed time.Time
+	// ready is closed once server is built, or err is set.
+	ready chan struct{}
+	err   error
+}
+
+// userServers keeps one tool set per client-supplied Slack token, each backed
+// by its own provider and caches, for multi-user deployments on the http, sse
+// and ws transports.
+type userServers struct {
+	mu         sync.Mutex
+	servers    map[string]*userServer
+	maxClients int
+	cfg        *config.Config
+	logger     *zap.Logger
+	// build authenticates a token and returns its tool set.
+	build func(cfg *config.Config, token string) (*server.MCPServer, error)
+}
+
+// newUserServers returns nil unless SLACK_MCP_MULTI_USER is enabled.
+func newUserServers(cfg *config.Config, logger *zap.Logger) *userServers {
+	if !cfg.MultiUserEnabled() {
+		return nil
+	}
+	if cfg.Transport == "stdio" {
+		logger.Warn("SLACK_MCP_MULTI_USER is ignored for the stdio transport",
+			zap.String("context", "console"),
+		)
+		return nil
+	}
+
+	maxClients := cfg.MultiUserLimit()
+
+	logger.Info("Multi-user mode enabled, clients may supply their own Slack token",
+		zap.String("context", "console"),
+		zap.String("header", auth.SlackTokenHeader),
+		zap.Int("max_clients", maxClients),
+	)
+
+	us := &userServers{
+		servers:    make(map[string]*userServer),
+		maxClients: maxClients,
+		cfg:        cfg,
+		logger:     logger,
+	}
+	us.build = us.buildServer
+	return us
+}
+
+// get returns the tool set of token, building it on first use. The token is
+// authenticated with Slack outside the lock, so a workflow engine switching
+// between many service identities does not hold up the calls of the ones
+// already known; concurrent first calls with one token share one build.
+func (us *userServers) get(ctx context.Context, token string) (*server.MCPServer, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	us.mu.Lock()
+	entry, ok := us.servers[key]
+	if ok {
+		entry.lastUsed = time.Now()
+	} else {
+		if len(us.servers) >= us.maxClients {
+			us.evictOldest()
+		}
+		entry = &userServer{lastUsed: time.Now(), ready: make(chan struct{})}
+		us.servers[key] = entry
+	}
+	cfg := us.cfg
+	us.mu.Unlock()
+
+	if !ok {
+		entry.server, entry.err = us.build(cfg, token)
+		close(entry.ready)
+		if entry.err != nil {
+			// A bad token is not kept; the next call tries again.
+			us.mu.Lock()
+			if us.servers[key] == entry {
+				delete(us.servers, key)
+			}
+			us.mu.Unlock()
+		}
+	}
+
+	select {
+	case <-entry.ready:
+		return entry.server, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// buildServer authenticates token and registers the tools it may use.
+func (us *userServers) buildServer(cfg *config.Config, token string) (*server.MCPServer, error) {
+	p, err := provider.NewForToken(cfg, token, us.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate client Slack token: %w", err)
+	}
+
+	go func() {
+		if err := p.RefreshUsers(context.Background()); err != nil {
+			us.logger.Warn("Failed to warm users cache for client token", zap.Error(err))
+		}
+		if err := p.RefreshChannels(context.Background()); err != nil {
+			us.logger.Warn("Failed to warm channels cache for client token", zap.Error(err))
+		}
+	}()
+
+	s := server.NewMCPServer("Slack MCP Server", version.Version)
+	registerTools(s, p, us.logger, cfg)
+	applyToolMetadata(s)
+	applyScopeFilter(s, p, us.logger)
+	return s, nil
+}
+
+// reset drops every per-client server so the next call of each client
+// rebuilds its tools from cfg. It is a no-op when multi-user mode is off.
+func (us *userServers) reset(cfg *config.Config) {
+	if us == nil {
+		return
+	}
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	us.servers = make(map[string]*userServer)
+	us.cfg = cfg
+}
+
+func (us *userServers) evictOldest() {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for k, entry := range us.servers {
+		if oldestKey == "" || entry.lastUsed.Before(oldest) {
+			oldestKey, oldest = k, entry.lastUsed
+		}
+	}
+	delete(us.servers, oldestKey)
+}
+
+// buildMultiUserMiddleware routes tool calls that carry a per-client Slack
+// token to that client's own tool set. Calls without a token use the shared
+// server token as before.
+func buildMultiUserMiddleware(us *userServers, logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			token := auth.SlackTokenFromContext(ctx)
+			if us == nil || token == "" {
+				if token != "" {
+					logger.Debug("Ignoring per-client Slack token, multi-user mode is disabled",
+						zap.String("tool", req.Params.Name),
+					)
+				}
+				return next(ctx, req)
+			}
+
+			s, err := us.get(ctx, token)
+			if err != nil {
+				return nil, err
+			}
+
+			tool := s.GetTool(req.Params.Name)
+			if tool == nil {
+				return nil, fmt.Errorf("tool %q is not available for the supplied Slack token", req.Params.Name)
+			}
+			return tool.Handler(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMultiUserMiddleware_PassesThroughWithoutToken(t *testing.T) {
+	called := false
+	next := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("shared"), nil
+	}
+
+	us := &userServers{servers: map[string]*userServer{}, maxClients: 1, logger: zap.NewNop()}
+	res, err := buildMultiUserMiddleware(us, zap.NewNop())(next)(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, mcp.NewToolResultText("shared"), res)
+}
+
+func TestMultiUserMiddleware_DisabledIgnoresToken(t *testing.T) {
+	assert.Nil(t, newUserServers(&config.Config{Transport: "http"}, zap.NewNop()))
+	assert.Nil(t, newUserServers(&config.Config{Transport: "stdio", MultiUser: "true"}, zap.NewNop()), "stdio has no per-client headers")
+
+	us := newUserServers(&config.Config{Transport: "http", MultiUser: "true"}, zap.NewNop())
+	require.NotNil(t, us)
+	assert.Equal(t, config.DefaultMultiUserMaxClients, us.maxClients)
+	us = newUserServers(&config.Config{Transport: "http", MultiUser: "1", MultiUserMaxClients: 5}, zap.NewNop())
+	require.NotNil(t, us)
+	assert.Equal(t, 5, us.maxClients)
+}
+
+func TestUserServersEvictOldest(t *testing.T) {
+	now := time.Now()
+	us := &userServers{servers: map[string]*userServer{
+		"a": {lastUsed: now.Add(-time.Minute)},
+		"b": {lastUsed: now.Add(-time.Hour)},
+		"c": {lastUsed: now},
+	}}
+
+	us.evictOldest()
+
+	assert.Len(t, us.servers, 2)
+	assert.NotContains(t, us.servers, "b")
+}
+
+func TestUserServersGetBuildsOncePerToken(t *testing.T) {
+	var builds atomic.Int32
+	release := make(chan struct{})
+	us := &userServers{servers: map[string]*userServer{}, maxClients: 10, logger: zap.NewNop()}
+	us.build = func(_ *config.Config, token string) (*server.MCPServer, error) {
+		builds.Add(1)
+		if token == "xoxb-bad" {
+			return nil, errors.New("invalid_auth")
+		}
+		<-release
+		return server.NewMCPServer(token, "test"), nil
+	}
+
+	var wg sync.WaitGroup
+	got := make([]*server.MCPServer, 3)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := us.get(context.Background(), "xoxb-svc")
+			assert.NoError(t, err)
+			got[i] = s
+		}()
+	}
+	// Another identity is not held up by the build in progress.
+	_, err := us.get(context.Background(), "xoxb-bad")
+	assert.ErrorContains(t, err, "invalid_auth")
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), builds.Load())
+	assert.Same(t, got[0], got[1])
+	assert.Same(t, got[0], got[2])
+
+	_, err = us.get(context.Background(), "xoxb-bad")
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), builds.Load(), "a failed token is tried again")
+	assert.Len(t, us.servers, 1)
+}
@@ -0,0 +1,333 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// maxQuotaClients bounds the clients without a quota entry that are
+// tracked; idle ones are dropped first.
+const maxQuotaClients = 1000
+
+// ErrQuotaExceeded is returned for tool calls and Slack API calls over the
+// caller's quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// quotaWindow counts events in a fixed window: the current minute or the
+// current UTC day.
+type quotaWindow struct {
+	start time.Time
+	count int
+}
+
+// take counts one event at now unless limit is reached; a zero limit never
+// is. It returns when the window resets.
+func (w *quotaWindow) take(now time.Time, length time.Duration, limit int) (bool, time.Time) {
+	if start := now.Truncate(length); !start.Equal(w.start) {
+		w.start, w.count = start, 0
+	}
+	reset := w.start.Add(length)
+	if limit > 0 && w.count >= limit {
+		return false, reset
+	}
+	w.count++
+	return true, reset
+}
+
+// current returns the count of the window that includes now.
+func (w *quotaWindow) current(now time.Time, length time.Duration) int {
+	if !now.Truncate(length).Equal(w.start) {
+		return 0
+	}
+	return w.count
+}
+
+// clientQuota is the usage of one client.
+type clientQuota struct {
+	name           string
+	limits         config.QuotaLimits
+	requestsMinute quotaWindow
+	requestsDay    quotaWindow
+	callsMinute    quotaWindow
+	callsDay       quotaWindow
+	rejected       int
+	lastUsed       time.Time
+}
+
+// quotaTracker enforces the quotas of the config file per client. Clients
+// listed there are identified by their bearer token, every other client by
+// a hash of its bearer and Slack tokens.
+type quotaTracker struct {
+	mu      sync.Mutex
+	cfg     config.QuotaConfig
+	byKey   map[string]string
+	clients map[string]*clientQuota
+	now     func() time.Time
+}
+
+func newQuotaTracker(cfg config.QuotaConfig) *quotaTracker {
+	q := &quotaTracker{clients: make(map[string]*clientQuota), now: time.Now}
+	q.configure(cfg)
+	return q
+}
+
+// configure applies cfg, keeping the usage counted so far, and registers
+// the client keys with the auth middleware.
+func (q *quotaTracker) configure(cfg config.QuotaConfig) {
+	if q == nil {
+		return
+	}
+	byKey := make(map[string]string, len(cfg.Clients))
+	keys := make([]string, 0, len(cfg.Clients))
+	for _, c := range cfg.Clients {
+		byKey[c.APIKey] = c.Name
+		keys = append(keys, c.APIKey)
+	}
+	auth.SetClientKeys(keys)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cfg, q.byKey = cfg, byKey
+	for id, c := range q.clients {
+		c.limits = q.limitsLocked(id)
+	}
+}
+
+func (q *quotaTracker) enabled() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.cfg.Enabled()
+}
+
+// clientID names the caller: a configured client's name, a hash of its
+// tokens, or "default" when the request carried neither.
+func (q *quotaTracker) clientID(ctx context.Context) string {
+	bearer := auth.BearerFromContext(ctx)
+	q.mu.Lock()
+	name, ok := q.byKey[bearer]
+	q.mu.Unlock()
+	if ok {
+		return name
+	}
+	slackToken := auth.SlackTokenFromContext(ctx)
+	if bearer == "" && slackToken == "" {
+		return "default"
+	}
+	sum := sha256.Sum256([]byte(bearer + "\x00" + slackToken))
+	return "client-" + hex.EncodeToString(sum[:6])
+}
+
+// limitsLocked returns the limits of the configured client id, or the
+// default limits.
+func (q *quotaTracker) limitsLocked(id string) config.QuotaLimits {
+	if c, ok := q.namedLocked(id); ok {
+		return c.QuotaLimits
+	}
+	return q.cfg.Default
+}
+
+// clientLocked returns the usage record of id, creating it on first use.
+func (q *quotaTracker) clientLocked(id string, now time.Time) *clientQuota {
+	c, ok := q.clients[id]
+	if !ok {
+		if len(q.clients) >= maxQuotaClients {
+			q.evictLocked()
+		}
+		c = &clientQuota{name: id, limits: q.limitsLocked(id)}
+		q.clients[id] = c
+	}
+	c.lastUsed = now
+	return c
+}
+
+// evictLocked drops the least recently used unnamed client.
+func (q *quotaTracker) evictLocked() {
+	var oldest *clientQuota
+	for id, c := range q.clients {
+		if _, named := q.namedLocked(id); named {
+			continue
+		}
+		if oldest == nil || c.lastUsed.Before(oldest.lastUsed) {
+			oldest = c
+		}
+	}
+	if oldest != nil {
+		delete(q.clients, oldest.name)
+	}
+}
+
+func (q *quotaTracker) namedLocked(id string) (config.QuotaClient, bool) {
+	for _, c := range q.cfg.Clients {
+		if c.Name == id {
+			return c, true
+		}
+	}
+	return config.QuotaClient{}, false
+}
+
+// startCall counts a tool call of the caller and returns the budget its
+// Slack API calls are charged to.
+func (q *quotaTracker) startCall(ctx context.Context) (*slackCallBudget, error) {
+	id := q.clientID(ctx)
+	now := q.now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c := q.clientLocked(id, now)
+	if ok, reset := c.requestsMinute.take(now, time.Minute, c.limits.RequestsPerMinute); !ok {
+		c.rejected++
+		return nil, quotaError(id, c.limits.RequestsPerMinute, "tool calls per minute", reset)
+	}
+	if ok, reset := c.requestsDay.take(now, 24*time.Hour, c.limits.RequestsPerDay); !ok {
+		c.requestsMinute.count--
+		c.rejected++
+		return nil, quotaError(id, c.limits.RequestsPerDay, "tool calls per day", reset)
+	}
+	return &slackCallBudget{q: q, id: id}, nil
+}
+
+// spend counts one Slack API call of client id.
+func (q *quotaTracker) spend(id string) error {
+	now := q.now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	c := q.clientLocked(id, now)
+	if ok, reset := c.callsMinute.take(now, time.Minute, c.limits.SlackCallsPerMinute); !ok {
+		c.rejected++
+		return quotaError(id, c.limits.SlackCallsPerMinute, "Slack API calls per minute", reset)
+	}
+	if ok, reset := c.callsDay.take(now, 24*time.Hour, c.limits.SlackCallsPerDay); !ok {
+		c.callsMinute.count--
+		c.rejected++
+		return quotaError(id, c.limits.SlackCallsPerDay, "Slack API calls per day", reset)
+	}
+	return nil
+}
+
+func quotaError(id string, limit int, what string, reset time.Time) error {
+	return fmt.Errorf("%w: client %q is limited to %d %s, retry after %s",
+		ErrQuotaExceeded, id, limit, what, reset.UTC().Format(time.RFC3339))
+}
+
+// slackCallBudget charges the Slack API calls of one tool call to its
+// client.
+type slackCallBudget struct {
+	q  *quotaTracker
+	id string
+}
+
+// Spend implements transport.CallBudget.
+func (b *slackCallBudget) Spend() error {
+	return b.q.spend(b.id)
+}
+
+// QuotaUsage is one client's entry in the quotas resource.
+type QuotaUsage struct {
+	Client               string             `json:"client"`
+	Named                bool               `json:"named"`
+	Limits               config.QuotaLimits `json:"limits"`
+	RequestsThisMinute   int                `json:"requests_this_minute"`
+	RequestsToday        int                `json:"requests_today"`
+	SlackCallsThisMinute int                `json:"slack_calls_this_minute"`
+	SlackCallsToday      int                `json:"slack_calls_today"`
+	Rejected             int                `json:"rejected"`
+}
+
+// usage reports every tracked client, named clients first.
+func (q *quotaTracker) usage() []QuotaUsage {
+	now := q.now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]QuotaUsage, 0, len(q.clients)+len(q.cfg.Clients))
+	seen := make(map[string]bool, len(q.clients))
+	add := func(id string, limits config.QuotaLimits, named bool) {
+		u := QuotaUsage{Client: id, Named: named, Limits: limits}
+		if c, ok := q.clients[id]; ok {
+			u.RequestsThisMinute = c.requestsMinute.current(now, time.Minute)
+			u.RequestsToday = c.requestsDay.current(now, 24*time.Hour)
+			u.SlackCallsThisMinute = c.callsMinute.current(now, time.Minute)
+			u.SlackCallsToday = c.callsDay.current(now, 24*time.Hour)
+			u.Rejected = c.rejected
+		}
+		seen[id] = true
+		out = append(out, u)
+	}
+	for _, c := range q.cfg.Clients {
+		add(c.Name, c.QuotaLimits, true)
+	}
+	ids := make([]string, 0, len(q.clients))
+	for id := range q.clients {
+		if !seen[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		add(id, q.cfg.Default, false)
+	}
+	return out
+}
+
+// buildQuotaMiddleware rejects tool calls over the caller's quota and
+// charges the Slack API calls they make. It runs after authentication, so
+// rejected credentials do not use up anyone's quota.
+func buildQuotaMiddleware(q *quotaTracker, logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !q.enabled() {
+				return next(ctx, req)
+			}
+			budget, err := q.startCall(ctx)
+			if err != nil {
+				logger.Warn("Tool call rejected by quota",
+					zap.String("tool", req.Params.Name),
+					zap.Error(err),
+				)
+				return nil, err
+			}
+			return next(transport.WithCallBudget(ctx, budget), req)
+		}
+	}
+}
+
+// buildQuotasResource serves the usage of every client against its quota.
+func buildQuotasResource(q *quotaTracker, ap *provider.ApiProvider, logger *zap.Logger) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Debug("QuotasResource called", zap.Any("params", request.Params))
+
+		if authenticated, err := auth.IsAuthenticated(ctx, ap.ServerTransport(), logger); !authenticated {
+			logger.Error("Authentication failed for quotas resource", zap.Error(err))
+			return nil, err
+		}
+
+		data, err := json.Marshal(q.usage())
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestQuotaMiddleware(t *testing.T) {
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer slack.Close()
+	client := &http.Client{Transport: transport.NewCallBudgetTransport(http.DefaultTransport)}
+
+	now := time.Date(2026, 3, 2, 10, 15, 30, 0, time.UTC)
+	q := newQuotaTracker(config.QuotaConfig{
+		Default: config.QuotaLimits{RequestsPerMinute: 1},
+		Clients: []config.QuotaClient{
+			{Name: "ci", APIKey: "ci-key", QuotaLimits: config.QuotaLimits{RequestsPerDay: 3, SlackCallsPerMinute: 2}},
+		},
+	})
+	defer auth.SetClientKeys(nil)
+	q.now = func() time.Time { return now }
+
+	slackCalls := 2
+	handler := buildQuotaMiddleware(q, zap.NewNop())(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for i := 0; i < slackCalls; i++ {
+			r, err := http.NewRequestWithContext(ctx, http.MethodPost, slack.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(r)
+			if err != nil {
+				return nil, err
+			}
+			_ = resp.Body.Close()
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(bearer string) error {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if bearer != "" {
+			r.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		ctx := auth.AuthFromRequest(zap.NewNop())(context.Background(), r)
+		var req mcp.CallToolRequest
+		req.Params.Name = ToolChannelsList
+		_, err := handler(ctx, req)
+		return err
+	}
+
+	require.NoError(t, call("ci-key"))
+	err := call("ci-key")
+	require.ErrorIs(t, err, ErrQuotaExceeded, "the third Slack call this minute is over budget")
+	assert.ErrorContains(t, err, `client "ci" is limited to 2 Slack API calls per minute, retry after 2026-03-02T10:16:00Z`)
+
+	now = now.Add(time.Minute)
+	slackCalls = 0
+	require.NoError(t, call("ci-key"))
+	err = call("ci-key")
+	assert.ErrorContains(t, err, "3 tool calls per day, retry after 2026-03-03T00:00:00Z")
+
+	require.NoError(t, call(""), "clients without a quota entry get the default limits")
+	assert.ErrorContains(t, call(""), `client "default" is limited to 1 tool calls per minute`)
+	require.NoError(t, call("someone-else"), "every unnamed client has its own counters")
+
+	usage := q.usage()
+	require.Len(t, usage, 3)
+	assert.Equal(t, QuotaUsage{
+		Client:             "ci",
+		Named:              true,
+		Limits:             config.QuotaLimits{RequestsPerDay: 3, SlackCallsPerMinute: 2},
+		RequestsThisMinute: 1,
+		RequestsToday:      3,
+		SlackCallsToday:    2,
+		Rejected:           2,
+	}, usage[0])
+	out, err := json.Marshal(usage[1:])
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"client":"default"`)
+	assert.Contains(t, string(out), `"rejected":1`)
+}
+
+func TestQuotaMiddlewareDisabled(t *testing.T) {
+	q := newQuotaTracker(config.QuotaConfig{})
+	handler := buildQuotaMiddleware(q, zap.NewNop())(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	})
+	for i := 0; i < 3; i++ {
+		_, err := handler(context.Background(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+	}
+	assert.Empty(t, q.usage(), "nothing is tracked without quotas")
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/text"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// redactionsMetaKey is the _meta field of a tool result that counts its
+// redactions by rule.
+const redactionsMetaKey = "redactions"
+
+// redactors compiles the redaction rules of the current configuration once
+// per configuration, so a reload applies on the next call.
+type redactors struct {
+	cfg    func() *config.Config
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	loadedOf *config.Config
+	redactor *text.Redactor
+}
+
+func newRedactors(cfg func() *config.Config, logger *zap.Logger) *redactors {
+	return &redactors{cfg: cfg, logger: logger}
+}
+
+func (r *redactors) get() *text.Redactor {
+	cfg := r.cfg()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cfg != r.loadedOf {
+		redactor, err := cfg.Redactor()
+		if err != nil {
+			// Validate rejects such a config before it is applied.
+			r.logger.Error("Invalid redaction rules", zap.Error(err))
+		}
+		r.loadedOf, r.redactor = cfg, redactor
+	}
+	return r.redactor
+}
+
+// logRedactions records what was masked, for auditing.
+func (r *redactors) logRedactions(kind, name string, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	r.logger.Info("Redacted sensitive content",
+		zap.String(kind, name),
+		zap.Any(redactionsMetaKey, counts),
+	)
+}
+
+// buildRedactionMiddleware masks the text of every tool result with the
+// SLACK_MCP_REDACT and redact_patterns rules. It runs inside the response
+// cache and result offloading so neither ever holds unmasked content, and
+// counts the redactions in the result's _meta.
+func buildRedactionMiddleware(r *redactors) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			res, err := next(ctx, req)
+			redactor := r.get()
+			if err != nil || res == nil || !redactor.Enabled() {
+				return res, err
+			}
+
+			counts := make(map[string]int)
+			for i, content := range res.Content {
+				switch c := content.(type) {
+				case mcp.TextContent:
+					c.Text = redactor.Redact(c.Text, counts)
+					res.Content[i] = c
+				case mcp.EmbeddedResource:
+					if tr, ok := c.Resource.(mcp.TextResourceContents); ok {
+						tr.Text = redactor.Redact(tr.Text, counts)
+						c.Resource = tr
+						res.Content[i] = c
+					}
+				}
+			}
+			if len(counts) > 0 {
+				if res.Meta == nil {
+					res.Meta = &mcp.Meta{}
+				}
+				if res.Meta.AdditionalFields == nil {
+					res.Meta.AdditionalFields = make(map[string]any)
+				}
+				res.Meta.AdditionalFields[redactionsMetaKey] = counts
+				r.logRedactions("tool", req.Params.Name, counts)
+			}
+			return res, nil
+		}
+	}
+}
+
+// buildResourceRedactionMiddleware masks the text of every resource as
+// buildRedactionMiddleware does tool results.
+func buildResourceRedactionMiddleware(r *redactors) server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			contents, err := next(ctx, req)
+			redactor := r.get()
+			if err != nil || !redactor.Enabled() {
+				return contents, err
+			}
+
+			counts := make(map[string]int)
+			for i, content := range contents {
+				if tr, ok := content.(mcp.TextResourceContents); ok {
+					tr.Text = redactor.Redact(tr.Text, counts)
+					contents[i] = tr
+				}
+			}
+			r.logRedactions("uri", req.Params.URI, counts)
+			return contents, nil
+		}
+	}
+}
+
+// resultRedactions returns the redaction counts buildRedactionMiddleware
+// recorded in res.
+func resultRedactions(res *mcp.CallToolResult) map[string]int {
+	if res == nil || res.Meta == nil {
+		return nil
+	}
+	counts, _ := res.Meta.AdditionalFields[redactionsMetaKey].(map[string]int)
+	return counts
+}
+
+// BuildPolicyMiddleware applies the tools config argument restrictions and
+// the redaction rules of cfg to a handler run without the server, as the
+// export command does, the way serve applies them to every tool call.
+func BuildPolicyMiddleware(cfg *config.Config, toolsConfig *ToolsConfig, p *provider.ApiProvider, logger *zap.Logger) server.ToolHandlerMiddleware {
+	restrict := buildToolRestrictionsMiddleware(func() *ToolsConfig { return toolsConfig }, p, logger)
+	redact := buildRedactionMiddleware(newRedactors(func() *config.Config { return cfg }, logger))
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return restrict(redact(next))
+	}
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/korotovsky/slack-mcp-server/pkg/version"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// adminReloadPath is served on the SSE and HTTP transports when reload is
+// enabled and an API key is configured.
+const adminReloadPath = "/admin/reload"
+
+// PrepareConfig validates cfg and resolves its enabled tools, loading the
+// tools config when one is set. The returned tools config is nil when unset.
+func PrepareConfig(cfg *config.Config) (*ToolsConfig, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ValidateEnabledTools(cfg.EnabledTools); err != nil {
+		return nil, fmt.Errorf("error in SLACK_MCP_ENABLED_TOOLS: %w", err)
+	}
+	if cfg.ToolsConfig == "" {
+		return nil, nil
+	}
+
+	toolsConfig, err := LoadToolsConfig(cfg.ToolsConfig, cfg.ToolsGroups)
+	if err == nil {
+		cfg.EnabledTools, err = toolsConfig.ResolveEnabledTools(cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error in SLACK_MCP_TOOLS_CONFIG: %w", err)
+	}
+	return toolsConfig, nil
+}
+
+// EnableReload lets SIGHUP and POST /admin/reload reload the configuration
+// from args, the environment and the config files the same way as on startup.
+func (s *MCPServer) EnableReload(args []string) {
+	s.loadConfig = func() (*config.Config, *ToolsConfig, error) {
+		cfg, err := config.Load(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		toolsConfig, err := PrepareConfig(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return cfg, toolsConfig, nil
+	}
+}
+
+// Reload re-reads the configuration and applies it without a restart, so
+// connected SSE and HTTP sessions survive. On error the running configuration
+// is kept.
+func (s *MCPServer) Reload() error {
+	if s.loadConfig == nil {
+		return errors.New("reload is not enabled")
+	}
+	cfg, toolsConfig, err := s.loadConfig()
+	if err != nil {
+		return err
+	}
+	return s.apply(cfg, toolsConfig)
+}
+
+// apply switches the provider to cfg, rebuilds the tool set on a staging
+// server and swaps it in, which notifies clients via tools/list_changed.
+// Transport, host and port are bound at startup and keep their values.
+func (s *MCPServer) apply(cfg *config.Config, toolsConfig *ToolsConfig) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	current := s.provider.Config()
+	if cfg.Transport != current.Transport || cfg.Host != current.Host || cfg.Port != current.Port {
+		s.logger.Warn("Transport, host and port changes need a restart, keeping the current values",
+			zap.String("context", "console"),
+		)
+		cfg.Transport, cfg.Host, cfg.Port = current.Transport, current.Host, current.Port
+	}
+
+	if err := s.provider.ApplyConfig(cfg); err != nil {
+		return err
+	}
+	s.toolsConfig.Store(toolsConfig)
+
+	staging := server.NewMCPServer("Slack MCP Server", version.Version)
+	registerTools(staging, s.provider, s.logger, cfg)
+	applyToolMetadata(staging)
+	applyDescriptionOverrides(staging, toolsConfig, s.logger)
+	caps := applyScopeFilter(staging, s.provider, s.logger)
+	tools := make([]server.ServerTool, 0, len(caps.Tools))
+	for _, name := range caps.Tools {
+		tools = append(tools, *staging.GetTool(name))
+	}
+	s.server.SetTools(tools...)
+	s.caps.Store(caps)
+
+	s.users.reset(cfg)
+	s.cache.clear()
+	s.digests.Configure(cfg.Digests)
+	s.quotas.configure(cfg.Quotas)
+	setAPIKey(cfg, s.logger)
+
+	s.logger.Info("Configuration reloaded",
+		zap.String("context", "console"),
+		zap.Strings("tools", caps.Tools),
+	)
+	return nil
+}
+
+// reloadOnSignal reloads on every signal received from sig until ctx ends.
+func (s *MCPServer) reloadOnSignal(ctx context.Context, sig <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			s.logger.Info("SIGHUP received, reloading configuration",
+				zap.String("context", "console"),
+			)
+			if err := s.Reload(); err != nil {
+				s.logger.Error("Failed to reload configuration, keeping the current one",
+					zap.String("context", "console"),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+// adminReloadHandler serves POST /admin/reload, authenticated like tool calls
+// with the SLACK_MCP_API_KEY bearer token, and responds with the capabilities
+// after the reload. It returns nil when reload is not enabled or no API key
+// is set, so the endpoint is never exposed unauthenticated.
+func (s *MCPServer) adminReloadHandler() http.Handler {
+	if s.loadConfig == nil || !auth.APIKeyConfigured() {
+		return nil
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		ctx := auth.AuthFromRequest(s.logger)(r.Context(), r)
+		if authenticated, err := auth.IsAuthenticated(ctx, s.transport, s.logger); !authenticated {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if err := s.Reload(); err != nil {
+			s.logger.Error("Failed to reload configuration, keeping the current one",
+				zap.String("context", "http"),
+				zap.Error(err),
+			)
+			http.Error(w, "reload failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.caps.Load())
+	})
+}
+
+// withAdminRoutes serves the admin endpoints next to the transport handler,
+// outside the origin policy since they are not called from browsers.
+func withAdminRoutes(transport, admin http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", transport)
+	mux.Handle(adminReloadPath, admin)
+	return mux
+}
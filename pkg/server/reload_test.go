@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newReloadTestServer(t *testing.T) *MCPServer {
+	t.Helper()
+	for _, name := range []string{"SLACK_MCP_XOXP_TOKEN", "SLACK_MCP_XOXB_TOKEN", "SLACK_MCP_XOXC_TOKEN", "SLACK_MCP_XOXD_TOKEN"} {
+		t.Setenv(name, "")
+	}
+
+	s := &MCPServer{
+		server:    server.NewMCPServer("test", "0"),
+		provider:  &provider.ApiProvider{},
+		logger:    zap.NewNop(),
+		transport: "http",
+	}
+	require.NoError(t, s.apply(offlineConfig(), nil))
+	return s
+}
+
+// offlineConfig is the default configuration without the scope check, which
+// needs a Slack client.
+func offlineConfig() *config.Config {
+	cfg := config.Default()
+	cfg.ScopeCheck = "false"
+	return cfg
+}
+
+func TestReloadReplacesTools(t *testing.T) {
+	s := newReloadTestServer(t)
+	assert.Contains(t, s.server.ListTools(), ToolChannelsList)
+	assert.Contains(t, s.server.ListTools(), ToolConversationsHistory)
+
+	cfg := offlineConfig()
+	cfg.EnabledTools = []string{ToolChannelsList}
+	cfg.ChannelDenylist = []string{"#hr-*"}
+	toolsConfig := &ToolsConfig{}
+	require.NoError(t, s.apply(cfg, toolsConfig))
+
+	assert.Contains(t, s.server.ListTools(), ToolChannelsList)
+	assert.NotContains(t, s.server.ListTools(), ToolConversationsHistory)
+	assert.Contains(t, s.caps.Load().Tools, ToolChannelsList)
+	assert.NotContains(t, s.caps.Load().Tools, ToolConversationsHistory)
+	assert.Same(t, toolsConfig, s.toolsConfig.Load())
+	assert.Same(t, cfg, s.provider.Config())
+	assert.NotNil(t, s.provider.ChannelPolicy())
+}
+
+func TestReloadKeepsTransport(t *testing.T) {
+	s := newReloadTestServer(t)
+
+	cfg := offlineConfig()
+	cfg.Transport = "sse"
+	cfg.Port = 9999
+	require.NoError(t, s.apply(cfg, nil))
+
+	assert.Equal(t, config.DefaultTransport, s.provider.Config().Transport)
+	assert.Equal(t, config.DefaultPort, s.provider.Config().Port)
+}
+
+func TestAdminReloadHandler(t *testing.T) {
+	s := newReloadTestServer(t)
+	reloads := 0
+	s.loadConfig = func() (*config.Config, *ToolsConfig, error) {
+		reloads++
+		cfg := offlineConfig()
+		cfg.EnabledTools = []string{ToolChannelsList}
+		return cfg, nil, nil
+	}
+
+	t.Cleanup(func() { auth.SetAPIKey("") })
+	auth.SetAPIKey("")
+	assert.Nil(t, s.adminReloadHandler(), "never exposed without an API key")
+
+	auth.SetAPIKey("secret")
+	h := s.adminReloadHandler()
+	require.NotNil(t, h)
+
+	call := func(method, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, adminReloadPath, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, call(http.MethodGet, "secret").Code)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodPost, "wrong").Code)
+	assert.Equal(t, 0, reloads)
+
+	rec := call(http.MethodPost, "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"tools":["channels_list"`)
+	assert.NotContains(t, s.server.ListTools(), ToolConversationsHistory)
+	assert.Equal(t, 1, reloads)
+}
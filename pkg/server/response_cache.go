@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const maxResponseCacheEntries = 1000
+
+// defaultResponseCacheTTLs lists the read-only tools whose responses are
+// cached and for how long. Override with SLACK_MCP_RESPONSE_CACHE_TTLS.
+var defaultResponseCacheTTLs = map[string]time.Duration{
+	ToolChannelsList:   5 * time.Minute,
+	"users_search":     5 * time.Minute,
+	ToolUsergroupsList: 5 * time.Minute,
+	ToolUsergroupsMe:   2 * time.Minute,
+	ToolUsersStatusGet: time.Minute,
+	ToolTeamInfo:       10 * time.Minute,
+}
+
+// responseCacheBypassArgs lists arguments that make a call's result too
+// short-lived to cache, such as who is online right now.
+var responseCacheBypassArgs = map[string][]string{
+	"users_search": {"presence", "include_presence", "dnd", "out_of_office"},
+}
+
+// responseCacheInvalidations maps write tools to the cached tools whose
+// responses they may change. A successful call drops those entries.
+var responseCacheInvalidations = map[string][]string{
+	ToolConversationsAddMessage:     {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext},
+	ToolConversationsAddMessages:    {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext},
+	ToolConversationsForwardMessage: {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext},
+	ToolConversationsCleanup:        {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext},
+	ToolHuddlesStart:                {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext},
+	ToolThreadsFollow:               {ToolActivityThreads},
+	ToolThreadsUnfollow:             {ToolActivityThreads},
+	ToolAdminUsersRemove:            {"users_search", ToolChannelsList},
+	ToolConversationsOpenDM:         {ToolChannelsList},
+	ToolConversationsInvite:         {ToolChannelsList},
+	ToolConversationsSetTopic:       {ToolChannelsList},
+	ToolConversationsSetPurpose:     {ToolChannelsList},
+	ToolReactionsAdd:                {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext, ToolReactionsSearch},
+	ToolReactionsRemove:             {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext, ToolReactionsSearch},
+	ToolUsergroupsCreate:            {ToolUsergroupsList, ToolUsergroupsMe},
+	ToolUsergroupsUpdate:            {ToolUsergroupsList, ToolUsergroupsMe},
+	ToolUsergroupsUsersUpdate:       {ToolUsergroupsList, ToolUsergroupsMe},
+	ToolUsergroupsUsersModify:       {ToolUsergroupsList, ToolUsergroupsMe},
+	ToolUsergroupsDisable:           {ToolUsergroupsList, ToolUsergroupsMe},
+	ToolUsersStatusSet:              {ToolUsersStatusGet},
+	ToolUsersProfileSet:             {"users_search"},
+	ToolReactionsAddBulk:            {ToolConversationsHistory, ToolConversationsDMHistory, ToolConversationsReplies, ToolThreadSearch, ToolThreadParticipants, ToolConversationsContext, ToolReactionsSearch},
+	ToolSavedComplete:               {ToolSavedList},
+}
+
+type cachedResponse struct {
+	tool      string
+	result    *mcp.CallToolResult
+	expiresAt time.Time
+}
+
+// responseCache memoizes tool results keyed on tool, arguments and the Slack
+// identity making the call, so repeated identical calls within a session skip
+// the Slack API.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+	ttls    map[string]time.Duration
+	now     func() time.Time
+	logger  *zap.Logger
+}
+
+// newResponseCache returns nil unless SLACK_MCP_RESPONSE_CACHE is enabled.
+// SLACK_MCP_RESPONSE_CACHE_TTLS adjusts per-tool TTLs, e.g.
+// "channels_list=10m,conversations_history=30s,users_search=0" (0 disables).
+func newResponseCache(cfg *config.Config, logger *zap.Logger) *responseCache {
+	if !cfg.ResponseCacheEnabled() {
+		return nil
+	}
+
+	ttls := make(map[string]time.Duration, len(defaultResponseCacheTTLs))
+	for tool, ttl := range defaultResponseCacheTTLs {
+		ttls[tool] = ttl
+	}
+	for tool, ttl := range cfg.ResponseCacheTTLOverrides() {
+		if ttl <= 0 {
+			delete(ttls, tool)
+			continue
+		}
+		ttls[tool] = ttl
+	}
+
+	logger.Info("Response cache enabled",
+		zap.String("context", "console"),
+		zap.Int("cached_tools", len(ttls)),
+	)
+
+	return &responseCache{
+		entries: make(map[string]cachedResponse),
+		ttls:    ttls,
+		now:     time.Now,
+		logger:  logger,
+	}
+}
+
+func (rc *responseCache) key(ctx context.Context, req mcp.CallToolRequest) (string, error) {
+	args, err := json.Marshal(req.GetArguments())
+	if err != nil {
+		return "", err
+	}
+	return req.Params.Name + "\x00" + callerIdentity(ctx) + "\x00" + string(args), nil
+}
+
+// callerIdentity returns a hash of the Slack token of the call, or "" when
+// the server's own token is used.
+func callerIdentity(ctx context.Context) string {
+	token := auth.SlackTokenFromContext(ctx)
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (rc *responseCache) get(key string) (*mcp.CallToolResult, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if rc.now().After(entry.expiresAt) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (rc *responseCache) put(key, tool string, res *mcp.CallToolResult, ttl time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := rc.now()
+	if len(rc.entries) >= maxResponseCacheEntries {
+		for k, entry := range rc.entries {
+			if now.After(entry.expiresAt) {
+				delete(rc.entries, k)
+			}
+		}
+		if len(rc.entries) >= maxResponseCacheEntries {
+			rc.entries = make(map[string]cachedResponse)
+		}
+	}
+	rc.entries[key] = cachedResponse{tool: tool, result: res, expiresAt: now.Add(ttl)}
+}
+
+// invalidate drops every cached response of the given tools.
+func (rc *responseCache) invalidate(tools []string) int {
+	drop := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		drop[t] = true
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	removed := 0
+	for k, entry := range rc.entries {
+		if drop[entry.tool] {
+			delete(rc.entries, k)
+			removed++
+		}
+	}
+	return removed
+}
+
+// clear drops every cached response. It is a no-op when the cache is off.
+func (rc *responseCache) clear() {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = make(map[string]cachedResponse)
+}
+
+func buildResponseCacheMiddleware(rc *responseCache) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if rc == nil {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			tool := req.Params.Name
+
+			ttl, cacheable := rc.ttls[tool]
+			for _, arg := range responseCacheBypassArgs[tool] {
+				if v := req.GetArguments()[arg]; v != nil && v != "" {
+					cacheable = false
+				}
+			}
+			var key string
+			if cacheable {
+				var err error
+				if key, err = rc.key(ctx, req); err != nil {
+					cacheable = false
+				} else if res, ok := rc.get(key); ok {
+					rc.logger.Debug("Serving tool result from response cache", zap.String("tool", tool))
+					return res, nil
+				}
+			}
+
+			res, err := next(ctx, req)
+			if err != nil || res == nil || res.IsError {
+				return res, err
+			}
+
+			if cacheable {
+				rc.put(key, tool, res, ttl)
+			}
+			if targets, ok := responseCacheInvalidations[tool]; ok {
+				if removed := rc.invalidate(targets); removed > 0 {
+					rc.logger.Debug("Invalidated cached tool results",
+						zap.String("tool", tool),
+						zap.Int("removed", removed),
+					)
+				}
+			}
+			return res, nil
+		}
+	}
+}
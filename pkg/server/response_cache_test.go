@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResponseCacheMiddleware(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rc := &responseCache{
+		entries: make(map[string]cachedResponse),
+		ttls:    map[string]time.Duration{ToolUsergroupsList: time.Minute, "users_search": time.Minute},
+		now:     func() time.Time { return now },
+		logger:  zap.NewNop(),
+	}
+
+	calls := 0
+	handler := buildResponseCacheMiddleware(rc)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(tool string, args map[string]any) {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		_, err := handler(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	call(ToolUsergroupsList, map[string]any{"include_users": true})
+	call(ToolUsergroupsList, map[string]any{"include_users": true})
+	assert.Equal(t, 1, calls, "identical call should be served from cache")
+
+	call(ToolUsergroupsList, map[string]any{"include_users": false})
+	assert.Equal(t, 2, calls, "different arguments must not share an entry")
+
+	call(ToolUsergroupsCreate, map[string]any{"name": "x"})
+	assert.Equal(t, 3, calls)
+	call(ToolUsergroupsList, map[string]any{"include_users": true})
+	assert.Equal(t, 4, calls, "write tool should invalidate cached list")
+
+	now = now.Add(2 * time.Minute)
+	call(ToolUsergroupsList, map[string]any{"include_users": true})
+	assert.Equal(t, 5, calls, "expired entry should be refetched")
+
+	call(ToolConversationsHistory, map[string]any{"channel_id": "C1"})
+	call(ToolConversationsHistory, map[string]any{"channel_id": "C1"})
+	assert.Equal(t, 7, calls, "tools without a TTL are never cached")
+
+	call("users_search", map[string]any{"query": "ana", "presence": "active"})
+	call("users_search", map[string]any{"query": "ana", "presence": "active"})
+	assert.Equal(t, 9, calls, "availability filters are never served from cache")
+	call("users_search", map[string]any{"query": "ana"})
+	call("users_search", map[string]any{"query": "ana"})
+	assert.Equal(t, 10, calls)
+}
+
+func TestNewResponseCache(t *testing.T) {
+	assert.Nil(t, newResponseCache(&config.Config{}, zap.NewNop()))
+
+	rc := newResponseCache(&config.Config{
+		ResponseCache:     "true",
+		ResponseCacheTTLs: []string{"channels_list=10m", "users_search=0", "conversations_history=30s", "bogus"},
+	}, zap.NewNop())
+	require.NotNil(t, rc)
+	assert.Equal(t, 10*time.Minute, rc.ttls[ToolChannelsList])
+	assert.Equal(t, 30*time.Second, rc.ttls[ToolConversationsHistory])
+	_, ok := rc.ttls["users_search"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/slack-go/slack"
+)
+
+// SelfTestCheck is the outcome of one step of the self-test.
+type SelfTestCheck struct {
+	Name     string
+	Status   string // "pass", "fail" or "skip"
+	Detail   string
+	Duration time.Duration
+}
+
+// SelfTestReport is what --self-test and token check print.
+type SelfTestReport struct {
+	Checks       []SelfTestCheck
+	Capabilities *Capabilities
+
+	// title heads the printed report, the self-test's when empty.
+	title string
+}
+
+// skipCheck marks a self-test step that did not apply.
+type skipCheck string
+
+func (s skipCheck) Error() string { return string(s) }
+
+// OK reports whether no check failed.
+func (r *SelfTestReport) OK() bool {
+	for _, c := range r.Checks {
+		if c.Status == "fail" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *SelfTestReport) run(name string, check func() (string, error)) bool {
+	start := time.Now()
+	detail, err := check()
+	c := SelfTestCheck{Name: name, Status: "pass", Detail: detail, Duration: time.Since(start)}
+
+	var skip skipCheck
+	switch {
+	case errors.As(err, &skip):
+		c.Status, c.Detail = "skip", skip.Error()
+	case err != nil:
+		c.Status, c.Detail = "fail", err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+	return c.Status != "fail"
+}
+
+// SelfTest checks the token end to end: auth.test, the users and channels
+// cache warm-up, reading one message and, when conversations_add_message is
+// registered, everything a post would check short of posting. channel is read
+// and checked for posting; when empty, the first channel the token is a
+// member of is read and only the posting policy is checked.
+func (s *MCPServer) SelfTest(ctx context.Context, channel string) *SelfTestReport {
+	ap := s.provider
+	r := &SelfTestReport{Capabilities: s.caps.Load()}
+
+	if !r.authTest(ctx, ap) {
+		return r
+	}
+
+	r.run("users cache", func() (string, error) {
+		if err := ap.RefreshUsers(ctx); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d users", len(ap.ProvideUsersMap().Users)), nil
+	})
+	r.run("channels cache", func() (string, error) {
+		if err := ap.RefreshChannels(ctx); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d channels", len(ap.ProvideChannelsMaps().Channels)), nil
+	})
+
+	r.run("conversations.history", func() (string, error) {
+		id, err := selfTestChannel(ctx, ap, channel)
+		if err != nil {
+			return "", err
+		}
+		history, err := ap.Slack().GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+			ChannelID: id,
+			Limit:     1,
+		})
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", id, err)
+		}
+		return fmt.Sprintf("read %d message(s) from %s", len(history.Messages), id), nil
+	})
+
+	r.run("conversations_add_message (dry run)", func() (string, error) {
+		if !r.Capabilities.hasTool(ToolConversationsAddMessage) {
+			return "", skipCheck("tool not registered")
+		}
+		if err := handler.NewConversationsHandler(ap, s.logger).CheckAddMessage(ctx, channel); err != nil {
+			return "", err
+		}
+		if channel == "" {
+			return "posting enabled; pass --self-test-channel to check a channel", nil
+		}
+		return "posting to " + channel + " allowed", nil
+	})
+
+	return r
+}
+
+// TokenCheck checks only the token: auth.test and, when
+// SLACK_MCP_TOKEN_EXPIRES_AT is set, how long it has left. The report lists
+// the scopes and the tools they enable as the self-test's does.
+func (s *MCPServer) TokenCheck(ctx context.Context) *SelfTestReport {
+	ap := s.provider
+	r := &SelfTestReport{Capabilities: s.caps.Load(), title: "Slack MCP Server token check"}
+
+	if !r.authTest(ctx, ap) {
+		return r
+	}
+	r.run("token expiry", func() (string, error) {
+		cfg := ap.Config()
+		expiresAt := cfg.TokenExpiryTime()
+		if expiresAt.IsZero() {
+			return "", skipCheck("SLACK_MCP_TOKEN_EXPIRES_AT not set")
+		}
+		left := time.Until(expiresAt).Round(time.Minute)
+		switch {
+		case left <= 0:
+			return "", fmt.Errorf("expired at %s", expiresAt.UTC().Format(time.RFC3339))
+		case left <= cfg.TokenWarningDuration():
+			return "", fmt.Errorf("expires at %s, in %s", expiresAt.UTC().Format(time.RFC3339), left)
+		}
+		return fmt.Sprintf("expires at %s, in %s", expiresAt.UTC().Format(time.RFC3339), left), nil
+	})
+	return r
+}
+
+func (r *SelfTestReport) authTest(ctx context.Context, ap *provider.ApiProvider) bool {
+	return r.run("auth.test", func() (string, error) {
+		ar, err := ap.Slack().AuthTestContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("team %s (%s), user %s (%s)", ar.Team, ar.TeamID, ar.User, ar.UserID), nil
+	})
+}
+
+// selfTestChannel resolves channel, or finds a channel the token is a member
+// of when it is empty.
+func selfTestChannel(ctx context.Context, ap *provider.ApiProvider, channel string) (string, error) {
+	if channel != "" {
+		if !strings.HasPrefix(channel, "#") && !strings.HasPrefix(channel, "@") {
+			return channel, nil
+		}
+		cache := ap.ProvideChannelsMaps()
+		if id, ok := cache.ChannelsInv[channel]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("channel %q not found", channel)
+	}
+
+	channels, _, err := ap.Slack().GetConversationsForUserContext(ctx, &slack.GetConversationsForUserParameters{
+		Types:           []string{"public_channel", "private_channel"},
+		ExcludeArchived: true,
+		Limit:           100,
+	})
+	if err != nil {
+		return "", fmt.Errorf("users.conversations: %w", err)
+	}
+	for _, c := range channels {
+		if ap.ChannelAllowed(c.ID) {
+			return c.ID, nil
+		}
+	}
+	return "", skipCheck("token is not a member of any allowed channel; pass --self-test-channel")
+}
+
+func (c *Capabilities) hasTool(name string) bool {
+	if c == nil {
+		return false
+	}
+	i := sort.SearchStrings(c.Tools, name)
+	return i < len(c.Tools) && c.Tools[i] == name
+}
+
+// Write prints the report as plain text.
+func (r *SelfTestReport) Write(w io.Writer) {
+	title := r.title
+	if title == "" {
+		title = "Slack MCP Server self-test"
+	}
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w)
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "  %-4s  %-36s %6dms  %s\n", strings.ToUpper(c.Status), c.Name, c.Duration.Milliseconds(), c.Detail)
+	}
+
+	if caps := r.Capabilities; caps != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Token type: %s\n", caps.TokenType)
+		if caps.ScopesKnown {
+			fmt.Fprintf(w, "Scopes:     %s\n", strings.Join(caps.Scopes, ", "))
+		} else {
+			fmt.Fprintln(w, "Scopes:     unknown")
+		}
+		fmt.Fprintf(w, "Tools:      %s\n", strings.Join(caps.Tools, ", "))
+		for _, t := range caps.SkippedTools {
+			fmt.Fprintf(w, "Skipped:    %s (needs one of %s)\n", t.Name, strings.Join(t.RequiredAnyOf, ", "))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if r.OK() {
+		fmt.Fprintln(w, "Result: OK")
+	} else {
+		fmt.Fprintln(w, "Result: FAILED")
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTestReport(t *testing.T) {
+	r := &SelfTestReport{Capabilities: &Capabilities{
+		TokenType: "bot",
+		Tools:     []string{ToolChannelsList, ToolConversationsHistory},
+		SkippedTools: []SkippedTool{
+			{Name: ToolConversationsSearchMessages, RequiredAnyOf: []string{"search:read"}},
+		},
+	}}
+
+	assert.True(t, r.run("auth.test", func() (string, error) { return "team Acme", nil }))
+	assert.True(t, r.run("dry run", func() (string, error) { return "", skipCheck("tool not registered") }))
+	assert.True(t, r.OK(), "skipped checks do not fail the report")
+	assert.False(t, r.run("conversations.history", func() (string, error) { return "", errors.New("not_in_channel") }))
+	assert.False(t, r.OK())
+
+	var buf bytes.Buffer
+	r.Write(&buf)
+	out := buf.String()
+	assert.Regexp(t, `PASS\s+auth.test\s+\d+ms\s+team Acme`, out)
+	assert.Regexp(t, `SKIP\s+dry run\s+\d+ms\s+tool not registered`, out)
+	assert.Regexp(t, `FAIL\s+conversations.history\s+\d+ms\s+not_in_channel`, out)
+	assert.Contains(t, out, "Scopes:     unknown")
+	assert.Contains(t, out, "Skipped:    conversations_search_messages (needs one of search:read)")
+	assert.Contains(t, out, "Result: FAILED")
+}
+
+func TestCapabilitiesHasTool(t *testing.T) {
+	caps := &Capabilities{Tools: []string{ToolChannelsList, ToolConversationsAddMessage, ToolConversationsHistory}}
+	assert.True(t, caps.hasTool(ToolConversationsAddMessage))
+	assert.False(t, caps.hasTool(ToolReactionsAdd))
+	assert.False(t, (*Capabilities)(nil).hasTool(ToolChannelsList))
+}
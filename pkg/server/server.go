@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
 	"github.com/korotovsky/slack-mcp-server/pkg/handler"
 	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
@@ -20,14 +23,39 @@ import (
 )
 
 type MCPServer struct {
-	server *server.MCPServer
-	logger *zap.Logger
+	server    *server.MCPServer
+	provider  *provider.ApiProvider
+	logger    *zap.Logger
+	transport string
+
+	// State replaced by Reload.
+	toolsConfig atomic.Pointer[ToolsConfig]
+	caps        atomic.Pointer[Capabilities]
+	users       *userServers
+	cache       *responseCache
+	digests     *handler.DigestScheduler
+	webhook     *webhookSink
+	embeddings  *handler.EmbeddingSink
+	journal     *handler.WriteJournal
+	quotas      *quotaTracker
+	sessions    *handler.SessionContexts
+	reloadMu    sync.Mutex
+	loadConfig  func() (*config.Config, *ToolsConfig, error)
+
+	drain          *drainTracker
+	stopBackground context.CancelFunc
+
+	mu            sync.Mutex
+	stopTransport func(context.Context) error
+	shutdownOnce  sync.Once
 }
 
 const (
 	ToolConversationsHistory        = "conversations_history"
 	ToolConversationsReplies        = "conversations_replies"
 	ToolConversationsAddMessage     = "conversations_add_message"
+	ToolConversationsAddMessages    = "conversations_add_messages"
+	ToolConversationsOpenDM         = "conversations_open_dm"
 	ToolReactionsAdd                = "reactions_add"
 	ToolReactionsRemove             = "reactions_remove"
 	ToolAttachmentGetData           = "attachment_get_data"
@@ -38,14 +66,52 @@ const (
 	ToolUsergroupsCreate            = "usergroups_create"
 	ToolUsergroupsUpdate            = "usergroups_update"
 	ToolUsergroupsUsersUpdate       = "usergroups_users_update"
+	ToolUsergroupsDisable           = "usergroups_disable"
 	ToolSavedList                   = "saved_list"
 	ToolSavedComplete               = "saved_complete"
+	ToolUsersStatusGet              = "users_status_get"
+	ToolUsersStatusSet              = "users_status_set"
+	ToolActivityMentions            = "activity_mentions"
+	ToolActivityThreads             = "activity_threads"
+	ToolFilesList                   = "files_list"
+	ToolActivityFeed                = "activity_feed"
+	ToolTeamInfo                    = "team_info"
+	ToolConversationsInvite         = "conversations_invite"
+	ToolReactionsSearch             = "reactions_search"
+	ToolContextSet                  = "context_set"
+	ToolConversationsTranscript     = "conversations_transcript"
+	ToolAdminAuditSearch            = "admin_audit_search"
+	ToolConversationsSetTopic       = "conversations_set_topic"
+	ToolConversationsSetPurpose     = "conversations_set_purpose"
+	ToolConversationsForwardMessage = "conversations_forward_message"
+	ToolUsersProfileSet             = "users_profile_set"
+	ToolReactionsAddBulk            = "reactions_add_bulk"
+	ToolAssistantSetStatus          = "assistant_threads_set_status"
+	ToolAssistantSetTitle           = "assistant_threads_set_title"
+	ToolAssistantSetPrompts         = "assistant_threads_set_suggested_prompts"
+	ToolConversationsCleanup        = "conversations_cleanup"
+	ToolHuddlesStart                = "huddles_start"
+	ToolThreadsFollow               = "threads_follow"
+	ToolThreadsUnfollow             = "threads_unfollow"
+	ToolAdminUsersInvite            = "admin_users_invite"
+	ToolAdminUsersRemove            = "admin_users_remove"
+	ToolWorkflowsTrigger            = "workflows_trigger"
+	ToolConversationsDMHistory      = "conversations_dm_history"
+	ToolDirectoryList               = "directory_list"
+	ToolPendingWrites               = "pending_writes"
+	ToolUsergroupsUsersModify       = "usergroups_users_modify"
+	ToolThreadSearch                = "thread_search"
+	ToolThreadParticipants          = "thread_participants"
+	ToolFilesInfo                   = "files_info"
+	ToolConversationsContext        = "conversations_context"
 )
 
 var ValidToolNames = []string{
 	ToolConversationsHistory,
 	ToolConversationsReplies,
 	ToolConversationsAddMessage,
+	ToolConversationsAddMessages,
+	ToolConversationsOpenDM,
 	ToolReactionsAdd,
 	ToolReactionsRemove,
 	ToolAttachmentGetData,
@@ -56,8 +122,44 @@ var ValidToolNames = []string{
 	ToolUsergroupsCreate,
 	ToolUsergroupsUpdate,
 	ToolUsergroupsUsersUpdate,
+	ToolUsergroupsDisable,
 	ToolSavedList,
 	ToolSavedComplete,
+	ToolUsersStatusGet,
+	ToolUsersStatusSet,
+	ToolActivityMentions,
+	ToolActivityThreads,
+	ToolFilesList,
+	ToolActivityFeed,
+	ToolTeamInfo,
+	ToolConversationsInvite,
+	ToolReactionsSearch,
+	ToolContextSet,
+	ToolConversationsTranscript,
+	ToolAdminAuditSearch,
+	ToolConversationsSetTopic,
+	ToolConversationsSetPurpose,
+	ToolConversationsForwardMessage,
+	ToolUsersProfileSet,
+	ToolReactionsAddBulk,
+	ToolAssistantSetStatus,
+	ToolAssistantSetTitle,
+	ToolAssistantSetPrompts,
+	ToolConversationsCleanup,
+	ToolHuddlesStart,
+	ToolThreadsFollow,
+	ToolThreadsUnfollow,
+	ToolAdminUsersInvite,
+	ToolAdminUsersRemove,
+	ToolWorkflowsTrigger,
+	ToolConversationsDMHistory,
+	ToolDirectoryList,
+	ToolPendingWrites,
+	ToolUsergroupsUsersModify,
+	ToolThreadSearch,
+	ToolThreadParticipants,
+	ToolFilesInfo,
+	ToolConversationsContext,
 }
 
 func ValidateEnabledTools(tools []string) error {
@@ -80,8 +182,10 @@ func ValidateEnabledTools(tools []string) error {
 	return nil
 }
 
-func shouldAddTool(name string, enabledTools []string, envVarName string) bool {
-	if envVarName == "" {
+func shouldAddTool(name string, cfg *config.Config) bool {
+	enabledTools := cfg.EnabledTools
+	policy, gated := toolPolicies[name]
+	if !gated {
 		if len(enabledTools) == 0 {
 			return true
 		}
@@ -93,26 +197,331 @@ func shouldAddTool(name string, enabledTools []string, envVarName string) bool {
 	}
 
 	if len(enabledTools) == 0 {
-		return os.Getenv(envVarName) != ""
+		return policy(cfg) != ""
 	}
 
 	return false
 }
 
-func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledTools []string) *MCPServer {
+// workflowsDescription lists the configured workflows with what they do and
+// the inputs they take, for the workflows_trigger description.
+func workflowsDescription(workflows []config.WorkflowConfig) string {
+	parts := make([]string, 0, len(workflows))
+	for _, w := range workflows {
+		part := w.Name
+		var details []string
+		if w.Description != "" {
+			details = append(details, strings.TrimSuffix(w.Description, "."))
+		}
+		if len(w.Inputs) > 0 {
+			details = append(details, "inputs: "+strings.Join(w.Inputs, ", "))
+		}
+		if len(details) > 0 {
+			part += " (" + strings.Join(details, "; ") + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ") + "."
+}
+
+// searchTemplatesDescription lists the configured search templates with
+// what they find, for the template parameter of conversations_search_messages.
+func searchTemplatesDescription(templates []config.SearchTemplateConfig) string {
+	parts := make([]string, 0, len(templates))
+	for _, t := range templates {
+		if t.Description != "" {
+			parts = append(parts, t.Name+" ("+strings.TrimSuffix(t.Description, ".")+")")
+		} else {
+			parts = append(parts, t.Name)
+		}
+	}
+	return strings.Join(parts, "; ") + "."
+}
+
+// botSearchTool is conversations_search_messages as registered for a bot
+// token: a scan of the recent history of named channels, since bot tokens
+// cannot use search.messages.
+func botSearchTool() mcp.Tool {
+	return mcp.NewTool(ToolConversationsSearchMessages,
+		mcp.WithDescription("Search the recent messages of up to 10 channels for words. This server uses a bot token, which Slack search does not accept, so the history of the channels in filter_in_channel is scanned and matched on the server: by default the last 7 days, at most 30, and not thread replies. Returns CSV rows, newest first, followed by JSON metadata with mode 'history_scan' and the number of messages scanned and matched."),
+		mcp.WithTitleAnnotation("Search Messages"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("search_query",
+			mcp.Required(),
+			mcp.Description("Words every matching message must contain, case-insensitively, in its text, attachments or file names. Use \"double quotes\" for a phrase and a leading - to exclude a word or phrase, e.g. 'rollback -staging'. Slack search modifiers are not supported."),
+		),
+		mcp.WithString("filter_in_channel",
+			mcp.Required(),
+			mcp.Description("Comma-separated channels to scan, up to 10, by ID or name. Example: 'C1234567890' or '#general,#support'."),
+		),
+		mcp.WithString("filter_users_from",
+			mcp.Description("Only match messages from this user, by ID or display name. Example: 'U1234567890' or '@username'."),
+		),
+		mcp.WithString("filter_date_after",
+			mcp.Description("Only match messages sent after this day, in format 'YYYY-MM-DD'. Defaults to 7 days before filter_date_before or now; the range can span at most 30 days."),
+		),
+		mcp.WithString("filter_date_before",
+			mcp.Description("Only match messages sent before this day, in format 'YYYY-MM-DD'. Defaults to now."),
+		),
+		mcp.WithString("filter_date_on",
+			mcp.Description("Only match messages sent on this day, in format 'YYYY-MM-DD'. Cannot be combined with filter_date_after or filter_date_before."),
+		),
+		mcp.WithBoolean("include_unfurls",
+			mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_language",
+			mcp.Description("If true, adds a Language column with the ISO 639-1 code of each message's language, or 'und', as in conversations_history. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("filter_language",
+			mcp.Description("Comma-separated ISO 639-1 codes, e.g. 'en' or 'ja', keeping only the messages detected in one of these languages; add 'und' for messages whose language cannot be told, as in conversations_history."),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(20),
+			mcp.Description("The maximum number of messages to return, 1 to 100. Metadata truncated is set when more matched."),
+		),
+		mcp.WithString("render",
+			mcp.DefaultString("plain"),
+			mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn to standard Markdown."),
+		),
+		mcp.WithString("tz",
+			mcp.Description("Timezone for the date filters and timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+		),
+	)
+}
+
+// setAPIKey registers the API key of cfg with the auth middleware.
+func setAPIKey(cfg *config.Config, logger *zap.Logger) {
+	if cfg.APIKey == "" && cfg.SSEAPIKey != "" {
+		logger.Warn("SLACK_MCP_SSE_API_KEY is deprecated, please use SLACK_MCP_API_KEY",
+			zap.String("context", "console"),
+		)
+	}
+	auth.SetAPIKey(cfg.ServerAPIKey())
+}
+
+// NewMCPServer builds the server with the tools cfg enables registered.
+// toolsConfig is optional; its argument restrictions are enforced on every
+// call.
+func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, cfg *config.Config, toolsConfig *ToolsConfig) *MCPServer {
+	setAPIKey(cfg, logger)
+	resultStore := handler.NewResultStore(handler.ResultTTL(cfg), handler.ResultMaxBytes(cfg))
+	resultsHandler := handler.NewResultsHandler(provider, resultStore, handler.ResultThreshold(cfg), logger)
+	drain := &drainTracker{}
+	sessionContexts := handler.NewSessionContexts(provider.Store(), logger)
+	cancels := newCallCancels(logger)
+	hooks := &server.Hooks{}
+	forgetSessionContexts(hooks, sessionContexts)
+	cancels.hooks(hooks)
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	journal, err := handler.NewWriteJournal(cfg.WriteJournal, logger)
+	if err != nil {
+		logger.Fatal("Failed to open the write journal",
+			zap.String("context", "console"),
+			zap.String("path", cfg.WriteJournal),
+			zap.Error(err),
+		)
+	}
+	classifier, err := handler.NewClassifier(cfg.Classifier, logger)
+	if err != nil {
+		logger.Fatal("Failed to set up the message classifier",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	m := &MCPServer{
+		provider:       provider,
+		logger:         logger,
+		transport:      provider.ServerTransport(),
+		users:          newUserServers(cfg, logger),
+		cache:          newResponseCache(cfg, logger),
+		webhook:        newWebhookSinkFromConfig(cfg, logger),
+		embeddings:     handler.NewEmbeddingSink(cfg.EmbeddingSink, logger),
+		journal:        journal,
+		sessions:       sessionContexts,
+		quotas:         newQuotaTracker(cfg.Quotas),
+		digests:        handler.NewDigestScheduler(bgCtx, provider, logger),
+		drain:          drain,
+		stopBackground: stopBackground,
+	}
+	m.toolsConfig.Store(toolsConfig)
+	redact := newRedactors(provider.Config, logger)
+
 	s := server.NewMCPServer(
 		"Slack MCP Server",
 		version.Version,
 		server.WithLogging(),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(drain.middleware()),
+		server.WithToolHandlerMiddleware(cancels.middleware()),
 		server.WithToolHandlerMiddleware(buildErrorRecoveryMiddleware(logger)),
 		server.WithToolHandlerMiddleware(buildLoggerMiddleware(logger)),
+		server.WithToolHandlerMiddleware(buildWebhookMiddleware(m.webhook, provider.ServerTransport())),
 		server.WithToolHandlerMiddleware(auth.BuildMiddleware(provider.ServerTransport(), logger)),
+		server.WithToolHandlerMiddleware(buildQuotaMiddleware(m.quotas, logger)),
+		server.WithToolHandlerMiddleware(buildSessionContextMiddleware(sessionContexts)),
+		server.WithToolHandlerMiddleware(buildEmbeddingSinkMiddleware(m.embeddings)),
+		server.WithToolHandlerMiddleware(buildClassifierMiddleware(classifier)),
+		server.WithToolHandlerMiddleware(buildToolRestrictionsMiddleware(m.toolsConfig.Load, provider, logger)),
+		server.WithToolHandlerMiddleware(buildIdempotencyMiddleware(newIdempotencyStore(logger, provider.Store()))),
+		server.WithToolHandlerMiddleware(buildWriteJournalMiddleware(journal)),
+		server.WithToolHandlerMiddleware(buildResponseCacheMiddleware(m.cache)),
+		server.WithToolHandlerMiddleware(buildResultOffloadMiddleware(resultsHandler)),
+		server.WithToolHandlerMiddleware(buildCSVOutputMiddleware(m.toolsConfig.Load, provider.Config)),
+		server.WithToolHandlerMiddleware(buildRedactionMiddleware(redact)),
+		server.WithToolHandlerMiddleware(buildMultiUserMiddleware(m.users, logger)),
+		server.WithResourceHandlerMiddleware(buildResourceRedactionMiddleware(redact)),
+	)
+	m.server = s
+	s.AddNotificationHandler("notifications/cancelled", cancels.handleCancelled)
+	if provider.Config().ChannelSuggestions == "sampling" {
+		s.EnableSampling()
+	}
+	if m.webhook != nil {
+		go m.webhook.run(bgCtx)
+	}
+	if m.embeddings != nil {
+		go m.embeddings.Run(bgCtx)
+	}
+
+	registerTools(s, provider, logger, cfg)
+	applyToolMetadata(s)
+	applyDescriptionOverrides(s, toolsConfig, logger)
+
+	logger.Info("Authenticating with Slack API...",
+		zap.String("context", "console"),
 	)
+	m.caps.Store(applyScopeFilter(s, provider, logger))
 
+	ar, err := provider.Slack().AuthTest()
+	if err != nil {
+		logger.Fatal("Failed to authenticate with Slack",
+			zap.String("context", "console"),
+			zap.Error(err),
+		)
+	}
+
+	logger.Info("Successfully authenticated with Slack",
+		zap.String("context", "console"),
+		zap.String("team", ar.Team),
+		zap.String("user", ar.User),
+		zap.String("enterprise", ar.EnterpriseID),
+		zap.String("url", ar.URL),
+	)
+
+	ws, err := text.Workspace(ar.URL)
+	if err != nil {
+		logger.Fatal("Failed to parse workspace from URL",
+			zap.String("context", "console"),
+			zap.String("url", ar.URL),
+			zap.Error(err),
+		)
+	}
+
+	channelsHandler := handler.NewChannelsHandler(provider, logger)
 	conversationsHandler := handler.NewConversationsHandler(provider, logger)
 
-	if shouldAddTool(ToolConversationsHistory, enabledTools, "") {
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/channels",
+		"Directory of Slack channels",
+		mcp.WithResourceDescription("This resource provides a directory of Slack channels."),
+		mcp.WithMIMEType("text/csv"),
+	), channelsHandler.ChannelsResource)
+
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/users",
+		"Directory of Slack users",
+		mcp.WithResourceDescription("This resource provides a directory of Slack users. Deactivated users and bots are left out; see the users template to include them."),
+		mcp.WithMIMEType("text/csv"),
+	), conversationsHandler.UsersResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://"+ws+"/users{?include_deleted,include_bots,include_custom_fields}",
+		"Directory of Slack users with filters",
+		mcp.WithTemplateDescription("The users directory with deactivated users (include_deleted=true), bot accounts (include_bots=true) and a CustomFields column holding the custom profile field values by label (include_custom_fields=true, needs SLACK_MCP_USERS_PROFILE_FIELDS). The IsDeleted and IsBot columns tell them apart."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), conversationsHandler.UsersResource)
+
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/capabilities",
+		"Token capabilities",
+		mcp.WithResourceDescription("Token type, granted OAuth scopes, registered tools and tools skipped because of missing scopes."),
+		mcp.WithMIMEType("application/json"),
+	), buildCapabilitiesResource(m.caps.Load, provider, logger))
+
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/tools",
+		"Tool guide",
+		mcp.WithResourceDescription("Cost hints (low, medium or high, with the typical number of Slack API calls) and example calls with abridged results for every registered tool. Read it to pick the cheapest tool for a task and to shape arguments correctly."),
+		mcp.WithMIMEType("application/json"),
+	), buildToolsResource(m.caps.Load, provider, logger))
+
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/cache-status",
+		"Cache warm-up status",
+		mcp.WithResourceDescription("Progress of the users and channels cache warm-up: state, source, entries and pages fetched so far, and the previous cache size as an estimate of the total. Until both are ready, user names may be resolved on demand and channel lists may be partial."),
+		mcp.WithMIMEType("application/json"),
+	), buildCacheStatusResource(provider, logger))
+
+	s.AddResource(mcp.NewResource(
+		"slack://"+ws+"/quotas",
+		"Client quota usage",
+		mcp.WithResourceDescription("Quota limits of every client, its tool calls and Slack API calls in the current minute and UTC day, and how many calls were rejected for exceeding them."),
+		mcp.WithMIMEType("application/json"),
+	), buildQuotasResource(m.quotas, provider, logger))
+
+	resultsHandler.SetWorkspace(ws)
+	if resultsHandler.Enabled() {
+		s.AddResourceTemplate(mcp.NewResourceTemplate(
+			"slack://"+ws+"/results/{id}",
+			"Large tool results",
+			mcp.WithTemplateDescription("Tool results larger than SLACK_MCP_RESULT_RESOURCE_THRESHOLD bytes, referenced by resource links in tool responses. Entries expire after SLACK_MCP_RESULT_RESOURCE_TTL."),
+		), resultsHandler.ResultsResource)
+
+		go resultStore.RunCleanup(bgCtx, 0, logger)
+	}
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://"+ws+"/threads/{channel}/{ts}",
+		"Thread index",
+		mcp.WithTemplateDescription("Summary of a thread (participants, reply count, reactions) with the URIs of its pages of up to 100 messages each. Earlier pages keep their contents as replies are added."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), conversationsHandler.ThreadsResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://"+ws+"/threads/{channel}/{ts}/pages/{page}",
+		"Thread page",
+		mcp.WithTemplateDescription("One page of a thread's messages as CSV, numbered from 1; the first page starts with the parent message."),
+		mcp.WithTemplateMIMEType("text/csv"),
+	), conversationsHandler.ThreadsResource)
+
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://"+ws+"/channels/{channel}/activity",
+		"Channel activity heatmap",
+		mcp.WithTemplateDescription("Messages posted in a channel per day over the last 90 days, with the total, the number of active days and the time of the last message, e.g. to tell whether a channel is still in use without reading its history. Computed server-side and cached for an hour."),
+		mcp.WithTemplateMIMEType("application/json"),
+	), conversationsHandler.ChannelActivityResource)
+
+	// Registered even without digests so a reload can add them.
+	s.AddResourceTemplate(mcp.NewResourceTemplate(
+		"slack://"+ws+"/digests/{name}",
+		"Scheduled digests",
+		mcp.WithTemplateDescription("Latest digest of the recent messages in a set of channels, configured under digests in the config file. Built on demand when the digest has not run yet."),
+		mcp.WithTemplateMIMEType("text/plain"),
+	), m.digests.DigestsResource)
+	m.digests.Configure(cfg.Digests)
+
+	return m
+}
+
+// registerTools adds every enabled tool backed by the given provider to s.
+func registerTools(s *server.MCPServer, provider *provider.ApiProvider, logger *zap.Logger, cfg *config.Config) {
+	conversationsHandler := handler.NewConversationsHandler(provider, logger)
+
+	if shouldAddTool(ToolConversationsHistory, cfg) {
 		s.AddTool(mcp.NewTool(ToolConversationsHistory,
 		mcp.WithDescription("Get messages from the channel (or DM) by channel_id, the last row/column in the response is used as 'cursor' parameter for pagination if not empty"),
 		mcp.WithTitleAnnotation("Get Conversation History"),
@@ -125,6 +534,18 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("include_unfurls",
+			mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message, so shared links can be understood without fetching them. Several links are joined with '|'. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_tombstones",
+			mcp.Description("If true, keeps the message_changed, message_deleted and tombstone records Slack leaves in history, as rows for the message they are about, and adds the Tombstone column ('edited' or 'deleted') and TombstoneTime (when it happened, if known) for these and for edited messages. Edited rows show the current text; Slack does not keep the previous one. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_metadata",
+			mcp.Description("If true, adds a Metadata column with the event_type and event_payload of messages posted with Slack message metadata, as JSON, e.g. to correlate messages with ticket IDs. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithString("cursor",
 			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
 		),
@@ -132,10 +553,92 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.DefaultString("1d"),
 			mcp.Description("Limit of messages to fetch in format of maximum ranges of time (e.g. 1d - 1 day, 1w - 1 week, 30d - 30 days, 90d - 90 days which is a default limit for free tier history) or number of messages (e.g. 50). Must be empty when 'cursor' is provided."),
 		),
+		mcp.WithString("oldest",
+			mcp.Description("Only return messages at or after this time: a Slack timestamp (1709251200.000000), an ISO date (2024-03-01), a local time (2024-03-01 09:00) or RFC 3339 time, in the 'tz' timezone. Together with 'latest' it replaces a duration 'limit'."),
+		),
+		mcp.WithString("latest",
+			mcp.Description("Only return messages at or before this time, in the same formats as 'oldest'. A date alone includes that whole day, so oldest=2024-03-01 and latest=2024-03-03 covers three days."),
+		),
+		mcp.WithString("around_ts",
+			mcp.Description("Timestamp of a message, e.g. a search hit, to return with the messages around it instead of the latest ones: the message itself and up to 'around_count' messages before and after it. 'limit' is ignored; cannot be combined with 'cursor', 'oldest' or 'latest'. Thread replies are not in channel history, use conversations_replies for them."),
+		),
+		mcp.WithNumber("around_count",
+			mcp.DefaultNumber(5),
+			mcp.Description("With 'around_ts', how many messages to return on each side of it, 1 to 100. Default is 5."),
+		),
+		mcp.WithBoolean("include_language",
+			mcp.Description("If true, adds a Language column with the ISO 639-1 code of each message's language (e.g. 'en', 'ja'), or 'und' when it cannot be told, detected locally from the text. Use it to route messages to translation. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("filter_language",
+			mcp.Description("Comma-separated ISO 639-1 codes, e.g. 'en' or 'ja,zh', keeping only the messages detected in one of these languages and adding the Language column. Add 'und' to keep messages whose language cannot be told, such as short replies and emoji. Detection covers Latin-script English, German, French, Spanish, Portuguese, Italian and Dutch, and languages with their own script such as Japanese, Chinese, Korean and Russian."),
+		),
+		mcp.WithString("render",
+			mcp.DefaultString("plain"),
+			mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn (mentions, links, bold, strikethrough, emoji codes, code blocks) to standard Markdown with resolved user and channel names."),
+		),
+		mcp.WithString("tz",
+			mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset. Rows carry an ISO-8601 time with offset plus a human-readable timeHuman column."),
+		),
 	), conversationsHandler.ConversationsHistoryHandler)
 	}
 
-	if shouldAddTool(ToolConversationsReplies, enabledTools, "") {
+	if shouldAddTool(ToolConversationsDMHistory, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsDMHistory,
+			mcp.WithDescription("Get the recent messages of the direct message (DM) with one user, given as user ID or @handle, in one call: the DM is looked up for you, so no channels_list is needed first. Use it for requests like 'show me my recent conversation with Alice'. Returns the same CSV as conversations_history; the last row/column is the 'cursor' for the next page if not empty."),
+			mcp.WithTitleAnnotation("Get DM History"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("user",
+				mcp.Required(),
+				mcp.Description("The other person of the DM: a user ID (U1234567890) or @handle such as @alice."),
+			),
+			mcp.WithBoolean("include_activity_messages",
+				mcp.Description("If true, the response will include activity messages. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("include_unfurls",
+				mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("include_tombstones",
+				mcp.Description("If true, keeps edited and deleted message records and adds the Tombstone and TombstoneTime columns, as in conversations_history. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("include_metadata",
+				mcp.Description("If true, adds the Metadata column of messages posted with Slack message metadata, as in conversations_history. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
+			),
+			mcp.WithString("limit",
+				mcp.DefaultString("1w"),
+				mcp.Description("Limit of messages to fetch as a time range (e.g. 1d, 1w, 30d) or a number of messages (e.g. 50). Default is 1w."),
+			),
+			mcp.WithString("oldest",
+				mcp.Description("Only return messages at or after this time: a Slack timestamp, an ISO date (2024-03-01), a local time (2024-03-01 09:00) or RFC 3339 time, in the 'tz' timezone. Replaces a time range limit."),
+			),
+			mcp.WithString("latest",
+				mcp.Description("Only return messages at or before this time, in the same formats as 'oldest'."),
+			),
+			mcp.WithBoolean("include_language",
+				mcp.Description("If true, adds a Language column with the ISO 639-1 code of each message's language, or 'und', as in conversations_history. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("filter_language",
+				mcp.Description("Comma-separated ISO 639-1 codes, e.g. 'en' or 'ja', keeping only the messages detected in one of these languages; add 'und' for messages whose language cannot be told, as in conversations_history."),
+			),
+			mcp.WithString("render",
+				mcp.DefaultString("plain"),
+				mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn to standard Markdown."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ConversationsDMHistoryHandler)
+	}
+
+	if shouldAddTool(ToolConversationsReplies, cfg) {
 		s.AddTool(mcp.NewTool(ToolConversationsReplies,
 		mcp.WithDescription("Get a thread of messages posted to a conversation by channelID and thread_ts, the last row/column in the response is used as 'cursor' parameter for pagination if not empty"),
 		mcp.WithTitleAnnotation("Get Thread Replies"),
@@ -152,6 +655,14 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.Description("If true, the response will include activity messages such as 'channel_join' or 'channel_leave'. Default is boolean false."),
 			mcp.DefaultBool(false),
 		),
+		mcp.WithBoolean("include_unfurls",
+			mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message, so shared links can be understood without fetching them. Several links are joined with '|'. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("include_metadata",
+			mcp.Description("If true, adds the Metadata column of messages posted with Slack message metadata, as in conversations_history. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
 		mcp.WithString("cursor",
 			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
 		),
@@ -159,32 +670,370 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.DefaultString("1d"),
 			mcp.Description("Limit of messages to fetch in format of maximum ranges of time (e.g. 1d - 1 day, 30d - 30 days, 90d - 90 days which is a default limit for free tier history) or number of messages (e.g. 50). Must be empty when 'cursor' is provided."),
 		),
-	), conversationsHandler.ConversationsRepliesHandler)
-	}
-
-	if shouldAddTool(ToolConversationsAddMessage, enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL") {
-		s.AddTool(mcp.NewTool(ToolConversationsAddMessage,
-		mcp.WithDescription("Add a message to a public channel, private channel, or direct message (DM, or IM) conversation by channel_id and thread_ts."),
-		mcp.WithTitleAnnotation("Send Message"),
-		mcp.WithDestructiveHintAnnotation(true),
-		mcp.WithString("channel_id",
-			mcp.Required(),
-			mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+		mcp.WithBoolean("fetch_all",
+			mcp.Description("If true, fetch the complete thread in one call, ignoring 'limit' and 'cursor'. Replies are returned oldest first and followed by a JSON block with parent message metadata (reply count, reactions, participants). Default is boolean false."),
+			mcp.DefaultBool(false),
 		),
-		mcp.WithString("thread_ts",
-			mcp.Description("Unique identifier of either a thread's parent message or a message in the thread_ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies. Optional, if not provided the message will be added to the channel itself, otherwise it will be added to the thread."),
+		mcp.WithBoolean("include_language",
+			mcp.Description("If true, adds a Language column with the ISO 639-1 code of each message's language, or 'und', as in conversations_history. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("filter_language",
+			mcp.Description("Comma-separated ISO 639-1 codes, e.g. 'en' or 'ja', keeping only the messages detected in one of these languages; add 'und' for messages whose language cannot be told, as in conversations_history."),
 		),
-		mcp.WithString("text",
-			mcp.Description("Message text in specified content_type format. Example: 'Hello, world!' for text/plain or '# Hello, world!' for text/markdown."),
+		mcp.WithString("render",
+			mcp.DefaultString("plain"),
+			mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn (mentions, links, bold, strikethrough, emoji codes, code blocks) to standard Markdown with resolved user and channel names."),
 		),
-		mcp.WithString("content_type",
-			mcp.DefaultString("text/markdown"),
-			mcp.Description("Content type of the message. Default is 'text/markdown'. Allowed values: 'text/markdown', 'text/plain'."),
+		mcp.WithString("tz",
+			mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset. Rows carry an ISO-8601 time with offset plus a human-readable timeHuman column."),
 		),
-	), conversationsHandler.ConversationsAddMessageHandler)
+	), conversationsHandler.ConversationsRepliesHandler)
 	}
 
-	if shouldAddTool(ToolReactionsAdd, enabledTools, "SLACK_MCP_REACTION_TOOL") {
+	if shouldAddTool(ToolThreadSearch, cfg) {
+		s.AddTool(mcp.NewTool(ToolThreadSearch,
+			mcp.WithDescription("Search the replies of one thread by channel_id and thread_ts. The whole thread is fetched and filtered by the server, since Slack's search cannot be limited to a thread, so a long thread can be searched without reading every reply. Matching messages, with optional context around them, are followed by a JSON block with the number of messages scanned and matched."),
+			mcp.WithTitleAnnotation("Search Thread"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Required(),
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies."),
+			),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("Words every matching message must contain, case-insensitively, in its text, attachments or file names. Use \"double quotes\" for a phrase and a leading - to exclude a word or phrase, e.g. 'rollback -staging'."),
+			),
+			mcp.WithString("user",
+				mcp.Description("Only match messages posted by this user, as a user ID (Uxxxxxxxxxx) or @username."),
+			),
+			mcp.WithNumber("context",
+				mcp.Description("Number of messages to include before and after each match, 0 to 5. Default is 0."),
+				mcp.DefaultNumber(0),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of matching messages to return, 1 to 100. Default is 20."),
+				mcp.DefaultNumber(20),
+			),
+			mcp.WithBoolean("include_activity_messages",
+				mcp.Description("If true, activity messages such as 'channel_join' or 'channel_leave' are searched and returned too. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithBoolean("include_unfurls",
+				mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("render",
+				mcp.DefaultString("plain"),
+				mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn to standard Markdown with resolved user and channel names."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ThreadSearchHandler)
+	}
+
+	if shouldAddTool(ToolThreadParticipants, cfg) {
+		s.AddTool(mcp.NewTool(ToolThreadParticipants,
+			mcp.WithDescription("List who posted in a thread by channel_id and thread_ts, computed server-side from the whole thread. Returns one CSV row per participant with profile (name, title, timezone), a Mention column ready to paste into a reply, whether they started the thread, their message and reaction counts and the times of their first and last messages, most active first. Followed by a JSON block with the number of messages and participants."),
+			mcp.WithTitleAnnotation("List Thread Participants"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Required(),
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread. ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies."),
+			),
+			mcp.WithBoolean("include_reactors",
+				mcp.Description("If true, users who only reacted to messages of the thread are listed too, with 0 messages. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for the FirstTime and LastTime columns, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ThreadParticipantsHandler)
+	}
+
+	if shouldAddTool(ToolConversationsContext, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsContext,
+			mcp.WithDescription("Get the context of a Slack message link in one call, for questions like 'what's going on here?' about a pasted permalink: the channel messages around the message (around its thread's parent for a reply), newest first, followed by the replies of its thread, oldest first. Returns CSV followed by a JSON block with target_ts, thread_ts, how many rows are channel context (context_rows) and thread replies (thread_rows), and the thread's participants and reply count."),
+			mcp.WithTitleAnnotation("Get Message Context"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("permalink",
+				mcp.Required(),
+				mcp.Description("Link to a Slack message, as copied with 'Copy link', e.g. https://example.slack.com/archives/C1234567890/p1718000000123456, with ?thread_ts=... for a thread reply."),
+			),
+			mcp.WithNumber("context",
+				mcp.DefaultNumber(5),
+				mcp.Description("Number of channel messages to return before and after the message, or its thread's parent, 1 to 100. Default is 5."),
+			),
+			mcp.WithBoolean("include_thread",
+				mcp.Description("If true, the replies of the thread the message starts or is in follow the channel context. Default is boolean true."),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithNumber("thread_limit",
+				mcp.DefaultNumber(50),
+				mcp.Description("Maximum number of thread replies to return, 1 to 1000; a longer thread is cut to the replies closest to the message and thread_truncated is set. Default is 50."),
+			),
+			mcp.WithString("render",
+				mcp.DefaultString("plain"),
+				mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn to standard Markdown with resolved user and channel names."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ConversationsContextHandler)
+	}
+
+	if shouldAddTool(ToolConversationsAddMessage, cfg) {
+		addMessageOptions := []mcp.ToolOption{
+			mcp.WithDescription("Add a message to a public channel, private channel, or direct message (DM, or IM) conversation by channel_id and thread_ts. Thread replies can also be broadcast to the channel with reply_broadcast, and messages can be scheduled with post_at."),
+			mcp.WithTitleAnnotation("Send Message"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Unique identifier of either a thread's parent message or a message in the thread_ts must be the timestamp in format 1234567890.123456 of an existing message with 0 or more replies. Optional, if not provided the message will be added to the channel itself, otherwise it will be added to the thread. The ts of a reply posts to the thread that reply is in."),
+			),
+			mcp.WithBoolean("reply_broadcast",
+				mcp.DefaultBool(false),
+				mcp.Description("If true, a thread reply is also sent to the channel, like 'Also send to #channel' in Slack, e.g. for incident updates. Requires thread_ts."),
+			),
+			mcp.WithString("text",
+				mcp.Description("Message text in specified content_type format. Example: 'Hello, world!' for text/plain or '# Hello, world!' for text/markdown. May be empty when 'attach' is set."),
+			),
+			mcp.WithString("attach",
+				mcp.Description("Comma separated existing Slack file IDs (e.g. F0123ABCD, from files_list) and http(s) URLs to attach below the text, up to 10. Files are shared again by reference instead of re-uploading; URLs are unfurled and must be allowed by SLACK_MCP_ADD_MESSAGE_UNFURLING."),
+			),
+			mcp.WithString("content_type",
+				mcp.DefaultString("text/markdown"),
+				mcp.Description("Content type of the message. Default is 'text/markdown'. Allowed values: 'text/markdown', 'text/plain'. Markdown tables are posted as aligned code blocks, since Slack cannot render tables."),
+			),
+			mcp.WithBoolean("resolve_mentions",
+				mcp.DefaultBool(true),
+				mcp.Description("If true (default), @handle, @display_name and #channel-name tokens in the text are converted into Slack mentions and channel links so they notify; @here, @channel and @everyone become special mentions. Set false to post them as plain text."),
+			),
+			mcp.WithString("metadata_event_type",
+				mcp.Description("Optional Slack message metadata event type, e.g. 'ticket_linked'. Lowercase letters, digits and underscores. Metadata is invisible to readers and is returned in the metadata column of conversations_history and conversations_replies, e.g. to correlate messages with ticket IDs."),
+			),
+			mcp.WithObject("metadata_event_payload",
+				mcp.Description("Optional JSON object sent as the metadata event_payload, e.g. {\"ticket_id\": \"OPS-123\"}. Requires metadata_event_type."),
+			),
+			mcp.WithString("post_at",
+				mcp.Description("Schedule the message instead of posting it now: a duration from now (e.g. '30m', '2h'), a local time (e.g. '2025-06-02 09:00') in the 'tz' timezone, an RFC 3339 time or Unix seconds. At most 120 days ahead. Returns CSV: channelID, scheduledMessageID, threadTs, postAt."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for local post_at times and timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+			withIdempotencyKey(),
+		}
+		if provider.IsBotToken() {
+			addMessageOptions = append(addMessageOptions,
+				mcp.WithString("username",
+					mcp.Description("Name to post as instead of the bot's own, e.g. 'Release Bot'. Requires SLACK_MCP_ADD_MESSAGE_IDENTITY and the chat:write.customize scope."),
+				),
+				mcp.WithString("icon_emoji",
+					mcp.Description("Emoji to use as the message icon, e.g. ':rocket:'. Cannot be combined with icon_url. Requires SLACK_MCP_ADD_MESSAGE_IDENTITY and the chat:write.customize scope."),
+				),
+				mcp.WithString("icon_url",
+					mcp.Description("URL of an image to use as the message icon. Cannot be combined with icon_emoji. Requires SLACK_MCP_ADD_MESSAGE_IDENTITY and the chat:write.customize scope."),
+				),
+			)
+		}
+		s.AddTool(mcp.NewTool(ToolConversationsAddMessage, addMessageOptions...), conversationsHandler.ConversationsAddMessageHandler)
+	}
+
+	if shouldAddTool(ToolConversationsAddMessages, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsAddMessages,
+			mcp.WithDescription("Post up to 50 messages in one call, e.g. the same announcement to many channels. Messages are posted in order with rate limiting; a failing item does not stop the batch. Returns CSV with one row per item: index, channelID, threadTs, ok, msgID, error. Subject to the same channel policy as conversations_add_message."),
+			mcp.WithTitleAnnotation("Send Messages (Batch)"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithArray("messages",
+				mcp.Required(),
+				mcp.MinItems(1),
+				mcp.MaxItems(50),
+				mcp.Description("Messages to post. Each item is an object with 'channel_id' (required, ID or #name/@username_dm), 'thread_ts' (optional, reply in thread), 'reply_broadcast' (optional, also send the reply to the channel), 'text' (required), 'content_type' and 'resolve_mentions' (optional, override the top-level values), and 'metadata_event_type' and 'metadata_event_payload' (optional, Slack message metadata as in conversations_add_message)."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"channel_id":             map[string]any{"type": "string"},
+						"thread_ts":              map[string]any{"type": "string"},
+						"reply_broadcast":        map[string]any{"type": "boolean"},
+						"text":                   map[string]any{"type": "string"},
+						"content_type":           map[string]any{"type": "string", "enum": []string{"text/markdown", "text/plain"}},
+						"resolve_mentions":       map[string]any{"type": "boolean"},
+						"metadata_event_type":    map[string]any{"type": "string"},
+						"metadata_event_payload": map[string]any{"type": "object"},
+					},
+					"required": []string{"channel_id", "text"},
+				}),
+			),
+			mcp.WithString("content_type",
+				mcp.DefaultString("text/markdown"),
+				mcp.Description("Default content type for items that don't set one. Allowed values: 'text/markdown', 'text/plain'."),
+			),
+			mcp.WithBoolean("resolve_mentions",
+				mcp.DefaultBool(true),
+				mcp.Description("Default for items that don't set resolve_mentions: convert @names and #channel-names into Slack mentions and channel links."),
+			),
+			withIdempotencyKey(),
+		), conversationsHandler.ConversationsAddMessagesHandler)
+	}
+
+	if shouldAddTool(ToolConversationsForwardMessage, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsForwardMessage,
+			mcp.WithDescription("Forward a message into another channel, DM or thread as a quote crediting the original author, with its time linked to the original's permalink and an optional comment above it. Returns CSV: channelID, msgID, threadTs, sourceChannelID, sourceMsgID, permalink. Subject to the same channel policy as conversations_add_message for the target."),
+			mcp.WithTitleAnnotation("Forward Message"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel holding the message to forward, in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("message_ts",
+				mcp.Required(),
+				mcp.Description("Timestamp of the message to forward, in format 1234567890.123456. Thread replies can be forwarded too."),
+			),
+			mcp.WithString("target_channel_id",
+				mcp.Required(),
+				mcp.Description("ID or name of the channel or DM to post the forward in, e.g. 'C1234567890', '#incidents' or '@username_dm'."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Optional thread in the target channel to post the forward into, in format 1234567890.123456. The ts of a reply posts to the thread that reply is in."),
+			),
+			mcp.WithString("comment",
+				mcp.Description("Optional text in Slack mrkdwn posted above the quote, e.g. 'FYI, this affects the release'."),
+			),
+			mcp.WithString("format",
+				mcp.DefaultString("quote"),
+				mcp.Enum("quote", "attachment"),
+				mcp.Description("'quote' (default) posts a block quote headed by author, channel and linked time. 'attachment' posts the message as a card with the author's avatar, linking the author name to the original."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for the quoted time, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+			withIdempotencyKey(),
+		), conversationsHandler.ConversationsForwardMessageHandler)
+	}
+
+	if shouldAddTool(ToolConversationsOpenDM, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsOpenDM,
+			mcp.WithDescription("Open a direct message with one user, or a group DM with up to 8 users, creating it if needed, and return its channel ID (D… or G…). Use it before conversations_add_message when messaging someone you have no DM with yet. Returns CSV: channelID, name, type (im or mpim), users, alreadyOpen. Subject to SLACK_MCP_ADD_MESSAGE_TOOL and SLACK_MCP_DM_ALLOWLIST."),
+			mcp.WithTitleAnnotation("Open DM"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("users",
+				mcp.Required(),
+				mcp.Description("Comma-separated user IDs (Uxxxxxxxxxx) or @handles, e.g. 'U0123456789' for a DM or 'U0123456789,@jane' for a group DM."),
+			),
+		), conversationsHandler.ConversationsOpenDMHandler)
+	}
+
+	if shouldAddTool(ToolConversationsInvite, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsInvite,
+			mcp.WithDescription("Invite people to a channel by email address: each email is resolved to a workspace member with users.lookupByEmail and invited with conversations.invite. Returns CSV with email, userID, userName, status (invited, already_in_channel, not_found or failed) and error, one row per email. Subject to SLACK_MCP_INVITE_TOOL."),
+			mcp.WithTitleAnnotation("Invite to Channel by Email"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID or name of the channel to invite to, e.g. 'C1234567890' or '#onboarding'."),
+			),
+			mcp.WithString("emails",
+				mcp.Required(),
+				mcp.Description("Comma-separated email addresses of workspace members, up to 50, e.g. 'ana@example.com, bo@example.com'."),
+			),
+		), conversationsHandler.ConversationsInviteHandler)
+	}
+
+	if shouldAddTool(ToolConversationsSetTopic, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsSetTopic,
+			mcp.WithDescription("Replace a channel's topic, e.g. to show the current on-call person or sprint goal. Returns CSV with channelID, channelName, field, previous and value. Subject to SLACK_MCP_CHANNEL_ADMIN_TOOL."),
+			mcp.WithTitleAnnotation("Set Channel Topic"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+			),
+			mcp.WithString("topic",
+				mcp.Required(),
+				mcp.Description("New topic, up to 250 characters, in Slack mrkdwn. An empty string clears the topic."),
+			),
+		), conversationsHandler.ConversationsSetTopicHandler)
+	}
+
+	if shouldAddTool(ToolConversationsSetPurpose, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsSetPurpose,
+			mcp.WithDescription("Replace a channel's purpose, the description shown in the channel details. Returns CSV with channelID, channelName, field, previous and value. Subject to SLACK_MCP_CHANNEL_ADMIN_TOOL."),
+			mcp.WithTitleAnnotation("Set Channel Purpose"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... aka #general."),
+			),
+			mcp.WithString("purpose",
+				mcp.Required(),
+				mcp.Description("New purpose, up to 250 characters. An empty string clears the purpose."),
+			),
+		), conversationsHandler.ConversationsSetPurposeHandler)
+	}
+
+	if shouldAddTool(ToolConversationsCleanup, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsCleanup,
+			mcp.WithDescription("Delete messages posted by the authenticated user or bot in one channel that are older than older_than and/or match a regex, e.g. to clear out a channel flooded by a notifier. Other people's messages are never touched. Runs as a dry run listing what would be deleted unless dry_run is false, and handles at most max_delete messages per call. Returns CSV with channelID, timestamp, time, text, status (would_delete, deleted or failed) and error, followed by JSON metadata with counts and next_latest to continue. Subject to SLACK_MCP_CLEANUP_TOOL."),
+			mcp.WithTitleAnnotation("Clean Up Own Messages"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("older_than",
+				mcp.Description("Only delete messages older than this, as a duration such as '36h' or a number of days or weeks such as '7d' or '2w'. older_than, pattern or both are required."),
+			),
+			mcp.WithString("pattern",
+				mcp.Description("Only delete messages whose raw text matches this Go regular expression, e.g. '^\\[deploy\\]' or '(?i)build (passed|failed)'."),
+			),
+			mcp.WithNumber("max_delete",
+				mcp.DefaultNumber(20),
+				mcp.Min(1),
+				mcp.Max(200),
+				mcp.Description("Most messages to delete in this call. Default 20, at most 200."),
+			),
+			mcp.WithBoolean("dry_run",
+				mcp.DefaultBool(true),
+				mcp.Description("List the messages that would be deleted without deleting them. Defaults to true; pass false to delete."),
+			),
+			mcp.WithString("latest",
+				mcp.Description("Only look at messages before this Slack timestamp, e.g. next_latest from a previous call."),
+			),
+		), conversationsHandler.ConversationsCleanupHandler)
+	}
+
+	if shouldAddTool(ToolHuddlesStart, cfg) {
+		s.AddTool(mcp.NewTool(ToolHuddlesStart,
+			mcp.WithDescription("Get the huddle link of a channel or DM and, when invite is set, post it there mentioning the invited users and user groups, e.g. to spin up a huddle in #sev1 and pull in on-call. It does not call the calls or huddle endpoints and does not open the call itself, since Slack has no API for that: it only returns and posts the link, the huddle starts when the first person follows it and everyone else joins the same one. Returns JSON with channel_id, huddle_url, invited and message_ts. Subject to SLACK_MCP_ADD_MESSAGE_TOOL."),
+			mcp.WithTitleAnnotation("Start Huddle"),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. Use conversations_open_dm first for a huddle with people you have no DM with."),
+			),
+			mcp.WithString("invite",
+				mcp.Description("Comma-separated user IDs (Uxxxxxxxxxx), @handles or user group IDs (Sxxxxxxxxxx, e.g. the on-call group) to mention in the invitation, up to 30. If empty, only the link is returned and nothing is posted."),
+			),
+			mcp.WithString("message",
+				mcp.Description("Optional line added to the invitation, e.g. what the huddle is about."),
+			),
+		), conversationsHandler.HuddlesStartHandler)
+	}
+
+	if shouldAddTool(ToolReactionsAdd, cfg) {
 		s.AddTool(mcp.NewTool(ToolReactionsAdd,
 		mcp.WithDescription("Add an emoji reaction to a message in a public channel, private channel, or direct message (DM, or IM) conversation."),
 		mcp.WithDestructiveHintAnnotation(true),
@@ -203,7 +1052,37 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	), conversationsHandler.ReactionsAddHandler)
 	}
 
-	if shouldAddTool(ToolReactionsRemove, enabledTools, "SLACK_MCP_REACTION_TOOL") {
+	if shouldAddTool(ToolReactionsAddBulk, cfg) {
+		s.AddTool(mcp.NewTool(ToolReactionsAddBulk,
+			mcp.WithDescription("Add one emoji reaction to many messages, e.g. to acknowledge a batch of messages, or several emoji reactions to one message, in a single call. Reactions are added in order with rate limiting; a failing reaction does not stop the rest and one that is already there counts as done. Returns CSV with one row per reaction: index, channelID, timestamp, emoji, status (added, already_reacted or failed), error. Subject to the same channel policy as reactions_add."),
+			mcp.WithTitleAnnotation("Add Reactions (Bulk)"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("emoji",
+				mcp.Required(),
+				mcp.Description("Emoji name to add, without colons, e.g. 'eyes'. To add several emojis to one message, pass a comma-separated list, e.g. 'eyes,white_check_mark'."),
+			),
+			mcp.WithArray("messages",
+				mcp.MaxItems(100),
+				mcp.Description("Messages to react to. Each item is an object with 'channel_id' (ID or #name/@username_dm) and 'timestamp' (message timestamp in format 1234567890.123456). Use instead of channel_id and timestamp."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"channel_id": map[string]any{"type": "string"},
+						"timestamp":  map[string]any{"type": "string"},
+					},
+					"required": []string{"channel_id", "timestamp"},
+				}),
+			),
+			mcp.WithString("channel_id",
+				mcp.Description("Channel of a single message to add several emojis to, in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. Use with timestamp instead of messages."),
+			),
+			mcp.WithString("timestamp",
+				mcp.Description("Timestamp of the single message to add several emojis to, in format 1234567890.123456."),
+			),
+		), conversationsHandler.ReactionsAddBulkHandler)
+	}
+
+	if shouldAddTool(ToolReactionsRemove, cfg) {
 		s.AddTool(mcp.NewTool(ToolReactionsRemove,
 		mcp.WithDescription("Remove an emoji reaction from a message in a public channel, private channel, or direct message (DM, or IM) conversation."),
 		mcp.WithDestructiveHintAnnotation(true),
@@ -222,25 +1101,285 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	), conversationsHandler.ReactionsRemoveHandler)
 	}
 
-	if shouldAddTool(ToolAttachmentGetData, enabledTools, "SLACK_MCP_ATTACHMENT_TOOL") {
+	if shouldAddTool(ToolReactionsSearch, cfg) {
+		s.AddTool(mcp.NewTool(ToolReactionsSearch,
+			mcp.WithDescription("Find messages in a channel that received an emoji reaction between oldest and latest, e.g. all messages marked done with white_check_mark in #requests this week. Scans channel history and filters on reactions, so thread replies are not included. Returns the same CSV as conversations_history, followed by a JSON block with scanned, matched, truncated and next_latest; pass next_latest as latest to continue a truncated scan."),
+			mcp.WithTitleAnnotation("Search Messages by Reaction"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("reaction",
+				mcp.Required(),
+				mcp.Description("Emoji name with or without colons, e.g. 'white_check_mark', or the emoji itself, e.g. '✅'. Aliases such as '+1' and 'thumbsup' and all skin tones match."),
+			),
+			mcp.WithString("exclude_reaction",
+				mcp.Description("Skip messages that also have this reaction, e.g. reaction 'eyes' with exclude_reaction 'white_check_mark' for requests that are picked up but not done."),
+			),
+			mcp.WithNumber("min_count",
+				mcp.DefaultNumber(1),
+				mcp.Description("Only return messages where at least this many people added the reaction."),
+			),
+			mcp.WithString("oldest",
+				mcp.Description("Only scan messages at or after this time: a Slack timestamp, an ISO date (2024-03-01), a local time (2024-03-01 09:00) or RFC 3339 time, in the 'tz' timezone. Defaults to 7 days ago."),
+			),
+			mcp.WithString("latest",
+				mcp.Description("Only scan messages before this time, in the same formats as oldest. A date includes the whole day. Defaults to now."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(50),
+				mcp.Description("Maximum number of matching messages to return, between 1 and 200. At most 5000 messages are scanned per call."),
+			),
+			mcp.WithString("render",
+				mcp.DefaultString("plain"),
+				mcp.Description("Output format for message text: 'plain' (default) or 'markdown', as in conversations_history."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for oldest, latest and timestamps in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ReactionsSearchHandler)
+	}
+
+	if shouldAddTool(ToolConversationsTranscript, cfg) {
+		s.AddTool(mcp.NewTool(ToolConversationsTranscript,
+			mcp.WithDescription("Export a channel over a date range, or a single thread, as a readable Markdown or HTML transcript with resolved names, timestamps, thread replies indented under their parent and attachment links. Returned inline followed by a JSON block with messages, threads and truncated, or written to SLACK_MCP_TRANSCRIPT_DIR with output=file, in which case only the JSON block with the file path is returned."),
+			mcp.WithTitleAnnotation("Export Transcript"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of a thread's parent message in format 1234567890.123456 to export only that thread. oldest and latest are ignored then."),
+			),
+			mcp.WithString("oldest",
+				mcp.Description("Start of the range: a Slack timestamp, an ISO date (2024-03-01), a local time (2024-03-01 09:00) or RFC 3339 time, in the 'tz' timezone. Defaults to 7 days ago."),
+			),
+			mcp.WithString("latest",
+				mcp.Description("End of the range, in the same formats as oldest. A date includes the whole day. Defaults to now."),
+			),
+			mcp.WithBoolean("include_threads",
+				mcp.DefaultBool(true),
+				mcp.Description("If true, the replies of each thread are included, indented under their parent. Default is boolean true."),
+			),
+			mcp.WithString("format",
+				mcp.DefaultString("markdown"),
+				mcp.Description("Document format: 'markdown' (default) or 'html' for a standalone page."),
+			),
+			mcp.WithString("output",
+				mcp.DefaultString("inline"),
+				mcp.Description("'inline' (default) returns the document; 'file' writes it to SLACK_MCP_TRANSCRIPT_DIR, which must be configured."),
+			),
+			mcp.WithString("filename",
+				mcp.Description("File name for output=file, e.g. 'incident-2024-03-01'. Letters, digits, '.', '_' and '-' only; the .md or .html extension is added when missing. An existing file is overwritten. Defaults to a name with the current time."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for oldest, latest and timestamps in the transcript, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.ConversationsTranscriptHandler)
+	}
+
+	if shouldAddTool(ToolAdminAuditSearch, cfg) {
+		s.AddTool(mcp.NewTool(ToolAdminAuditSearch,
+			mcp.WithDescription("Search the Enterprise Grid audit logs, newest first, e.g. who archived a channel and when. Needs an org-level user token with the auditlogs:read scope (SLACK_MCP_AUDIT_TOKEN, or SLACK_MCP_XOXP_TOKEN when unset). Returns CSV with id, date, action, actorID, actorName, actorEmail, entityType, entityID, entityName, location, ipAddress, details (previous and new value as JSON) and cursor; a non-empty cursor in the last row means more entries are available."),
+			mcp.WithTitleAnnotation("Search Audit Logs"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("action",
+				mcp.Description("Comma-separated audit actions to match, e.g. 'channel_archive', 'user_channel_join,user_channel_leave', 'file_downloaded', 'user_login'."),
+			),
+			mcp.WithString("actor",
+				mcp.Description("User who performed the action: a user ID (Uxxxxxxxxxx or Wxxxxxxxxxx) or @username."),
+			),
+			mcp.WithString("entity",
+				mcp.Description("Object the action was performed on: a channel ID or #name, a user ID or @username, or a file, app or workspace ID. Archived channels may only resolve by ID."),
+			),
+			mcp.WithString("oldest",
+				mcp.Description("Start of the range: a Slack timestamp, an ISO date (2024-03-01), a local time (2024-03-01 09:00) or RFC 3339 time, in the 'tz' timezone."),
+			),
+			mcp.WithString("latest",
+				mcp.Description("End of the range, in the same formats as oldest. A date includes the whole day."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(100),
+				mcp.Description("Maximum number of entries to return, between 1 and 9999. Default is 100."),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor from the last row of a previous call to get the next page."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for oldest, latest and dates in the output, as an IANA name (e.g. 'Europe/Berlin') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), conversationsHandler.AdminAuditSearchHandler)
+	}
+
+	// The admin user tools need SLACK_MCP_ADMIN_USERS_TOOL=true, even when
+	// they are listed in SLACK_MCP_ENABLED_TOOLS.
+	if cfg.AdminUsersTool == "true" && shouldAddTool(ToolAdminUsersInvite, cfg) {
+		s.AddTool(mcp.NewTool(ToolAdminUsersInvite,
+			mcp.WithDescription("Invite someone by email to a workspace of an Enterprise Grid organization with admin.users.invite, adding them to the given channels, e.g. for a joiner ticket. Needs an org-level user token with the admin.users:write scope (SLACK_MCP_ADMIN_TOKEN, or SLACK_MCP_XOXP_TOKEN when unset). confirm must repeat the email and reason is written to the server's audit log. Returns JSON with action, team_id, email, channel_ids and reason. Subject to SLACK_MCP_ADMIN_USERS_TOOL."),
+			mcp.WithTitleAnnotation("Invite User to Workspace"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("email",
+				mcp.Required(),
+				mcp.Description("Email address to invite, e.g. 'ana@example.com'."),
+			),
+			mcp.WithString("channel_ids",
+				mcp.Required(),
+				mcp.Description("Comma-separated channel IDs or #names the person is added to, at least one, e.g. '#general,C0123456789'."),
+			),
+			mcp.WithString("confirm",
+				mcp.Required(),
+				mcp.Description("Must repeat email exactly, to confirm the invitation."),
+			),
+			mcp.WithString("reason",
+				mcp.Required(),
+				mcp.Description("Why the person is invited, e.g. the ticket 'IT-1234'. Written to the audit log."),
+			),
+			mcp.WithString("team_id",
+				mcp.Description("ID of the workspace to invite to (Txxxxxxxxxx). Defaults to the workspace of the server's token."),
+			),
+			mcp.WithString("real_name",
+				mcp.Description("Optional full name of the person."),
+			),
+			mcp.WithString("custom_message",
+				mcp.Description("Optional message included in the invitation email."),
+			),
+		), conversationsHandler.AdminUsersInviteHandler)
+	}
+
+	if cfg.AdminUsersTool == "true" && shouldAddTool(ToolAdminUsersRemove, cfg) {
+		s.AddTool(mcp.NewTool(ToolAdminUsersRemove,
+			mcp.WithDescription("Remove a user from a workspace of an Enterprise Grid organization with admin.users.remove and sign them out of every session, e.g. for a leaver ticket. The org-wide account is not deactivated; use SCIM or the admin console for that. Needs an org-level user token with the admin.users:write scope (SLACK_MCP_ADMIN_TOKEN, or SLACK_MCP_XOXP_TOKEN when unset). confirm must repeat user and reason is written to the server's audit log. Returns JSON with action, team_id, user_id, email, sessions_reset and reason. Subject to SLACK_MCP_ADMIN_USERS_TOOL."),
+			mcp.WithTitleAnnotation("Remove User from Workspace"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("user",
+				mcp.Required(),
+				mcp.Description("User to remove: a user ID (Uxxxxxxxxxx or Wxxxxxxxxxx), @handle or email address."),
+			),
+			mcp.WithString("confirm",
+				mcp.Required(),
+				mcp.Description("Must repeat user exactly, to confirm the removal."),
+			),
+			mcp.WithString("reason",
+				mcp.Required(),
+				mcp.Description("Why the user is removed, e.g. the leaver ticket 'IT-1234'. Written to the audit log."),
+			),
+			mcp.WithString("team_id",
+				mcp.Description("ID of the workspace to remove the user from (Txxxxxxxxxx). Defaults to the workspace of the server's token."),
+			),
+			mcp.WithBoolean("reset_sessions",
+				mcp.DefaultBool(true),
+				mcp.Description("Also sign the user out of every session on every device with admin.users.session.reset. Defaults to true."),
+			),
+		), conversationsHandler.AdminUsersRemoveHandler)
+	}
+
+	if len(cfg.Workflows) > 0 && shouldAddTool(ToolWorkflowsTrigger, cfg) {
+		s.AddTool(mcp.NewTool(ToolWorkflowsTrigger,
+			mcp.WithDescription("Start a Slack workflow configured for this server, such as an access request, by posting inputs to its webhook trigger, instead of reimplementing what the workflow does. The workflow runs as built in Slack and may post messages, open forms or ask people for approval. Returns JSON with workflow, inputs and started. Available workflows: "+workflowsDescription(cfg.Workflows)),
+			mcp.WithTitleAnnotation("Trigger Workflow"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("workflow",
+				mcp.Required(),
+				mcp.Enum(handler.WorkflowNames(cfg.Workflows)...),
+				mcp.Description("Name of the workflow to start."),
+			),
+			mcp.WithObject("inputs",
+				mcp.Description("Variables passed to the workflow's trigger, e.g. {\"system\": \"grafana\", \"reason\": \"on-call\"}. Values are strings; numbers and booleans are sent as text."),
+			),
+		), conversationsHandler.WorkflowsTriggerHandler)
+	}
+
+	if cfg.WriteJournal != "" && shouldAddTool(ToolPendingWrites, cfg) {
+		s.AddTool(mcp.NewTool(ToolPendingWrites,
+			mcp.WithDescription("List write tool calls, such as conversations_add_messages batches, that were cut short when the server last crashed or restarted, so it is unknown whether Slack applied them. Each entry has the tool, its arguments, when it started and, for batches, the items Slack accepted before the stop. Check the channel and redo only what is missing instead of repeating the whole call, then acknowledge the entry. Returns JSON with pending and acknowledged."),
+			mcp.WithTitleAnnotation("Pending Writes"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("acknowledge",
+				mcp.Description("Comma-separated IDs of pending writes that have been checked or redone, or 'all', to drop them from the list. Without it the list is only returned."),
+			),
+		), conversationsHandler.PendingWritesHandler)
+	}
+
+	if shouldAddTool(ToolAttachmentGetData, cfg) {
 		s.AddTool(mcp.NewTool(ToolAttachmentGetData,
-		mcp.WithDescription("Download an attachment's content by file ID. Returns file metadata and content (text files as-is, binary files as base64). Maximum file size is 5MB."),
+		mcp.WithDescription("Download an attachment's content by file ID. Returns file metadata and content (text files as-is, binary files as base64), or with preview=true a downscaled image preview. Maximum file size is 5MB."),
 		mcp.WithTitleAnnotation("Get Attachment Data"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("file_id",
 			mcp.Required(),
 			mcp.Description("The ID of the attachment to download, in format Fxxxxxxxxxx. Attachment IDs can be found in message metadata when HasMedia is true or AttachmentCount > 0."),
 		),
+		mcp.WithBoolean("preview",
+			mcp.Description("For PNG, JPEG and GIF images: if true, return a downscaled JPEG preview as image content instead of the full file, to look at screenshots without the full base64 payload. Default is boolean false."),
+		),
+		mcp.WithNumber("preview_max_dimension",
+			mcp.DefaultNumber(768),
+			mcp.Description("Longest side of the preview in pixels, between 32 and 2048. Only used with preview=true; images already within it are returned unchanged."),
+		),
 	), conversationsHandler.FilesGetHandler)
 	}
 
+	filesHandler := handler.NewFilesHandler(provider, logger)
+	if shouldAddTool(ToolFilesList, cfg) {
+		s.AddTool(mcp.NewTool(ToolFilesList,
+			mcp.WithDescription("List files shared in a channel and/or uploaded by a user, newest first, optionally within a date range. Use it to discover file IDs for attachment_get_data. Returns CSV with id, name, title, type, mimetype, size, uploaderID, uploaderName, created, channels, permalink and cursor."),
+			mcp.WithTitleAnnotation("List Files"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. If not provided, files from all conversations visible to the token are listed."),
+			),
+			mcp.WithString("user",
+				mcp.Description("Only list files uploaded by this user: a user ID (e.g., 'U1234567890') or handle (e.g., '@john')."),
+			),
+			mcp.WithString("since",
+				mcp.Description("Only list files created on or after this date. Example: 'Today', 'Yesterday', '3 days ago' or '2025-01-31'."),
+			),
+			mcp.WithString("until",
+				mcp.Description("Only list files created on or before this date (inclusive). Same formats as since."),
+			),
+			mcp.WithString("types",
+				mcp.Description("Comma-separated file types to include. Allowed values: 'all', 'spaces', 'snippets', 'images', 'gdocs', 'zips', 'pdfs'. If not provided, all types are listed."),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor for pagination. Use the value from the last row's cursor column in the previous response."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("The maximum number of files to return. Must be an integer between 1 and 100."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), filesHandler.FilesListHandler)
+	}
+
+	if shouldAddTool(ToolFilesInfo, cfg) {
+		s.AddTool(mcp.NewTool(ToolFilesInfo,
+			mcp.WithDescription("Get a file's metadata without downloading it: name, title, type, mimetype, size, uploader, created, the messages it was shared in, permalink, whether attachment_get_data can download it, and for text files the first lines as preview. Use it to decide whether a file is worth downloading. Returns JSON."),
+			mcp.WithTitleAnnotation("Get File Info"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("file_id",
+				mcp.Required(),
+				mcp.Description("The ID of the file, in format Fxxxxxxxxxx, from files_list or message metadata."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), filesHandler.FilesInfoHandler)
+	}
+
 	conversationsSearchTool := mcp.NewTool(ToolConversationsSearchMessages,
-		mcp.WithDescription("Search messages in a public channel, private channel, or direct message (DM, or IM) conversation using filters. All filters are optional, if not provided then search_query is required."),
+		mcp.WithDescription("Search messages in a public channel, private channel, or direct message (DM, or IM) conversation using filters. All filters are optional, if not provided then search_query is required. Returns one page of CSV rows followed by JSON metadata with the total number of matches, page, page_count, has_more and next_cursor; when the total is large, narrow the query with filters instead of paging through every match."),
 		mcp.WithTitleAnnotation("Search Messages"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("search_query",
 			mcp.Description("Search query to filter messages. Example: 'marketing report' or full URL of Slack message e.g. 'https://slack.com/archives/C1234567890/p1234567890123456', then the tool will return a single message matching given URL, herewith all other parameters will be ignored."),
 		),
+		mcp.WithString("search_modifiers",
+			mcp.Description("Space-separated Slack search modifiers appended to the query. Supported keys: is, has, in, from, to, with, before, after, on, during. Example: 'is:thread has:link has::eyes: in:#general from:@alice'. Channel and user references are resolved to IDs; unknown keys or values are rejected."),
+		),
 		mcp.WithString("filter_in_channel",
 			mcp.Description("Filter messages in a specific public/private channel by its ID or name. Example: 'C1234567890', 'G1234567890', or '#general'. If not provided, all channels will be searched."),
 		),
@@ -268,6 +1407,40 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		mcp.WithBoolean("filter_threads_only",
 			mcp.Description("If true, the response will include only messages from threads. Default is boolean false."),
 		),
+		mcp.WithBoolean("is_thread",
+			mcp.Description("If true, only messages in threads are returned (adds is:thread). Same as filter_threads_only. Default is boolean false."),
+		),
+		mcp.WithBoolean("has_reactions",
+			mcp.Description("If true, only messages with at least one reaction are returned (adds has:reaction). Default is boolean false."),
+		),
+		mcp.WithBoolean("has_files",
+			mcp.Description("If true, only messages with attached files are returned (adds has:file). Default is boolean false."),
+		),
+		mcp.WithBoolean("has_links",
+			mcp.Description("If true, only messages containing links are returned (adds has:link). Default is boolean false."),
+		),
+		mcp.WithBoolean("include_unfurls",
+			mcp.Description("If true, adds UnfurlURLs, UnfurlTitles, UnfurlDescriptions and UnfurlServices columns with the link previews Slack attached to each message, so shared links can be understood without fetching them. Several links are joined with '|'. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("sort",
+			mcp.Enum("score", "timestamp"),
+			mcp.Description("Order of the results: 'score' (default) sorts by relevance, 'timestamp' by message time."),
+		),
+		mcp.WithString("sort_dir",
+			mcp.Enum("desc", "asc"),
+			mcp.Description("Sort direction: 'desc' (default) returns the best or most recent matches first, 'asc' the reverse. Use sort='timestamp' with sort_dir='desc' for most recent first."),
+		),
+		mcp.WithString("post_filter_regex",
+			mcp.Description("Regular expression (RE2 syntax) applied to the returned page, keeping only messages whose raw text or attachments match, for exact patterns Slack search cannot express. Example: 'ERR-\\d{4}' with search_query 'ERR'. Prefix (?i) to ignore case. Metadata post_filtered counts the removed matches; a page may then return fewer rows than limit while has_more is true."),
+		),
+		mcp.WithBoolean("include_language",
+			mcp.Description("If true, adds a Language column with the ISO 639-1 code of each message's language, or 'und', as in conversations_history. Default is boolean false."),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithString("filter_language",
+			mcp.Description("Comma-separated ISO 639-1 codes, e.g. 'en' or 'ja', keeping only the messages detected in one of these languages; add 'und' for messages whose language cannot be told, as in conversations_history. Metadata language_filtered counts the removed matches; a page may then return fewer rows than limit while has_more is true."),
+		),
 		mcp.WithString("cursor",
 			mcp.DefaultString(""),
 			mcp.Description("Cursor for pagination. Use the value of the last row and column in the response as next_cursor field returned from the previous request."),
@@ -276,14 +1449,139 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.DefaultNumber(20),
 			mcp.Description("The maximum number of items to return. Must be an integer between 1 and 100."),
 		),
+		mcp.WithString("render",
+			mcp.DefaultString("plain"),
+			mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn (mentions, links, bold, strikethrough, emoji codes, code blocks) to standard Markdown with resolved user and channel names."),
+		),
+		mcp.WithString("tz",
+			mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset. Rows carry an ISO-8601 time with offset plus a human-readable timeHuman column."),
+		),
 	)
-	// Only register search tool for non-bot tokens (bot tokens cannot use search.messages API)
-	if !provider.IsBotToken() && shouldAddTool(ToolConversationsSearchMessages, enabledTools, "") {
-		s.AddTool(conversationsSearchTool, conversationsHandler.ConversationsSearchHandler)
+	if len(cfg.SearchTemplates) > 0 {
+		mcp.WithString("template",
+			mcp.Enum(handler.SearchTemplateNames(cfg.SearchTemplates)...),
+			mcp.Description("Named search configured on the server, expanding into preset arguments; other arguments override them, and search_query and search_modifiers are added to the template's. Templates: "+searchTemplatesDescription(cfg.SearchTemplates)),
+		)(&conversationsSearchTool)
+	}
+	// Bot tokens cannot use the search.messages API; they get a search that
+	// scans the history of named channels instead.
+	if shouldAddTool(ToolConversationsSearchMessages, cfg) {
+		if provider.IsBotToken() {
+			s.AddTool(botSearchTool(), conversationsHandler.ConversationsBotSearchHandler)
+		} else {
+			s.AddTool(conversationsSearchTool, conversationsHandler.ConversationsSearchHandler)
+		}
+	}
+
+	// Mentions inbox is built on search.messages, which bot tokens cannot use
+	mentionsHandler := handler.NewMentionsHandler(provider, logger)
+	if !provider.IsBotToken() && shouldAddTool(ToolActivityMentions, cfg) {
+		s.AddTool(mcp.NewTool(ToolActivityMentions,
+			mcp.WithDescription("List recent messages that mention the authenticated user, plus direct messages sent to them, newest first. Use it to build a 'what needs my reply' view without searching every channel. Returns CSV with msgID, time, timeHuman, channelID, channelName, userID, userName, realName, threadTs, kind (mention or dm), snippet and permalink."),
+			mcp.WithTitleAnnotation("List Mentions"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("since",
+				mcp.Description("Only return messages from this date on. Example: 'Today', 'Yesterday', '3 days ago' or '2025-01-31'. If not provided, the most recent mentions are returned regardless of date."),
+			),
+			mcp.WithBoolean("include_dms",
+				mcp.Description("If true, direct messages sent to the user are included in addition to @-mentions. Default is boolean true."),
+				mcp.DefaultBool(true),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("The maximum number of items to return. Must be an integer between 1 and 100."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset. Rows carry an ISO-8601 time with offset plus a human-readable timeHuman column."),
+			),
+		), mentionsHandler.MentionsHandler)
+	}
+
+	// The activity feed is built on search.messages too
+	activityHandler := handler.NewActivityHandler(provider, logger)
+	if !provider.IsBotToken() && shouldAddTool(ToolActivityFeed, cfg) {
+		s.AddTool(mcp.NewTool(ToolActivityFeed,
+			mcp.WithDescription("Summarize what the authenticated user did over a date range in one call: messages sent, thread replies, reactions given and received, and channels joined, newest first. Use it for weekly self-reports instead of searching piece by piece. Returns CSV with kind (message, thread_reply, reaction_given, reaction_received or channel_joined), msgID, time, timeHuman, channelID, channelName, threadTs, reaction, count, snippet and permalink, followed by JSON totals (messages_sent, thread_replies, threads_replied_to, reactions_given, reactions_received, channels_joined) and the sections that hit a lookup cap."),
+			mcp.WithTitleAnnotation("My Activity"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("since",
+				mcp.Description("First day of the range. Example: 'Yesterday', '7 days ago' or '2025-01-27'. Defaults to 7 days ago, today included."),
+			),
+			mcp.WithString("until",
+				mcp.Description("Last day of the range, included. Defaults to today."),
+			),
+			mcp.WithString("include",
+				mcp.Description("Comma-separated sections to compute: 'messages', 'reactions_given', 'reactions_received', 'channels_joined'. Defaults to all. Leave out the sections you do not need to save API calls."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(200),
+				mcp.Description("The maximum number of rows to return. Must be an integer between 1 and 1000. Totals are computed before this limit is applied."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for the date range and the timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), activityHandler.ActivityFeedHandler)
+	}
+
+	// The Threads view is an internal API only reachable with browser session tokens
+	threadsHandler := handler.NewThreadsHandler(provider, logger)
+	if !provider.IsOAuth() && shouldAddTool(ToolActivityThreads, cfg) {
+		s.AddTool(mcp.NewTool(ToolActivityThreads,
+			mcp.WithDescription("List threads the authenticated user participates in (the Slack 'Threads' view), most recently active first. Use it to catch up on threads: rows with hasUnread=true have replies since the user last read them. Returns CSV with channelID, channelName, threadTs, time, timeHuman, userName, snippet, replyCount, latestReply, lastReplyUser, lastReplySnippet, unreadReplies, hasUnread, permalink and cursor, followed by JSON with total_unread_replies and new_threads_count."),
+			mcp.WithTitleAnnotation("List My Threads"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithBoolean("unread_only",
+				mcp.Description("If true, only threads with replies since the user last read them are returned. Default is boolean false."),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor for pagination. Use the value from the last row's cursor column in the previous response."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(20),
+				mcp.Description("The maximum number of threads to return. Must be an integer between 1 and 50."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset. Rows carry an ISO-8601 time with offset plus a human-readable timeHuman column."),
+			),
+		), threadsHandler.ThreadsDigestHandler)
+	}
+
+	// Thread subscriptions use the same internal API as the Threads view
+	if !provider.IsOAuth() && shouldAddTool(ToolThreadsFollow, cfg) {
+		s.AddTool(mcp.NewTool(ToolThreadsFollow,
+			mcp.WithDescription("Follow a thread for the authenticated user, as 'Get notified about new replies' does, so its replies show up in activity_threads and notify the user. Use it for threads the user should keep track of without replying. Returns JSON with channel_id, thread_ts and following. Subject to SLACK_MCP_THREAD_FOLLOW_TOOL."),
+			mcp.WithTitleAnnotation("Follow Thread"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Required(),
+				mcp.Description("Timestamp of the thread's parent message in format 1234567890.123456."),
+			),
+		), conversationsHandler.ThreadsFollowHandler)
+	}
+
+	if !provider.IsOAuth() && shouldAddTool(ToolThreadsUnfollow, cfg) {
+		s.AddTool(mcp.NewTool(ToolThreadsUnfollow,
+			mcp.WithDescription("Unfollow a thread for the authenticated user, including one they replied in, to stop notifications about its replies and drop it from activity_threads. Returns JSON with channel_id, thread_ts and following. Subject to SLACK_MCP_THREAD_FOLLOW_TOOL."),
+			mcp.WithTitleAnnotation("Unfollow Thread"),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("channel_id",
+				mcp.Required(),
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Required(),
+				mcp.Description("Timestamp of the thread's parent message in format 1234567890.123456."),
+			),
+		), conversationsHandler.ThreadsUnfollowHandler)
 	}
 
 	s.AddTool(mcp.NewTool("users_search",
-		mcp.WithDescription("Search for users by name, email, or display name. Returns user details and DM channel ID if available."),
+		mcp.WithDescription("Search for users by name, email, or display name. Returns user details including title, avatar URL, status and whether the user is a bot or deactivated, the DM channel ID if available, timezone, UTC offset, locale and working hours for scheduling, and presence, Do Not Disturb and out-of-office columns with filters on them to pick who can be reached now."),
 		mcp.WithTitleAnnotation("Search Users"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("query",
@@ -294,23 +1592,60 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.DefaultNumber(10),
 			mcp.Description("Maximum number of results to return (1-100). Default is 10."),
 		),
+		mcp.WithBoolean("include_working_hours",
+			mcp.DefaultBool(true),
+			mcp.Description("If true (default), add a WorkingHours hint derived from each user's Do Not Disturb schedule, e.g. '08:00-22:00' in their timezone, to help pick meeting times. Needs the dnd:read scope; set false to skip the lookup."),
+		),
+		mcp.WithBoolean("include_deleted",
+			mcp.Description("If true, deactivated users are returned too, with IsDeleted set. Default is boolean false."),
+		),
+		mcp.WithBoolean("include_dm_channel",
+			mcp.Description("If true, opens the DM with each matching user that has none yet, so DMChannelID is always filled for messaging. Bots, deactivated users and users outside SLACK_MCP_DM_ALLOWLIST are skipped; at most 20 DMs are opened per call. Default is boolean false."),
+		),
+		mcp.WithBoolean("include_presence",
+			mcp.Description("If true, fills the Presence column ('active' or 'away') of up to 30 users; each is a users.getPresence call. Needs the users:read scope. Default is boolean false."),
+		),
+		mcp.WithString("presence",
+			mcp.Enum("active", "away"),
+			mcp.Description("Only return users with this presence. Bots and users whose presence could not be looked up are left out."),
+		),
+		mcp.WithBoolean("dnd",
+			mcp.Description("If set, only return users whose Do Not Disturb schedule is (true) or is not (false) in effect now, as shown in InDND. Manual snoozes are not visible to other users."),
+		),
+		mcp.WithBoolean("out_of_office",
+			mcp.Description("If set, only return users who are (true) or are not (false) out of office, as shown in OutOfOffice: guessed from a status emoji like :palm_tree: or :face_with_thermometer: or a status text like 'OOO', 'vacation', 'PTO' or 'sick'. For who to contact now, combine presence='active', dnd=false and out_of_office=false."),
+		),
 	), conversationsHandler.UsersSearchHandler)
 
 	channelsHandler := handler.NewChannelsHandler(provider, logger)
 	usergroupsHandler := handler.NewUsergroupsHandler(provider, logger)
 
-	if shouldAddTool(ToolChannelsList, enabledTools, "") {
+	if shouldAddTool(ToolChannelsList, cfg) {
 		s.AddTool(mcp.NewTool(ToolChannelsList,
 		mcp.WithDescription("Get list of channels"),
 		mcp.WithTitleAnnotation("List Channels"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithString("channel_types",
-			mcp.Required(),
-			mcp.Description("Comma-separated channel types. Allowed values: 'mpim', 'im', 'public_channel', 'private_channel'. Example: 'public_channel,private_channel,im'"),
+			mcp.DefaultString("public_channel,private_channel"),
+			mcp.Description("Comma-separated channel types. Allowed values: 'mpim', 'im', 'public_channel', 'private_channel'. Example: 'public_channel,private_channel,im'. Defaults to public and private channels."),
 		),
 		mcp.WithString("sort",
 			mcp.Description("Type of sorting. Allowed values: 'popularity' - sort by number of members/participants in each channel."),
 		),
+		mcp.WithString("name_contains",
+			mcp.Description("Only return channels whose name contains this text (case-insensitive, leading # or @ ignored). Example: 'eng' matches #engineering and #eng-oncall. Use it to find a channel without listing the whole workspace."),
+		),
+		mcp.WithString("name_prefix",
+			mcp.Description("Only return channels whose name starts with this text (case-insensitive, leading # or @ ignored). Example: 'team-' matches #team-payments but not #dream-team."),
+		),
+		mcp.WithBoolean("member_of_only",
+			mcp.DefaultBool(false),
+			mcp.Description("If true, only return channels the authenticated user has joined (DMs and group DMs always count). Combine with name_prefix or name_contains to find 'channels I'm in that match X' without listing the whole workspace."),
+		),
+		mcp.WithBoolean("include_unread",
+			mcp.DefaultBool(false),
+			mcp.Description("Add lastRead (timestamp of the last message the user has read) and unreadCount columns, to find what you have not read in one call. Costs one extra API call per channel, so it is applied to the first 100 channels only; narrow the list with channel_types or name_contains."),
+		),
 		mcp.WithNumber("limit",
 			mcp.DefaultNumber(100),
 			mcp.Description("The maximum number of items to return. Must be an integer between 1 and 1000 (maximum 999)."), // context fix for cursor: https://github.com/korotovsky/slack-mcp-server/issues/7
@@ -321,8 +1656,35 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 	), channelsHandler.ChannelsHandler)
 	}
 
+	if shouldAddTool(ToolDirectoryList, cfg) {
+		directoryHandler := handler.NewDirectoryHandler(provider, logger)
+		s.AddTool(mcp.NewTool(ToolDirectoryList,
+			mcp.WithDescription("Page through the workspace's users or channels directory, the same data as the slack://<workspace>/users and slack://<workspace>/channels resources, for clients that do not show resources. Served from the caches, sorted by name. Returns CSV with the resource's columns plus a cursor column filled on the last row when more pages exist, followed by JSON metadata with kind, total, returned, next_cursor and warming (the cache is still filling, so the list may be incomplete)."),
+			mcp.WithTitleAnnotation("List Directory"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("kind",
+				mcp.Required(),
+				mcp.Enum("users", "channels"),
+				mcp.Description("Directory to list: 'users' (UserID, UserName, RealName, TZ, TZOffset, Locale, WorkingHours) or 'channels' (ID, Name, Topic, Purpose, MemberCount)."),
+			),
+			mcp.WithString("query",
+				mcp.Description("Only return entries containing this text, case-insensitively: in the handle, real name or display name of users, or in the name, topic or purpose of channels. A leading @ or # is ignored."),
+			),
+			mcp.WithString("channel_types",
+				mcp.Description("For kind=channels, comma-separated channel types to return: 'public_channel', 'private_channel', 'im', 'mpim'. Defaults to all."),
+			),
+			mcp.WithNumber("limit",
+				mcp.DefaultNumber(100),
+				mcp.Description("The maximum number of entries to return, 1 to 1000. Default is 100."),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("Cursor for pagination, from the cursor column of the last row or next_cursor of the previous response, with the same kind, query and channel_types."),
+			),
+		), directoryHandler.DirectoryListHandler)
+	}
+
 	// User groups tools
-	if shouldAddTool(ToolUsergroupsList, enabledTools, "") {
+	if shouldAddTool(ToolUsergroupsList, cfg) {
 		s.AddTool(mcp.NewTool(ToolUsergroupsList,
 			mcp.WithDescription("List all user groups (subteams) in the Slack workspace. User groups are mention groups like @engineering or @design that notify all members. Use this to discover available groups, check group membership counts, or find a group's ID before joining/updating it. Returns CSV with columns: id, name, handle, description, user_count, is_external."),
 			mcp.WithTitleAnnotation("List User Groups"),
@@ -342,7 +1704,7 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usergroupsHandler.UsergroupsListHandler)
 	}
 
-	if shouldAddTool(ToolUsergroupsMe, enabledTools, "") {
+	if shouldAddTool(ToolUsergroupsMe, cfg) {
 		s.AddTool(mcp.NewTool(ToolUsergroupsMe,
 			mcp.WithDescription("Manage your own user group membership. Use action='list' to see which groups you belong to. Use action='join' with a usergroup_id to add yourself to a group (e.g., to receive @mentions). Use action='leave' with a usergroup_id to remove yourself. This is the easiest way to join/leave groups without needing to know the full member list."),
 			mcp.WithTitleAnnotation("My User Groups"),
@@ -356,7 +1718,7 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usergroupsHandler.UsergroupsMeHandler)
 	}
 
-	if shouldAddTool(ToolUsergroupsCreate, enabledTools, "") {
+	if shouldAddTool(ToolUsergroupsCreate, cfg) {
 		s.AddTool(mcp.NewTool(ToolUsergroupsCreate,
 			mcp.WithDescription("Create a new user group (mention group) in the Slack workspace. After creation, use usergroups_users_update to add members, or users can join themselves with usergroups_me. The handle becomes the @mention (e.g., handle='engineering' creates @engineering)."),
 			mcp.WithTitleAnnotation("Create User Group"),
@@ -374,10 +1736,11 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 			mcp.WithString("channels",
 				mcp.Description("Comma-separated channel IDs where this group is commonly mentioned. Members get suggestions to join these channels."),
 			),
+			withIdempotencyKey(),
 		), usergroupsHandler.UsergroupsCreateHandler)
 	}
 
-	if shouldAddTool(ToolUsergroupsUpdate, enabledTools, "") {
+	if shouldAddTool(ToolUsergroupsUpdate, cfg) {
 		s.AddTool(mcp.NewTool(ToolUsergroupsUpdate,
 			mcp.WithDescription("Update a user group's metadata: name, handle (@mention), description, or default channels. Does NOT change members - use usergroups_users_update for that. At least one field must be provided."),
 			mcp.WithTitleAnnotation("Update User Group"),
@@ -401,9 +1764,9 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usergroupsHandler.UsergroupsUpdateHandler)
 	}
 
-	if shouldAddTool(ToolUsergroupsUsersUpdate, enabledTools, "") {
+	if shouldAddTool(ToolUsergroupsUsersUpdate, cfg) {
 		s.AddTool(mcp.NewTool(ToolUsergroupsUsersUpdate,
-			mcp.WithDescription("Replace all members of a user group with a new list. WARNING: This completely replaces the member list - any user not in the 'users' parameter will be removed. To add/remove just yourself, use usergroups_me instead. To add a single user without removing others, first get current members from usergroups_list with include_users=true, then call this with the combined list."),
+			mcp.WithDescription("Replace all members of a user group with a new list. WARNING: This completely replaces the member list - any user not in the 'users' parameter will be removed. To add/remove just yourself, use usergroups_me instead. To add or remove some users without touching the others, use usergroups_users_modify."),
 			mcp.WithTitleAnnotation("Update User Group Members"),
 			mcp.WithDestructiveHintAnnotation(true),
 			mcp.WithString("usergroup_id",
@@ -417,20 +1780,79 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 		), usergroupsHandler.UsergroupsUsersUpdateHandler)
 	}
 
+	if shouldAddTool(ToolUsergroupsUsersModify, cfg) {
+		s.AddTool(mcp.NewTool(ToolUsergroupsUsersModify,
+			mcp.WithDescription("Add and/or remove members of a user group while keeping everyone else. Reads the current members, applies the changes and writes the merged list, so unlike usergroups_users_update it never drops members by accident. Adding an existing member or removing a non-member is not an error; nothing is written when no member changes. Returns JSON with the updated group (id, name, handle, user_count, users) plus added, removed, already_members and not_members."),
+			mcp.WithTitleAnnotation("Modify User Group Members"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithString("usergroup_id",
+				mcp.Required(),
+				mcp.Description("ID of the user group (starts with 'S', e.g., 'S0123456789'). Get IDs from usergroups_list."),
+			),
+			mcp.WithString("add_users",
+				mcp.Description("Comma-separated user IDs to add (e.g., 'U0123456789,U9876543210')."),
+			),
+			mcp.WithString("remove_users",
+				mcp.Description("Comma-separated user IDs to remove. A group must keep at least one member; to retire it use usergroups_disable."),
+			),
+		), usergroupsHandler.UsergroupsUsersModifyHandler)
+	}
+
+	if shouldAddTool(ToolUsergroupsDisable, cfg) {
+		s.AddTool(mcp.NewTool(ToolUsergroupsDisable,
+			mcp.WithDescription("Disable a user group so its @handle no longer notifies anyone, or re-enable a disabled one with action='enable'. Members and settings are kept. Disabling requires confirm=true. Disabled groups are listed by usergroups_list with include_disabled=true."),
+			mcp.WithTitleAnnotation("Disable or Enable User Group"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("usergroup_id",
+				mcp.Required(),
+				mcp.Description("ID of the user group (starts with 'S', e.g., 'S0123456789'). Get IDs from usergroups_list."),
+			),
+			mcp.WithString("action",
+				mcp.DefaultString("disable"),
+				mcp.Enum("disable", "enable"),
+				mcp.Description("'disable' (default) retires the group; 'enable' restores a disabled group."),
+			),
+			mcp.WithBoolean("confirm",
+				mcp.DefaultBool(false),
+				mcp.Description("Must be true to disable a group. Not needed to re-enable."),
+			),
+		), usergroupsHandler.UsergroupsDisableHandler)
+	}
+
 	// Saved items (Save for Later)
 	savedHandler := handler.NewSavedHandler(provider, logger)
-	if shouldAddTool(ToolSavedList, enabledTools, "SLACK_MCP_SAVED_LIST_TOOL") {
+	if shouldAddTool(ToolSavedList, cfg) {
 		s.AddTool(mcp.NewTool(ToolSavedList,
-			mcp.WithDescription("List your 'Save for Later' items from Slack. Returns saved messages with channel, timestamp, state, and due dates. Use cursor for pagination."),
+			mcp.WithDescription("List your 'Save for Later' items from Slack. Returns saved messages with channel, timestamp, thread_ts (set for thread replies), state, and due dates. Filters and sorting are applied server-side. Use cursor for pagination."),
 			mcp.WithTitleAnnotation("List Saved Items"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithString("cursor",
 				mcp.Description("Cursor for pagination. Use the value from the last row's cursor column in the previous response."),
 			),
+			mcp.WithString("state",
+				mcp.Description("Only return items in this state. Allowed values: 'in_progress', 'completed', 'archived'. If not provided, items in all states are returned."),
+			),
+			mcp.WithString("due_before",
+				mcp.Description("Only return items due before this date. Accepts 'YYYY-MM-DD', an RFC3339 timestamp, or relative dates such as 'Today' or 'Yesterday'. Items without a due date are excluded."),
+			),
+			mcp.WithString("due_after",
+				mcp.Description("Only return items due after this date. Accepts 'YYYY-MM-DD', an RFC3339 timestamp, or relative dates such as 'Today' or 'Yesterday'. Items without a due date are excluded."),
+			),
+			mcp.WithString("sort",
+				mcp.Description("Sort order. Allowed values: 'date_due' - soonest due first, items without a due date last; 'date_created' - most recently saved first. If not provided, Slack's order is kept."),
+			),
+			mcp.WithString("render",
+				mcp.DefaultString("plain"),
+				mcp.Description("Output format for message text: 'plain' (default) strips Slack markup, 'markdown' converts Slack mrkdwn (mentions, links, bold, strikethrough, emoji codes, code blocks) to standard Markdown with resolved user and channel names."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
 		), savedHandler.SavedListHandler)
 	}
 
-	if shouldAddTool(ToolSavedComplete, enabledTools, "SLACK_MCP_SAVED_COMPLETE_TOOL") {
+	if shouldAddTool(ToolSavedComplete, cfg) {
 		s.AddTool(mcp.NewTool(ToolSavedComplete,
 			mcp.WithDescription("Mark a 'Save for Later' item as complete in Slack."),
 			mcp.WithTitleAnnotation("Complete Saved Item"),
@@ -443,55 +1865,165 @@ func NewMCPServer(provider *provider.ApiProvider, logger *zap.Logger, enabledToo
 				mcp.Required(),
 				mcp.Description("Timestamp of the saved message in format 1234567890.123456."),
 			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of the thread parent when the saved message is a thread reply. Use the thread_ts column from saved_list."),
+			),
 		), savedHandler.SavedCompleteHandler)
 	}
 
-	logger.Info("Authenticating with Slack API...",
-		zap.String("context", "console"),
-	)
-	ar, err := provider.Slack().AuthTest()
-	if err != nil {
-		logger.Fatal("Failed to authenticate with Slack",
-			zap.String("context", "console"),
-			zap.Error(err),
-		)
+	statusHandler := handler.NewStatusHandler(provider, logger)
+	if shouldAddTool(ToolUsersStatusGet, cfg) {
+		s.AddTool(mcp.NewTool(ToolUsersStatusGet,
+			mcp.WithDescription("Get the current custom status (text, emoji and expiration) of a user. Useful to check whether a teammate is in a meeting, out of office or otherwise busy before routing a request to them."),
+			mcp.WithTitleAnnotation("Get User Status"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("user",
+				mcp.Description("User ID (e.g., 'U1234567890') or handle (e.g., '@john'). Defaults to the authenticated user."),
+			),
+			mcp.WithString("tz",
+				mcp.Description("Timezone for timestamps in the output, as an IANA name (e.g. 'Europe/Berlin', 'America/New_York') or 'UTC'. Defaults to SLACK_MCP_TIMEZONE, or UTC when unset."),
+			),
+		), statusHandler.UsersStatusGetHandler)
 	}
 
-	logger.Info("Successfully authenticated with Slack",
-		zap.String("context", "console"),
-		zap.String("team", ar.Team),
-		zap.String("user", ar.User),
-		zap.String("enterprise", ar.EnterpriseID),
-		zap.String("url", ar.URL),
-	)
+	if shouldAddTool(ToolUsersStatusSet, cfg) {
+		s.AddTool(mcp.NewTool(ToolUsersStatusSet,
+			mcp.WithDescription("Set or clear the custom status of the authenticated user. Call with empty status_text and status_emoji to clear the status."),
+			mcp.WithTitleAnnotation("Set User Status"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("status_text",
+				mcp.Description("Status text, up to 100 characters (e.g., 'In a meeting'). Empty to clear."),
+			),
+			mcp.WithString("status_emoji",
+				mcp.Description("Status emoji (e.g., ':calendar:'). Empty to clear."),
+			),
+			mcp.WithString("expiration",
+				mcp.Description("When the status expires: a duration ('30m', '2h'), a time of day in server local time ('15:00', '3pm'), an RFC3339 timestamp, or Unix seconds. Empty or '0' means it never expires."),
+			),
+		), statusHandler.UsersStatusSetHandler)
+	}
 
-	ws, err := text.Workspace(ar.URL)
-	if err != nil {
-		logger.Fatal("Failed to parse workspace from URL",
-			zap.String("context", "console"),
-			zap.String("url", ar.URL),
-			zap.Error(err),
-		)
+	if shouldAddTool(ToolUsersProfileSet, cfg) {
+		profileHandler := handler.NewProfileHandler(provider, logger)
+		s.AddTool(mcp.NewTool(ToolUsersProfileSet,
+			mcp.WithDescription("Update text fields of the authenticated user's own profile: title, pronouns, phone, display name and the workspace's custom profile fields (see team_info for their IDs and labels). Only the parameters passed are changed; an empty value clears a field. The profile photo cannot be changed. Returns the saved values as JSON."),
+			mcp.WithTitleAnnotation("Set Own Profile Fields"),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithString("title",
+				mcp.Description("Job title, e.g. 'Support Engineer'."),
+			),
+			mcp.WithString("pronouns",
+				mcp.Description("Pronouns, e.g. 'they/them'."),
+			),
+			mcp.WithString("phone",
+				mcp.Description("Phone number."),
+			),
+			mcp.WithString("display_name",
+				mcp.Description("Display name shown in messages instead of the full name."),
+			),
+			mcp.WithObject("custom_fields",
+				mcp.Description("Custom profile fields as a JSON object of field ID (e.g. 'Xf0123ABCD') or label (e.g. 'Team') to value, e.g. {\"Team\": \"Payments\", \"Start date\": \"2025-06-02\"}. Fields managed by an identity provider cannot be changed."),
+			),
+		), profileHandler.UsersProfileSetHandler)
 	}
 
-	s.AddResource(mcp.NewResource(
-		"slack://"+ws+"/channels",
-		"Directory of Slack channels",
-		mcp.WithResourceDescription("This resource provides a directory of Slack channels."),
-		mcp.WithMIMEType("text/csv"),
-	), channelsHandler.ChannelsResource)
+	assistantHandler := handler.NewAssistantHandler(provider, logger)
+	assistantThreadParams := []mcp.ToolOption{
+		mcp.WithString("channel_id",
+			mcp.Required(),
+			mcp.Description("ID of the assistant's DM with the user, in format Dxxxxxxxxxx, from the assistant_thread_started event."),
+		),
+		mcp.WithString("thread_ts",
+			mcp.Required(),
+			mcp.Description("Timestamp of the assistant thread in format 1234567890.123456, from the assistant_thread_started event."),
+		),
+	}
 
-	s.AddResource(mcp.NewResource(
-		"slack://"+ws+"/users",
-		"Directory of Slack users",
-		mcp.WithResourceDescription("This resource provides a directory of Slack users."),
-		mcp.WithMIMEType("text/csv"),
-	), conversationsHandler.UsersResource)
+	if shouldAddTool(ToolAssistantSetStatus, cfg) {
+		s.AddTool(mcp.NewTool(ToolAssistantSetStatus, append([]mcp.ToolOption{
+			mcp.WithDescription("Show a status such as 'is looking through the incident channel...' under a Slack AI assistant thread while working on the user's request, or clear it. Slack clears the status when the app replies. Requires the bot token of an app with the Agents & AI Apps feature. Returns JSON."),
+			mcp.WithTitleAnnotation("Set Assistant Thread Status"),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("status",
+				mcp.Description("Status text shown after the app's name, e.g. 'is thinking...'. An empty value clears the status."),
+			),
+		}, assistantThreadParams...)...), assistantHandler.AssistantThreadsSetStatusHandler)
+	}
+
+	if shouldAddTool(ToolAssistantSetTitle, cfg) {
+		s.AddTool(mcp.NewTool(ToolAssistantSetTitle, append([]mcp.ToolOption{
+			mcp.WithDescription("Set the title of a Slack AI assistant thread, shown in the user's history of conversations with the app, e.g. a summary of the first question. Requires the bot token of an app with the Agents & AI Apps feature. Returns JSON."),
+			mcp.WithTitleAnnotation("Set Assistant Thread Title"),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("title",
+				mcp.Required(),
+				mcp.Description("Title of the thread."),
+			),
+		}, assistantThreadParams...)...), assistantHandler.AssistantThreadsSetTitleHandler)
+	}
+
+	if shouldAddTool(ToolAssistantSetPrompts, cfg) {
+		s.AddTool(mcp.NewTool(ToolAssistantSetPrompts, append([]mcp.ToolOption{
+			mcp.WithDescription("Offer up to 4 suggested prompts in a Slack AI assistant thread, replacing those offered before. Picking one sends its message as the user. Requires the bot token of an app with the Agents & AI Apps feature. Returns JSON."),
+			mcp.WithTitleAnnotation("Set Assistant Suggested Prompts"),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithArray("prompts",
+				mcp.Required(),
+				mcp.MinItems(1),
+				mcp.MaxItems(4),
+				mcp.Description("Prompts to offer. Each item is an object with 'title' (shown on the button) and 'message' (sent when it is picked)."),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"title":   map[string]any{"type": "string"},
+						"message": map[string]any{"type": "string"},
+					},
+					"required": []string{"title", "message"},
+				}),
+			),
+			mcp.WithString("title",
+				mcp.Description("Heading shown above the prompts, e.g. 'Try asking:'."),
+			),
+		}, assistantThreadParams...)...), assistantHandler.AssistantThreadsSetSuggestedPromptsHandler)
+	}
+
+	if shouldAddTool(ToolTeamInfo, cfg) {
+		teamHandler := handler.NewTeamHandler(provider, logger)
+		s.AddTool(mcp.NewTool(ToolTeamInfo,
+			mcp.WithDescription("Describe the workspace: name, domain, URL, icon, Enterprise Grid organization and default channels, plus the custom profile field schema. Use the field IDs to interpret the profile fields of users. Returns CSV with id, label, hint, type, ordering, possibleValues and isHidden per profile field, followed by the workspace as JSON."),
+			mcp.WithTitleAnnotation("Workspace Info"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithBoolean("include_hidden",
+				mcp.Description("If true, profile fields hidden from member profiles are included. Default is boolean false."),
+			),
+		), teamHandler.TeamInfoHandler)
+	}
 
-	return &MCPServer{
-		server: s,
-		logger: logger,
+	if shouldAddTool(ToolContextSet, cfg) {
+		s.AddTool(mcp.NewTool(ToolContextSet,
+			mcp.WithDescription("Set the default channel and thread for this session, so later calls to conversations_history, conversations_replies, conversations_add_message, reactions_add, reactions_remove, reactions_search, conversations_invite, conversations_transcript, conversations_set_topic and conversations_set_purpose can omit channel_id and thread_ts. The thread is only used for calls on the same channel; pass an explicit empty thread_ts to post to the channel itself. Call without arguments to see the current context. Returns CSV with channelID, channelName and threadTs."),
+			mcp.WithTitleAnnotation("Set Session Context"),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithString("channel_id",
+				mcp.Description("ID of the channel in format Cxxxxxxxxxx or its name starting with #... or @... aka #general or @username_dm. Changing the channel clears the thread; an empty value clears the context."),
+			),
+			mcp.WithString("thread_ts",
+				mcp.Description("Timestamp of the thread's parent message in format 1234567890.123456, in the context channel. Empty to clear the thread only."),
+			),
+			mcp.WithBoolean("clear",
+				mcp.Description("If true, clears the context before applying channel_id and thread_ts. Default is boolean false."),
+			),
+		), conversationsHandler.ContextSetHandler)
 	}
+
+	relaxSessionDefaults(s)
+	declareCSVFields(s)
+}
+
+// requestContext carries the caller's credentials from the HTTP request of
+// every network transport into its tool calls.
+func (s *MCPServer) requestContext(ctx context.Context, r *http.Request) context.Context {
+	return auth.AuthFromRequest(s.logger)(ctx, r)
 }
 
 func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
@@ -502,14 +2034,33 @@ func (s *MCPServer) ServeSSE(addr string) *server.SSEServer {
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
 	)
-	return server.NewSSEServer(s.server,
+	opts := []server.SSEOption{
 		server.WithBaseURL(fmt.Sprintf("http://%s", addr)),
-		server.WithSSEContextFunc(func(ctx context.Context, r *http.Request) context.Context {
-			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
+		server.WithSSEContextFunc(s.requestContext),
+	}
+	if s.provider.Store() != nil {
+		s.logger.Warn("SSE sessions live on the replica holding the stream; behind a load balancer use the http transport or sticky sessions",
+			zap.String("context", "console"),
+		)
+	}
 
-			return ctx
-		}),
-	)
+	policy := newOriginPolicy(s.provider.Config(), s.logger)
+	admin := s.adminReloadHandler()
+	if policy == nil && admin == nil {
+		return server.NewSSEServer(s.server, opts...)
+	}
+
+	httpSrv := &http.Server{}
+	sseServer := server.NewSSEServer(s.server, append(opts, server.WithHTTPServer(httpSrv))...)
+	var handler http.Handler = sseServer
+	if policy != nil {
+		handler = policy.middleware(handler)
+	}
+	if admin != nil {
+		handler = withAdminRoutes(handler, admin)
+	}
+	httpSrv.Handler = handler
+	return sseServer
 }
 
 func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
@@ -520,14 +2071,72 @@ func (s *MCPServer) ServeHTTP(addr string) *server.StreamableHTTPServer {
 		zap.String("commit_hash", version.CommitHash),
 		zap.String("address", addr),
 	)
-	return server.NewStreamableHTTPServer(s.server,
+	opts := []server.StreamableHTTPOption{
 		server.WithEndpointPath("/mcp"),
-		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
-			ctx = auth.AuthFromRequest(s.logger)(ctx, r)
+		server.WithHTTPContextFunc(s.requestContext),
+	}
+	opts = append(opts, s.httpSessionOptions()...)
+
+	policy := newOriginPolicy(s.provider.Config(), s.logger)
+	admin := s.adminReloadHandler()
+	if policy == nil && admin == nil {
+		return server.NewStreamableHTTPServer(s.server, opts...)
+	}
+
+	// Wrap the endpoint so preflight requests and origin checks are handled
+	// before the MCP transport sees the request.
+	httpSrv := &http.Server{}
+	httpServer := server.NewStreamableHTTPServer(s.server, append(opts, server.WithStreamableHTTPServer(httpSrv))...)
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", httpServer)
+	var handler http.Handler = mux
+	if policy != nil {
+		handler = policy.middleware(handler)
+	}
+	if admin != nil {
+		handler = withAdminRoutes(handler, admin)
+	}
+	httpSrv.Handler = handler
+	return httpServer
+}
 
-			return ctx
-		}),
+// ServeWebSocket returns the WebSocket transport, accepting connections at
+// /ws behind the same origin policy and admin routes as the HTTP transport.
+func (s *MCPServer) ServeWebSocket(addr string) *WebSocketServer {
+	s.logger.Info("Creating WebSocket server",
+		zap.String("context", "console"),
+		zap.String("version", version.Version),
+		zap.String("build_time", version.BuildTime),
+		zap.String("commit_hash", version.CommitHash),
+		zap.String("address", addr),
 	)
+	policy := newOriginPolicy(s.provider.Config(), s.logger)
+	ws := &WebSocketServer{
+		server:      s.server,
+		logger:      s.logger,
+		contextFunc: s.requestContext,
+		sessions:    make(map[*wsSession]struct{}),
+	}
+	if policy != nil {
+		// Browsers do not apply CORS to WebSockets, so a disallowed origin is
+		// refused here even when the policy does not enforce origins.
+		ws.upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || policy.allows(origin)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(wsEndpointPath, ws)
+	var handler http.Handler = mux
+	if policy != nil {
+		handler = policy.middleware(handler)
+	}
+	if admin := s.adminReloadHandler(); admin != nil {
+		handler = withAdminRoutes(handler, admin)
+	}
+	ws.httpServer = &http.Server{Handler: handler}
+	return ws
 }
 
 func (s *MCPServer) ServeStdio() error {
@@ -555,6 +2164,10 @@ func buildErrorRecoveryMiddleware(logger *zap.Logger) server.ToolHandlerMiddlewa
 					zap.String("tool", req.Params.Name),
 					zap.Error(err),
 				)
+				var policyErr *provider.ChannelPolicyError
+				if errors.As(err, &policyErr) {
+					return channelPolicyErrorResult(policyErr), nil
+				}
 				errMsg := err.Error()
 				if isSlackAuthError(errMsg) {
 					errMsg = fmt.Sprintf(
@@ -570,6 +2183,20 @@ func buildErrorRecoveryMiddleware(logger *zap.Logger) server.ToolHandlerMiddlewa
 	}
 }
 
+// channelPolicyErrorResult reports a channel policy violation as an error
+// result whose structured content lets clients tell it apart from Slack errors.
+func channelPolicyErrorResult(e *provider.ChannelPolicyError) *mcp.CallToolResult {
+	res := mcp.NewToolResultStructured(map[string]string{
+		"error":        "channel_policy_violation",
+		"channel_id":   e.ChannelID,
+		"channel_name": e.ChannelName,
+		"rule":         e.Rule,
+		"message":      e.Error(),
+	}, e.Error())
+	res.IsError = true
+	return res
+}
+
 func isSlackAuthError(msg string) bool {
 	authErrors := []string{"invalid_auth", "not_authed", "token_expired", "token_revoked"}
 	for _, e := range authErrors {
@@ -580,6 +2207,20 @@ func isSlackAuthError(msg string) bool {
 	return false
 }
 
+// buildResultOffloadMiddleware moves oversized tool payloads into the results
+// store and returns a resource link in their place.
+func buildResultOffloadMiddleware(resultsHandler *handler.ResultsHandler) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			res, err := next(ctx, req)
+			if err != nil {
+				return res, err
+			}
+			return resultsHandler.Offload(req.Params.Name, res), nil
+		}
+	}
+}
+
 func buildLoggerMiddleware(logger *zap.Logger) server.ToolHandlerMiddleware {
 	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
 		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -8,6 +8,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -26,18 +28,18 @@ func TestShouldAddTool_ReadOnly_EmptyEnabledTools(t *testing.T) {
 			ToolChannelsList,
 		}
 		for _, tool := range readOnlyTools {
-			result := shouldAddTool(tool, []string{}, "")
+			result := shouldAddTool(tool, envConfig(t, []string{}))
 			assert.True(t, result, "tool %s should be registered when enabledTools is empty", tool)
 		}
 	})
 
 	t.Run("all read-only tools registered with nil enabledTools", func(t *testing.T) {
-		result := shouldAddTool(ToolConversationsHistory, nil, "")
+		result := shouldAddTool(ToolConversationsHistory, envConfig(t, nil))
 		assert.True(t, result, "tool should be registered when enabledTools is nil")
 	})
 
 	t.Run("unknown tools also registered with empty enabledTools", func(t *testing.T) {
-		result := shouldAddTool("future_new_tool", []string{}, "")
+		result := shouldAddTool("future_new_tool", envConfig(t, []string{}))
 		assert.True(t, result, "unknown tools should be registered when enabledTools is empty")
 	})
 }
@@ -71,7 +73,7 @@ func TestShouldAddTool_ReadOnly_ExplicitEnabledTools(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := shouldAddTool(tt.toolName, tt.enabledTools, "")
+			result := shouldAddTool(tt.toolName, envConfig(t, tt.enabledTools))
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -81,7 +83,7 @@ func TestShouldAddTool_SingleToolEnabled(t *testing.T) {
 	enabledTools := []string{ToolChannelsList}
 
 	for _, tool := range ValidToolNames {
-		result := shouldAddTool(tool, enabledTools, "")
+		result := shouldAddTool(tool, envConfig(t, enabledTools))
 		if tool == ToolChannelsList {
 			assert.True(t, result, "channels_list should be registered")
 		} else {
@@ -96,6 +98,8 @@ func TestValidToolNames(t *testing.T) {
 			ToolConversationsHistory:        true,
 			ToolConversationsReplies:        true,
 			ToolConversationsAddMessage:     true,
+			ToolConversationsAddMessages:    true,
+			ToolConversationsOpenDM:         true,
 			ToolReactionsAdd:                true,
 			ToolReactionsRemove:             true,
 			ToolAttachmentGetData:           true,
@@ -106,6 +110,44 @@ func TestValidToolNames(t *testing.T) {
 			ToolUsergroupsCreate:            true,
 			ToolUsergroupsUpdate:            true,
 			ToolUsergroupsUsersUpdate:       true,
+			ToolUsergroupsDisable:           true,
+			ToolSavedList:                   true,
+			ToolSavedComplete:               true,
+			ToolUsersStatusGet:              true,
+			ToolUsersStatusSet:              true,
+			ToolActivityMentions:            true,
+			ToolActivityThreads:             true,
+			ToolFilesList:                   true,
+			ToolActivityFeed:                true,
+			ToolTeamInfo:                    true,
+			ToolConversationsInvite:         true,
+			ToolReactionsSearch:             true,
+			ToolContextSet:                  true,
+			ToolConversationsTranscript:     true,
+			ToolAdminAuditSearch:            true,
+			ToolConversationsSetTopic:       true,
+			ToolConversationsSetPurpose:     true,
+			ToolConversationsForwardMessage: true,
+			ToolUsersProfileSet:             true,
+			ToolReactionsAddBulk:            true,
+			ToolAssistantSetStatus:          true,
+			ToolAssistantSetTitle:           true,
+			ToolAssistantSetPrompts:         true,
+			ToolConversationsCleanup:        true,
+			ToolHuddlesStart:                true,
+			ToolThreadsFollow:               true,
+			ToolThreadsUnfollow:             true,
+			ToolAdminUsersInvite:            true,
+			ToolAdminUsersRemove:            true,
+			ToolWorkflowsTrigger:            true,
+			ToolConversationsDMHistory:      true,
+			ToolDirectoryList:               true,
+			ToolPendingWrites:               true,
+			ToolUsergroupsUsersModify:       true,
+			ToolThreadSearch:                true,
+			ToolThreadParticipants:          true,
+			ToolFilesInfo:                   true,
+			ToolConversationsContext:        true,
 		}
 
 		assert.Equal(t, len(expectedTools), len(ValidToolNames), "ValidToolNames should have %d tools", len(expectedTools))
@@ -119,6 +161,8 @@ func TestValidToolNames(t *testing.T) {
 		assert.Equal(t, "conversations_history", ToolConversationsHistory)
 		assert.Equal(t, "conversations_replies", ToolConversationsReplies)
 		assert.Equal(t, "conversations_add_message", ToolConversationsAddMessage)
+		assert.Equal(t, "conversations_add_messages", ToolConversationsAddMessages)
+		assert.Equal(t, "conversations_open_dm", ToolConversationsOpenDM)
 		assert.Equal(t, "reactions_add", ToolReactionsAdd)
 		assert.Equal(t, "reactions_remove", ToolReactionsRemove)
 		assert.Equal(t, "attachment_get_data", ToolAttachmentGetData)
@@ -129,6 +173,43 @@ func TestValidToolNames(t *testing.T) {
 		assert.Equal(t, "usergroups_create", ToolUsergroupsCreate)
 		assert.Equal(t, "usergroups_update", ToolUsergroupsUpdate)
 		assert.Equal(t, "usergroups_users_update", ToolUsergroupsUsersUpdate)
+		assert.Equal(t, "saved_list", ToolSavedList)
+		assert.Equal(t, "saved_complete", ToolSavedComplete)
+		assert.Equal(t, "users_status_get", ToolUsersStatusGet)
+		assert.Equal(t, "users_status_set", ToolUsersStatusSet)
+		assert.Equal(t, "activity_mentions", ToolActivityMentions)
+		assert.Equal(t, "activity_threads", ToolActivityThreads)
+		assert.Equal(t, "files_list", ToolFilesList)
+		assert.Equal(t, "activity_feed", ToolActivityFeed)
+		assert.Equal(t, "team_info", ToolTeamInfo)
+		assert.Equal(t, "conversations_invite", ToolConversationsInvite)
+		assert.Equal(t, "reactions_search", ToolReactionsSearch)
+		assert.Equal(t, "context_set", ToolContextSet)
+		assert.Equal(t, "conversations_transcript", ToolConversationsTranscript)
+		assert.Equal(t, "admin_audit_search", ToolAdminAuditSearch)
+		assert.Equal(t, "conversations_set_topic", ToolConversationsSetTopic)
+		assert.Equal(t, "conversations_set_purpose", ToolConversationsSetPurpose)
+		assert.Equal(t, "conversations_forward_message", ToolConversationsForwardMessage)
+		assert.Equal(t, "users_profile_set", ToolUsersProfileSet)
+		assert.Equal(t, "reactions_add_bulk", ToolReactionsAddBulk)
+		assert.Equal(t, "assistant_threads_set_status", ToolAssistantSetStatus)
+		assert.Equal(t, "assistant_threads_set_title", ToolAssistantSetTitle)
+		assert.Equal(t, "assistant_threads_set_suggested_prompts", ToolAssistantSetPrompts)
+		assert.Equal(t, "conversations_cleanup", ToolConversationsCleanup)
+		assert.Equal(t, "huddles_start", ToolHuddlesStart)
+		assert.Equal(t, "threads_follow", ToolThreadsFollow)
+		assert.Equal(t, "threads_unfollow", ToolThreadsUnfollow)
+		assert.Equal(t, "admin_users_invite", ToolAdminUsersInvite)
+		assert.Equal(t, "admin_users_remove", ToolAdminUsersRemove)
+		assert.Equal(t, "workflows_trigger", ToolWorkflowsTrigger)
+		assert.Equal(t, "conversations_dm_history", ToolConversationsDMHistory)
+		assert.Equal(t, "directory_list", ToolDirectoryList)
+		assert.Equal(t, "pending_writes", ToolPendingWrites)
+		assert.Equal(t, "usergroups_users_modify", ToolUsergroupsUsersModify)
+		assert.Equal(t, "thread_search", ToolThreadSearch)
+		assert.Equal(t, "thread_participants", ToolThreadParticipants)
+		assert.Equal(t, "files_info", ToolFilesInfo)
+		assert.Equal(t, "conversations_context", ToolConversationsContext)
 	})
 }
 
@@ -180,6 +261,16 @@ func TestValidateEnabledTools(t *testing.T) {
 	})
 }
 
+// envConfig loads the config from the environment, as the server binary does
+// without flags, with the given --enabled-tools list.
+func envConfig(t *testing.T, enabledTools []string) *config.Config {
+	t.Helper()
+	cfg, err := config.FromEnv()
+	require.NoError(t, err)
+	cfg.EnabledTools = enabledTools
+	return cfg
+}
+
 // Helper to set/unset env vars for tests
 func setEnv(key, value string) func() {
 	old := os.Getenv(key)
@@ -198,7 +289,7 @@ func TestShouldAddTool_WriteTool_AddMessage(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolConversationsAddMessage, []string{}, "SLACK_MCP_ADD_MESSAGE_TOOL")
+		result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, []string{}))
 		assert.False(t, result, "write tool should NOT be registered when both enabledTools is empty and env var is not set")
 	})
 
@@ -206,7 +297,7 @@ func TestShouldAddTool_WriteTool_AddMessage(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", "true")
 		defer cleanup()
 
-		result := shouldAddTool(ToolConversationsAddMessage, []string{}, "SLACK_MCP_ADD_MESSAGE_TOOL")
+		result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, []string{}))
 		assert.True(t, result, "write tool should be registered when enabledTools is empty but env var is set")
 	})
 
@@ -214,7 +305,7 @@ func TestShouldAddTool_WriteTool_AddMessage(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", "C123,C456")
 		defer cleanup()
 
-		result := shouldAddTool(ToolConversationsAddMessage, []string{}, "SLACK_MCP_ADD_MESSAGE_TOOL")
+		result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, []string{}))
 		assert.True(t, result, "write tool should be registered when enabledTools is empty but env var has channel list")
 	})
 
@@ -222,7 +313,7 @@ func TestShouldAddTool_WriteTool_AddMessage(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolConversationsAddMessage, []string{ToolConversationsAddMessage}, "SLACK_MCP_ADD_MESSAGE_TOOL")
+		result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, []string{ToolConversationsAddMessage}))
 		assert.True(t, result, "write tool should be registered when explicitly in enabledTools even without env var")
 	})
 
@@ -230,7 +321,7 @@ func TestShouldAddTool_WriteTool_AddMessage(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", "true")
 		defer cleanup()
 
-		result := shouldAddTool(ToolConversationsAddMessage, []string{ToolConversationsHistory}, "SLACK_MCP_ADD_MESSAGE_TOOL")
+		result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, []string{ToolConversationsHistory}))
 		assert.False(t, result, "write tool should NOT be registered when not in explicit enabledTools list")
 	})
 }
@@ -240,10 +331,10 @@ func TestShouldAddTool_WriteTool_Reactions(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_REACTION_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolReactionsAdd, []string{}, "SLACK_MCP_REACTION_TOOL")
+		result := shouldAddTool(ToolReactionsAdd, envConfig(t, []string{}))
 		assert.False(t, result, "reactions_add should NOT be registered when env var is not set")
 
-		result = shouldAddTool(ToolReactionsRemove, []string{}, "SLACK_MCP_REACTION_TOOL")
+		result = shouldAddTool(ToolReactionsRemove, envConfig(t, []string{}))
 		assert.False(t, result, "reactions_remove should NOT be registered when env var is not set")
 	})
 
@@ -251,10 +342,10 @@ func TestShouldAddTool_WriteTool_Reactions(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_REACTION_TOOL", "true")
 		defer cleanup()
 
-		result := shouldAddTool(ToolReactionsAdd, []string{}, "SLACK_MCP_REACTION_TOOL")
+		result := shouldAddTool(ToolReactionsAdd, envConfig(t, []string{}))
 		assert.True(t, result, "reactions_add should be registered when env var is set")
 
-		result = shouldAddTool(ToolReactionsRemove, []string{}, "SLACK_MCP_REACTION_TOOL")
+		result = shouldAddTool(ToolReactionsRemove, envConfig(t, []string{}))
 		assert.True(t, result, "reactions_remove should be registered when env var is set")
 	})
 
@@ -262,7 +353,7 @@ func TestShouldAddTool_WriteTool_Reactions(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_REACTION_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolReactionsAdd, []string{ToolReactionsAdd}, "SLACK_MCP_REACTION_TOOL")
+		result := shouldAddTool(ToolReactionsAdd, envConfig(t, []string{ToolReactionsAdd}))
 		assert.True(t, result, "reactions_add should be registered when explicitly in enabledTools")
 	})
 }
@@ -272,7 +363,7 @@ func TestShouldAddTool_WriteTool_Attachment(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ATTACHMENT_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolAttachmentGetData, []string{}, "SLACK_MCP_ATTACHMENT_TOOL")
+		result := shouldAddTool(ToolAttachmentGetData, envConfig(t, []string{}))
 		assert.False(t, result, "attachment_get_data should NOT be registered when env var is not set")
 	})
 
@@ -280,7 +371,7 @@ func TestShouldAddTool_WriteTool_Attachment(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ATTACHMENT_TOOL", "true")
 		defer cleanup()
 
-		result := shouldAddTool(ToolAttachmentGetData, []string{}, "SLACK_MCP_ATTACHMENT_TOOL")
+		result := shouldAddTool(ToolAttachmentGetData, envConfig(t, []string{}))
 		assert.True(t, result, "attachment_get_data should be registered when env var is set")
 	})
 
@@ -288,7 +379,7 @@ func TestShouldAddTool_WriteTool_Attachment(t *testing.T) {
 		cleanup := setEnv("SLACK_MCP_ATTACHMENT_TOOL", "")
 		defer cleanup()
 
-		result := shouldAddTool(ToolAttachmentGetData, []string{ToolAttachmentGetData}, "SLACK_MCP_ATTACHMENT_TOOL")
+		result := shouldAddTool(ToolAttachmentGetData, envConfig(t, []string{ToolAttachmentGetData}))
 		assert.True(t, result, "attachment_get_data should be registered when explicitly in enabledTools")
 	})
 }
@@ -392,6 +483,30 @@ func TestIntegrationErrorRecoveryMiddleware(t *testing.T) {
 	})
 }
 
+func TestUnitErrorRecoveryChannelPolicy(t *testing.T) {
+	mw := buildErrorRecoveryMiddleware(zap.NewNop())
+	handler := mw(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("history: %w", &provider.ChannelPolicyError{
+			ChannelID:   "C0123456789",
+			ChannelName: "#general",
+			Rule:        "not in SLACK_MCP_CHANNEL_ALLOWLIST",
+		})
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+	structured, ok := result.StructuredContent.(map[string]string)
+	require.True(t, ok, "structured content should describe the violation")
+	assert.Equal(t, "channel_policy_violation", structured["error"])
+	assert.Equal(t, "C0123456789", structured["channel_id"])
+	assert.Equal(t, "#general", structured["channel_name"])
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "#general (C0123456789)")
+}
+
 func TestShouldAddTool_Matrix(t *testing.T) {
 	// Test the complete matrix from the plan:
 	// | ENABLED_TOOLS | TOOL_ENV_VAR | Result |
@@ -457,8 +572,65 @@ func TestShouldAddTool_Matrix(t *testing.T) {
 			cleanup := setEnv("SLACK_MCP_ADD_MESSAGE_TOOL", tt.envVarValue)
 			defer cleanup()
 
-			result := shouldAddTool(ToolConversationsAddMessage, tt.enabledTools, "SLACK_MCP_ADD_MESSAGE_TOOL")
+			result := shouldAddTool(ToolConversationsAddMessage, envConfig(t, tt.enabledTools))
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestAdminUsersToolsNeedTheirFlag(t *testing.T) {
+	s := newReloadTestServer(t)
+	cfg := offlineConfig()
+	cfg.EnabledTools = []string{ToolAdminUsersInvite, ToolAdminUsersRemove}
+	require.NoError(t, s.apply(cfg, nil))
+	assert.NotContains(t, s.server.ListTools(), ToolAdminUsersInvite)
+	assert.NotContains(t, s.server.ListTools(), ToolAdminUsersRemove)
+
+	cfg = offlineConfig()
+	cfg.AdminUsersTool = "true"
+	require.NoError(t, s.apply(cfg, nil))
+	assert.Contains(t, s.server.ListTools(), ToolAdminUsersInvite)
+	assert.Contains(t, s.server.ListTools(), ToolAdminUsersRemove)
+}
+
+func TestWorkflowsTriggerNeedsWorkflows(t *testing.T) {
+	s := newReloadTestServer(t)
+	cfg := offlineConfig()
+	cfg.EnabledTools = []string{ToolWorkflowsTrigger}
+	require.NoError(t, s.apply(cfg, nil))
+	assert.NotContains(t, s.server.ListTools(), ToolWorkflowsTrigger)
+
+	cfg = offlineConfig()
+	cfg.Workflows = []config.WorkflowConfig{{
+		Name:        "access_request",
+		TriggerURL:  "https://hooks.slack.com/triggers/T0123/456/abc",
+		Description: "Request access to an internal system.",
+		Inputs:      []string{"system", "reason"},
+	}}
+	require.NoError(t, s.apply(cfg, nil))
+	tool, ok := s.server.ListTools()[ToolWorkflowsTrigger]
+	require.True(t, ok)
+	assert.Contains(t, tool.Tool.Description, "access_request (Request access to an internal system; inputs: system, reason).")
+}
+
+func TestSearchTemplateParam(t *testing.T) {
+	s := newReloadTestServer(t)
+	cfg := offlineConfig()
+	require.NoError(t, s.apply(cfg, nil))
+	tool, ok := s.server.ListTools()[ToolConversationsSearchMessages]
+	require.True(t, ok)
+	assert.NotContains(t, tool.Tool.InputSchema.Properties, "template")
+
+	cfg = offlineConfig()
+	cfg.SearchTemplates = []config.SearchTemplateConfig{
+		{Name: "my_mentions_today", Description: "Messages mentioning me today.", Args: map[string]any{"search_modifiers": "to:me", "filter_date_on": "Today"}},
+		{Name: "support_escalations", Args: map[string]any{"filter_in_channel": "#support", "has_reactions": true}},
+	}
+	require.NoError(t, s.apply(cfg, nil))
+	tool, ok = s.server.ListTools()[ToolConversationsSearchMessages]
+	require.True(t, ok)
+	prop, ok := tool.Tool.InputSchema.Properties["template"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []string{"my_mentions_today", "support_escalations"}, prop["enum"])
+	assert.Contains(t, prop["description"], "my_mentions_today (Messages mentioning me today); support_escalations.")
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const sessionDefaultUsage = " Defaults to the session value set with context_set."
+
+// sessionDefaultParams lists, per tool, the parameters that fall back to the
+// session context when omitted.
+var sessionDefaultParams = map[string][]string{
+	ToolConversationsHistory:        {"channel_id"},
+	ToolConversationsReplies:        {"channel_id", "thread_ts"},
+	ToolThreadSearch:                {"channel_id", "thread_ts"},
+	ToolThreadParticipants:          {"channel_id", "thread_ts"},
+	ToolConversationsAddMessage:     {"channel_id", "thread_ts"},
+	ToolConversationsForwardMessage: {"channel_id"},
+	ToolConversationsInvite:         {"channel_id"},
+	ToolReactionsAdd:                {"channel_id"},
+	ToolReactionsRemove:             {"channel_id"},
+	ToolReactionsSearch:             {"channel_id"},
+	ToolConversationsTranscript:     {"channel_id"},
+	ToolConversationsSetTopic:       {"channel_id"},
+	ToolConversationsSetPurpose:     {"channel_id"},
+	ToolConversationsCleanup:        {"channel_id"},
+	ToolHuddlesStart:                {"channel_id"},
+	ToolThreadsFollow:               {"channel_id", "thread_ts"},
+	ToolThreadsUnfollow:             {"channel_id", "thread_ts"},
+}
+
+// buildSessionContextMiddleware fills omitted channel_id and thread_ts
+// arguments from the session context. A thread is only filled in when the
+// call targets the context channel, and an argument passed explicitly, even
+// empty, is never replaced.
+func buildSessionContextMiddleware(sc *handler.SessionContexts) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx = handler.WithSessionContexts(ctx, sc)
+
+			params, ok := sessionDefaultParams[req.Params.Name]
+			if !ok {
+				return next(ctx, req)
+			}
+			current, ok := sc.Get(handler.SessionID(ctx))
+			if !ok {
+				return next(ctx, req)
+			}
+
+			args := maps.Clone(req.GetArguments())
+			if args == nil {
+				args = make(map[string]any)
+			}
+			if _, set := args["channel_id"]; !set && current.ChannelID != "" {
+				args["channel_id"] = current.ChannelID
+			}
+			if _, set := args["thread_ts"]; !set && current.ThreadTs != "" && slices.Contains(params, "thread_ts") {
+				if channel, _ := args["channel_id"].(string); channel == current.ChannelID || (channel != "" && channel == current.ChannelName) {
+					args["thread_ts"] = current.ThreadTs
+				}
+			}
+			req.Params.Arguments = args
+			return next(ctx, req)
+		}
+	}
+}
+
+// relaxSessionDefaults makes the parameters in sessionDefaultParams optional
+// in the registered tool schemas, since the session context can supply them.
+func relaxSessionDefaults(s *server.MCPServer) {
+	for name, params := range sessionDefaultParams {
+		tool := s.GetTool(name)
+		if tool == nil {
+			continue
+		}
+		schema := &tool.Tool.InputSchema
+		schema.Required = slices.DeleteFunc(slices.Clone(schema.Required), func(p string) bool {
+			return slices.Contains(params, p)
+		})
+		schema.Properties = maps.Clone(schema.Properties)
+		for _, p := range params {
+			prop, ok := schema.Properties[p].(map[string]any)
+			if !ok {
+				continue
+			}
+			prop = maps.Clone(prop)
+			desc, _ := prop["description"].(string)
+			prop["description"] = desc + sessionDefaultUsage
+			schema.Properties[p] = prop
+		}
+		s.AddTools(*tool)
+	}
+}
+
+// forgetSessionContexts drops a session's context when the session ends.
+func forgetSessionContexts(hooks *server.Hooks, sc *handler.SessionContexts) {
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		sc.Forget(session.SessionID())
+	})
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/korotovsky/slack-mcp-server/pkg/store"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testSession struct{ id string }
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return s.id }
+
+func TestSessionContextMiddleware(t *testing.T) {
+	sc := handler.NewSessionContexts(nil, zap.NewNop())
+	sc.Set("s1", handler.SessionContext{ChannelID: "C1", ChannelName: "#incidents", ThreadTs: "1700000000.000100"})
+
+	var got map[string]any
+	next := buildSessionContextMiddleware(sc)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		got = req.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	})
+	srv := server.NewMCPServer("test", "0")
+	call := func(session, tool string, args map[string]any) map[string]any {
+		ctx := context.Background()
+		if session != "" {
+			ctx = srv.WithContext(ctx, testSession{id: session})
+		}
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		_, err := next(ctx, req)
+		require.NoError(t, err)
+		return got
+	}
+
+	assert.Equal(t, map[string]any{"channel_id": "C1", "thread_ts": "1700000000.000100", "text": "hi"},
+		call("s1", ToolConversationsAddMessage, map[string]any{"text": "hi"}))
+	assert.Equal(t, map[string]any{"channel_id": "#incidents", "thread_ts": "1700000000.000100"},
+		call("s1", ToolConversationsReplies, map[string]any{"channel_id": "#incidents"}), "the context channel by name keeps the thread")
+	assert.Equal(t, map[string]any{"channel_id": "C2", "text": "hi"},
+		call("s1", ToolConversationsAddMessage, map[string]any{"channel_id": "C2", "text": "hi"}), "another channel does not get the thread")
+	assert.Equal(t, map[string]any{"channel_id": "C1", "thread_ts": "", "text": "hi"},
+		call("s1", ToolConversationsAddMessage, map[string]any{"thread_ts": "", "text": "hi"}), "an explicit empty thread_ts is kept")
+	assert.Equal(t, map[string]any{"channel_id": "C1"},
+		call("s1", ToolConversationsHistory, nil), "history takes the channel only")
+	assert.Equal(t, map[string]any{"query": "x"},
+		call("s1", ToolConversationsSearchMessages, map[string]any{"query": "x"}), "other tools are untouched")
+	assert.Equal(t, map[string]any{"text": "hi"},
+		call("s2", ToolConversationsAddMessage, map[string]any{"text": "hi"}), "sessions do not share context")
+	assert.Equal(t, map[string]any{"text": "hi"},
+		call("", ToolConversationsAddMessage, map[string]any{"text": "hi"}))
+
+	sc.Forget("s1")
+	assert.Equal(t, map[string]any{"text": "hi"},
+		call("s1", ToolConversationsAddMessage, map[string]any{"text": "hi"}))
+}
+
+func TestSessionContextsSharedStore(t *testing.T) {
+	shared := store.NewMemory()
+	replicaA := handler.NewSessionContexts(shared, zap.NewNop())
+	replicaB := handler.NewSessionContexts(shared, zap.NewNop())
+
+	c := handler.SessionContext{ChannelID: "C1", ChannelName: "#incidents", ThreadTs: "1700000000.000100"}
+	replicaA.Set("s1", c)
+	got, ok := replicaB.Get("s1")
+	require.True(t, ok, "a context set on one replica is seen by the others")
+	assert.Equal(t, c, got)
+
+	replicaB.Set("s1", handler.SessionContext{})
+	_, ok = replicaA.Get("s1")
+	assert.False(t, ok, "an empty context forgets the session everywhere")
+
+	replicaA.Set("s2", c)
+	replicaB.Forget("s2")
+	_, ok = replicaA.Get("s2")
+	assert.False(t, ok)
+}
+
+func TestRelaxSessionDefaults(t *testing.T) {
+	s := server.NewMCPServer("test", "0")
+	s.AddTool(mcp.NewTool(ToolConversationsReplies,
+		mcp.WithString("channel_id", mcp.Required(), mcp.Description("Channel.")),
+		mcp.WithString("thread_ts", mcp.Required(), mcp.Description("Thread.")),
+		mcp.WithString("cursor", mcp.Required()),
+	), nil)
+
+	relaxSessionDefaults(s)
+
+	schema := s.GetTool(ToolConversationsReplies).Tool.InputSchema
+	assert.Equal(t, []string{"cursor"}, schema.Required)
+	assert.Equal(t, "Channel."+sessionDefaultUsage, schema.Properties["channel_id"].(map[string]any)["description"])
+	assert.Nil(t, s.GetTool(ToolConversationsHistory), "tools that are not registered are not added")
+}
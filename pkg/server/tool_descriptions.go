@@ -0,0 +1,50 @@
+package server
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// applyDescriptionOverrides replaces the tool and parameter descriptions of
+// the registered tools with those set in the tools config, so the prompts a
+// client's model sees can carry organization-specific guidance. It runs after
+// registerTools; tools that are not registered are skipped.
+func applyDescriptionOverrides(s *server.MCPServer, tc *ToolsConfig, logger *zap.Logger) {
+	if tc == nil {
+		return
+	}
+	for name, override := range tc.Tools {
+		if override.Description == "" && len(override.Params) == 0 {
+			continue
+		}
+		tool := s.GetTool(name)
+		if tool == nil {
+			continue
+		}
+
+		if d := strings.TrimSpace(override.Description); d != "" {
+			tool.Tool.Description = d
+		}
+		if len(override.Params) > 0 {
+			schema := &tool.Tool.InputSchema
+			schema.Properties = maps.Clone(schema.Properties)
+			for param, description := range override.Params {
+				prop, ok := schema.Properties[param].(map[string]any)
+				if !ok {
+					logger.Warn("Tools config describes an unknown parameter",
+						zap.String("tool", name),
+						zap.String("param", param),
+					)
+					continue
+				}
+				prop = maps.Clone(prop)
+				prop["description"] = strings.TrimSpace(description)
+				schema.Properties[param] = prop
+			}
+		}
+		s.AddTools(*tool)
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// ToolInfo describes a tool the configuration registers, for the tools list
+// command.
+type ToolInfo struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description"`
+	Groups      []string `json:"groups,omitempty"`
+	ReadOnly    bool     `json:"read_only"`
+	Cost        ToolCost `json:"cost,omitempty"`
+	SlackCalls  string   `json:"slack_calls,omitempty"`
+	// Scopes are the OAuth scopes of which the token needs one.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// ConfiguredTools returns the tools cfg and toolsConfig register, sorted by
+// name, with their descriptions as overridden by the tools config. It needs
+// no token: tools that are only skipped for missing scopes are included.
+func ConfiguredTools(cfg *config.Config, toolsConfig *ToolsConfig) []ToolInfo {
+	s := server.NewMCPServer("tools", "", server.WithToolCapabilities(true))
+	registerTools(s, &provider.ApiProvider{}, zap.NewNop(), cfg)
+	applyDescriptionOverrides(s, toolsConfig, zap.NewNop())
+
+	groups := make(map[string][]string)
+	for group, names := range ToolGroups {
+		for _, name := range names {
+			groups[name] = append(groups[name], group)
+		}
+	}
+
+	tools := make([]ToolInfo, 0, len(s.ListTools()))
+	for name, tool := range s.ListTools() {
+		info := ToolInfo{
+			Name:        name,
+			Title:       tool.Tool.Annotations.Title,
+			Description: tool.Tool.Description,
+			Groups:      groups[name],
+			Scopes:      toolScopes[name],
+		}
+		if ro := tool.Tool.Annotations.ReadOnlyHint; ro != nil {
+			info.ReadOnly = *ro
+		}
+		if md, ok := toolMetadata[name]; ok {
+			info.Cost, info.SlackCalls = md.Cost, md.SlackCalls
+		}
+		sort.Strings(info.Groups)
+		tools = append(tools, info)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
@@ -0,0 +1,647 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+// ToolCost hints how much Slack API work and time one call of a tool takes,
+// so a model can prefer cheap tools and narrow expensive ones.
+type ToolCost string
+
+const (
+	// CostLow is one Slack API call, or an answer from the caches.
+	CostLow ToolCost = "low"
+	// CostMedium is a few Slack API calls, such as one page of results plus
+	// resolving the users in it.
+	CostMedium ToolCost = "medium"
+	// CostHigh paginates or fans out over many Slack API calls; it is slow on
+	// large workspaces and uses up rate limits.
+	CostHigh ToolCost = "high"
+)
+
+// ToolExample is a sample call of a tool with an abridged result.
+type ToolExample struct {
+	Description string         `json:"description"`
+	Input       map[string]any `json:"input"`
+	Output      string         `json:"output,omitempty"`
+}
+
+// ToolMetadata is the guidance kept for each tool next to its description:
+// a cost hint and sample calls. It is attached to the tool's _meta and served
+// as the slack://<workspace>/tools resource.
+type ToolMetadata struct {
+	Cost ToolCost `json:"cost"`
+	// SlackCalls is the typical number of Slack API calls per tool call.
+	SlackCalls string `json:"slack_calls"`
+	// Hint is advice on keeping the call cheap or well-formed.
+	Hint     string        `json:"hint,omitempty"`
+	Examples []ToolExample `json:"examples"`
+}
+
+// toolMetadata is the registry of cost hints and examples for every tool in
+// ValidToolNames. Example inputs must only use parameters the tool declares.
+var toolMetadata = map[string]ToolMetadata{
+	ToolConversationsHistory: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Hint:       "Prefer a duration limit such as 1d over large counts; pass cursor to page back further.",
+		Examples: []ToolExample{
+			{
+				Description: "Messages of the last day in #general",
+				Input:       map[string]any{"channel_id": "#general", "limit": "1d"},
+				Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000000.123456,U0123ABCD,ana,Ana Lee,C0123ABCD,,Deploy finished,2024-06-10T06:13:20Z,...",
+			},
+			{
+				Description: "Ten messages around a linked message",
+				Input:       map[string]any{"channel_id": "C0123ABCD", "around_ts": "1718000000.123456", "around_count": 10},
+			},
+			{
+				Description: "Only the Japanese messages of the last week in #support-global",
+				Input:       map[string]any{"channel_id": "#support-global", "limit": "1w", "filter_language": "ja"},
+			},
+		},
+	},
+	ToolConversationsDMHistory: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2, plus 1 when the DM is not cached",
+		Hint:       "Use it instead of channels_list plus conversations_history when the other person is known.",
+		Examples: []ToolExample{{
+			Description: "Last week of the DM with Alice",
+			Input:       map[string]any{"user": "@alice", "limit": "1w"},
+			Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000000.123456,U0123ABCD,alice,Alice Park,D0123ABCD,,Can you review my PR?,2024-06-10T06:13:20Z,...",
+		}},
+	},
+	ToolConversationsReplies: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Hint:       "fetch_all pages through the whole thread and costs one call per 1000 replies.",
+		Examples: []ToolExample{{
+			Description: "Replies of a thread",
+			Input:       map[string]any{"channel_id": "C0123ABCD", "thread_ts": "1718000000.123456"},
+			Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000100.000200,U0456EFGH,bo,Bo Kim,C0123ABCD,1718000000.123456,Looking into it,...",
+		}},
+	},
+	ToolThreadSearch: {
+		Cost:       CostMedium,
+		SlackCalls: "1 per 1000 replies",
+		Hint:       "Use it instead of conversations_replies with fetch_all when only part of a long thread is relevant.",
+		Examples: []ToolExample{{
+			Description: "Find where the rollback was discussed in an incident thread",
+			Input:       map[string]any{"channel_id": "#incidents", "thread_ts": "1718000000.123456", "query": "rollback -staging", "context": 1},
+			Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000300.000400,U0456EFGH,bo,Bo Kim,C0123ABCD,1718000000.123456,Starting rollback of 2024.06.1,...",
+		}},
+	},
+	ToolThreadParticipants: {
+		Cost:       CostMedium,
+		SlackCalls: "1 per 1000 replies",
+		Hint:       "Use it instead of conversations_replies with fetch_all to find whom to mention in a follow-up.",
+		Examples: []ToolExample{{
+			Description: "Who took part in an incident thread",
+			Input:       map[string]any{"channel_id": "#incidents", "thread_ts": "1718000000.123456"},
+			Output:      "UserID,UserName,RealName,DisplayName,Title,TZ,IsBot,IsDeleted,Mention,IsAuthor,Messages,Reactions,FirstTime,LastTime\nU0456EFGH,bo,Bo Kim,bo,SRE,Europe/Berlin,false,false,<@U0456EFGH>,false,7,2,2024-06-10T06:15:00Z,2024-06-10T07:40:12Z",
+		}},
+	},
+	ToolConversationsContext: {
+		Cost:       CostMedium,
+		SlackCalls: "2-3, plus 1 per 1000 replies",
+		Hint:       "Use it first when the user pastes a message link, instead of conversations_history, conversations_replies and search.",
+		Examples: []ToolExample{{
+			Description: "What is going on around a linked thread reply",
+			Input:       map[string]any{"permalink": "https://example.slack.com/archives/C0123ABCD/p1718000300000400?thread_ts=1718000000.123456", "context": 3},
+			Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000000.123456,U0123ABCD,ana,Ana Lee,C0123ABCD,1718000000.123456,Checkout is failing for EU customers,...\n1718000300.000400,U0456EFGH,bo,Bo Kim,C0123ABCD,1718000000.123456,Rolling back 2024.06.1,...",
+		}},
+	},
+	ToolConversationsAddMessage: {
+		Cost:       CostLow,
+		SlackCalls: "1, plus 1 per attached file",
+		Hint:       "Pass an idempotency_key when the call may be retried.",
+		Examples: []ToolExample{
+			{
+				Description: "Reply in a thread",
+				Input:       map[string]any{"channel_id": "#incidents", "thread_ts": "1718000000.123456", "text": "Mitigated, monitoring for 30 minutes."},
+				Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000500.000300,U0123ABCD,ana,Ana Lee,C0123ABCD,1718000000.123456,\"Mitigated, monitoring for 30 minutes.\",...",
+			},
+			{
+				Description: "Share an uploaded report in another channel",
+				Input:       map[string]any{"channel_id": "#leadership", "text": "Q2 report, as discussed", "attach": "F0123ABCD"},
+				Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000600.000400,U0123ABCD,ana,Ana Lee,C0456EFGH,,\"Q2 report, as discussed\nhttps://acme.slack.com/files/U0123ABCD/F0123ABCD/q2-report.pdf\",...",
+			},
+			{
+				Description: "Schedule a reminder for tomorrow morning",
+				Input:       map[string]any{"channel_id": "#team", "text": "Standup in 5 minutes", "post_at": "2024-06-11 09:55", "tz": "Europe/Berlin"},
+			},
+		},
+	},
+	ToolConversationsAddMessages: {
+		Cost:       CostMedium,
+		SlackCalls: "1 per message",
+		Examples: []ToolExample{{
+			Description: "Post the same notice to two channels",
+			Input: map[string]any{"messages": []any{
+				map[string]any{"channel_id": "#eng", "text": "Deploy freeze starts at 18:00"},
+				map[string]any{"channel_id": "#support", "text": "Deploy freeze starts at 18:00"},
+			}},
+			Output: "index,channelID,threadTs,ok,msgID,error\n0,C0123ABCD,,true,1718000600.000100,\n1,C0456EFGH,,true,1718000600.000200,",
+		}},
+	},
+	ToolConversationsForwardMessage: {
+		Cost:       CostLow,
+		SlackCalls: "2",
+		Examples: []ToolExample{{
+			Description: "Forward a message to another channel with a comment",
+			Input:       map[string]any{"channel_id": "#alerts", "message_ts": "1718000000.123456", "target_channel_id": "#incidents", "comment": "Same error as last week"},
+		}},
+	},
+	ToolConversationsOpenDM: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Open a group DM with two people",
+			Input:       map[string]any{"users": "@ana,@bo"},
+			Output:      "channelID,name,type,users,alreadyOpen\nC0789IJKL,@mpdm-me--ana--bo-1,mpim,\"U0123ABCD,U0456EFGH\",false",
+		}},
+	},
+	ToolConversationsInvite: {
+		Cost:       CostMedium,
+		SlackCalls: "1 per email, plus 1",
+		Examples: []ToolExample{{
+			Description: "Invite two people to a channel by email",
+			Input:       map[string]any{"channel_id": "#project-x", "emails": "ana@example.com, bo@example.com"},
+			Output:      "email,userID,userName,status,error\nana@example.com,U0123ABCD,ana,invited,\nbo@example.com,,,not_found,",
+		}},
+	},
+	ToolConversationsSetTopic: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Show the current on-call person",
+			Input:       map[string]any{"channel_id": "#oncall", "topic": "On call: @ana until Friday"},
+			Output:      "channelID,channelName,field,previous,value\nC0123ABCD,#oncall,topic,On call: @bo,On call: @ana until Friday",
+		}},
+	},
+	ToolConversationsSetPurpose: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Describe a new project channel",
+			Input:       map[string]any{"channel_id": "#project-x", "purpose": "Launch coordination for Project X"},
+		}},
+	},
+	ToolConversationsCleanup: {
+		Cost:       CostHigh,
+		SlackCalls: "1 per 200 messages scanned, plus 1 per delete",
+		Hint:       "Run with dry_run first and check the rows before deleting.",
+		Examples: []ToolExample{{
+			Description: "Preview deleting week-old deploy notices",
+			Input:       map[string]any{"channel_id": "#deploys", "older_than": "7d", "pattern": `^\[deploy\]`, "dry_run": true},
+			Output:      "channelID,timestamp,time,text,status,error\nC0123ABCD,1717300000.000100,2024-06-02T03:46:40Z,[deploy] api v1.2 done,would_delete,",
+		}},
+	},
+	ToolHuddlesStart: {
+		Cost:       CostLow,
+		SlackCalls: "1, plus 1 to post the invitation",
+		Hint:       "Leave invite empty to only get the link; nothing is posted then.",
+		Examples: []ToolExample{{
+			Description: "Spin up an incident huddle and pull in on-call",
+			Input:       map[string]any{"channel_id": "#sev1", "invite": "S0123ONCALL", "message": "Checkout is down"},
+			Output:      `{"channel_id":"C0123ABCD","huddle_url":"https://app.slack.com/huddle/T0123ABCD/C0123ABCD","invited":["<!subteam^S0123ONCALL>"],"message_ts":"1718000000.123456"}`,
+		}},
+	},
+	ToolReactionsAdd: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Acknowledge a message",
+			Input:       map[string]any{"channel_id": "#support", "timestamp": "1718000000.123456", "emoji": "eyes"},
+		}},
+	},
+	ToolReactionsAddBulk: {
+		Cost:       CostMedium,
+		SlackCalls: "1 per reaction",
+		Hint:       "Reactions are throttled by the server; do not add delays between calls.",
+		Examples: []ToolExample{{
+			Description: "Mark two messages as handled",
+			Input: map[string]any{"emoji": "white_check_mark", "messages": []any{
+				map[string]any{"channel_id": "#support", "timestamp": "1718000000.123456"},
+				map[string]any{"channel_id": "#support", "timestamp": "1718000100.000200"},
+			}},
+			Output: "index,channelID,timestamp,emoji,status,error\n0,C0123ABCD,1718000000.123456,white_check_mark,added,\n1,C0123ABCD,1718000100.000200,white_check_mark,already_reacted,",
+		}},
+	},
+	ToolReactionsRemove: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Take back an acknowledgement",
+			Input:       map[string]any{"channel_id": "#support", "timestamp": "1718000000.123456", "emoji": "eyes"},
+		}},
+	},
+	ToolReactionsSearch: {
+		Cost:       CostHigh,
+		SlackCalls: "1 per 200 messages scanned",
+		Hint:       "Keep oldest close to now; the scan walks the whole window.",
+		Examples: []ToolExample{{
+			Description: "Requests from the last week nobody has marked done",
+			Input:       map[string]any{"channel_id": "#requests", "reaction": "eyes", "exclude_reaction": "white_check_mark", "oldest": "2024-06-03"},
+		}},
+	},
+	ToolConversationsTranscript: {
+		Cost:       CostHigh,
+		SlackCalls: "1 per 200 messages, plus 1 per thread with include_threads",
+		Examples: []ToolExample{{
+			Description: "Markdown transcript of an incident thread",
+			Input:       map[string]any{"channel_id": "#incidents", "thread_ts": "1718000000.123456", "format": "markdown"},
+		}},
+	},
+	ToolAdminAuditSearch: {
+		Cost:       CostMedium,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Channels archived this month",
+			Input:       map[string]any{"action": "channel_archive", "oldest": "2024-06-01"},
+		}},
+	},
+	ToolAdminUsersInvite: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Hint:       "Ask the user to confirm before calling; confirm must repeat the email.",
+		Examples: []ToolExample{{
+			Description: "Invite a joiner to their team channels",
+			Input:       map[string]any{"email": "ana@example.com", "channel_ids": "#general,#team-payments", "confirm": "ana@example.com", "reason": "IT-1234"},
+		}},
+	},
+	ToolAdminUsersRemove: {
+		Cost:       CostLow,
+		SlackCalls: "2-3",
+		Hint:       "Ask the user to confirm before calling; confirm must repeat user.",
+		Examples: []ToolExample{{
+			Description: "Offboard a leaver",
+			Input:       map[string]any{"user": "bo@example.com", "confirm": "bo@example.com", "reason": "IT-1235"},
+			Output:      `{"action":"remove","team_id":"T0123ABCD","email":"bo@example.com","user_id":"U0123ABCD","sessions_reset":true,"reason":"IT-1235"}`,
+		}},
+	},
+	ToolWorkflowsTrigger: {
+		Cost:       CostLow,
+		SlackCalls: "1 webhook post",
+		Hint:       "Only configured workflows can be started; their names and inputs are listed in the tool description.",
+		Examples: []ToolExample{{
+			Description: "Request access to a system",
+			Input:       map[string]any{"workflow": "access_request", "inputs": map[string]any{"system": "grafana", "reason": "on-call rotation"}},
+			Output:      `{"workflow":"access_request","inputs":["reason","system"],"started":true}`,
+		}},
+	},
+	ToolPendingWrites: {
+		Cost:       CostLow,
+		SlackCalls: "0, read from the write journal",
+		Hint:       "Call after a restart before retrying writes; look up the completed items in the channel history before reposting.",
+		Examples: []ToolExample{
+			{
+				Description: "Batch cut short after its first message",
+				Input:       map[string]any{},
+				Output:      `{"pending":[{"id":"3f2a9c1d7e4b8a06","tool":"conversations_add_messages","started_at":"2025-06-02T09:14:03Z","args":{"messages":[{"channel_id":"C0123ABCD","text":"Deploy started"},{"channel_id":"C0456EFGH","text":"Deploy started"}]},"completed":[{"index":0,"channel":"C0123ABCD","ts":"1748855643.000100"}]}]}`,
+			},
+			{
+				Description: "Drop an entry once redone",
+				Input:       map[string]any{"acknowledge": "3f2a9c1d7e4b8a06"},
+			},
+		},
+	},
+	ToolAttachmentGetData: {
+		Cost:       CostMedium,
+		SlackCalls: "2",
+		Hint:       "Files are downloaded in full; check the size with files_info first.",
+		Examples: []ToolExample{{
+			Description: "Read a shared log file",
+			Input:       map[string]any{"file_id": "F0123ABCD"},
+		}},
+	},
+	ToolFilesList: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "PDFs shared in a channel this month",
+			Input:       map[string]any{"channel_id": "#legal", "types": "pdfs", "since": "2024-06-01"},
+		}},
+	},
+	ToolFilesInfo: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Check a log file before downloading it",
+			Input:       map[string]any{"file_id": "F0123ABCD"},
+			Output:      `{"file_id":"F0123ABCD","name":"deploy.log","type":"text","mimetype":"text/plain","size":48213,"uploader_id":"U0123ABCD","uploader_name":"ana","created":"2024-06-03T09:12:44Z","is_public":true,"shares":[{"channel_id":"C0123ABCD","channel_name":"eng","ts":"1717405964.000200"}],"permalink":"https://example.slack.com/files/U0123ABCD/F0123ABCD/deploy.log","downloadable":true,"preview":"2024-06-03 09:10:02 starting deploy...","preview_truncated":true,"lines":812}`,
+		}},
+	},
+	ToolConversationsSearchMessages: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Hint:       "Narrow with filter_in_channel, filter_users_from and dates instead of paging through broad queries. With a bot token it scans the history of the channels in filter_in_channel, so name few channels and keep the date range short.",
+		Examples: []ToolExample{
+			{
+				Description: "Messages about a deploy from one person in a channel",
+				Input:       map[string]any{"search_query": "deploy", "filter_in_channel": "#eng", "filter_users_from": "@ana", "filter_date_after": "2024-06-01"},
+				Output:      "MsgID,UserID,UserName,RealName,Channel,ThreadTs,Text,Time,...\n1718000000.123456,U0123ABCD,ana,Ana Lee,C0123ABCD,,Deploy finished,...",
+			},
+			{
+				Description: "Find a message by its permalink",
+				Input:       map[string]any{"search_query": "https://example.slack.com/archives/C0123ABCD/p1718000000123456"},
+			},
+			{
+				Description: "Messages with a four-digit error code",
+				Input:       map[string]any{"search_query": "ERR", "post_filter_regex": `ERR-\d{4}\b`, "filter_in_channel": "#alerts"},
+			},
+		},
+	},
+	ToolActivityMentions: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Mentions and DMs since yesterday",
+			Input:       map[string]any{"since": "Yesterday", "include_dms": true},
+		}},
+	},
+	ToolActivityThreads: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Threads with unread replies",
+			Input:       map[string]any{"unread_only": true},
+		}},
+	},
+	ToolThreadsFollow: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Keep track of a design discussion",
+			Input:       map[string]any{"channel_id": "#design", "thread_ts": "1718000000.123456"},
+			Output:      `{"channel_id":"C0123ABCD","thread_ts":"1718000000.123456","following":true}`,
+		}},
+	},
+	ToolThreadsUnfollow: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Mute a noisy thread",
+			Input:       map[string]any{"channel_id": "C0123ABCD", "thread_ts": "1718000000.123456"},
+		}},
+	},
+	ToolActivityFeed: {
+		Cost:       CostHigh,
+		SlackCalls: "3-10",
+		Examples: []ToolExample{{
+			Description: "My messages and the reactions to them this week",
+			Input:       map[string]any{"since": "7 days ago", "include": "messages,reactions_received"},
+		}},
+	},
+	ToolChannelsList: {
+		Cost:       CostLow,
+		SlackCalls: "0, served from the channels cache",
+		Hint:       "Filter with name_prefix or member_of_only rather than paging through every channel.",
+		Examples: []ToolExample{{
+			Description: "Incident channels I am in",
+			Input:       map[string]any{"name_prefix": "inc-", "member_of_only": true},
+			Output:      "ID,Name,Topic,Purpose,MemberCount,Cursor\nC0123ABCD,#inc-1042,Sev1: checkout errors,,14,",
+		}},
+	},
+	ToolDirectoryList: {
+		Cost:       CostLow,
+		SlackCalls: "0, served from the users and channels caches",
+		Hint:       "Narrow with query instead of paging through a large directory; users_search returns more details per user.",
+		Examples: []ToolExample{
+			{
+				Description: "Users named like Alice",
+				Input:       map[string]any{"kind": "users", "query": "alice"},
+				Output:      "UserID,UserName,RealName,TZ,TZOffset,Locale,WorkingHours,Cursor\nU0123ABCD,alice,Alice Park,Europe/Berlin,+02:00,en-US,,",
+			},
+			{
+				Description: "First 200 public channels",
+				Input:       map[string]any{"kind": "channels", "channel_types": "public_channel", "limit": 200},
+			},
+		},
+	},
+	ToolUsergroupsList: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "User groups with their members",
+			Input:       map[string]any{"include_users": true},
+		}},
+	},
+	ToolUsergroupsMe: {
+		Cost:       CostLow,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Join the on-call group",
+			Input:       map[string]any{"action": "join", "usergroup_id": "S0123ABCD"},
+		}},
+	},
+	ToolUsergroupsCreate: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Create a group for the platform team",
+			Input:       map[string]any{"name": "Platform Team", "handle": "platform", "channels": "C0123ABCD"},
+		}},
+	},
+	ToolUsergroupsUpdate: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Rename a group",
+			Input:       map[string]any{"usergroup_id": "S0123ABCD", "name": "Platform Engineering"},
+		}},
+	},
+	ToolUsergroupsUsersUpdate: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Hint:       "users replaces the whole member list; usergroups_users_modify adds or removes members without it.",
+		Examples: []ToolExample{{
+			Description: "Set the members of the on-call group",
+			Input:       map[string]any{"usergroup_id": "S0123ABCD", "users": "U0123ABCD,U0456EFGH"},
+		}},
+	},
+	ToolUsergroupsUsersModify: {
+		Cost:       CostLow,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Hand over on-call from one engineer to another",
+			Input:       map[string]any{"usergroup_id": "S0123ABCD", "add_users": "U0456EFGH", "remove_users": "U0123ABCD"},
+			Output:      `{"id":"S0123ABCD","name":"On-call","handle":"oncall","description":"","user_count":2,"is_external":false,"date_create":"2024-01-08T10:00:00Z","date_update":"2024-06-10T06:13:20Z","users":"U0789IJKL,U0456EFGH","added":["U0456EFGH"],"removed":["U0123ABCD"]}`,
+		}},
+	},
+	ToolUsergroupsDisable: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Disable a group that is no longer used",
+			Input:       map[string]any{"usergroup_id": "S0123ABCD", "confirm": true},
+		}},
+	},
+	ToolSavedList: {
+		Cost:       CostMedium,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Open saved items due by mid-June",
+			Input:       map[string]any{"state": "in_progress", "due_before": "2024-06-14"},
+		}},
+	},
+	ToolSavedComplete: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Mark a saved message as done",
+			Input:       map[string]any{"channel": "C0123ABCD", "ts": "1718000000.123456"},
+		}},
+	},
+	ToolUsersStatusGet: {
+		Cost:       CostLow,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Is a colleague available",
+			Input:       map[string]any{"user": "@ana"},
+		}},
+	},
+	ToolUsersStatusSet: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Focus time for two hours",
+			Input:       map[string]any{"status_text": "Focus time", "status_emoji": ":headphones:", "expiration": "2h"},
+		}},
+	},
+	ToolUsersProfileSet: {
+		Cost:       CostLow,
+		SlackCalls: "1-2",
+		Examples: []ToolExample{{
+			Description: "Fill in a new hire's profile",
+			Input:       map[string]any{"title": "Support Engineer", "pronouns": "they/them", "custom_fields": map[string]any{"Team": "Support"}},
+		}},
+	},
+	ToolTeamInfo: {
+		Cost:       CostLow,
+		SlackCalls: "1-3",
+		Examples: []ToolExample{{
+			Description: "Workspace name, plan and custom profile fields",
+			Input:       map[string]any{},
+		}},
+	},
+	ToolContextSet: {
+		Cost:       CostLow,
+		SlackCalls: "0",
+		Hint:       "Set once so later calls can leave out channel_id and thread_ts.",
+		Examples: []ToolExample{{
+			Description: "Work in one thread for the rest of the session",
+			Input:       map[string]any{"channel_id": "#incidents", "thread_ts": "1718000000.123456"},
+		}},
+	},
+	ToolAssistantSetStatus: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Show progress while answering",
+			Input:       map[string]any{"channel_id": "D0123ABCD", "thread_ts": "1718000000.123456", "status": "is reading #incidents..."},
+		}},
+	},
+	ToolAssistantSetTitle: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Title a thread after the first question",
+			Input:       map[string]any{"channel_id": "D0123ABCD", "thread_ts": "1718000000.123456", "title": "Checkout errors on June 10"},
+		}},
+	},
+	ToolAssistantSetPrompts: {
+		Cost:       CostLow,
+		SlackCalls: "1",
+		Examples: []ToolExample{{
+			Description: "Offer two starting points",
+			Input: map[string]any{"channel_id": "D0123ABCD", "thread_ts": "1718000000.123456", "title": "Try asking:", "prompts": []any{
+				map[string]any{"title": "Summarize", "message": "Summarize #incidents from the last day"},
+				map[string]any{"title": "My mentions", "message": "What did people ask me today?"},
+			}},
+		}},
+	},
+	"users_search": {
+		Cost:       CostLow,
+		SlackCalls: "0-1, usually served from the users cache; plus 1 per user with presence or include_presence, at most 30",
+		Examples: []ToolExample{
+			{
+				Description: "Find a colleague by name",
+				Input:       map[string]any{"query": "ana lee"},
+				Output:      "UserID,UserName,RealName,DisplayName,Email,Title,...\nU0123ABCD,ana,Ana Lee,Ana,ana@example.com,Support Engineer,...",
+			},
+			{
+				Description: "Someone on the SRE team who can be reached now",
+				Input:       map[string]any{"query": "sre", "presence": "active", "dnd": false, "out_of_office": false},
+			},
+		},
+	},
+}
+
+// applyToolMetadata attaches the cost hint and examples of every registered
+// tool to its _meta, where clients that show tool details can pick them up.
+func applyToolMetadata(s *server.MCPServer) {
+	for name, tool := range s.ListTools() {
+		md, ok := toolMetadata[name]
+		if !ok {
+			continue
+		}
+		meta := &mcp.Meta{AdditionalFields: map[string]any{}}
+		if tool.Tool.Meta != nil {
+			meta.AdditionalFields = maps.Clone(tool.Tool.Meta.AdditionalFields)
+		}
+		meta.AdditionalFields["cost"] = md.Cost
+		meta.AdditionalFields["slack_calls"] = md.SlackCalls
+		meta.AdditionalFields["examples"] = md.Examples
+		tool.Tool.Meta = meta
+		s.AddTools(*tool)
+	}
+}
+
+// ToolGuide is one entry of the slack://<workspace>/tools resource.
+type ToolGuide struct {
+	Name string `json:"name"`
+	ToolMetadata
+}
+
+// buildToolsResource serves the cost hints and examples of the registered
+// tools, in the order of the capabilities' tool list.
+func buildToolsResource(caps func() *Capabilities, ap *provider.ApiProvider, logger *zap.Logger) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		logger.Debug("ToolsResource called", zap.Any("params", request.Params))
+
+		if authenticated, err := auth.IsAuthenticated(ctx, ap.ServerTransport(), logger); !authenticated {
+			logger.Error("Authentication failed for tools resource", zap.Error(err))
+			return nil, err
+		}
+
+		guides := []ToolGuide{}
+		if c := caps(); c != nil {
+			for _, name := range c.Tools {
+				if md, ok := toolMetadata[name]; ok {
+					guides = append(guides, ToolGuide{Name: name, ToolMetadata: md})
+				}
+			}
+		}
+		data, err := json.Marshal(guides)
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		}, nil
+	}
+}
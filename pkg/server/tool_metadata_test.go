@@ -0,0 +1,129 @@
+package server
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gocarina/gocsv"
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolMetadataCoversRegisteredTools(t *testing.T) {
+	s := newReloadTestServer(t)
+	cfg := offlineConfig()
+	cfg.EnabledTools = ValidToolNames
+	cfg.AdminUsersTool = "true"
+	cfg.Workflows = []config.WorkflowConfig{{Name: "access_request", TriggerURL: "https://hooks.slack.com/triggers/T0123/456/abc"}}
+	cfg.WriteJournal = filepath.Join(t.TempDir(), "write-journal.jsonl")
+	require.NoError(t, s.apply(cfg, nil))
+
+	tools := s.server.ListTools()
+	for _, name := range ValidToolNames {
+		md, ok := toolMetadata[name]
+		if !assert.True(t, ok, "tool %s has no metadata", name) {
+			continue
+		}
+		assert.Contains(t, []ToolCost{CostLow, CostMedium, CostHigh}, md.Cost, name)
+		assert.NotEmpty(t, md.Examples, "tool %s has no examples", name)
+
+		tool, ok := tools[name]
+		require.True(t, ok, "tool %s is not registered", name)
+		for _, ex := range md.Examples {
+			for param := range ex.Input {
+				assert.Contains(t, tool.Tool.InputSchema.Properties, param, "example %q of %s", ex.Description, name)
+			}
+			for _, param := range tool.Tool.InputSchema.Required {
+				if !slices.Contains(sessionDefaultParams[name], param) {
+					assert.Contains(t, ex.Input, param, "example %q of %s misses a required parameter", ex.Description, name)
+				}
+			}
+		}
+
+		require.NotNil(t, tool.Tool.Meta, name)
+		assert.Equal(t, md.Cost, tool.Tool.Meta.AdditionalFields["cost"], name)
+	}
+}
+
+func TestConfiguredTools(t *testing.T) {
+	cfg := config.Default()
+	cfg.EnabledTools = []string{ToolConversationsHistory, ToolConversationsAddMessage}
+	toolsConfig, err := LoadToolsConfig(writeToolsConfig(t, `
+tools:
+  conversations_history:
+    description: "Read a channel."
+`), nil)
+	require.NoError(t, err)
+
+	tools := ConfiguredTools(cfg, toolsConfig)
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	assert.True(t, slices.IsSorted(names), names)
+	require.Contains(t, names, ToolConversationsAddMessage)
+	require.Contains(t, names, ToolConversationsHistory)
+	assert.NotContains(t, names, ToolChannelsList)
+
+	post := tools[slices.Index(names, ToolConversationsAddMessage)]
+	assert.False(t, post.ReadOnly)
+	assert.Contains(t, post.Groups, "write")
+	assert.Equal(t, toolScopes[ToolConversationsAddMessage], post.Scopes)
+
+	history := tools[slices.Index(names, ToolConversationsHistory)]
+	assert.Equal(t, "Read a channel.", history.Description, "tools config overrides the description")
+	assert.True(t, history.ReadOnly)
+	assert.Equal(t, []string{"read"}, history.Groups)
+	assert.Equal(t, CostMedium, history.Cost)
+}
+
+// exampleRows are the row types of the tools whose examples show CSV.
+var exampleRows = map[string]any{
+	ToolConversationsHistory:        handler.Message{},
+	ToolConversationsDMHistory:      handler.Message{},
+	ToolConversationsReplies:        handler.Message{},
+	ToolThreadSearch:                handler.Message{},
+	ToolConversationsContext:        handler.Message{},
+	ToolConversationsAddMessage:     handler.Message{},
+	ToolConversationsSearchMessages: handler.Message{},
+	ToolThreadParticipants:          handler.ThreadParticipantRow{},
+	ToolConversationsAddMessages:    handler.BatchMessageResult{},
+	ToolConversationsOpenDM:         handler.OpenedDM{},
+	ToolConversationsInvite:         handler.InviteResult{},
+	ToolConversationsSetTopic:       handler.ChannelInfoUpdate{},
+	ToolConversationsCleanup:        handler.CleanupResult{},
+	ToolReactionsAddBulk:            handler.BulkReactionResult{},
+	ToolChannelsList:                handler.Channel{},
+	ToolDirectoryList:               handler.DirectoryUser{},
+	"users_search":                  handler.UserSearchResult{},
+}
+
+func TestToolMetadataExampleHeaders(t *testing.T) {
+	for name, md := range toolMetadata {
+		for _, ex := range md.Examples {
+			if ex.Output == "" || strings.HasPrefix(ex.Output, "{") {
+				continue
+			}
+			row, ok := exampleRows[name]
+			require.True(t, ok, "tool %s has a CSV example but no row type", name)
+
+			rows := reflect.New(reflect.SliceOf(reflect.TypeOf(row)))
+			out, err := gocsv.MarshalString(rows.Interface())
+			require.NoError(t, err)
+			header := strings.Split(strings.TrimSpace(out), ",")
+
+			columns := strings.Split(strings.SplitN(ex.Output, "\n", 2)[0], ",")
+			if columns[len(columns)-1] == "..." {
+				columns = columns[:len(columns)-1]
+				require.LessOrEqual(t, len(columns), len(header), "example %q of %s", ex.Description, name)
+				header = header[:len(columns)]
+			}
+			assert.Equal(t, header, columns, "example %q of %s", ex.Description, name)
+		}
+	}
+}
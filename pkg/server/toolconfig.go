@@ -0,0 +1,362 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/korotovsky/slack-mcp-server/pkg/provider"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// ToolGroups maps each group name usable in the tools config to its tools.
+// A tool may belong to several groups.
+var ToolGroups = map[string][]string{
+	"read": {
+		ToolConversationsHistory,
+		ToolConversationsDMHistory,
+		ToolConversationsReplies,
+		ToolThreadSearch,
+		ToolThreadParticipants,
+		ToolConversationsContext,
+		ToolConversationsSearchMessages,
+		ToolChannelsList,
+		ToolDirectoryList,
+		ToolAttachmentGetData,
+		ToolUsersStatusGet,
+		ToolActivityMentions,
+		ToolActivityThreads,
+		ToolFilesList,
+		ToolFilesInfo,
+		ToolActivityFeed,
+		ToolTeamInfo,
+		ToolReactionsSearch,
+		ToolContextSet,
+		ToolConversationsTranscript,
+		ToolPendingWrites,
+	},
+	"write": {
+		ToolConversationsAddMessage,
+		ToolConversationsAddMessages,
+		ToolConversationsForwardMessage,
+		ToolConversationsOpenDM,
+		ToolConversationsInvite,
+		ToolConversationsSetTopic,
+		ToolConversationsSetPurpose,
+		ToolConversationsCleanup,
+		ToolHuddlesStart,
+		ToolThreadsFollow,
+		ToolThreadsUnfollow,
+		ToolReactionsAdd,
+		ToolReactionsAddBulk,
+		ToolReactionsRemove,
+		ToolUsersStatusSet,
+		ToolUsersProfileSet,
+		ToolSavedComplete,
+		ToolAssistantSetStatus,
+		ToolAssistantSetTitle,
+		ToolAssistantSetPrompts,
+		ToolWorkflowsTrigger,
+	},
+	"admin": {
+		ToolAdminAuditSearch,
+		ToolAdminUsersInvite,
+		ToolAdminUsersRemove,
+		ToolUsergroupsCreate,
+		ToolUsergroupsUpdate,
+		ToolUsergroupsUsersUpdate,
+		ToolUsergroupsUsersModify,
+		ToolUsergroupsDisable,
+	},
+	"usergroups": {
+		ToolUsergroupsList,
+		ToolUsergroupsMe,
+		ToolUsergroupsCreate,
+		ToolUsergroupsUpdate,
+		ToolUsergroupsUsersUpdate,
+		ToolUsergroupsUsersModify,
+		ToolUsergroupsDisable,
+	},
+	"saved": {
+		ToolSavedList,
+		ToolSavedComplete,
+	},
+	"assistant": {
+		ToolAssistantSetStatus,
+		ToolAssistantSetTitle,
+		ToolAssistantSetPrompts,
+	},
+}
+
+// toolPolicies lists the tools that are off by default unless their policy
+// setting (SLACK_MCP_ADD_MESSAGE_TOOL and friends) is non-empty.
+var toolPolicies = map[string]func(*config.Config) string{
+	ToolConversationsAddMessage:     func(c *config.Config) string { return c.AddMessageTool },
+	ToolConversationsAddMessages:    func(c *config.Config) string { return c.AddMessageTool },
+	ToolConversationsForwardMessage: func(c *config.Config) string { return c.AddMessageTool },
+	ToolConversationsOpenDM:         func(c *config.Config) string { return c.AddMessageTool },
+	ToolConversationsInvite:         func(c *config.Config) string { return c.InviteTool },
+	ToolConversationsSetTopic:       func(c *config.Config) string { return c.ChannelAdminTool },
+	ToolConversationsSetPurpose:     func(c *config.Config) string { return c.ChannelAdminTool },
+	ToolConversationsCleanup:        func(c *config.Config) string { return c.CleanupTool },
+	ToolHuddlesStart:                func(c *config.Config) string { return c.AddMessageTool },
+	ToolThreadsFollow:               func(c *config.Config) string { return c.ThreadFollowTool },
+	ToolThreadsUnfollow:             func(c *config.Config) string { return c.ThreadFollowTool },
+	ToolAdminAuditSearch:            func(c *config.Config) string { return c.AuditLogsTool },
+	ToolAdminUsersInvite:            func(c *config.Config) string { return c.AdminUsersTool },
+	ToolAdminUsersRemove:            func(c *config.Config) string { return c.AdminUsersTool },
+	ToolReactionsAdd:                func(c *config.Config) string { return c.ReactionTool },
+	ToolReactionsAddBulk:            func(c *config.Config) string { return c.ReactionTool },
+	ToolReactionsRemove:             func(c *config.Config) string { return c.ReactionTool },
+	ToolAttachmentGetData:           func(c *config.Config) string { return c.AttachmentTool },
+	ToolSavedList:                   func(c *config.Config) string { return c.SavedListTool },
+	ToolSavedComplete:               func(c *config.Config) string { return c.SavedCompleteTool },
+	ToolUsersStatusSet:              func(c *config.Config) string { return c.UserStatusTool },
+	ToolUsersProfileSet:             func(c *config.Config) string { return c.UserProfileTool },
+	ToolAssistantSetStatus:          func(c *config.Config) string { return c.AssistantTool },
+	ToolAssistantSetTitle:           func(c *config.Config) string { return c.AssistantTool },
+	ToolAssistantSetPrompts:         func(c *config.Config) string { return c.AssistantTool },
+	ToolWorkflowsTrigger: func(c *config.Config) string {
+		if len(c.Workflows) > 0 {
+			return "true"
+		}
+		return ""
+	},
+}
+
+// ToolsConfig is the optional YAML file selecting tools by group or by name,
+// restricting the argument values individual tools accept and rewording the
+// descriptions clients see:
+//
+//	groups:
+//	  read: true
+//	  write: false
+//	tools:
+//	  conversations_add_message:
+//	    enabled: true
+//	    allow:
+//	      channel_id: ["#support", "C0123456789"]
+//	    params:
+//	      channel_id: "Post in #support unless the user names a channel."
+//	  conversations_history:
+//	    fields: [MsgID, UserName, Text, Time]
+type ToolsConfig struct {
+	Groups map[string]bool       `yaml:"groups"`
+	Tools  map[string]ToolConfig `yaml:"tools"`
+}
+
+// ToolConfig overrides group membership for one tool and restricts the values
+// of its arguments. Allow maps an argument name to accepted values; entries
+// prefixed with "!" are rejected instead. Channel names ("#general") and IDs
+// both match. Fields selects the CSV columns of read tools when a call does
+// not pass fields itself. Description and Params replace the descriptions of
+// the tool and of its parameters, by name, that clients are shown.
+type ToolConfig struct {
+	Enabled     *bool               `yaml:"enabled"`
+	Allow       map[string][]string `yaml:"allow"`
+	Fields      []string            `yaml:"fields"`
+	Description string              `yaml:"description"`
+	Params      map[string]string   `yaml:"params"`
+}
+
+// LoadToolsConfig reads the tools config at path and applies the group
+// overrides of config.Config.ToolsGroups.
+func LoadToolsConfig(path string, groups map[string]bool) (*ToolsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tools config: %w", err)
+	}
+
+	var cfg ToolsConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse tools config %s: %w", path, err)
+	}
+	if cfg.Groups == nil {
+		cfg.Groups = make(map[string]bool)
+	}
+
+	maps.Copy(cfg.Groups, groups)
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid tools config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+func (c *ToolsConfig) validate() error {
+	for group := range c.Groups {
+		if _, ok := ToolGroups[group]; !ok {
+			return fmt.Errorf("unknown tool group %q, valid groups are: %s", group, strings.Join(toolGroupNames(), ", "))
+		}
+	}
+	var tools []string
+	for tool, tc := range c.Tools {
+		if len(tc.Fields) > 0 && !csvOutputTools[tool] {
+			return fmt.Errorf("%s: fields is only supported by tools returning CSV", tool)
+		}
+		tools = append(tools, tool)
+	}
+	return ValidateEnabledTools(tools)
+}
+
+// ResolveEnabledTools returns the tools to register. A tool's own enabled
+// setting wins; otherwise a disabled group it belongs to turns it off and an
+// enabled one turns it on. Tools the config doesn't mention keep the behaviour
+// of cfg.EnabledTools (the --enabled-tools list) or, when it is empty, the
+// defaults.
+func (c *ToolsConfig) ResolveEnabledTools(cfg *config.Config) ([]string, error) {
+	var enabled []string
+	for _, tool := range ValidToolNames {
+		if c.toolEnabled(tool, cfg) {
+			enabled = append(enabled, tool)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, errors.New("tools config disables every tool")
+	}
+	return enabled, nil
+}
+
+func (c *ToolsConfig) toolEnabled(tool string, cfg *config.Config) bool {
+	if tc, ok := c.Tools[tool]; ok && tc.Enabled != nil {
+		return *tc.Enabled
+	}
+
+	decided, on := false, false
+	for group, tools := range ToolGroups {
+		v, ok := c.Groups[group]
+		if !ok || !slices.Contains(tools, tool) {
+			continue
+		}
+		if !v {
+			return false
+		}
+		decided, on = true, true
+	}
+	if decided {
+		return on
+	}
+
+	return shouldAddTool(tool, cfg)
+}
+
+func (c *ToolsConfig) hasRestrictions() bool {
+	if c == nil {
+		return false
+	}
+	for _, tc := range c.Tools {
+		if len(tc.Allow) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func toolGroupNames() []string {
+	names := make([]string, 0, len(ToolGroups))
+	for name := range ToolGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildToolRestrictionsMiddleware rejects calls whose arguments fall outside
+// the allow rules of the tools config. Rules on an argument also apply to the
+// same key inside array-of-object arguments, so batch tools are covered.
+// toolsConfig is read on every call so reloads apply immediately.
+func buildToolRestrictionsMiddleware(toolsConfig func() *ToolsConfig, p *provider.ApiProvider, logger *zap.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			cfg := toolsConfig()
+			if !cfg.hasRestrictions() {
+				return next(ctx, req)
+			}
+			tc, ok := cfg.Tools[req.Params.Name]
+			if !ok {
+				return next(ctx, req)
+			}
+			args := req.GetArguments()
+			for param, rule := range tc.Allow {
+				for _, value := range argumentValues(args, param) {
+					if !restrictionAllows(p, rule, value) {
+						logger.Warn("Tool call rejected by tools config",
+							zap.String("tool", req.Params.Name),
+							zap.String("param", param),
+							zap.String("value", value),
+						)
+						return nil, fmt.Errorf("%s: %s %q is not allowed by the tools config", req.Params.Name, param, value)
+					}
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// argumentValues collects the string form of args[param] and of param in
+// every object of array arguments.
+func argumentValues(args map[string]any, param string) []string {
+	var values []string
+	if v, ok := args[param]; ok && v != nil {
+		values = append(values, fmt.Sprint(v))
+	}
+	for _, v := range args {
+		items, ok := v.([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			if obj, ok := item.(map[string]any); ok {
+				if iv, ok := obj[param]; ok && iv != nil {
+					values = append(values, fmt.Sprint(iv))
+				}
+			}
+		}
+	}
+	return values
+}
+
+// restrictionAllows reports whether value passes rule: it must match an
+// allow entry when there are any, and must not match a "!" entry.
+func restrictionAllows(p *provider.ApiProvider, rule []string, value string) bool {
+	value = resolveRestrictionValue(p, value)
+	hasAllow, allowed := false, false
+	for _, entry := range rule {
+		entry = strings.TrimSpace(entry)
+		if deny, ok := strings.CutPrefix(entry, "!"); ok {
+			if resolveRestrictionValue(p, deny) == value {
+				return false
+			}
+			continue
+		}
+		hasAllow = true
+		if resolveRestrictionValue(p, entry) == value {
+			allowed = true
+		}
+	}
+	return !hasAllow || allowed
+}
+
+// resolveRestrictionValue maps "#channel" and "@user_dm" names to channel IDs
+// when the channels cache knows them.
+func resolveRestrictionValue(p *provider.ApiProvider, value string) string {
+	if p == nil || (!strings.HasPrefix(value, "#") && !strings.HasPrefix(value, "@")) {
+		return value
+	}
+	if cache := p.ProvideChannelsMaps(); cache != nil {
+		if id, ok := cache.ChannelsInv[value]; ok {
+			return id
+		}
+	}
+	return value
+}
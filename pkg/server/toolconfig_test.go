@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeToolsConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tools.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestToolGroupsCoverAllTools(t *testing.T) {
+	for _, tool := range ValidToolNames {
+		found := false
+		for _, tools := range ToolGroups {
+			if slices.Contains(tools, tool) {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "tool %s is not in any group", tool)
+	}
+}
+
+func TestLoadToolsConfigResolve(t *testing.T) {
+	for _, env := range []string{"SLACK_MCP_ADD_MESSAGE_TOOL", "SLACK_MCP_REACTION_TOOL", "SLACK_MCP_ATTACHMENT_TOOL",
+		"SLACK_MCP_SAVED_LIST_TOOL", "SLACK_MCP_SAVED_COMPLETE_TOOL", "SLACK_MCP_USER_STATUS_TOOL"} {
+		t.Setenv(env, "")
+	}
+
+	path := writeToolsConfig(t, `
+groups:
+  read: true
+  usergroups: true
+  admin: false
+tools:
+  conversations_add_message:
+    enabled: true
+    allow:
+      channel_id: ["C111", "C222"]
+  channels_list:
+    enabled: false
+`)
+	cfg, err := LoadToolsConfig(path, nil)
+	require.NoError(t, err)
+
+	enabled, err := cfg.ResolveEnabledTools(envConfig(t, nil))
+	require.NoError(t, err)
+	assert.Contains(t, enabled, ToolConversationsHistory)
+	assert.Contains(t, enabled, ToolAttachmentGetData, "enabled group overrides env default")
+	assert.Contains(t, enabled, ToolConversationsAddMessage, "per-tool setting wins")
+	assert.Contains(t, enabled, ToolUsergroupsList)
+	assert.NotContains(t, enabled, ToolChannelsList)
+	assert.NotContains(t, enabled, ToolUsergroupsCreate, "disabled group wins over enabled group")
+	assert.NotContains(t, enabled, ToolConversationsAddMessages, "unmentioned write tool keeps its env default")
+
+	t.Setenv("SLACK_MCP_TOOLS_GROUP_READ", "false")
+	cfg, err = LoadToolsConfig(path, envConfig(t, nil).ToolsGroups)
+	require.NoError(t, err)
+	enabled, err = cfg.ResolveEnabledTools(envConfig(t, nil))
+	require.NoError(t, err)
+	assert.NotContains(t, enabled, ToolConversationsHistory, "env override disables the group")
+}
+
+func TestLoadToolsConfigInvalid(t *testing.T) {
+	_, err := LoadToolsConfig(writeToolsConfig(t, "groups:\n  bogus: true\n"), nil)
+	assert.ErrorContains(t, err, "unknown tool group")
+
+	_, err = LoadToolsConfig(writeToolsConfig(t, "tools:\n  not_a_tool:\n    enabled: true\n"), nil)
+	assert.ErrorContains(t, err, "invalid tool name")
+
+	_, err = LoadToolsConfig(writeToolsConfig(t, "tools:\n  conversations_add_message:\n    fields: [msgID]\n"), nil)
+	assert.ErrorContains(t, err, "fields is only supported by tools returning CSV")
+
+	_, err = LoadToolsConfig(writeToolsConfig(t, "groups: [read]\n"), nil)
+	assert.Error(t, err)
+}
+
+func TestToolRestrictionsMiddleware(t *testing.T) {
+	cfg := &ToolsConfig{Tools: map[string]ToolConfig{
+		ToolConversationsAddMessage:  {Allow: map[string][]string{"channel_id": {"C111"}}},
+		ToolConversationsAddMessages: {Allow: map[string][]string{"channel_id": {"!C999"}}},
+	}}
+
+	called := 0
+	handler := buildToolRestrictionsMiddleware(func() *ToolsConfig { return cfg }, nil, zap.NewNop())(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called++
+		return mcp.NewToolResultText("ok"), nil
+	})
+	call := func(tool string, args map[string]any) error {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		_, err := handler(context.Background(), req)
+		return err
+	}
+
+	assert.NoError(t, call(ToolConversationsAddMessage, map[string]any{"channel_id": "C111"}))
+	assert.Error(t, call(ToolConversationsAddMessage, map[string]any{"channel_id": "C222"}))
+	assert.NoError(t, call(ToolConversationsHistory, map[string]any{"channel_id": "C222"}))
+
+	batch := func(channels ...string) map[string]any {
+		var items []any
+		for _, c := range channels {
+			items = append(items, map[string]any{"channel_id": c, "text": "hi"})
+		}
+		return map[string]any{"messages": items}
+	}
+	assert.NoError(t, call(ToolConversationsAddMessages, batch("C111", "C222")))
+	assert.Error(t, call(ToolConversationsAddMessages, batch("C111", "C999")))
+	assert.Equal(t, 3, called)
+}
+
+func TestApplyDescriptionOverrides(t *testing.T) {
+	cfg, err := LoadToolsConfig(writeToolsConfig(t, `
+tools:
+  conversations_add_message:
+    description: "Post a message. Always post in #agent-output."
+    params:
+      channel_id: "Use #agent-output."
+      bogus: ignored
+  channels_list:
+    description: not registered
+`), nil)
+	require.NoError(t, err)
+
+	original := mcp.NewTool(ToolConversationsAddMessage,
+		mcp.WithDescription("Add a message"),
+		mcp.WithString("channel_id", mcp.Required(), mcp.Description("ID of the channel")),
+		mcp.WithString("text", mcp.Description("Message text")),
+	)
+	s := server.NewMCPServer("test", "0")
+	s.AddTool(original, nil)
+
+	applyDescriptionOverrides(s, cfg, zap.NewNop())
+
+	tool := s.GetTool(ToolConversationsAddMessage).Tool
+	assert.Equal(t, "Post a message. Always post in #agent-output.", tool.Description)
+	assert.Equal(t, "Use #agent-output.", tool.InputSchema.Properties["channel_id"].(map[string]any)["description"])
+	assert.Equal(t, "string", tool.InputSchema.Properties["channel_id"].(map[string]any)["type"])
+	assert.Equal(t, "Message text", tool.InputSchema.Properties["text"].(map[string]any)["description"])
+	assert.Equal(t, []string{"channel_id"}, tool.InputSchema.Required)
+	assert.NotContains(t, tool.InputSchema.Properties, "bogus")
+	assert.Equal(t, "ID of the channel", original.InputSchema.Properties["channel_id"].(map[string]any)["description"], "the static definition is not modified")
+	assert.Nil(t, s.GetTool(ToolChannelsList))
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	webhookQueueSize   = 1000
+	webhookTimeout     = 5 * time.Second
+	webhookMaxAttempts = 3
+	// webhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of
+	// "<timestamp>.<body>" keyed with SLACK_MCP_WEBHOOK_SECRET.
+	webhookSignatureHeader = "X-Slack-MCP-Signature"
+	webhookTimestampHeader = "X-Slack-MCP-Timestamp"
+)
+
+// toolEvent is the JSON body posted to the webhook for every tool call.
+type toolEvent struct {
+	ID         string         `json:"id"`
+	Time       time.Time      `json:"time"`
+	Tool       string         `json:"tool"`
+	Outcome    string         `json:"outcome"` // success or error
+	Error      string         `json:"error,omitempty"`
+	DurationMs int64          `json:"duration_ms"`
+	Transport  string         `json:"transport"`
+	SessionID  string         `json:"session_id,omitempty"`
+	Caller     string         `json:"caller,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	// Redactions counts the matches of each redaction rule masked in the
+	// result.
+	Redactions map[string]int `json:"redactions,omitempty"`
+}
+
+// webhookSink posts tool events to an HTTP endpoint from a background worker,
+// so a slow or unreachable endpoint never delays tool calls. Events that do
+// not fit the queue are dropped and counted.
+type webhookSink struct {
+	url          string
+	secret       []byte
+	includeArgs  bool
+	client       *http.Client
+	retryBackoff time.Duration
+	logger       *zap.Logger
+
+	queue   chan toolEvent
+	pending sync.WaitGroup // queued events not yet delivered or given up on
+
+	mu              sync.Mutex // protects dropped, droppedReported
+	dropped         int
+	droppedReported time.Time
+}
+
+// newWebhookSinkFromConfig returns nil unless SLACK_MCP_WEBHOOK_URL is set.
+func newWebhookSinkFromConfig(cfg *config.Config, logger *zap.Logger) *webhookSink {
+	raw := cfg.WebhookURL
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		logger.Error("Ignoring SLACK_MCP_WEBHOOK_URL, it must be an absolute http or https URL",
+			zap.String("context", "console"),
+		)
+		return nil
+	}
+
+	secret := cfg.WebhookSecret
+	if secret == "" {
+		logger.Warn("SLACK_MCP_WEBHOOK_SECRET is not set, webhook events will not be signed",
+			zap.String("context", "console"),
+		)
+	}
+
+	logger.Info("Tool call webhook enabled",
+		zap.String("context", "console"),
+		zap.String("host", u.Host),
+	)
+	return newWebhookSink(raw, secret, cfg.WebhookArguments(), logger)
+}
+
+func newWebhookSink(endpoint, secret string, includeArgs bool, logger *zap.Logger) *webhookSink {
+	return &webhookSink{
+		url:          endpoint,
+		secret:       []byte(secret),
+		includeArgs:  includeArgs,
+		client:       &http.Client{Timeout: webhookTimeout},
+		logger:       logger,
+		queue:        make(chan toolEvent, webhookQueueSize),
+		retryBackoff: time.Second,
+	}
+}
+
+// enqueue hands ev to the worker without blocking.
+func (w *webhookSink) enqueue(ev toolEvent) {
+	w.pending.Add(1)
+	select {
+	case w.queue <- ev:
+	default:
+		w.pending.Done()
+		w.mu.Lock()
+		w.dropped++
+		dropped := w.dropped
+		report := time.Since(w.droppedReported) > time.Minute
+		if report {
+			w.droppedReported = time.Now()
+		}
+		w.mu.Unlock()
+		if report {
+			w.logger.Warn("Webhook queue full, dropping tool events", zap.Int("dropped_total", dropped))
+		}
+	}
+}
+
+// run delivers queued events until ctx is cancelled.
+func (w *webhookSink) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-w.queue:
+			if err := w.deliver(ctx, ev); err != nil {
+				w.logger.Warn("Failed to deliver tool event to webhook",
+					zap.String("tool", ev.Tool),
+					zap.String("event_id", ev.ID),
+					zap.Error(err),
+				)
+			}
+			w.pending.Done()
+		}
+	}
+}
+
+// flush waits until queued events are delivered or ctx expires.
+func (w *webhookSink) flush(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		w.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// deliver posts ev, retrying network errors and 5xx/429 responses.
+func (w *webhookSink) deliver(ctx context.Context, ev toolEvent) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.retryBackoff * time.Duration(attempt-1)):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if len(w.secret) > 0 {
+			ts := strconv.FormatInt(time.Now().Unix(), 10)
+			req.Header.Set(webhookTimestampHeader, ts)
+			req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(w.secret, ts, body))
+		}
+
+		resp, err := w.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook returned %s", resp.Status)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "<ts>.<body>". Receivers should
+// recompute it and reject stale timestamps to prevent replays.
+func signWebhook(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// buildWebhookMiddleware reports every tool call and its outcome to w. It
+// sits outside the auth middleware so rejected calls are reported too.
+func buildWebhookMiddleware(w *webhookSink, transport string) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if w == nil {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			res, err := next(ctx, req)
+
+			ev := toolEvent{
+				ID:         newEventID(),
+				Time:       start.UTC(),
+				Tool:       req.Params.Name,
+				Outcome:    "success",
+				DurationMs: time.Since(start).Milliseconds(),
+				Transport:  transport,
+				Caller:     callerIdentity(ctx),
+				Redactions: resultRedactions(res),
+			}
+			if session := server.ClientSessionFromContext(ctx); session != nil {
+				ev.SessionID = session.SessionID()
+			}
+			if w.includeArgs {
+				ev.Arguments = req.GetArguments()
+			}
+			switch {
+			case err != nil:
+				ev.Outcome, ev.Error = "error", err.Error()
+			case res != nil && res.IsError:
+				ev.Outcome, ev.Error = "error", toolResultText(res)
+			}
+			w.enqueue(ev)
+
+			return res, err
+		}
+	}
+}
+
+// toolResultText returns the first text content of res.
+func toolResultText(res *mcp.CallToolResult) string {
+	for _, c := range res.Content {
+		if t, ok := c.(mcp.TextContent); ok {
+			return t.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWebhookSinkDelivers(t *testing.T) {
+	events := make(chan toolEvent, 4)
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get(webhookTimestampHeader)
+		if r.Header.Get(webhookSignatureHeader) != "sha256="+signWebhook([]byte("s3cret"), ts, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// The first attempt fails to exercise the retry.
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var ev toolEvent
+		_ = json.Unmarshal(body, &ev)
+		events <- ev
+	}))
+	defer srv.Close()
+
+	sink := newWebhookSink(srv.URL, "s3cret", false, zap.NewNop())
+	sink.retryBackoff = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sink.run(ctx)
+
+	ok := buildWebhookMiddleware(sink, "http")(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done"), nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "channels_list"
+	req.Params.Arguments = map[string]any{"limit": 10}
+	_, err := ok(ctx, req)
+	require.NoError(t, err)
+
+	flushCtx, flushCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer flushCancel()
+	sink.flush(flushCtx)
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, "channels_list", ev.Tool)
+		assert.Equal(t, "success", ev.Outcome)
+		assert.Equal(t, "http", ev.Transport)
+		assert.NotEmpty(t, ev.ID)
+		assert.Nil(t, ev.Arguments, "arguments are only sent when enabled")
+	default:
+		t.Fatal("event was not delivered")
+	}
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestWebhookMiddlewareOutcome(t *testing.T) {
+	sink := newWebhookSink("http://example.invalid", "", true, zap.NewNop())
+	mw := buildWebhookMiddleware(sink, "stdio")
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "conversations_add_message"
+	req.Params.Arguments = map[string]any{"channel_id": "C1"}
+
+	_, _ = mw(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("not allowed")
+	})(context.Background(), req)
+	_, _ = mw(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("channel not found"), nil
+	})(context.Background(), req)
+
+	ev := <-sink.queue
+	assert.Equal(t, "error", ev.Outcome)
+	assert.Equal(t, "not allowed", ev.Error)
+	assert.Equal(t, map[string]any{"channel_id": "C1"}, ev.Arguments)
+
+	ev = <-sink.queue
+	assert.Equal(t, "error", ev.Outcome)
+	assert.Equal(t, "channel not found", ev.Error)
+}
+
+func TestWebhookSinkDropsWhenFull(t *testing.T) {
+	sink := newWebhookSink("http://example.invalid", "", false, zap.NewNop())
+	sink.queue = make(chan toolEvent, 1)
+
+	sink.enqueue(toolEvent{Tool: "a"})
+	sink.enqueue(toolEvent{Tool: "b"})
+
+	assert.Equal(t, 1, sink.dropped)
+	assert.Len(t, sink.queue, 1)
+}
+
+func TestNewWebhookSinkFromConfig(t *testing.T) {
+	assert.Nil(t, newWebhookSinkFromConfig(&config.Config{}, zap.NewNop()))
+	assert.Nil(t, newWebhookSinkFromConfig(&config.Config{WebhookURL: "ftp://example.com/hook"}, zap.NewNop()))
+
+	sink := newWebhookSinkFromConfig(&config.Config{
+		WebhookURL:              "https://example.com/hook",
+		WebhookIncludeArguments: "true",
+	}, zap.NewNop())
+	require.NotNil(t, sink)
+	assert.True(t, sink.includeArgs)
+}
+
+func TestWebhookMiddlewareDisabled(t *testing.T) {
+	var called bool
+	next := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return nil, nil
+	}
+	_, _ = buildWebhookMiddleware(nil, "stdio")(next)(context.Background(), mcp.CallToolRequest{})
+	assert.True(t, called)
+}
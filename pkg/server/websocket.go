@@ -0,0 +1,256 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.uber.org/zap"
+)
+
+const (
+	wsEndpointPath = "/ws"
+	// wsReadLimit bounds one incoming JSON-RPC message.
+	wsReadLimit = 4 << 20
+	// wsPingInterval keeps idle connections open through proxies and load
+	// balancers; a peer that misses two pings is dropped.
+	wsPingInterval = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WebSocketServer serves MCP over WebSocket. Each connection is one session
+// carrying one JSON-RPC message per frame in both directions, so responses
+// and notifications are not held back by proxies that buffer SSE streams.
+type WebSocketServer struct {
+	server      *server.MCPServer
+	logger      *zap.Logger
+	contextFunc func(context.Context, *http.Request) context.Context
+	upgrader    websocket.Upgrader
+	httpServer  *http.Server
+
+	mu       sync.Mutex
+	sessions map[*wsSession]struct{}
+	closing  bool
+}
+
+// wsSession is the MCP client session of one WebSocket connection.
+type wsSession struct {
+	id            string
+	conn          *websocket.Conn
+	writeMu       sync.Mutex
+	notifications chan mcp.JSONRPCNotification
+	initialized   atomic.Bool
+}
+
+func (s *wsSession) SessionID() string { return s.id }
+
+func (s *wsSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *wsSession) Initialize() { s.initialized.Store(true) }
+
+func (s *wsSession) Initialized() bool { return s.initialized.Load() }
+
+// write sends one JSON-RPC message; the connection allows a single writer.
+func (s *wsSession) write(v any) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return s.conn.WriteJSON(v)
+}
+
+func (ws *WebSocketServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error.
+		ws.logger.Debug("WebSocket upgrade failed",
+			zap.String("context", "http"),
+			zap.String("remote_addr", r.RemoteAddr),
+			zap.Error(err),
+		)
+		return
+	}
+
+	session := &wsSession{
+		id:            uuid.NewString(),
+		conn:          conn,
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+	}
+	if !ws.track(session) {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(wsWriteTimeout))
+		_ = conn.Close()
+		return
+	}
+	defer ws.untrack(session)
+
+	// After the upgrade the request context stays live until this handler
+	// returns, so it spans the connection.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	if ws.contextFunc != nil {
+		ctx = ws.contextFunc(ctx, r)
+	}
+	if err := ws.server.RegisterSession(ctx, session); err != nil {
+		ws.logger.Error("Failed to register WebSocket session", zap.String("context", "http"), zap.Error(err))
+		_ = conn.Close()
+		return
+	}
+	defer ws.server.UnregisterSession(ctx, session.id)
+	ctx = ws.server.WithContext(ctx, session)
+
+	ws.logger.Debug("WebSocket session opened",
+		zap.String("context", "http"),
+		zap.String("session_id", session.id),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+	ws.serve(ctx, session)
+	ws.logger.Debug("WebSocket session closed",
+		zap.String("context", "http"),
+		zap.String("session_id", session.id),
+	)
+}
+
+// serve reads messages until the connection closes. Tool calls run
+// concurrently so that pings, notifications/cancelled and other requests
+// are still read while a long call is in flight; they are cancelled when the
+// connection goes away.
+func (ws *WebSocketServer) serve(ctx context.Context, session *wsSession) {
+	ctx, cancel := context.WithCancel(ctx)
+	var calls sync.WaitGroup
+	defer func() {
+		cancel()
+		_ = session.conn.Close()
+		calls.Wait()
+	}()
+
+	conn := session.conn
+	conn.SetReadLimit(wsReadLimit)
+	extend := func() { _ = conn.SetReadDeadline(time.Now().Add(2 * wsPingInterval)) }
+	extend()
+	conn.SetPongHandler(func(string) error {
+		extend()
+		return nil
+	})
+
+	go ws.writeLoop(ctx, session)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				ws.logger.Debug("WebSocket read failed",
+					zap.String("context", "http"),
+					zap.String("session_id", session.id),
+					zap.Error(err),
+				)
+			}
+			return
+		}
+		extend()
+
+		message := json.RawMessage(data)
+		var base struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(message, &base) == nil && base.Method == string(mcp.MethodToolsCall) {
+			calls.Add(1)
+			go func() {
+				defer calls.Done()
+				ws.respond(ctx, session, message)
+			}()
+			continue
+		}
+		ws.respond(ctx, session, message)
+	}
+}
+
+func (ws *WebSocketServer) respond(ctx context.Context, session *wsSession, message json.RawMessage) {
+	response := ws.server.HandleMessage(ctx, message)
+	if response == nil {
+		return
+	}
+	if err := session.write(response); err != nil {
+		ws.logger.Debug("WebSocket write failed",
+			zap.String("context", "http"),
+			zap.String("session_id", session.id),
+			zap.Error(err),
+		)
+	}
+}
+
+// writeLoop forwards server notifications and pings the client until the
+// connection closes.
+func (ws *WebSocketServer) writeLoop(ctx context.Context, session *wsSession) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-session.notifications:
+			if err := session.write(notification); err != nil {
+				_ = session.conn.Close()
+				return
+			}
+		case <-ticker.C:
+			if err := session.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				_ = session.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (ws *WebSocketServer) track(session *wsSession) bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if ws.closing {
+		return false
+	}
+	ws.sessions[session] = struct{}{}
+	return true
+}
+
+func (ws *WebSocketServer) untrack(session *wsSession) {
+	ws.mu.Lock()
+	delete(ws.sessions, session)
+	ws.mu.Unlock()
+}
+
+// Start listens on addr and serves until Shutdown.
+func (ws *WebSocketServer) Start(addr string) error {
+	ws.httpServer.Addr = addr
+	return ws.httpServer.ListenAndServe()
+}
+
+// Shutdown stops the listener and closes open connections with a going-away
+// close frame. http.Server.Shutdown does not track upgraded connections, so
+// they are closed here.
+func (ws *WebSocketServer) Shutdown(ctx context.Context) error {
+	ws.mu.Lock()
+	ws.closing = true
+	sessions := make([]*wsSession, 0, len(ws.sessions))
+	for session := range ws.sessions {
+		sessions = append(sessions, session)
+	}
+	ws.mu.Unlock()
+
+	err := ws.httpServer.Shutdown(ctx)
+	for _, session := range sessions {
+		_ = session.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+			time.Now().Add(wsWriteTimeout))
+		_ = session.conn.Close()
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/korotovsky/slack-mcp-server/pkg/server/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWebSocketServer(t *testing.T) {
+	srv := server.NewMCPServer("test", "0", server.WithToolCapabilities(false))
+	srv.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(auth.SlackTokenFromContext(ctx)), nil
+	})
+
+	ws := &WebSocketServer{
+		server:      srv,
+		logger:      zap.NewNop(),
+		contextFunc: auth.AuthFromRequest(zap.NewNop()),
+		sessions:    make(map[*wsSession]struct{}),
+	}
+	ws.httpServer = &http.Server{Handler: ws}
+	ts := httptest.NewServer(ws)
+	defer ts.Close()
+
+	header := http.Header{}
+	header.Set(auth.SlackTokenHeader, "xoxp-client")
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), header)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	call := func(id int, method string, params any) map[string]any {
+		t.Helper()
+		require.NoError(t, conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params}))
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var resp map[string]any
+		require.NoError(t, conn.ReadJSON(&resp))
+		assert.EqualValues(t, id, resp["id"])
+		return resp
+	}
+
+	resp := call(1, "initialize", map[string]any{
+		"protocolVersion": mcp.LATEST_PROTOCOL_VERSION,
+		"clientInfo":      map[string]any{"name": "test", "version": "0"},
+		"capabilities":    map[string]any{},
+	})
+	require.Contains(t, resp, "result")
+	require.NoError(t, conn.WriteJSON(map[string]any{"jsonrpc": "2.0", "method": "notifications/initialized"}))
+
+	resp = call(2, "tools/call", map[string]any{"name": "whoami"})
+	out, err := json.Marshal(resp["result"])
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "xoxp-client", "request headers reach the tool context")
+
+	require.NoError(t, ws.Shutdown(context.Background()))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "shutdown closes sessions: %v", err)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// journaledTool reports whether calls of tool change Slack and so go to
+// the write journal: the tools of the write and admin groups, except the
+// read-only audit log search.
+func journaledTool(tool string) bool {
+	if tool == ToolAdminAuditSearch {
+		return false
+	}
+	return slices.Contains(ToolGroups["write"], tool) || slices.Contains(ToolGroups["admin"], tool)
+}
+
+// buildWriteJournalMiddleware records write tool calls in j before they run
+// and once they finish. A nil j turns journaling off.
+func buildWriteJournalMiddleware(j *handler.WriteJournal) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if j == nil {
+				return next(ctx, req)
+			}
+			caller := callerIdentity(ctx)
+			if !journaledTool(req.Params.Name) {
+				return next(handler.WithWriteJournal(ctx, j, caller, ""), req)
+			}
+
+			id, err := j.Begin(caller, req.Params.Name, req.GetArguments())
+			if err != nil {
+				return nil, err
+			}
+			res, err := next(handler.WithWriteJournal(ctx, j, caller, id), req)
+			callErr := err
+			if callErr == nil && res != nil && res.IsError {
+				callErr = errors.New(toolResultText(res))
+			}
+			j.Finish(id, callErr)
+			return res, err
+		}
+	}
+}
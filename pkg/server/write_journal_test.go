@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/korotovsky/slack-mcp-server/pkg/handler"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWriteJournalMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "write-journal.jsonl")
+	j, err := handler.NewWriteJournal(path, zap.NewNop())
+	require.NoError(t, err)
+
+	var interrupted bool
+	next := buildWriteJournalMiddleware(j)(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		switch {
+		case interrupted:
+			// Simulate a crash: the call never returns to the middleware.
+			require.NoError(t, j.Close())
+			interrupted = false
+			j, err = handler.NewWriteJournal(path, zap.NewNop())
+			require.NoError(t, err)
+			return nil, errors.New("killed")
+		case req.GetString("text", "") == "fail":
+			return mcp.NewToolResultError("channel_not_found"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	call := func(tool string, args map[string]any) {
+		var req mcp.CallToolRequest
+		req.Params.Name = tool
+		req.Params.Arguments = args
+		_, _ = next(context.Background(), req)
+	}
+
+	call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "hello"})
+	call(ToolConversationsAddMessage, map[string]any{"channel_id": "C1", "text": "fail"})
+	call(ToolConversationsHistory, map[string]any{"channel_id": "C1"})
+	interrupted = true
+	call(ToolConversationsAddMessages, map[string]any{"messages": []any{}})
+
+	pending := j.Pending("")
+	require.Len(t, pending, 1, "only the call cut short is pending")
+	assert.Equal(t, ToolConversationsAddMessages, pending[0].Tool)
+	require.NoError(t, j.Close())
+}
+
+func TestJournaledTool(t *testing.T) {
+	assert.True(t, journaledTool(ToolConversationsAddMessages))
+	assert.True(t, journaledTool(ToolUsergroupsCreate))
+	assert.False(t, journaledTool(ToolAdminAuditSearch))
+	assert.False(t, journaledTool(ToolConversationsHistory))
+	assert.False(t, journaledTool(ToolPendingWrites))
+}
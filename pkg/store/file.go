@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// File keeps one file per key in a directory, written atomically through a
+// rename so readers never see a partial value. Replicas sharing the
+// directory, e.g. a network volume, share the store.
+type File struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFile opens the store in dir, creating the directory if needed.
+func NewFile(dir string) (*File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &File{dir: dir, now: time.Now}, nil
+}
+
+// path returns the file of key. Keys are escaped into a single file name so
+// a key cannot point outside the directory.
+func (f *File) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") {
+		return "", fmt.Errorf("invalid store key %q", key)
+	}
+	return filepath.Join(f.dir, url.PathEscape(key)), nil
+}
+
+func (f *File) Get(_ context.Context, key string) (Entry, bool, error) {
+	path, err := f.path(key)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	return f.read(path)
+}
+
+func (f *File) read(path string) (Entry, bool, error) {
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e, err := decodeEntry(buf)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if e.expired(f.now()) {
+		_ = os.Remove(path)
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (f *File) Put(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(encodeEntry(newEntry(value, ttl, f.now()))); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (f *File) Delete(_ context.Context, key string) error {
+	path, err := f.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (f *File) Scan(ctx context.Context, prefix string, fn func(key string, e Entry) error) error {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		key, err := url.PathUnescape(file.Name())
+		if err != nil || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		e, ok, err := f.read(filepath.Join(f.dir, file.Name()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(key, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *File) Close() error { return nil }
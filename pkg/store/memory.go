@@ -0,0 +1,80 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory is a process-local store, the default when SLACK_MCP_STORE is
+// unset.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]Entry
+	now     func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]Entry), now: time.Now}
+}
+
+func (m *Memory) Get(_ context.Context, key string) (Entry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	if e.expired(m.now()) {
+		delete(m.entries, key)
+		return Entry{}, false, nil
+	}
+	e.Value = bytes.Clone(e.Value)
+	return e, true, nil
+}
+
+func (m *Memory) Put(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = newEntry(bytes.Clone(value), ttl, m.now())
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) Scan(ctx context.Context, prefix string, fn func(key string, e Entry) error) error {
+	m.mu.Lock()
+	now := m.now()
+	matched := make(map[string]Entry)
+	for k, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, k)
+			continue
+		}
+		if strings.HasPrefix(k, prefix) {
+			e.Value = bytes.Clone(e.Value)
+			matched[k] = e
+		}
+	}
+	m.mu.Unlock()
+
+	// fn runs unlocked so it may call back into the store.
+	for k, e := range matched {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(k, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Close() error { return nil }
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRedisPrefix  = "slack-mcp:"
+	redisDialTimeout    = 5 * time.Second
+	redisCommandTimeout = 10 * time.Second
+	redisScanCount      = 200
+)
+
+// Redis is a store in a Redis server, shared by every replica pointing at
+// it, reached through a go-redis connection pool. Keys are namespaced by
+// the prefix query parameter, "slack-mcp:" by default.
+type Redis struct {
+	client *redis.Client
+	prefix string
+	now    func() time.Time
+}
+
+// NewRedis returns the store of a redis:// or rediss:// URL. Connections
+// are made on first use.
+func NewRedis(u *url.URL) (*Redis, error) {
+	db, err := redisDB(u)
+	if err != nil {
+		return nil, err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "6379"
+	}
+	opts := &redis.Options{
+		Addr:         net.JoinHostPort(u.Hostname(), port),
+		DB:           db,
+		DialTimeout:  redisDialTimeout,
+		ReadTimeout:  redisCommandTimeout,
+		WriteTimeout: redisCommandTimeout,
+	}
+	if u.Scheme == "rediss" {
+		opts.TLSConfig = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	r := &Redis{
+		client: redis.NewClient(opts),
+		prefix: defaultRedisPrefix,
+		now:    time.Now,
+	}
+	if p, ok := u.Query()["prefix"]; ok {
+		r.prefix = p[0]
+	}
+	return r, nil
+}
+
+func redisDB(u *url.URL) (int, error) {
+	path := strings.Trim(u.Path, "/")
+	if path == "" {
+		return 0, nil
+	}
+	db, err := strconv.Atoi(path)
+	if err != nil || db < 0 {
+		return 0, fmt.Errorf("redis store %q: the path must be a database number", u.Redacted())
+	}
+	return db, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) (Entry, bool, error) {
+	buf, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e, err := decodeEntry(buf)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if e.expired(r.now()) {
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (r *Redis) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+key, encodeEntry(newEntry(value, ttl, r.now())), ttl).Err()
+}
+
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.prefix+key).Err()
+}
+
+func (r *Redis) Scan(ctx context.Context, prefix string, fn func(key string, e Entry) error) error {
+	// SCAN may return a key twice across pages; callers only read.
+	iter := r.client.Scan(ctx, 0, redisGlobEscape(r.prefix+prefix)+"*", redisScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := strings.TrimPrefix(iter.Val(), r.prefix)
+		e, ok, err := r.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := fn(key, e); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+// redisGlobEscape escapes the glob characters of a SCAN MATCH pattern so a
+// prefix matches literally.
+func redisGlobEscape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
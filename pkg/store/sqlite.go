@@ -0,0 +1,148 @@
+//go:build cgo
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteAvailable reports whether this build has the SQLite driver, which
+// needs cgo.
+const sqliteAvailable = true
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS entries (
+	key        TEXT PRIMARY KEY,
+	value      BLOB NOT NULL,
+	stored_at  INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL DEFAULT 0
+)`
+
+// SQLite keeps the entries in one table of a SQLite database file, in WAL
+// mode so that readers do not wait for a writer. Servers on the same host
+// can share the file; replicas on several hosts should use Redis, since
+// SQLite locking is unreliable on network file systems.
+type SQLite struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewSQLite opens the database at path, creating it and its directory if
+// needed.
+func NewSQLite(path string) (*SQLite, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?_journal_mode=WAL&_busy_timeout=5000&_txlock=immediate"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open sqlite store %s: %w", path, err)
+	}
+	return &SQLite{db: db, now: time.Now}, nil
+}
+
+func (s *SQLite) Get(ctx context.Context, key string) (Entry, bool, error) {
+	var (
+		value               []byte
+		storedAt, expiresAt int64
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT value, stored_at, expires_at FROM entries WHERE key = ?`, key).
+		Scan(&value, &storedAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e := sqliteEntry(value, storedAt, expiresAt)
+	if e.expired(s.now()) {
+		_, _ = s.db.ExecContext(ctx, `DELETE FROM entries WHERE key = ? AND expires_at = ?`, key, expiresAt)
+		return Entry{}, false, nil
+	}
+	return e, true, nil
+}
+
+func (s *SQLite) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	e := newEntry(value, ttl, s.now())
+	var expiresAt int64
+	if !e.ExpiresAt.IsZero() {
+		expiresAt = e.ExpiresAt.UnixNano()
+	}
+	if e.Value == nil {
+		e.Value = []byte{}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO entries (key, value, stored_at, expires_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, stored_at = excluded.stored_at, expires_at = excluded.expires_at`,
+		key, e.Value, e.StoredAt.UnixNano(), expiresAt)
+	return err
+}
+
+func (s *SQLite) Delete(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM entries WHERE key = ?`, key)
+	return err
+}
+
+// Scan reads the matching entries before calling fn, so fn may use the
+// store itself.
+func (s *SQLite) Scan(ctx context.Context, prefix string, fn func(key string, e Entry) error) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key, value, stored_at, expires_at FROM entries
+		WHERE substr(key, 1, length(?1)) = ?1 AND (expires_at = 0 OR expires_at > ?2)`,
+		prefix, s.now().UnixNano())
+	if err != nil {
+		return err
+	}
+	type row struct {
+		key string
+		e   Entry
+	}
+	var matched []row
+	for rows.Next() {
+		var (
+			r                   row
+			value               []byte
+			storedAt, expiresAt int64
+		)
+		if err := rows.Scan(&r.key, &value, &storedAt, &expiresAt); err != nil {
+			rows.Close()
+			return err
+		}
+		r.e = sqliteEntry(value, storedAt, expiresAt)
+		matched = append(matched, r)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, r := range matched {
+		if err := fn(r.key, r.e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLite) Close() error { return s.db.Close() }
+
+func sqliteEntry(value []byte, storedAt, expiresAt int64) Entry {
+	e := Entry{Value: value, StoredAt: time.Unix(0, storedAt)}
+	if expiresAt != 0 {
+		e.ExpiresAt = time.Unix(0, expiresAt)
+	}
+	return e
+}
@@ -0,0 +1,15 @@
+//go:build !cgo
+
+package store
+
+// sqliteAvailable reports whether this build has the SQLite driver, which
+// needs cgo.
+const sqliteAvailable = false
+
+// SQLite is the SQLite store of cgo builds.
+type SQLite struct{ Store }
+
+// NewSQLite fails: the SQLite driver is only compiled into cgo builds.
+func NewSQLite(string) (*SQLite, error) {
+	return nil, errSQLiteNeedsCgo
+}
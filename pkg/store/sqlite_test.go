@@ -0,0 +1,46 @@
+//go:build cgo
+
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLite(t *testing.T) {
+	st, err := NewSQLite(filepath.Join(t.TempDir(), "state", "store.db"))
+	require.NoError(t, err)
+	now := time.Now()
+	st.now = func() time.Time { return now }
+	testStore(t, st, func(d time.Duration) { now = now.Add(d) })
+}
+
+func TestSQLiteShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	ctx := context.Background()
+
+	a, err := Open("sqlite://" + path)
+	require.NoError(t, err)
+	b, err := Open("sqlite://" + path)
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, a.Put(ctx, "idempotency/k1", []byte(`{"ok":true}`), time.Minute))
+	require.NoError(t, a.Close())
+
+	e, ok, err := b.Get(ctx, "idempotency/k1")
+	require.NoError(t, err)
+	require.True(t, ok, "a second server on the same file sees the entry")
+	assert.Equal(t, `{"ok":true}`, string(e.Value))
+	assert.False(t, e.ExpiresAt.IsZero())
+
+	require.NoError(t, b.Put(ctx, "idempotency/k2", nil, 0))
+	require.NoError(t, b.Put(ctx, "idempotencyXk3", nil, 0))
+	assert.Equal(t, []string{"idempotency/k1", "idempotency/k2"}, scanKeys(t, b, "idempotency/"))
+	assert.Empty(t, scanKeys(t, b, "idempotency_"), "prefixes match literally")
+}
@@ -0,0 +1,160 @@
+// Package store is the key-value storage the server keeps its caches and
+// shared state in. A process-local store suits a single server; replicas
+// behind a load balancer point SLACK_MCP_STORE at the same file directory,
+// SQLite database or Redis so they share users and channels, idempotency results and
+// session contexts instead of each keeping a private, diverging copy.
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Entry is a stored value with the time it was written.
+type Entry struct {
+	Value    []byte
+	StoredAt time.Time
+	// ExpiresAt is zero for a value kept until it is overwritten or deleted.
+	ExpiresAt time.Time
+}
+
+// expired reports whether e is past its TTL at now.
+func (e Entry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// Store is a key-value store with per-key TTLs. Keys are slash-separated
+// paths such as "cache/T123_users_cache.json"; implementations must be
+// safe for concurrent use.
+type Store interface {
+	// Get returns the entry of key, and false when it is missing or expired.
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	// Put stores value under key. A ttl of zero keeps it until overwritten
+	// or deleted.
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Scan calls fn for every live entry whose key starts with prefix, in
+	// no particular order, and stops at the first error fn returns.
+	Scan(ctx context.Context, prefix string, fn func(key string, e Entry) error) error
+	Close() error
+}
+
+// errSQLiteNeedsCgo is returned for sqlite:// stores by builds without the
+// SQLite driver, which is linked through cgo.
+var errSQLiteNeedsCgo = errors.New("the sqlite store needs a binary built with cgo (CGO_ENABLED=1), use file:// or redis:// instead")
+
+// Validate checks that uri, the SLACK_MCP_STORE value, names a supported
+// store, without opening it.
+func Validate(uri string) error {
+	_, err := parse(uri)
+	return err
+}
+
+// Open opens the store uri names:
+//
+//	"" or memory            process-local, lost on restart
+//	file:///var/lib/slack   one file per key in the directory
+//	sqlite:///var/lib/slack/store.db, in cgo builds
+//	redis://[user:pass@]host:port/db, rediss:// for TLS
+func Open(uri string) (Store, error) {
+	u, err := parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "memory":
+		return NewMemory(), nil
+	case "file":
+		return NewFile(u.Path)
+	case "sqlite", "sqlite3":
+		return NewSQLite(u.Path)
+	default:
+		return NewRedis(u)
+	}
+}
+
+func parse(uri string) (*url.URL, error) {
+	if uri == "" || uri == "memory" {
+		return &url.URL{Scheme: "memory"}, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("file store %q must be a local path such as file:///var/lib/slack-mcp", uri)
+		}
+		if u.Path == "" {
+			return nil, fmt.Errorf("file store %q has no directory", uri)
+		}
+	case "redis", "rediss":
+		if u.Hostname() == "" {
+			return nil, fmt.Errorf("redis store %q has no host", uri)
+		}
+		if db := strings.Trim(u.Path, "/"); db != "" {
+			if _, err := redisDB(u); err != nil {
+				return nil, err
+			}
+		}
+	case "sqlite", "sqlite3":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("sqlite store %q must be a local path such as sqlite:///var/lib/slack-mcp/store.db", uri)
+		}
+		if u.Path == "" || strings.HasSuffix(u.Path, "/") {
+			return nil, fmt.Errorf("sqlite store %q has no database file", uri)
+		}
+		if !sqliteAvailable {
+			return nil, errSQLiteNeedsCgo
+		}
+	default:
+		return nil, fmt.Errorf("unsupported store %q, use memory, file:///dir, sqlite:///file.db or redis://host:port/db", uri)
+	}
+	return u, nil
+}
+
+// entryHeaderSize is the size of the times encoded before a value by
+// encodeEntry.
+const entryHeaderSize = 16
+
+// encodeEntry serializes e for the stores that keep bytes: the stored and
+// expiry times in Unix nanoseconds, zero for no expiry, then the value.
+func encodeEntry(e Entry) []byte {
+	buf := make([]byte, entryHeaderSize+len(e.Value))
+	binary.BigEndian.PutUint64(buf[0:8], uint64(e.StoredAt.UnixNano()))
+	if !e.ExpiresAt.IsZero() {
+		binary.BigEndian.PutUint64(buf[8:16], uint64(e.ExpiresAt.UnixNano()))
+	}
+	copy(buf[entryHeaderSize:], e.Value)
+	return buf
+}
+
+func decodeEntry(buf []byte) (Entry, error) {
+	if len(buf) < entryHeaderSize {
+		return Entry{}, errors.New("stored entry is truncated")
+	}
+	e := Entry{
+		StoredAt: time.Unix(0, int64(binary.BigEndian.Uint64(buf[0:8]))),
+		Value:    buf[entryHeaderSize:],
+	}
+	if exp := int64(binary.BigEndian.Uint64(buf[8:16])); exp != 0 {
+		e.ExpiresAt = time.Unix(0, exp)
+	}
+	return e, nil
+}
+
+// newEntry builds the entry Put stores at now.
+func newEntry(value []byte, ttl time.Duration, now time.Time) Entry {
+	e := Entry{Value: value, StoredAt: now}
+	if ttl > 0 {
+		e.ExpiresAt = now.Add(ttl)
+	}
+	return e
+}
@@ -0,0 +1,142 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// languageNoiseRe matches what carries no language: code, Slack links,
+	// mentions and channels in <...>, bare URLs and :emoji: codes.
+	languageNoiseRe = regexp.MustCompile("(?s)```.*?```|`[^`]*`|<[^>]*>|https?://\\S+|:[a-z0-9_+'-]+:")
+
+	// languageStopwords are frequent short words of the Latin-script
+	// languages DetectLanguage tells apart. Several are shared between
+	// languages; a text is only attributed when one language clearly leads.
+	languageStopwords = map[string][]string{
+		"en": {"the", "and", "is", "are", "was", "were", "this", "that", "with", "have", "has", "you", "for", "not", "it", "we", "will", "be", "of", "to", "what", "can", "on", "my", "do", "does", "please", "thanks", "should", "would", "i"},
+		"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "sie", "ein", "eine", "mit", "auf", "für", "zu", "den", "dem", "wir", "auch", "sind", "war", "bitte", "danke", "kann", "noch", "aber", "wie", "du", "ihr", "bei"},
+		"fr": {"le", "la", "les", "et", "est", "un", "une", "des", "du", "je", "vous", "nous", "pas", "pour", "que", "qui", "dans", "ce", "sur", "avec", "merci", "mais", "il", "elle", "sont", "tu", "au", "peux"},
+		"es": {"el", "la", "los", "las", "y", "es", "una", "que", "de", "en", "por", "para", "con", "no", "gracias", "pero", "está", "son", "lo", "se", "muy", "hay", "yo", "puedes", "del"},
+		"pt": {"o", "os", "as", "e", "é", "um", "uma", "não", "que", "de", "do", "da", "em", "para", "com", "por", "obrigado", "obrigada", "mas", "você", "eu", "está", "são", "isso", "pode"},
+		"it": {"il", "lo", "gli", "le", "e", "è", "un", "una", "che", "di", "del", "della", "per", "con", "non", "sono", "grazie", "ma", "io", "questo", "anche", "come", "puoi"},
+		"nl": {"de", "het", "een", "en", "is", "van", "niet", "ik", "je", "we", "dat", "die", "op", "met", "voor", "zijn", "maar", "ook", "bedankt", "dank", "wat", "er", "kun"},
+	}
+	// languageLetters are letters that only some of these languages use.
+	languageLetters = map[rune]string{
+		'ß': "de", 'ä': "de", 'ö': "de", 'ü': "de",
+		'ñ': "es",
+		'ã': "pt", 'õ': "pt",
+		'œ': "fr", 'ê': "fr", 'î': "fr", 'û': "fr", 'ë': "fr",
+		'ì': "it", 'ò': "it",
+	}
+
+	stopwordLanguages = func() map[string][]string {
+		m := make(map[string][]string)
+		for lang, words := range languageStopwords {
+			for _, w := range words {
+				m[w] = append(m[w], lang)
+			}
+		}
+		return m
+	}()
+)
+
+// DetectLanguage returns the ISO 639-1 code of the language s is written in,
+// or "" when it cannot tell, e.g. for a short reply, emoji or code only.
+// It is a fast local heuristic, not a model: non-Latin scripts are told
+// apart by their characters, and English, German, French, Spanish,
+// Portuguese, Italian and Dutch by their most frequent words.
+func DetectLanguage(s string) string {
+	s = languageNoiseRe.ReplaceAllString(s, " ")
+
+	var kana, hangul, han, cyrillic, ukrainian, arabic, hebrew, greek, thai, devanagari, latin int
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+			if strings.ContainsRune("іїєґІЇЄҐ", r) {
+				ukrainian++
+			}
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Hebrew, r):
+			hebrew++
+		case unicode.Is(unicode.Greek, r):
+			greek++
+		case unicode.Is(unicode.Thai, r):
+			thai++
+		case unicode.Is(unicode.Devanagari, r):
+			devanagari++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	// The script with the most letters wins; Japanese mixes kana with Han.
+	best, lang := 0, ""
+	for _, c := range []struct {
+		n    int
+		lang string
+	}{
+		{kana + han, "zh"}, {hangul, "ko"}, {cyrillic, "ru"}, {arabic, "ar"}, {hebrew, "he"},
+		{greek, "el"}, {thai, "th"}, {devanagari, "hi"},
+	} {
+		if c.n > best {
+			best, lang = c.n, c.lang
+		}
+	}
+	if best < 2 || best*2 < latin {
+		return detectLatinLanguage(s)
+	}
+	switch {
+	case lang == "zh" && kana > 0:
+		return "ja"
+	case lang == "ru" && ukrainian > 0:
+		return "uk"
+	}
+	return lang
+}
+
+// detectLatinLanguage scores s by the stopwords and letters of each
+// language and returns the one that leads, if any.
+func detectLatinLanguage(s string) string {
+	scores := make(map[string]int)
+	if strings.ContainsAny(s, "¿¡") {
+		scores["es"] += 2
+	}
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for _, lang := range stopwordLanguages[word] {
+			scores[lang] += 2
+		}
+		seen := make(map[string]bool)
+		for _, r := range word {
+			if lang, ok := languageLetters[r]; ok && !seen[lang] {
+				seen[lang] = true
+				scores[lang]++
+			}
+		}
+	}
+
+	best, second, lang := 0, 0, ""
+	for l, score := range scores {
+		switch {
+		case score > best:
+			best, second, lang = score, best, l
+		case score > second:
+			second = score
+		}
+	}
+	// One stopword is not enough, e.g. "de" in a name.
+	if best < 4 || best == second {
+		return ""
+	}
+	return lang
+}
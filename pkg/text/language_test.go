@@ -0,0 +1,39 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"The deploy is failing on staging, can you take a look?", "en"},
+		{"Thanks, will do", "en"},
+		{"Der Build ist fehlgeschlagen, kannst du bitte nachsehen?", "de"},
+		{"Le déploiement est cassé sur la préproduction, tu peux regarder ?", "fr"},
+		{"El despliegue está fallando en staging, ¿puedes revisarlo?", "es"},
+		{"O deploy não funcionou, você pode verificar isso?", "pt"},
+		{"Il deploy non è andato a buon fine, puoi controllare per favore?", "it"},
+		{"De build is mislukt, kun je er even naar kijken?", "nl"},
+		{"デプロイが失敗しています。確認してもらえますか？", "ja"},
+		{"部署失败了，请检查一下", "zh"},
+		{"배포가 실패했습니다. 확인해 주세요", "ko"},
+		{"Развертывание не удалось, посмотри пожалуйста", "ru"},
+		{"Розгортання не вдалося, перевір його і напиши мені", "uk"},
+		{"فشل النشر، هل يمكنك التحقق؟", "ar"},
+		{"<@U0123ABCD> デプロイ完了しました :tada: <https://ci.example.com/build/42|build>", "ja"},
+		// Too little to tell.
+		{"ok", ""},
+		{":+1:", ""},
+		{"<https://example.com/de/la/que>", ""},
+		{"```SELECT * FROM users WHERE id = 1```", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DetectLanguage(tt.in), tt.in)
+	}
+}
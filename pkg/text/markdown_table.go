@@ -0,0 +1,163 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	markdownTableDelimRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	markdownTableLinkRe  = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	markdownTableEmphRe  = regexp.MustCompile("\\*\\*|__|`")
+)
+
+type tableAlign int
+
+const (
+	alignLeft tableAlign = iota
+	alignRight
+	alignCenter
+)
+
+// MarkdownTablesToCode replaces GitHub-style Markdown tables with fenced
+// code blocks holding the table as aligned plain text. Slack renders
+// neither Markdown tables nor mrkdwn ones, so without this a table is
+// posted as a run of pipes. Tables inside fenced code blocks are left as
+// they are.
+func MarkdownTablesToCode(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			out = append(out, line)
+			continue
+		}
+
+		if i+1 < len(lines) && strings.Contains(line, "|") && markdownTableDelimRe.MatchString(lines[i+1]) {
+			header := splitTableRow(line)
+			aligns := tableAligns(lines[i+1])
+			if len(header) == len(aligns) {
+				rows := [][]string{header}
+				j := i + 2
+				for ; j < len(lines); j++ {
+					if strings.TrimSpace(lines[j]) == "" || !strings.Contains(lines[j], "|") {
+						break
+					}
+					rows = append(rows, splitTableRow(lines[j]))
+				}
+				out = append(out, "```")
+				out = append(out, renderTable(rows, aligns)...)
+				out = append(out, "```")
+				i = j - 1
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// splitTableRow returns the cells of a table row, with inline Markdown that
+// a code block would show literally removed.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, cleanTableCell(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, cleanTableCell(cell.String()))
+}
+
+func cleanTableCell(cell string) string {
+	cell = markdownTableLinkRe.ReplaceAllString(cell, "$1 ($2)")
+	cell = markdownTableEmphRe.ReplaceAllString(cell, "")
+	return strings.TrimSpace(cell)
+}
+
+func tableAligns(delim string) []tableAlign {
+	var aligns []tableAlign
+	for _, c := range splitTableRow(delim) {
+		switch {
+		case strings.HasPrefix(c, ":") && strings.HasSuffix(c, ":"):
+			aligns = append(aligns, alignCenter)
+		case strings.HasSuffix(c, ":"):
+			aligns = append(aligns, alignRight)
+		default:
+			aligns = append(aligns, alignLeft)
+		}
+	}
+	return aligns
+}
+
+// renderTable lays out rows, the header first, in columns padded to their
+// widest cell, with a rule under the header. Rows with fewer cells than the
+// header are padded, extra cells dropped, as GitHub does.
+func renderTable(rows [][]string, aligns []tableAlign) []string {
+	widths := make([]int, len(aligns))
+	for r, row := range rows {
+		if len(row) < len(aligns) {
+			row = append(row, make([]string, len(aligns)-len(row))...)
+		}
+		rows[r] = row[:len(aligns)]
+		for c, cell := range rows[r] {
+			widths[c] = max(widths[c], utf8.RuneCountInString(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for c, cell := range row {
+			cells[c] = padCell(cell, widths[c], aligns[c])
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+		if r == 0 {
+			rule := make([]string, len(widths))
+			for c, w := range widths {
+				rule[c] = strings.Repeat("-", w)
+			}
+			lines = append(lines, strings.Join(rule, "-+-"))
+		}
+	}
+	return lines
+}
+
+func padCell(cell string, width int, align tableAlign) string {
+	gap := width - utf8.RuneCountInString(cell)
+	switch align {
+	case alignRight:
+		return strings.Repeat(" ", gap) + cell
+	case alignCenter:
+		return strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+	}
+	return cell + strings.Repeat(" ", gap)
+}
@@ -0,0 +1,43 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownTablesToCode(t *testing.T) {
+	in := "Deploy results:\n\n" +
+		"| Service | Errors | Status |\n" +
+		"|:--------|-------:|:------:|\n" +
+		"| **api** | 12 | ok |\n" +
+		"| [web](https://example.com) | 3 | `degraded` |\n" +
+		"| worker |\n" +
+		"\nThanks"
+	want := "Deploy results:\n\n" +
+		"```\n" +
+		"Service                   | Errors |  Status\n" +
+		"--------------------------+--------+---------\n" +
+		"api                       |     12 |    ok\n" +
+		"web (https://example.com) |      3 | degraded\n" +
+		"worker                    |        |\n" +
+		"```\n" +
+		"\nThanks"
+	assert.Equal(t, want, MarkdownTablesToCode(in))
+}
+
+func TestMarkdownTablesToCodeLeavesOtherText(t *testing.T) {
+	for name, in := range map[string]string{
+		"no table":          "a | b\nplain text",
+		"column mismatch":   "| a | b |\n|---|\n| 1 | 2 |",
+		"inside code block": "```\n| a | b |\n|---|---|\n| 1 | 2 |\n```",
+	} {
+		assert.Equal(t, in, MarkdownTablesToCode(in), name)
+	}
+}
+
+func TestMarkdownTablesToCodeEscapedPipe(t *testing.T) {
+	in := "a | b\n--- | ---\nx \\| y | z"
+	want := "```\na     | b\n------+--\nx | y | z\n```"
+	assert.Equal(t, want, MarkdownTablesToCode(in))
+}
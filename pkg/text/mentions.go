@@ -0,0 +1,90 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MentionResolver looks up IDs for the @names and #channel-names written in
+// outgoing messages. Either function may be nil to leave those tokens as text.
+type MentionResolver struct {
+	User    func(name string) (string, bool)
+	Channel func(name string) (string, bool)
+}
+
+// mentionTokenRe matches @name and #name tokens that start a word, so e-mail
+// addresses, URL fragments and existing <...> markup are not touched.
+var mentionTokenRe = regexp.MustCompile(`(^|[^\w<@#&/|])([@#])([\w][\w.-]*)`)
+
+var specialMentions = map[string]string{
+	"here":     "<!here>",
+	"channel":  "<!channel>",
+	"everyone": "<!everyone>",
+}
+
+// ResolveMentions converts @name and #channel-name tokens into Slack <@U…> and
+// <#C…> markup so they notify and link as if typed in the Slack client.
+// @here, @channel and @everyone become their special mentions. Tokens that do
+// not resolve, and anything inside code spans, code blocks or existing <...>
+// markup, are left unchanged.
+func ResolveMentions(s string, r MentionResolver) string {
+	if s == "" || !strings.ContainsAny(s, "@#") {
+		return s
+	}
+
+	var protected []string
+	protect := func(v string) string {
+		protected = append(protected, v)
+		return "\x00" + string(rune('A'+len(protected)-1)) + "\x00"
+	}
+	s = mrkdwnCodeBlockRe.ReplaceAllStringFunc(s, protect)
+	s = mrkdwnInlineCodeRe.ReplaceAllStringFunc(s, protect)
+	s = mrkdwnAngleRe.ReplaceAllStringFunc(s, protect)
+
+	s = mentionTokenRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mentionTokenRe.FindStringSubmatch(m)
+		prefix, sigil, name := sub[1], sub[2], sub[3]
+
+		// Sentence punctuation directly after a name is not part of it.
+		trimmed := strings.TrimRight(name, ".-")
+		suffix := name[len(trimmed):]
+
+		if markup, ok := resolveMention(sigil, trimmed, r); ok {
+			return prefix + markup + suffix
+		}
+		return m
+	})
+
+	for i := len(protected) - 1; i >= 0; i-- {
+		s = strings.Replace(s, "\x00"+string(rune('A'+i))+"\x00", protected[i], 1)
+	}
+	return s
+}
+
+func resolveMention(sigil, name string, r MentionResolver) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	if sigil == "#" {
+		if r.Channel == nil {
+			return "", false
+		}
+		id, ok := r.Channel(name)
+		if !ok {
+			return "", false
+		}
+		return "<#" + id + ">", true
+	}
+
+	if special, ok := specialMentions[strings.ToLower(name)]; ok {
+		return special, true
+	}
+	if r.User == nil {
+		return "", false
+	}
+	id, ok := r.User(name)
+	if !ok {
+		return "", false
+	}
+	return "<@" + id + ">", true
+}
@@ -0,0 +1,53 @@
+package text
+
+import "testing"
+
+func TestResolveMentions(t *testing.T) {
+	resolver := MentionResolver{
+		User: func(name string) (string, bool) {
+			switch name {
+			case "alice":
+				return "U123", true
+			case "bob.jones":
+				return "U456", true
+			}
+			return "", false
+		},
+		Channel: func(name string) (string, bool) {
+			if name == "general" || name == "support-eu" {
+				return "C123", true
+			}
+			return "", false
+		},
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"user", "hi @alice", "hi <@U123>"},
+		{"dotted handle with trailing period", "thanks @bob.jones.", "thanks <@U456>."},
+		{"channel", "see #general, then #support-eu", "see <#C123>, then <#C123>"},
+		{"special mention", "@here deploy starts", "<!here> deploy starts"},
+		{"unknown left alone", "ping @carol in #random", "ping @carol in #random"},
+		{"email untouched", "mail alice@example.com", "mail alice@example.com"},
+		{"url fragment untouched", "https://example.com/#general", "https://example.com/#general"},
+		{"existing markup untouched", "<@U999|alice> and <#C999|general>", "<@U999|alice> and <#C999|general>"},
+		{"inline code untouched", "run `@alice #general`", "run `@alice #general`"},
+		{"code block untouched", "```\n@alice\n```", "```\n@alice\n```"},
+		{"adjacent tokens", "@alice,@bob.jones", "<@U123>,<@U456>"},
+		{"markdown heading", "# Release notes for #general", "# Release notes for <#C123>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveMentions(tt.in, resolver); got != tt.want {
+				t.Errorf("ResolveMentions(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	if got := ResolveMentions("hi @alice", MentionResolver{}); got != "hi @alice" {
+		t.Errorf("nil resolver changed text: %q", got)
+	}
+}
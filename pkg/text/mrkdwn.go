@@ -0,0 +1,175 @@
+package text
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MrkdwnResolver looks up display names for IDs referenced in Slack mrkdwn.
+// Either function may be nil, in which case the label embedded in the markup
+// (or the raw ID) is used.
+type MrkdwnResolver struct {
+	User    func(id string) (string, bool)
+	Channel func(id string) (string, bool)
+}
+
+var (
+	mrkdwnCodeBlockRe  = regexp.MustCompile("(?s)```(.*?)```")
+	mrkdwnInlineCodeRe = regexp.MustCompile("`[^`\n]+`")
+	mrkdwnAngleRe      = regexp.MustCompile(`<([^<>\s][^<>]*)>`)
+	mrkdwnBoldRe       = regexp.MustCompile(`(^|[\s(>_~])\*([^*\n]+?)\*([\s).,;:!?_~]|$)`)
+	mrkdwnStrikeRe     = regexp.MustCompile(`(^|[\s(>*_])~([^~\n]+?)~([\s).,;:!?*_]|$)`)
+	mrkdwnEmojiRe      = regexp.MustCompile(`:([a-z0-9_+'-]+):`)
+)
+
+// emojiCodes maps the most common Slack emoji short codes to Unicode. Codes
+// that are not listed (including custom workspace emoji) are left as :name:.
+var emojiCodes = map[string]string{
+	"+1":                    "👍",
+	"thumbsup":              "👍",
+	"-1":                    "👎",
+	"thumbsdown":            "👎",
+	"smile":                 "😄",
+	"slightly_smiling_face": "🙂",
+	"grinning":              "😀",
+	"joy":                   "😂",
+	"laughing":              "😆",
+	"wink":                  "😉",
+	"heart":                 "❤️",
+	"tada":                  "🎉",
+	"fire":                  "🔥",
+	"eyes":                  "👀",
+	"rocket":                "🚀",
+	"white_check_mark":      "✅",
+	"heavy_check_mark":      "✔️",
+	"x":                     "❌",
+	"warning":               "⚠️",
+	"rotating_light":        "🚨",
+	"pray":                  "🙏",
+	"clap":                  "👏",
+	"wave":                  "👋",
+	"thinking_face":         "🤔",
+	"raised_hands":          "🙌",
+	"100":                   "💯",
+	"bug":                   "🐛",
+	"memo":                  "📝",
+	"calendar":              "📆",
+	"point_right":           "👉",
+	"sob":                   "😭",
+	"cry":                   "😢",
+	"sweat_smile":           "😅",
+	"ok_hand":               "👌",
+	"muscle":                "💪",
+	"star":                  "⭐",
+	"sparkles":              "✨",
+	"bulb":                  "💡",
+	"question":              "❓",
+	"exclamation":           "❗",
+	"heavy_plus_sign":       "➕",
+	"no_entry":              "⛔",
+	"hourglass":             "⌛",
+	"construction":          "🚧",
+	"lock":                  "🔒",
+	"link":                  "🔗",
+	"coffee":                "☕",
+	"sunglasses":            "😎",
+	"see_no_evil":           "🙈",
+}
+
+// EmojiUnicode returns the Unicode character for a known Slack emoji short
+// code, e.g. "white_check_mark" -> "✅".
+func EmojiUnicode(code string) (string, bool) {
+	u, ok := emojiCodes[code]
+	return u, ok
+}
+
+// MrkdwnToMarkdown converts Slack mrkdwn into standard Markdown: mentions and
+// channel references become @name/#name, <url|label> becomes [label](url),
+// *bold* and ~strike~ use Markdown syntax, known emoji codes become Unicode and
+// HTML entities are decoded. Code spans and blocks are left untouched.
+func MrkdwnToMarkdown(s string, r MrkdwnResolver) string {
+	if s == "" {
+		return s
+	}
+
+	var protected []string
+	protect := func(v string) string {
+		protected = append(protected, v)
+		return "\x00" + string(rune('A'+len(protected)-1)) + "\x00"
+	}
+
+	s = mrkdwnCodeBlockRe.ReplaceAllStringFunc(s, func(m string) string {
+		body := strings.Trim(m[3:len(m)-3], "\n")
+		return protect("```\n" + decodeEntities(body) + "\n```")
+	})
+	s = mrkdwnInlineCodeRe.ReplaceAllStringFunc(s, func(m string) string {
+		return protect(decodeEntities(m))
+	})
+
+	s = mrkdwnAngleRe.ReplaceAllStringFunc(s, func(m string) string {
+		return protect(convertAngle(m[1:len(m)-1], r))
+	})
+
+	s = decodeEntities(s)
+	s = mrkdwnBoldRe.ReplaceAllString(s, "$1**$2**$3")
+	s = mrkdwnStrikeRe.ReplaceAllString(s, "$1~~$2~~$3")
+	s = mrkdwnEmojiRe.ReplaceAllStringFunc(s, func(m string) string {
+		if u, ok := emojiCodes[m[1:len(m)-1]]; ok {
+			return u
+		}
+		return m
+	})
+
+	for i := len(protected) - 1; i >= 0; i-- {
+		s = strings.Replace(s, "\x00"+string(rune('A'+i))+"\x00", protected[i], 1)
+	}
+	return s
+}
+
+func convertAngle(inner string, r MrkdwnResolver) string {
+	target, label, _ := strings.Cut(inner, "|")
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		id := target[1:]
+		if r.User != nil {
+			if name, ok := r.User(id); ok {
+				return "@" + name
+			}
+		}
+		if label != "" {
+			return "@" + strings.TrimPrefix(label, "@")
+		}
+		return "@" + id
+	case strings.HasPrefix(target, "#"):
+		id := target[1:]
+		if r.Channel != nil {
+			if name, ok := r.Channel(id); ok {
+				return "#" + strings.TrimPrefix(name, "#")
+			}
+		}
+		if label != "" {
+			return "#" + strings.TrimPrefix(label, "#")
+		}
+		return "#" + id
+	case strings.HasPrefix(target, "!"):
+		if label != "" {
+			return decodeEntities(label)
+		}
+		special := target[1:]
+		if i := strings.IndexAny(special, "^"); i >= 0 {
+			special = special[:i]
+		}
+		return "@" + special
+	}
+
+	url := decodeEntities(target)
+	if label == "" || label == target {
+		return "<" + url + ">"
+	}
+	return "[" + decodeEntities(label) + "](" + url + ")"
+}
+
+func decodeEntities(s string) string {
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&").Replace(s)
+}
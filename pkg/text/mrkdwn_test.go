@@ -0,0 +1,52 @@
+package text
+
+import "testing"
+
+func TestMrkdwnToMarkdown(t *testing.T) {
+	resolver := MrkdwnResolver{
+		User: func(id string) (string, bool) {
+			if id == "U123" {
+				return "alice", true
+			}
+			return "", false
+		},
+		Channel: func(id string) (string, bool) {
+			if id == "C123" {
+				return "#general", true
+			}
+			return "", false
+		},
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"resolved user mention", "hi <@U123>", "hi @alice"},
+		{"unresolved user mention with label", "hi <@U999|bob>", "hi @bob"},
+		{"unresolved user mention", "hi <@U999>", "hi @U999"},
+		{"channel mention", "see <#C123>", "see #general"},
+		{"channel mention with label", "see <#C999|random>", "see #random"},
+		{"special mention", "<!here> deploy", "@here deploy"},
+		{"subteam mention", "<!subteam^S123|@oncall> ping", "@oncall ping"},
+		{"labelled link", "read <https://example.com/a?b=1&amp;c=2|the docs>", "read [the docs](https://example.com/a?b=1&c=2)"},
+		{"bare link", "<https://example.com>", "<https://example.com>"},
+		{"bold", "this is *important* now", "this is **important** now"},
+		{"strike", "~old~ new", "~~old~~ new"},
+		{"italic unchanged", "_maybe_", "_maybe_"},
+		{"emoji", "ship it :rocket: :custom_emoji:", "ship it 🚀 :custom_emoji:"},
+		{"entities and quote", "&gt; quoted &amp; done", "> quoted & done"},
+		{"inline code untouched", "run `*not bold* :rocket:`", "run `*not bold* :rocket:`"},
+		{"code block", "```SELECT *\nFROM t```", "```\nSELECT *\nFROM t\n```"},
+		{"time is not emoji", "at 12:30:45", "at 12:30:45"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MrkdwnToMarkdown(tt.in, resolver); got != tt.want {
+				t.Errorf("MrkdwnToMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
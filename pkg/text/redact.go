@@ -0,0 +1,156 @@
+package text
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The built-in redaction rules.
+const (
+	RedactAPIKeys     = "api_keys"
+	RedactCreditCards = "credit_cards"
+	RedactEmails      = "emails"
+)
+
+// RedactRules are the built-in rules a Redactor can be built with.
+var RedactRules = []string{RedactAPIKeys, RedactCreditCards, RedactEmails}
+
+var (
+	// redactAPIKeyRe matches the credentials people paste most: Slack,
+	// AWS, GitHub, Google, Stripe and OpenAI-style keys, JWTs and PEM
+	// private keys.
+	redactAPIKeyRe = regexp.MustCompile(strings.Join([]string{
+		`\bxox[abposr]-[A-Za-z0-9-]{10,}`,
+		`\bxapp-[A-Za-z0-9-]{10,}`,
+		`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+		`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+		`\bgithub_pat_[A-Za-z0-9_]{22,}\b`,
+		`\bAIza[0-9A-Za-z_-]{35}\b`,
+		`\b[rs]k_(?:live|test)_[0-9A-Za-z]{16,}\b`,
+		`\bsk-[A-Za-z0-9_-]{20,}`,
+		`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`,
+		`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	}, "|"))
+	// redactCardRe matches 13 to 19 digits, optionally grouped by spaces or
+	// dashes, starting as card numbers do; Slack timestamps and other IDs
+	// start with 1. Matches must also pass the Luhn check.
+	redactCardRe  = regexp.MustCompile(`\b[2-6]\d{3}(?:[ -]?\d){9,15}\b`)
+	redactEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+	redactNameRe = regexp.MustCompile(`^[a-z0-9_]+$`)
+)
+
+type redactRule struct {
+	name string
+	re   *regexp.Regexp
+	// valid filters matches, e.g. by checksum; nil keeps every match.
+	valid func(string) bool
+}
+
+// Redactor masks sensitive content, replacing each match of a rule with
+// [REDACTED:<rule>]. A nil Redactor redacts nothing.
+type Redactor struct {
+	rules []redactRule
+}
+
+// NewRedactor returns a Redactor with the built-in rules named in rules, or
+// all of them for "all".
+func NewRedactor(rules []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, name := range rules {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "":
+		case "all":
+			for _, rule := range RedactRules {
+				r.addBuiltin(rule)
+			}
+		case RedactAPIKeys, RedactCreditCards, RedactEmails:
+			r.addBuiltin(name)
+		default:
+			return nil, fmt.Errorf("unknown redaction rule %q, allowed: %s or all", name, strings.Join(RedactRules, ", "))
+		}
+	}
+	return r, nil
+}
+
+func (r *Redactor) addBuiltin(name string) {
+	if slices.ContainsFunc(r.rules, func(rule redactRule) bool { return rule.name == name }) {
+		return
+	}
+	switch name {
+	case RedactAPIKeys:
+		r.rules = append(r.rules, redactRule{name: name, re: redactAPIKeyRe})
+	case RedactCreditCards:
+		r.rules = append(r.rules, redactRule{name: name, re: redactCardRe, valid: luhnValid})
+	case RedactEmails:
+		r.rules = append(r.rules, redactRule{name: name, re: redactEmailRe})
+	}
+}
+
+// AddPattern adds a rule masking the matches of the regular expression
+// pattern as [REDACTED:<name>].
+func (r *Redactor) AddPattern(name, pattern string) error {
+	if !redactNameRe.MatchString(name) {
+		return fmt.Errorf("invalid redaction rule name %q: use lower-case letters, digits and _", name)
+	}
+	if slices.Contains(RedactRules, name) || slices.ContainsFunc(r.rules, func(rule redactRule) bool { return rule.name == name }) {
+		return fmt.Errorf("duplicate redaction rule name %q", name)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("redaction rule %q: %w", name, err)
+	}
+	if re.MatchString("") {
+		return fmt.Errorf("redaction rule %q matches the empty string", name)
+	}
+	r.rules = append(r.rules, redactRule{name: name, re: re})
+	return nil
+}
+
+// Enabled reports whether r has any rule.
+func (r *Redactor) Enabled() bool {
+	return r != nil && len(r.rules) > 0
+}
+
+// Redact returns s with every match of the rules masked, adding the number
+// of matches of each rule to counts.
+func (r *Redactor) Redact(s string, counts map[string]int) string {
+	if !r.Enabled() {
+		return s
+	}
+	for _, rule := range r.rules {
+		replacement := "[REDACTED:" + rule.name + "]"
+		s = rule.re.ReplaceAllStringFunc(s, func(match string) string {
+			if rule.valid != nil && !rule.valid(match) {
+				return match
+			}
+			counts[rule.name]++
+			return replacement
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum card
+// numbers carry.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -90,18 +89,7 @@ func IsUnfurlingEnabled(text string, opt string, logger *zap.Logger) bool {
 		allowed[d] = struct{}{}
 	}
 
-	urlRe := regexp.MustCompile(`https?://[^\s]+`)
-	urls := urlRe.FindAllString(text, -1)
-	for _, rawURL := range urls {
-		u, err := url.Parse(rawURL)
-		if err != nil || u.Host == "" {
-			continue
-		}
-		host := strings.ToLower(u.Host)
-		if idx := strings.Index(host, ":"); idx != -1 {
-			host = host[:idx]
-		}
-		host = strings.TrimPrefix(host, "www.")
+	for _, host := range LinkHosts(text) {
 		if _, ok := allowed[host]; !ok {
 			if logger != nil {
 				logger.Warn("Security: attempt to unfurl non-whitelisted host",
@@ -113,30 +101,37 @@ func IsUnfurlingEnabled(text string, opt string, logger *zap.Logger) bool {
 		}
 	}
 
+	return true
+}
+
+// LinkHosts returns the lower-cased hosts text links to: those of its URLs,
+// then bare domain names with a public suffix.
+func LinkHosts(text string) []string {
+	var hosts []string
+	urlRe := regexp.MustCompile(`https?://[^\s]+`)
+	for _, rawURL := range urlRe.FindAllString(text, -1) {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		host := strings.ToLower(u.Host)
+		if idx := strings.Index(host, ":"); idx != -1 {
+			host = host[:idx]
+		}
+		hosts = append(hosts, strings.TrimPrefix(host, "www."))
+	}
+
 	txtNoURLs := urlRe.ReplaceAllString(text, " ")
 
 	domRe := regexp.MustCompile(`\b(?:[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?\.)+[A-Za-z]{2,}\b`)
-	doms := domRe.FindAllString(txtNoURLs, -1)
-
-	for _, d := range doms {
+	for _, d := range domRe.FindAllString(txtNoURLs, -1) {
 		d = strings.ToLower(d)
-
 		if _, icann := publicsuffix.PublicSuffix(d); !icann {
 			continue
 		}
-
-		if _, ok := allowed[d]; !ok {
-			if logger != nil {
-				logger.Warn("Security: attempt to unfurl non-whitelisted host",
-					zap.String("host", d),
-					zap.String("allowed", opt),
-				)
-			}
-			return false
-		}
+		hosts = append(hosts, d)
 	}
-
-	return true
+	return hosts
 }
 
 func Workspace(rawURL string) (string, error) {
@@ -153,23 +148,11 @@ func Workspace(rawURL string) (string, error) {
 }
 
 func TimestampToIsoRFC3339(slackTS string) (string, error) {
-	parts := strings.Split(slackTS, ".")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid slack timestamp format: %s", slackTS)
-	}
-
-	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	t, err := SlackTimestampToTime(slackTS)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse seconds: %v", err)
-	}
-
-	microseconds, err := strconv.ParseInt(parts[1], 10, 64)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse microseconds: %v", err)
+		return "", err
 	}
 
-	t := time.Unix(seconds, microseconds*1000)
-
 	return t.UTC().Format(time.RFC3339), nil
 }
 
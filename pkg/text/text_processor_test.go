@@ -108,6 +108,22 @@ func TestIsUnfurlingEnabled(t *testing.T) {
 	}
 }
 
+func TestLinkHosts(t *testing.T) {
+	got := LinkHosts("See https://www.Example.com:8443/a, docs.github.com and v1.2 notes")
+	want := []string{"example.com", "docs.github.com"}
+	if len(got) != len(want) {
+		t.Fatalf("LinkHosts() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("LinkHosts()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if hosts := LinkHosts("no links here"); len(hosts) != 0 {
+		t.Errorf("LinkHosts() = %v, want none", hosts)
+	}
+}
+
 func TestFilterSpecialCharsWithCommas(t *testing.T) {
 	tests := []struct {
 		name     string
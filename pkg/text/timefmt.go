@@ -0,0 +1,77 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HumanTimeLayout is the layout of the human-readable time columns, e.g.
+// "Tue, 14 Nov 2023 22:13 CET".
+const HumanTimeLayout = "Mon, 02 Jan 2006 15:04 MST"
+
+// LoadTimezone resolves an IANA timezone name ("Europe/Berlin"), "UTC" or
+// "Local". An empty name means UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	switch strings.ToLower(name) {
+	case "", "utc", "z":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// SlackTimestampToTime parses a Slack message timestamp ("1700000000.123456").
+func SlackTimestampToTime(slackTS string) (time.Time, error) {
+	parts := strings.Split(slackTS, ".")
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("invalid slack timestamp format: %s", slackTS)
+	}
+
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse seconds: %v", err)
+	}
+
+	microseconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse microseconds: %v", err)
+	}
+
+	return time.Unix(seconds, microseconds*1000), nil
+}
+
+// FormatTime renders t in loc as ISO-8601 (RFC3339) and in HumanTimeLayout.
+// A nil loc means UTC.
+func FormatTime(t time.Time, loc *time.Location) (iso, human string) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	return t.Format(time.RFC3339), t.Format(HumanTimeLayout)
+}
+
+// FormatSlackTimestamp is FormatTime for a Slack message timestamp.
+func FormatSlackTimestamp(slackTS string, loc *time.Location) (iso, human string, err error) {
+	t, err := SlackTimestampToTime(slackTS)
+	if err != nil {
+		return "", "", err
+	}
+	iso, human = FormatTime(t, loc)
+	return iso, human, nil
+}
+
+// FormatUnix is FormatTime for Unix seconds; zero yields empty strings.
+func FormatUnix(sec int64, loc *time.Location) (iso, human string) {
+	if sec == 0 {
+		return "", ""
+	}
+	return FormatTime(time.Unix(sec, 0), loc)
+}
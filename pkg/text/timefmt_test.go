@@ -0,0 +1,53 @@
+package text
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	loc, err := LoadTimezone("")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = LoadTimezone("utc")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+
+	loc, err = LoadTimezone("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", loc.String())
+
+	_, err = LoadTimezone("Mars/Olympus_Mons")
+	assert.Error(t, err)
+}
+
+func TestFormatSlackTimestamp(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	iso, human, err := FormatSlackTimestamp("1700000000.123456", berlin)
+	require.NoError(t, err)
+	assert.Equal(t, "2023-11-14T23:13:20+01:00", iso)
+	assert.Equal(t, "Tue, 14 Nov 2023 23:13 CET", human)
+
+	iso, human, err = FormatSlackTimestamp("1700000000.123456", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2023-11-14T22:13:20Z", iso)
+	assert.Equal(t, "Tue, 14 Nov 2023 22:13 UTC", human)
+
+	_, _, err = FormatSlackTimestamp("1700000000", nil)
+	assert.Error(t, err)
+}
+
+func TestFormatUnix(t *testing.T) {
+	iso, human := FormatUnix(0, time.UTC)
+	assert.Empty(t, iso)
+	assert.Empty(t, human)
+
+	iso, _ = FormatUnix(1700000000, time.UTC)
+	assert.Equal(t, "2023-11-14T22:13:20Z", iso)
+}
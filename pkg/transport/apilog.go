@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxLoggedFormBody is the largest form body whose parameters are logged.
+const maxLoggedFormBody = 64 << 10
+
+// slackStatusPeek is how much of a JSON response is read to find Slack's
+// "ok" and "error" fields, which it sends first.
+const slackStatusPeek = 512
+
+const redactedValue = "[redacted]"
+
+var (
+	tokenRe      = regexp.MustCompile(`xox[a-z]-[A-Za-z0-9%-]+`)
+	emailRe      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	slackOKRe    = regexp.MustCompile(`"ok"\s*:\s*(true|false)`)
+	slackErrorRe = regexp.MustCompile(`"error"\s*:\s*"([^"]*)"`)
+)
+
+// contentParams carry message or profile content and are never logged.
+var contentParams = map[string]bool{
+	"text":            true,
+	"blocks":          true,
+	"attachments":     true,
+	"markdown_text":   true,
+	"initial_comment": true,
+	"content":         true,
+	"profile":         true,
+	"metadata":        true,
+}
+
+// APICallLogTransport wraps another RoundTripper to log every Slack API call
+// with its method, redacted parameters, latency and result at debug level.
+type APICallLogTransport struct {
+	roundTripper http.RoundTripper
+	logger       *zap.Logger
+}
+
+// NewAPICallLogTransport creates a new APICallLogTransport
+func NewAPICallLogTransport(roundTripper http.RoundTripper, logger *zap.Logger) *APICallLogTransport {
+	return &APICallLogTransport{
+		roundTripper: roundTripper,
+		logger:       logger,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *APICallLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields := []zap.Field{
+		zap.String("method", apiMethod(req.URL)),
+		zap.String("http_method", req.Method),
+		zap.String("params", redactParams(requestParams(req))),
+	}
+
+	start := time.Now()
+	resp, err := t.roundTripper.RoundTrip(req)
+	fields = append(fields, zap.Duration("latency", time.Since(start)))
+
+	if err != nil {
+		fields = append(fields, zap.String("error", redactString(err.Error())))
+		t.logger.Debug("Slack API call failed", fields...)
+		return resp, err
+	}
+
+	fields = append(fields, zap.Int("status", resp.StatusCode))
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		fields = append(fields, zap.String("retry_after", retryAfter))
+	}
+	if ok, slackErr, found := peekSlackStatus(resp); found {
+		fields = append(fields, zap.Bool("ok", ok))
+		if slackErr != "" {
+			fields = append(fields, zap.String("slack_error", slackErr))
+		}
+	}
+	t.logger.Debug("Slack API call", fields...)
+	return resp, nil
+}
+
+// apiMethod returns the Slack method name of an API URL, e.g.
+// "conversations.history", or the path for other requests.
+func apiMethod(u *url.URL) string {
+	if _, method, ok := strings.Cut(u.Path, "/api/"); ok {
+		return method
+	}
+	return u.Path
+}
+
+// requestParams collects the query and, for small form-encoded bodies, the
+// body parameters of req. The body is read through GetBody, so the one sent
+// is left untouched.
+func requestParams(req *http.Request) url.Values {
+	params := url.Values{}
+	for k, v := range req.URL.Query() {
+		params[k] = v
+	}
+
+	if req.Body == nil || req.GetBody == nil ||
+		req.ContentLength <= 0 || req.ContentLength > maxLoggedFormBody ||
+		!strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return params
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return params
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return params
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return params
+	}
+	for k, v := range form {
+		params[k] = append(params[k], v...)
+	}
+	return params
+}
+
+// redactParams renders params sorted by name, dropping tokens, message and
+// profile content, and e-mail addresses.
+func redactParams(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range params[k] {
+			if k == "token" || contentParams[k] {
+				v = redactedValue
+			} else {
+				v = redactString(v)
+			}
+			parts = append(parts, k+"="+v)
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func redactString(s string) string {
+	s = tokenRe.ReplaceAllString(s, redactedValue)
+	return emailRe.ReplaceAllString(s, redactedValue)
+}
+
+// peekSlackStatus reads Slack's "ok" and "error" fields from the start of a
+// JSON response without consuming the body.
+func peekSlackStatus(resp *http.Response) (ok bool, slackErr string, found bool) {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false, "", false
+	}
+
+	head := make([]byte, slackStatusPeek)
+	n, err := io.ReadFull(resp.Body, head)
+	head = head[:n]
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, "", false
+	}
+
+	m := slackOKRe.FindSubmatch(head)
+	if m == nil {
+		return false, "", false
+	}
+	ok = string(m[1]) == "true"
+	if e := slackErrorRe.FindSubmatch(head); e != nil && !ok {
+		slackErr = string(e[1])
+	}
+	return ok, slackErr, true
+}
@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedactParams(t *testing.T) {
+	params := url.Values{
+		"token":   {"xoxp-123-456"},
+		"channel": {"C123"},
+		"text":    {"secret plans"},
+		"query":   {"from:alice@example.com deploy"},
+		"cursor":  {"dXNlcjpVMEc5V0ZYTlo="},
+		"blocks":  {`[{"type":"section"}]`},
+	}
+	got := redactParams(params)
+	assert.Equal(t, "blocks=[redacted]&channel=C123&cursor=dXNlcjpVMEc5V0ZYTlo=&query=from:[redacted] deploy&text=[redacted]&token=[redacted]", got)
+	assert.Equal(t, "Post https://x/api?token=[redacted]: EOF", redactString("Post https://x/api?token=xoxc-1-2-abc: EOF"))
+}
+
+func TestAPICallLogTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "hello bob@example.com", r.PostForm.Get("text"), "the body reaches the server unchanged")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"ok":false,"error":"ratelimited"}`)
+	}))
+	defer srv.Close()
+
+	core, logs := observer.New(zap.DebugLevel)
+	client := &http.Client{Transport: NewAPICallLogTransport(http.DefaultTransport, zap.New(core))}
+
+	form := url.Values{"token": {"xoxb-1-2-3"}, "channel": {"C123"}, "text": {"hello bob@example.com"}}
+	resp, err := client.Post(srv.URL+"/api/chat.postMessage", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, `{"ok":false,"error":"ratelimited"}`, string(body), "the body is not consumed")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "Slack API call", entries[0].Message)
+	assert.Equal(t, "chat.postMessage", fields["method"])
+	assert.Equal(t, "channel=C123&text=[redacted]&token=[redacted]", fields["params"])
+	assert.Equal(t, int64(http.StatusTooManyRequests), fields["status"])
+	assert.Equal(t, "30", fields["retry_after"])
+	assert.Equal(t, false, fields["ok"])
+	assert.Equal(t, "ratelimited", fields["slack_error"])
+}
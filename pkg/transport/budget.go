@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+// CallBudget is charged once for every Slack API request made with a
+// context carrying it, see WithCallBudget.
+type CallBudget interface {
+	// Spend records one request, or returns why it may not be sent.
+	Spend() error
+}
+
+type callBudgetKey struct{}
+
+// WithCallBudget charges the Slack API requests made with ctx to b.
+func WithCallBudget(ctx context.Context, b CallBudget) context.Context {
+	return context.WithValue(ctx, callBudgetKey{}, b)
+}
+
+// CallBudgetTransport wraps another RoundTripper to refuse requests whose
+// context carries an exhausted CallBudget, so one tool call cannot keep
+// paginating past its client's quota.
+type CallBudgetTransport struct {
+	roundTripper http.RoundTripper
+}
+
+// NewCallBudgetTransport creates a new CallBudgetTransport
+func NewCallBudgetTransport(roundTripper http.RoundTripper) *CallBudgetTransport {
+	return &CallBudgetTransport{roundTripper: roundTripper}
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *CallBudgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if b, ok := req.Context().Value(callBudgetKey{}).(CallBudget); ok {
+		if err := b.Spend(); err != nil {
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	return t.roundTripper.RoundTrip(req)
+}
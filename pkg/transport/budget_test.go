@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingBudget struct {
+	left int
+}
+
+func (b *countingBudget) Spend() error {
+	if b.left == 0 {
+		return errors.New("budget exhausted")
+	}
+	b.left--
+	return nil
+}
+
+func TestCallBudgetTransport(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: NewCallBudgetTransport(http.DefaultTransport)}
+
+	do := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return err
+	}
+
+	ctx := WithCallBudget(context.Background(), &countingBudget{left: 1})
+	require.NoError(t, do(ctx))
+	assert.ErrorContains(t, do(ctx), "budget exhausted")
+	assert.Equal(t, 1, hits, "a refused request is not sent")
+
+	require.NoError(t, do(context.Background()), "requests without a budget pass through")
+	assert.Equal(t, 2, hits)
+}
@@ -333,8 +333,9 @@ func detectBrowserFromUserAgent(userAgent string) utls.ClientHelloID {
 	return utls.HelloChrome_Auto
 }
 
-// ProvideHTTPClient creates an HTTP client with optional uTLS support
-func ProvideHTTPClient(cookies []*http.Cookie, logger *zap.Logger) *http.Client {
+// ProvideHTTPClient creates an HTTP client with optional uTLS support. With
+// logAPICalls, every call is logged by an APICallLogTransport.
+func ProvideHTTPClient(cookies []*http.Cookie, logAPICalls bool, logger *zap.Logger) *http.Client {
 	if os.Getenv("SLACK_MCP_PROXY") != "" && os.Getenv("SLACK_MCP_CUSTOM_TLS") != "" {
 		logger.Fatal("SLACK_MCP_PROXY and SLACK_MCP_CUSTOM_TLS cannot be used together",
 			zap.String("reason", "Custom TLS fingerprinting has no effect when using a proxy, as the target server sees the proxy's TLS handshake"))
@@ -441,6 +442,10 @@ func ProvideHTTPClient(cookies []*http.Cookie, logger *zap.Logger) *http.Client
 	}
 
 	transport = NewUserAgentTransport(transport, userAgent, cookies, logger)
+	transport = NewCallBudgetTransport(transport)
+	if logAPICalls {
+		transport = NewAPICallLogTransport(transport, logger)
+	}
 
 	client := &http.Client{
 		Transport: transport,